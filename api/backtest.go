@@ -12,7 +12,9 @@ import (
 	"time"
 
 	"nofx/backtest"
+	"nofx/logger"
 	"nofx/store"
+	"nofx/trader"
 
 	"github.com/gin-gonic/gin"
 )
@@ -65,9 +67,50 @@ func (s *Server) handleBacktestStart(c *gin.Context) {
 	}
 	cfg.CustomPrompt = strings.TrimSpace(cfg.CustomPrompt)
 	cfg.UserID = normalizeUserID(c.GetString("user_id"))
-	if err := s.hydrateBacktestAIConfig(&cfg); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
-		return
+	if cfg.StrategyID != "" {
+		// Apply the strategy's session windows, blackout calendar, custom indicators, expressions, taker flow, regimes,
+		// rules and AI gate (where not set in the request) so results match live trading
+		strategy, err := s.store.Strategy().Get(c.GetString("user_id"), cfg.StrategyID)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "strategy not found"})
+			return
+		}
+		if strategyCfg, err := strategy.ParseConfig(); err == nil {
+			if !cfg.TradingSessions.Enabled {
+				cfg.TradingSessions = strategyCfg.TradingSessions
+			}
+			if len(cfg.Indicators) == 0 {
+				cfg.Indicators = strategyCfg.Indicators.CustomIndicators
+			}
+			if len(cfg.Expressions) == 0 {
+				cfg.Expressions = strategyCfg.Indicators.Expressions
+			}
+			if !cfg.EnableTakerFlow {
+				cfg.EnableTakerFlow = strategyCfg.Indicators.EnableTakerFlow
+			}
+			if !cfg.Regime.Enabled {
+				cfg.Regime = strategyCfg.Regime
+			}
+			if cfg.Mode == "" {
+				cfg.Mode = strategyCfg.Mode
+			}
+			if len(cfg.Rules.Rules) == 0 {
+				cfg.Rules = strategyCfg.Rules
+			}
+			if !cfg.AIGate.Enabled {
+				cfg.AIGate = strategyCfg.AIGate
+			}
+		}
+	}
+	// Rule-based backtests make no AI calls
+	if cfg.Mode != store.StrategyModeRules {
+		if err := s.hydrateBacktestAIConfig(&cfg); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+	}
+	if cfg.Exchange != "" {
+		s.loadBacktestInstruments(c.GetString("user_id"), strings.ToLower(cfg.Exchange))
 	}
 
 	runner, err := s.backtestManager.Start(context.Background(), cfg)
@@ -80,6 +123,35 @@ func (s *Server) handleBacktestStart(c *gin.Context) {
 	c.JSON(http.StatusOK, meta)
 }
 
+// loadBacktestInstruments loads the venue's mainnet instrument rules through one of the user's exchange accounts
+// (backtests replay mainnet klines, testnet accounts list other instruments)
+// When no mainnet account of that venue is configured, the backtest runs without venue rules
+func (s *Server) loadBacktestInstruments(userID, exchangeType string) {
+	venue := trader.Venue{Exchange: exchangeType}
+	if trader.Instruments.Loaded(venue) {
+		return
+	}
+	exchanges, err := s.store.Exchange().List(userID)
+	if err != nil {
+		return
+	}
+	for _, exchange := range exchanges {
+		if !exchange.Enabled || exchange.ExchangeType != exchangeType || exchange.Testnet {
+			continue
+		}
+		client, err := trader.NewTraderFromExchange(exchange, userID)
+		if err != nil {
+			continue
+		}
+		if provider, ok := client.(trader.InstrumentProvider); ok {
+			if err := trader.Instruments.Load(venue, provider); err != nil {
+				logger.Infof("⚠️ Backtest: %v", err)
+			}
+		}
+		return
+	}
+}
+
 func (s *Server) handleBacktestPause(c *gin.Context) {
 	s.handleBacktestControl(c, s.backtestManager.Pause)
 }
@@ -0,0 +1,180 @@
+package api
+
+import (
+	"net/http"
+	"nofx/logger"
+	"nofx/manager"
+	"nofx/store"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// handleListFollowers lists the copy-trading followers of a trader
+func (s *Server) handleListFollowers(c *gin.Context) {
+	traderID, ok := s.ownedTraderID(c)
+	if !ok {
+		return
+	}
+
+	followers, err := s.store.Follower().ListByLeader(traderID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get followers: " + err.Error()})
+		return
+	}
+	if followers == nil {
+		followers = []*store.Follower{}
+	}
+	c.JSON(http.StatusOK, followers)
+}
+
+// handleCreateFollower adds an exchange account replicating the trader's orders
+func (s *Server) handleCreateFollower(c *gin.Context) {
+	userID := c.GetString("user_id")
+	traderID, ok := s.ownedTraderID(c)
+	if !ok {
+		return
+	}
+
+	var follower store.Follower
+	if err := c.ShouldBindJSON(&follower); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	follower.ID = ""
+	follower.UserID = userID
+	follower.LeaderTraderID = traderID
+	if !s.validateFollower(c, userID, traderID, &follower) {
+		return
+	}
+
+	if err := s.store.Follower().Create(&follower); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	s.reloadFollowers(userID, traderID)
+
+	logger.Infof("👥 Follower '%s' added to trader %s", follower.Name, traderID)
+	c.JSON(http.StatusCreated, follower)
+}
+
+// handleUpdateFollower updates a follower's sizing and risk limits
+func (s *Server) handleUpdateFollower(c *gin.Context) {
+	userID := c.GetString("user_id")
+	traderID, ok := s.ownedTraderID(c)
+	if !ok {
+		return
+	}
+
+	existing, err := s.store.Follower().Get(userID, c.Param("followerId"))
+	if err != nil || existing.LeaderTraderID != traderID {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Follower does not exist"})
+		return
+	}
+
+	follower := *existing
+	if err := c.ShouldBindJSON(&follower); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	follower.ID = existing.ID
+	follower.UserID = userID
+	follower.LeaderTraderID = traderID
+	if !s.validateFollower(c, userID, traderID, &follower) {
+		return
+	}
+
+	if err := s.store.Follower().Update(&follower); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	s.reloadFollowers(userID, traderID)
+
+	c.JSON(http.StatusOK, follower)
+}
+
+// handleDeleteFollower removes a follower (its open positions are left untouched)
+func (s *Server) handleDeleteFollower(c *gin.Context) {
+	userID := c.GetString("user_id")
+	traderID, ok := s.ownedTraderID(c)
+	if !ok {
+		return
+	}
+
+	existing, err := s.store.Follower().Get(userID, c.Param("followerId"))
+	if err != nil || existing.LeaderTraderID != traderID {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Follower does not exist"})
+		return
+	}
+	if err := s.store.Follower().Delete(userID, existing.ID); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	s.reloadFollowers(userID, traderID)
+
+	c.JSON(http.StatusOK, gin.H{"message": "Follower deleted"})
+}
+
+// handleFollowerExecutions gets per-follower execution results of replicated orders
+// Query params: follower_id (optional), limit (default 100)
+func (s *Server) handleFollowerExecutions(c *gin.Context) {
+	traderID, ok := s.ownedTraderID(c)
+	if !ok {
+		return
+	}
+
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "100"))
+	executions, err := s.store.Follower().GetExecutions(traderID, c.Query("follower_id"), limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if executions == nil {
+		executions = []*store.FollowerExecution{}
+	}
+	c.JSON(http.StatusOK, executions)
+}
+
+// ownedTraderID returns the trader ID of the route if it belongs to the user
+func (s *Server) ownedTraderID(c *gin.Context) (string, bool) {
+	traderID := c.Param("id")
+	traderRecord, err := s.store.Trader().GetByID(traderID)
+	if err != nil || traderRecord.UserID != c.GetString("user_id") {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Trader does not exist"})
+		return "", false
+	}
+	return traderID, true
+}
+
+// validateFollower validates the follower config and its exchange account
+// A follower can't trade on the leader's own exchange account
+func (s *Server) validateFollower(c *gin.Context, userID, traderID string, follower *store.Follower) bool {
+	if err := follower.Validate(); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return false
+	}
+	exchange, err := s.store.Exchange().GetByID(userID, follower.ExchangeID)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Exchange does not exist"})
+		return false
+	}
+	if leader, err := s.store.Trader().GetByID(traderID); err == nil && leader.ExchangeID == exchange.ID {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Follower must use a different exchange account than the leader"})
+		return false
+	}
+	if follower.Name == "" {
+		follower.Name = exchange.AccountName
+	}
+	return true
+}
+
+// reloadFollowers applies follower changes to the trader if it is loaded in memory
+func (s *Server) reloadFollowers(userID, traderID string) {
+	at, err := s.traderManager.GetTrader(traderID)
+	if err != nil {
+		return
+	}
+	if err := manager.LoadFollowers(s.store, at, userID); err != nil {
+		logger.Infof("⚠️ Failed to reload followers of trader %s: %v", traderID, err)
+	}
+}
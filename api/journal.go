@@ -0,0 +1,99 @@
+package api
+
+import (
+	"net/http"
+	"nofx/store"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// handleListJournal lists a trader's trade journal entries (newest first)
+// Query params: symbol (optional), limit (default 50)
+func (s *Server) handleListJournal(c *gin.Context) {
+	traderID, ok := s.ownedTraderID(c)
+	if !ok {
+		return
+	}
+
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "50"))
+	entries, err := s.store.Journal().List(traderID, strings.ToUpper(c.Query("symbol")), limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get trade journal: " + err.Error()})
+		return
+	}
+	if entries == nil {
+		entries = []*store.JournalEntry{}
+	}
+	c.JSON(http.StatusOK, entries)
+}
+
+// handleUpdateJournalEntry replaces the summary the AI sees for a journal entry
+func (s *Server) handleUpdateJournalEntry(c *gin.Context) {
+	traderID, ok := s.ownedTraderID(c)
+	if !ok {
+		return
+	}
+	entry, ok := s.journalEntry(c, traderID)
+	if !ok {
+		return
+	}
+
+	var req struct {
+		Summary string `json:"summary" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	summary := strings.TrimSpace(req.Summary)
+	if summary == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "summary must not be empty"})
+		return
+	}
+
+	if err := s.store.Journal().UpdateSummary(traderID, entry.ID, summary); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	updated, err := s.store.Journal().Get(traderID, entry.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, updated)
+}
+
+// handleDeleteJournalEntry removes a journal entry so it is no longer shown to the AI
+func (s *Server) handleDeleteJournalEntry(c *gin.Context) {
+	traderID, ok := s.ownedTraderID(c)
+	if !ok {
+		return
+	}
+	entry, ok := s.journalEntry(c, traderID)
+	if !ok {
+		return
+	}
+
+	if err := s.store.Journal().Delete(traderID, entry.ID); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Journal entry deleted"})
+}
+
+// journalEntry returns the journal entry of the route if it belongs to the trader
+func (s *Server) journalEntry(c *gin.Context, traderID string) (*store.JournalEntry, bool) {
+	id, err := strconv.ParseInt(c.Param("entryId"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid journal entry ID"})
+		return nil, false
+	}
+	entry, err := s.store.Journal().Get(traderID, id)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Journal entry does not exist"})
+		return nil, false
+	}
+	return entry, true
+}
@@ -0,0 +1,343 @@
+package api
+
+import (
+	"net/http"
+	"nofx/auth"
+	"nofx/logger"
+	"nofx/store"
+	"nofx/trader"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// killSwitchStopTimeout max wait for traders to finish their current cycle
+// An exchange account is only flattened once every trader on it has stopped, otherwise a cycle
+// still in flight could trade against the flattened book (opens are refused as soon as the kill switch is engaged)
+const killSwitchStopTimeout = 15 * time.Second
+
+type killSwitchRequest struct {
+	OTPCode string `json:"otp_code" binding:"required"`
+	Reason  string `json:"reason"`
+}
+
+// KillSwitchTraderResult outcome of stopping one trader
+type KillSwitchTraderResult struct {
+	TraderID   string `json:"trader_id"`
+	Name       string `json:"name"`
+	WasRunning bool   `json:"was_running"`
+	Stopped    bool   `json:"stopped"`
+	Error      string `json:"error,omitempty"`
+}
+
+// KillSwitchReport outcome of a kill switch run
+type KillSwitchReport struct {
+	Scope      string                   `json:"scope"` // "all" | "trader" | "exchange"
+	Reason     string                   `json:"reason,omitempty"`
+	StartedAt  time.Time                `json:"started_at"`
+	FinishedAt time.Time                `json:"finished_at"`
+	Traders    []KillSwitchTraderResult `json:"traders"`
+	Exchanges  []*trader.FlattenResult  `json:"exchanges"`
+	Success    bool                     `json:"success"`
+}
+
+// handleKillSwitch stops every trader of the user and flattens every enabled exchange account
+func (s *Server) handleKillSwitch(c *gin.Context) {
+	userID := c.GetString("user_id")
+	req, ok := s.bindKillSwitchRequest(c, userID)
+	if !ok {
+		return
+	}
+
+	traders, err := s.store.Trader().List(userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get traders: " + err.Error()})
+		return
+	}
+	allExchanges, err := s.store.Exchange().List(userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get exchanges: " + err.Error()})
+		return
+	}
+	var exchanges []*store.Exchange
+	for _, exchange := range allExchanges {
+		if exchange.Enabled {
+			exchanges = append(exchanges, exchange)
+		}
+	}
+
+	c.JSON(http.StatusOK, s.runKillSwitch(userID, "all", req.Reason, traders, exchanges))
+}
+
+// handleTraderKillSwitch stops one trader and flattens its exchange account and its copy-trading follower accounts
+// Flattening closes every position on an account, so the other traders bound to these accounts are stopped too
+func (s *Server) handleTraderKillSwitch(c *gin.Context) {
+	userID := c.GetString("user_id")
+	traderID := c.Param("id")
+	req, ok := s.bindKillSwitchRequest(c, userID)
+	if !ok {
+		return
+	}
+
+	fullConfig, err := s.store.Trader().GetFullConfig(userID, traderID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Trader does not exist"})
+		return
+	}
+	var exchanges []*store.Exchange
+	if fullConfig.Exchange != nil {
+		exchanges = append(exchanges, fullConfig.Exchange)
+	}
+	followerExchanges, err := s.followerExchanges(userID, traderID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get followers: " + err.Error()})
+		return
+	}
+	for _, exchange := range followerExchanges {
+		if !containsExchange(exchanges, exchange.ID) {
+			exchanges = append(exchanges, exchange)
+		}
+	}
+
+	traders := []*store.Trader{fullConfig.Trader}
+	seen := map[string]bool{fullConfig.Trader.ID: true}
+	for _, exchange := range exchanges {
+		onExchange, err := s.tradersOnExchange(userID, exchange.ID)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get traders: " + err.Error()})
+			return
+		}
+		for _, t := range onExchange {
+			if !seen[t.ID] {
+				seen[t.ID] = true
+				traders = append(traders, t)
+			}
+		}
+	}
+
+	c.JSON(http.StatusOK, s.runKillSwitch(userID, "trader", req.Reason, traders, exchanges))
+}
+
+// followerExchanges the exchange accounts of a trader's enabled copy-trading followers
+func (s *Server) followerExchanges(userID, leaderTraderID string) ([]*store.Exchange, error) {
+	followers, err := s.store.Follower().ListByLeader(leaderTraderID)
+	if err != nil {
+		return nil, err
+	}
+	var exchanges []*store.Exchange
+	for _, f := range followers {
+		if !f.Enabled || f.UserID != userID || containsExchange(exchanges, f.ExchangeID) {
+			continue
+		}
+		exchange, err := s.store.Exchange().GetByID(userID, f.ExchangeID)
+		if err != nil {
+			logger.Infof("⚠️ Kill switch: follower %s exchange %s not found: %v", f.Name, f.ExchangeID, err)
+			continue
+		}
+		exchanges = append(exchanges, exchange)
+	}
+	return exchanges, nil
+}
+
+// containsExchange reports whether the exchange account is in the list
+func containsExchange(exchanges []*store.Exchange, id string) bool {
+	for _, exchange := range exchanges {
+		if exchange.ID == id {
+			return true
+		}
+	}
+	return false
+}
+
+// handleExchangeKillSwitch stops the traders using an exchange account and flattens it
+func (s *Server) handleExchangeKillSwitch(c *gin.Context) {
+	userID := c.GetString("user_id")
+	exchangeID := c.Param("id")
+	req, ok := s.bindKillSwitchRequest(c, userID)
+	if !ok {
+		return
+	}
+
+	exchange, err := s.store.Exchange().GetByID(userID, exchangeID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Exchange does not exist"})
+		return
+	}
+	traders, err := s.tradersOnExchange(userID, exchange.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get traders: " + err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, s.runKillSwitch(userID, "exchange", req.Reason, traders, []*store.Exchange{exchange}))
+}
+
+// tradersOnExchange the user's traders bound to an exchange account
+func (s *Server) tradersOnExchange(userID, exchangeID string) ([]*store.Trader, error) {
+	allTraders, err := s.store.Trader().List(userID)
+	if err != nil {
+		return nil, err
+	}
+	var traders []*store.Trader
+	for _, t := range allTraders {
+		if t.ExchangeID == exchangeID {
+			traders = append(traders, t)
+		}
+	}
+	return traders, nil
+}
+
+// bindKillSwitchRequest parses the request and re-verifies the user's OTP
+func (s *Server) bindKillSwitchRequest(c *gin.Context, userID string) (*killSwitchRequest, bool) {
+	var req killSwitchRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "otp_code is required"})
+		return nil, false
+	}
+
+	user, err := s.store.User().GetByID(userID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "User does not exist"})
+		return nil, false
+	}
+	if user.OTPSecret == "" || !auth.VerifyOTP(user.OTPSecret, req.OTPCode) {
+		logger.Infof("⚠️ Kill switch rejected for user %s: invalid OTP", userID)
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Verification code error"})
+		return nil, false
+	}
+	return &req, true
+}
+
+// runKillSwitch stops the traders, then cancels orders and closes positions on the exchange accounts
+func (s *Server) runKillSwitch(userID, scope, reason string, traders []*store.Trader, exchanges []*store.Exchange) *KillSwitchReport {
+	report := &KillSwitchReport{
+		Scope:     scope,
+		Reason:    reason,
+		StartedAt: time.Now().UTC(),
+		Traders:   []KillSwitchTraderResult{},
+		Exchanges: []*trader.FlattenResult{},
+		Success:   true,
+	}
+	logger.Infof("🛑 KILL SWITCH engaged by user %s (scope=%s, traders=%d, exchanges=%d, reason=%q)",
+		userID, scope, len(traders), len(exchanges), reason)
+
+	// 1. Stop traders (in parallel, a trader in the middle of an AI call may take a while)
+	var wg sync.WaitGroup
+	results := make([]KillSwitchTraderResult, len(traders))
+	stopped := make([]chan struct{}, len(traders))
+	for i, t := range traders {
+		results[i] = KillSwitchTraderResult{TraderID: t.ID, Name: t.Name}
+		stopped[i] = make(chan struct{})
+		if err := s.store.Trader().UpdateStatus(userID, t.ID, false); err != nil {
+			logger.Infof("⚠️  Failed to update trader status: %v", err)
+		}
+
+		at, err := s.traderManager.GetTrader(t.ID)
+		if err != nil {
+			// Not loaded in memory: nothing is running
+			results[i].Stopped = true
+			close(stopped[i])
+			continue
+		}
+		if isRunning, ok := at.GetStatus()["is_running"].(bool); ok {
+			results[i].WasRunning = isRunning
+		}
+
+		wg.Add(1)
+		go func(at *trader.AutoTrader, done chan struct{}) {
+			defer wg.Done()
+			at.Kill()
+			close(done)
+		}(at, stopped[i])
+	}
+
+	waitDone := make(chan struct{})
+	go func() {
+		wg.Wait()
+		close(waitDone)
+	}()
+	select {
+	case <-waitDone:
+	case <-time.After(killSwitchStopTimeout):
+		logger.Infof("⚠️ Kill switch: some traders are still finishing their cycle, their exchange accounts are not flattened")
+	}
+	running := make(map[string][]string) // exchange ID -> names of traders that have not stopped
+	for i, t := range traders {
+		select {
+		case <-stopped[i]:
+			results[i].Stopped = true
+		default:
+			results[i].Error = "still finishing the current cycle (new positions are refused)"
+			report.Success = false
+			running[t.ExchangeID] = append(running[t.ExchangeID], t.Name)
+		}
+	}
+	report.Traders = results
+
+	// 2. Flatten exchange accounts (in parallel, accounts are independent)
+	knownSymbols := s.knownSymbolsByExchange(traders)
+	flattenResults := make([]*trader.FlattenResult, len(exchanges))
+	var flattenWg sync.WaitGroup
+	for i, exchange := range exchanges {
+		if names := running[exchange.ID]; len(names) > 0 {
+			flattenResults[i] = &trader.FlattenResult{
+				ExchangeID:         exchange.ID,
+				ExchangeType:       exchange.ExchangeType,
+				AccountName:        exchange.AccountName,
+				RemainingPositions: -1,
+				Errors:             []string{"not flattened: traders still running: " + strings.Join(names, ", ")},
+			}
+			continue
+		}
+		flattenWg.Add(1)
+		go func(i int, exchange *store.Exchange) {
+			defer flattenWg.Done()
+			flattenResults[i] = s.flattenExchange(userID, exchange, knownSymbols[exchange.ID])
+		}(i, exchange)
+	}
+	flattenWg.Wait()
+
+	for _, result := range flattenResults {
+		if !result.Success {
+			report.Success = false
+		}
+	}
+	report.Exchanges = flattenResults
+	report.FinishedAt = time.Now().UTC()
+
+	logger.Infof("🛑 KILL SWITCH finished for user %s: success=%v (%v)", userID, report.Success, report.FinishedAt.Sub(report.StartedAt).Round(time.Millisecond))
+	return report
+}
+
+// flattenExchange cancels orders and closes positions on one exchange account
+func (s *Server) flattenExchange(userID string, exchange *store.Exchange, knownSymbols []string) *trader.FlattenResult {
+	var result *trader.FlattenResult
+	client, err := trader.NewTraderFromExchange(exchange, userID)
+	if err != nil {
+		result = &trader.FlattenResult{RemainingPositions: -1, Errors: []string{"connect: " + err.Error()}}
+	} else {
+		result = trader.FlattenAccount(client, knownSymbols)
+	}
+	result.ExchangeID = exchange.ID
+	result.ExchangeType = exchange.ExchangeType
+	result.AccountName = exchange.AccountName
+	return result
+}
+
+// knownSymbolsByExchange symbols nofx tracks as open for the traders, grouped by exchange account
+// Used to cancel orders on symbols that may no longer have a position
+func (s *Server) knownSymbolsByExchange(traders []*store.Trader) map[string][]string {
+	symbols := make(map[string][]string)
+	for _, t := range traders {
+		positions, err := s.store.Position().GetOpenPositions(t.ID)
+		if err != nil {
+			continue
+		}
+		for _, pos := range positions {
+			symbols[t.ExchangeID] = append(symbols[t.ExchangeID], pos.Symbol)
+		}
+	}
+	return symbols
+}
@@ -0,0 +1,185 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"nofx/auth"
+	"nofx/manager"
+	"nofx/store"
+
+	"github.com/gin-gonic/gin"
+	"github.com/pquerna/otp/totp"
+)
+
+// TestTraderKillSwitchStopsTradersOnSharedAccount tests the per-trader kill switch stops every trader
+// bound to the exchange account it flattens, and leaves traders on other accounts running
+func TestTraderKillSwitchStopsTradersOnSharedAccount(t *testing.T) {
+	st, err := store.New(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("failed to open store: %v", err)
+	}
+	defer st.Close()
+
+	secret, err := auth.GenerateOTPSecret()
+	if err != nil {
+		t.Fatalf("failed to generate OTP secret: %v", err)
+	}
+	userID := "user-1"
+	if err := st.User().Create(&store.User{ID: userID, Email: "a@b.c", OTPSecret: secret, OTPVerified: true}); err != nil {
+		t.Fatalf("failed to create user: %v", err)
+	}
+
+	if err := st.AIModel().Create(userID, "model-1", "DeepSeek", "deepseek", true, "", ""); err != nil {
+		t.Fatalf("failed to create AI model: %v", err)
+	}
+
+	// Unsupported exchange type: flattening fails fast without reaching the network
+	sharedID, err := st.Exchange().Create(userID, "offline", "Shared", true, "", "", "", false, "", "", "", "", "", "", "", "", "", 0, "")
+	if err != nil {
+		t.Fatalf("failed to create exchange: %v", err)
+	}
+	otherID, err := st.Exchange().Create(userID, "offline", "Other", true, "", "", "", false, "", "", "", "", "", "", "", "", "", 0, "")
+	if err != nil {
+		t.Fatalf("failed to create exchange: %v", err)
+	}
+	for _, tr := range []*store.Trader{
+		{ID: "trader-a", UserID: userID, Name: "A", AIModelID: "model-1", ExchangeID: sharedID, IsRunning: true},
+		{ID: "trader-b", UserID: userID, Name: "B", AIModelID: "model-1", ExchangeID: sharedID, IsRunning: true},
+		{ID: "trader-c", UserID: userID, Name: "C", AIModelID: "model-1", ExchangeID: otherID, IsRunning: true},
+	} {
+		if err := st.Trader().Create(tr); err != nil {
+			t.Fatalf("failed to create trader: %v", err)
+		}
+	}
+
+	code, err := totp.GenerateCode(secret, time.Now())
+	if err != nil {
+		t.Fatalf("failed to generate OTP code: %v", err)
+	}
+	gin.SetMode(gin.TestMode)
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodPost, "/api/traders/trader-a/kill-switch", strings.NewReader(`{"otp_code":"`+code+`"}`))
+	c.Request.Header.Set("Content-Type", "application/json")
+	c.Params = gin.Params{{Key: "id", Value: "trader-a"}}
+	c.Set("user_id", userID)
+
+	s := &Server{store: st, traderManager: manager.NewTraderManager()}
+	s.handleTraderKillSwitch(c)
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+
+	var report KillSwitchReport
+	if err := json.Unmarshal(w.Body.Bytes(), &report); err != nil {
+		t.Fatalf("failed to decode report: %v", err)
+	}
+	var stopped []string
+	for _, result := range report.Traders {
+		if !result.Stopped {
+			t.Errorf("trader %s not stopped: %s", result.TraderID, result.Error)
+		}
+		stopped = append(stopped, result.TraderID)
+	}
+	if strings.Join(stopped, ",") != "trader-a,trader-b" {
+		t.Errorf("expected both traders on the shared account to be stopped, got %v", stopped)
+	}
+	if len(report.Exchanges) != 1 || report.Exchanges[0].ExchangeID != sharedID {
+		t.Errorf("expected only the shared account to be flattened, got %+v", report.Exchanges)
+	}
+
+	for id, wantRunning := range map[string]bool{"trader-a": false, "trader-b": false, "trader-c": true} {
+		tr, err := st.Trader().GetByID(id)
+		if err != nil {
+			t.Fatalf("failed to get trader %s: %v", id, err)
+		}
+		if tr.IsRunning != wantRunning {
+			t.Errorf("trader %s: expected is_running=%v, got %v", id, wantRunning, tr.IsRunning)
+		}
+	}
+}
+
+// TestTraderKillSwitchFlattensFollowerAccounts tests the per-trader kill switch also stops and flattens
+// the exchange accounts of the trader's copy-trading followers
+func TestTraderKillSwitchFlattensFollowerAccounts(t *testing.T) {
+	st, err := store.New(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("failed to open store: %v", err)
+	}
+	defer st.Close()
+
+	secret, err := auth.GenerateOTPSecret()
+	if err != nil {
+		t.Fatalf("failed to generate OTP secret: %v", err)
+	}
+	userID := "user-1"
+	if err := st.User().Create(&store.User{ID: userID, Email: "a@b.c", OTPSecret: secret, OTPVerified: true}); err != nil {
+		t.Fatalf("failed to create user: %v", err)
+	}
+	if err := st.AIModel().Create(userID, "model-1", "DeepSeek", "deepseek", true, "", ""); err != nil {
+		t.Fatalf("failed to create AI model: %v", err)
+	}
+
+	leaderID, err := st.Exchange().Create(userID, "offline", "Leader", true, "", "", "", false, "", "", "", "", "", "", "", "", "", 0, "")
+	if err != nil {
+		t.Fatalf("failed to create exchange: %v", err)
+	}
+	followerID, err := st.Exchange().Create(userID, "offline", "Team", true, "", "", "", false, "", "", "", "", "", "", "", "", "", 0, "")
+	if err != nil {
+		t.Fatalf("failed to create exchange: %v", err)
+	}
+	for _, tr := range []*store.Trader{
+		{ID: "trader-a", UserID: userID, Name: "A", AIModelID: "model-1", ExchangeID: leaderID, IsRunning: true},
+		{ID: "trader-d", UserID: userID, Name: "D", AIModelID: "model-1", ExchangeID: followerID, IsRunning: true},
+	} {
+		if err := st.Trader().Create(tr); err != nil {
+			t.Fatalf("failed to create trader: %v", err)
+		}
+	}
+	if err := st.Follower().Create(&store.Follower{UserID: userID, LeaderTraderID: "trader-a", ExchangeID: followerID, Name: "team", Enabled: true}); err != nil {
+		t.Fatalf("failed to create follower: %v", err)
+	}
+
+	code, err := totp.GenerateCode(secret, time.Now())
+	if err != nil {
+		t.Fatalf("failed to generate OTP code: %v", err)
+	}
+	gin.SetMode(gin.TestMode)
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodPost, "/api/traders/trader-a/kill-switch", strings.NewReader(`{"otp_code":"`+code+`"}`))
+	c.Request.Header.Set("Content-Type", "application/json")
+	c.Params = gin.Params{{Key: "id", Value: "trader-a"}}
+	c.Set("user_id", userID)
+
+	s := &Server{store: st, traderManager: manager.NewTraderManager()}
+	s.handleTraderKillSwitch(c)
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+
+	var report KillSwitchReport
+	if err := json.Unmarshal(w.Body.Bytes(), &report); err != nil {
+		t.Fatalf("failed to decode report: %v", err)
+	}
+	var stopped []string
+	for _, result := range report.Traders {
+		stopped = append(stopped, result.TraderID)
+	}
+	if strings.Join(stopped, ",") != "trader-a,trader-d" {
+		t.Errorf("expected the leader and the trader on the follower account to be stopped, got %v", stopped)
+	}
+	var flattened []string
+	for _, result := range report.Exchanges {
+		flattened = append(flattened, result.ExchangeID)
+	}
+	if strings.Join(flattened, ",") != leaderID+","+followerID {
+		t.Errorf("expected the leader and follower accounts to be flattened, got %v", flattened)
+	}
+}
@@ -14,6 +14,7 @@ import (
 	"nofx/manager"
 	"nofx/store"
 	"nofx/trader"
+	"strconv"
 	"strings"
 	"time"
 
@@ -29,6 +30,7 @@ type Server struct {
 	cryptoHandler   *CryptoHandler
 	backtestManager *backtest.Manager
 	debateHandler   *DebateHandler
+	positionSync    *trader.PositionSyncManager
 	httpServer      *http.Server
 	port            int
 }
@@ -143,6 +145,26 @@ func (s *Server) setupRoutes() {
 			protected.POST("/traders/:id/sync-balance", s.handleSyncBalance)
 			protected.POST("/traders/:id/close-position", s.handleClosePosition)
 			protected.PUT("/traders/:id/competition", s.handleToggleCompetition)
+			protected.GET("/traders/:id/execution-quality", s.handleExecutionQuality)
+			protected.POST("/traders/:id/trigger", s.handleTriggerCycle)
+			protected.POST("/traders/:id/kill-switch", s.handleTraderKillSwitch)
+			protected.POST("/traders/:id/positions/rebuild", s.handleRebuildPositions)
+
+			// Copy-trading followers (replicate the trader's orders on other exchange accounts)
+			protected.GET("/traders/:id/followers", s.handleListFollowers)
+			protected.POST("/traders/:id/followers", s.handleCreateFollower)
+			protected.PUT("/traders/:id/followers/:followerId", s.handleUpdateFollower)
+			protected.DELETE("/traders/:id/followers/:followerId", s.handleDeleteFollower)
+			protected.GET("/traders/:id/followers/executions", s.handleFollowerExecutions)
+
+			// Trade journal (reflections on closed positions added to the prompt)
+			protected.GET("/traders/:id/journal", s.handleListJournal)
+			protected.PUT("/traders/:id/journal/:entryId", s.handleUpdateJournalEntry)
+			protected.DELETE("/traders/:id/journal/:entryId", s.handleDeleteJournalEntry)
+
+			// Kill switch (OTP re-verification): stop traders, cancel orders, close all positions
+			protected.POST("/kill-switch", s.handleKillSwitch)
+			protected.POST("/exchanges/:id/kill-switch", s.handleExchangeKillSwitch)
 
 			// AI model configuration
 			protected.GET("/models", s.handleGetModelConfigs)
@@ -158,6 +180,7 @@ func (s *Server) setupRoutes() {
 			protected.GET("/strategies", s.handleGetStrategies)
 			protected.GET("/strategies/active", s.handleGetActiveStrategy)
 			protected.GET("/strategies/default-config", s.handleGetDefaultStrategyConfig)
+			protected.GET("/strategies/indicators", s.handleGetIndicators)
 			protected.POST("/strategies/preview-prompt", s.handlePreviewPrompt)
 			protected.POST("/strategies/test-run", s.handleStrategyTestRun)
 			protected.GET("/strategies/:id", s.handleGetStrategy)
@@ -166,6 +189,7 @@ func (s *Server) setupRoutes() {
 			protected.DELETE("/strategies/:id", s.handleDeleteStrategy)
 			protected.POST("/strategies/:id/activate", s.handleActivateStrategy)
 			protected.POST("/strategies/:id/duplicate", s.handleDuplicateStrategy)
+			protected.POST("/strategies/:id/blackouts/import", s.handleImportBlackouts)
 
 			// Debate Arena
 			protected.GET("/debates", s.debateHandler.HandleListDebates)
@@ -424,16 +448,19 @@ type ExchangeConfig struct {
 // SafeExchangeConfig Safe exchange configuration structure (does not contain sensitive information)
 type SafeExchangeConfig struct {
 	ID                    string `json:"id"`            // UUID
-	ExchangeType          string `json:"exchange_type"` // "binance", "bybit", "okx", "hyperliquid", "aster", "lighter"
+	ExchangeType          string `json:"exchange_type"` // "binance", "bybit", "okx", "hyperliquid", "aster", "lighter", "dydx"
 	AccountName           string `json:"account_name"`  // User-defined account name
 	Name                  string `json:"name"`          // Display name
 	Type                  string `json:"type"`          // "cex" or "dex"
 	Enabled               bool   `json:"enabled"`
 	Testnet               bool   `json:"testnet,omitempty"`
 	HyperliquidWalletAddr string `json:"hyperliquidWalletAddr"` // Hyperliquid wallet address (not sensitive)
+	HyperliquidVaultAddr  string `json:"hyperliquidVaultAddr"`  // Hyperliquid sub-account or vault address (not sensitive)
 	AsterUser             string `json:"asterUser"`             // Aster username (not sensitive)
 	AsterSigner           string `json:"asterSigner"`           // Aster signer (not sensitive)
 	LighterWalletAddr     string `json:"lighterWalletAddr"`     // LIGHTER wallet address (not sensitive)
+	DydxAddress           string `json:"dydxAddress"`           // dYdX address (not sensitive)
+	DydxSubaccount        int    `json:"dydxSubaccount"`        // dYdX subaccount number
 }
 
 type UpdateModelConfigRequest struct {
@@ -459,6 +486,10 @@ type UpdateExchangeConfigRequest struct {
 		LighterWalletAddr       string `json:"lighter_wallet_addr"`
 		LighterPrivateKey       string `json:"lighter_private_key"`
 		LighterAPIKeyPrivateKey string `json:"lighter_api_key_private_key"`
+		DydxAddress             string `json:"dydx_address"`
+		DydxMnemonic            string `json:"dydx_mnemonic"`
+		DydxSubaccount          int    `json:"dydx_subaccount"`
+		HyperliquidVaultAddr    string `json:"hyperliquid_vault_addr"`
 	} `json:"exchanges"`
 }
 
@@ -554,61 +585,8 @@ func (s *Server) handleCreateTrader(c *gin.Context) {
 	} else if !exchangeCfg.Enabled {
 		logger.Infof("⚠️ Exchange %s not enabled, using user input for initial balance", req.ExchangeID)
 	} else {
-		// Create temporary trader based on exchange type to query balance
-		var tempTrader trader.Trader
-		var createErr error
-
-		// Use ExchangeType (e.g., "binance") instead of ID (UUID)
-		switch exchangeCfg.ExchangeType {
-		case "binance":
-			tempTrader = trader.NewFuturesTrader(exchangeCfg.APIKey, exchangeCfg.SecretKey, userID)
-		case "hyperliquid":
-			tempTrader, createErr = trader.NewHyperliquidTrader(
-				exchangeCfg.APIKey, // private key
-				exchangeCfg.HyperliquidWalletAddr,
-				exchangeCfg.Testnet,
-			)
-		case "aster":
-			tempTrader, createErr = trader.NewAsterTrader(
-				exchangeCfg.AsterUser,
-				exchangeCfg.AsterSigner,
-				exchangeCfg.AsterPrivateKey,
-			)
-		case "bybit":
-			tempTrader = trader.NewBybitTrader(
-				exchangeCfg.APIKey,
-				exchangeCfg.SecretKey,
-			)
-		case "okx":
-			tempTrader = trader.NewOKXTrader(
-				exchangeCfg.APIKey,
-				exchangeCfg.SecretKey,
-				exchangeCfg.Passphrase,
-			)
-		case "bitget":
-			tempTrader = trader.NewBitgetTrader(
-				exchangeCfg.APIKey,
-				exchangeCfg.SecretKey,
-				exchangeCfg.Passphrase,
-			)
-		case "lighter":
-			if exchangeCfg.LighterAPIKeyPrivateKey != "" {
-				tempTrader, createErr = trader.NewLighterTraderV2(
-					exchangeCfg.LighterPrivateKey,
-					exchangeCfg.LighterWalletAddr,
-					exchangeCfg.LighterAPIKeyPrivateKey,
-					exchangeCfg.Testnet,
-				)
-			} else {
-				tempTrader, createErr = trader.NewLighterTrader(
-					exchangeCfg.LighterPrivateKey,
-					exchangeCfg.LighterWalletAddr,
-					exchangeCfg.Testnet,
-				)
-			}
-		default:
-			logger.Infof("⚠️ Unsupported exchange type: %s, using user input for initial balance", exchangeCfg.ExchangeType)
-		}
+		// Create temporary trader to query balance
+		tempTrader, createErr := trader.NewTraderFromExchange(exchangeCfg, userID)
 
 		if createErr != nil {
 			logger.Infof("⚠️ Failed to create temporary trader, using user input for initial balance: %v", createErr)
@@ -1072,61 +1050,7 @@ func (s *Server) handleSyncBalance(c *gin.Context) {
 	}
 
 	// Create temporary trader to query balance
-	var tempTrader trader.Trader
-	var createErr error
-
-	// Use ExchangeType (e.g., "binance") instead of ExchangeID (which is now UUID)
-	switch exchangeCfg.ExchangeType {
-	case "binance":
-		tempTrader = trader.NewFuturesTrader(exchangeCfg.APIKey, exchangeCfg.SecretKey, userID)
-	case "hyperliquid":
-		tempTrader, createErr = trader.NewHyperliquidTrader(
-			exchangeCfg.APIKey,
-			exchangeCfg.HyperliquidWalletAddr,
-			exchangeCfg.Testnet,
-		)
-	case "aster":
-		tempTrader, createErr = trader.NewAsterTrader(
-			exchangeCfg.AsterUser,
-			exchangeCfg.AsterSigner,
-			exchangeCfg.AsterPrivateKey,
-		)
-	case "bybit":
-		tempTrader = trader.NewBybitTrader(
-			exchangeCfg.APIKey,
-			exchangeCfg.SecretKey,
-		)
-	case "okx":
-		tempTrader = trader.NewOKXTrader(
-			exchangeCfg.APIKey,
-			exchangeCfg.SecretKey,
-			exchangeCfg.Passphrase,
-		)
-	case "bitget":
-		tempTrader = trader.NewBitgetTrader(
-			exchangeCfg.APIKey,
-			exchangeCfg.SecretKey,
-			exchangeCfg.Passphrase,
-		)
-	case "lighter":
-		if exchangeCfg.LighterAPIKeyPrivateKey != "" {
-			tempTrader, createErr = trader.NewLighterTraderV2(
-				exchangeCfg.LighterPrivateKey,
-				exchangeCfg.LighterWalletAddr,
-				exchangeCfg.LighterAPIKeyPrivateKey,
-				exchangeCfg.Testnet,
-			)
-		} else {
-			tempTrader, createErr = trader.NewLighterTrader(
-				exchangeCfg.LighterPrivateKey,
-				exchangeCfg.LighterWalletAddr,
-				exchangeCfg.Testnet,
-			)
-		}
-	default:
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Unsupported exchange type"})
-		return
-	}
+	tempTrader, createErr := trader.NewTraderFromExchange(exchangeCfg, userID)
 
 	if createErr != nil {
 		logger.Infof("⚠️ Failed to create temporary trader: %v", createErr)
@@ -1226,61 +1150,7 @@ func (s *Server) handleClosePosition(c *gin.Context) {
 	}
 
 	// Create temporary trader to execute close position
-	var tempTrader trader.Trader
-	var createErr error
-
-	// Use ExchangeType (e.g., "binance") instead of ExchangeID (which is now UUID)
-	switch exchangeCfg.ExchangeType {
-	case "binance":
-		tempTrader = trader.NewFuturesTrader(exchangeCfg.APIKey, exchangeCfg.SecretKey, userID)
-	case "hyperliquid":
-		tempTrader, createErr = trader.NewHyperliquidTrader(
-			exchangeCfg.APIKey,
-			exchangeCfg.HyperliquidWalletAddr,
-			exchangeCfg.Testnet,
-		)
-	case "aster":
-		tempTrader, createErr = trader.NewAsterTrader(
-			exchangeCfg.AsterUser,
-			exchangeCfg.AsterSigner,
-			exchangeCfg.AsterPrivateKey,
-		)
-	case "bybit":
-		tempTrader = trader.NewBybitTrader(
-			exchangeCfg.APIKey,
-			exchangeCfg.SecretKey,
-		)
-	case "okx":
-		tempTrader = trader.NewOKXTrader(
-			exchangeCfg.APIKey,
-			exchangeCfg.SecretKey,
-			exchangeCfg.Passphrase,
-		)
-	case "bitget":
-		tempTrader = trader.NewBitgetTrader(
-			exchangeCfg.APIKey,
-			exchangeCfg.SecretKey,
-			exchangeCfg.Passphrase,
-		)
-	case "lighter":
-		if exchangeCfg.LighterAPIKeyPrivateKey != "" {
-			tempTrader, createErr = trader.NewLighterTraderV2(
-				exchangeCfg.LighterPrivateKey,
-				exchangeCfg.LighterWalletAddr,
-				exchangeCfg.LighterAPIKeyPrivateKey,
-				exchangeCfg.Testnet,
-			)
-		} else {
-			tempTrader, createErr = trader.NewLighterTrader(
-				exchangeCfg.LighterPrivateKey,
-				exchangeCfg.LighterWalletAddr,
-				exchangeCfg.Testnet,
-			)
-		}
-	default:
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Unsupported exchange type"})
-		return
-	}
+	tempTrader, createErr := trader.NewTraderFromExchange(exchangeCfg, userID)
 
 	if createErr != nil {
 		logger.Infof("⚠️ Failed to create temporary trader: %v", createErr)
@@ -1473,9 +1343,12 @@ func (s *Server) handleGetExchangeConfigs(c *gin.Context) {
 			Enabled:               exchange.Enabled,
 			Testnet:               exchange.Testnet,
 			HyperliquidWalletAddr: exchange.HyperliquidWalletAddr,
+			HyperliquidVaultAddr:  exchange.HyperliquidVaultAddr,
 			AsterUser:             exchange.AsterUser,
 			AsterSigner:           exchange.AsterSigner,
 			LighterWalletAddr:     exchange.LighterWalletAddr,
+			DydxAddress:           exchange.DydxAddress,
+			DydxSubaccount:        exchange.DydxSubaccount,
 		}
 	}
 
@@ -1544,7 +1417,7 @@ func (s *Server) handleUpdateExchangeConfigs(c *gin.Context) {
 
 	// Update each exchange's configuration
 	for exchangeID, exchangeData := range req.Exchanges {
-		err := s.store.Exchange().Update(userID, exchangeID, exchangeData.Enabled, exchangeData.APIKey, exchangeData.SecretKey, exchangeData.Passphrase, exchangeData.Testnet, exchangeData.HyperliquidWalletAddr, exchangeData.AsterUser, exchangeData.AsterSigner, exchangeData.AsterPrivateKey, exchangeData.LighterWalletAddr, exchangeData.LighterPrivateKey, exchangeData.LighterAPIKeyPrivateKey)
+		err := s.store.Exchange().Update(userID, exchangeID, exchangeData.Enabled, exchangeData.APIKey, exchangeData.SecretKey, exchangeData.Passphrase, exchangeData.Testnet, exchangeData.HyperliquidWalletAddr, exchangeData.AsterUser, exchangeData.AsterSigner, exchangeData.AsterPrivateKey, exchangeData.LighterWalletAddr, exchangeData.LighterPrivateKey, exchangeData.LighterAPIKeyPrivateKey, exchangeData.DydxAddress, exchangeData.DydxMnemonic, exchangeData.DydxSubaccount, exchangeData.HyperliquidVaultAddr)
 		if err != nil {
 			c.JSON(http.StatusInternalServerError, gin.H{"error": fmt.Sprintf("Failed to update exchange %s: %v", exchangeID, err)})
 			return
@@ -1564,7 +1437,7 @@ func (s *Server) handleUpdateExchangeConfigs(c *gin.Context) {
 
 // CreateExchangeRequest request structure for creating a new exchange account
 type CreateExchangeRequest struct {
-	ExchangeType            string `json:"exchange_type" binding:"required"` // "binance", "bybit", "okx", "hyperliquid", "aster", "lighter"
+	ExchangeType            string `json:"exchange_type" binding:"required"` // "binance", "bybit", "okx", "hyperliquid", "aster", "lighter", "dydx"
 	AccountName             string `json:"account_name"`                     // User-defined account name
 	Enabled                 bool   `json:"enabled"`
 	APIKey                  string `json:"api_key"`
@@ -1578,6 +1451,10 @@ type CreateExchangeRequest struct {
 	LighterWalletAddr       string `json:"lighter_wallet_addr"`
 	LighterPrivateKey       string `json:"lighter_private_key"`
 	LighterAPIKeyPrivateKey string `json:"lighter_api_key_private_key"`
+	DydxAddress             string `json:"dydx_address"`
+	DydxMnemonic            string `json:"dydx_mnemonic"`
+	DydxSubaccount          int    `json:"dydx_subaccount"`
+	HyperliquidVaultAddr    string `json:"hyperliquid_vault_addr"`
 }
 
 // handleCreateExchange Create a new exchange account
@@ -1633,8 +1510,8 @@ func (s *Server) handleCreateExchange(c *gin.Context) {
 
 	// Validate exchange type
 	validTypes := map[string]bool{
-		"binance": true, "bybit": true, "okx": true, "bitget": true,
-		"hyperliquid": true, "aster": true, "lighter": true,
+		"binance": true, "bybit": true, "okx": true, "bitget": true, "gate": true,
+		"hyperliquid": true, "aster": true, "lighter": true, "dydx": true,
 	}
 	if !validTypes[req.ExchangeType] {
 		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("Invalid exchange type: %s", req.ExchangeType)})
@@ -1647,6 +1524,7 @@ func (s *Server) handleCreateExchange(c *gin.Context) {
 		req.APIKey, req.SecretKey, req.Passphrase, req.Testnet,
 		req.HyperliquidWalletAddr, req.AsterUser, req.AsterSigner, req.AsterPrivateKey,
 		req.LighterWalletAddr, req.LighterPrivateKey, req.LighterAPIKeyPrivateKey,
+		req.DydxAddress, req.DydxMnemonic, req.DydxSubaccount, req.HyperliquidVaultAddr,
 	)
 	if err != nil {
 		logger.Infof("❌ Failed to create exchange account: %v", err)
@@ -1956,6 +1834,116 @@ func (s *Server) handleStatistics(c *gin.Context) {
 	c.JSON(http.StatusOK, stats)
 }
 
+// handleExecutionQuality Execution quality report (slippage and latency) of a trader
+// Query: days (default 30)
+func (s *Server) handleExecutionQuality(c *gin.Context) {
+	userID := c.GetString("user_id")
+	traderID := c.Param("id")
+
+	traderRecord, err := s.store.Trader().GetByID(traderID)
+	if err != nil || traderRecord.UserID != userID {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Trader does not exist"})
+		return
+	}
+
+	days := 30
+	if daysStr := c.Query("days"); daysStr != "" {
+		if d, err := strconv.Atoi(daysStr); err == nil && d > 0 {
+			days = d
+		}
+	}
+	since := time.Now().AddDate(0, 0, -days)
+
+	report, err := s.store.Execution().GetQualityReport(traderID, since)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": fmt.Sprintf("Failed to get execution quality: %v", err),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, report)
+}
+
+// handleRebuildPositions rebuilds a trader's closed position history from exchange fills since a date
+func (s *Server) handleRebuildPositions(c *gin.Context) {
+	userID := c.GetString("user_id")
+	traderID := c.Param("id")
+
+	traderRecord, err := s.store.Trader().GetByID(traderID)
+	if err != nil || traderRecord.UserID != userID {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Trader does not exist"})
+		return
+	}
+
+	var req struct {
+		Since string `json:"since" binding:"required"` // YYYY-MM-DD or RFC3339
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	since, err := time.Parse(time.RFC3339, req.Since)
+	if err != nil {
+		if since, err = time.Parse("2006-01-02", req.Since); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "since must be a date (YYYY-MM-DD) or RFC3339 time"})
+			return
+		}
+	}
+	if since.After(time.Now()) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "since must be in the past"})
+		return
+	}
+
+	if s.positionSync == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "Position sync is not running"})
+		return
+	}
+
+	result, err := s.positionSync.RebuildHistory(traderID, since)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": fmt.Sprintf("Failed to rebuild positions: %v", err),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+// handleTriggerCycle starts an out-of-band decision cycle from an external signal
+func (s *Server) handleTriggerCycle(c *gin.Context) {
+	userID := c.GetString("user_id")
+	traderID := c.Param("id")
+
+	traderRecord, err := s.store.Trader().GetByID(traderID)
+	if err != nil || traderRecord.UserID != userID {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Trader does not exist"})
+		return
+	}
+
+	var req struct {
+		Reason string `json:"reason" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	trader, err := s.traderManager.GetTrader(traderID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Trader is not loaded"})
+		return
+	}
+
+	if err := trader.TriggerCycle(req.Reason); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Decision cycle requested"})
+}
+
 // handleCompetition Competition overview (compare all traders)
 func (s *Server) handleCompetition(c *gin.Context) {
 	userID := c.GetString("user_id")
@@ -2403,14 +2391,21 @@ func (s *Server) handleGetSupportedExchanges(c *gin.Context) {
 		{ExchangeType: "binance", Name: "Binance Futures", Type: "cex"},
 		{ExchangeType: "bybit", Name: "Bybit Futures", Type: "cex"},
 		{ExchangeType: "okx", Name: "OKX Futures", Type: "cex"},
+		{ExchangeType: "gate", Name: "Gate Futures", Type: "cex"},
 		{ExchangeType: "hyperliquid", Name: "Hyperliquid", Type: "dex"},
 		{ExchangeType: "aster", Name: "Aster DEX", Type: "dex"},
 		{ExchangeType: "lighter", Name: "LIGHTER DEX", Type: "dex"},
+		{ExchangeType: "dydx", Name: "dYdX v4", Type: "dex"},
 	}
 
 	c.JSON(http.StatusOK, supportedExchanges)
 }
 
+// SetPositionSyncManager Sets the position sync manager used for position history rebuilds
+func (s *Server) SetPositionSyncManager(m *trader.PositionSyncManager) {
+	s.positionSync = m
+}
+
 // Start Start server
 func (s *Server) Start() error {
 	addr := fmt.Sprintf(":%d", s.port)
@@ -2427,6 +2422,11 @@ func (s *Server) Start() error {
 	logger.Infof("  • DELETE /api/traders/:id    - Delete AI trader")
 	logger.Infof("  • POST /api/traders/:id/start - Start AI trader")
 	logger.Infof("  • POST /api/traders/:id/stop  - Stop AI trader")
+	logger.Infof("  • GET  /api/traders/:id/execution-quality - Slippage and latency report")
+	logger.Infof("  • POST /api/traders/:id/trigger - Trigger a decision cycle from an external signal")
+	logger.Infof("  • POST /api/traders/:id/positions/rebuild - Rebuild closed position history from exchange fills")
+	logger.Infof("  • POST /api/kill-switch      - Stop all traders and flatten all exchange accounts (OTP required)")
+	logger.Infof("  • GET  /api/traders/:id/followers - Copy-trading follower accounts of a trader")
 	logger.Infof("  • GET  /api/models           - Get AI model config")
 	logger.Infof("  • PUT  /api/models           - Update AI model config")
 	logger.Infof("  • GET  /api/exchanges        - Get exchange config")
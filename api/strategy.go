@@ -8,6 +8,7 @@ import (
 	"nofx/market"
 	"nofx/mcp"
 	"nofx/store"
+	"sort"
 	"strings"
 	"time"
 
@@ -26,6 +27,50 @@ func validateStrategyConfig(config *store.StrategyConfig) []string {
 		}
 	}
 
+	// Validate custom indicators and expressions
+	if err := decision.ValidateSeriesOptions(config.Indicators); err != nil {
+		warnings = append(warnings, "Custom indicators and expressions: "+err.Error())
+	}
+
+	// Validate screener filters
+	if config.CoinSource.SourceType == "screener" {
+		if err := decision.ValidateScreenerConfig(config.CoinSource.Screener); err != nil {
+			warnings = append(warnings, "Screener: "+err.Error())
+		}
+	}
+
+	// Validate trading session windows
+	if config.TradingSessions.Enabled {
+		if err := decision.ValidateSessionConfig(config.TradingSessions); err != nil {
+			warnings = append(warnings, "Trading sessions: "+err.Error())
+		}
+	}
+
+	// Validate regime thresholds and overrides
+	if config.Regime.Enabled {
+		if err := decision.ValidateRegimeConfig(config.Regime); err != nil {
+			warnings = append(warnings, "Market regime: "+err.Error())
+		}
+	}
+
+	// Validate pre-AI gate conditions
+	if config.AIGate.Enabled {
+		if err := decision.ValidateGateConfig(config.AIGate, config.Triggers.Enabled); err != nil {
+			warnings = append(warnings, "AI gate: "+err.Error())
+		}
+	}
+
+	// Validate rules of rule-based strategies
+	switch config.Mode {
+	case "", store.StrategyModeAI:
+	case store.StrategyModeRules:
+		if err := decision.ValidateRuleConfig(config.Rules); err != nil {
+			warnings = append(warnings, "Rules: "+err.Error())
+		}
+	default:
+		warnings = append(warnings, fmt.Sprintf("Mode: unknown mode %q", config.Mode))
+	}
+
 	return warnings
 }
 
@@ -215,6 +260,80 @@ func (s *Server) handleUpdateStrategy(c *gin.Context) {
 	c.JSON(http.StatusOK, response)
 }
 
+// handleImportBlackouts Import blackout events (CPI, FOMC, ...) from an iCal or CSV file into a strategy
+func (s *Server) handleImportBlackouts(c *gin.Context) {
+	userID := c.GetString("user_id")
+	strategyID := c.Param("id")
+
+	var req struct {
+		Format  string `json:"format" binding:"required"`  // "ics" | "csv"
+		Content string `json:"content" binding:"required"` // file content
+		Replace bool   `json:"replace"`                    // replace existing events instead of merging
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request parameters: " + err.Error()})
+		return
+	}
+
+	strategy, err := s.store.Strategy().Get(userID, strategyID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Strategy not found"})
+		return
+	}
+	if strategy.IsDefault {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Cannot modify system default strategy"})
+		return
+	}
+
+	events, err := decision.ParseBlackoutCalendar([]byte(req.Content), req.Format)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Failed to parse calendar: " + err.Error()})
+		return
+	}
+
+	config, err := strategy.ParseConfig()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if req.Replace {
+		config.TradingSessions.Blackouts = events
+	} else {
+		config.TradingSessions.Blackouts = mergeBlackouts(config.TradingSessions.Blackouts, events)
+	}
+	if err := strategy.SetConfig(config); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if err := s.store.Strategy().Update(strategy); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update strategy: " + err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"imported":  len(events),
+		"total":     len(config.TradingSessions.Blackouts),
+		"blackouts": config.TradingSessions.Blackouts,
+	})
+}
+
+// mergeBlackouts adds imported events, skipping ones already present (same name and start)
+func mergeBlackouts(existing, imported []store.BlackoutEvent) []store.BlackoutEvent {
+	seen := make(map[string]bool, len(existing))
+	for _, e := range existing {
+		seen[e.Name+"|"+e.Start.UTC().Format(time.RFC3339)] = true
+	}
+	merged := append([]store.BlackoutEvent(nil), existing...)
+	for _, e := range imported {
+		if key := e.Name + "|" + e.Start.UTC().Format(time.RFC3339); !seen[key] {
+			seen[key] = true
+			merged = append(merged, e)
+		}
+	}
+	sort.Slice(merged, func(i, j int) bool { return merged[i].Start.Before(merged[j].Start) })
+	return merged
+}
+
 // handleDeleteStrategy Delete strategy
 func (s *Server) handleDeleteStrategy(c *gin.Context) {
 	userID := c.GetString("user_id")
@@ -312,6 +431,11 @@ func (s *Server) handleGetActiveStrategy(c *gin.Context) {
 	})
 }
 
+// handleGetIndicators Get the indicators available for custom_indicators, with their parameters
+func (s *Server) handleGetIndicators(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{"indicators": market.ListIndicators()})
+}
+
 // handleGetDefaultStrategyConfig Get default strategy configuration template
 func (s *Server) handleGetDefaultStrategyConfig(c *gin.Context) {
 	// Get language from query parameter, default to "en"
@@ -361,7 +485,7 @@ func (s *Server) handlePreviewPrompt(c *gin.Context) {
 		req.PromptVariant,
 	)
 
-	c.JSON(http.StatusOK, gin.H{
+	response := gin.H{
 		"system_prompt":  systemPrompt,
 		"prompt_variant": req.PromptVariant,
 		"config_summary": gin.H{
@@ -371,7 +495,12 @@ func (s *Server) handlePreviewPrompt(c *gin.Context) {
 			"altcoin_leverage": req.Config.RiskControl.AltcoinMaxLeverage,
 			"max_positions":    req.Config.RiskControl.MaxPositions,
 		},
-	})
+	}
+	if warnings := validateStrategyConfig(&req.Config); len(warnings) > 0 {
+		response["warnings"] = warnings
+	}
+
+	c.JSON(http.StatusOK, response)
 }
 
 // handleStrategyTestRun AI test run (does not execute trades, only returns AI analysis results)
@@ -440,7 +569,7 @@ func (s *Server) handleStrategyTestRun(c *gin.Context) {
 	// Get real market data (using multiple timeframes)
 	marketDataMap := make(map[string]*market.Data)
 	for _, coin := range candidates {
-		data, err := market.GetWithTimeframes(coin.Symbol, timeframes, primaryTimeframe, klineCount)
+		data, err := market.GetWithTimeframes(coin.Symbol, timeframes, primaryTimeframe, klineCount, decision.SeriesOptions(req.Config.Indicators))
 		if err != nil {
 			// If getting data for a coin fails, log but continue
 			fmt.Printf("⚠️  Failed to get market data for %s: %v\n", coin.Symbol, err)
@@ -449,6 +578,13 @@ func (s *Server) handleStrategyTestRun(c *gin.Context) {
 		marketDataMap[coin.Symbol] = data
 	}
 
+	if req.Config.Indicators.EnableOrderBook {
+		decision.AttachOrderBooks(marketDataMap)
+	}
+	if req.Config.Indicators.EnableLiquidations {
+		decision.AttachLiquidations(marketDataMap)
+	}
+
 	// Fetch quantitative data for each candidate coin
 	symbols := make([]string, 0, len(candidates))
 	for _, c := range candidates {
@@ -482,12 +618,36 @@ func (s *Server) handleStrategyTestRun(c *gin.Context) {
 		OIRankingData:  oiRankingData,
 	}
 
+	if req.Config.Regime.Enabled {
+		decision.AttachRegimes(testContext, &req.Config)
+	}
+
 	// Build System Prompt
 	systemPrompt := engine.BuildSystemPrompt(1000.0, req.PromptVariant)
 
 	// Build User Prompt (using real market data)
 	userPrompt := engine.BuildUserPrompt(testContext)
 
+	// Rule-based strategies are evaluated directly, no AI model needed
+	if req.Config.Mode == store.StrategyModeRules {
+		result, err := engine.RunRules(testContext)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{
+			"system_prompt":   systemPrompt,
+			"user_prompt":     userPrompt,
+			"candidate_count": len(candidates),
+			"candidates":      candidates,
+			"prompt_variant":  req.PromptVariant,
+			"ai_response":     result.CoTTrace,
+			"decisions":       result.Decisions,
+			"note":            "✅ Rules evaluated (no AI call)",
+		})
+		return
+	}
+
 	// If requesting real AI call
 	if req.RunRealAI && req.AIModelID != "" {
 		aiResponse, aiErr := s.runRealAITest(userID, req.AIModelID, systemPrompt, userPrompt)
@@ -2,13 +2,19 @@ package backtest
 
 import (
 	"fmt"
+	"slices"
 	"strings"
 	"time"
 
+	"nofx/decision"
 	"nofx/market"
 	"nofx/store"
+	"nofx/trader"
 )
 
+// promptKlineCount number of klines per timeframe shown in backtest prompts
+const promptKlineCount = 30
+
 // AIConfig defines the AI client configuration used in backtesting.
 type AIConfig struct {
 	Provider    string  `json:"provider"`
@@ -53,6 +59,27 @@ type BacktestConfig struct {
 	CheckpointIntervalBars    int    `json:"checkpoint_interval_bars,omitempty"`
 	CheckpointIntervalSeconds int    `json:"checkpoint_interval_seconds,omitempty"`
 	ReplayDecisionDir         string `json:"replay_decision_dir,omitempty"`
+
+	// Trading session windows and blackout calendar (see store.TradingSessionConfig)
+	// StrategyID loads them from a saved strategy when TradingSessions is not set
+	StrategyID      string                     `json:"strategy_id,omitempty"`
+	TradingSessions store.TradingSessionConfig `json:"trading_sessions,omitempty"`
+
+	// Custom registry indicators and expressions rendered into the prompt (loaded from StrategyID when not set)
+	Indicators  []store.IndicatorSpec  `json:"indicators,omitempty"`
+	Expressions []store.ExpressionSpec `json:"expressions,omitempty"`
+	// Taker buy/sell volume and CVD from kline taker volume (loaded from StrategyID when not set)
+	EnableTakerFlow bool `json:"enable_taker_flow,omitempty"`
+	// Market regime classification and per-regime overrides (loaded from StrategyID when not set)
+	Regime store.RegimeConfig `json:"regime,omitempty"`
+	// "rules" evaluates declarative rules instead of calling the AI (loaded from StrategyID when not set)
+	Mode  string           `json:"mode,omitempty"`
+	Rules store.RuleConfig `json:"rules,omitempty"`
+	// Pre-AI gate, cycles that don't pass it record a wait without an AI call (loaded from StrategyID when not set)
+	AIGate store.AIGateConfig `json:"ai_gate,omitempty"`
+
+	// Exchange venue whose instrument rules (listing, lot step, min notional, max leverage) apply to simulated orders
+	Exchange string `json:"exchange,omitempty"`
 }
 
 // Validate performs validity checks on the configuration and fills in default values.
@@ -149,9 +176,55 @@ func (cfg *BacktestConfig) Validate() error {
 		cfg.Leverage.AltcoinLeverage = 5
 	}
 
+	cfg.Exchange = strings.ToLower(strings.TrimSpace(cfg.Exchange))
+	if cfg.Exchange != "" {
+		for _, sym := range cfg.Symbols {
+			if err := trader.Instruments.CheckListed(trader.Venue{Exchange: cfg.Exchange}, sym); err != nil {
+				return err
+			}
+		}
+	}
+
+	if err := decision.ValidateSeriesOptions(cfg.seriesConfig()); err != nil {
+		return fmt.Errorf("invalid indicators: %w", err)
+	}
+	if err := decision.ValidateSessionConfig(cfg.TradingSessions); err != nil {
+		return fmt.Errorf("invalid trading_sessions: %w", err)
+	}
+	if err := decision.ValidateRegimeConfig(cfg.Regime); err != nil {
+		return fmt.Errorf("invalid regime: %w", err)
+	}
+	if tf := cfg.Regime.Timeframe; cfg.Regime.Enabled && tf != "" && !slices.Contains(cfg.Timeframes, tf) {
+		return fmt.Errorf("regime timeframe %s must be one of the backtest timeframes", tf)
+	}
+	switch cfg.Mode {
+	case "", store.StrategyModeAI:
+	case store.StrategyModeRules:
+		if err := decision.ValidateRuleConfig(cfg.Rules); err != nil {
+			return fmt.Errorf("invalid rules: %w", err)
+		}
+		if tf := cfg.Rules.Timeframe; tf != "" && !slices.Contains(cfg.Timeframes, tf) {
+			return fmt.Errorf("rules timeframe %s must be one of the backtest timeframes", tf)
+		}
+	default:
+		return fmt.Errorf("invalid mode %q", cfg.Mode)
+	}
+	// Backtests have no event triggers
+	if err := decision.ValidateGateConfig(cfg.AIGate, false); err != nil {
+		return fmt.Errorf("invalid ai_gate: %w", err)
+	}
+	if tf := cfg.AIGate.Timeframe; cfg.AIGate.Enabled && tf != "" && !slices.Contains(cfg.Timeframes, tf) {
+		return fmt.Errorf("ai_gate timeframe %s must be one of the backtest timeframes", tf)
+	}
+
 	return nil
 }
 
+// seriesConfig returns the custom indicators, expressions and taker flow switch as strategy indicator config
+func (cfg *BacktestConfig) seriesConfig() store.IndicatorConfig {
+	return store.IndicatorConfig{CustomIndicators: cfg.Indicators, Expressions: cfg.Expressions, EnableTakerFlow: cfg.EnableTakerFlow}
+}
+
 // Duration returns the backtest interval duration.
 func (cfg *BacktestConfig) Duration() time.Duration {
 	if cfg == nil {
@@ -203,7 +276,7 @@ func (cfg *BacktestConfig) ToStrategyConfig() *store.StrategyConfig {
 		Indicators: store.IndicatorConfig{
 			Klines: store.KlineConfig{
 				PrimaryTimeframe:     primaryTF,
-				PrimaryCount:         30,
+				PrimaryCount:         promptKlineCount,
 				LongerTimeframe:      longerTF,
 				LongerCount:          10,
 				EnableMultiTimeframe: len(cfg.Timeframes) > 1,
@@ -220,8 +293,16 @@ func (cfg *BacktestConfig) ToStrategyConfig() *store.StrategyConfig {
 			EMAPeriods:        []int{20, 50},
 			RSIPeriods:        []int{7, 14},
 			ATRPeriods:        []int{14},
+			CustomIndicators:  cfg.Indicators,
+			Expressions:       cfg.Expressions,
+			EnableTakerFlow:   cfg.EnableTakerFlow,
 		},
-		CustomPrompt: cfg.CustomPrompt,
+		CustomPrompt:    cfg.CustomPrompt,
+		TradingSessions: cfg.TradingSessions,
+		Regime:          cfg.Regime,
+		Mode:            cfg.Mode,
+		Rules:           cfg.Rules,
+		AIGate:          cfg.AIGate,
 		RiskControl: store.RiskControlConfig{
 			MaxPositions:                 3,
 			BTCETHMaxLeverage:            cfg.Leverage.BTCETHLeverage,
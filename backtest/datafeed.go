@@ -5,6 +5,7 @@ import (
 	"sort"
 	"time"
 
+	"nofx/decision"
 	"nofx/market"
 )
 
@@ -26,6 +27,7 @@ type DataFeed struct {
 	decisionTimes []int64
 	primaryTF     string
 	longerTF      string
+	seriesOpts    market.SeriesOptions
 }
 
 func NewDataFeed(cfg BacktestConfig) (*DataFeed, error) {
@@ -35,6 +37,7 @@ func NewDataFeed(cfg BacktestConfig) (*DataFeed, error) {
 		timeframes:   append([]string(nil), cfg.Timeframes...),
 		symbolSeries: make(map[string]*symbolSeries),
 		primaryTF:    cfg.DecisionTimeframe,
+		seriesOpts:   decision.SeriesOptions(cfg.seriesConfig()),
 	}
 	copy(df.symbols, cfg.Symbols)
 
@@ -65,34 +68,27 @@ func (df *DataFeed) loadAll() error {
 	for _, symbol := range df.symbols {
 		ss := &symbolSeries{byTF: make(map[string]*timeframeSeries)}
 		for _, tf := range df.timeframes {
-			dur, _ := market.TFDuration(tf)
-			buffer := dur * 200
-			fetchStart := start.Add(-buffer)
-			if fetchStart.Before(time.Unix(0, 0)) {
-				fetchStart = time.Unix(0, 0)
-			}
-			fetchEnd := end.Add(dur)
-
-			klines, err := market.GetKlinesRange(symbol, tf, fetchStart, fetchEnd)
+			series, err := loadSeries(symbol, tf, start, end)
 			if err != nil {
-				return fmt.Errorf("fetch klines for %s %s: %w", symbol, tf, err)
-			}
-			if len(klines) == 0 {
-				return fmt.Errorf("no klines for %s %s", symbol, tf)
-			}
-
-			series := &timeframeSeries{
-				klines:     klines,
-				closeTimes: make([]int64, len(klines)),
-			}
-			for i, k := range klines {
-				series.closeTimes[i] = k.CloseTime
+				return err
 			}
 			ss.byTF[tf] = series
 		}
 		df.symbolSeries[symbol] = ss
 	}
 
+	// The market regime is read from BTCUSDT, load it like live trading does even when it isn't traded
+	if df.cfg.Regime.Enabled {
+		if _, ok := df.symbolSeries[decision.RegimeMarketSymbol]; !ok {
+			tf := df.regimeTimeframe()
+			series, err := loadSeries(decision.RegimeMarketSymbol, tf, start, end)
+			if err != nil {
+				return fmt.Errorf("market regime: %w", err)
+			}
+			df.symbolSeries[decision.RegimeMarketSymbol] = &symbolSeries{byTF: map[string]*timeframeSeries{tf: series}}
+		}
+	}
+
 	// Generate backtest progress timeline using the primary timeframe of the first symbol
 	firstSymbol := df.symbols[0]
 	primarySeries := df.symbolSeries[firstSymbol].byTF[df.primaryTF]
@@ -119,6 +115,42 @@ func (df *DataFeed) loadAll() error {
 	return nil
 }
 
+// loadSeries fetches the klines of a symbol's timeframe covering the backtest range plus an indicator warm-up buffer
+func loadSeries(symbol, tf string, start, end time.Time) (*timeframeSeries, error) {
+	dur, _ := market.TFDuration(tf)
+	buffer := dur * 200
+	fetchStart := start.Add(-buffer)
+	if fetchStart.Before(time.Unix(0, 0)) {
+		fetchStart = time.Unix(0, 0)
+	}
+	fetchEnd := end.Add(dur)
+
+	klines, err := market.GetKlinesRange(symbol, tf, fetchStart, fetchEnd)
+	if err != nil {
+		return nil, fmt.Errorf("fetch klines for %s %s: %w", symbol, tf, err)
+	}
+	if len(klines) == 0 {
+		return nil, fmt.Errorf("no klines for %s %s", symbol, tf)
+	}
+
+	series := &timeframeSeries{
+		klines:     klines,
+		closeTimes: make([]int64, len(klines)),
+	}
+	for i, k := range klines {
+		series.closeTimes[i] = k.CloseTime
+	}
+	return series, nil
+}
+
+// regimeTimeframe timeframe regimes are classified on (default: decision timeframe)
+func (df *DataFeed) regimeTimeframe() string {
+	if tf := df.cfg.Regime.Timeframe; tf != "" {
+		return tf
+	}
+	return df.primaryTF
+}
+
 func (df *DataFeed) DecisionBarCount() int {
 	return len(df.decisionTimes)
 }
@@ -138,12 +170,29 @@ func (df *DataFeed) sliceUpTo(symbol, tf string, ts int64) []market.Kline {
 	return series.klines[:idx]
 }
 
+// klinesUpTo returns the symbol's klines closed by ts, limited to the window live trading caches
+func (df *DataFeed) klinesUpTo(symbol, tf string, ts int64) ([]market.Kline, error) {
+	ss, ok := df.symbolSeries[symbol]
+	if !ok {
+		return nil, fmt.Errorf("no data for %s", symbol)
+	}
+	if _, ok := ss.byTF[tf]; !ok {
+		return nil, fmt.Errorf("no %s data for %s", tf, symbol)
+	}
+	window := df.sliceUpTo(symbol, tf, ts)
+	if len(window) > market.KlineCacheSize {
+		window = window[len(window)-market.KlineCacheSize:]
+	}
+	return window, nil
+}
+
 func (df *DataFeed) BuildMarketData(ts int64) (map[string]*market.Data, map[string]map[string]*market.Data, error) {
 	result := make(map[string]*market.Data, len(df.symbols))
 	multi := make(map[string]map[string]*market.Data, len(df.symbols))
 
 	for _, symbol := range df.symbols {
 		perTF := make(map[string]*market.Data, len(df.timeframes))
+		timeframeData := make(map[string]*market.TimeframeSeriesData, len(df.timeframes))
 		for _, tf := range df.timeframes {
 			series := df.sliceUpTo(symbol, tf, ts)
 			if len(series) == 0 {
@@ -158,13 +207,22 @@ func (df *DataFeed) BuildMarketData(ts int64) (map[string]*market.Data, map[stri
 				return nil, nil, err
 			}
 			perTF[tf] = data
+			// Live trading computes indicators over the cached kline window, use the same window
+			window := series
+			if len(window) > market.KlineCacheSize {
+				window = window[len(window)-market.KlineCacheSize:]
+			}
+			timeframeData[tf] = market.BuildTimeframeSeries(window, tf, promptKlineCount, df.seriesOpts)
 			if tf == df.primaryTF {
 				result[symbol] = data
 			}
 		}
-		if _, ok := perTF[df.primaryTF]; !ok {
+		primary, ok := perTF[df.primaryTF]
+		if !ok {
 			return nil, nil, fmt.Errorf("no primary data for %s at %d", symbol, ts)
 		}
+		// Same per-timeframe series as live trading, so prompts match
+		primary.TimeframeData = timeframeData
 		multi[symbol] = perTF
 	}
 	return result, multi, nil
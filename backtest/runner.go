@@ -17,6 +17,7 @@ import (
 	"nofx/market"
 	"nofx/mcp"
 	"nofx/store"
+	"nofx/trader"
 )
 
 var (
@@ -60,6 +61,9 @@ type Runner struct {
 	aiCache   *AICache
 	cachePath string
 
+	regimes *decision.RegimeState // Regimes of the current decision cycle (nil = disabled)
+	stops   map[string]float64    // Stops set by tighten_stops blackouts (symbol_side -> stop price)
+
 	lockInfo *RunLockInfo
 	lockStop chan struct{}
 }
@@ -104,7 +108,8 @@ func NewRunner(cfg BacktestConfig, mcpClient mcp.AIClient) (*Runner, error) {
 		aiCache   *AICache
 		cachePath string
 	)
-	if cfg.CacheAI || cfg.ReplayOnly || cfg.SharedAICachePath != "" {
+	// Rule-based runs are deterministic and cheap, their decisions aren't cached
+	if cfg.Mode != store.StrategyModeRules && (cfg.CacheAI || cfg.ReplayOnly || cfg.SharedAICachePath != "") {
 		cachePath = cfg.SharedAICachePath
 		if cachePath == "" {
 			cachePath = filepath.Join(runDir(cfg.RunID), "ai_cache.json")
@@ -304,6 +309,29 @@ func (r *Runner) stepOnce() error {
 		}
 		record = rec
 
+		// Trading session restrictions, same rules as live trading
+		ctx.Session = decision.EvaluateSession(r.cfg.TradingSessions, time.UnixMilli(ts))
+		if ctx.Session != nil && !ctx.Session.OpensAllowed {
+			execLog = append(execLog, "🚫 Trading restricted: "+ctx.Session.Reason)
+			if ctx.Session.Blackout != nil && ctx.Session.Action == store.BlackoutActionFlatten {
+				actions, trades, logs := r.flattenPositions(ctx.Session.Blackout.Name, priceMap, ts, callCount)
+				decisionActions = append(decisionActions, actions...)
+				tradeEvents = append(tradeEvents, trades...)
+				execLog = append(execLog, logs...)
+			} else if ctx.Session.Blackout != nil && ctx.Session.Action == store.BlackoutActionTightenStops {
+				execLog = append(execLog, r.tightenStops(ctx.Session, priceMap)...)
+			}
+		}
+
+		// Regimes from the klines closed by ts, same overrides as live trading
+		if r.cfg.Regime.Enabled {
+			ctx.Regimes = r.classifyRegimes(ts)
+		}
+		r.regimes = ctx.Regimes
+
+		record.MarketRegime = ctx.Regimes.MarketLabel()
+		record.Regimes = ctx.Regimes.Labels()
+
 		var (
 			fullDecision *decision.FullDecision
 			fromCache    bool
@@ -338,7 +366,10 @@ func (r *Runner) stepOnce() error {
 				r.setLastError(err)
 			} else {
 				fullDecision = fd
-				if r.cfg.CacheAI && r.aiCache != nil && cacheKey != "" {
+				if fd.Gated {
+					// Gated waits depend on the gate config rather than the AI, they aren't cached
+					execLog = append(execLog, "⏭️ "+fd.CoTTrace)
+				} else if r.cfg.CacheAI && r.aiCache != nil && cacheKey != "" {
 					if err := r.aiCache.Put(cacheKey, r.cfg.PromptVariant, ts, fullDecision); err != nil {
 						logger.Infof("failed to persist ai cache for %s: %v", r.cfg.RunID, err)
 					}
@@ -352,7 +383,7 @@ func (r *Runner) stepOnce() error {
 			sorted := sortDecisionsByPriority(fullDecision.Decisions)
 
 			prevLogs := execLog
-			decisionActions = make([]store.DecisionAction, 0, len(sorted))
+			decisionActions = append(make([]store.DecisionAction, 0, len(sorted)+len(decisionActions)), decisionActions...)
 			execLog = make([]string, 0, len(sorted)+len(prevLogs))
 			if len(prevLogs) > 0 {
 				execLog = append(execLog, prevLogs...)
@@ -385,6 +416,13 @@ func (r *Runner) stepOnce() error {
 		cycleForLog = callCount
 	}
 
+	stopEvents, stopLogs, err := r.checkStops(ts, priceMap, cycleForLog)
+	if err != nil {
+		return err
+	}
+	tradeEvents = append(tradeEvents, stopEvents...)
+	execLog = append(execLog, stopLogs...)
+
 	liquidationEvents, liquidationNote, err := r.checkLiquidation(ts, priceMap, cycleForLog)
 	if err != nil {
 		if record != nil {
@@ -562,9 +600,21 @@ func (r *Runner) invokeAIWithRetry(ctx *decision.Context) (*decision.FullDecisio
 	return nil, lastErr
 }
 
+// classifyRegimes classifies the backtest symbols and the market (BTCUSDT, loaded even when not traded)
+// from the klines closed by ts
+func (r *Runner) classifyRegimes(ts int64) *decision.RegimeState {
+	timeframe := r.feed.regimeTimeframe()
+	return decision.ClassifyRegimes(r.cfg.Regime, timeframe, r.feed.symbols, func(symbol string) ([]market.Kline, error) {
+		return r.feed.klinesUpTo(symbol, timeframe, ts)
+	})
+}
+
 func (r *Runner) executeDecision(dec decision.Decision, priceMap map[string]float64, ts int64, cycle int) (store.DecisionAction, []TradeEvent, string, error) {
 	symbol := dec.Symbol
 	usedLeverage := r.resolveLeverage(dec.Leverage, symbol)
+	if maxLeverage := r.regimes.MaxLeverage(symbol); maxLeverage > 0 && usedLeverage > maxLeverage {
+		usedLeverage = maxLeverage
+	}
 	actionRecord := store.DecisionAction{
 		Action:    dec.Action,
 		Symbol:    symbol,
@@ -578,12 +628,24 @@ func (r *Runner) executeDecision(dec decision.Decision, priceMap map[string]floa
 	}
 	fillPrice := r.executionPrice(symbol, basePrice, ts)
 
+	if dec.Action == "open_long" || dec.Action == "open_short" {
+		if session := decision.EvaluateSession(r.cfg.TradingSessions, time.UnixMilli(ts)); session != nil && !session.OpensAllowed {
+			return actionRecord, nil, "", fmt.Errorf("opening new positions is not allowed: %s", session.Reason)
+		}
+		if reason := r.regimes.EntryBlocked(symbol); reason != "" {
+			return actionRecord, nil, "", fmt.Errorf("opening new positions is not allowed: %s", reason)
+		}
+	}
+
 	switch dec.Action {
 	case "open_long":
 		qty := r.determineQuantity(dec, basePrice)
 		if qty <= 0 {
 			return actionRecord, nil, "", fmt.Errorf("invalid qty")
 		}
+		if err := r.checkOrderRules(symbol, qty, basePrice); err != nil {
+			return actionRecord, nil, "", err
+		}
 		pos, fee, execPrice, err := r.account.Open(symbol, "long", qty, usedLeverage, fillPrice, ts)
 		if err != nil {
 			return actionRecord, nil, "", err
@@ -613,6 +675,9 @@ func (r *Runner) executeDecision(dec decision.Decision, priceMap map[string]floa
 		if qty <= 0 {
 			return actionRecord, nil, "", fmt.Errorf("invalid qty")
 		}
+		if err := r.checkOrderRules(symbol, qty, basePrice); err != nil {
+			return actionRecord, nil, "", err
+		}
 		pos, fee, execPrice, err := r.account.Open(symbol, "short", qty, usedLeverage, fillPrice, ts)
 		if err != nil {
 			return actionRecord, nil, "", err
@@ -718,9 +783,28 @@ func (r *Runner) determineQuantity(dec decision.Decision, price float64) float64
 	if qty < 0 {
 		qty = 0
 	}
+	if inst, ok := r.instrument(dec.Symbol); ok {
+		qty = inst.RoundQuantity(qty)
+	}
 	return qty
 }
 
+// instrument gets the venue trading rules of a symbol (only when the backtest targets an exchange)
+func (r *Runner) instrument(symbol string) (*trader.Instrument, bool) {
+	if r.cfg.Exchange == "" {
+		return nil, false
+	}
+	return trader.Instruments.Get(trader.Venue{Exchange: r.cfg.Exchange}, symbol)
+}
+
+// checkOrderRules validates an order against the venue's minimum quantity and notional
+func (r *Runner) checkOrderRules(symbol string, qty, price float64) error {
+	if inst, ok := r.instrument(symbol); ok {
+		return inst.CheckOrder(qty, price)
+	}
+	return nil
+}
+
 func (r *Runner) determineCloseQuantity(symbol, side string, dec decision.Decision) float64 {
 	for _, pos := range r.account.Positions() {
 		if pos.Symbol == strings.ToUpper(symbol) && pos.Side == side {
@@ -731,6 +815,14 @@ func (r *Runner) determineCloseQuantity(symbol, side string, dec decision.Decisi
 }
 
 func (r *Runner) resolveLeverage(requested int, symbol string) int {
+	leverage := r.defaultLeverage(requested, symbol)
+	if inst, ok := r.instrument(symbol); ok {
+		leverage = inst.CapLeverage(leverage)
+	}
+	return leverage
+}
+
+func (r *Runner) defaultLeverage(requested int, symbol string) int {
 	if requested > 0 {
 		return requested
 	}
@@ -978,14 +1070,118 @@ func (r *Runner) checkLiquidation(ts int64, priceMap map[string]float64, cycle i
 	return events, note, nil
 }
 
-func (r *Runner) shouldTriggerDecision(barIndex int) bool {
-	if r.cfg.DecisionCadenceNBars <= 1 {
-		return true
+// flattenPositions closes all positions for a blackout
+func (r *Runner) flattenPositions(eventName string, priceMap map[string]float64, ts int64, cycle int) ([]store.DecisionAction, []TradeEvent, []string) {
+	var (
+		actions []store.DecisionAction
+		trades  []TradeEvent
+		logs    []string
+	)
+	positions := r.account.Positions()
+	sort.Slice(positions, func(i, j int) bool {
+		return positions[i].Symbol+positions[i].Side < positions[j].Symbol+positions[j].Side
+	})
+	for _, pos := range positions {
+		dec := decision.Decision{Symbol: pos.Symbol, Action: "close_" + pos.Side, Reasoning: "blackout: " + eventName}
+		actionRecord, events, logEntry, err := r.executeDecision(dec, priceMap, ts, cycle)
+		if err != nil {
+			actionRecord.Error = err.Error()
+			logs = append(logs, fmt.Sprintf("❌ Blackout close %s %s: %v", pos.Symbol, pos.Side, err))
+		} else {
+			actionRecord.Success = true
+			logs = append(logs, fmt.Sprintf("🚫 Blackout close %s %s", pos.Symbol, pos.Side))
+		}
+		if logEntry != "" {
+			logs = append(logs, logEntry)
+		}
+		actions = append(actions, actionRecord)
+		trades = append(trades, events...)
 	}
-	if barIndex < 0 {
-		return true
+	return actions, trades, logs
+}
+
+// tightenStops moves the stops of open positions towards the price for a blackout, same rule as live trading
+// A stop is never loosened; checkStops closes the positions whose stop is hit on later bars
+func (r *Runner) tightenStops(session *decision.SessionState, priceMap map[string]float64) []string {
+	if r.stops == nil {
+		r.stops = make(map[string]float64)
 	}
-	return barIndex%r.cfg.DecisionCadenceNBars == 0
+	positions := r.account.Positions()
+	sort.Slice(positions, func(i, j int) bool {
+		return positions[i].Symbol+positions[i].Side < positions[j].Symbol+positions[j].Side
+	})
+	var logs []string
+	for _, pos := range positions {
+		price := priceMap[pos.Symbol]
+		if price <= 0 {
+			continue
+		}
+		posKey := pos.Symbol + "_" + pos.Side
+		stop, ok := session.TightenedStop(pos.Side, price, r.stops[posKey])
+		if !ok {
+			continue
+		}
+		r.stops[posKey] = stop
+		logs = append(logs, fmt.Sprintf("🚫 Blackout stop %s %s → %.4f", pos.Symbol, pos.Side, stop))
+	}
+	return logs
+}
+
+// checkStops closes the positions whose tightened stop is hit, filled at the stop price like liquidations
+// Stops of positions that are no longer open are forgotten
+func (r *Runner) checkStops(ts int64, priceMap map[string]float64, cycle int) ([]TradeEvent, []string, error) {
+	if len(r.stops) == 0 {
+		return nil, nil, nil
+	}
+	var (
+		events []TradeEvent
+		logs   []string
+	)
+	open := make(map[string]bool, len(r.stops))
+	for _, pos := range append([]*position(nil), r.account.Positions()...) {
+		posKey := pos.Symbol + "_" + pos.Side
+		stop, ok := r.stops[posKey]
+		if !ok {
+			continue
+		}
+		price := priceMap[pos.Symbol]
+		if price <= 0 || (pos.Side == "long" && price > stop) || (pos.Side == "short" && price < stop) {
+			open[posKey] = true
+			continue
+		}
+
+		quantity, leverage := pos.Quantity, pos.Leverage
+		realized, fee, finalPrice, err := r.account.Close(pos.Symbol, pos.Side, quantity, stop)
+		if err != nil {
+			return nil, nil, err
+		}
+		events = append(events, TradeEvent{
+			Timestamp:   ts,
+			Symbol:      pos.Symbol,
+			Action:      "close_" + pos.Side,
+			Side:        pos.Side,
+			Quantity:    quantity,
+			Price:       finalPrice,
+			Fee:         fee,
+			OrderValue:  finalPrice * quantity,
+			RealizedPnL: realized - fee,
+			Leverage:    leverage,
+			Cycle:       cycle,
+			Note:        fmt.Sprintf("blackout stop hit at %.4f", stop),
+		})
+		logs = append(logs, fmt.Sprintf("🛑 Blackout stop hit %s %s @ %.4f", pos.Symbol, pos.Side, finalPrice))
+	}
+	for posKey := range r.stops {
+		if !open[posKey] {
+			delete(r.stops, posKey)
+		}
+	}
+	return events, logs, nil
+}
+
+// shouldTriggerDecision uses the same bar cadence as candle-aligned live trading
+func (r *Runner) shouldTriggerDecision(barIndex int) bool {
+	return market.IsDecisionBar(barIndex, r.cfg.DecisionCadenceNBars)
 }
 
 func (r *Runner) handleStop(reason error) {
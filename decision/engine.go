@@ -11,6 +11,7 @@ import (
 	"nofx/provider"
 	"nofx/store"
 	"regexp"
+	"slices"
 	"strings"
 	"time"
 )
@@ -67,7 +68,8 @@ type AccountInfo struct {
 // CandidateCoin candidate coin (from coin pool)
 type CandidateCoin struct {
 	Symbol  string   `json:"symbol"`
-	Sources []string `json:"sources"` // Sources: "ai500" and/or "oi_top"
+	Sources []string `json:"sources"`           // Sources: "ai500" and/or "oi_top", "static", "screener"
+	Reasons []string `json:"reasons,omitempty"` // Why the screener selected the coin
 }
 
 // OITopData open interest growth top data (for AI decision reference)
@@ -108,12 +110,16 @@ type Context struct {
 	CurrentTime     string                             `json:"current_time"`
 	RuntimeMinutes  int                                `json:"runtime_minutes"`
 	CallCount       int                                `json:"call_count"`
+	TriggerReason   string                             `json:"trigger_reason,omitempty"` // Event that started this cycle (empty = scheduled)
+	Session         *SessionState                      `json:"session,omitempty"`        // Trading session restrictions (nil = none)
+	Regimes         *RegimeState                       `json:"regimes,omitempty"`        // Market and symbol regimes (nil = disabled)
 	Account         AccountInfo                        `json:"account"`
 	Positions       []PositionInfo                     `json:"positions"`
 	CandidateCoins  []CandidateCoin                    `json:"candidate_coins"`
 	PromptVariant   string                             `json:"prompt_variant,omitempty"`
 	TradingStats    *TradingStats                      `json:"trading_stats,omitempty"`
 	RecentOrders    []RecentOrder                      `json:"recent_orders,omitempty"`
+	Journal         []JournalEntry                     `json:"journal,omitempty"` // Past trade journal entries, most recent first (selected per cycle)
 	MarketDataMap   map[string]*market.Data            `json:"-"`
 	MultiTFMarket   map[string]map[string]*market.Data `json:"-"`
 	OITopDataMap    map[string]*OITopData              `json:"-"`
@@ -122,6 +128,7 @@ type Context struct {
 	BTCETHLeverage  int                                `json:"-"`
 	AltcoinLeverage int                                `json:"-"`
 	Timeframes      []string                           `json:"-"`
+	Klines          KlineSource                        `json:"-"` // Closed klines for rules and the AI gate (nil = the cycle's per-timeframe series)
 }
 
 // Decision AI trading decision
@@ -150,6 +157,7 @@ type FullDecision struct {
 	RawResponse         string     `json:"raw_response"`
 	Timestamp           time.Time  `json:"timestamp"`
 	AIRequestDurationMs int64      `json:"ai_request_duration_ms,omitempty"`
+	Gated               bool       `json:"gated,omitempty"` // AI call skipped by the pre-AI gate, Decisions holds a synthetic wait
 }
 
 // QuantData quantitative data structure (fund flow, position changes, price changes)
@@ -235,6 +243,21 @@ func GetFullDecisionWithStrategy(ctx *Context, mcpClient mcp.AIClient, engine *S
 		}
 	}
 
+	// Classify regimes unless the caller already did (backtests classify from historical klines)
+	if ctx.Regimes == nil && engine.GetConfig().Regime.Enabled {
+		AttachRegimes(ctx, engine.GetConfig())
+	}
+
+	// Rule-based strategies decide from their rules without an AI call
+	if engine.GetConfig().Mode == store.StrategyModeRules {
+		return engine.RunRules(ctx)
+	}
+
+	// Pre-AI gate: quiet cycles record a wait without calling the AI
+	if gate := EvaluateGate(ctx, engine.GetConfig()); gate != nil && !gate.Open {
+		return gatedDecision(gate), nil
+	}
+
 	// Ensure OITopDataMap is initialized
 	if ctx.OITopDataMap == nil {
 		ctx.OITopDataMap = make(map[string]*OITopData)
@@ -295,12 +318,33 @@ func GetFullDecisionWithStrategy(ctx *Context, mcpClient mcp.AIClient, engine *S
 // Market Data Fetching
 // ============================================================================
 
+// SeriesOptions converts the strategy's custom indicators, expressions and taker flow switch to market series options
+func SeriesOptions(indicators store.IndicatorConfig) market.SeriesOptions {
+	opts := market.SeriesOptions{TakerFlow: indicators.EnableTakerFlow}
+	for _, ci := range indicators.CustomIndicators {
+		opts.Indicators = append(opts.Indicators, market.IndicatorSpec{Name: ci.Name, Params: ci.Params})
+	}
+	for _, ex := range indicators.Expressions {
+		opts.Expressions = append(opts.Expressions, market.ExpressionSpec{Name: ex.Name, Expr: ex.Expr})
+	}
+	return opts
+}
+
+// ValidateSeriesOptions checks the strategy's custom indicators and expressions
+func ValidateSeriesOptions(indicators store.IndicatorConfig) error {
+	opts := SeriesOptions(indicators)
+	if err := market.ValidateIndicatorSpecs(opts.Indicators); err != nil {
+		return err
+	}
+	return market.ValidateExpressionSpecs(opts.Expressions)
+}
+
 // fetchMarketDataWithStrategy fetches market data using strategy config (multiple timeframes)
 func fetchMarketDataWithStrategy(ctx *Context, engine *StrategyEngine) error {
 	config := engine.GetConfig()
 	ctx.MarketDataMap = make(map[string]*market.Data)
 
-	timeframes := config.Indicators.Klines.SelectedTimeframes
+	timeframes := slices.Clone(config.Indicators.Klines.SelectedTimeframes)
 	primaryTimeframe := config.Indicators.Klines.PrimaryTimeframe
 	klineCount := config.Indicators.Klines.PrimaryCount
 
@@ -318,15 +362,28 @@ func fetchMarketDataWithStrategy(ctx *Context, engine *StrategyEngine) error {
 	if primaryTimeframe == "" {
 		primaryTimeframe = timeframes[0]
 	}
+	// Rules and the AI gate are evaluated on the cycle's series, so their timeframes are fetched too
+	for _, tf := range conditionTimeframes(config) {
+		if !slices.Contains(timeframes, tf) {
+			timeframes = append(timeframes, tf)
+		}
+	}
 	if klineCount <= 0 {
 		klineCount = 30
 	}
 
 	logger.Infof("📊 Strategy timeframes: %v, Primary: %s, Kline count: %d", timeframes, primaryTimeframe, klineCount)
 
+	// Candle-aligned cycles only look at closed candles (same bar semantics as backtests)
+	getMarketData := market.GetWithTimeframes
+	if config.CandleAlignment.Enabled {
+		getMarketData = market.GetClosedWithTimeframes
+	}
+	seriesOpts := SeriesOptions(config.Indicators)
+
 	// 1. First fetch data for position coins (must fetch)
 	for _, pos := range ctx.Positions {
-		data, err := market.GetWithTimeframes(pos.Symbol, timeframes, primaryTimeframe, klineCount)
+		data, err := getMarketData(pos.Symbol, timeframes, primaryTimeframe, klineCount, seriesOpts)
 		if err != nil {
 			logger.Infof("⚠️  Failed to fetch market data for position %s: %v", pos.Symbol, err)
 			continue
@@ -347,7 +404,7 @@ func fetchMarketDataWithStrategy(ctx *Context, engine *StrategyEngine) error {
 			continue
 		}
 
-		data, err := market.GetWithTimeframes(coin.Symbol, timeframes, primaryTimeframe, klineCount)
+		data, err := getMarketData(coin.Symbol, timeframes, primaryTimeframe, klineCount, seriesOpts)
 		if err != nil {
 			logger.Infof("⚠️  Failed to fetch market data for %s: %v", coin.Symbol, err)
 			continue
@@ -368,10 +425,44 @@ func fetchMarketDataWithStrategy(ctx *Context, engine *StrategyEngine) error {
 		ctx.MarketDataMap[coin.Symbol] = data
 	}
 
+	if config.Indicators.EnableOrderBook {
+		AttachOrderBooks(ctx.MarketDataMap)
+	}
+	if config.Indicators.EnableLiquidations {
+		AttachLiquidations(ctx.MarketDataMap)
+	}
+
 	logger.Infof("📊 Successfully fetched multi-timeframe market data for %d coins", len(ctx.MarketDataMap))
 	return nil
 }
 
+// AttachOrderBooks adds order book liquidity features to each coin's market data
+func AttachOrderBooks(marketDataMap map[string]*market.Data) {
+	for symbol, data := range marketDataMap {
+		features, err := market.GetOrderBookFeatures(symbol)
+		if err != nil {
+			logger.Infof("⚠️  Failed to fetch order book for %s: %v", symbol, err)
+			continue
+		}
+		data.OrderBook = features
+	}
+}
+
+// AttachLiquidations adds recent liquidation totals to each coin's market data
+func AttachLiquidations(marketDataMap map[string]*market.Data) {
+	if market.WSMonitorCli == nil {
+		return
+	}
+	for symbol, data := range marketDataMap {
+		stats, err := market.WSMonitorCli.GetLiquidationStats(symbol)
+		if err != nil {
+			logger.Infof("⚠️  Failed to get liquidations for %s: %v", symbol, err)
+			continue
+		}
+		data.Liquidations = stats
+	}
+}
+
 // ============================================================================
 // Candidate Coins
 // ============================================================================
@@ -407,6 +498,9 @@ func (e *StrategyEngine) GetCandidateCoins() ([]CandidateCoin, error) {
 	case "oi_top":
 		return e.getOITopCoins(coinSource.OITopLimit)
 
+	case "screener":
+		return e.getScreenerCoins(coinSource.Screener)
+
 	case "mixed":
 		if coinSource.UseCoinPool {
 			poolCoins, err := e.getCoinPoolCoins(coinSource.CoinPoolLimit)
@@ -496,6 +590,72 @@ func (e *StrategyEngine) getOITopCoins(limit int) ([]CandidateCoin, error) {
 	return candidates, nil
 }
 
+// getScreenerCoins runs the built-in screener over the exchange universe
+func (e *StrategyEngine) getScreenerCoins(cfg store.ScreenerConfig) ([]CandidateCoin, error) {
+	results, err := market.RunScreener(screenerFilters(cfg))
+	if err != nil {
+		return nil, err
+	}
+
+	candidates := make([]CandidateCoin, 0, len(results))
+	for _, r := range results {
+		candidates = append(candidates, CandidateCoin{
+			Symbol:  r.Symbol,
+			Sources: []string{"screener"},
+			Reasons: r.Reasons,
+		})
+	}
+	logger.Infof("🔎 Screener selected %d coins", len(candidates))
+	return candidates, nil
+}
+
+// screenerFilters converts the strategy's screener config to market screener filters
+func screenerFilters(cfg store.ScreenerConfig) market.ScreenerFilters {
+	return market.ScreenerFilters{
+		MinQuoteVolume:     cfg.MinQuoteVolume,
+		MinVolatilityPct:   cfg.MinVolatilityPct,
+		MaxVolatilityPct:   cfg.MaxVolatilityPct,
+		MinFundingRatePct:  cfg.MinFundingRatePct,
+		MaxFundingRatePct:  cfg.MaxFundingRatePct,
+		MinOIChangePct:     cfg.MinOIChangePct,
+		MaxOIChangePct:     cfg.MaxOIChangePct,
+		OIChangeHours:      cfg.OIChangeHours,
+		MaxPriceChangeRank: cfg.MaxPriceChangeRank,
+		MinListingDays:     cfg.MinListingDays,
+		RankBy:             cfg.RankBy,
+		Limit:              cfg.Limit,
+	}
+}
+
+// ValidateScreenerConfig checks the screener filters for invalid values and empty bands
+func ValidateScreenerConfig(cfg store.ScreenerConfig) error {
+	switch cfg.RankBy {
+	case "", "volume", "price_change", "volatility", "oi_change":
+	default:
+		return fmt.Errorf("unknown rank_by %q (volume, price_change, volatility or oi_change)", cfg.RankBy)
+	}
+	if cfg.MinQuoteVolume < 0 || cfg.MinVolatilityPct < 0 || cfg.MaxVolatilityPct < 0 {
+		return fmt.Errorf("volume and volatility filters must not be negative")
+	}
+	if cfg.MaxVolatilityPct > 0 && cfg.MinVolatilityPct > cfg.MaxVolatilityPct {
+		return fmt.Errorf("min_volatility_pct is above max_volatility_pct")
+	}
+	if cfg.MinFundingRatePct != nil && cfg.MaxFundingRatePct != nil && *cfg.MinFundingRatePct > *cfg.MaxFundingRatePct {
+		return fmt.Errorf("min_funding_rate_pct is above max_funding_rate_pct")
+	}
+	if cfg.MinOIChangePct != nil && cfg.MaxOIChangePct != nil && *cfg.MinOIChangePct > *cfg.MaxOIChangePct {
+		return fmt.Errorf("min_oi_change_pct is above max_oi_change_pct")
+	}
+	// The open interest history endpoint returns at most 500 hourly entries
+	if cfg.OIChangeHours < 0 || cfg.OIChangeHours > 499 {
+		return fmt.Errorf("oi_change_hours must be between 1 and 499 (0 = default 24)")
+	}
+	if cfg.MaxPriceChangeRank < 0 || cfg.MinListingDays < 0 || cfg.Limit < 0 {
+		return fmt.Errorf("rank, listing age and limit must not be negative")
+	}
+	return nil
+}
+
 // ============================================================================
 // External & Quant Data
 // ============================================================================
@@ -861,6 +1021,30 @@ func (e *StrategyEngine) writeAvailableIndicators(sb *strings.Builder) {
 		sb.WriteString("- Funding rate\n")
 	}
 
+	if indicators.EnableOrderBook {
+		sb.WriteString("- Order book liquidity (spread, top-10 bid/ask imbalance, depth within ±0.5%/±1%, large resting walls)\n")
+	}
+
+	if indicators.EnableLiquidations {
+		sb.WriteString("- Liquidations (long/short liquidated USDT and counts over 5m/15m/1h/4h, pace of the last 5m vs the 1h average)\n")
+	}
+
+	if indicators.EnableTakerFlow {
+		sb.WriteString(fmt.Sprintf("- Taker flow per bar (taker buy/sell USDT volume, delta, CVD over the shown window, count of trades >= %.0f USDT)\n", market.LargeTradeUSDT))
+	}
+
+	for _, spec := range SeriesOptions(indicators).Indicators {
+		desc, err := market.DescribeIndicator(spec)
+		if err != nil {
+			continue
+		}
+		sb.WriteString(fmt.Sprintf("- %s\n", desc))
+	}
+
+	for _, ex := range indicators.Expressions {
+		sb.WriteString(fmt.Sprintf("- %s = %s (custom series)\n", ex.Name, ex.Expr))
+	}
+
 	if len(e.config.CoinSource.StaticCoins) > 0 || e.config.CoinSource.UseCoinPool || e.config.CoinSource.UseOITop {
 		sb.WriteString("- AI500 / OI_Top filter tags (if available)\n")
 	}
@@ -874,6 +1058,29 @@ func (e *StrategyEngine) writeAvailableIndicators(sb *strings.Builder) {
 // Prompt Building - User Prompt
 // ============================================================================
 
+// lastCandleStatus describes whether the last primary timeframe candle in the market data is closed
+func (e *StrategyEngine) lastCandleStatus(ctx *Context) string {
+	if len(ctx.MarketDataMap) == 0 {
+		return ""
+	}
+	timeframe := e.config.Indicators.Klines.PrimaryTimeframe
+	if timeframe == "" {
+		timeframe = "primary"
+	}
+
+	var formingCloseTime int64
+	for _, data := range ctx.MarketDataMap {
+		if data != nil && !data.LastKlineClosed && data.LastKlineCloseTime > formingCloseTime {
+			formingCloseTime = data.LastKlineCloseTime
+		}
+	}
+	if formingCloseTime == 0 {
+		return fmt.Sprintf("Candles: the last %s candle of every series is CLOSED (final values)", timeframe)
+	}
+	closesAt := time.UnixMilli(formingCloseTime + 1).UTC().Format("15:04:05 UTC")
+	return fmt.Sprintf("Candles: the last %s candle is still FORMING (closes at %s), its values are provisional", timeframe, closesAt)
+}
+
 // BuildUserPrompt builds User Prompt based on strategy configuration
 func (e *StrategyEngine) BuildUserPrompt(ctx *Context) string {
 	var sb strings.Builder
@@ -882,6 +1089,31 @@ func (e *StrategyEngine) BuildUserPrompt(ctx *Context) string {
 	sb.WriteString(fmt.Sprintf("Time: %s | Period: #%d | Runtime: %d minutes\n\n",
 		ctx.CurrentTime, ctx.CallCount, ctx.RuntimeMinutes))
 
+	// Out-of-band cycle: tell the AI what happened since the last cycle
+	if ctx.TriggerReason != "" {
+		sb.WriteString(fmt.Sprintf("⚡ Triggered cycle (not scheduled): %s\n\n", ctx.TriggerReason))
+	}
+
+	// State of the last primary timeframe candle
+	if candleStatus := e.lastCandleStatus(ctx); candleStatus != "" {
+		sb.WriteString(candleStatus + "\n\n")
+	}
+
+	// Trading session restrictions
+	if ctx.Session != nil && !ctx.Session.OpensAllowed {
+		sb.WriteString(fmt.Sprintf("🚫 Trading restricted: %s. Opening new positions is NOT allowed this cycle (open_long/open_short will be rejected); only hold, adjust or close existing positions.\n", ctx.Session.Reason))
+		switch ctx.Session.Action {
+		case store.BlackoutActionFlatten:
+			sb.WriteString("All positions are being closed for the blackout.\n")
+		case store.BlackoutActionTightenStops:
+			sb.WriteString("Stop losses of open positions have been tightened for the blackout.\n")
+		}
+		sb.WriteString("\n")
+	}
+
+	// Market regime and market-wide regime overrides
+	sb.WriteString(ctx.Regimes.formatMarketRegime())
+
 	// BTC market
 	if btcData, hasBTC := ctx.MarketDataMap["BTCUSDT"]; hasBTC {
 		sb.WriteString(fmt.Sprintf("BTC: %.2f (1h: %+.2f%%, 4h: %+.2f%%) | MACD: %.4f | RSI: %.2f\n\n",
@@ -915,6 +1147,9 @@ func (e *StrategyEngine) BuildUserPrompt(ctx *Context) string {
 		sb.WriteString("\n")
 	}
 
+	// Past trade journal: why similar trades won or lost
+	sb.WriteString(formatJournal(SelectJournalEntries(ctx, e.config.Journal)))
+
 	// Position information
 	if len(ctx.Positions) > 0 {
 		sb.WriteString("## Current Positions\n")
@@ -937,6 +1172,10 @@ func (e *StrategyEngine) BuildUserPrompt(ctx *Context) string {
 
 		sourceTags := e.formatCoinSourceTag(coin.Sources)
 		sb.WriteString(fmt.Sprintf("### %d. %s%s\n\n", displayedCount, coin.Symbol, sourceTags))
+		if len(coin.Reasons) > 0 {
+			sb.WriteString(fmt.Sprintf("Screener: %s\n\n", strings.Join(coin.Reasons, ", ")))
+		}
+		sb.WriteString(ctx.Regimes.formatSymbolRegime(coin.Symbol))
 		sb.WriteString(e.formatMarketData(marketData))
 
 		if ctx.QuantDataMap != nil {
@@ -985,6 +1224,7 @@ func (e *StrategyEngine) formatPositionInfo(index int, pos PositionInfo, ctx *Co
 		pos.EntryPrice, pos.MarkPrice, pos.Quantity, positionValue, pos.UnrealizedPnLPct, pos.UnrealizedPnL, pos.PeakPnLPct,
 		pos.Leverage, pos.MarginUsed, pos.LiquidationPrice, holdingDuration))
 
+	sb.WriteString(ctx.Regimes.formatSymbolRegime(pos.Symbol))
 	if marketData, ok := ctx.MarketDataMap[pos.Symbol]; ok {
 		sb.WriteString(e.formatMarketData(marketData))
 
@@ -1010,6 +1250,8 @@ func (e *StrategyEngine) formatCoinSourceTag(sources []string) string {
 			return " (OI_Top position growth)"
 		case "static":
 			return " (Manual selection)"
+		case "screener":
+			return " (Screener)"
 		}
 	}
 	return ""
@@ -1052,6 +1294,16 @@ func (e *StrategyEngine) formatMarketData(data *market.Data) string {
 		}
 	}
 
+	if indicators.EnableOrderBook && data.OrderBook != nil {
+		sb.WriteString(market.FormatOrderBookFeatures(data.OrderBook))
+		sb.WriteString("\n")
+	}
+
+	if indicators.EnableLiquidations && data.Liquidations != nil {
+		sb.WriteString(market.FormatLiquidationStats(data.Liquidations))
+		sb.WriteString("\n")
+	}
+
 	if len(data.TimeframeData) > 0 {
 		timeframeOrder := []string{"1m", "3m", "5m", "15m", "30m", "1h", "2h", "4h", "6h", "8h", "12h", "1d", "3d", "1w"}
 		for _, tf := range timeframeOrder {
@@ -1174,6 +1426,22 @@ func (e *StrategyEngine) formatTimeframeSeriesData(sb *strings.Builder, data *ma
 		sb.WriteString(fmt.Sprintf("ATR14: %.4f\n", data.ATR14))
 	}
 
+	// Custom indicators selected by the strategy
+	for _, series := range data.Indicators {
+		sb.WriteString(fmt.Sprintf("%s: %s\n", series.Label, formatFloatSlice(series.Values)))
+	}
+
+	// User-defined expressions, in strategy order
+	for _, ex := range indicators.Expressions {
+		if values, ok := data.Expressions[ex.Name]; ok {
+			sb.WriteString(fmt.Sprintf("%s: %s\n", ex.Name, formatFloatSlice(values)))
+		}
+	}
+
+	if indicators.EnableTakerFlow {
+		sb.WriteString(market.FormatTakerFlow(data.TakerFlow))
+	}
+
 	sb.WriteString("\n")
 }
 
@@ -0,0 +1,94 @@
+package decision
+
+import (
+	"fmt"
+	"nofx/store"
+	"strings"
+	"time"
+)
+
+// GateResult outcome of the pre-AI gate
+type GateResult struct {
+	Open   bool   `json:"open"`
+	Reason string `json:"reason"`
+}
+
+// ValidateGateConfig checks the gate's conditions
+// Without conditions only positions and triggers open an enabled gate, so a flat trader
+// without triggers (and every backtest) would never call the AI
+func ValidateGateConfig(config store.AIGateConfig, triggersEnabled bool) error {
+	if config.Enabled && len(config.Conditions) == 0 && !triggersEnabled {
+		return fmt.Errorf("conditions are required unless triggers are enabled, otherwise a flat trader never calls the AI")
+	}
+	_, err := compileConditions(config.Conditions)
+	return err
+}
+
+// gateTimeframe returns the timeframe gate conditions are evaluated on
+func gateTimeframe(config *store.StrategyConfig) string {
+	if config.AIGate.Timeframe != "" {
+		return config.AIGate.Timeframe
+	}
+	return primaryTimeframe(config)
+}
+
+// EvaluateGate decides whether the cycle needs the AI
+// Returns nil when the gate is disabled; invalid conditions leave the gate open
+func EvaluateGate(ctx *Context, config *store.StrategyConfig) *GateResult {
+	gate := config.AIGate
+	if !gate.Enabled {
+		return nil
+	}
+	if len(ctx.Positions) > 0 {
+		return &GateResult{Open: true, Reason: fmt.Sprintf("%d positions open", len(ctx.Positions))}
+	}
+	if ctx.TriggerReason != "" {
+		return &GateResult{Open: true, Reason: "triggered: " + ctx.TriggerReason}
+	}
+
+	conditions, err := compileConditions(gate.Conditions)
+	if err != nil {
+		return &GateResult{Open: true, Reason: fmt.Sprintf("invalid gate conditions: %v", err)}
+	}
+	if len(conditions) == 0 {
+		return &GateResult{Reason: "no positions open"}
+	}
+
+	getKlines := ctx.Klines
+	if getKlines == nil {
+		getKlines = seriesKlines(ctx)
+	}
+	timeframe := gateTimeframe(config)
+	for _, coin := range ctx.CandidateCoins {
+		if _, ok := ctx.MarketDataMap[coin.Symbol]; !ok {
+			continue
+		}
+		klines, err := getKlines(coin.Symbol, timeframe)
+		if err != nil || len(klines) == 0 {
+			continue
+		}
+		if matched, descs := matchConditions(conditions, klines); matched {
+			return &GateResult{Open: true, Reason: fmt.Sprintf("%s meets %s", coin.Symbol, strings.Join(descs, ", "))}
+		}
+	}
+
+	descs := make([]string, len(gate.Conditions))
+	for i, cond := range gate.Conditions {
+		descs[i] = fmt.Sprintf("%s %s %s", cond.Left, cond.Op, cond.Right)
+	}
+	return &GateResult{Reason: fmt.Sprintf("no positions open and no candidate meets %s on %s", strings.Join(descs, " and "), timeframe)}
+}
+
+// gatedDecision the synthetic wait recorded when the gate is closed
+func gatedDecision(gate *GateResult) *FullDecision {
+	return &FullDecision{
+		CoTTrace: "AI gate closed: " + gate.Reason,
+		Decisions: []Decision{{
+			Symbol:    "ALL",
+			Action:    "wait",
+			Reasoning: "AI call skipped by the pre-AI gate: " + gate.Reason,
+		}},
+		Timestamp: time.Now(),
+		Gated:     true,
+	}
+}
@@ -0,0 +1,107 @@
+package decision
+
+import (
+	"strings"
+	"testing"
+
+	"nofx/market"
+	"nofx/store"
+)
+
+func gatedStrategy(conditions ...store.RuleCondition) *store.StrategyConfig {
+	config := store.GetDefaultStrategyConfig("en")
+	config.AIGate = store.AIGateConfig{Enabled: true, Timeframe: "15m", Conditions: conditions}
+	return &config
+}
+
+// TestEvaluateGate tests that positions, triggers and candidate conditions open the gate
+func TestEvaluateGate(t *testing.T) {
+	klines := crossKlines(t)
+	quiet := klines[:len(klines)-1]
+	newCtx := func(k []market.Kline) *Context {
+		return &Context{
+			CandidateCoins: []CandidateCoin{{Symbol: "SOLUSDT"}},
+			MarketDataMap:  map[string]*market.Data{"SOLUSDT": {}},
+			Klines:         func(string, string) ([]market.Kline, error) { return k, nil },
+		}
+	}
+	cross := store.RuleCondition{Left: "ema(close,9)", Op: "crosses_above", Right: "ema(close,21)"}
+
+	config := store.GetDefaultStrategyConfig("en")
+	if EvaluateGate(newCtx(quiet), &config) != nil {
+		t.Error("disabled gate should return nil")
+	}
+
+	if gate := EvaluateGate(newCtx(quiet), gatedStrategy(cross)); gate.Open {
+		t.Errorf("gate open without a signal: %s", gate.Reason)
+	} else if !strings.Contains(gate.Reason, "ema(close,9) crosses_above ema(close,21) on 15m") {
+		t.Errorf("unexpected reason: %s", gate.Reason)
+	}
+	if gate := EvaluateGate(newCtx(klines), gatedStrategy(cross)); !gate.Open || !strings.HasPrefix(gate.Reason, "SOLUSDT meets") {
+		t.Errorf("gate = %+v, want open on the SOL cross", gate)
+	}
+
+	withPosition := newCtx(quiet)
+	withPosition.Positions = []PositionInfo{{Symbol: "ETHUSDT", Side: "long"}}
+	if gate := EvaluateGate(withPosition, gatedStrategy(cross)); !gate.Open {
+		t.Error("an open position should open the gate")
+	}
+	triggered := newCtx(quiet)
+	triggered.TriggerReason = "BTCUSDT moved 2.1% in 5m"
+	if gate := EvaluateGate(triggered, gatedStrategy()); !gate.Open {
+		t.Error("a triggered cycle should open the gate")
+	}
+	if gate := EvaluateGate(newCtx(quiet), gatedStrategy()); gate.Open {
+		t.Error("without conditions only positions and triggers open the gate")
+	}
+}
+
+// TestValidateGateConfig tests that an enabled gate needs conditions unless triggers can open it
+func TestValidateGateConfig(t *testing.T) {
+	cross := store.RuleCondition{Left: "ema(close,9)", Op: "crosses_above", Right: "ema(close,21)"}
+	tests := []struct {
+		name     string
+		gate     store.AIGateConfig
+		triggers bool
+		wantErr  string
+	}{
+		{"disabled", store.AIGateConfig{}, false, ""},
+		{"conditions", store.AIGateConfig{Enabled: true, Conditions: []store.RuleCondition{cross}}, false, ""},
+		{"triggers only", store.AIGateConfig{Enabled: true}, true, ""},
+		{"never opens when flat", store.AIGateConfig{Enabled: true}, false, "conditions are required"},
+		{"invalid condition", store.AIGateConfig{Enabled: true, Conditions: []store.RuleCondition{{Left: "rsi(close)", Op: ">", Right: "50"}}}, true, "rsi"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateGateConfig(tt.gate, tt.triggers)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("unexpected error: %v", err)
+				}
+			} else if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+// TestGetFullDecisionWithStrategy_Gated tests that a closed gate records a wait without calling the AI
+func TestGetFullDecisionWithStrategy_Gated(t *testing.T) {
+	klines := crossKlines(t)
+	ctx := &Context{
+		Account:        AccountInfo{TotalEquity: 1000},
+		CandidateCoins: []CandidateCoin{{Symbol: "SOLUSDT"}},
+		MarketDataMap:  map[string]*market.Data{"SOLUSDT": {}},
+		Klines:         func(string, string) ([]market.Kline, error) { return klines[:len(klines)-1], nil },
+	}
+	config := gatedStrategy(store.RuleCondition{Left: "ema(close,9)", Op: "crosses_above", Right: "ema(close,21)"})
+
+	// A nil AI client would panic if the gate let the cycle through
+	result, err := GetFullDecisionWithStrategy(ctx, nil, NewStrategyEngine(config), "balanced")
+	if err != nil {
+		t.Fatalf("GetFullDecisionWithStrategy: %v", err)
+	}
+	if !result.Gated || len(result.Decisions) != 1 || result.Decisions[0].Action != "wait" || result.Decisions[0].Symbol != "ALL" {
+		t.Errorf("result = %+v, want a gated wait", result)
+	}
+}
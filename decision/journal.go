@@ -0,0 +1,192 @@
+package decision
+
+import (
+	"fmt"
+	"nofx/store"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	defaultJournalEntries     = 5
+	defaultJournalTokenBudget = 600
+	journalCharsPerToken      = 4 // Rough estimate, good enough for a prompt budget
+
+	journalEntryReasoningChars = 240
+	journalExitReasoningChars  = 160
+)
+
+// JournalEntry a past trade's journal summary (for AI input)
+type JournalEntry struct {
+	Symbol   string    `json:"symbol"`
+	Side     string    `json:"side"`             // long/short
+	Regime   string    `json:"regime,omitempty"` // Regime label at entry
+	ExitTime time.Time `json:"exit_time"`
+	Summary  string    `json:"summary"`
+}
+
+// ReflectTrade builds the journal entry of a closed position
+// entryReasoning and exitReasoning come from the decisions that opened and closed it, regime is the regime at entry
+func ReflectTrade(pos *store.TraderPosition, entryReasoning, exitReasoning, regime string) *store.JournalEntry {
+	leverage := max(pos.Leverage, 1)
+	entry := &store.JournalEntry{
+		TraderID:       pos.TraderID,
+		PositionID:     pos.ID,
+		Symbol:         pos.Symbol,
+		Side:           strings.ToLower(pos.Side),
+		Regime:         regime,
+		Leverage:       leverage,
+		EntryPrice:     pos.EntryPrice,
+		ExitPrice:      pos.ExitPrice,
+		RealizedPnL:    pos.RealizedPnL,
+		CloseReason:    pos.CloseReason,
+		EntryReasoning: entryReasoning,
+		ExitReasoning:  exitReasoning,
+		EntryTime:      pos.EntryTime,
+	}
+	if pos.ExitTime != nil {
+		entry.ExitTime = *pos.ExitTime
+		entry.HoldMinutes = int(entry.ExitTime.Sub(pos.EntryTime).Minutes())
+	}
+	// Return on margin from realized PnL, so fees count and the percentage agrees with the win/loss outcome
+	if margin := pos.EntryPrice * pos.Quantity / float64(leverage); margin > 0 {
+		entry.PnLPct = pos.RealizedPnL / margin * 100
+	}
+	entry.Summary = summarizeTrade(entry)
+	return entry
+}
+
+// summarizeTrade writes the compact journal text: setup, outcome, and why the trade was opened and closed
+func summarizeTrade(e *store.JournalEntry) string {
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("%s %s %dx", e.Symbol, e.Side, e.Leverage))
+	if e.Regime != "" {
+		sb.WriteString(" in " + e.Regime)
+	}
+
+	outcome := "breakeven"
+	if e.RealizedPnL > 0 {
+		outcome = "win"
+	} else if e.RealizedPnL < 0 {
+		outcome = "loss"
+	}
+	sb.WriteString(fmt.Sprintf(": %s %+.2f%% (%+.2f USDT) after %s", outcome, e.PnLPct, e.RealizedPnL, formatHoldMinutes(e.HoldMinutes)))
+	if e.CloseReason != "" {
+		sb.WriteString(", closed by " + e.CloseReason)
+	}
+	sb.WriteString(".")
+
+	if reasoning := clipText(e.EntryReasoning, journalEntryReasoningChars); reasoning != "" {
+		sb.WriteString(" Entry: " + reasoning)
+	}
+	if reasoning := clipText(e.ExitReasoning, journalExitReasoningChars); reasoning != "" {
+		sb.WriteString(" Exit: " + reasoning)
+	}
+	return sb.String()
+}
+
+// formatHoldMinutes formats a holding time, e.g. "45m", "3h", "3h20m", "2d5h"
+func formatHoldMinutes(minutes int) string {
+	switch {
+	case minutes < 60:
+		return fmt.Sprintf("%dm", minutes)
+	case minutes%60 == 0 && minutes < 24*60:
+		return fmt.Sprintf("%dh", minutes/60)
+	case minutes < 24*60:
+		return fmt.Sprintf("%dh%dm", minutes/60, minutes%60)
+	default:
+		return fmt.Sprintf("%dd%dh", minutes/(24*60), minutes%(24*60)/60)
+	}
+}
+
+// clipText collapses whitespace and cuts text to max characters
+func clipText(text string, maxChars int) string {
+	text = strings.Join(strings.Fields(text), " ")
+	runes := []rune(text)
+	if len(runes) <= maxChars {
+		return text
+	}
+	return strings.TrimSpace(string(runes[:maxChars-1])) + "…"
+}
+
+// SelectJournalEntries picks the most relevant journal entries for the cycle within the token budget
+// Entries on a held or candidate symbol rank first, then entries opened in the symbol's or market's
+// current regime; ties go to the most recent
+func SelectJournalEntries(ctx *Context, config store.JournalConfig) []JournalEntry {
+	if !config.Enabled || len(ctx.Journal) == 0 {
+		return nil
+	}
+	maxEntries := config.MaxEntries
+	if maxEntries <= 0 {
+		maxEntries = defaultJournalEntries
+	}
+	budget := config.TokenBudget
+	if budget <= 0 {
+		budget = defaultJournalTokenBudget
+	}
+
+	symbols := make(map[string]bool, len(ctx.Positions)+len(ctx.CandidateCoins))
+	for _, pos := range ctx.Positions {
+		symbols[pos.Symbol] = true
+	}
+	for _, coin := range ctx.CandidateCoins {
+		symbols[coin.Symbol] = true
+	}
+	marketRegime := ctx.Regimes.MarketLabel()
+	symbolRegimes := ctx.Regimes.Labels()
+
+	score := func(entry JournalEntry) int {
+		s := 0
+		if symbols[entry.Symbol] {
+			s += 2
+		}
+		if entry.Regime != "" && (entry.Regime == marketRegime || entry.Regime == symbolRegimes[entry.Symbol]) {
+			s++
+		}
+		return s
+	}
+	ranked := make([]JournalEntry, len(ctx.Journal))
+	copy(ranked, ctx.Journal)
+	sort.SliceStable(ranked, func(i, j int) bool {
+		si, sj := score(ranked[i]), score(ranked[j])
+		if si != sj {
+			return si > sj
+		}
+		return ranked[i].ExitTime.After(ranked[j].ExitTime)
+	})
+
+	remaining := budget * journalCharsPerToken
+	var selected []JournalEntry
+	for _, entry := range ranked {
+		size := len(formatJournalEntry(entry))
+		if size > remaining {
+			continue
+		}
+		remaining -= size
+		selected = append(selected, entry)
+		if len(selected) == maxEntries {
+			break
+		}
+	}
+	return selected
+}
+
+// formatJournalEntry renders one journal entry as a prompt line
+func formatJournalEntry(entry JournalEntry) string {
+	return fmt.Sprintf("- [%s] %s\n", entry.ExitTime.UTC().Format("01-02 15:04"), entry.Summary)
+}
+
+// formatJournal renders the selected journal entries for the user prompt
+func formatJournal(entries []JournalEntry) string {
+	if len(entries) == 0 {
+		return ""
+	}
+	var sb strings.Builder
+	sb.WriteString("## Trade Journal (why past trades won or lost)\n")
+	for _, entry := range entries {
+		sb.WriteString(formatJournalEntry(entry))
+	}
+	sb.WriteString("\n")
+	return sb.String()
+}
@@ -0,0 +1,98 @@
+package decision
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"nofx/store"
+)
+
+// TestReflectTrade tests the journal summary of a closed position
+func TestReflectTrade(t *testing.T) {
+	entry := time.Date(2026, 3, 2, 8, 0, 0, 0, time.UTC)
+	exit := entry.Add(200 * time.Minute)
+	pos := &store.TraderPosition{
+		ID:          7,
+		TraderID:    "t1",
+		Symbol:      "ETHUSDT",
+		Side:        "SHORT",
+		Quantity:    1,
+		EntryPrice:  2000,
+		ExitPrice:   1960,
+		EntryTime:   entry,
+		ExitTime:    &exit,
+		RealizedPnL: 38, // 40 USDT move minus 2 USDT fees
+		Leverage:    3,
+		CloseReason: "ai_decision",
+	}
+
+	e := ReflectTrade(pos, "Lower high\nunder the 4h EMA,\tfunding positive", "Target zone reached", store.RegimeTrendingDown)
+	if e.Side != "short" || e.HoldMinutes != 200 || e.PositionID != 7 {
+		t.Errorf("entry = %+v", e)
+	}
+	want := "ETHUSDT short 3x in trending_down: win +5.70% (+38.00 USDT) after 3h20m, closed by ai_decision. " +
+		"Entry: Lower high under the 4h EMA, funding positive Exit: Target zone reached"
+	if e.Summary != want {
+		t.Errorf("summary = %q\nwant      %q", e.Summary, want)
+	}
+
+	// A small favorable move eaten by fees is a loss with a negative return
+	pos.ExitPrice, pos.RealizedPnL = 1999, -1
+	if e := ReflectTrade(pos, "", "", ""); e.PnLPct >= 0 || !strings.Contains(e.Summary, "loss -0.15%") {
+		t.Errorf("fee-eaten trade: pnl %.4f%%, summary %q", e.PnLPct, e.Summary)
+	}
+
+	long := strings.Repeat("word ", 100)
+	if got := clipText(long, journalEntryReasoningChars); len([]rune(got)) > journalEntryReasoningChars || !strings.HasSuffix(got, "…") {
+		t.Errorf("clipped text = %q", got)
+	}
+}
+
+// TestSelectJournalEntries tests ranking by symbol, regime and recency under the budget
+func TestSelectJournalEntries(t *testing.T) {
+	now := time.Now()
+	journal := []JournalEntry{
+		{Symbol: "XRPUSDT", Regime: store.RegimeRanging, ExitTime: now.Add(-1 * time.Hour), Summary: "xrp recent"},
+		{Symbol: "DOGEUSDT", Regime: store.RegimeHighVolatility, ExitTime: now.Add(-2 * time.Hour), Summary: "doge same market regime"},
+		{Symbol: "SOLUSDT", Regime: store.RegimeRanging, ExitTime: now.Add(-3 * time.Hour), Summary: "sol other regime"},
+		{Symbol: "SOLUSDT", Regime: store.RegimeTrendingUp, ExitTime: now.Add(-4 * time.Hour), Summary: "sol same regime"},
+		{Symbol: "ETHUSDT", ExitTime: now.Add(-5 * time.Hour), Summary: "eth held"},
+	}
+	ctx := &Context{
+		Positions:      []PositionInfo{{Symbol: "ETHUSDT", Side: "long"}},
+		CandidateCoins: []CandidateCoin{{Symbol: "SOLUSDT"}},
+		Regimes: &RegimeState{
+			Market:  &Regime{Label: store.RegimeHighVolatility},
+			Symbols: map[string]*Regime{"SOLUSDT": {Label: store.RegimeTrendingUp}},
+		},
+		Journal: journal,
+	}
+	summaries := func(entries []JournalEntry) string {
+		var s []string
+		for _, e := range entries {
+			s = append(s, e.Summary)
+		}
+		return strings.Join(s, ", ")
+	}
+
+	if got := SelectJournalEntries(ctx, store.JournalConfig{}); got != nil {
+		t.Errorf("disabled journal selected %v", got)
+	}
+
+	got := SelectJournalEntries(ctx, store.JournalConfig{Enabled: true, MaxEntries: 4})
+	if want := "sol same regime, sol other regime, eth held, doge same market regime"; summaries(got) != want {
+		t.Errorf("selected %q, want %q", summaries(got), want)
+	}
+
+	// Budget for two lines: an entry that no longer fits is skipped for a smaller one
+	budget := (len(formatJournalEntry(journal[3])) + len(formatJournalEntry(journal[4])) + journalCharsPerToken - 1) / journalCharsPerToken
+	got = SelectJournalEntries(ctx, store.JournalConfig{Enabled: true, TokenBudget: budget})
+	if want := "sol same regime, eth held"; summaries(got) != want {
+		t.Errorf("selected %q under %d tokens, want %q", summaries(got), budget, want)
+	}
+
+	if text := formatJournal(got); !strings.HasPrefix(text, "## Trade Journal") || !strings.Contains(text, "sol same regime") {
+		t.Errorf("unexpected journal text: %s", text)
+	}
+}
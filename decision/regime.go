@@ -0,0 +1,369 @@
+package decision
+
+import (
+	"fmt"
+	"math"
+	"nofx/logger"
+	"nofx/market"
+	"nofx/store"
+	"sort"
+	"strings"
+)
+
+// RegimeMarketSymbol symbol the overall market regime is read from
+const RegimeMarketSymbol = "BTCUSDT"
+
+const (
+	regimeMinBars          = 30 // Fewer klines leave ADX/ATR unreliable, no regime
+	regimePeriod           = 14 // ADX and ATR period
+	regimeEMAPeriod        = 20
+	regimeSlopeBars        = 5  // EMA slope is measured over the last 5 bars
+	regimeVolumeBars       = 20 // Quote volume is averaged over the last 20 bars
+	defaultTrendADX        = 25.0
+	defaultHighVolATRRatio = 1.5
+)
+
+// RegimeFeatures features a regime is classified from
+type RegimeFeatures struct {
+	ADX            float64 `json:"adx"`
+	ATRPct         float64 `json:"atr_pct"`          // ATR as percent of the close
+	ATRRatio       float64 `json:"atr_ratio"`        // ATR relative to its average over the kline window
+	EMASlopePct    float64 `json:"ema_slope_pct"`    // EMA20 change over the last 5 bars, percent
+	AvgQuoteVolume float64 `json:"avg_quote_volume"` // Average quote volume per bar (USDT)
+}
+
+// Regime regime of one symbol
+type Regime struct {
+	Label    string         `json:"label"`
+	Features RegimeFeatures `json:"features"`
+	Reason   string         `json:"reason"`
+}
+
+// RegimeState regimes of the market and of each symbol for one cycle
+type RegimeState struct {
+	Timeframe string             `json:"timeframe"`
+	Market    *Regime            `json:"market,omitempty"` // BTC regime (nil = unknown)
+	Symbols   map[string]*Regime `json:"symbols,omitempty"`
+
+	overrides []store.RegimeOverride
+}
+
+// ClassifyRegime classifies a symbol from its klines (oldest → latest)
+// Returns nil when there are too few klines
+func ClassifyRegime(klines []market.Kline, config store.RegimeConfig) *Regime {
+	if len(klines) < regimeMinBars {
+		return nil
+	}
+	features, ok := regimeFeatures(klines)
+	if !ok {
+		return nil
+	}
+
+	trendADX := config.TrendADX
+	if trendADX <= 0 {
+		trendADX = defaultTrendADX
+	}
+	highVolRatio := config.HighVolATRRatio
+	if highVolRatio <= 0 {
+		highVolRatio = defaultHighVolATRRatio
+	}
+
+	regime := &Regime{Features: features}
+	switch {
+	case config.MinQuoteVolume > 0 && features.AvgQuoteVolume < config.MinQuoteVolume:
+		regime.Label = store.RegimeLowLiquidity
+		regime.Reason = fmt.Sprintf("avg volume %s USDT/bar < %s", formatRegimeVolume(features.AvgQuoteVolume), formatRegimeVolume(config.MinQuoteVolume))
+	case features.ATRRatio >= highVolRatio:
+		regime.Label = store.RegimeHighVolatility
+		regime.Reason = fmt.Sprintf("ATR %.1fx its average", features.ATRRatio)
+	case config.HighVolATRPct > 0 && features.ATRPct >= config.HighVolATRPct:
+		regime.Label = store.RegimeHighVolatility
+		regime.Reason = fmt.Sprintf("ATR %.2f%% of price", features.ATRPct)
+	case features.ADX >= trendADX && features.EMASlopePct > 0:
+		regime.Label = store.RegimeTrendingUp
+		regime.Reason = fmt.Sprintf("ADX %.1f, EMA20 rising", features.ADX)
+	case features.ADX >= trendADX && features.EMASlopePct < 0:
+		regime.Label = store.RegimeTrendingDown
+		regime.Reason = fmt.Sprintf("ADX %.1f, EMA20 falling", features.ADX)
+	default:
+		regime.Label = store.RegimeRanging
+		regime.Reason = fmt.Sprintf("ADX %.1f", features.ADX)
+	}
+	return regime
+}
+
+// regimeFeatures computes ADX, ATR and EMA slope features from the klines
+func regimeFeatures(klines []market.Kline) (RegimeFeatures, bool) {
+	var features RegimeFeatures
+	params := map[string]float64{"period": regimePeriod}
+
+	adx, err := market.NewIndicator(market.IndicatorSpec{Name: "adx", Params: params})
+	if err != nil {
+		return features, false
+	}
+	atr, err := market.NewIndicator(market.IndicatorSpec{Name: "atr", Params: params})
+	if err != nil {
+		return features, false
+	}
+	ema, err := market.NewIndicator(market.IndicatorSpec{Name: "ema", Params: map[string]float64{"period": regimeEMAPeriod}})
+	if err != nil {
+		return features, false
+	}
+
+	adxValues := adx.Compute(klines)
+	atrValues := atr.Compute(klines)
+	emaValues := ema.Compute(klines)
+	n := len(klines)
+	last := klines[n-1].Close
+	if last <= 0 || math.IsNaN(adxValues[n-1]) || math.IsNaN(atrValues[n-1]) {
+		return features, false
+	}
+
+	features.ADX = adxValues[n-1]
+	features.ATRPct = atrValues[n-1] / last * 100
+
+	var atrSum float64
+	var atrCount int
+	for _, v := range atrValues {
+		if !math.IsNaN(v) {
+			atrSum += v
+			atrCount++
+		}
+	}
+	if atrSum > 0 {
+		features.ATRRatio = atrValues[n-1] / (atrSum / float64(atrCount))
+	}
+
+	if prev := emaValues[n-1-regimeSlopeBars]; !math.IsNaN(prev) && prev > 0 && !math.IsNaN(emaValues[n-1]) {
+		features.EMASlopePct = (emaValues[n-1] - prev) / prev * 100
+	}
+
+	bars := klines[n-min(n, regimeVolumeBars):]
+	var volume float64
+	for _, k := range bars {
+		if k.QuoteVolume > 0 {
+			volume += k.QuoteVolume
+		} else {
+			volume += k.Volume * k.Close
+		}
+	}
+	features.AvgQuoteVolume = volume / float64(len(bars))
+	return features, true
+}
+
+// RegimeTimeframe returns the timeframe regimes are classified on
+func RegimeTimeframe(config *store.StrategyConfig) string {
+	if config.Regime.Timeframe != "" {
+		return config.Regime.Timeframe
+	}
+	return primaryTimeframe(config)
+}
+
+// ClassifyRegimes classifies each symbol and the market (BTC) using klines from getKlines
+// Symbols whose klines can't be loaded are left out
+func ClassifyRegimes(config store.RegimeConfig, timeframe string, symbols []string, getKlines func(symbol string) ([]market.Kline, error)) *RegimeState {
+	state := &RegimeState{
+		Timeframe: timeframe,
+		Symbols:   make(map[string]*Regime, len(symbols)),
+		overrides: config.Overrides,
+	}
+	for _, symbol := range symbols {
+		if _, done := state.Symbols[symbol]; done {
+			continue
+		}
+		klines, err := getKlines(symbol)
+		if err != nil {
+			continue
+		}
+		if regime := ClassifyRegime(klines, config); regime != nil {
+			state.Symbols[symbol] = regime
+		}
+	}
+
+	if regime, ok := state.Symbols[RegimeMarketSymbol]; ok {
+		state.Market = regime
+	} else if klines, err := getKlines(RegimeMarketSymbol); err == nil {
+		state.Market = ClassifyRegime(klines, config)
+	}
+	return state
+}
+
+// MarketLabel returns the market regime label ("" = unknown)
+func (s *RegimeState) MarketLabel() string {
+	if s == nil || s.Market == nil {
+		return ""
+	}
+	return s.Market.Label
+}
+
+// Labels returns the regime label of each classified symbol
+func (s *RegimeState) Labels() map[string]string {
+	if s == nil || len(s.Symbols) == 0 {
+		return nil
+	}
+	labels := make(map[string]string, len(s.Symbols))
+	for symbol, regime := range s.Symbols {
+		labels[symbol] = regime.Label
+	}
+	return labels
+}
+
+// activeOverrides returns the overrides that apply to the symbol
+// An empty symbol only matches market-scoped overrides
+func (s *RegimeState) activeOverrides(symbol string) []store.RegimeOverride {
+	if s == nil {
+		return nil
+	}
+	var active []store.RegimeOverride
+	for _, o := range s.overrides {
+		if o.Scope == store.RegimeScopeMarket {
+			if s.Market != nil && s.Market.Label == o.Regime {
+				active = append(active, o)
+			}
+			continue
+		}
+		if regime, ok := s.Symbols[symbol]; ok && symbol != "" && regime.Label == o.Regime {
+			active = append(active, o)
+		}
+	}
+	return active
+}
+
+// EntryBlocked returns why new positions in the symbol aren't allowed by a regime override ("" = allowed)
+func (s *RegimeState) EntryBlocked(symbol string) string {
+	for _, o := range s.activeOverrides(symbol) {
+		if !o.BlockNewEntries {
+			continue
+		}
+		if o.Scope == store.RegimeScopeMarket {
+			return fmt.Sprintf("market regime is %s", o.Regime)
+		}
+		return fmt.Sprintf("%s regime is %s", symbol, o.Regime)
+	}
+	return ""
+}
+
+// MaxLeverage returns the lowest leverage cap of the symbol's active overrides (0 = no cap)
+func (s *RegimeState) MaxLeverage(symbol string) int {
+	maxLeverage := 0
+	for _, o := range s.activeOverrides(symbol) {
+		if o.MaxLeverage > 0 && (maxLeverage == 0 || o.MaxLeverage < maxLeverage) {
+			maxLeverage = o.MaxLeverage
+		}
+	}
+	return maxLeverage
+}
+
+// FormatRegime formats a regime with its features for prompts
+func FormatRegime(regime *Regime) string {
+	if regime == nil {
+		return ""
+	}
+	f := regime.Features
+	return fmt.Sprintf("%s (%s) | ADX %.1f | ATR %.2f%% (%.2fx avg) | EMA20 slope %+.2f%%",
+		regime.Label, regime.Reason, f.ADX, f.ATRPct, f.ATRRatio, f.EMASlopePct)
+}
+
+// formatMarketRegime formats the market regime and market-scoped overrides for the user prompt
+func (s *RegimeState) formatMarketRegime() string {
+	if s == nil || s.Market == nil {
+		return ""
+	}
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("📈 Market regime (BTC, %s): %s\n", s.Timeframe, FormatRegime(s.Market)))
+	for _, o := range s.activeOverrides("") {
+		s.writeOverride(&sb, o, "the market regime")
+	}
+	sb.WriteString("\n")
+	return sb.String()
+}
+
+// formatSymbolRegime formats a symbol's regime and symbol-scoped overrides for the user prompt
+func (s *RegimeState) formatSymbolRegime(symbol string) string {
+	if s == nil {
+		return ""
+	}
+	regime, ok := s.Symbols[symbol]
+	if !ok {
+		return ""
+	}
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("Regime (%s): %s\n", s.Timeframe, FormatRegime(regime)))
+	for _, o := range s.activeOverrides(symbol) {
+		if o.Scope != store.RegimeScopeMarket {
+			s.writeOverride(&sb, o, "this regime")
+		}
+	}
+	return sb.String()
+}
+
+func (s *RegimeState) writeOverride(sb *strings.Builder, o store.RegimeOverride, subject string) {
+	if o.BlockNewEntries {
+		sb.WriteString(fmt.Sprintf("🚫 No new entries in %s (open_long/open_short will be rejected).\n", subject))
+	}
+	if o.MaxLeverage > 0 {
+		sb.WriteString(fmt.Sprintf("Max leverage in %s: %dx\n", subject, o.MaxLeverage))
+	}
+	if section := strings.TrimSpace(o.PromptSection); section != "" {
+		sb.WriteString(section + "\n")
+	}
+}
+
+// ValidateRegimeConfig checks thresholds and overrides of the regime config
+func ValidateRegimeConfig(config store.RegimeConfig) error {
+	if config.TrendADX < 0 || config.TrendADX > 100 {
+		return fmt.Errorf("trend_adx must be between 0 and 100")
+	}
+	if config.HighVolATRRatio < 0 || config.HighVolATRPct < 0 || config.MinQuoteVolume < 0 {
+		return fmt.Errorf("thresholds must not be negative")
+	}
+	for _, o := range config.Overrides {
+		switch o.Regime {
+		case store.RegimeTrendingUp, store.RegimeTrendingDown, store.RegimeRanging, store.RegimeHighVolatility, store.RegimeLowLiquidity:
+		default:
+			return fmt.Errorf("invalid regime %q", o.Regime)
+		}
+		switch o.Scope {
+		case "", store.RegimeScopeSymbol, store.RegimeScopeMarket:
+		default:
+			return fmt.Errorf("invalid override scope %q", o.Scope)
+		}
+		if o.MaxLeverage < 0 {
+			return fmt.Errorf("override max_leverage must not be negative")
+		}
+	}
+	return nil
+}
+
+// formatRegimeVolume formats a USDT volume compactly
+func formatRegimeVolume(v float64) string {
+	switch {
+	case v >= 1e6:
+		return fmt.Sprintf("%.2fM", v/1e6)
+	case v >= 1e3:
+		return fmt.Sprintf("%.1fK", v/1e3)
+	default:
+		return fmt.Sprintf("%.0f", v)
+	}
+}
+
+// AttachRegimes classifies the symbols of the context's market data from the monitor's cached klines (each timeframe is fetched and subscribed once)
+func AttachRegimes(ctx *Context, config *store.StrategyConfig) {
+	symbols := make([]string, 0, len(ctx.MarketDataMap))
+	for symbol := range ctx.MarketDataMap {
+		symbols = append(symbols, symbol)
+	}
+	sort.Strings(symbols)
+
+	timeframe := RegimeTimeframe(config)
+	state := ClassifyRegimes(config.Regime, timeframe, symbols, func(symbol string) ([]market.Kline, error) {
+		if market.WSMonitorCli == nil {
+			return nil, fmt.Errorf("market monitor not initialized")
+		}
+		return market.WSMonitorCli.GetCurrentKlines(symbol, timeframe)
+	})
+	if label := state.MarketLabel(); label != "" {
+		logger.Infof("📈 Market regime (BTC, %s): %s", timeframe, label)
+	}
+	ctx.Regimes = state
+}
@@ -0,0 +1,173 @@
+package decision
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"testing"
+
+	"nofx/market"
+	"nofx/store"
+)
+
+// regimeKlines builds klines from closes with a fixed high-low range in percent of the close
+func regimeKlines(closes []float64, rangePct func(i int) float64) []market.Kline {
+	klines := make([]market.Kline, len(closes))
+	for i, c := range closes {
+		half := c * rangePct(i) / 200
+		open := c
+		if i > 0 {
+			open = closes[i-1]
+		}
+		klines[i] = market.Kline{
+			OpenTime:    int64(i) * 60_000,
+			Open:        open,
+			High:        math.Max(open, c) + half,
+			Low:         math.Min(open, c) - half,
+			Close:       c,
+			Volume:      10,
+			QuoteVolume: 10 * c,
+		}
+	}
+	return klines
+}
+
+func trendCloses(n int, stepPct float64) []float64 {
+	closes := make([]float64, n)
+	price := 100.0
+	for i := range closes {
+		price *= 1 + stepPct/100
+		closes[i] = price
+	}
+	return closes
+}
+
+func choppyCloses(n int) []float64 {
+	closes := make([]float64, n)
+	for i := range closes {
+		closes[i] = 100 + math.Sin(float64(i)*1.3)*0.5
+	}
+	return closes
+}
+
+func constantRange(pct float64) func(int) float64 {
+	return func(int) float64 { return pct }
+}
+
+// TestClassifyRegime tests trend, range, volatility and liquidity labels on synthetic klines
+func TestClassifyRegime(t *testing.T) {
+	config := store.RegimeConfig{Enabled: true}
+	spike := func(i int) float64 {
+		if i >= 95 {
+			return 6
+		}
+		return 0.6
+	}
+
+	tests := []struct {
+		name   string
+		klines []market.Kline
+		config store.RegimeConfig
+		want   string
+	}{
+		{"steady rise", regimeKlines(trendCloses(100, 0.4), constantRange(0.3)), config, store.RegimeTrendingUp},
+		{"steady fall", regimeKlines(trendCloses(100, -0.4), constantRange(0.3)), config, store.RegimeTrendingDown},
+		{"chop", regimeKlines(choppyCloses(100), constantRange(0.6)), config, store.RegimeRanging},
+		{"range expansion", regimeKlines(choppyCloses(100), spike), config, store.RegimeHighVolatility},
+		{"atr percent threshold", regimeKlines(choppyCloses(100), constantRange(0.6)), store.RegimeConfig{HighVolATRPct: 0.5}, store.RegimeHighVolatility},
+		{"thin volume", regimeKlines(trendCloses(100, 0.4), constantRange(0.3)), store.RegimeConfig{MinQuoteVolume: 1e6}, store.RegimeLowLiquidity},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			regime := ClassifyRegime(tt.klines, tt.config)
+			if regime == nil {
+				t.Fatal("regime is nil")
+			}
+			if regime.Label != tt.want {
+				t.Errorf("label = %s (%s, %+v), want %s", regime.Label, regime.Reason, regime.Features, tt.want)
+			}
+		})
+	}
+
+	if ClassifyRegime(regimeKlines(trendCloses(regimeMinBars-1, 0.4), constantRange(0.3)), config) != nil {
+		t.Error("regime should be nil with too few klines")
+	}
+}
+
+// TestRegimeState_Overrides tests market and symbol scoped overrides and their prompt rendering
+func TestRegimeState_Overrides(t *testing.T) {
+	klines := map[string][]market.Kline{
+		"BTCUSDT": regimeKlines(choppyCloses(100), func(i int) float64 {
+			if i >= 95 {
+				return 6
+			}
+			return 0.6
+		}),
+		"ETHUSDT": regimeKlines(trendCloses(100, 0.4), constantRange(0.3)),
+		"SOLUSDT": regimeKlines(choppyCloses(100), constantRange(0.6)),
+	}
+	config := store.RegimeConfig{
+		Enabled: true,
+		Overrides: []store.RegimeOverride{
+			{Regime: store.RegimeHighVolatility, Scope: store.RegimeScopeMarket, MaxLeverage: 5, PromptSection: "Market is whipsawing, favour smaller size."},
+			{Regime: store.RegimeRanging, BlockNewEntries: true},
+			{Regime: store.RegimeTrendingUp, MaxLeverage: 3},
+		},
+	}
+	getKlines := func(symbol string) ([]market.Kline, error) {
+		k, ok := klines[symbol]
+		if !ok {
+			return nil, fmt.Errorf("no klines for %s", symbol)
+		}
+		return k, nil
+	}
+
+	state := ClassifyRegimes(config, "15m", []string{"ETHUSDT", "SOLUSDT", "XRPUSDT"}, getKlines)
+	if state.MarketLabel() != store.RegimeHighVolatility {
+		t.Fatalf("market regime = %q, want high_volatility", state.MarketLabel())
+	}
+	labels := state.Labels()
+	if len(labels) != 2 || labels["ETHUSDT"] != store.RegimeTrendingUp || labels["SOLUSDT"] != store.RegimeRanging {
+		t.Errorf("labels = %v, want ETH trending_up and SOL ranging", labels)
+	}
+
+	if reason := state.EntryBlocked("SOLUSDT"); reason != "SOLUSDT regime is ranging" {
+		t.Errorf("SOL entry blocked = %q", reason)
+	}
+	if reason := state.EntryBlocked("ETHUSDT"); reason != "" {
+		t.Errorf("ETH entry blocked = %q, want allowed", reason)
+	}
+	if got := state.MaxLeverage("ETHUSDT"); got != 3 {
+		t.Errorf("ETH max leverage = %d, want 3 (lowest of market 5 and symbol 3)", got)
+	}
+	if got := state.MaxLeverage("SOLUSDT"); got != 5 {
+		t.Errorf("SOL max leverage = %d, want the market cap 5", got)
+	}
+
+	marketText := state.formatMarketRegime()
+	if !strings.Contains(marketText, "Market regime (BTC, 15m): high_volatility") || !strings.Contains(marketText, "Market is whipsawing") {
+		t.Errorf("unexpected market regime text: %s", marketText)
+	}
+	if text := state.formatSymbolRegime("SOLUSDT"); !strings.Contains(text, "No new entries in this regime") {
+		t.Errorf("unexpected symbol regime text: %s", text)
+	}
+
+	var disabled *RegimeState
+	if disabled.EntryBlocked("ETHUSDT") != "" || disabled.MaxLeverage("ETHUSDT") != 0 || disabled.formatMarketRegime() != "" {
+		t.Error("nil state should not restrict anything")
+	}
+}
+
+// TestValidateRegimeConfig tests override validation
+func TestValidateRegimeConfig(t *testing.T) {
+	valid := store.RegimeConfig{Overrides: []store.RegimeOverride{{Regime: store.RegimeHighVolatility, Scope: store.RegimeScopeMarket, BlockNewEntries: true}}}
+	if err := ValidateRegimeConfig(valid); err != nil {
+		t.Errorf("valid config rejected: %v", err)
+	}
+	if err := ValidateRegimeConfig(store.RegimeConfig{Overrides: []store.RegimeOverride{{Regime: "choppy"}}}); err == nil {
+		t.Error("unknown regime should be rejected")
+	}
+	if err := ValidateRegimeConfig(store.RegimeConfig{Overrides: []store.RegimeOverride{{Regime: store.RegimeRanging, Scope: "portfolio"}}}); err == nil {
+		t.Error("unknown scope should be rejected")
+	}
+}
@@ -0,0 +1,369 @@
+package decision
+
+import (
+	"fmt"
+	"math"
+	"nofx/market"
+	"nofx/store"
+	"strings"
+	"time"
+)
+
+const defaultRuleATRPeriod = 14
+
+// KlineSource loads a symbol's klines of a timeframe (oldest → latest)
+type KlineSource func(symbol, timeframe string) ([]market.Kline, error)
+
+// compiledRule a trading rule with compiled condition expressions
+type compiledRule struct {
+	store.TradingRule
+	conditions []compiledCondition
+}
+
+type compiledCondition struct {
+	store.RuleCondition
+	left, right *market.Expression
+}
+
+// compileRules validates rule actions and operators and compiles every condition expression
+func compileRules(config store.RuleConfig) ([]compiledRule, error) {
+	if len(config.Rules) == 0 {
+		return nil, fmt.Errorf("at least one rule is required")
+	}
+	rules := make([]compiledRule, 0, len(config.Rules))
+	for i, rule := range config.Rules {
+		name := ruleName(rule, i)
+		switch rule.Action {
+		case "open_long", "open_short":
+			if rule.StopLossATR <= 0 || rule.TakeProfitATR <= 0 {
+				return nil, fmt.Errorf("rule %s: stop_loss_atr and take_profit_atr must be greater than 0", name)
+			}
+			if rule.ATRPeriod < 0 || rule.ATRPeriod > 200 {
+				return nil, fmt.Errorf("rule %s: atr_period must be between 1 and 200 (0 = default %d)", name, defaultRuleATRPeriod)
+			}
+			if rule.Leverage < 0 || rule.PositionSizePct < 0 {
+				return nil, fmt.Errorf("rule %s: leverage and position_size_pct must not be negative", name)
+			}
+		case "close_long", "close_short":
+		default:
+			return nil, fmt.Errorf("rule %s: invalid action %q", name, rule.Action)
+		}
+		if len(rule.Conditions) == 0 {
+			return nil, fmt.Errorf("rule %s: at least one condition is required", name)
+		}
+
+		conditions, err := compileConditions(rule.Conditions)
+		if err != nil {
+			return nil, fmt.Errorf("rule %s: %w", name, err)
+		}
+		rules = append(rules, compiledRule{TradingRule: rule, conditions: conditions})
+	}
+	return rules, nil
+}
+
+// compileConditions checks operators and compiles both sides of every condition
+func compileConditions(conds []store.RuleCondition) ([]compiledCondition, error) {
+	compiled := make([]compiledCondition, 0, len(conds))
+	for _, cond := range conds {
+		switch cond.Op {
+		case ">", ">=", "<", "<=", "crosses_above", "crosses_below":
+		default:
+			return nil, fmt.Errorf("invalid operator %q", cond.Op)
+		}
+		left, err := market.CompileExpression(cond.Left)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", cond.Left, err)
+		}
+		right, err := market.CompileExpression(cond.Right)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", cond.Right, err)
+		}
+		compiled = append(compiled, compiledCondition{RuleCondition: cond, left: left, right: right})
+	}
+	return compiled, nil
+}
+
+// ValidateRuleConfig checks rule actions, operators, stop distances and expressions
+func ValidateRuleConfig(config store.RuleConfig) error {
+	_, err := compileRules(config)
+	return err
+}
+
+// ruleName returns the rule's name, or its position when unnamed
+func ruleName(rule store.TradingRule, index int) string {
+	if rule.Name != "" {
+		return rule.Name
+	}
+	return fmt.Sprintf("#%d", index+1)
+}
+
+// evaluate reports whether the condition holds on the last kline, with the compared values
+func (c compiledCondition) evaluate(klines []market.Kline) (bool, string) {
+	left := c.left.Evaluate(klines)
+	right := c.right.Evaluate(klines)
+	n := len(klines)
+	l, r := left[n-1], right[n-1]
+	desc := fmt.Sprintf("%s %s %s (%.6g vs %.6g)", c.Left, c.Op, c.Right, l, r)
+	if math.IsNaN(l) || math.IsNaN(r) {
+		return false, desc + " [not enough data]"
+	}
+
+	switch c.Op {
+	case ">":
+		return l > r, desc
+	case ">=":
+		return l >= r, desc
+	case "<":
+		return l < r, desc
+	case "<=":
+		return l <= r, desc
+	}
+
+	// Crosses compare the last two klines
+	if n < 2 || math.IsNaN(left[n-2]) || math.IsNaN(right[n-2]) {
+		return false, desc + " [not enough data]"
+	}
+	if c.Op == "crosses_above" {
+		return l > r && left[n-2] <= right[n-2], desc
+	}
+	return l < r && left[n-2] >= right[n-2], desc
+}
+
+// matchConditions reports whether all conditions hold, describing each condition
+func matchConditions(conditions []compiledCondition, klines []market.Kline) (bool, []string) {
+	matched := true
+	descs := make([]string, 0, len(conditions))
+	for _, cond := range conditions {
+		ok, desc := cond.evaluate(klines)
+		if !ok {
+			matched = false
+			desc += " ✗"
+		} else {
+			desc += " ✓"
+		}
+		descs = append(descs, desc)
+	}
+	return matched, descs
+}
+
+// RuleTimeframe returns the timeframe rules are evaluated on
+func RuleTimeframe(config *store.StrategyConfig) string {
+	if config.Rules.Timeframe != "" {
+		return config.Rules.Timeframe
+	}
+	return primaryTimeframe(config)
+}
+
+// primaryTimeframe returns the strategy's primary timeframe
+func primaryTimeframe(config *store.StrategyConfig) string {
+	if config.Indicators.Klines.PrimaryTimeframe != "" {
+		return config.Indicators.Klines.PrimaryTimeframe
+	}
+	if len(config.Indicators.Klines.SelectedTimeframes) > 0 {
+		return config.Indicators.Klines.SelectedTimeframes[0]
+	}
+	return "3m"
+}
+
+// seriesKlines reads closed klines from the cycle's per-timeframe series, the same bars backtests evaluate
+func seriesKlines(ctx *Context) KlineSource {
+	now := time.Now()
+	return func(symbol, timeframe string) ([]market.Kline, error) {
+		data, ok := ctx.MarketDataMap[symbol]
+		if !ok || data == nil {
+			return nil, fmt.Errorf("no market data for %s", symbol)
+		}
+		series, ok := data.TimeframeData[timeframe]
+		if !ok || series == nil {
+			return nil, fmt.Errorf("no %s series for %s", timeframe, symbol)
+		}
+		dur, err := market.TFDuration(timeframe)
+		if err != nil {
+			return nil, err
+		}
+		klines := make([]market.Kline, len(series.Klines))
+		for i, bar := range series.Klines {
+			klines[i] = market.Kline{
+				OpenTime:  bar.Time,
+				Open:      bar.Open,
+				High:      bar.High,
+				Low:       bar.Low,
+				Close:     bar.Close,
+				Volume:    bar.Volume,
+				CloseTime: bar.Time + dur.Milliseconds() - 1,
+			}
+		}
+		// Drop the forming candle (cycles not aligned to candle closes)
+		if len(klines) > 0 && !market.IsKlineClosed(klines[len(klines)-1], now) {
+			klines = klines[:len(klines)-1]
+		}
+		return klines, nil
+	}
+}
+
+// conditionTimeframes returns the timeframes rules and the AI gate are evaluated on
+func conditionTimeframes(config *store.StrategyConfig) []string {
+	var timeframes []string
+	if config.Mode == store.StrategyModeRules {
+		timeframes = append(timeframes, RuleTimeframe(config))
+	}
+	if config.AIGate.Enabled {
+		timeframes = append(timeframes, gateTimeframe(config))
+	}
+	return timeframes
+}
+
+// RunRules produces decisions from the strategy's declarative rules instead of an AI call
+// Positions are checked against close rules, other symbols against open rules; the evaluation is kept as the trace
+func (e *StrategyEngine) RunRules(ctx *Context) (*FullDecision, error) {
+	config := e.GetConfig()
+	rules, err := compileRules(config.Rules)
+	if err != nil {
+		return nil, fmt.Errorf("invalid rules: %w", err)
+	}
+	getKlines := ctx.Klines
+	if getKlines == nil {
+		getKlines = seriesKlines(ctx)
+	}
+	timeframe := RuleTimeframe(config)
+	risk := e.GetRiskControlConfig()
+
+	held := make(map[string]string, len(ctx.Positions))
+	symbols := make([]string, 0, len(ctx.Positions)+len(ctx.CandidateCoins))
+	for _, pos := range ctx.Positions {
+		if _, ok := held[pos.Symbol]; !ok {
+			symbols = append(symbols, pos.Symbol)
+		}
+		held[pos.Symbol] = pos.Side
+	}
+	for _, coin := range ctx.CandidateCoins {
+		if _, ok := held[coin.Symbol]; ok {
+			continue
+		}
+		if _, ok := ctx.MarketDataMap[coin.Symbol]; ok {
+			symbols = append(symbols, coin.Symbol)
+		}
+	}
+
+	var trace strings.Builder
+	trace.WriteString(fmt.Sprintf("Rule-based strategy: %d rules on %s closed klines\n", len(rules), timeframe))
+	openCount := len(ctx.Positions)
+	decisions := make([]Decision, 0)
+
+	for _, symbol := range symbols {
+		klines, err := getKlines(symbol, timeframe)
+		if err != nil || len(klines) == 0 {
+			trace.WriteString(fmt.Sprintf("\n%s: no klines (%v)\n", symbol, err))
+			continue
+		}
+		side, holding := held[symbol]
+		trace.WriteString(fmt.Sprintf("\n%s (close %.6g", symbol, klines[len(klines)-1].Close))
+		if holding {
+			trace.WriteString(", holding " + side)
+		}
+		trace.WriteString(")\n")
+
+		for i, rule := range rules {
+			switch rule.Action {
+			case "close_long", "close_short":
+				if rule.Action != "close_"+side {
+					continue
+				}
+			default:
+				if holding {
+					continue
+				}
+			}
+
+			matched, descs := matchConditions(rule.conditions, klines)
+			name := ruleName(rule.TradingRule, i)
+			trace.WriteString(fmt.Sprintf("  rule %s (%s): %s\n", name, rule.Action, strings.Join(descs, ", ")))
+			if !matched {
+				continue
+			}
+
+			d := Decision{
+				Symbol:    symbol,
+				Action:    rule.Action,
+				Reasoning: fmt.Sprintf("rule %s: %s", name, strings.Join(descs, ", ")),
+			}
+			if rule.Action == "open_long" || rule.Action == "open_short" {
+				if risk.MaxPositions > 0 && openCount >= risk.MaxPositions {
+					trace.WriteString(fmt.Sprintf("  → skipped: max positions (%d) reached\n", risk.MaxPositions))
+					break
+				}
+				if err := ruleEntry(&d, rule.TradingRule, klines, ctx.Account.TotalEquity, risk); err != nil {
+					trace.WriteString(fmt.Sprintf("  → skipped: %v\n", err))
+					break
+				}
+				openCount++
+			}
+			trace.WriteString(fmt.Sprintf("  → %s\n", rule.Action))
+			decisions = append(decisions, d)
+			break
+		}
+	}
+
+	return &FullDecision{
+		CoTTrace:  trace.String(),
+		Decisions: decisions,
+		Timestamp: time.Now(),
+	}, nil
+}
+
+// ruleEntry sets leverage, size, stop loss and take profit of an open decision
+// Stops are ATR multiples from the last close; size and leverage default to, and are capped by, the risk control limits.
+// The AI's minimum risk/reward doesn't apply, the rule declares its own stops.
+func ruleEntry(d *Decision, rule store.TradingRule, klines []market.Kline, equity float64, risk store.RiskControlConfig) error {
+	period := rule.ATRPeriod
+	if period <= 0 {
+		period = defaultRuleATRPeriod
+	}
+	atrIndicator, err := market.NewIndicator(market.IndicatorSpec{Name: "atr", Params: map[string]float64{"period": float64(period)}})
+	if err != nil {
+		return err
+	}
+	atrValues := atrIndicator.Compute(klines)
+	atr := atrValues[len(atrValues)-1]
+	price := klines[len(klines)-1].Close
+	if math.IsNaN(atr) || atr <= 0 {
+		return fmt.Errorf("ATR(%d) not available", period)
+	}
+	if equity <= 0 {
+		return fmt.Errorf("account equity unavailable")
+	}
+
+	if d.Action == "open_long" {
+		d.StopLoss = price - rule.StopLossATR*atr
+		d.TakeProfit = price + rule.TakeProfitATR*atr
+	} else {
+		d.StopLoss = price + rule.StopLossATR*atr
+		d.TakeProfit = price - rule.TakeProfitATR*atr
+	}
+	if d.StopLoss <= 0 || d.TakeProfit <= 0 {
+		return fmt.Errorf("stop loss %.6g / take profit %.6g out of range", d.StopLoss, d.TakeProfit)
+	}
+
+	maxLeverage := risk.AltcoinMaxLeverage
+	ratio := risk.AltcoinMaxPositionValueRatio
+	if d.Symbol == "BTCUSDT" || d.Symbol == "ETHUSDT" {
+		maxLeverage = risk.BTCETHMaxLeverage
+		ratio = risk.BTCETHMaxPositionValueRatio
+	}
+	d.Leverage = rule.Leverage
+	if d.Leverage <= 0 || d.Leverage > maxLeverage {
+		d.Leverage = maxLeverage
+	}
+
+	maxValue := equity * ratio
+	if rule.PositionSizePct > 0 {
+		d.PositionSizeUSD = math.Min(equity*rule.PositionSizePct/100, maxValue)
+	} else {
+		d.PositionSizeUSD = maxValue / float64(max(risk.MaxPositions, 1))
+	}
+	if d.PositionSizeUSD < risk.MinPositionSize {
+		return fmt.Errorf("position size %.2f USDT below minimum %.2f", d.PositionSizeUSD, risk.MinPositionSize)
+	}
+	d.RiskUSD = d.PositionSizeUSD * math.Abs(price-d.StopLoss) / price
+	return nil
+}
@@ -0,0 +1,193 @@
+package decision
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"testing"
+	"time"
+
+	"nofx/market"
+	"nofx/store"
+)
+
+// crossKlines a decline followed by a rally, cut at the first kline where EMA9 crosses above EMA21
+func crossKlines(t *testing.T) []market.Kline {
+	closes := make([]float64, 120)
+	for i := range closes {
+		if i < 80 {
+			closes[i] = 120 - float64(i)*0.25
+		} else {
+			closes[i] = closes[i-1] + 0.6
+		}
+	}
+	klines := regimeKlines(closes, constantRange(0.5))
+
+	cross := compiledCondition{RuleCondition: store.RuleCondition{Left: "ema(close,9)", Op: "crosses_above", Right: "ema(close,21)"}}
+	cross.left, _ = market.CompileExpression(cross.Left)
+	cross.right, _ = market.CompileExpression(cross.Right)
+	for n := 30; n <= len(klines); n++ {
+		if ok, _ := cross.evaluate(klines[:n]); ok {
+			return klines[:n]
+		}
+	}
+	t.Fatal("EMA9 never crosses above EMA21")
+	return nil
+}
+
+func ruleStrategy(rules ...store.TradingRule) *StrategyEngine {
+	config := store.GetDefaultStrategyConfig("en")
+	config.Mode = store.StrategyModeRules
+	config.Rules = store.RuleConfig{Timeframe: "15m", Rules: rules}
+	return NewStrategyEngine(&config)
+}
+
+var emaCrossLong = store.TradingRule{
+	Name:   "ema cross",
+	Action: "open_long",
+	Conditions: []store.RuleCondition{
+		{Left: "ema(close,9)", Op: "crosses_above", Right: "ema(close,21)"},
+		{Left: "rsi(close,14)", Op: "<", Right: "90"},
+	},
+	StopLossATR:   1.5,
+	TakeProfitATR: 3,
+	Leverage:      3,
+}
+
+// TestRunRules_OpenLong tests an EMA cross entry with ATR stops and default sizing
+func TestRunRules_OpenLong(t *testing.T) {
+	klines := crossKlines(t)
+	engine := ruleStrategy(emaCrossLong)
+	ctx := &Context{
+		Account:        AccountInfo{TotalEquity: 900},
+		CandidateCoins: []CandidateCoin{{Symbol: "SOLUSDT"}, {Symbol: "DOGEUSDT"}},
+		MarketDataMap:  map[string]*market.Data{"SOLUSDT": {}, "DOGEUSDT": {}},
+		Klines: func(symbol, timeframe string) ([]market.Kline, error) {
+			if timeframe != "15m" {
+				return nil, fmt.Errorf("unexpected timeframe %s", timeframe)
+			}
+			if symbol == "DOGEUSDT" {
+				return klines[:len(klines)-1], nil // One bar before the cross
+			}
+			return klines, nil
+		},
+	}
+
+	result, err := engine.RunRules(ctx)
+	if err != nil {
+		t.Fatalf("RunRules: %v", err)
+	}
+	if len(result.Decisions) != 1 {
+		t.Fatalf("decisions = %+v, want one open_long\n%s", result.Decisions, result.CoTTrace)
+	}
+	d := result.Decisions[0]
+	if d.Symbol != "SOLUSDT" || d.Action != "open_long" || d.Leverage != 3 {
+		t.Errorf("decision = %+v, want SOLUSDT open_long at 3x", d)
+	}
+
+	atr, _ := market.CompileExpression("atr(14)")
+	atrValues := atr.Evaluate(klines)
+	lastATR := atrValues[len(atrValues)-1]
+	price := klines[len(klines)-1].Close
+	if math.Abs(d.StopLoss-(price-1.5*lastATR)) > 1e-9 || math.Abs(d.TakeProfit-(price+3*lastATR)) > 1e-9 {
+		t.Errorf("stops = %v / %v, want %v / %v", d.StopLoss, d.TakeProfit, price-1.5*lastATR, price+3*lastATR)
+	}
+	// Altcoin cap is 1x equity, shared by max 3 positions
+	if math.Abs(d.PositionSizeUSD-300) > 1e-9 {
+		t.Errorf("position size = %v, want 300", d.PositionSizeUSD)
+	}
+	if !strings.Contains(result.CoTTrace, "DOGEUSDT") || !strings.Contains(d.Reasoning, "rule ema cross") {
+		t.Errorf("unexpected trace: %s\nreasoning: %s", result.CoTTrace, d.Reasoning)
+	}
+}
+
+// TestRunRules_CycleSeries tests rules read the cycle's per-timeframe series without its forming candle
+func TestRunRules_CycleSeries(t *testing.T) {
+	klines := crossKlines(t)
+	// A forming candle that would undo the cross if it were evaluated
+	forming := market.Kline{OpenTime: time.Now().UnixMilli(), Open: 110, High: 110, Low: 50, Close: 50, Volume: 10}
+	series := market.BuildTimeframeSeries(append(klines, forming), "15m", len(klines)+1, market.SeriesOptions{})
+	engine := ruleStrategy(emaCrossLong)
+	ctx := &Context{
+		Account:        AccountInfo{TotalEquity: 900},
+		CandidateCoins: []CandidateCoin{{Symbol: "SOLUSDT"}, {Symbol: "DOGEUSDT"}},
+		MarketDataMap: map[string]*market.Data{
+			"SOLUSDT":  {TimeframeData: map[string]*market.TimeframeSeriesData{"15m": series}},
+			"DOGEUSDT": {TimeframeData: map[string]*market.TimeframeSeriesData{"1h": series}},
+		},
+	}
+
+	result, err := engine.RunRules(ctx)
+	if err != nil {
+		t.Fatalf("RunRules: %v", err)
+	}
+	if len(result.Decisions) != 1 || result.Decisions[0].Symbol != "SOLUSDT" || result.Decisions[0].Action != "open_long" {
+		t.Fatalf("decisions = %+v, want SOLUSDT open_long\n%s", result.Decisions, result.CoTTrace)
+	}
+	if price := klines[len(klines)-1].Close; !strings.Contains(result.Decisions[0].Reasoning, "rule ema cross") || result.Decisions[0].StopLoss >= price {
+		t.Errorf("decision = %+v, want stops from the last closed candle at %v", result.Decisions[0], price)
+	}
+}
+
+// TestRunRules_Positions tests that held symbols only see close rules and max positions limits entries
+func TestRunRules_Positions(t *testing.T) {
+	klines := crossKlines(t)
+	engine := ruleStrategy(
+		emaCrossLong,
+		store.TradingRule{Action: "close_long", Conditions: []store.RuleCondition{{Left: "close", Op: ">", Right: "ema(close,21)"}}},
+		store.TradingRule{Action: "close_short", Conditions: []store.RuleCondition{{Left: "close", Op: ">", Right: "0"}}},
+	)
+	ctx := &Context{
+		Account: AccountInfo{TotalEquity: 900},
+		Positions: []PositionInfo{
+			{Symbol: "ETHUSDT", Side: "long"},
+			{Symbol: "BTCUSDT", Side: "long"},
+			{Symbol: "XRPUSDT", Side: "long"},
+		},
+		CandidateCoins: []CandidateCoin{{Symbol: "ETHUSDT"}, {Symbol: "SOLUSDT"}},
+		MarketDataMap:  map[string]*market.Data{"ETHUSDT": {}, "SOLUSDT": {}},
+		Klines:         func(string, string) ([]market.Kline, error) { return klines, nil },
+	}
+
+	result, err := engine.RunRules(ctx)
+	if err != nil {
+		t.Fatalf("RunRules: %v", err)
+	}
+	var actions []string
+	for _, d := range result.Decisions {
+		actions = append(actions, d.Symbol+" "+d.Action)
+	}
+	// Every long closes; SOL's entry is refused since 3 positions are still open this cycle
+	want := "ETHUSDT close_long,BTCUSDT close_long,XRPUSDT close_long"
+	if strings.Join(actions, ",") != want {
+		t.Errorf("decisions = %v, want %s", actions, want)
+	}
+	if !strings.Contains(result.CoTTrace, "max positions (3) reached") {
+		t.Errorf("trace should explain the skipped entry:\n%s", result.CoTTrace)
+	}
+}
+
+// TestValidateRuleConfig tests rule validation
+func TestValidateRuleConfig(t *testing.T) {
+	if err := ValidateRuleConfig(store.RuleConfig{Rules: []store.TradingRule{emaCrossLong}}); err != nil {
+		t.Errorf("valid rules rejected: %v", err)
+	}
+
+	tests := map[string]store.TradingRule{
+		"missing stops":   {Action: "open_long", Conditions: emaCrossLong.Conditions},
+		"unknown action":  {Action: "buy", Conditions: emaCrossLong.Conditions},
+		"no conditions":   {Action: "close_long"},
+		"bad operator":    {Action: "close_long", Conditions: []store.RuleCondition{{Left: "close", Op: "==", Right: "1"}}},
+		"bad expression":  {Action: "close_long", Conditions: []store.RuleCondition{{Left: "ema(close)", Op: ">", Right: "1"}}},
+		"unknown series":  {Action: "close_long", Conditions: []store.RuleCondition{{Left: "price", Op: ">", Right: "1"}}},
+		"negative period": {Action: "open_short", Conditions: emaCrossLong.Conditions, StopLossATR: 1, TakeProfitATR: 2, ATRPeriod: -1},
+	}
+	for name, rule := range tests {
+		if err := ValidateRuleConfig(store.RuleConfig{Rules: []store.TradingRule{rule}}); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+	if err := ValidateRuleConfig(store.RuleConfig{}); err == nil {
+		t.Error("empty rules should be rejected")
+	}
+}
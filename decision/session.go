@@ -0,0 +1,355 @@
+package decision
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"nofx/store"
+	"sort"
+	"strings"
+	"time"
+)
+
+const defaultTightenStopPct = 1.0
+
+// SessionState trading restrictions at a point in time
+type SessionState struct {
+	OpensAllowed   bool                 `json:"opens_allowed"`
+	Blackout       *store.BlackoutEvent `json:"blackout,omitempty"`         // Active blackout event (nil = none)
+	Action         string               `json:"action,omitempty"`           // Blackout action for open positions
+	TightenStopPct float64              `json:"tighten_stop_pct,omitempty"` // tighten_stops: max stop distance from mark price in percent
+	Reason         string               `json:"reason,omitempty"`           // Why opens are not allowed
+}
+
+var weekdayNames = map[string]time.Weekday{
+	"sun": time.Sunday, "mon": time.Monday, "tue": time.Tuesday, "wed": time.Wednesday,
+	"thu": time.Thursday, "fri": time.Friday, "sat": time.Saturday,
+}
+
+// EvaluateSession evaluates session windows and blackouts of the strategy at time t
+// Returns nil when session restrictions are disabled
+func EvaluateSession(config store.TradingSessionConfig, t time.Time) *SessionState {
+	if !config.Enabled {
+		return nil
+	}
+	state := &SessionState{OpensAllowed: true}
+
+	for i := range config.Blackouts {
+		event := config.Blackouts[i]
+		if !t.Before(event.Start) && t.Before(event.End) {
+			state.OpensAllowed = false
+			state.Blackout = &event
+			state.Action = config.BlackoutAction
+			if state.Action == "" {
+				state.Action = store.BlackoutActionBlockOpens
+			}
+			if state.Action == store.BlackoutActionTightenStops {
+				state.TightenStopPct = config.TightenStopPct
+				if state.TightenStopPct <= 0 {
+					state.TightenStopPct = defaultTightenStopPct
+				}
+			}
+			state.Reason = fmt.Sprintf("blackout %q until %s", event.Name, event.End.UTC().Format("2006-01-02 15:04 UTC"))
+			return state
+		}
+	}
+
+	if len(config.Windows) == 0 {
+		return state
+	}
+	loc := time.UTC
+	if config.Timezone != "" {
+		if l, err := time.LoadLocation(config.Timezone); err == nil {
+			loc = l
+		}
+	}
+	local := t.In(loc)
+	for _, window := range config.Windows {
+		if inSessionWindow(window, local) {
+			return state
+		}
+	}
+	state.OpensAllowed = false
+	state.Reason = fmt.Sprintf("outside trading session windows (%s %s)", local.Format("Mon 15:04"), loc.String())
+	return state
+}
+
+// TightenedStop the tighten_stops stop of a position at markPrice
+// ok is false when the current stop (0 = none) is already as close to the price, a stop is never loosened
+func (s *SessionState) TightenedStop(side string, markPrice, current float64) (stop float64, ok bool) {
+	if side == "short" {
+		stop = markPrice * (1 + s.TightenStopPct/100)
+		return stop, current <= 0 || stop < current
+	}
+	stop = markPrice * (1 - s.TightenStopPct/100)
+	return stop, current <= 0 || stop > current
+}
+
+// inSessionWindow reports whether the local time falls in the window
+// A window crossing midnight belongs to the day it starts on
+func inSessionWindow(window store.SessionWindow, local time.Time) bool {
+	start, errStart := parseClock(window.Start)
+	end, errEnd := parseClock(window.End)
+	if errStart != nil || errEnd != nil {
+		return false
+	}
+	minute := local.Hour()*60 + local.Minute()
+	day := local.Weekday()
+
+	switch {
+	case start < end:
+		return minute >= start && minute < end && windowHasDay(window, day)
+	case start > end: // Crosses midnight
+		if minute >= start {
+			return windowHasDay(window, day)
+		}
+		return minute < end && windowHasDay(window, (day+6)%7)
+	default: // Start == End: whole day
+		return windowHasDay(window, day)
+	}
+}
+
+// windowHasDay reports whether the window applies to the weekday
+func windowHasDay(window store.SessionWindow, day time.Weekday) bool {
+	if len(window.Days) == 0 {
+		return true
+	}
+	for _, d := range window.Days {
+		if wd, ok := parseWeekday(d); ok && wd == day {
+			return true
+		}
+	}
+	return false
+}
+
+// parseWeekday parses "mon", "Monday", ... into a weekday
+func parseWeekday(s string) (time.Weekday, bool) {
+	s = strings.ToLower(strings.TrimSpace(s))
+	if len(s) < 3 {
+		return 0, false
+	}
+	wd, ok := weekdayNames[s[:3]]
+	return wd, ok
+}
+
+// parseClock parses "HH:MM" into minutes since midnight
+func parseClock(s string) (int, error) {
+	t, err := time.Parse("15:04", strings.TrimSpace(s))
+	if err != nil {
+		return 0, fmt.Errorf("invalid time %q, expected HH:MM", s)
+	}
+	return t.Hour()*60 + t.Minute(), nil
+}
+
+// ValidateSessionConfig validates timezone and windows of a session configuration
+func ValidateSessionConfig(config store.TradingSessionConfig) error {
+	if config.Timezone != "" {
+		if _, err := time.LoadLocation(config.Timezone); err != nil {
+			return fmt.Errorf("invalid timezone %q: %w", config.Timezone, err)
+		}
+	}
+	for _, window := range config.Windows {
+		if _, err := parseClock(window.Start); err != nil {
+			return err
+		}
+		if _, err := parseClock(window.End); err != nil {
+			return err
+		}
+		for _, d := range window.Days {
+			if _, ok := parseWeekday(d); !ok {
+				return fmt.Errorf("invalid weekday %q", d)
+			}
+		}
+	}
+	switch config.BlackoutAction {
+	case "", store.BlackoutActionBlockOpens, store.BlackoutActionTightenStops, store.BlackoutActionFlatten:
+	default:
+		return fmt.Errorf("invalid blackout action %q", config.BlackoutAction)
+	}
+	return nil
+}
+
+// ParseBlackoutCalendar parses blackout events from an iCal (.ics) or CSV file
+// format is "ics" or "csv"; CSV rows are name,start,end with RFC3339 or "2006-01-02 15:04" (UTC) times
+func ParseBlackoutCalendar(data []byte, format string) ([]store.BlackoutEvent, error) {
+	var (
+		events []store.BlackoutEvent
+		err    error
+	)
+	switch strings.ToLower(format) {
+	case "ics", "ical":
+		events, err = parseICalEvents(data)
+	case "csv":
+		events, err = parseCSVEvents(data)
+	default:
+		return nil, fmt.Errorf("unsupported calendar format %q (expected ics or csv)", format)
+	}
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(events, func(i, j int) bool { return events[i].Start.Before(events[j].Start) })
+	return events, nil
+}
+
+// parseICalEvents parses VEVENT entries (SUMMARY, DTSTART, DTEND/DURATION)
+func parseICalEvents(data []byte) ([]store.BlackoutEvent, error) {
+	// Unfold continuation lines (RFC 5545: lines starting with a space or tab)
+	var lines []string
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), "\r")
+		if (strings.HasPrefix(line, " ") || strings.HasPrefix(line, "\t")) && len(lines) > 0 {
+			lines[len(lines)-1] += line[1:]
+			continue
+		}
+		lines = append(lines, line)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	var (
+		events   []store.BlackoutEvent
+		current  *store.BlackoutEvent
+		duration time.Duration
+	)
+	for _, line := range lines {
+		name, value, ok := strings.Cut(line, ":")
+		if !ok {
+			continue
+		}
+		key, params, _ := strings.Cut(name, ";")
+		switch strings.ToUpper(key) {
+		case "BEGIN":
+			if strings.EqualFold(value, "VEVENT") {
+				current = &store.BlackoutEvent{}
+				duration = 0
+			}
+		case "END":
+			if strings.EqualFold(value, "VEVENT") && current != nil {
+				if current.End.IsZero() && duration > 0 {
+					current.End = current.Start.Add(duration)
+				}
+				if current.Start.IsZero() || !current.End.After(current.Start) {
+					return nil, fmt.Errorf("event %q has an invalid start/end", current.Name)
+				}
+				events = append(events, *current)
+				current = nil
+			}
+		case "SUMMARY":
+			if current != nil {
+				current.Name = strings.ReplaceAll(value, "\\,", ",")
+			}
+		case "DTSTART", "DTEND":
+			if current == nil {
+				continue
+			}
+			t, err := parseICalTime(value, params)
+			if err != nil {
+				return nil, err
+			}
+			if strings.EqualFold(key, "DTSTART") {
+				current.Start = t
+			} else {
+				current.End = t
+			}
+		case "DURATION":
+			d, err := parseICalDuration(value)
+			if err != nil {
+				return nil, err
+			}
+			duration = d
+		}
+	}
+	return events, nil
+}
+
+// parseICalTime parses DATE-TIME (UTC, floating or TZID) and DATE values
+func parseICalTime(value, params string) (time.Time, error) {
+	loc := time.UTC
+	for _, param := range strings.Split(params, ";") {
+		if k, v, ok := strings.Cut(param, "="); ok && strings.EqualFold(k, "TZID") {
+			if l, err := time.LoadLocation(v); err == nil {
+				loc = l
+			}
+		}
+	}
+	if strings.HasSuffix(value, "Z") {
+		return time.Parse("20060102T150405Z", value)
+	}
+	if len(value) == len("20060102") {
+		return time.ParseInLocation("20060102", value, loc)
+	}
+	t, err := time.ParseInLocation("20060102T150405", value, loc)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid iCal time %q", value)
+	}
+	return t, nil
+}
+
+// parseICalDuration parses durations such as PT30M, PT1H30M, P1D
+func parseICalDuration(value string) (time.Duration, error) {
+	v := strings.TrimPrefix(strings.ToUpper(value), "P")
+	var d time.Duration
+	if days, rest, ok := strings.Cut(v, "D"); ok {
+		var n int
+		if _, err := fmt.Sscanf(days, "%d", &n); err != nil {
+			return 0, fmt.Errorf("invalid iCal duration %q", value)
+		}
+		d += time.Duration(n) * 24 * time.Hour
+		v = rest
+	}
+	if v = strings.TrimPrefix(v, "T"); v != "" {
+		parsed, err := time.ParseDuration(strings.ToLower(v))
+		if err != nil {
+			return 0, fmt.Errorf("invalid iCal duration %q", value)
+		}
+		d += parsed
+	}
+	return d, nil
+}
+
+// parseCSVEvents parses name,start,end rows (a header row is skipped)
+func parseCSVEvents(data []byte) ([]store.BlackoutEvent, error) {
+	reader := csv.NewReader(bytes.NewReader(data))
+	reader.TrimLeadingSpace = true
+	reader.FieldsPerRecord = -1
+
+	var events []store.BlackoutEvent
+	for row := 1; ; row++ {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		if len(record) < 3 {
+			return nil, fmt.Errorf("row %d: expected name,start,end", row)
+		}
+		start, errStart := parseCSVTime(record[1])
+		end, errEnd := parseCSVTime(record[2])
+		if errStart != nil || errEnd != nil {
+			if row == 1 {
+				continue // Header
+			}
+			return nil, fmt.Errorf("row %d: invalid start/end time", row)
+		}
+		if !end.After(start) {
+			return nil, fmt.Errorf("row %d: end must be after start", row)
+		}
+		events = append(events, store.BlackoutEvent{Name: strings.TrimSpace(record[0]), Start: start, End: end})
+	}
+	return events, nil
+}
+
+// parseCSVTime parses RFC3339 or "2006-01-02 15:04" (UTC)
+func parseCSVTime(s string) (time.Time, error) {
+	s = strings.TrimSpace(s)
+	if t, err := time.Parse(time.RFC3339, s); err == nil {
+		return t, nil
+	}
+	return time.Parse("2006-01-02 15:04", s)
+}
@@ -0,0 +1,135 @@
+package decision
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"nofx/store"
+)
+
+// TestEvaluateSession tests session windows, midnight-crossing windows and blackouts
+func TestEvaluateSession(t *testing.T) {
+	cpiStart := time.Date(2025, 1, 15, 13, 0, 0, 0, time.UTC)
+	config := store.TradingSessionConfig{
+		Enabled:  true,
+		Timezone: "America/New_York",
+		Windows: []store.SessionWindow{
+			{Days: []string{"mon", "tue", "wed", "thu", "fri"}, Start: "09:30", End: "16:00"},
+			{Days: []string{"Sunday"}, Start: "22:00", End: "02:00"},
+		},
+		Blackouts: []store.BlackoutEvent{
+			{Name: "CPI", Start: cpiStart, End: cpiStart.Add(time.Hour)},
+		},
+		BlackoutAction: store.BlackoutActionFlatten,
+	}
+	ny, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Skipf("timezone data unavailable: %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		at       time.Time
+		allowed  bool
+		blackout bool
+	}{
+		{"weekday inside window", time.Date(2025, 1, 14, 10, 0, 0, 0, ny), true, false},
+		{"weekday before window", time.Date(2025, 1, 14, 9, 0, 0, 0, ny), false, false},
+		{"window end is exclusive", time.Date(2025, 1, 14, 16, 0, 0, 0, ny), false, false},
+		{"saturday", time.Date(2025, 1, 18, 12, 0, 0, 0, ny), false, false},
+		{"sunday late evening", time.Date(2025, 1, 19, 23, 0, 0, 0, ny), true, false},
+		{"monday after midnight (sunday window)", time.Date(2025, 1, 20, 1, 0, 0, 0, ny), true, false},
+		{"monday after midnight window end", time.Date(2025, 1, 20, 3, 0, 0, 0, ny), false, false},
+		{"blackout inside window", cpiStart.Add(30 * time.Minute), false, true},
+	}
+	for _, tt := range tests {
+		state := EvaluateSession(config, tt.at)
+		if state == nil {
+			t.Fatalf("%s: expected session state", tt.name)
+		}
+		if state.OpensAllowed != tt.allowed {
+			t.Errorf("%s: OpensAllowed = %v, want %v (%s)", tt.name, state.OpensAllowed, tt.allowed, state.Reason)
+		}
+		if (state.Blackout != nil) != tt.blackout {
+			t.Errorf("%s: blackout = %v, want %v", tt.name, state.Blackout != nil, tt.blackout)
+		}
+		if tt.blackout && state.Action != store.BlackoutActionFlatten {
+			t.Errorf("%s: action = %q, want flatten", tt.name, state.Action)
+		}
+	}
+
+	config.Enabled = false
+	if EvaluateSession(config, cpiStart) != nil {
+		t.Error("disabled sessions should return nil")
+	}
+}
+
+// TestParseBlackoutCalendar tests iCal and CSV imports
+func TestParseBlackoutCalendar(t *testing.T) {
+	ics := "BEGIN:VCALENDAR\r\nVERSION:2.0\r\n" +
+		"BEGIN:VEVENT\r\nSUMMARY:FOMC Rate\r\n  Decision\r\nDTSTART;TZID=America/New_York:20250129T140000\r\nDURATION:PT1H30M\r\nEND:VEVENT\r\n" +
+		"BEGIN:VEVENT\r\nSUMMARY:CPI\r\nDTSTART:20250115T133000Z\r\nDTEND:20250115T143000Z\r\nEND:VEVENT\r\n" +
+		"END:VCALENDAR\r\n"
+	events, err := ParseBlackoutCalendar([]byte(ics), "ics")
+	if err != nil {
+		t.Fatalf("ics: %v", err)
+	}
+	if len(events) != 2 {
+		t.Fatalf("ics: got %d events, want 2", len(events))
+	}
+	if events[0].Name != "CPI" || !events[0].Start.Equal(time.Date(2025, 1, 15, 13, 30, 0, 0, time.UTC)) {
+		t.Errorf("ics: unexpected first event %+v", events[0])
+	}
+	if events[1].Name != "FOMC Rate Decision" || events[1].End.Sub(events[1].Start) != 90*time.Minute {
+		t.Errorf("ics: unexpected second event %+v", events[1])
+	}
+
+	csvData := "name,start,end\nNFP,2025-02-07 13:00,2025-02-07 14:00\nCPI,2025-02-12T13:30:00Z,2025-02-12T14:30:00Z\n"
+	events, err = ParseBlackoutCalendar([]byte(csvData), "csv")
+	if err != nil {
+		t.Fatalf("csv: %v", err)
+	}
+	if len(events) != 2 || events[0].Name != "NFP" || events[1].Name != "CPI" {
+		t.Errorf("csv: unexpected events %+v", events)
+	}
+
+	if _, err := ParseBlackoutCalendar([]byte("X,2025-02-07 14:00,2025-02-07 13:00\n"), "csv"); err == nil {
+		t.Error("csv: expected error when end is before start")
+	}
+	if _, err := ParseBlackoutCalendar(nil, "xlsx"); err == nil {
+		t.Error("expected error for unsupported format")
+	}
+}
+
+// TestTightenedStop tests tighten_stops never loosens a stop
+func TestTightenedStop(t *testing.T) {
+	state := EvaluateSession(store.TradingSessionConfig{
+		Enabled:        true,
+		BlackoutAction: store.BlackoutActionTightenStops,
+		Blackouts:      []store.BlackoutEvent{{Name: "CPI", Start: time.Unix(0, 0), End: time.Unix(3600, 0)}},
+	}, time.Unix(60, 0))
+	if state.TightenStopPct != defaultTightenStopPct {
+		t.Fatalf("expected default tighten pct, got %v", state.TightenStopPct)
+	}
+
+	tests := []struct {
+		side    string
+		current float64
+		stop    float64
+		ok      bool
+	}{
+		{"long", 0, 99, true},     // No stop yet
+		{"long", 95, 99, true},    // Looser stop is replaced
+		{"long", 99.5, 99, false}, // Tighter stop is kept
+		{"short", 0, 101, true},
+		{"short", 105, 101, true},
+		{"short", 100.5, 101, false},
+	}
+	for _, tt := range tests {
+		stop, ok := state.TightenedStop(tt.side, 100, tt.current)
+		if ok != tt.ok || math.Abs(stop-tt.stop) > 1e-9 {
+			t.Errorf("%s current=%v: got (%v, %v), want (%v, %v)", tt.side, tt.current, stop, ok, tt.stop, tt.ok)
+		}
+	}
+}
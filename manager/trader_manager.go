@@ -407,6 +407,7 @@ func (tm *TraderManager) RemoveTrader(traderID string) {
 
 	if _, exists := tm.traders[traderID]; exists {
 		delete(tm.traders, traderID)
+		trader.ReleaseAccountStream(traderID)
 		logger.Infof("✓ Trader %s removed from memory", traderID)
 	}
 }
@@ -634,10 +635,7 @@ func (tm *TraderManager) addTraderFromStore(traderCfg *store.Trader, aiModelCfg
 		AIModel:               aiModelCfg.Provider,
 		Exchange:              exchangeCfg.ExchangeType, // Exchange type: binance/bybit/okx/etc
 		ExchangeID:            exchangeCfg.ID,           // Exchange account UUID (for multi-account)
-		BinanceAPIKey:         "",
-		BinanceSecretKey:      "",
-		HyperliquidPrivateKey: "",
-		HyperliquidTestnet:    exchangeCfg.Testnet,
+		Account:               exchangeCfg,
 		UseQwen:               aiModelCfg.Provider == "qwen",
 		DeepSeekKey:           "",
 		QwenKey:               "",
@@ -650,35 +648,6 @@ func (tm *TraderManager) addTraderFromStore(traderCfg *store.Trader, aiModelCfg
 		StrategyConfig:       strategyConfig,
 	}
 
-	// Set API keys based on exchange type
-	switch exchangeCfg.ExchangeType {
-	case "binance":
-		traderConfig.BinanceAPIKey = exchangeCfg.APIKey
-		traderConfig.BinanceSecretKey = exchangeCfg.SecretKey
-	case "bybit":
-		traderConfig.BybitAPIKey = exchangeCfg.APIKey
-		traderConfig.BybitSecretKey = exchangeCfg.SecretKey
-	case "okx":
-		traderConfig.OKXAPIKey = exchangeCfg.APIKey
-		traderConfig.OKXSecretKey = exchangeCfg.SecretKey
-		traderConfig.OKXPassphrase = exchangeCfg.Passphrase
-	case "bitget":
-		traderConfig.BitgetAPIKey = exchangeCfg.APIKey
-		traderConfig.BitgetSecretKey = exchangeCfg.SecretKey
-		traderConfig.BitgetPassphrase = exchangeCfg.Passphrase
-	case "hyperliquid":
-		traderConfig.HyperliquidPrivateKey = exchangeCfg.APIKey
-		traderConfig.HyperliquidWalletAddr = exchangeCfg.HyperliquidWalletAddr
-	case "aster":
-		traderConfig.AsterUser = exchangeCfg.AsterUser
-		traderConfig.AsterSigner = exchangeCfg.AsterSigner
-		traderConfig.AsterPrivateKey = exchangeCfg.AsterPrivateKey
-	case "lighter":
-		traderConfig.LighterPrivateKey = exchangeCfg.LighterPrivateKey
-		traderConfig.LighterWalletAddr = exchangeCfg.LighterWalletAddr
-		traderConfig.LighterTestnet = exchangeCfg.Testnet
-	}
-
 	// Set API keys based on AI model
 	switch aiModelCfg.Provider {
 	case "qwen":
@@ -707,6 +676,11 @@ func (tm *TraderManager) addTraderFromStore(traderCfg *store.Trader, aiModelCfg
 		}
 	}
 
+	// Copy-trading followers replicate this trader's orders
+	if err := LoadFollowers(st, at, traderCfg.UserID); err != nil {
+		logger.Warnf("⚠️ Failed to load followers of trader '%s': %v", traderCfg.Name, err)
+	}
+
 	tm.traders[traderCfg.ID] = at
 	logger.Infof("✓ Trader '%s' (%s + %s/%s) loaded to memory", traderCfg.Name, aiModelCfg.Provider, exchangeCfg.ExchangeType, exchangeCfg.AccountName)
 
@@ -737,3 +711,32 @@ func (tm *TraderManager) GetTraderExecutor(traderID string) (debate.TraderExecut
 	}
 	return &TraderExecutorAdapter{autoTrader: at}, nil
 }
+
+// LoadFollowers (re)loads the copy-trading followers of a trader from the store
+// Followers whose exchange account can't be loaded are skipped
+func LoadFollowers(st *store.Store, at *trader.AutoTrader, userID string) error {
+	configs, err := st.Follower().ListByLeader(at.GetID())
+	if err != nil {
+		return err
+	}
+
+	var followers []*trader.Follower
+	for _, cfg := range configs {
+		if !cfg.Enabled {
+			continue
+		}
+		exchangeCfg, err := st.Exchange().GetByID(userID, cfg.ExchangeID)
+		if err != nil {
+			logger.Warnf("⚠️ Follower '%s': exchange %s not found: %v", cfg.Name, cfg.ExchangeID, err)
+			continue
+		}
+		client, err := trader.NewTraderFromExchange(exchangeCfg, userID)
+		if err != nil {
+			logger.Warnf("⚠️ Follower '%s': failed to create %s client: %v", cfg.Name, exchangeCfg.ExchangeType, err)
+			continue
+		}
+		followers = append(followers, trader.NewFollower(cfg, client))
+	}
+	at.SetFollowers(followers)
+	return nil
+}
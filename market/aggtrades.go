@@ -0,0 +1,305 @@
+package market
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// LargeTradeUSDT aggregate trades at or above this notional count as large trades
+	LargeTradeUSDT = 100_000.0
+
+	aggTradeBucketMs   = int64(60_000) // Trades are aggregated into 1-minute buckets
+	aggTradeRetention  = 48 * 60       // Minute buckets kept per symbol (48 hours)
+	aggTradeBufferSize = 1000          // Stream channel buffer, busy symbols print many trades per second
+	aggTradeMinCover   = 0.5           // A bar counts as stream-covered when the stream saw at least this share of its quote volume
+	aggTradeIdleTTL    = 2 * time.Hour // Streams of symbols no cycle asked for within this time are unsubscribed
+)
+
+// AggTrade an aggregate trade from the <symbol>@aggTrade stream
+type AggTrade struct {
+	Price        float64
+	Quantity     float64
+	Time         int64 // Trade time (ms)
+	BuyerIsMaker bool  // true = the taker sold
+}
+
+// AggTradeWSData aggTrade stream payload
+type AggTradeWSData struct {
+	EventType    string `json:"e"`
+	Symbol       string `json:"s"`
+	Price        string `json:"p"`
+	Quantity     string `json:"q"`
+	TradeTime    int64  `json:"T"`
+	BuyerIsMaker bool   `json:"m"`
+}
+
+// FlowBucket taker flow of one minute
+type FlowBucket struct {
+	Start      int64   // Bucket open time (ms)
+	BuyVolume  float64 // Taker buy notional (USDT)
+	SellVolume float64 // Taker sell notional (USDT)
+	LargeBuys  int     // Taker buys >= LargeTradeUSDT
+	LargeSells int     // Taker sells >= LargeTradeUSDT
+}
+
+// TakerFlowSeries taker flow per kline of a timeframe (oldest → latest)
+type TakerFlowSeries struct {
+	BuyVolume  []float64 `json:"buy_volume"`  // Taker buy notional (USDT)
+	SellVolume []float64 `json:"sell_volume"` // Taker sell notional (USDT)
+	Delta      []float64 `json:"delta"`       // Buy - sell
+	CVD        []float64 `json:"cvd"`         // Cumulative delta from the first bar of the window
+	LargeBuys  []int     `json:"large_buys"`
+	LargeSells []int     `json:"large_sells"`
+	// Trailing bars covered by the aggTrade stream, older bars use kline taker volume and have no large-trade counts
+	StreamBars int `json:"stream_bars"`
+}
+
+// AggTradeTracker aggregates aggTrade stream trades into per-symbol minute buckets
+type AggTradeTracker struct {
+	mu      sync.RWMutex
+	buckets map[string][]FlowBucket
+	since   map[string]int64 // First minute fully seen by the stream
+}
+
+// NewAggTradeTracker creates an empty tracker
+func NewAggTradeTracker() *AggTradeTracker {
+	return &AggTradeTracker{
+		buckets: make(map[string][]FlowBucket),
+		since:   make(map[string]int64),
+	}
+}
+
+// AddTrade adds a trade to the symbol's minute bucket
+func (t *AggTradeTracker) AddTrade(symbol string, trade AggTrade) {
+	if trade.Price <= 0 || trade.Quantity <= 0 {
+		return
+	}
+	notional := trade.Price * trade.Quantity
+	start := trade.Time - trade.Time%aggTradeBucketMs
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if _, ok := t.since[symbol]; !ok {
+		// The first minute is only partially seen, coverage starts with the next one
+		t.since[symbol] = start + aggTradeBucketMs
+	}
+
+	buckets := t.buckets[symbol]
+	if n := len(buckets); n == 0 || buckets[n-1].Start < start {
+		buckets = append(buckets, FlowBucket{Start: start})
+		if len(buckets) > aggTradeRetention {
+			buckets = buckets[len(buckets)-aggTradeRetention:]
+		}
+	}
+
+	// Trades arrive in order, late ones can only belong to a recent bucket
+	i := len(buckets) - 1
+	for i >= 0 && buckets[i].Start > start {
+		i--
+	}
+	if i < 0 || buckets[i].Start != start {
+		t.buckets[symbol] = buckets
+		return
+	}
+
+	b := &buckets[i]
+	if trade.BuyerIsMaker {
+		b.SellVolume += notional
+		if notional >= LargeTradeUSDT {
+			b.LargeSells++
+		}
+	} else {
+		b.BuyVolume += notional
+		if notional >= LargeTradeUSDT {
+			b.LargeBuys++
+		}
+	}
+	t.buckets[symbol] = buckets
+}
+
+// Remove drops the symbol's buckets (stream unsubscribed)
+func (t *AggTradeTracker) Remove(symbol string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	delete(t.buckets, symbol)
+	delete(t.since, symbol)
+}
+
+// Buckets returns a copy of the symbol's minute buckets and the time (ms) from which the stream fully covers them
+// since is 0 when no trade has been seen
+func (t *AggTradeTracker) Buckets(symbol string) (buckets []FlowBucket, since int64) {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	src := t.buckets[symbol]
+	buckets = make([]FlowBucket, len(src))
+	copy(buckets, src)
+	return buckets, t.since[symbol]
+}
+
+// ComputeTakerFlow computes taker flow for the last count klines
+// Bars opening at or after since take their flow from the stream buckets, older bars (and every bar when
+// there are no buckets, e.g. backtests) fall back to the kline taker buy quote volume
+func ComputeTakerFlow(klines []Kline, count int, buckets []FlowBucket, since int64) *TakerFlowSeries {
+	start := len(klines) - count
+	if start < 0 {
+		start = 0
+	}
+	window := klines[start:]
+	n := len(window)
+
+	flow := &TakerFlowSeries{
+		BuyVolume:  make([]float64, n),
+		SellVolume: make([]float64, n),
+		Delta:      make([]float64, n),
+		CVD:        make([]float64, n),
+		LargeBuys:  make([]int, n),
+		LargeSells: make([]int, n),
+	}
+
+	// Walk backwards so coverage stops at the first bar the stream didn't fully see
+	covered := since > 0 && len(buckets) > 0
+	j := len(buckets) - 1
+	for i := n - 1; i >= 0; i-- {
+		k := window[i]
+		if covered && k.OpenTime >= since {
+			var bar FlowBucket
+			for j >= 0 && buckets[j].Start >= k.OpenTime {
+				if buckets[j].Start <= k.CloseTime {
+					bar.BuyVolume += buckets[j].BuyVolume
+					bar.SellVolume += buckets[j].SellVolume
+					bar.LargeBuys += buckets[j].LargeBuys
+					bar.LargeSells += buckets[j].LargeSells
+				}
+				j--
+			}
+			if k.QuoteVolume <= 0 || bar.BuyVolume+bar.SellVolume >= k.QuoteVolume*aggTradeMinCover {
+				flow.BuyVolume[i] = bar.BuyVolume
+				flow.SellVolume[i] = bar.SellVolume
+				flow.LargeBuys[i] = bar.LargeBuys
+				flow.LargeSells[i] = bar.LargeSells
+				flow.StreamBars++
+				continue
+			}
+		}
+		covered = false
+		flow.BuyVolume[i] = k.TakerBuyQuoteVolume
+		flow.SellVolume[i] = k.QuoteVolume - k.TakerBuyQuoteVolume
+	}
+
+	cvd := 0.0
+	for i := 0; i < n; i++ {
+		flow.Delta[i] = flow.BuyVolume[i] - flow.SellVolume[i]
+		cvd += flow.Delta[i]
+		flow.CVD[i] = cvd
+	}
+	return flow
+}
+
+// SubscribeAggTrades subscribes to the symbol's aggTrade stream once, trades are kept in the monitor's tracker
+// Every call marks the symbol as in use; streams of symbols that rotated out of the candidates are unsubscribed
+// once no cycle asked for them within aggTradeIdleTTL
+func (m *WSMonitor) SubscribeAggTrades(symbol string) error {
+	symbol = strings.ToUpper(symbol)
+	now := time.Now()
+	m.pruneAggTrades(now)
+	if _, loaded := m.aggTradeSubs.Swap(symbol, now); loaded {
+		return nil
+	}
+
+	stream := aggTradeStream(symbol)
+	ch := m.combinedClient.AddSubscriber(stream, aggTradeBufferSize)
+	go m.handleAggTradeData(symbol, ch)
+
+	if err := m.combinedClient.subscribeStreams([]string{stream}); err != nil {
+		// Closing the channel ends the handler goroutine
+		m.combinedClient.RemoveSubscriber(stream)
+		m.aggTradeSubs.Delete(symbol)
+		return fmt.Errorf("failed to subscribe to %s: %w", stream, err)
+	}
+	log.Printf("Subscribed to aggregate trade stream: %s", stream)
+	return nil
+}
+
+// pruneAggTrades unsubscribes the aggTrade streams of symbols not asked for within aggTradeIdleTTL
+func (m *WSMonitor) pruneAggTrades(now time.Time) {
+	m.aggTradeSubs.Range(func(key, value any) bool {
+		symbol, lastUsed := key.(string), value.(time.Time)
+		if now.Sub(lastUsed) < aggTradeIdleTTL || !m.aggTradeSubs.CompareAndDelete(symbol, lastUsed) {
+			return true
+		}
+		stream := aggTradeStream(symbol)
+		if err := m.combinedClient.unsubscribeStreams([]string{stream}); err != nil {
+			log.Printf("Failed to unsubscribe from %s: %v", stream, err)
+		}
+		m.combinedClient.RemoveSubscriber(stream)
+		m.aggTrades.Remove(symbol)
+		log.Printf("Unsubscribed from idle aggregate trade stream: %s", stream)
+		return true
+	})
+}
+
+// aggTradeStream stream name of a symbol's aggregate trades
+func aggTradeStream(symbol string) string {
+	return fmt.Sprintf("%s@aggTrade", strings.ToLower(symbol))
+}
+
+// GetTakerFlowBuckets returns the symbol's aggTrade minute buckets and the time from which they are complete
+func (m *WSMonitor) GetTakerFlowBuckets(symbol string) ([]FlowBucket, int64) {
+	return m.aggTrades.Buckets(strings.ToUpper(symbol))
+}
+
+func (m *WSMonitor) handleAggTradeData(symbol string, ch <-chan []byte) {
+	for data := range ch {
+		var wsData AggTradeWSData
+		if err := json.Unmarshal(data, &wsData); err != nil {
+			log.Printf("Failed to parse aggTrade data: %v", err)
+			continue
+		}
+		trade := AggTrade{Time: wsData.TradeTime, BuyerIsMaker: wsData.BuyerIsMaker}
+		trade.Price, _ = parseFloat(wsData.Price)
+		trade.Quantity, _ = parseFloat(wsData.Quantity)
+		m.aggTrades.AddTrade(symbol, trade)
+	}
+}
+
+// FormatTakerFlow formats a timeframe's taker flow series for prompts
+func FormatTakerFlow(flow *TakerFlowSeries) string {
+	if flow == nil || len(flow.Delta) == 0 {
+		return ""
+	}
+
+	s := fmt.Sprintf("Taker buy (USDT): %s\n", formatVolumeSlice(flow.BuyVolume))
+	s += fmt.Sprintf("Taker sell (USDT): %s\n", formatVolumeSlice(flow.SellVolume))
+	s += fmt.Sprintf("Taker delta (USDT): %s\n", formatVolumeSlice(flow.Delta))
+	s += fmt.Sprintf("CVD (USDT, from first bar): %s\n", formatVolumeSlice(flow.CVD))
+	if flow.StreamBars > 0 {
+		from := len(flow.Delta) - flow.StreamBars
+		s += fmt.Sprintf("Large trades >= %.0f USDT, last %d bars (buys/sells): %s\n",
+			LargeTradeUSDT, flow.StreamBars, formatLargeTrades(flow.LargeBuys[from:], flow.LargeSells[from:]))
+	}
+	return s
+}
+
+func formatVolumeSlice(values []float64) string {
+	parts := make([]string, len(values))
+	for i, v := range values {
+		parts[i] = fmt.Sprintf("%.0f", v)
+	}
+	return "[" + strings.Join(parts, ", ") + "]"
+}
+
+func formatLargeTrades(buys, sells []int) string {
+	parts := make([]string, len(buys))
+	for i := range buys {
+		parts[i] = fmt.Sprintf("%d/%d", buys[i], sells[i])
+	}
+	return "[" + strings.Join(parts, ", ") + "]"
+}
@@ -0,0 +1,165 @@
+package market
+
+import (
+	"math"
+	"strings"
+	"testing"
+	"time"
+)
+
+// flowKlines 5-minute klines starting at t=0 with quote volume 1000 and 600 taker buys
+func flowKlines(count int) []Kline {
+	klines := make([]Kline, count)
+	for i := range klines {
+		open := int64(i) * 5 * aggTradeBucketMs
+		klines[i] = Kline{
+			OpenTime:            open,
+			CloseTime:           open + 5*aggTradeBucketMs - 1,
+			Close:               100,
+			QuoteVolume:         1000,
+			TakerBuyQuoteVolume: 600,
+		}
+	}
+	return klines
+}
+
+// TestAggTradeTracker_AddTrade tests minute bucketing, taker side and large-trade counting
+func TestAggTradeTracker_AddTrade(t *testing.T) {
+	tracker := NewAggTradeTracker()
+	tracker.AddTrade("BTCUSDT", AggTrade{Price: 100, Quantity: 2, Time: 30_000})                        // Buy 200
+	tracker.AddTrade("BTCUSDT", AggTrade{Price: 100, Quantity: 1, Time: 59_999, BuyerIsMaker: true})    // Sell 100
+	tracker.AddTrade("BTCUSDT", AggTrade{Price: 100, Quantity: 1500, Time: 61_000, BuyerIsMaker: true}) // Large sell
+	tracker.AddTrade("BTCUSDT", AggTrade{Price: 100, Quantity: 1, Time: 45_000})                        // Late trade, first minute
+	tracker.AddTrade("BTCUSDT", AggTrade{Price: 100, Quantity: 0, Time: 62_000})                        // Ignored
+
+	buckets, since := tracker.Buckets("BTCUSDT")
+	if since != aggTradeBucketMs {
+		t.Errorf("since = %d, want %d (first full minute)", since, aggTradeBucketMs)
+	}
+	if len(buckets) != 2 {
+		t.Fatalf("buckets = %+v, want 2", buckets)
+	}
+	if buckets[0].BuyVolume != 300 || buckets[0].SellVolume != 100 {
+		t.Errorf("first bucket = %+v, want buy 300 / sell 100", buckets[0])
+	}
+	if buckets[1].SellVolume != 150_000 || buckets[1].LargeSells != 1 || buckets[1].LargeBuys != 0 {
+		t.Errorf("second bucket = %+v, want one large sell of 150000", buckets[1])
+	}
+
+	if b, since := tracker.Buckets("ETHUSDT"); len(b) != 0 || since != 0 {
+		t.Errorf("unknown symbol returned %d buckets since %d", len(b), since)
+	}
+}
+
+// TestComputeTakerFlow_KlineFallback tests that taker flow comes from kline taker volume without a stream
+func TestComputeTakerFlow_KlineFallback(t *testing.T) {
+	flow := ComputeTakerFlow(flowKlines(10), 4, nil, 0)
+
+	if len(flow.Delta) != 4 || flow.StreamBars != 0 {
+		t.Fatalf("flow has %d bars, %d from stream, want 4 and 0", len(flow.Delta), flow.StreamBars)
+	}
+	for i := range flow.Delta {
+		if flow.BuyVolume[i] != 600 || flow.SellVolume[i] != 400 || flow.Delta[i] != 200 {
+			t.Errorf("bar %d = buy %v sell %v delta %v, want 600/400/200", i, flow.BuyVolume[i], flow.SellVolume[i], flow.Delta[i])
+		}
+		if flow.CVD[i] != float64(200*(i+1)) {
+			t.Errorf("cvd[%d] = %v, want %v", i, flow.CVD[i], 200*(i+1))
+		}
+	}
+	if strings.Contains(FormatTakerFlow(flow), "Large trades") {
+		t.Error("large trades should not be shown without stream coverage")
+	}
+}
+
+// TestComputeTakerFlow_StreamCoverage tests that covered bars use stream buckets and coverage stops at a gap
+func TestComputeTakerFlow_StreamCoverage(t *testing.T) {
+	klines := flowKlines(4)
+
+	// Bars 2 and 3 fully seen (5 minutes each), bar 1 only has one minute of trades
+	var buckets []FlowBucket
+	buckets = append(buckets, FlowBucket{Start: 9 * aggTradeBucketMs, BuyVolume: 100, SellVolume: 100})
+	for m := int64(10); m < 20; m++ {
+		b := FlowBucket{Start: m * aggTradeBucketMs, BuyVolume: 100, SellVolume: 120}
+		if m == 17 {
+			b.LargeBuys = 2
+		}
+		buckets = append(buckets, b)
+	}
+
+	flow := ComputeTakerFlow(klines, 4, buckets, 5*aggTradeBucketMs)
+	if flow.StreamBars != 2 {
+		t.Fatalf("stream bars = %d, want 2", flow.StreamBars)
+	}
+	if flow.BuyVolume[2] != 500 || flow.SellVolume[2] != 600 || flow.LargeBuys[3] != 2 {
+		t.Errorf("stream bars = buy %v sell %v large %v, want 500/600/2", flow.BuyVolume[2], flow.SellVolume[2], flow.LargeBuys[3])
+	}
+	// Bar 1 saw 200 of 1000 quote volume, below the coverage threshold -> kline fallback
+	if flow.BuyVolume[1] != 600 || flow.SellVolume[1] != 400 {
+		t.Errorf("partially seen bar = buy %v sell %v, want kline 600/400", flow.BuyVolume[1], flow.SellVolume[1])
+	}
+	if math.Abs(flow.CVD[3]-(200+200-100-100)) > 1e-9 {
+		t.Errorf("cvd = %v, want 200", flow.CVD[3])
+	}
+
+	text := FormatTakerFlow(flow)
+	if !strings.Contains(text, "last 2 bars (buys/sells): [0/0, 2/0]") {
+		t.Errorf("unexpected format: %s", text)
+	}
+}
+
+// TestCalculateTimeframeSeries_TakerFlow tests that taker flow is added when enabled
+func TestCalculateTimeframeSeries_TakerFlow(t *testing.T) {
+	klines := flowKlines(20)
+	if data := BuildTimeframeSeries(klines, "5m", 10, SeriesOptions{}); data.TakerFlow != nil {
+		t.Error("taker flow should be nil when disabled")
+	}
+	data := BuildTimeframeSeries(klines, "5m", 10, SeriesOptions{TakerFlow: true})
+	if data.TakerFlow == nil || len(data.TakerFlow.CVD) != 10 {
+		t.Fatalf("taker flow = %+v, want 10 bars", data.TakerFlow)
+	}
+	if !strings.Contains(Format(&Data{TimeframeData: map[string]*TimeframeSeriesData{"5m": data}}), "CVD (USDT, from first bar): [") {
+		t.Error("formatted data should contain the CVD series")
+	}
+}
+
+// TestSubscribeAggTrades_Cleanup tests that failed and idle subscriptions release their subscriber
+func TestSubscribeAggTrades_Cleanup(t *testing.T) {
+	m := &WSMonitor{combinedClient: NewCombinedStreamsClient(10), aggTrades: NewAggTradeTracker()}
+	subscribers := func() int {
+		m.combinedClient.mu.RLock()
+		defer m.combinedClient.mu.RUnlock()
+		return len(m.combinedClient.subscribers)
+	}
+
+	// Not connected: the subscribe fails and nothing is left behind
+	if err := m.SubscribeAggTrades("btcusdt"); err == nil {
+		t.Fatal("expected an error without a connection")
+	}
+	if n := subscribers(); n != 0 {
+		t.Errorf("subscribers after failed subscribe = %d, want 0", n)
+	}
+	if _, ok := m.aggTradeSubs.Load("BTCUSDT"); ok {
+		t.Error("failed subscription still marked as subscribed")
+	}
+
+	// A symbol no cycle asked for within the TTL is dropped with its buckets
+	now := time.Now()
+	for symbol, lastUsed := range map[string]time.Time{"ETHUSDT": now.Add(-aggTradeIdleTTL), "SOLUSDT": now.Add(-time.Minute)} {
+		m.aggTradeSubs.Store(symbol, lastUsed)
+		m.combinedClient.AddSubscriber(aggTradeStream(symbol), 1)
+		m.aggTrades.AddTrade(symbol, AggTrade{Price: 10, Quantity: 1, Time: now.UnixMilli()})
+	}
+	m.pruneAggTrades(now)
+	if _, ok := m.aggTradeSubs.Load("ETHUSDT"); ok {
+		t.Error("idle ETHUSDT stream still subscribed")
+	}
+	if buckets, _ := m.aggTrades.Buckets("ETHUSDT"); len(buckets) != 0 {
+		t.Errorf("idle ETHUSDT buckets kept: %d", len(buckets))
+	}
+	if _, ok := m.aggTradeSubs.Load("SOLUSDT"); !ok {
+		t.Error("recently used SOLUSDT stream dropped")
+	}
+	if n := subscribers(); n != 1 {
+		t.Errorf("subscribers after prune = %d, want 1", n)
+	}
+}
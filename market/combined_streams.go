@@ -110,6 +110,25 @@ func (c *CombinedStreamsClient) subscribeStreams(streams []string) error {
 	return c.conn.WriteJSON(subscribeMsg)
 }
 
+// unsubscribeStreams unsubscribes from multiple streams
+func (c *CombinedStreamsClient) unsubscribeStreams(streams []string) error {
+	unsubscribeMsg := map[string]interface{}{
+		"method": "UNSUBSCRIBE",
+		"params": streams,
+		"id":     time.Now().UnixNano(),
+	}
+
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	if c.conn == nil {
+		return fmt.Errorf("WebSocket not connected")
+	}
+
+	log.Printf("Unsubscribing from streams: %v", streams)
+	return c.conn.WriteJSON(unsubscribeMsg)
+}
+
 func (c *CombinedStreamsClient) readMessages() {
 	for {
 		select {
@@ -148,11 +167,11 @@ func (c *CombinedStreamsClient) handleCombinedMessage(message []byte) {
 		return
 	}
 
+	// Send under the lock, RemoveSubscriber closes the channel
 	c.mu.RLock()
-	ch, exists := c.subscribers[combinedMsg.Stream]
-	c.mu.RUnlock()
+	defer c.mu.RUnlock()
 
-	if exists {
+	if ch, exists := c.subscribers[combinedMsg.Stream]; exists {
 		select {
 		case ch <- combinedMsg.Data:
 		default:
@@ -169,6 +188,17 @@ func (c *CombinedStreamsClient) AddSubscriber(stream string, bufferSize int) <-c
 	return ch
 }
 
+// RemoveSubscriber removes a stream's subscriber and closes its channel
+func (c *CombinedStreamsClient) RemoveSubscriber(stream string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if ch, ok := c.subscribers[stream]; ok {
+		close(ch)
+		delete(c.subscribers, stream)
+	}
+}
+
 func (c *CombinedStreamsClient) handleReconnect() {
 	if !c.reconnect {
 		return
@@ -6,6 +6,7 @@ import (
 	"io"
 	"nofx/logger"
 	"math"
+	"sort"
 	"strconv"
 	"strings"
 	"sync"
@@ -116,8 +117,20 @@ func Get(symbol string) (*Data, error) {
 // timeframes: list of timeframes, e.g. ["5m", "15m", "1h", "4h"]
 // primaryTimeframe: primary timeframe (used for calculating current indicators), defaults to timeframes[0]
 // count: number of K-lines for each timeframe
-func GetWithTimeframes(symbol string, timeframes []string, primaryTimeframe string, count int) (*Data, error) {
+// opts: registry indicators and expressions computed for each timeframe
+func GetWithTimeframes(symbol string, timeframes []string, primaryTimeframe string, count int, opts SeriesOptions) (*Data, error) {
+	return getWithTimeframes(symbol, timeframes, primaryTimeframe, count, false, opts)
+}
+
+// GetClosedWithTimeframes is like GetWithTimeframes but drops still-forming candles,
+// so every timeframe ends with a closed candle (same bar semantics as backtests)
+func GetClosedWithTimeframes(symbol string, timeframes []string, primaryTimeframe string, count int, opts SeriesOptions) (*Data, error) {
+	return getWithTimeframes(symbol, timeframes, primaryTimeframe, count, true, opts)
+}
+
+func getWithTimeframes(symbol string, timeframes []string, primaryTimeframe string, count int, closedOnly bool, opts SeriesOptions) (*Data, error) {
 	symbol = Normalize(symbol)
+	now := time.Now()
 
 	if len(timeframes) == 0 {
 		return nil, fmt.Errorf("at least one timeframe is required")
@@ -144,6 +157,16 @@ func GetWithTimeframes(symbol string, timeframes []string, primaryTimeframe stri
 	timeframeData := make(map[string]*TimeframeSeriesData)
 	var primaryKlines []Kline
 
+	// Taker flow from the aggTrade stream, bars before the subscription fall back to kline taker volume
+	var flowBuckets []FlowBucket
+	var flowSince int64
+	if opts.TakerFlow {
+		if err := WSMonitorCli.SubscribeAggTrades(symbol); err != nil {
+			logger.Infof("⚠️ Failed to subscribe to %s aggregate trades: %v", symbol, err)
+		}
+		flowBuckets, flowSince = WSMonitorCli.GetTakerFlowBuckets(symbol)
+	}
+
 	// Get K-line data for each timeframe
 	for _, tf := range timeframes {
 		klines, err := WSMonitorCli.GetCurrentKlines(symbol, tf)
@@ -151,6 +174,9 @@ func GetWithTimeframes(symbol string, timeframes []string, primaryTimeframe stri
 			logger.Infof("⚠️ Failed to get %s %s K-line: %v", symbol, tf, err)
 			continue
 		}
+		if closedOnly {
+			klines = dropUnclosedKline(klines, now)
+		}
 
 		if len(klines) == 0 {
 			logger.Infof("⚠️ %s %s K-line data is empty", symbol, tf)
@@ -163,7 +189,10 @@ func GetWithTimeframes(symbol string, timeframes []string, primaryTimeframe stri
 		}
 
 		// Calculate series data for this timeframe (use count from config)
-		seriesData := calculateTimeframeSeries(klines, tf, count)
+		seriesData := calculateTimeframeSeries(klines, tf, count, opts)
+		if opts.TakerFlow && len(flowBuckets) > 0 {
+			seriesData.TakerFlow = ComputeTakerFlow(klines, count, flowBuckets, flowSince)
+		}
 		timeframeData[tf] = seriesData
 	}
 
@@ -179,7 +208,8 @@ func GetWithTimeframes(symbol string, timeframes []string, primaryTimeframe stri
 	}
 
 	// Calculate current indicators (based on primary timeframe latest data)
-	currentPrice := primaryKlines[len(primaryKlines)-1].Close
+	lastKline := primaryKlines[len(primaryKlines)-1]
+	currentPrice := lastKline.Close
 	currentEMA20 := calculateEMA(primaryKlines, 20)
 	currentMACD := calculateMACD(primaryKlines)
 	currentRSI7 := calculateRSI(primaryKlines, 7)
@@ -198,21 +228,29 @@ func GetWithTimeframes(symbol string, timeframes []string, primaryTimeframe stri
 	fundingRate, _ := getFundingRate(symbol)
 
 	return &Data{
-		Symbol:        symbol,
-		CurrentPrice:  currentPrice,
-		PriceChange1h: priceChange1h,
-		PriceChange4h: priceChange4h,
-		CurrentEMA20:  currentEMA20,
-		CurrentMACD:   currentMACD,
-		CurrentRSI7:   currentRSI7,
-		OpenInterest:  oiData,
-		FundingRate:   fundingRate,
-		TimeframeData: timeframeData,
+		Symbol:             symbol,
+		CurrentPrice:       currentPrice,
+		PriceChange1h:      priceChange1h,
+		PriceChange4h:      priceChange4h,
+		CurrentEMA20:       currentEMA20,
+		CurrentMACD:        currentMACD,
+		CurrentRSI7:        currentRSI7,
+		OpenInterest:       oiData,
+		FundingRate:        fundingRate,
+		TimeframeData:      timeframeData,
+		LastKlineClosed:    IsKlineClosed(lastKline, now),
+		LastKlineCloseTime: lastKline.CloseTime,
 	}, nil
 }
 
+// BuildTimeframeSeries calculates series data for a single timeframe from preloaded klines
+// Backtests use it so their prompts contain the same series as live trading
+func BuildTimeframeSeries(klines []Kline, timeframe string, count int, opts SeriesOptions) *TimeframeSeriesData {
+	return calculateTimeframeSeries(klines, timeframe, count, opts)
+}
+
 // calculateTimeframeSeries calculates series data for a single timeframe
-func calculateTimeframeSeries(klines []Kline, timeframe string, count int) *TimeframeSeriesData {
+func calculateTimeframeSeries(klines []Kline, timeframe string, count int, opts SeriesOptions) *TimeframeSeriesData {
 	if count <= 0 {
 		count = 10 // default
 	}
@@ -282,6 +320,13 @@ func calculateTimeframeSeries(klines []Kline, timeframe string, count int) *Time
 	// Calculate ATR14
 	data.ATR14 = calculateATR(klines, 14)
 
+	// Registry indicators and expressions are computed over all klines, so warm-up uses the full history
+	data.Indicators = ComputeIndicatorSeries(klines, count, opts.Indicators)
+	data.Expressions = ComputeExpressionSeries(klines, count, opts.Expressions)
+	if opts.TakerFlow {
+		data.TakerFlow = ComputeTakerFlow(klines, count, nil, 0)
+	}
+
 	return data
 }
 
@@ -432,6 +477,11 @@ func calculateRSI(klines []Kline, period int) float64 {
 	return rsi
 }
 
+// CalculateATR calculates ATR of klines (0 when there are not enough klines)
+func CalculateATR(klines []Kline, period int) float64 {
+	return calculateATR(klines, period)
+}
+
 // calculateATR calculates ATR
 func calculateATR(klines []Kline, period int) float64 {
 	if len(klines) <= period {
@@ -596,6 +646,11 @@ func getOpenInterestData(symbol string) (*OIData, error) {
 	}, nil
 }
 
+// GetFundingRate retrieves current funding rate of a symbol (cached for 1 hour)
+func GetFundingRate(symbol string) (float64, error) {
+	return getFundingRate(symbol)
+}
+
 // getFundingRate retrieves funding rate (optimized: uses 1-hour cache)
 func getFundingRate(symbol string) (float64, error) {
 	// Check cache (1-hour validity)
@@ -785,6 +840,21 @@ func formatTimeframeData(sb *strings.Builder, data *TimeframeSeriesData) {
 		sb.WriteString(fmt.Sprintf("ATR14: %.4f\n", data.ATR14))
 	}
 
+	for _, series := range data.Indicators {
+		sb.WriteString(fmt.Sprintf("%s: %s\n", series.Label, formatFloatSlice(series.Values)))
+	}
+
+	names := make([]string, 0, len(data.Expressions))
+	for name := range data.Expressions {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		sb.WriteString(fmt.Sprintf("%s: %s\n", name, formatFloatSlice(data.Expressions[name])))
+	}
+
+	sb.WriteString(FormatTakerFlow(data.TakerFlow))
+
 	sb.WriteString("\n")
 }
 
@@ -875,6 +945,9 @@ func BuildDataFromKlines(symbol string, primary []Kline, longer []Kline) (*Data,
 		FundingRate:       0,
 		IntradaySeries:    calculateIntradaySeries(primary),
 		LongerTermContext: nil,
+		// Backtest series only contain bars closed at the decision time
+		LastKlineClosed:    true,
+		LastKlineCloseTime: current.CloseTime,
 	}
 
 	if len(longer) > 0 {
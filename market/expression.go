@@ -0,0 +1,631 @@
+package market
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// =============================================================================
+// Indicator Expressions
+// A small arithmetic language over kline series, e.g. "ema(close,9) - ema(close,21)"
+// There are no variables, loops or side effects, only series, numbers, operators and
+// whitelisted functions with constant periods, so user input is safe to evaluate
+// =============================================================================
+
+const (
+	maxExpressionLength = 256 // Max characters of an expression
+	maxExpressionNodes  = 64  // Max operands, operators and calls of an expression
+
+	// Max window of a function call; expressions are evaluated on the KlineCacheSize window,
+	// a longer period would never produce a value
+	maxExpressionPeriod = KlineCacheSize - 1
+)
+
+// ExpressionSpec a named user-defined series
+type ExpressionSpec struct {
+	Name string `json:"name"` // Series name shown in the prompt, e.g. "ema_spread"
+	Expr string `json:"expr"` // e.g. "ema(close,9) - ema(close,21)"
+}
+
+// Expression a compiled expression
+type Expression struct {
+	source string
+	root   exprNode
+}
+
+var expressionNameRe = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]{0,31}$`)
+
+// expressionSeries kline fields usable in expressions
+var expressionSeries = map[string]func(k Kline) float64{
+	"open":   func(k Kline) float64 { return k.Open },
+	"high":   func(k Kline) float64 { return k.High },
+	"low":    func(k Kline) float64 { return k.Low },
+	"close":  func(k Kline) float64 { return k.Close },
+	"volume": func(k Kline) float64 { return k.Volume },
+	"hl2":    func(k Kline) float64 { return (k.High + k.Low) / 2 },
+	"hlc3":   func(k Kline) float64 { return (k.High + k.Low + k.Close) / 3 },
+}
+
+// exprFunc a whitelisted function, args are series except trailing constant periods
+type exprFunc struct {
+	series  int // Number of series arguments
+	periods int // Number of trailing constant period arguments
+	eval    func(klines []Kline, args [][]float64, periods []int) []float64
+}
+
+var expressionFuncs = map[string]exprFunc{
+	"sma":     windowFunc(smaSeries),
+	"ema":     windowFunc(emaSeries),
+	"rsi":     windowFunc(rsiSeries),
+	"highest": windowFunc(highestSeries),
+	"lowest":  windowFunc(lowestSeries),
+	"sum":     windowFunc(sumSeries),
+	"stddev":  windowFunc(stddevSeries),
+	"zscore":  windowFunc(zscoreSeries),
+	"change": windowFunc(func(values []float64, n int) []float64 {
+		return shiftCombine(values, n, func(cur, prev float64) float64 { return cur - prev })
+	}),
+	"roc": windowFunc(func(values []float64, n int) []float64 {
+		return shiftCombine(values, n, func(cur, prev float64) float64 { return (cur/prev - 1) * 100 })
+	}),
+	"shift": windowFunc(func(values []float64, n int) []float64 {
+		return shiftCombine(values, n, func(_, prev float64) float64 { return prev })
+	}),
+	"atr": {periods: 1, eval: func(klines []Kline, _ [][]float64, periods []int) []float64 {
+		return atrSeries(klines, periods[0])
+	}},
+	"abs":  mathFunc(math.Abs),
+	"sqrt": mathFunc(math.Sqrt),
+	"log":  mathFunc(math.Log),
+	"min":  pairFunc(math.Min),
+	"max":  pairFunc(math.Max),
+}
+
+// CompileExpression parses an expression and checks functions, arguments and size limits
+func CompileExpression(source string) (*Expression, error) {
+	source = strings.TrimSpace(source)
+	if source == "" {
+		return nil, fmt.Errorf("expression is empty")
+	}
+	if len(source) > maxExpressionLength {
+		return nil, fmt.Errorf("expression is longer than %d characters", maxExpressionLength)
+	}
+
+	tokens, err := tokenizeExpression(source)
+	if err != nil {
+		return nil, err
+	}
+	p := &exprParser{tokens: tokens}
+	root, err := p.parseExpr()
+	if err != nil {
+		return nil, err
+	}
+	if tok := p.peek(); tok.kind != tokEOF {
+		return nil, fmt.Errorf("unexpected %q at position %d", tok.text, tok.pos)
+	}
+	if p.nodes > maxExpressionNodes {
+		return nil, fmt.Errorf("expression has more than %d terms", maxExpressionNodes)
+	}
+	return &Expression{source: source, root: root}, nil
+}
+
+// String returns the expression source
+func (e *Expression) String() string {
+	return e.source
+}
+
+// Evaluate computes the expression for every kline, NaN where it is undefined (warm-up, division by zero)
+func (e *Expression) Evaluate(klines []Kline) []float64 {
+	values := e.root.eval(klines)
+	for i, v := range values {
+		if math.IsInf(v, 0) {
+			values[i] = math.NaN()
+		}
+	}
+	return values
+}
+
+// ValidateExpressionSpecs checks expression names (unique identifiers) and compiles every expression
+func ValidateExpressionSpecs(specs []ExpressionSpec) error {
+	seen := make(map[string]bool, len(specs))
+	for _, spec := range specs {
+		if !expressionNameRe.MatchString(spec.Name) {
+			return fmt.Errorf("invalid expression name %q: use letters, digits and underscores (max 32)", spec.Name)
+		}
+		if seen[strings.ToLower(spec.Name)] {
+			return fmt.Errorf("duplicate expression name %q", spec.Name)
+		}
+		seen[strings.ToLower(spec.Name)] = true
+		if _, err := CompileExpression(spec.Expr); err != nil {
+			return fmt.Errorf("expression %s: %w", spec.Name, err)
+		}
+	}
+	return nil
+}
+
+// ComputeExpressionSeries evaluates each spec and keeps the values of the last count klines by name
+// Leading warm-up values are dropped, gaps inside the series repeat the previous value
+func ComputeExpressionSeries(klines []Kline, count int, specs []ExpressionSpec) map[string][]float64 {
+	if len(klines) == 0 || len(specs) == 0 {
+		return nil
+	}
+
+	result := make(map[string][]float64, len(specs))
+	for _, spec := range specs {
+		expr, err := CompileExpression(spec.Expr)
+		if err != nil {
+			continue // Specs are validated when the strategy is saved
+		}
+		if values := lastValues(expr.Evaluate(klines), count); len(values) > 0 {
+			result[spec.Name] = values
+		}
+	}
+	if len(result) == 0 {
+		return nil
+	}
+	return result
+}
+
+// lastValues keeps the last count values, drops leading NaN and fills later NaN with the previous value
+func lastValues(series []float64, count int) []float64 {
+	start := len(series) - count
+	if count <= 0 || start < 0 {
+		start = 0
+	}
+
+	values := make([]float64, 0, len(series)-start)
+	for _, v := range series[start:] {
+		if math.IsNaN(v) {
+			if len(values) == 0 {
+				continue
+			}
+			v = values[len(values)-1]
+		}
+		values = append(values, v)
+	}
+	return values
+}
+
+// =============================================================================
+// Evaluation
+// =============================================================================
+
+type exprNode interface {
+	eval(klines []Kline) []float64
+}
+
+type numberNode float64
+
+func (n numberNode) eval(klines []Kline) []float64 {
+	values := make([]float64, len(klines))
+	for i := range values {
+		values[i] = float64(n)
+	}
+	return values
+}
+
+type seriesNode string
+
+func (s seriesNode) eval(klines []Kline) []float64 {
+	field := expressionSeries[string(s)]
+	values := make([]float64, len(klines))
+	for i, k := range klines {
+		values[i] = field(k)
+	}
+	return values
+}
+
+type unaryNode struct {
+	operand exprNode
+}
+
+func (u unaryNode) eval(klines []Kline) []float64 {
+	values := u.operand.eval(klines)
+	for i := range values {
+		values[i] = -values[i]
+	}
+	return values
+}
+
+type binaryNode struct {
+	op          byte
+	left, right exprNode
+}
+
+func (b binaryNode) eval(klines []Kline) []float64 {
+	left := b.left.eval(klines)
+	right := b.right.eval(klines)
+	for i := range left {
+		switch b.op {
+		case '+':
+			left[i] += right[i]
+		case '-':
+			left[i] -= right[i]
+		case '*':
+			left[i] *= right[i]
+		case '/':
+			if right[i] == 0 {
+				left[i] = math.NaN()
+			} else {
+				left[i] /= right[i]
+			}
+		case '^':
+			left[i] = math.Pow(left[i], right[i])
+		}
+	}
+	return left
+}
+
+type callNode struct {
+	fn      exprFunc
+	args    []exprNode
+	periods []int
+}
+
+func (c callNode) eval(klines []Kline) []float64 {
+	args := make([][]float64, len(c.args))
+	for i, arg := range c.args {
+		args[i] = arg.eval(klines)
+	}
+	return c.fn.eval(klines, args, c.periods)
+}
+
+// windowFunc a function of one series and one period, applied after the source series' warm-up
+func windowFunc(f func(values []float64, period int) []float64) exprFunc {
+	return exprFunc{series: 1, periods: 1, eval: func(_ []Kline, args [][]float64, periods []int) []float64 {
+		values := args[0]
+		first := 0
+		for first < len(values) && math.IsNaN(values[first]) {
+			first++
+		}
+		out := nanSeries(len(values))
+		copy(out[first:], f(values[first:], periods[0]))
+		return out
+	}}
+}
+
+func mathFunc(f func(float64) float64) exprFunc {
+	return exprFunc{series: 1, eval: func(_ []Kline, args [][]float64, _ []int) []float64 {
+		values := args[0]
+		for i, v := range values {
+			values[i] = f(v)
+		}
+		return values
+	}}
+}
+
+func pairFunc(f func(a, b float64) float64) exprFunc {
+	return exprFunc{series: 2, eval: func(_ []Kline, args [][]float64, _ []int) []float64 {
+		values := args[0]
+		for i := range values {
+			if math.IsNaN(values[i]) || math.IsNaN(args[1][i]) {
+				values[i] = math.NaN()
+				continue
+			}
+			values[i] = f(values[i], args[1][i])
+		}
+		return values
+	}}
+}
+
+// rollingSeries applies f to each full window of period values
+func rollingSeries(values []float64, period int, f func(window []float64) float64) []float64 {
+	out := nanSeries(len(values))
+	for i := period - 1; i < len(values); i++ {
+		out[i] = f(values[i-period+1 : i+1])
+	}
+	return out
+}
+
+func highestSeries(values []float64, period int) []float64 {
+	return rollingSeries(values, period, func(w []float64) float64 {
+		high := math.Inf(-1)
+		for _, v := range w {
+			high = math.Max(high, v)
+		}
+		return high
+	})
+}
+
+func lowestSeries(values []float64, period int) []float64 {
+	return rollingSeries(values, period, func(w []float64) float64 {
+		low := math.Inf(1)
+		for _, v := range w {
+			low = math.Min(low, v)
+		}
+		return low
+	})
+}
+
+func sumSeries(values []float64, period int) []float64 {
+	return rollingSeries(values, period, func(w []float64) float64 {
+		sum := 0.0
+		for _, v := range w {
+			sum += v
+		}
+		return sum
+	})
+}
+
+// stddevSeries population standard deviation over period values
+func stddevSeries(values []float64, period int) []float64 {
+	return rollingSeries(values, period, func(w []float64) float64 {
+		_, sd := meanStddev(w)
+		return sd
+	})
+}
+
+// zscoreSeries (value - mean) / stddev over period values
+func zscoreSeries(values []float64, period int) []float64 {
+	return rollingSeries(values, period, func(w []float64) float64 {
+		mean, sd := meanStddev(w)
+		if sd == 0 {
+			return 0
+		}
+		return (w[len(w)-1] - mean) / sd
+	})
+}
+
+func meanStddev(values []float64) (float64, float64) {
+	mean := 0.0
+	for _, v := range values {
+		mean += v
+	}
+	mean /= float64(len(values))
+	variance := 0.0
+	for _, v := range values {
+		variance += (v - mean) * (v - mean)
+	}
+	return mean, math.Sqrt(variance / float64(len(values)))
+}
+
+// shiftCombine combines each value with the value n bars earlier
+func shiftCombine(values []float64, n int, f func(cur, prev float64) float64) []float64 {
+	out := nanSeries(len(values))
+	for i := n; i < len(values); i++ {
+		out[i] = f(values[i], values[i-n])
+	}
+	return out
+}
+
+// =============================================================================
+// Parsing
+// =============================================================================
+
+type tokenKind int
+
+const (
+	tokEOF tokenKind = iota
+	tokNumber
+	tokIdent
+	tokOp
+	tokLParen
+	tokRParen
+	tokComma
+)
+
+type exprToken struct {
+	kind tokenKind
+	text string
+	pos  int
+}
+
+func tokenizeExpression(source string) ([]exprToken, error) {
+	var tokens []exprToken
+	for i := 0; i < len(source); {
+		ch := source[i]
+		switch {
+		case ch == ' ' || ch == '\t' || ch == '\n':
+			i++
+		case ch >= '0' && ch <= '9' || ch == '.':
+			start := i
+			for i < len(source) && (source[i] >= '0' && source[i] <= '9' || source[i] == '.') {
+				i++
+			}
+			tokens = append(tokens, exprToken{tokNumber, source[start:i], start})
+		case ch == '_' || ch >= 'a' && ch <= 'z' || ch >= 'A' && ch <= 'Z':
+			start := i
+			for i < len(source) && (source[i] == '_' || source[i] >= 'a' && source[i] <= 'z' ||
+				source[i] >= 'A' && source[i] <= 'Z' || source[i] >= '0' && source[i] <= '9') {
+				i++
+			}
+			tokens = append(tokens, exprToken{tokIdent, strings.ToLower(source[start:i]), start})
+		case strings.IndexByte("+-*/^", ch) >= 0:
+			tokens = append(tokens, exprToken{tokOp, string(ch), i})
+			i++
+		case ch == '(':
+			tokens = append(tokens, exprToken{tokLParen, "(", i})
+			i++
+		case ch == ')':
+			tokens = append(tokens, exprToken{tokRParen, ")", i})
+			i++
+		case ch == ',':
+			tokens = append(tokens, exprToken{tokComma, ",", i})
+			i++
+		default:
+			return nil, fmt.Errorf("unexpected character %q at position %d", ch, i)
+		}
+	}
+	return append(tokens, exprToken{kind: tokEOF, text: "end of expression", pos: len(source)}), nil
+}
+
+// exprParser recursive descent parser
+//
+//	expr   = term { ("+" | "-") term }
+//	term   = unary { ("*" | "/") unary }
+//	unary  = "-" unary | power
+//	power  = atom [ "^" unary ]
+//	atom   = number | series | func "(" args ")" | "(" expr ")"
+type exprParser struct {
+	tokens []exprToken
+	pos    int
+	nodes  int
+	depth  int
+}
+
+func (p *exprParser) peek() exprToken {
+	return p.tokens[p.pos]
+}
+
+func (p *exprParser) next() exprToken {
+	tok := p.tokens[p.pos]
+	if tok.kind != tokEOF {
+		p.pos++
+	}
+	return tok
+}
+
+func (p *exprParser) expect(kind tokenKind, what string) error {
+	if tok := p.next(); tok.kind != kind {
+		return fmt.Errorf("expected %s at position %d, got %q", what, tok.pos, tok.text)
+	}
+	return nil
+}
+
+func (p *exprParser) parseExpr() (exprNode, error) {
+	p.depth++
+	defer func() { p.depth-- }()
+	if p.depth > maxExpressionNodes {
+		return nil, fmt.Errorf("expression is nested too deeply")
+	}
+
+	left, err := p.parseTerm()
+	if err != nil {
+		return nil, err
+	}
+	for tok := p.peek(); tok.kind == tokOp && (tok.text == "+" || tok.text == "-"); tok = p.peek() {
+		p.next()
+		right, err := p.parseTerm()
+		if err != nil {
+			return nil, err
+		}
+		p.nodes++
+		left = binaryNode{op: tok.text[0], left: left, right: right}
+	}
+	return left, nil
+}
+
+func (p *exprParser) parseTerm() (exprNode, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	for tok := p.peek(); tok.kind == tokOp && (tok.text == "*" || tok.text == "/"); tok = p.peek() {
+		p.next()
+		right, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		p.nodes++
+		left = binaryNode{op: tok.text[0], left: left, right: right}
+	}
+	return left, nil
+}
+
+func (p *exprParser) parseUnary() (exprNode, error) {
+	if tok := p.peek(); tok.kind == tokOp && tok.text == "-" {
+		p.next()
+		p.depth++
+		defer func() { p.depth-- }()
+		if p.depth > maxExpressionNodes {
+			return nil, fmt.Errorf("expression is nested too deeply")
+		}
+		operand, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		p.nodes++
+		return unaryNode{operand: operand}, nil
+	}
+
+	base, err := p.parseAtom()
+	if err != nil {
+		return nil, err
+	}
+	if tok := p.peek(); tok.kind == tokOp && tok.text == "^" {
+		p.next()
+		exponent, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		p.nodes++
+		return binaryNode{op: '^', left: base, right: exponent}, nil
+	}
+	return base, nil
+}
+
+func (p *exprParser) parseAtom() (exprNode, error) {
+	tok := p.next()
+	p.nodes++
+	switch tok.kind {
+	case tokNumber:
+		v, err := strconv.ParseFloat(tok.text, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid number %q at position %d", tok.text, tok.pos)
+		}
+		return numberNode(v), nil
+	case tokLParen:
+		node, err := p.parseExpr()
+		if err != nil {
+			return nil, err
+		}
+		if err := p.expect(tokRParen, "\")\""); err != nil {
+			return nil, err
+		}
+		return node, nil
+	case tokIdent:
+		if p.peek().kind == tokLParen {
+			return p.parseCall(tok)
+		}
+		if _, ok := expressionSeries[tok.text]; ok {
+			return seriesNode(tok.text), nil
+		}
+		if _, ok := expressionFuncs[tok.text]; ok {
+			return nil, fmt.Errorf("function %s at position %d needs arguments", tok.text, tok.pos)
+		}
+		return nil, fmt.Errorf("unknown series %q at position %d (use open, high, low, close, volume, hl2, hlc3)", tok.text, tok.pos)
+	default:
+		return nil, fmt.Errorf("unexpected %q at position %d", tok.text, tok.pos)
+	}
+}
+
+func (p *exprParser) parseCall(name exprToken) (exprNode, error) {
+	fn, ok := expressionFuncs[name.text]
+	if !ok {
+		return nil, fmt.Errorf("unknown function %q at position %d", name.text, name.pos)
+	}
+	p.next() // "("
+
+	call := callNode{fn: fn}
+	total := fn.series + fn.periods
+	for i := 0; i < total; i++ {
+		if i > 0 {
+			if err := p.expect(tokComma, "\",\""); err != nil {
+				return nil, fmt.Errorf("%s takes %d arguments: %w", name.text, total, err)
+			}
+		}
+		if i < fn.series {
+			arg, err := p.parseExpr()
+			if err != nil {
+				return nil, err
+			}
+			call.args = append(call.args, arg)
+			continue
+		}
+
+		// Periods must be constant so the lookback is bounded
+		tok := p.next()
+		n, err := strconv.Atoi(tok.text)
+		if tok.kind != tokNumber || err != nil || n < 1 || n > maxExpressionPeriod {
+			return nil, fmt.Errorf("%s period at position %d must be an integer between 1 and %d", name.text, tok.pos, maxExpressionPeriod)
+		}
+		call.periods = append(call.periods, n)
+	}
+	if err := p.expect(tokRParen, "\")\""); err != nil {
+		return nil, fmt.Errorf("%s takes %d arguments: %w", name.text, total, err)
+	}
+	return call, nil
+}
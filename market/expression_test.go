@@ -0,0 +1,172 @@
+package market
+
+import (
+	"math"
+	"strings"
+	"testing"
+)
+
+// TestExpression_MatchesIndicators tests that expression functions match the indicator implementations
+func TestExpression_MatchesIndicators(t *testing.T) {
+	klines := trendingKlines(120)
+
+	expr, err := CompileExpression("ema(close, 9) - ema(close, 21)")
+	if err != nil {
+		t.Fatalf("compile error: %v", err)
+	}
+	got := expr.Evaluate(klines)
+	fast := emaSeries(closes(klines), 9)
+	slow := emaSeries(closes(klines), 21)
+
+	if !math.IsNaN(got[19]) {
+		t.Errorf("value before ema21 warm-up = %v, want NaN", got[19])
+	}
+	for i := 20; i < len(klines); i++ {
+		if math.Abs(got[i]-(fast[i]-slow[i])) > 1e-9 {
+			t.Fatalf("value at %d = %v, want %v", i, got[i], fast[i]-slow[i])
+		}
+	}
+}
+
+// TestExpression_Functions tests window functions, precedence and division by zero
+func TestExpression_Functions(t *testing.T) {
+	klines := make([]Kline, 5)
+	for i, c := range []float64{1, 2, 3, 4, 5} {
+		klines[i] = Kline{Open: c, High: c + 1, Low: c - 1, Close: c, Volume: c * 10}
+	}
+
+	tests := []struct {
+		expr string
+		want float64 // Value at the last kline
+	}{
+		{"close / highest(high, 3)", 5.0 / 6},
+		{"lowest(low, 5)", 0},
+		{"sma(close, 5)", 3},
+		{"zscore(volume, 5)", 20 / (10 * math.Sqrt(2))},
+		{"change(close, 2)", 2},
+		{"shift(close, 4)", 1},
+		{"roc(close, 4)", 400},
+		{"sum(volume, 2)", 90},
+		{"1 + 2 * 3 ^ 2", 19},
+		{"-close + max(open, 10)", 5},
+		{"(close - 5) / (close - 5)", math.NaN()},
+		{"abs(1 - close)", 4},
+	}
+
+	for _, tt := range tests {
+		expr, err := CompileExpression(tt.expr)
+		if err != nil {
+			t.Fatalf("%s: compile error: %v", tt.expr, err)
+		}
+		values := expr.Evaluate(klines)
+		got := values[len(values)-1]
+		if math.IsNaN(tt.want) {
+			if !math.IsNaN(got) {
+				t.Errorf("%s = %v, want NaN", tt.expr, got)
+			}
+			continue
+		}
+		if math.Abs(got-tt.want) > 1e-9 {
+			t.Errorf("%s = %v, want %v", tt.expr, got, tt.want)
+		}
+	}
+}
+
+// TestCompileExpression_Errors tests that invalid or unsafe expressions are rejected
+func TestCompileExpression_Errors(t *testing.T) {
+	tests := []struct {
+		expr    string
+		wantErr string
+	}{
+		{"", "empty"},
+		{"close +", "unexpected"},
+		{"foo(close, 3)", "unknown function"},
+		{"price * 2", "unknown series"},
+		{"ema(close)", "takes 2 arguments"},
+		{"ema(close, 3, 4)", "takes 2 arguments"},
+		{"ema(close, volume)", "must be an integer"},
+		{"ema(close, 0)", "must be an integer"},
+		{"sma(close, 5000)", "must be an integer"},
+		{"ema(close, 200)", "must be an integer between 1 and 99"},
+		{"close; drop", "unexpected character"},
+		{"(close", "expected \")\""},
+		{strings.Repeat("close+", 50) + "close", "longer than"},
+		{strings.Repeat("1+", 70) + "1", "more than"},
+		{strings.Repeat("(", 70) + "1" + strings.Repeat(")", 70), "nested too deeply"},
+	}
+
+	for _, tt := range tests {
+		_, err := CompileExpression(tt.expr)
+		if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+			t.Errorf("CompileExpression(%q) error = %v, want containing %q", tt.expr, err, tt.wantErr)
+		}
+	}
+}
+
+// TestValidateExpressionSpecs tests name validation
+func TestValidateExpressionSpecs(t *testing.T) {
+	valid := []ExpressionSpec{{Name: "ema_spread", Expr: "ema(close,9) - ema(close,21)"}}
+	if err := ValidateExpressionSpecs(valid); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+
+	invalid := [][]ExpressionSpec{
+		{{Name: "bad name", Expr: "close"}},
+		{{Name: "a", Expr: "close"}, {Name: "A", Expr: "open"}},
+		{{Name: "broken", Expr: "close * "}},
+	}
+	for _, specs := range invalid {
+		if err := ValidateExpressionSpecs(specs); err == nil {
+			t.Errorf("expected error for %+v", specs)
+		}
+	}
+}
+
+// TestCalculateTimeframeSeries_Expressions tests that expressions are stored by name in the timeframe series
+func TestCalculateTimeframeSeries_Expressions(t *testing.T) {
+	klines := trendingKlines(100)
+	opts := SeriesOptions{Expressions: []ExpressionSpec{
+		{Name: "range_pos", Expr: "close / highest(high, 50)"},
+		{Name: "vol_z", Expr: "zscore(volume, 20)"},
+	}}
+
+	data := BuildTimeframeSeries(klines, "1h", 30, opts)
+	if len(data.Expressions) != 2 {
+		t.Fatalf("expressions = %d, want 2", len(data.Expressions))
+	}
+	rangePos := data.Expressions["range_pos"]
+	if len(rangePos) != 30 {
+		t.Fatalf("range_pos has %d values, want 30", len(rangePos))
+	}
+	want := klines[99].Close / highestSeries(highsOf(klines), 50)[99]
+	if math.Abs(rangePos[29]-want) > 1e-9 {
+		t.Errorf("range_pos latest = %v, want %v", rangePos[29], want)
+	}
+
+	if !strings.Contains(Format(&Data{TimeframeData: map[string]*TimeframeSeriesData{"1h": data}}), "vol_z: [") {
+		t.Error("formatted data should contain the expression series")
+	}
+}
+
+// TestLastValues tests trimming of warm-up values and filling of gaps
+func TestLastValues(t *testing.T) {
+	nan := math.NaN()
+	got := lastValues([]float64{nan, nan, 1, nan, 3}, 4)
+	want := []float64{1, 1, 3}
+	if len(got) != len(want) {
+		t.Fatalf("lastValues = %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("lastValues = %v, want %v", got, want)
+		}
+	}
+}
+
+func highsOf(klines []Kline) []float64 {
+	values := make([]float64, len(klines))
+	for i, k := range klines {
+		values[i] = k.High
+	}
+	return values
+}
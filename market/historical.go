@@ -77,15 +77,21 @@ func GetKlinesRange(symbol string, timeframe string, start, end time.Time) ([]Kl
 			close, _ := parseFloat(item[4])
 			volume, _ := parseFloat(item[5])
 			closeTime := int64(item[6].(float64))
+			quoteVolume, _ := parseFloat(item[7])
+			takerBuyBase, _ := parseFloat(item[9])
+			takerBuyQuote, _ := parseFloat(item[10])
 
 			batch[i] = Kline{
-				OpenTime:  openTime,
-				Open:      open,
-				High:      high,
-				Low:       low,
-				Close:     close,
-				Volume:    volume,
-				CloseTime: closeTime,
+				OpenTime:            openTime,
+				Open:                open,
+				High:                high,
+				Low:                 low,
+				Close:               close,
+				Volume:              volume,
+				CloseTime:           closeTime,
+				QuoteVolume:         quoteVolume,
+				TakerBuyBaseVolume:  takerBuyBase,
+				TakerBuyQuoteVolume: takerBuyQuote,
 			}
 		}
 
@@ -0,0 +1,881 @@
+package market
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// =============================================================================
+// Indicator Registry
+// Indicators are looked up by name and built with per-strategy parameters, the same
+// implementation computes the series for live prompts and for backtests
+// =============================================================================
+
+// IndicatorSpec selects a registered indicator and its parameters
+type IndicatorSpec struct {
+	Name   string             `json:"name"`             // Registered indicator name, e.g. "bollinger"
+	Params map[string]float64 `json:"params,omitempty"` // Missing params use the indicator defaults
+}
+
+// Indicator computes a technical indicator series from klines
+// Compute returns one value per kline, NaN while the indicator is warming up
+type Indicator interface {
+	Name() string
+	Params() map[string]float64
+	Compute(klines []Kline) []float64
+}
+
+// MultiLineIndicator is implemented by indicators with several output lines (bands, channels, clouds)
+// Compute returns the main line, ComputeLines returns all lines
+type MultiLineIndicator interface {
+	Indicator
+	ComputeLines(klines []Kline) []IndicatorLine
+}
+
+// IndicatorLine a named output line of an indicator
+type IndicatorLine struct {
+	Name   string
+	Values []float64
+}
+
+// IndicatorParam a tunable indicator parameter
+type IndicatorParam struct {
+	Name    string  `json:"name"`
+	Default float64 `json:"default"`
+	Min     float64 `json:"min"`
+	Max     float64 `json:"max"`
+}
+
+// IndicatorDefinition describes a registered indicator
+type IndicatorDefinition struct {
+	Name        string           `json:"name"`
+	Description string           `json:"description"`
+	Params      []IndicatorParam `json:"params"`
+	Lines       []string         `json:"lines,omitempty"` // Output lines of multi-line indicators
+}
+
+// IndicatorSeries a computed indicator line, ready for prompts
+type IndicatorSeries struct {
+	Label  string    `json:"label"`  // e.g. "BOLLINGER_UPPER(20,2)"
+	Values []float64 `json:"values"` // Oldest → latest, aligned with the last klines
+}
+
+type registeredIndicator struct {
+	def   IndicatorDefinition
+	build func(params map[string]float64) Indicator
+}
+
+var (
+	indicatorRegistry   = make(map[string]registeredIndicator)
+	indicatorRegistryMu sync.RWMutex
+)
+
+// RegisterIndicator adds an indicator to the registry, replacing any indicator with the same name
+func RegisterIndicator(def IndicatorDefinition, build func(params map[string]float64) Indicator) {
+	indicatorRegistryMu.Lock()
+	defer indicatorRegistryMu.Unlock()
+	indicatorRegistry[strings.ToLower(def.Name)] = registeredIndicator{def: def, build: build}
+}
+
+// ListIndicators returns the registered indicators sorted by name
+func ListIndicators() []IndicatorDefinition {
+	indicatorRegistryMu.RLock()
+	defer indicatorRegistryMu.RUnlock()
+
+	defs := make([]IndicatorDefinition, 0, len(indicatorRegistry))
+	for _, reg := range indicatorRegistry {
+		defs = append(defs, reg.def)
+	}
+	sort.Slice(defs, func(i, j int) bool { return defs[i].Name < defs[j].Name })
+	return defs
+}
+
+// NewIndicator builds an indicator from a spec, filling in defaults and validating parameter ranges
+func NewIndicator(spec IndicatorSpec) (Indicator, error) {
+	indicatorRegistryMu.RLock()
+	reg, ok := indicatorRegistry[strings.ToLower(strings.TrimSpace(spec.Name))]
+	indicatorRegistryMu.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("unknown indicator: %s", spec.Name)
+	}
+
+	params := make(map[string]float64, len(reg.def.Params))
+	for _, p := range reg.def.Params {
+		v, set := spec.Params[p.Name]
+		if !set {
+			v = p.Default
+		}
+		if v < p.Min || v > p.Max {
+			return nil, fmt.Errorf("indicator %s: %s must be between %g and %g", reg.def.Name, p.Name, p.Min, p.Max)
+		}
+		params[p.Name] = v
+	}
+	for name := range spec.Params {
+		if _, known := params[name]; !known {
+			return nil, fmt.Errorf("indicator %s: unknown parameter %s", reg.def.Name, name)
+		}
+	}
+
+	return reg.build(params), nil
+}
+
+// ValidateIndicatorSpecs checks that every spec names a registered indicator with valid parameters
+func ValidateIndicatorSpecs(specs []IndicatorSpec) error {
+	for _, spec := range specs {
+		if _, err := NewIndicator(spec); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ComputeIndicatorSeries computes the lines of each spec and keeps the values of the last count klines
+// Warm-up values are dropped, so a series can be shorter than count
+func ComputeIndicatorSeries(klines []Kline, count int, specs []IndicatorSpec) []IndicatorSeries {
+	if len(klines) == 0 || len(specs) == 0 {
+		return nil
+	}
+	start := len(klines) - count
+	if count <= 0 || start < 0 {
+		start = 0
+	}
+
+	var result []IndicatorSeries
+	for _, spec := range specs {
+		ind, err := NewIndicator(spec)
+		if err != nil {
+			continue // Specs are validated when the strategy is saved
+		}
+
+		lines := []IndicatorLine{{Values: ind.Compute(klines)}}
+		if multi, ok := ind.(MultiLineIndicator); ok {
+			lines = multi.ComputeLines(klines)
+		}
+
+		for _, line := range lines {
+			values := make([]float64, 0, len(klines)-start)
+			for _, v := range line.Values[start:] {
+				if math.IsNaN(v) {
+					continue
+				}
+				values = append(values, v)
+			}
+			if len(values) == 0 {
+				continue
+			}
+			result = append(result, IndicatorSeries{Label: indicatorLabel(ind, line.Name), Values: values})
+		}
+	}
+	return result
+}
+
+// DescribeIndicator returns the prompt label and description of a spec, e.g. "BOLLINGER(20,2): Bollinger bands..."
+func DescribeIndicator(spec IndicatorSpec) (string, error) {
+	ind, err := NewIndicator(spec)
+	if err != nil {
+		return "", err
+	}
+
+	indicatorRegistryMu.RLock()
+	def := indicatorRegistry[strings.ToLower(ind.Name())].def
+	indicatorRegistryMu.RUnlock()
+
+	desc := indicatorLabel(ind, "") + ": " + def.Description
+	if len(def.Lines) > 0 {
+		desc += " (lines: " + strings.Join(def.Lines, ", ") + ")"
+	}
+	return desc, nil
+}
+
+// indicatorLabel formats the prompt label of an indicator line, e.g. "BOLLINGER_UPPER(20,2)"
+func indicatorLabel(ind Indicator, line string) string {
+	label := strings.ToUpper(ind.Name())
+	if line != "" {
+		label += "_" + strings.ToUpper(line)
+	}
+
+	indicatorRegistryMu.RLock()
+	reg := indicatorRegistry[strings.ToLower(ind.Name())]
+	indicatorRegistryMu.RUnlock()
+
+	params := ind.Params()
+	if len(reg.def.Params) == 0 {
+		return label
+	}
+	values := make([]string, 0, len(reg.def.Params))
+	for _, p := range reg.def.Params {
+		values = append(values, strconv.FormatFloat(params[p.Name], 'f', -1, 64))
+	}
+	return label + "(" + strings.Join(values, ",") + ")"
+}
+
+// indicatorBase shared name/params handling of the built-in indicators
+type indicatorBase struct {
+	name   string
+	params map[string]float64
+}
+
+func (b indicatorBase) Name() string { return b.name }
+
+func (b indicatorBase) Params() map[string]float64 {
+	params := make(map[string]float64, len(b.params))
+	for k, v := range b.params {
+		params[k] = v
+	}
+	return params
+}
+
+// period reads an integer parameter
+func (b indicatorBase) period(name string) int {
+	return int(b.params[name])
+}
+
+// lineIndicator a multi-line indicator whose main line is the first line
+type lineIndicator struct {
+	indicatorBase
+	compute func(klines []Kline) []IndicatorLine
+}
+
+func (l lineIndicator) Compute(klines []Kline) []float64 {
+	return l.compute(klines)[0].Values
+}
+
+func (l lineIndicator) ComputeLines(klines []Kline) []IndicatorLine {
+	return l.compute(klines)
+}
+
+// singleIndicator an indicator with one output line
+type singleIndicator struct {
+	indicatorBase
+	compute func(klines []Kline) []float64
+}
+
+func (s singleIndicator) Compute(klines []Kline) []float64 {
+	return s.compute(klines)
+}
+
+func init() {
+	RegisterIndicator(IndicatorDefinition{
+		Name:        "ema",
+		Description: "Exponential moving average of close",
+		Params:      []IndicatorParam{{Name: "period", Default: 20, Min: 1, Max: 500}},
+	}, func(p map[string]float64) Indicator {
+		b := indicatorBase{name: "ema", params: p}
+		return singleIndicator{b, func(k []Kline) []float64 { return emaSeries(closes(k), b.period("period")) }}
+	})
+
+	RegisterIndicator(IndicatorDefinition{
+		Name:        "rsi",
+		Description: "Relative strength index (Wilder)",
+		Params:      []IndicatorParam{{Name: "period", Default: 14, Min: 2, Max: 200}},
+	}, func(p map[string]float64) Indicator {
+		b := indicatorBase{name: "rsi", params: p}
+		return singleIndicator{b, func(k []Kline) []float64 { return rsiSeries(closes(k), b.period("period")) }}
+	})
+
+	RegisterIndicator(IndicatorDefinition{
+		Name:        "macd",
+		Description: "Moving average convergence divergence",
+		Params: []IndicatorParam{
+			{Name: "fast", Default: 12, Min: 1, Max: 200},
+			{Name: "slow", Default: 26, Min: 2, Max: 400},
+			{Name: "signal", Default: 9, Min: 1, Max: 200},
+		},
+		Lines: []string{"macd", "signal", "histogram"},
+	}, func(p map[string]float64) Indicator {
+		b := indicatorBase{name: "macd", params: p}
+		return lineIndicator{b, func(k []Kline) []IndicatorLine {
+			return macdLines(closes(k), b.period("fast"), b.period("slow"), b.period("signal"))
+		}}
+	})
+
+	RegisterIndicator(IndicatorDefinition{
+		Name:        "atr",
+		Description: "Average true range (Wilder)",
+		Params:      []IndicatorParam{{Name: "period", Default: 14, Min: 1, Max: 200}},
+	}, func(p map[string]float64) Indicator {
+		b := indicatorBase{name: "atr", params: p}
+		return singleIndicator{b, func(k []Kline) []float64 { return atrSeries(k, b.period("period")) }}
+	})
+
+	RegisterIndicator(IndicatorDefinition{
+		Name:        "bollinger",
+		Description: "Bollinger bands: SMA of close ± stddev multiples",
+		Params: []IndicatorParam{
+			{Name: "period", Default: 20, Min: 2, Max: 500},
+			{Name: "stddev", Default: 2, Min: 0.1, Max: 10},
+		},
+		Lines: []string{"upper", "middle", "lower"},
+	}, func(p map[string]float64) Indicator {
+		b := indicatorBase{name: "bollinger", params: p}
+		return lineIndicator{b, func(k []Kline) []IndicatorLine {
+			return bollingerLines(closes(k), b.period("period"), b.params["stddev"])
+		}}
+	})
+
+	RegisterIndicator(IndicatorDefinition{
+		Name:        "vwap",
+		Description: "Volume weighted average price, anchored at UTC midnight (period 0) or rolling over period bars",
+		Params:      []IndicatorParam{{Name: "period", Default: 0, Min: 0, Max: 1000}},
+	}, func(p map[string]float64) Indicator {
+		b := indicatorBase{name: "vwap", params: p}
+		return singleIndicator{b, func(k []Kline) []float64 { return vwapSeries(k, b.period("period")) }}
+	})
+
+	RegisterIndicator(IndicatorDefinition{
+		Name:        "stoch_rsi",
+		Description: "Stochastic RSI %K and %D (0-100)",
+		Params: []IndicatorParam{
+			{Name: "rsi_period", Default: 14, Min: 2, Max: 200},
+			{Name: "stoch_period", Default: 14, Min: 2, Max: 200},
+			{Name: "k", Default: 3, Min: 1, Max: 50},
+			{Name: "d", Default: 3, Min: 1, Max: 50},
+		},
+		Lines: []string{"k", "d"},
+	}, func(p map[string]float64) Indicator {
+		b := indicatorBase{name: "stoch_rsi", params: p}
+		return lineIndicator{b, func(k []Kline) []IndicatorLine {
+			return stochRSILines(closes(k), b.period("rsi_period"), b.period("stoch_period"), b.period("k"), b.period("d"))
+		}}
+	})
+
+	RegisterIndicator(IndicatorDefinition{
+		Name:        "adx",
+		Description: "Average directional index with +DI/-DI (Wilder)",
+		Params:      []IndicatorParam{{Name: "period", Default: 14, Min: 2, Max: 200}},
+		Lines:       []string{"adx", "plus_di", "minus_di"},
+	}, func(p map[string]float64) Indicator {
+		b := indicatorBase{name: "adx", params: p}
+		return lineIndicator{b, func(k []Kline) []IndicatorLine { return adxLines(k, b.period("period")) }}
+	})
+
+	RegisterIndicator(IndicatorDefinition{
+		Name:        "supertrend",
+		Description: "SuperTrend line and direction (1 = up, -1 = down)",
+		Params: []IndicatorParam{
+			{Name: "period", Default: 10, Min: 1, Max: 200},
+			{Name: "multiplier", Default: 3, Min: 0.1, Max: 20},
+		},
+		Lines: []string{"line", "direction"},
+	}, func(p map[string]float64) Indicator {
+		b := indicatorBase{name: "supertrend", params: p}
+		return lineIndicator{b, func(k []Kline) []IndicatorLine {
+			return supertrendLines(k, b.period("period"), b.params["multiplier"])
+		}}
+	})
+
+	RegisterIndicator(IndicatorDefinition{
+		Name:        "ichimoku",
+		Description: "Ichimoku cloud: conversion, base and leading spans as plotted at each bar (no lagging span, it looks ahead)",
+		Params: []IndicatorParam{
+			{Name: "conversion", Default: 9, Min: 1, Max: 200},
+			{Name: "base", Default: 26, Min: 1, Max: 200},
+			{Name: "span_b", Default: 52, Min: 1, Max: 400},
+			{Name: "displacement", Default: 26, Min: 0, Max: 200},
+		},
+		Lines: []string{"conversion", "base", "span_a", "span_b"},
+	}, func(p map[string]float64) Indicator {
+		b := indicatorBase{name: "ichimoku", params: p}
+		return lineIndicator{b, func(k []Kline) []IndicatorLine {
+			return ichimokuLines(k, b.period("conversion"), b.period("base"), b.period("span_b"), b.period("displacement"))
+		}}
+	})
+
+	RegisterIndicator(IndicatorDefinition{
+		Name:        "obv",
+		Description: "On-balance volume",
+	}, func(p map[string]float64) Indicator {
+		return singleIndicator{indicatorBase{name: "obv", params: p}, obvSeries}
+	})
+
+	RegisterIndicator(IndicatorDefinition{
+		Name:        "donchian",
+		Description: "Donchian channel: highest high / lowest low over period bars",
+		Params:      []IndicatorParam{{Name: "period", Default: 20, Min: 1, Max: 500}},
+		Lines:       []string{"upper", "middle", "lower"},
+	}, func(p map[string]float64) Indicator {
+		b := indicatorBase{name: "donchian", params: p}
+		return lineIndicator{b, func(k []Kline) []IndicatorLine { return donchianLines(k, b.period("period")) }}
+	})
+}
+
+// =============================================================================
+// Series implementations (full length, NaN during warm-up)
+// =============================================================================
+
+func closes(klines []Kline) []float64 {
+	values := make([]float64, len(klines))
+	for i, k := range klines {
+		values[i] = k.Close
+	}
+	return values
+}
+
+func nanSeries(n int) []float64 {
+	values := make([]float64, n)
+	for i := range values {
+		values[i] = math.NaN()
+	}
+	return values
+}
+
+// emaSeries EMA seeded with the SMA of the first period values (same as calculateEMA)
+// NaN inputs (warm-up of a source series) are skipped
+func emaSeries(values []float64, period int) []float64 {
+	out := nanSeries(len(values))
+	if period <= 0 {
+		return out
+	}
+
+	first := 0
+	for first < len(values) && math.IsNaN(values[first]) {
+		first++
+	}
+	if len(values)-first < period {
+		return out
+	}
+
+	sum := 0.0
+	for i := first; i < first+period; i++ {
+		sum += values[i]
+	}
+	ema := sum / float64(period)
+	out[first+period-1] = ema
+
+	multiplier := 2.0 / float64(period+1)
+	for i := first + period; i < len(values); i++ {
+		ema = (values[i]-ema)*multiplier + ema
+		out[i] = ema
+	}
+	return out
+}
+
+// smaSeries simple moving average, NaN inputs are skipped like emaSeries
+func smaSeries(values []float64, period int) []float64 {
+	out := nanSeries(len(values))
+	if period <= 0 {
+		return out
+	}
+
+	first := 0
+	for first < len(values) && math.IsNaN(values[first]) {
+		first++
+	}
+	sum := 0.0
+	for i := first; i < len(values); i++ {
+		sum += values[i]
+		if i-first >= period {
+			sum -= values[i-period]
+		}
+		if i-first >= period-1 {
+			out[i] = sum / float64(period)
+		}
+	}
+	return out
+}
+
+// rsiSeries RSI with Wilder smoothing (same as calculateRSI)
+func rsiSeries(values []float64, period int) []float64 {
+	out := nanSeries(len(values))
+	if period <= 0 || len(values) <= period {
+		return out
+	}
+
+	gains, losses := 0.0, 0.0
+	for i := 1; i <= period; i++ {
+		change := values[i] - values[i-1]
+		if change > 0 {
+			gains += change
+		} else {
+			losses -= change
+		}
+	}
+	avgGain := gains / float64(period)
+	avgLoss := losses / float64(period)
+	out[period] = rsiValue(avgGain, avgLoss)
+
+	for i := period + 1; i < len(values); i++ {
+		change := values[i] - values[i-1]
+		gain, loss := 0.0, 0.0
+		if change > 0 {
+			gain = change
+		} else {
+			loss = -change
+		}
+		avgGain = (avgGain*float64(period-1) + gain) / float64(period)
+		avgLoss = (avgLoss*float64(period-1) + loss) / float64(period)
+		out[i] = rsiValue(avgGain, avgLoss)
+	}
+	return out
+}
+
+func rsiValue(avgGain, avgLoss float64) float64 {
+	if avgLoss == 0 {
+		return 100
+	}
+	return 100 - 100/(1+avgGain/avgLoss)
+}
+
+// macdLines MACD line (fast EMA - slow EMA), signal EMA and histogram
+func macdLines(values []float64, fast, slow, signal int) []IndicatorLine {
+	fastEMA := emaSeries(values, fast)
+	slowEMA := emaSeries(values, slow)
+
+	macd := nanSeries(len(values))
+	for i := range values {
+		if !math.IsNaN(fastEMA[i]) && !math.IsNaN(slowEMA[i]) {
+			macd[i] = fastEMA[i] - slowEMA[i]
+		}
+	}
+	signalLine := emaSeries(macd, signal)
+	histogram := nanSeries(len(values))
+	for i := range values {
+		if !math.IsNaN(macd[i]) && !math.IsNaN(signalLine[i]) {
+			histogram[i] = macd[i] - signalLine[i]
+		}
+	}
+
+	return []IndicatorLine{
+		{Name: "macd", Values: macd},
+		{Name: "signal", Values: signalLine},
+		{Name: "histogram", Values: histogram},
+	}
+}
+
+// trueRanges true range of each kline (the first one has no previous close)
+func trueRanges(klines []Kline) []float64 {
+	trs := make([]float64, len(klines))
+	for i := 1; i < len(klines); i++ {
+		prevClose := klines[i-1].Close
+		trs[i] = math.Max(klines[i].High-klines[i].Low,
+			math.Max(math.Abs(klines[i].High-prevClose), math.Abs(klines[i].Low-prevClose)))
+	}
+	return trs
+}
+
+// atrSeries ATR with Wilder smoothing (same as calculateATR)
+func atrSeries(klines []Kline, period int) []float64 {
+	out := nanSeries(len(klines))
+	if period <= 0 || len(klines) <= period {
+		return out
+	}
+
+	trs := trueRanges(klines)
+	sum := 0.0
+	for i := 1; i <= period; i++ {
+		sum += trs[i]
+	}
+	atr := sum / float64(period)
+	out[period] = atr
+
+	for i := period + 1; i < len(klines); i++ {
+		atr = (atr*float64(period-1) + trs[i]) / float64(period)
+		out[i] = atr
+	}
+	return out
+}
+
+// bollingerLines SMA middle band with population stddev bands
+func bollingerLines(values []float64, period int, stddev float64) []IndicatorLine {
+	middle := smaSeries(values, period)
+	upper := nanSeries(len(values))
+	lower := nanSeries(len(values))
+	for i := period - 1; i < len(values); i++ {
+		if i < 0 || math.IsNaN(middle[i]) {
+			continue
+		}
+		variance := 0.0
+		for j := i - period + 1; j <= i; j++ {
+			d := values[j] - middle[i]
+			variance += d * d
+		}
+		sd := math.Sqrt(variance / float64(period))
+		upper[i] = middle[i] + stddev*sd
+		lower[i] = middle[i] - stddev*sd
+	}
+
+	return []IndicatorLine{
+		{Name: "upper", Values: upper},
+		{Name: "middle", Values: middle},
+		{Name: "lower", Values: lower},
+	}
+}
+
+// vwapSeries VWAP of typical price, anchored at UTC midnight (period 0) or rolling over period bars
+func vwapSeries(klines []Kline, period int) []float64 {
+	out := nanSeries(len(klines))
+	var pv, vol float64
+	var day int64 = -1
+	for i, k := range klines {
+		typical := (k.High + k.Low + k.Close) / 3
+		if period > 0 {
+			pv += typical * k.Volume
+			vol += k.Volume
+			if i >= period {
+				old := klines[i-period]
+				pv -= (old.High + old.Low + old.Close) / 3 * old.Volume
+				vol -= old.Volume
+			}
+			if i < period-1 {
+				continue
+			}
+		} else {
+			if d := k.OpenTime / int64(24*time.Hour/time.Millisecond); d != day {
+				day = d
+				pv, vol = 0, 0
+			}
+			pv += typical * k.Volume
+			vol += k.Volume
+		}
+		if vol > 0 {
+			out[i] = pv / vol
+		} else {
+			out[i] = typical
+		}
+	}
+	return out
+}
+
+// stochRSILines stochastic of RSI smoothed into %K and %D
+func stochRSILines(values []float64, rsiPeriod, stochPeriod, kPeriod, dPeriod int) []IndicatorLine {
+	rsi := rsiSeries(values, rsiPeriod)
+	raw := nanSeries(len(values))
+	for i := range rsi {
+		if i < stochPeriod-1 {
+			continue
+		}
+		lowest, highest := math.Inf(1), math.Inf(-1)
+		complete := true
+		for j := i - stochPeriod + 1; j <= i; j++ {
+			if math.IsNaN(rsi[j]) {
+				complete = false
+				break
+			}
+			lowest = math.Min(lowest, rsi[j])
+			highest = math.Max(highest, rsi[j])
+		}
+		if !complete {
+			continue
+		}
+		if highest == lowest {
+			raw[i] = 0
+		} else {
+			raw[i] = (rsi[i] - lowest) / (highest - lowest) * 100
+		}
+	}
+
+	k := smaSeries(raw, kPeriod)
+	return []IndicatorLine{
+		{Name: "k", Values: k},
+		{Name: "d", Values: smaSeries(k, dPeriod)},
+	}
+}
+
+// adxLines ADX and directional indicators with Wilder smoothing
+func adxLines(klines []Kline, period int) []IndicatorLine {
+	n := len(klines)
+	adx, plusDI, minusDI := nanSeries(n), nanSeries(n), nanSeries(n)
+	lines := []IndicatorLine{
+		{Name: "adx", Values: adx},
+		{Name: "plus_di", Values: plusDI},
+		{Name: "minus_di", Values: minusDI},
+	}
+	if period <= 0 || n <= period {
+		return lines
+	}
+
+	trs := trueRanges(klines)
+	plusDM := make([]float64, n)
+	minusDM := make([]float64, n)
+	for i := 1; i < n; i++ {
+		up := klines[i].High - klines[i-1].High
+		down := klines[i-1].Low - klines[i].Low
+		if up > down && up > 0 {
+			plusDM[i] = up
+		}
+		if down > up && down > 0 {
+			minusDM[i] = down
+		}
+	}
+
+	var trSum, plusSum, minusSum float64
+	for i := 1; i <= period; i++ {
+		trSum += trs[i]
+		plusSum += plusDM[i]
+		minusSum += minusDM[i]
+	}
+
+	dx := nanSeries(n)
+	for i := period; i < n; i++ {
+		if i > period {
+			trSum = trSum - trSum/float64(period) + trs[i]
+			plusSum = plusSum - plusSum/float64(period) + plusDM[i]
+			minusSum = minusSum - minusSum/float64(period) + minusDM[i]
+		}
+		if trSum == 0 {
+			plusDI[i], minusDI[i], dx[i] = 0, 0, 0
+			continue
+		}
+		plusDI[i] = 100 * plusSum / trSum
+		minusDI[i] = 100 * minusSum / trSum
+		if sum := plusDI[i] + minusDI[i]; sum > 0 {
+			dx[i] = 100 * math.Abs(plusDI[i]-minusDI[i]) / sum
+		} else {
+			dx[i] = 0
+		}
+	}
+
+	// ADX: average of the first period DX values, then Wilder smoothing
+	first := 2*period - 1
+	if first >= n {
+		return lines
+	}
+	sum := 0.0
+	for i := period; i <= first; i++ {
+		sum += dx[i]
+	}
+	adx[first] = sum / float64(period)
+	for i := first + 1; i < n; i++ {
+		adx[i] = (adx[i-1]*float64(period-1) + dx[i]) / float64(period)
+	}
+	return lines
+}
+
+// supertrendLines SuperTrend line and trend direction
+func supertrendLines(klines []Kline, period int, multiplier float64) []IndicatorLine {
+	n := len(klines)
+	line, direction := nanSeries(n), nanSeries(n)
+	atr := atrSeries(klines, period)
+
+	var finalUpper, finalLower float64
+	trend := 1.0
+	started := false
+	for i := 0; i < n; i++ {
+		if math.IsNaN(atr[i]) {
+			continue
+		}
+		mid := (klines[i].High + klines[i].Low) / 2
+		basicUpper := mid + multiplier*atr[i]
+		basicLower := mid - multiplier*atr[i]
+
+		if !started {
+			finalUpper, finalLower = basicUpper, basicLower
+			if klines[i].Close < mid {
+				trend = -1
+			}
+			started = true
+		} else {
+			prevClose := klines[i-1].Close
+			if basicUpper < finalUpper || prevClose > finalUpper {
+				finalUpper = basicUpper
+			}
+			if basicLower > finalLower || prevClose < finalLower {
+				finalLower = basicLower
+			}
+			if trend < 0 && klines[i].Close > finalUpper {
+				trend = 1
+			} else if trend > 0 && klines[i].Close < finalLower {
+				trend = -1
+			}
+		}
+
+		direction[i] = trend
+		if trend > 0 {
+			line[i] = finalLower
+		} else {
+			line[i] = finalUpper
+		}
+	}
+
+	return []IndicatorLine{
+		{Name: "line", Values: line},
+		{Name: "direction", Values: direction},
+	}
+}
+
+// midpointSeries (highest high + lowest low) / 2 over period bars
+func midpointSeries(klines []Kline, period int) []float64 {
+	upper, _, lower := donchianBands(klines, period)
+	out := nanSeries(len(klines))
+	for i := range out {
+		if !math.IsNaN(upper[i]) {
+			out[i] = (upper[i] + lower[i]) / 2
+		}
+	}
+	return out
+}
+
+// ichimokuLines conversion, base and the leading spans as plotted at each bar
+// The leading spans are computed displacement bars earlier, so no future data is used
+func ichimokuLines(klines []Kline, conversion, base, spanB, displacement int) []IndicatorLine {
+	n := len(klines)
+	conversionLine := midpointSeries(klines, conversion)
+	baseLine := midpointSeries(klines, base)
+	spanBRaw := midpointSeries(klines, spanB)
+
+	spanA, spanBLine := nanSeries(n), nanSeries(n)
+	for i := displacement; i < n; i++ {
+		j := i - displacement
+		if !math.IsNaN(conversionLine[j]) && !math.IsNaN(baseLine[j]) {
+			spanA[i] = (conversionLine[j] + baseLine[j]) / 2
+		}
+		spanBLine[i] = spanBRaw[j]
+	}
+
+	return []IndicatorLine{
+		{Name: "conversion", Values: conversionLine},
+		{Name: "base", Values: baseLine},
+		{Name: "span_a", Values: spanA},
+		{Name: "span_b", Values: spanBLine},
+	}
+}
+
+// obvSeries on-balance volume, starting at 0
+func obvSeries(klines []Kline) []float64 {
+	out := make([]float64, len(klines))
+	for i := 1; i < len(klines); i++ {
+		switch {
+		case klines[i].Close > klines[i-1].Close:
+			out[i] = out[i-1] + klines[i].Volume
+		case klines[i].Close < klines[i-1].Close:
+			out[i] = out[i-1] - klines[i].Volume
+		default:
+			out[i] = out[i-1]
+		}
+	}
+	return out
+}
+
+// donchianBands highest high, midpoint and lowest low over period bars
+func donchianBands(klines []Kline, period int) ([]float64, []float64, []float64) {
+	n := len(klines)
+	upper, middle, lower := nanSeries(n), nanSeries(n), nanSeries(n)
+	if period <= 0 {
+		return upper, middle, lower
+	}
+	for i := period - 1; i < n; i++ {
+		high, low := math.Inf(-1), math.Inf(1)
+		for j := i - period + 1; j <= i; j++ {
+			high = math.Max(high, klines[j].High)
+			low = math.Min(low, klines[j].Low)
+		}
+		upper[i], lower[i] = high, low
+		middle[i] = (high + low) / 2
+	}
+	return upper, middle, lower
+}
+
+// donchianLines Donchian channel lines
+func donchianLines(klines []Kline, period int) []IndicatorLine {
+	upper, middle, lower := donchianBands(klines, period)
+	return []IndicatorLine{
+		{Name: "upper", Values: upper},
+		{Name: "middle", Values: middle},
+		{Name: "lower", Values: lower},
+	}
+}
@@ -0,0 +1,221 @@
+package market
+
+import (
+	"math"
+	"strings"
+	"testing"
+)
+
+// trendingKlines generates klines with a trend and oscillation so every indicator has something to measure
+func trendingKlines(count int) []Kline {
+	klines := make([]Kline, count)
+	for i := 0; i < count; i++ {
+		base := 100 + float64(i)*0.2 + 3*math.Sin(float64(i)/4)
+		klines[i] = Kline{
+			OpenTime:  int64(i) * 3600000, // 1-hour interval
+			Open:      base - 0.4,
+			High:      base + 1.2,
+			Low:       base - 1.1,
+			Close:     base + 0.3,
+			Volume:    1000 + float64(i%7)*150,
+			CloseTime: int64(i+1)*3600000 - 1,
+		}
+	}
+	return klines
+}
+
+// TestRegistryIndicators_MatchLegacy tests that registry EMA/RSI/MACD/ATR match the legacy calculations
+func TestRegistryIndicators_MatchLegacy(t *testing.T) {
+	klines := trendingKlines(120)
+
+	compute := func(name string, params map[string]float64) []float64 {
+		ind, err := NewIndicator(IndicatorSpec{Name: name, Params: params})
+		if err != nil {
+			t.Fatalf("NewIndicator(%s) error: %v", name, err)
+		}
+		return ind.Compute(klines)
+	}
+
+	ema := compute("ema", map[string]float64{"period": 20})
+	rsi := compute("rsi", map[string]float64{"period": 7})
+	macd := compute("macd", nil)
+	atr := compute("atr", nil)
+
+	for i := 50; i < len(klines); i++ {
+		window := klines[:i+1]
+		checks := []struct {
+			name      string
+			got, want float64
+		}{
+			{"ema20", ema[i], calculateEMA(window, 20)},
+			{"rsi7", rsi[i], calculateRSI(window, 7)},
+			{"macd", macd[i], calculateMACD(window)},
+			{"atr14", atr[i], calculateATR(window, 14)},
+		}
+		for _, c := range checks {
+			if math.Abs(c.got-c.want) > 1e-9 {
+				t.Fatalf("%s at %d = %v, legacy = %v", c.name, i, c.got, c.want)
+			}
+		}
+	}
+
+	if !math.IsNaN(ema[18]) || math.IsNaN(ema[19]) {
+		t.Errorf("ema20 warm-up should end at index 19")
+	}
+}
+
+// TestNewIndicator_Params tests default filling and parameter validation
+func TestNewIndicator_Params(t *testing.T) {
+	ind, err := NewIndicator(IndicatorSpec{Name: "Bollinger", Params: map[string]float64{"stddev": 2.5}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	params := ind.Params()
+	if params["period"] != 20 || params["stddev"] != 2.5 {
+		t.Errorf("params = %v, want period 20 and stddev 2.5", params)
+	}
+
+	if _, err := NewIndicator(IndicatorSpec{Name: "unknown"}); err == nil {
+		t.Error("expected error for unknown indicator")
+	}
+	if _, err := NewIndicator(IndicatorSpec{Name: "adx", Params: map[string]float64{"period": 0}}); err == nil {
+		t.Error("expected error for out of range parameter")
+	}
+	if _, err := NewIndicator(IndicatorSpec{Name: "adx", Params: map[string]float64{"length": 14}}); err == nil {
+		t.Error("expected error for unknown parameter")
+	}
+}
+
+// TestRegistry_BuiltinIndicators tests that all built-in indicators produce finite values after warm-up
+func TestRegistry_BuiltinIndicators(t *testing.T) {
+	klines := trendingKlines(150)
+	names := []string{"ema", "rsi", "macd", "atr", "bollinger", "vwap", "stoch_rsi", "adx", "supertrend", "ichimoku", "obv", "donchian"}
+
+	for _, name := range names {
+		ind, err := NewIndicator(IndicatorSpec{Name: name})
+		if err != nil {
+			t.Fatalf("NewIndicator(%s) error: %v", name, err)
+		}
+		lines := []IndicatorLine{{Values: ind.Compute(klines)}}
+		if multi, ok := ind.(MultiLineIndicator); ok {
+			lines = multi.ComputeLines(klines)
+		}
+		for _, line := range lines {
+			if len(line.Values) != len(klines) {
+				t.Fatalf("%s %s: %d values, want %d", name, line.Name, len(line.Values), len(klines))
+			}
+			last := line.Values[len(line.Values)-1]
+			if math.IsNaN(last) || math.IsInf(last, 0) {
+				t.Errorf("%s %s: last value %v is not finite", name, line.Name, last)
+			}
+		}
+	}
+}
+
+// TestBollingerAndDonchian tests band values on a known series
+func TestBollingerAndDonchian(t *testing.T) {
+	klines := make([]Kline, 5)
+	for i, c := range []float64{1, 2, 3, 4, 5} {
+		klines[i] = Kline{Open: c, High: c + 1, Low: c - 1, Close: c, Volume: 1}
+	}
+
+	bands := bollingerLines(closes(klines), 5, 2)
+	sd := math.Sqrt(2) // Population stddev of 1..5
+	if math.Abs(bands[1].Values[4]-3) > 1e-9 || math.Abs(bands[0].Values[4]-(3+2*sd)) > 1e-9 || math.Abs(bands[2].Values[4]-(3-2*sd)) > 1e-9 {
+		t.Errorf("bollinger = %v/%v/%v", bands[0].Values[4], bands[1].Values[4], bands[2].Values[4])
+	}
+	if !math.IsNaN(bands[1].Values[3]) {
+		t.Error("bollinger should be NaN before the period is complete")
+	}
+
+	channel := donchianLines(klines, 3)
+	if channel[0].Values[4] != 6 || channel[2].Values[4] != 2 || channel[1].Values[4] != 4 {
+		t.Errorf("donchian = %v/%v/%v, want 6/4/2", channel[0].Values[4], channel[1].Values[4], channel[2].Values[4])
+	}
+}
+
+// TestVWAP_DailyAnchor tests that anchored VWAP resets at UTC midnight
+func TestVWAP_DailyAnchor(t *testing.T) {
+	day := int64(24 * 3600000)
+	klines := []Kline{
+		{OpenTime: day - 7200000, High: 10, Low: 10, Close: 10, Volume: 1},
+		{OpenTime: day - 3600000, High: 20, Low: 20, Close: 20, Volume: 3},
+		{OpenTime: day, High: 30, Low: 30, Close: 30, Volume: 2},
+	}
+
+	vwap := vwapSeries(klines, 0)
+	if math.Abs(vwap[1]-17.5) > 1e-9 {
+		t.Errorf("vwap before midnight = %v, want 17.5", vwap[1])
+	}
+	if math.Abs(vwap[2]-30) > 1e-9 {
+		t.Errorf("vwap after midnight = %v, want 30", vwap[2])
+	}
+}
+
+// TestOBV tests on-balance volume accumulation
+func TestOBV(t *testing.T) {
+	klines := []Kline{
+		{Close: 10, Volume: 5},
+		{Close: 11, Volume: 3},
+		{Close: 10, Volume: 2},
+		{Close: 10, Volume: 4},
+	}
+	want := []float64{0, 3, 1, 1}
+	for i, v := range obvSeries(klines) {
+		if v != want[i] {
+			t.Errorf("obv[%d] = %v, want %v", i, v, want[i])
+		}
+	}
+}
+
+// TestSupertrend_Direction tests that SuperTrend follows a steady trend
+func TestSupertrend_Direction(t *testing.T) {
+	klines := make([]Kline, 60)
+	for i := range klines {
+		c := 100 + float64(i)
+		klines[i] = Kline{Open: c - 0.5, High: c + 1, Low: c - 1, Close: c, Volume: 1}
+	}
+
+	lines := supertrendLines(klines, 10, 3)
+	if lines[1].Values[59] != 1 {
+		t.Errorf("direction = %v, want 1 in an uptrend", lines[1].Values[59])
+	}
+	if lines[0].Values[59] >= klines[59].Close {
+		t.Errorf("uptrend supertrend %v should be below close %v", lines[0].Values[59], klines[59].Close)
+	}
+}
+
+// TestCalculateTimeframeSeries_CustomIndicators tests that selected indicators are added to the timeframe series
+func TestCalculateTimeframeSeries_CustomIndicators(t *testing.T) {
+	klines := trendingKlines(100)
+	specs := []IndicatorSpec{
+		{Name: "bollinger", Params: map[string]float64{"period": 20, "stddev": 2}},
+		{Name: "obv"},
+	}
+
+	data := BuildTimeframeSeries(klines, "1h", 30, SeriesOptions{Indicators: specs})
+	if len(data.Indicators) != 4 {
+		t.Fatalf("indicators = %d, want 4 (3 bollinger lines + obv)", len(data.Indicators))
+	}
+	if data.Indicators[0].Label != "BOLLINGER_UPPER(20,2)" {
+		t.Errorf("label = %s, want BOLLINGER_UPPER(20,2)", data.Indicators[0].Label)
+	}
+	if data.Indicators[3].Label != "OBV" {
+		t.Errorf("label = %s, want OBV", data.Indicators[3].Label)
+	}
+	for _, series := range data.Indicators {
+		if len(series.Values) != 30 {
+			t.Errorf("%s has %d values, want 30", series.Label, len(series.Values))
+		}
+	}
+
+	// Series end at the latest kline
+	middle := smaSeries(closes(klines), 20)
+	if data.Indicators[1].Values[29] != middle[99] {
+		t.Errorf("middle band = %v, want %v", data.Indicators[1].Values[29], middle[99])
+	}
+
+	if !strings.Contains(Format(&Data{TimeframeData: map[string]*TimeframeSeriesData{"1h": data}}), "BOLLINGER_MIDDLE(20,2): [") {
+		t.Error("formatted data should contain the custom indicator series")
+	}
+}
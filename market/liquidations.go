@@ -0,0 +1,263 @@
+package market
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	liquidationStream    = "!forceOrder@arr" // All-market liquidation stream, one snapshot per symbol per second at most
+	liquidationBucketMs  = int64(60_000)     // Liquidations are aggregated into 1-minute buckets
+	liquidationRetention = 4 * 60            // Minute buckets kept per symbol, covers the longest window
+	liquidationBuffer    = 1000
+)
+
+// LiquidationWindows rolling windows reported for each symbol
+var LiquidationWindows = []struct {
+	Label   string
+	Minutes int
+}{
+	{"5m", 5},
+	{"15m", 15},
+	{"1h", 60},
+	{"4h", 240},
+}
+
+// LiquidationWSData force order stream payload
+type LiquidationWSData struct {
+	EventType string `json:"e"`
+	EventTime int64  `json:"E"`
+	Order     struct {
+		Symbol    string `json:"s"`
+		Side      string `json:"S"` // SELL = a long was liquidated, BUY = a short was liquidated
+		Price     string `json:"p"`
+		AvgPrice  string `json:"ap"`
+		Quantity  string `json:"q"`
+		FilledQty string `json:"z"`
+		TradeTime int64  `json:"T"`
+	} `json:"o"`
+}
+
+// Liquidation a forced liquidation order
+type Liquidation struct {
+	Symbol   string
+	Long     bool    // true = a long position was liquidated (forced sell)
+	Notional float64 // USDT
+	Time     int64   // ms
+}
+
+// liquidationBucket liquidations of one minute
+type liquidationBucket struct {
+	Start         int64
+	LongNotional  float64
+	ShortNotional float64
+	LongCount     int
+	ShortCount    int
+}
+
+// LiquidationWindow liquidation totals over a rolling window
+type LiquidationWindow struct {
+	Window        string  `json:"window"`
+	LongNotional  float64 `json:"long_notional"`  // Longs liquidated (USDT)
+	ShortNotional float64 `json:"short_notional"` // Shorts liquidated (USDT)
+	LongCount     int     `json:"long_count"`
+	ShortCount    int     `json:"short_count"`
+	Complete      bool    `json:"complete"` // false when the stream has been watched for less than the window
+}
+
+// LiquidationStats recent liquidations of a symbol
+type LiquidationStats struct {
+	Windows []LiquidationWindow `json:"windows"`
+	// 5-minute notional relative to the average 5 minutes of the last hour (> 1 = accelerating), 0 when unknown
+	Acceleration float64   `json:"acceleration"`
+	Since        time.Time `json:"since"` // Time the stream has been watched from
+	UpdatedAt    time.Time `json:"updated_at"`
+}
+
+// LiquidationTracker aggregates liquidation events into per-symbol minute buckets
+type LiquidationTracker struct {
+	mu      sync.RWMutex
+	buckets map[string][]liquidationBucket
+	since   int64 // Time watching started (ms), windows reaching further back are incomplete
+}
+
+// NewLiquidationTracker creates an empty tracker
+func NewLiquidationTracker() *LiquidationTracker {
+	return &LiquidationTracker{buckets: make(map[string][]liquidationBucket)}
+}
+
+// Start marks the time from which the stream is watched
+func (t *LiquidationTracker) Start(now time.Time) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.since == 0 {
+		t.since = now.UnixMilli()
+	}
+}
+
+// Add adds a liquidation to the symbol's minute bucket
+func (t *LiquidationTracker) Add(l Liquidation) {
+	if l.Notional <= 0 || l.Symbol == "" {
+		return
+	}
+	start := l.Time - l.Time%liquidationBucketMs
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.since == 0 {
+		t.since = l.Time
+	}
+
+	buckets := t.buckets[l.Symbol]
+	if n := len(buckets); n == 0 || buckets[n-1].Start < start {
+		buckets = append(buckets, liquidationBucket{Start: start})
+		if len(buckets) > liquidationRetention {
+			buckets = buckets[len(buckets)-liquidationRetention:]
+		}
+	}
+
+	i := len(buckets) - 1
+	for i >= 0 && buckets[i].Start > start {
+		i--
+	}
+	if i >= 0 && buckets[i].Start == start {
+		if l.Long {
+			buckets[i].LongNotional += l.Notional
+			buckets[i].LongCount++
+		} else {
+			buckets[i].ShortNotional += l.Notional
+			buckets[i].ShortCount++
+		}
+	}
+	t.buckets[l.Symbol] = buckets
+}
+
+// Stats returns the symbol's liquidation totals over LiquidationWindows ending at now
+// Returns nil when the stream hasn't been started
+func (t *LiquidationTracker) Stats(symbol string, now time.Time) *LiquidationStats {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	if t.since == 0 {
+		return nil
+	}
+
+	nowMs := now.UnixMilli()
+	stats := &LiquidationStats{
+		Since:     time.UnixMilli(t.since),
+		UpdatedAt: now,
+	}
+	buckets := t.buckets[symbol]
+	for _, w := range LiquidationWindows {
+		from := nowMs - int64(w.Minutes)*liquidationBucketMs
+		window := LiquidationWindow{Window: w.Label, Complete: t.since <= from}
+		for i := len(buckets) - 1; i >= 0 && buckets[i].Start+liquidationBucketMs > from; i-- {
+			if buckets[i].Start > nowMs {
+				continue
+			}
+			window.LongNotional += buckets[i].LongNotional
+			window.ShortNotional += buckets[i].ShortNotional
+			window.LongCount += buckets[i].LongCount
+			window.ShortCount += buckets[i].ShortCount
+		}
+		stats.Windows = append(stats.Windows, window)
+	}
+
+	// Acceleration compares the 5m window with the 1h average per 5 minutes
+	if w5, w60 := stats.Windows[0], stats.Windows[2]; w60.Complete {
+		hourly := w60.LongNotional + w60.ShortNotional
+		if hourly > 0 {
+			stats.Acceleration = (w5.LongNotional + w5.ShortNotional) / (hourly / 12)
+		}
+	}
+	return stats
+}
+
+// parseLiquidation converts a force order payload to a liquidation, using the filled quantity and average price when set
+func parseLiquidation(data LiquidationWSData) Liquidation {
+	o := data.Order
+	qty, _ := parseFloat(o.FilledQty)
+	if qty <= 0 {
+		qty, _ = parseFloat(o.Quantity)
+	}
+	price, _ := parseFloat(o.AvgPrice)
+	if price <= 0 {
+		price, _ = parseFloat(o.Price)
+	}
+	tradeTime := o.TradeTime
+	if tradeTime == 0 {
+		tradeTime = data.EventTime
+	}
+	return Liquidation{
+		Symbol:   strings.ToUpper(o.Symbol),
+		Long:     o.Side == "SELL",
+		Notional: qty * price,
+		Time:     tradeTime,
+	}
+}
+
+// SubscribeLiquidations subscribes to the all-market liquidation stream once
+func (m *WSMonitor) SubscribeLiquidations() error {
+	m.liquidationMu.Lock()
+	defer m.liquidationMu.Unlock()
+	if m.liquidationSubscribed {
+		return nil
+	}
+
+	ch := m.combinedClient.AddSubscriber(liquidationStream, liquidationBuffer)
+	if err := m.combinedClient.subscribeStreams([]string{liquidationStream}); err != nil {
+		return fmt.Errorf("failed to subscribe to liquidation stream: %w", err)
+	}
+	m.liquidationSubscribed = true
+	m.liquidations.Start(time.Now())
+	go m.handleLiquidationData(ch)
+
+	log.Printf("Subscribed to liquidation stream: %s", liquidationStream)
+	return nil
+}
+
+// GetLiquidationStats returns the symbol's recent liquidations, subscribing to the stream on first use
+func (m *WSMonitor) GetLiquidationStats(symbol string) (*LiquidationStats, error) {
+	if err := m.SubscribeLiquidations(); err != nil {
+		return nil, err
+	}
+	return m.liquidations.Stats(Normalize(symbol), time.Now()), nil
+}
+
+func (m *WSMonitor) handleLiquidationData(ch <-chan []byte) {
+	for data := range ch {
+		var wsData LiquidationWSData
+		if err := json.Unmarshal(data, &wsData); err != nil {
+			log.Printf("Failed to parse liquidation data: %v", err)
+			continue
+		}
+		m.liquidations.Add(parseLiquidation(wsData))
+	}
+}
+
+// FormatLiquidationStats formats recent liquidations for prompts
+func FormatLiquidationStats(stats *LiquidationStats) string {
+	if stats == nil || len(stats.Windows) == 0 {
+		return ""
+	}
+
+	parts := make([]string, 0, len(stats.Windows))
+	for _, w := range stats.Windows {
+		part := fmt.Sprintf("%s longs %.0f (%d) / shorts %.0f (%d)", w.Window, w.LongNotional, w.LongCount, w.ShortNotional, w.ShortCount)
+		if !w.Complete {
+			part += " [partial]"
+		}
+		parts = append(parts, part)
+	}
+
+	s := "Liquidations (USDT, count): " + strings.Join(parts, " | ") + "\n"
+	if stats.Acceleration > 0 {
+		s += fmt.Sprintf("Liquidation pace: last 5m is %.1fx the 1h average\n", stats.Acceleration)
+	}
+	return s
+}
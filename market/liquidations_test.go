@@ -0,0 +1,82 @@
+package market
+
+import (
+	"encoding/json"
+	"math"
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestParseLiquidation tests side mapping and notional from the force order payload
+func TestParseLiquidation(t *testing.T) {
+	raw := `{"e":"forceOrder","E":1568014460893,"o":{"s":"BTCUSDT","S":"SELL","o":"LIMIT","f":"IOC","q":"0.014","p":"9910","ap":"9900","X":"FILLED","l":"0.014","z":"0.010","T":1568014460890}}`
+	var data LiquidationWSData
+	if err := json.Unmarshal([]byte(raw), &data); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+
+	l := parseLiquidation(data)
+	if l.Symbol != "BTCUSDT" || !l.Long || l.Time != 1568014460890 {
+		t.Errorf("liquidation = %+v, want a BTCUSDT long at 1568014460890", l)
+	}
+	if math.Abs(l.Notional-99) > 1e-9 {
+		t.Errorf("notional = %v, want 99 (filled 0.010 @ avg 9900)", l.Notional)
+	}
+
+	data.Order.Side = "BUY"
+	data.Order.FilledQty = "0"
+	data.Order.AvgPrice = "0"
+	l = parseLiquidation(data)
+	if l.Long || math.Abs(l.Notional-0.014*9910) > 1e-9 {
+		t.Errorf("liquidation = %+v, want a short with the order quantity and price", l)
+	}
+}
+
+// TestLiquidationTracker_Stats tests rolling window totals, completeness and acceleration
+func TestLiquidationTracker_Stats(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	now := start.Add(2 * time.Hour)
+
+	tracker := NewLiquidationTracker()
+	if tracker.Stats("BTCUSDT", now) != nil {
+		t.Fatal("stats should be nil before the stream is started")
+	}
+	tracker.Start(start)
+
+	at := func(d time.Duration) int64 { return now.Add(-d).UnixMilli() }
+	// One short every 10 minutes during the last hour, 60k each
+	for m := 57; m > 0; m -= 10 {
+		tracker.Add(Liquidation{Symbol: "BTCUSDT", Long: false, Notional: 60_000, Time: at(time.Duration(m) * time.Minute)})
+	}
+	// A long cascade in the last 3 minutes
+	tracker.Add(Liquidation{Symbol: "BTCUSDT", Long: true, Notional: 200_000, Time: at(3 * time.Minute)})
+	tracker.Add(Liquidation{Symbol: "BTCUSDT", Long: true, Notional: 300_000, Time: at(2 * time.Minute)})
+	tracker.Add(Liquidation{Symbol: "ETHUSDT", Long: true, Notional: 1_000, Time: at(time.Minute)})
+
+	stats := tracker.Stats("BTCUSDT", now)
+	if len(stats.Windows) != 4 {
+		t.Fatalf("windows = %d, want 4", len(stats.Windows))
+	}
+	w5, w1h, w4h := stats.Windows[0], stats.Windows[2], stats.Windows[3]
+	if w5.LongNotional != 500_000 || w5.LongCount != 2 || w5.ShortNotional != 0 {
+		t.Errorf("5m window = %+v, want 2 longs for 500000", w5)
+	}
+	if w1h.ShortCount != 6 || w1h.ShortNotional != 360_000 || !w1h.Complete {
+		t.Errorf("1h window = %+v, want 6 shorts for 360000, complete", w1h)
+	}
+	if w4h.Complete {
+		t.Error("4h window should be partial after watching for 2 hours")
+	}
+
+	// 500k in 5m vs (500k + 360k) / 12 per 5m over the hour
+	want := 500_000 / (860_000.0 / 12)
+	if math.Abs(stats.Acceleration-want) > 1e-9 {
+		t.Errorf("acceleration = %v, want %v", stats.Acceleration, want)
+	}
+
+	text := FormatLiquidationStats(stats)
+	if !strings.Contains(text, "5m longs 500000 (2) / shorts 0 (0)") || !strings.Contains(text, "[partial]") {
+		t.Errorf("unexpected format: %s", text)
+	}
+}
@@ -9,6 +9,9 @@ import (
 	"time"
 )
 
+// KlineCacheSize number of klines kept per symbol and timeframe, indicators are computed over this window
+const KlineCacheSize = 100
+
 type WSMonitor struct {
 	wsClient       *WSClient
 	combinedClient *CombinedStreamsClient
@@ -17,12 +20,29 @@ type WSMonitor struct {
 	alertsChan     chan Alert
 	klineDataMap3m sync.Map // Store K-line historical data for each trading pair
 	klineDataMap4h sync.Map // Store K-line historical data for each trading pair
+	klineDataMaps  sync.Map // K-line data of the other timeframes: timeframe -> *sync.Map (symbol -> []Kline)
+	klineSubs      sync.Map // Subscribed K-line streams (stream name -> struct{})
 	tickerDataMap  sync.Map // Store ticker data for each trading pair
 	batchSize      int
-	filterSymbols  sync.Map // Use sync.Map to store monitored coins and their status
-	symbolStats    sync.Map // Store symbol statistics
-	FilterSymbol   []string // Filtered symbols
+	filterSymbols  sync.Map         // Use sync.Map to store monitored coins and their status
+	symbolStats    sync.Map         // Store symbol statistics
+	FilterSymbol   []string         // Filtered symbols
+	aggTrades      *AggTradeTracker // Taker flow from aggTrade streams
+	aggTradeSubs   sync.Map         // Symbols subscribed to the aggTrade stream -> last time a cycle asked for them
+
+	liquidations          *LiquidationTracker // Liquidations from the all-market force order stream
+	liquidationSubscribed bool
+	liquidationMu         sync.Mutex
+
+	listeners      map[int]KlineListener // Kline update listeners (decision triggers etc.)
+	nextListenerID int
+	listenersMu    sync.RWMutex
 }
+
+// KlineListener is called on every kline update of the subscribed streams
+// interval is "3m", "4h" or a timeframe loaded on demand by GetCurrentKlines, closed reports whether the kline is final
+type KlineListener func(symbol, interval string, kline Kline, closed bool)
+
 type SymbolStats struct {
 	LastActiveTime   time.Time
 	AlertCount       int
@@ -39,6 +59,8 @@ func NewWSMonitor(batchSize int) *WSMonitor {
 		wsClient:       NewWSClient(),
 		combinedClient: NewCombinedStreamsClient(batchSize),
 		alertsChan:     make(chan Alert, 1000),
+		aggTrades:      NewAggTradeTracker(),
+		liquidations:   NewLiquidationTracker(),
 		batchSize:      batchSize,
 	}
 	return WSMonitorCli
@@ -90,7 +112,7 @@ func (m *WSMonitor) initializeHistoricalData() error {
 			defer func() { <-semaphore }()
 
 			// Get historical K-line data
-			klines, err := apiClient.GetKlines(s, "3m", 100)
+			klines, err := apiClient.GetKlines(s, "3m", KlineCacheSize)
 			if err != nil {
 				log.Printf("Failed to get %s historical data: %v", s, err)
 				return
@@ -100,7 +122,7 @@ func (m *WSMonitor) initializeHistoricalData() error {
 				log.Printf("Loaded %s historical K-line data-3m: %d entries", s, len(klines))
 			}
 			// Get historical K-line data
-			klines4h, err := apiClient.GetKlines(s, "4h", 100)
+			klines4h, err := apiClient.GetKlines(s, "4h", KlineCacheSize)
 			if err != nil {
 				log.Printf("Failed to get %s historical data: %v", s, err)
 				return
@@ -153,7 +175,9 @@ func (m *WSMonitor) subscribeAll() error {
 	log.Println("Starting to subscribe to all trading pairs...")
 	for _, symbol := range m.symbols {
 		for _, st := range subKlineTime {
-			m.subscribeSymbol(symbol, st)
+			for _, stream := range m.subscribeSymbol(symbol, st) {
+				m.klineSubs.Store(stream, struct{}{})
+			}
 		}
 	}
 	for _, st := range subKlineTime {
@@ -179,15 +203,15 @@ func (m *WSMonitor) handleKlineData(symbol string, ch <-chan []byte, _time strin
 }
 
 func (m *WSMonitor) getKlineDataMap(_time string) *sync.Map {
-	var klineDataMap *sync.Map
-	if _time == "3m" {
-		klineDataMap = &m.klineDataMap3m
-	} else if _time == "4h" {
-		klineDataMap = &m.klineDataMap4h
-	} else {
-		klineDataMap = &sync.Map{}
+	switch _time {
+	case "3m":
+		return &m.klineDataMap3m
+	case "4h":
+		return &m.klineDataMap4h
 	}
-	return klineDataMap
+	// Other timeframes get their own cache on first use, so they are fetched once and then kept current by their stream
+	klineDataMap, _ := m.klineDataMaps.LoadOrStore(_time, &sync.Map{})
+	return klineDataMap.(*sync.Map)
 }
 func (m *WSMonitor) processKlineUpdate(symbol string, wsData KlineWSData, _time string) {
 	// Convert WebSocket data to Kline structure
@@ -221,7 +245,7 @@ func (m *WSMonitor) processKlineUpdate(symbol string, wsData KlineWSData, _time
 			klines = append(klines, kline)
 
 			// Maintain data length
-			if len(klines) > 100 {
+			if len(klines) > KlineCacheSize {
 				klines = klines[1:]
 			}
 		}
@@ -230,28 +254,59 @@ func (m *WSMonitor) processKlineUpdate(symbol string, wsData KlineWSData, _time
 	}
 
 	klineDataMap.Store(symbol, klines)
+
+	m.notifyListeners(symbol, _time, kline, wsData.Kline.IsFinal)
+}
+
+// AddKlineListener registers a listener for kline updates, returns a function that removes it
+// Listeners run on the stream goroutine and must not block
+func (m *WSMonitor) AddKlineListener(listener KlineListener) func() {
+	m.listenersMu.Lock()
+	defer m.listenersMu.Unlock()
+
+	if m.listeners == nil {
+		m.listeners = make(map[int]KlineListener)
+	}
+	id := m.nextListenerID
+	m.nextListenerID++
+	m.listeners[id] = listener
+
+	return func() {
+		m.listenersMu.Lock()
+		delete(m.listeners, id)
+		m.listenersMu.Unlock()
+	}
+}
+
+// notifyListeners forwards a kline update to all listeners
+func (m *WSMonitor) notifyListeners(symbol, interval string, kline Kline, closed bool) {
+	m.listenersMu.RLock()
+	defer m.listenersMu.RUnlock()
+
+	for _, listener := range m.listeners {
+		listener(symbol, interval, kline, closed)
+	}
 }
 
 func (m *WSMonitor) GetCurrentKlines(symbol string, duration string) ([]Kline, error) {
 	// Check if each incoming symbol exists internally, if not subscribe to it
-	value, exists := m.getKlineDataMap(duration).Load(symbol)
+	symbol = strings.ToUpper(symbol)
+	klineDataMap := m.getKlineDataMap(duration)
+	value, exists := klineDataMap.Load(symbol)
 	if !exists {
 		// If WS data is not initialized, use API separately - compatibility code (prevents trader from running when not initialized)
 		apiClient := NewAPIClient()
-		klines, err := apiClient.GetKlines(symbol, duration, 100)
+		klines, err := apiClient.GetKlines(symbol, duration, KlineCacheSize)
 		if err != nil {
 			return nil, fmt.Errorf("Failed to get %v-minute K-line: %v", duration, err)
 		}
 
-		// Dynamically cache into cache
-		m.getKlineDataMap(duration).Store(strings.ToUpper(symbol), klines)
-
-		// Subscribe to WebSocket stream
-		subStr := m.subscribeSymbol(symbol, duration)
-		subErr := m.combinedClient.subscribeStreams(subStr)
-		log.Printf("Dynamic subscription to stream: %v", subStr)
-		if subErr != nil {
-			log.Printf("Warning: Failed to dynamically subscribe to %v-minute K-line: %v (using API data)", duration, subErr)
+		// Dynamically cache into cache, the stream keeps it current
+		klineDataMap.Store(symbol, klines)
+		if err := m.subscribeKlines(symbol, duration); err != nil {
+			// Without a stream the cache would go stale: fetch again next time
+			klineDataMap.Delete(symbol)
+			log.Printf("Warning: Failed to dynamically subscribe to %v-minute K-line: %v (using API data)", duration, err)
 		}
 
 		// ✅ FIX: Return deep copy instead of reference
@@ -267,6 +322,24 @@ func (m *WSMonitor) GetCurrentKlines(symbol string, duration string) ([]Kline, e
 	return result, nil
 }
 
+// subscribeKlines subscribes to a symbol's kline stream once, its updates are cached by processKlineUpdate
+func (m *WSMonitor) subscribeKlines(symbol, timeframe string) error {
+	stream := fmt.Sprintf("%s@kline_%s", strings.ToLower(symbol), timeframe)
+	if _, loaded := m.klineSubs.LoadOrStore(stream, struct{}{}); loaded {
+		return nil
+	}
+
+	m.subscribeSymbol(symbol, timeframe)
+	if err := m.combinedClient.subscribeStreams([]string{stream}); err != nil {
+		// Closing the channel ends the handler goroutine
+		m.combinedClient.RemoveSubscriber(stream)
+		m.klineSubs.Delete(stream)
+		return err
+	}
+	log.Printf("Dynamic subscription to stream: %s", stream)
+	return nil
+}
+
 func (m *WSMonitor) Close() {
 	m.wsClient.Close()
 	close(m.alertsChan)
@@ -0,0 +1,39 @@
+package market
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+// TestGetCurrentKlines_CachesOtherTimeframes tests klines of timeframes other than 3m/4h are served from the monitor's cache
+func TestGetCurrentKlines_CachesOtherTimeframes(t *testing.T) {
+	m := &WSMonitor{}
+	m.getKlineDataMap("15m").Store("BTCUSDT", generateTestKlines(5))
+
+	if m.getKlineDataMap("15m") != m.getKlineDataMap("15m") {
+		t.Fatal("15m klines should have a single cache")
+	}
+	if m.getKlineDataMap("1h") == m.getKlineDataMap("15m") {
+		t.Fatal("timeframes should not share a cache")
+	}
+
+	// Cached: no REST fetch or subscription (the monitor has no clients)
+	klines, err := m.GetCurrentKlines("btcusdt", "15m")
+	if err != nil {
+		t.Fatalf("GetCurrentKlines() error = %v", err)
+	}
+	if len(klines) != 5 {
+		t.Fatalf("GetCurrentKlines() returned %d klines, want 5", len(klines))
+	}
+
+	// A stream update lands in the same cache
+	var update KlineWSData
+	if err := json.Unmarshal([]byte(`{"k":{"t":1099511627776,"c":"123"}}`), &update); err != nil {
+		t.Fatal(err)
+	}
+	m.processKlineUpdate("BTCUSDT", update, "15m")
+	klines, _ = m.GetCurrentKlines("BTCUSDT", "15m")
+	if len(klines) != 6 || klines[5].Close != 123 {
+		t.Fatalf("stream update not cached: %d klines", len(klines))
+	}
+}
@@ -0,0 +1,287 @@
+package market
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+)
+
+const (
+	orderBookDepthLimit = 500             // Levels per side fetched from the depth endpoint
+	orderBookCacheTTL   = 5 * time.Second // Snapshots are reused within a decision cycle
+	orderBookTopN       = 10              // Levels used for the bid/ask imbalance
+	orderBookWallFactor = 5.0             // A wall rests at least this many times the median level notional
+	orderBookMaxWalls   = 3               // Walls reported per side
+	orderBookWallRange  = 2.0             // Walls are searched within this % of the mid price
+)
+
+// OrderBookLevel a price level of the order book
+type OrderBookLevel struct {
+	Price    float64 `json:"price"`
+	Quantity float64 `json:"quantity"`
+}
+
+// OrderBook order book snapshot, bids descending and asks ascending by price
+type OrderBook struct {
+	Symbol    string           `json:"symbol"`
+	Bids      []OrderBookLevel `json:"bids"`
+	Asks      []OrderBookLevel `json:"asks"`
+	UpdatedAt time.Time        `json:"updated_at"`
+}
+
+// OrderBookWall a price level with unusually large resting size
+type OrderBookWall struct {
+	Price       float64 `json:"price"`
+	Notional    float64 `json:"notional"`     // USDT
+	DistancePct float64 `json:"distance_pct"` // Distance from the mid price in %
+}
+
+// OrderBookFeatures liquidity features of an order book snapshot
+type OrderBookFeatures struct {
+	BestBid    float64         `json:"best_bid"`
+	BestAsk    float64         `json:"best_ask"`
+	MidPrice   float64         `json:"mid_price"`
+	SpreadBps  float64         `json:"spread_bps"`
+	TopN       int             `json:"top_n"`
+	Imbalance  float64         `json:"imbalance"`    // (bid - ask) / (bid + ask) quantity over the top N levels, -1..1
+	BidDepth05 float64         `json:"bid_depth_05"` // Bid notional (USDT) within 0.5% of mid
+	AskDepth05 float64         `json:"ask_depth_05"` // Ask notional (USDT) within 0.5% of mid
+	BidDepth1  float64         `json:"bid_depth_1"`  // Bid notional (USDT) within 1% of mid
+	AskDepth1  float64         `json:"ask_depth_1"`  // Ask notional (USDT) within 1% of mid
+	BidWalls   []OrderBookWall `json:"bid_walls,omitempty"`
+	AskWalls   []OrderBookWall `json:"ask_walls,omitempty"`
+	UpdatedAt  time.Time       `json:"updated_at"`
+}
+
+var orderBookCache sync.Map // symbol -> *OrderBook
+
+// GetOrderBook retrieves an order book snapshot of a symbol (cached for a few seconds)
+func GetOrderBook(symbol string) (*OrderBook, error) {
+	symbol = Normalize(symbol)
+	if cached, ok := orderBookCache.Load(symbol); ok {
+		book := cached.(*OrderBook)
+		if time.Since(book.UpdatedAt) < orderBookCacheTTL {
+			return book, nil
+		}
+	}
+
+	book, err := NewAPIClient().GetDepth(symbol, orderBookDepthLimit)
+	if err != nil {
+		return nil, err
+	}
+	orderBookCache.Store(symbol, book)
+	return book, nil
+}
+
+// GetOrderBookFeatures retrieves the order book of a symbol and computes its liquidity features
+func GetOrderBookFeatures(symbol string) (*OrderBookFeatures, error) {
+	book, err := GetOrderBook(symbol)
+	if err != nil {
+		return nil, err
+	}
+	return ComputeOrderBookFeatures(book, orderBookTopN)
+}
+
+// GetDepth retrieves the order book from the depth endpoint
+func (c *APIClient) GetDepth(symbol string, limit int) (*OrderBook, error) {
+	url := fmt.Sprintf("%s/fapi/v1/depth?symbol=%s&limit=%d", baseURL, symbol, limit)
+	resp, err := c.client.Get(url)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != 200 {
+		return nil, fmt.Errorf("depth request failed (status %d): %s", resp.StatusCode, string(body))
+	}
+
+	var result struct {
+		Bids [][]string `json:"bids"`
+		Asks [][]string `json:"asks"`
+	}
+	if err := json.Unmarshal(body, &result); err != nil {
+		return nil, err
+	}
+
+	return &OrderBook{
+		Symbol:    symbol,
+		Bids:      parseDepthLevels(result.Bids),
+		Asks:      parseDepthLevels(result.Asks),
+		UpdatedAt: time.Now(),
+	}, nil
+}
+
+func parseDepthLevels(raw [][]string) []OrderBookLevel {
+	levels := make([]OrderBookLevel, 0, len(raw))
+	for _, level := range raw {
+		if len(level) < 2 {
+			continue
+		}
+		price, err1 := strconv.ParseFloat(level[0], 64)
+		qty, err2 := strconv.ParseFloat(level[1], 64)
+		if err1 != nil || err2 != nil || price <= 0 || qty <= 0 {
+			continue
+		}
+		levels = append(levels, OrderBookLevel{Price: price, Quantity: qty})
+	}
+	return levels
+}
+
+// ComputeOrderBookFeatures computes spread, top-N imbalance, depth bands and walls of a snapshot
+func ComputeOrderBookFeatures(book *OrderBook, topN int) (*OrderBookFeatures, error) {
+	if book == nil || len(book.Bids) == 0 || len(book.Asks) == 0 {
+		return nil, fmt.Errorf("order book is empty")
+	}
+
+	bestBid, bestAsk := book.Bids[0].Price, book.Asks[0].Price
+	mid := (bestBid + bestAsk) / 2
+	f := &OrderBookFeatures{
+		BestBid:   bestBid,
+		BestAsk:   bestAsk,
+		MidPrice:  mid,
+		SpreadBps: (bestAsk - bestBid) / mid * 10000,
+		TopN:      topN,
+		UpdatedAt: book.UpdatedAt,
+	}
+
+	var bidQty, askQty float64
+	for i := 0; i < topN && i < len(book.Bids); i++ {
+		bidQty += book.Bids[i].Quantity
+	}
+	for i := 0; i < topN && i < len(book.Asks); i++ {
+		askQty += book.Asks[i].Quantity
+	}
+	if bidQty+askQty > 0 {
+		f.Imbalance = (bidQty - askQty) / (bidQty + askQty)
+	}
+
+	f.BidDepth05 = depthWithin(book.Bids, mid, 0.5)
+	f.AskDepth05 = depthWithin(book.Asks, mid, 0.5)
+	f.BidDepth1 = depthWithin(book.Bids, mid, 1)
+	f.AskDepth1 = depthWithin(book.Asks, mid, 1)
+	f.BidWalls = findWalls(book.Bids, mid)
+	f.AskWalls = findWalls(book.Asks, mid)
+	return f, nil
+}
+
+// depthWithin sums the notional of the levels within pct % of the mid price
+func depthWithin(levels []OrderBookLevel, mid, pct float64) float64 {
+	depth := 0.0
+	for _, level := range levels {
+		if math.Abs(level.Price-mid)/mid*100 > pct {
+			break
+		}
+		depth += level.Price * level.Quantity
+	}
+	return depth
+}
+
+// findWalls finds the largest levels resting at least orderBookWallFactor times the median level notional
+func findWalls(levels []OrderBookLevel, mid float64) []OrderBookWall {
+	var candidates []OrderBookWall
+	var notionals []float64
+	for _, level := range levels {
+		distance := math.Abs(level.Price-mid) / mid * 100
+		if distance > orderBookWallRange {
+			break
+		}
+		notional := level.Price * level.Quantity
+		notionals = append(notionals, notional)
+		candidates = append(candidates, OrderBookWall{Price: level.Price, Notional: notional, DistancePct: distance})
+	}
+	if len(notionals) < 3 {
+		return nil
+	}
+
+	sort.Float64s(notionals)
+	threshold := notionals[len(notionals)/2] * orderBookWallFactor
+
+	var walls []OrderBookWall
+	for _, c := range candidates {
+		if c.Notional >= threshold {
+			walls = append(walls, c)
+		}
+	}
+	sort.Slice(walls, func(i, j int) bool { return walls[i].Notional > walls[j].Notional })
+	if len(walls) > orderBookMaxWalls {
+		walls = walls[:orderBookMaxWalls]
+	}
+	sort.Slice(walls, func(i, j int) bool { return walls[i].DistancePct < walls[j].DistancePct })
+	return walls
+}
+
+// EstimateSlippageBps walks the book for a market order of notional USDT and returns the expected
+// average fill price distance from the mid price in bps (positive = adverse)
+// ok is false when the visible book can't fill the whole order, bps then covers the visible part
+func EstimateSlippageBps(book *OrderBook, buy bool, notional float64) (bps float64, ok bool) {
+	if book == nil || len(book.Bids) == 0 || len(book.Asks) == 0 || notional <= 0 {
+		return 0, false
+	}
+	mid := (book.Bids[0].Price + book.Asks[0].Price) / 2
+
+	levels := book.Bids
+	if buy {
+		levels = book.Asks
+	}
+
+	remaining := notional
+	var filledQty, filledNotional float64
+	for _, level := range levels {
+		take := math.Min(remaining, level.Price*level.Quantity)
+		filledNotional += take
+		filledQty += take / level.Price
+		remaining -= take
+		if remaining <= 0 {
+			break
+		}
+	}
+	if filledQty == 0 {
+		return 0, false
+	}
+
+	avgPrice := filledNotional / filledQty
+	bps = (avgPrice - mid) / mid * 10000
+	if !buy {
+		bps = -bps
+	}
+	return bps, remaining <= 0
+}
+
+// FormatOrderBookFeatures formats order book features for prompts
+func FormatOrderBookFeatures(f *OrderBookFeatures) string {
+	if f == nil {
+		return ""
+	}
+
+	s := fmt.Sprintf("Order Book: spread %.2f bps, top-%d imbalance %+.2f (positive = more bids)\n", f.SpreadBps, f.TopN, f.Imbalance)
+	s += fmt.Sprintf("Depth ±0.5%%: bids %.0f / asks %.0f USDT | ±1%%: bids %.0f / asks %.0f USDT\n",
+		f.BidDepth05, f.AskDepth05, f.BidDepth1, f.AskDepth1)
+	if len(f.BidWalls) > 0 {
+		s += "Bid walls: " + formatWalls(f.BidWalls, "-") + "\n"
+	}
+	if len(f.AskWalls) > 0 {
+		s += "Ask walls: " + formatWalls(f.AskWalls, "+") + "\n"
+	}
+	return s
+}
+
+// formatWalls formats walls as "price (notional, ±distance)"
+func formatWalls(walls []OrderBookWall, sign string) string {
+	s := ""
+	for i, w := range walls {
+		if i > 0 {
+			s += ", "
+		}
+		s += fmt.Sprintf("%s (%.0f USDT, %s%.2f%%)", formatPriceWithDynamicPrecision(w.Price), w.Notional, sign, w.DistancePct)
+	}
+	return s
+}
@@ -0,0 +1,94 @@
+package market
+
+import (
+	"math"
+	"strings"
+	"testing"
+)
+
+// testOrderBook book around 100 with 0.1 price steps, 1 unit per level and a large bid at 99.5
+func testOrderBook() *OrderBook {
+	book := &OrderBook{Symbol: "BTCUSDT"}
+	for i := 0; i < 30; i++ {
+		bid := OrderBookLevel{Price: 99.95 - float64(i)*0.1, Quantity: 1}
+		if i == 4 {
+			bid.Quantity = 20 // Wall at 99.55
+		}
+		book.Bids = append(book.Bids, bid)
+		book.Asks = append(book.Asks, OrderBookLevel{Price: 100.05 + float64(i)*0.1, Quantity: 2})
+	}
+	return book
+}
+
+// TestComputeOrderBookFeatures tests spread, imbalance, depth bands and walls
+func TestComputeOrderBookFeatures(t *testing.T) {
+	f, err := ComputeOrderBookFeatures(testOrderBook(), 5)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if math.Abs(f.MidPrice-100) > 1e-9 {
+		t.Errorf("mid = %v, want 100", f.MidPrice)
+	}
+	if math.Abs(f.SpreadBps-10) > 1e-6 {
+		t.Errorf("spread = %v bps, want 10", f.SpreadBps)
+	}
+	// Top 5: bids 1+1+1+1+20 = 24, asks 5*2 = 10
+	if math.Abs(f.Imbalance-14.0/34) > 1e-9 {
+		t.Errorf("imbalance = %v, want %v", f.Imbalance, 14.0/34)
+	}
+	// Within 0.5%: bids 99.95..99.55 (5 levels), asks 100.05..100.45 (5 levels)
+	wantBid05 := 99.95 + 99.85 + 99.75 + 99.65 + 99.55*20
+	if math.Abs(f.BidDepth05-wantBid05) > 1e-6 {
+		t.Errorf("bid depth 0.5%% = %v, want %v", f.BidDepth05, wantBid05)
+	}
+	if f.AskDepth1 <= f.AskDepth05 {
+		t.Errorf("ask depth 1%% (%v) should exceed 0.5%% (%v)", f.AskDepth1, f.AskDepth05)
+	}
+
+	if len(f.BidWalls) != 1 || math.Abs(f.BidWalls[0].Price-99.55) > 1e-9 {
+		t.Fatalf("bid walls = %+v, want one at 99.55", f.BidWalls)
+	}
+	if len(f.AskWalls) != 0 {
+		t.Errorf("ask walls = %+v, want none on a flat book", f.AskWalls)
+	}
+
+	text := FormatOrderBookFeatures(f)
+	if !strings.Contains(text, "spread 10.00 bps") || !strings.Contains(text, "Bid walls: 99.55") {
+		t.Errorf("unexpected format: %s", text)
+	}
+
+	if _, err := ComputeOrderBookFeatures(&OrderBook{}, 5); err == nil {
+		t.Error("expected error for empty book")
+	}
+}
+
+// TestEstimateSlippageBps tests walking the book for market orders
+func TestEstimateSlippageBps(t *testing.T) {
+	book := testOrderBook()
+
+	// Buy fills entirely at the best ask: 5 bps above mid
+	bps, ok := EstimateSlippageBps(book, true, 100)
+	if !ok || math.Abs(bps-5) > 1e-6 {
+		t.Errorf("small buy = %v bps (ok=%v), want 5", bps, ok)
+	}
+
+	// Sell of two levels: 1 @ 99.95 and 1 @ 99.85 -> worse than the best bid
+	bps, ok = EstimateSlippageBps(book, false, 99.95+99.85)
+	if !ok || math.Abs(bps-10) > 1e-6 {
+		t.Errorf("two-level sell = %v bps (ok=%v), want 10", bps, ok)
+	}
+
+	// Larger than the visible book
+	if _, ok := EstimateSlippageBps(book, true, 1e9); ok {
+		t.Error("expected incomplete estimate for an order larger than the book")
+	}
+}
+
+// TestParseDepthLevels tests parsing of depth endpoint levels
+func TestParseDepthLevels(t *testing.T) {
+	levels := parseDepthLevels([][]string{{"100.5", "2"}, {"bad", "1"}, {"101", "0"}, {"102"}})
+	if len(levels) != 1 || levels[0].Price != 100.5 || levels[0].Quantity != 2 {
+		t.Errorf("levels = %+v, want one level 100.5 x 2", levels)
+	}
+}
@@ -0,0 +1,71 @@
+package market
+
+import (
+	"fmt"
+	"time"
+)
+
+// BarSchedule decision cadence aligned to candle closes, shared by live trading and backtests
+// so both decide on the same bars: bar 0 is the first closed bar of the run, then every CadenceNBars bars.
+type BarSchedule struct {
+	Timeframe    string
+	CadenceNBars int           // Decide every N closed bars (<= 1 means every bar)
+	Offset       time.Duration // Delay after the close, leaves time for the final kline to arrive
+	Jitter       time.Duration // Extra per-trader delay, spreads API calls of traders sharing a timeframe
+}
+
+// IsDecisionBar reports whether the bar at barIndex (0 = first bar of the run) is a decision bar
+func IsDecisionBar(barIndex, cadenceNBars int) bool {
+	if cadenceNBars <= 1 || barIndex < 0 {
+		return true
+	}
+	return barIndex%cadenceNBars == 0
+}
+
+// LastBarClose returns the close time of the most recent bar of tf closed at or before t
+func LastBarClose(tf string, t time.Time) (time.Time, error) {
+	dur, err := TFDuration(tf)
+	if err != nil {
+		return time.Time{}, err
+	}
+	ms := t.UnixMilli()
+	return time.UnixMilli(ms - ms%dur.Milliseconds()), nil
+}
+
+// NextDecisionTime returns the first decision time after now
+// anchor is the close of bar 0 (see LastBarClose)
+func (s BarSchedule) NextDecisionTime(anchor, now time.Time) (time.Time, error) {
+	dur, err := TFDuration(s.Timeframe)
+	if err != nil {
+		return time.Time{}, err
+	}
+	step := dur
+	if s.CadenceNBars > 1 {
+		step = dur * time.Duration(s.CadenceNBars)
+	}
+	delay := s.Offset + s.Jitter
+	if delay >= step {
+		return time.Time{}, fmt.Errorf("close offset + jitter (%v) must be shorter than the decision cadence (%v)", delay, step)
+	}
+
+	// Number of decision bars already past (their decision time included)
+	elapsed := now.Sub(anchor.Add(delay))
+	bars := int64(0)
+	if elapsed >= 0 {
+		bars = int64(elapsed/step) + 1
+	}
+	return anchor.Add(time.Duration(bars) * step).Add(delay), nil
+}
+
+// IsKlineClosed reports whether the kline had closed at time t
+func IsKlineClosed(k Kline, t time.Time) bool {
+	return k.CloseTime < t.UnixMilli()
+}
+
+// dropUnclosedKline removes the still-forming last kline, if any
+func dropUnclosedKline(klines []Kline, now time.Time) []Kline {
+	if len(klines) > 0 && !IsKlineClosed(klines[len(klines)-1], now) {
+		return klines[:len(klines)-1]
+	}
+	return klines
+}
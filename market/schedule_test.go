@@ -0,0 +1,83 @@
+package market
+
+import (
+	"testing"
+	"time"
+)
+
+func TestIsDecisionBar(t *testing.T) {
+	tests := []struct {
+		barIndex int
+		cadence  int
+		want     bool
+	}{
+		{0, 0, true},
+		{7, 1, true},
+		{0, 4, true},
+		{3, 4, false},
+		{8, 4, true},
+		{-1, 4, true},
+	}
+	for _, tt := range tests {
+		if got := IsDecisionBar(tt.barIndex, tt.cadence); got != tt.want {
+			t.Errorf("IsDecisionBar(%d, %d) = %v, want %v", tt.barIndex, tt.cadence, got, tt.want)
+		}
+	}
+}
+
+func TestLastBarClose(t *testing.T) {
+	now := time.Date(2025, 1, 1, 10, 17, 42, 0, time.UTC)
+	got, err := LastBarClose("15m", now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := time.Date(2025, 1, 1, 10, 15, 0, 0, time.UTC); !got.Equal(want) {
+		t.Errorf("LastBarClose = %v, want %v", got, want)
+	}
+
+	if _, err := LastBarClose("7m", now); err == nil {
+		t.Error("expected error for unsupported timeframe")
+	}
+}
+
+func TestBarScheduleNextDecisionTime(t *testing.T) {
+	anchor := time.Date(2025, 1, 1, 10, 0, 0, 0, time.UTC)
+	schedule := BarSchedule{Timeframe: "15m", CadenceNBars: 2, Offset: 5 * time.Second, Jitter: 2 * time.Second}
+
+	tests := []struct {
+		name string
+		now  time.Time
+		want time.Time
+	}{
+		{"before bar 0 decision", anchor.Add(3 * time.Second), anchor.Add(7 * time.Second)},
+		{"at bar 0 decision", anchor.Add(7 * time.Second), anchor.Add(30*time.Minute + 7*time.Second)},
+		{"bar 1 is skipped", anchor.Add(20 * time.Minute), anchor.Add(30*time.Minute + 7*time.Second)},
+		{"after bar 2 decision", anchor.Add(31 * time.Minute), anchor.Add(60*time.Minute + 7*time.Second)},
+	}
+	for _, tt := range tests {
+		got, err := schedule.NextDecisionTime(anchor, tt.now)
+		if err != nil {
+			t.Fatalf("%s: %v", tt.name, err)
+		}
+		if !got.Equal(tt.want) {
+			t.Errorf("%s: got %v, want %v", tt.name, got, tt.want)
+		}
+	}
+
+	tooLate := BarSchedule{Timeframe: "1m", Offset: 50 * time.Second, Jitter: 20 * time.Second}
+	if _, err := tooLate.NextDecisionTime(anchor, anchor); err == nil {
+		t.Error("expected error when offset + jitter exceeds the cadence")
+	}
+}
+
+func TestDropUnclosedKline(t *testing.T) {
+	klines := generateTestKlines(3)
+	lastClose := time.UnixMilli(klines[2].CloseTime)
+
+	if got := dropUnclosedKline(klines, lastClose); len(got) != 2 {
+		t.Errorf("forming kline should be dropped, got %d klines", len(got))
+	}
+	if got := dropUnclosedKline(klines, lastClose.Add(time.Millisecond)); len(got) != 3 {
+		t.Errorf("closed kline should be kept, got %d klines", len(got))
+	}
+}
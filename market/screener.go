@@ -0,0 +1,331 @@
+package market
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	screenerDefaultLimit   = 10
+	screenerDefaultOIHours = 24
+	screenerMaxOIRequests  = 50 // OI history is fetched per symbol, only for the highest-volume survivors
+)
+
+// ScreenerFilters filters and ranking of the built-in coin screener
+// Pointer bounds are optional, nil means no bound
+type ScreenerFilters struct {
+	MinQuoteVolume     float64  // Min 24h quote volume (USDT)
+	MinVolatilityPct   float64  // Min 24h high-low range as % of the last price
+	MaxVolatilityPct   float64  // Max 24h range, 0 = no upper bound
+	MinFundingRatePct  *float64 // Funding rate band in % per funding interval
+	MaxFundingRatePct  *float64
+	MinOIChangePct     *float64 // Open interest change over OIChangeHours in %
+	MaxOIChangePct     *float64
+	OIChangeHours      int    // Default 24
+	MaxPriceChangeRank int    // Keep only the top N coins by absolute 24h price change, 0 = no filter
+	MinListingDays     int    // Min days since listing
+	RankBy             string // "volume" (default) | "price_change" | "volatility" | "oi_change"
+	Limit              int    // Max results, default 10
+}
+
+// ScreenerRow a symbol of the exchange universe with its 24h statistics
+type ScreenerRow struct {
+	Symbol         string
+	LastPrice      float64
+	HighPrice      float64
+	LowPrice       float64
+	PriceChangePct float64 // 24h
+	QuoteVolume    float64 // 24h (USDT)
+	FundingRate    float64 // Last funding rate (fraction)
+	OnboardDate    int64   // Listing time (ms), 0 = unknown
+}
+
+// ScreenerResult a symbol that passed the screener
+type ScreenerResult struct {
+	Symbol          string   `json:"symbol"`
+	QuoteVolume     float64  `json:"quote_volume"`
+	PriceChangePct  float64  `json:"price_change_pct"`
+	PriceChangeRank int      `json:"price_change_rank"` // 1 = largest absolute 24h move in the universe
+	VolatilityPct   float64  `json:"volatility_pct"`
+	FundingRate     float64  `json:"funding_rate"`
+	OIChangePct     *float64 `json:"oi_change_pct,omitempty"`
+	ListingDays     int      `json:"listing_days"` // -1 = unknown
+	Reasons         []string `json:"reasons"`
+}
+
+// RunScreener scans the exchange's USDT perpetual universe and returns the ranked symbols that pass the filters
+func RunScreener(filters ScreenerFilters) ([]ScreenerResult, error) {
+	client := NewAPIClient()
+	rows, err := client.GetScreenerUniverse()
+	if err != nil {
+		return nil, err
+	}
+
+	hours := filters.OIChangeHours
+	if hours <= 0 {
+		hours = screenerDefaultOIHours
+	}
+	oiChange := func(symbol string) (float64, error) {
+		return client.GetOpenInterestChange(symbol, hours)
+	}
+	return ScreenCoins(rows, filters, time.Now(), oiChange), nil
+}
+
+// ScreenCoins applies the filters to the universe and ranks the survivors
+// oiChange is only called when an OI bound is set or results are ranked by OI change
+func ScreenCoins(rows []ScreenerRow, filters ScreenerFilters, now time.Time, oiChange func(symbol string) (float64, error)) []ScreenerResult {
+	// Price change rank over the whole universe
+	ranked := make([]ScreenerRow, len(rows))
+	copy(ranked, rows)
+	sort.Slice(ranked, func(i, j int) bool {
+		return math.Abs(ranked[i].PriceChangePct) > math.Abs(ranked[j].PriceChangePct)
+	})
+	changeRank := make(map[string]int, len(ranked))
+	for i, row := range ranked {
+		changeRank[row.Symbol] = i + 1
+	}
+
+	var results []ScreenerResult
+	for _, row := range rows {
+		if row.LastPrice <= 0 || row.QuoteVolume < filters.MinQuoteVolume {
+			continue
+		}
+
+		volatility := (row.HighPrice - row.LowPrice) / row.LastPrice * 100
+		if volatility < filters.MinVolatilityPct || (filters.MaxVolatilityPct > 0 && volatility > filters.MaxVolatilityPct) {
+			continue
+		}
+
+		fundingPct := row.FundingRate * 100
+		if !withinBounds(fundingPct, filters.MinFundingRatePct, filters.MaxFundingRatePct) {
+			continue
+		}
+
+		rank := changeRank[row.Symbol]
+		if filters.MaxPriceChangeRank > 0 && rank > filters.MaxPriceChangeRank {
+			continue
+		}
+
+		listingDays := -1
+		if row.OnboardDate > 0 {
+			listingDays = int(now.Sub(time.UnixMilli(row.OnboardDate)).Hours() / 24)
+		}
+		if filters.MinListingDays > 0 && (listingDays < 0 || listingDays < filters.MinListingDays) {
+			continue
+		}
+
+		results = append(results, ScreenerResult{
+			Symbol:          row.Symbol,
+			QuoteVolume:     row.QuoteVolume,
+			PriceChangePct:  row.PriceChangePct,
+			PriceChangeRank: rank,
+			VolatilityPct:   volatility,
+			FundingRate:     row.FundingRate,
+			ListingDays:     listingDays,
+		})
+	}
+
+	// OI change needs a request per symbol, check the most liquid survivors only
+	needOI := filters.MinOIChangePct != nil || filters.MaxOIChangePct != nil || filters.RankBy == "oi_change"
+	if needOI && oiChange != nil {
+		sort.Slice(results, func(i, j int) bool { return results[i].QuoteVolume > results[j].QuoteVolume })
+		if len(results) > screenerMaxOIRequests {
+			results = results[:screenerMaxOIRequests]
+		}
+
+		filtered := results[:0]
+		for _, r := range results {
+			change, err := oiChange(r.Symbol)
+			if err != nil {
+				continue
+			}
+			if !withinBounds(change, filters.MinOIChangePct, filters.MaxOIChangePct) {
+				continue
+			}
+			r.OIChangePct = &change
+			filtered = append(filtered, r)
+		}
+		results = filtered
+	}
+
+	sortScreenerResults(results, filters.RankBy)
+
+	limit := filters.Limit
+	if limit <= 0 {
+		limit = screenerDefaultLimit
+	}
+	if len(results) > limit {
+		results = results[:limit]
+	}
+
+	hours := filters.OIChangeHours
+	if hours <= 0 {
+		hours = screenerDefaultOIHours
+	}
+	for i := range results {
+		results[i].Reasons = screenerReasons(results[i], hours)
+	}
+	return results
+}
+
+// withinBounds reports whether v lies within the optional bounds
+func withinBounds(v float64, min, max *float64) bool {
+	if min != nil && v < *min {
+		return false
+	}
+	if max != nil && v > *max {
+		return false
+	}
+	return true
+}
+
+// sortScreenerResults sorts results by the ranking key, highest first
+func sortScreenerResults(results []ScreenerResult, rankBy string) {
+	key := func(r ScreenerResult) float64 {
+		switch rankBy {
+		case "price_change":
+			return math.Abs(r.PriceChangePct)
+		case "volatility":
+			return r.VolatilityPct
+		case "oi_change":
+			if r.OIChangePct != nil {
+				return *r.OIChangePct
+			}
+			return math.Inf(-1)
+		default:
+			return r.QuoteVolume
+		}
+	}
+	sort.SliceStable(results, func(i, j int) bool { return key(results[i]) > key(results[j]) })
+}
+
+// screenerReasons describes why a symbol was selected, shown in the prompt
+func screenerReasons(r ScreenerResult, oiHours int) []string {
+	reasons := []string{
+		fmt.Sprintf("24h volume %s USDT", formatCompactNumber(r.QuoteVolume)),
+		fmt.Sprintf("24h change %+.2f%% (#%d mover)", r.PriceChangePct, r.PriceChangeRank),
+		fmt.Sprintf("24h range %.2f%%", r.VolatilityPct),
+		fmt.Sprintf("funding %+.4f%%", r.FundingRate*100),
+	}
+	if r.OIChangePct != nil {
+		reasons = append(reasons, fmt.Sprintf("OI %+.2f%% over %dh", *r.OIChangePct, oiHours))
+	}
+	if r.ListingDays >= 0 {
+		reasons = append(reasons, fmt.Sprintf("listed %dd ago", r.ListingDays))
+	}
+	return reasons
+}
+
+// formatCompactNumber formats large numbers as 1.23B / 45.6M / 7.8K
+func formatCompactNumber(v float64) string {
+	switch abs := math.Abs(v); {
+	case abs >= 1e9:
+		return fmt.Sprintf("%.2fB", v/1e9)
+	case abs >= 1e6:
+		return fmt.Sprintf("%.1fM", v/1e6)
+	case abs >= 1e3:
+		return fmt.Sprintf("%.1fK", v/1e3)
+	default:
+		return fmt.Sprintf("%.0f", v)
+	}
+}
+
+// GetScreenerUniverse retrieves 24h statistics, funding rates and listing dates of all trading USDT perpetuals
+func (c *APIClient) GetScreenerUniverse() ([]ScreenerRow, error) {
+	info, err := c.GetExchangeInfo()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get exchange info: %w", err)
+	}
+	onboard := make(map[string]int64)
+	for _, s := range info.Symbols {
+		if s.Status == "TRADING" && s.ContractType == "PERPETUAL" && strings.HasSuffix(s.Symbol, "USDT") {
+			onboard[s.Symbol] = s.OnboardDate
+		}
+	}
+
+	var tickers []struct {
+		Symbol             string `json:"symbol"`
+		LastPrice          string `json:"lastPrice"`
+		HighPrice          string `json:"highPrice"`
+		LowPrice           string `json:"lowPrice"`
+		PriceChangePercent string `json:"priceChangePercent"`
+		QuoteVolume        string `json:"quoteVolume"`
+	}
+	if err := c.getJSON(fmt.Sprintf("%s/fapi/v1/ticker/24hr", baseURL), &tickers); err != nil {
+		return nil, fmt.Errorf("failed to get 24h tickers: %w", err)
+	}
+
+	var premium []struct {
+		Symbol          string `json:"symbol"`
+		LastFundingRate string `json:"lastFundingRate"`
+	}
+	if err := c.getJSON(fmt.Sprintf("%s/fapi/v1/premiumIndex", baseURL), &premium); err != nil {
+		return nil, fmt.Errorf("failed to get funding rates: %w", err)
+	}
+	funding := make(map[string]float64, len(premium))
+	for _, p := range premium {
+		funding[p.Symbol], _ = strconv.ParseFloat(p.LastFundingRate, 64)
+	}
+
+	rows := make([]ScreenerRow, 0, len(onboard))
+	for _, t := range tickers {
+		onboardDate, ok := onboard[t.Symbol]
+		if !ok {
+			continue
+		}
+		row := ScreenerRow{Symbol: t.Symbol, FundingRate: funding[t.Symbol], OnboardDate: onboardDate}
+		row.LastPrice, _ = strconv.ParseFloat(t.LastPrice, 64)
+		row.HighPrice, _ = strconv.ParseFloat(t.HighPrice, 64)
+		row.LowPrice, _ = strconv.ParseFloat(t.LowPrice, 64)
+		row.PriceChangePct, _ = strconv.ParseFloat(t.PriceChangePercent, 64)
+		row.QuoteVolume, _ = strconv.ParseFloat(t.QuoteVolume, 64)
+		rows = append(rows, row)
+	}
+	return rows, nil
+}
+
+// GetOpenInterestChange returns the open interest change of a symbol over the last hours in %
+func (c *APIClient) GetOpenInterestChange(symbol string, hours int) (float64, error) {
+	var hist []struct {
+		SumOpenInterest string `json:"sumOpenInterest"`
+		Timestamp       int64  `json:"timestamp"`
+	}
+	url := fmt.Sprintf("%s/futures/data/openInterestHist?symbol=%s&period=1h&limit=%d", baseURL, symbol, hours+1)
+	if err := c.getJSON(url, &hist); err != nil {
+		return 0, err
+	}
+	if len(hist) < 2 {
+		return 0, fmt.Errorf("not enough open interest history for %s", symbol)
+	}
+
+	first, _ := strconv.ParseFloat(hist[0].SumOpenInterest, 64)
+	last, _ := strconv.ParseFloat(hist[len(hist)-1].SumOpenInterest, 64)
+	if first <= 0 {
+		return 0, fmt.Errorf("invalid open interest history for %s", symbol)
+	}
+	return (last - first) / first * 100, nil
+}
+
+// getJSON performs a GET request and decodes the JSON response
+func (c *APIClient) getJSON(url string, v interface{}) error {
+	resp, err := c.client.Get(url)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode != 200 {
+		return fmt.Errorf("request failed (status %d): %s", resp.StatusCode, string(body))
+	}
+	return json.Unmarshal(body, v)
+}
@@ -0,0 +1,89 @@
+package market
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+)
+
+func screenerUniverse(now time.Time) []ScreenerRow {
+	listed := func(days int) int64 { return now.Add(-time.Duration(days) * 24 * time.Hour).UnixMilli() }
+	return []ScreenerRow{
+		{Symbol: "BTCUSDT", LastPrice: 100, HighPrice: 102, LowPrice: 99, PriceChangePct: 1.5, QuoteVolume: 5e9, FundingRate: 0.0001, OnboardDate: listed(1500)},
+		{Symbol: "ETHUSDT", LastPrice: 100, HighPrice: 105, LowPrice: 97, PriceChangePct: -4, QuoteVolume: 2e9, FundingRate: -0.0002, OnboardDate: listed(1400)},
+		{Symbol: "SOLUSDT", LastPrice: 100, HighPrice: 112, LowPrice: 98, PriceChangePct: 9, QuoteVolume: 8e8, FundingRate: 0.0008, OnboardDate: listed(900)},
+		{Symbol: "NEWUSDT", LastPrice: 100, HighPrice: 140, LowPrice: 90, PriceChangePct: 35, QuoteVolume: 3e8, FundingRate: 0.0001, OnboardDate: listed(3)},
+		{Symbol: "DEADUSDT", LastPrice: 100, HighPrice: 100.5, LowPrice: 99.8, PriceChangePct: 0.1, QuoteVolume: 1e6, FundingRate: 0, OnboardDate: listed(700)},
+	}
+}
+
+// TestScreenCoins_Filters tests volume, volatility, funding, listing age and price change rank filters
+func TestScreenCoins_Filters(t *testing.T) {
+	now := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+	maxFunding := 0.05
+
+	results := ScreenCoins(screenerUniverse(now), ScreenerFilters{
+		MinQuoteVolume:     1e8,         // Drops DEAD
+		MinVolatilityPct:   2.5,         // BTC range is 3%
+		MaxVolatilityPct:   40,          // Drops NEW (50%)
+		MaxFundingRatePct:  &maxFunding, // Drops SOL (0.08%)
+		MaxPriceChangeRank: 4,           // Drops DEAD (#5)
+		MinListingDays:     30,          // Drops NEW
+	}, now, nil)
+
+	var symbols []string
+	for _, r := range results {
+		symbols = append(symbols, r.Symbol)
+	}
+	if strings.Join(symbols, ",") != "BTCUSDT,ETHUSDT" {
+		t.Fatalf("results = %v, want BTCUSDT,ETHUSDT ranked by volume", symbols)
+	}
+	if results[1].PriceChangeRank != 3 || results[1].ListingDays != 1400 {
+		t.Errorf("ETH rank %d listing %d, want 3 and 1400", results[1].PriceChangeRank, results[1].ListingDays)
+	}
+	reasons := strings.Join(results[0].Reasons, ", ")
+	if !strings.Contains(reasons, "24h volume 5.00B USDT") || !strings.Contains(reasons, "funding +0.0100%") {
+		t.Errorf("unexpected reasons: %s", reasons)
+	}
+}
+
+// TestScreenCoins_OIChange tests the OI filter, ranking by OI change and the limit
+func TestScreenCoins_OIChange(t *testing.T) {
+	now := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+	oi := map[string]float64{"BTCUSDT": 2, "ETHUSDT": 12, "SOLUSDT": 25, "NEWUSDT": 60}
+	calls := 0
+	oiChange := func(symbol string) (float64, error) {
+		calls++
+		change, ok := oi[symbol]
+		if !ok {
+			return 0, fmt.Errorf("no history")
+		}
+		return change, nil
+	}
+	minOI := 5.0
+
+	results := ScreenCoins(screenerUniverse(now), ScreenerFilters{
+		MinOIChangePct: &minOI,
+		OIChangeHours:  4,
+		RankBy:         "oi_change",
+		Limit:          2,
+	}, now, oiChange)
+
+	if len(results) != 2 || results[0].Symbol != "NEWUSDT" || results[1].Symbol != "SOLUSDT" {
+		t.Fatalf("results = %+v, want NEWUSDT and SOLUSDT", results)
+	}
+	if calls != 5 {
+		t.Errorf("oi requests = %d, want 5", calls)
+	}
+	if !strings.Contains(strings.Join(results[0].Reasons, ", "), "OI +60.00% over 4h") {
+		t.Errorf("unexpected reasons: %v", results[0].Reasons)
+	}
+
+	// Without OI bounds or ranking, OI history isn't requested
+	calls = 0
+	ScreenCoins(screenerUniverse(now), ScreenerFilters{RankBy: "price_change"}, now, oiChange)
+	if calls != 0 {
+		t.Errorf("oi requests = %d, want 0", calls)
+	}
+}
@@ -17,6 +17,13 @@ type Data struct {
 	LongerTermContext *LongerTermData
 	// Multi-timeframe data (new)
 	TimeframeData map[string]*TimeframeSeriesData `json:"timeframe_data,omitempty"`
+	// Order book liquidity features (nil unless IndicatorConfig.EnableOrderBook)
+	OrderBook *OrderBookFeatures `json:"order_book,omitempty"`
+	// Recent liquidations over rolling windows (nil unless IndicatorConfig.EnableLiquidations)
+	Liquidations *LiquidationStats `json:"liquidations,omitempty"`
+	// Whether the last primary timeframe candle had closed when the data was fetched
+	LastKlineClosed    bool
+	LastKlineCloseTime int64 // Close time of the last primary candle (milliseconds)
 }
 
 // KlineBar single kline bar with OHLCV data
@@ -41,6 +48,19 @@ type TimeframeSeriesData struct {
 	RSI14Values []float64  `json:"rsi14_values"` // RSI14 series
 	Volume      []float64  `json:"volume"`       // Volume series (deprecated, use Klines)
 	ATR14       float64    `json:"atr14"`        // ATR14
+	// Registry indicators selected by the strategy (see IndicatorSpec)
+	Indicators []IndicatorSeries `json:"indicators,omitempty"`
+	// User-defined expression series by name (see ExpressionSpec)
+	Expressions map[string][]float64 `json:"expressions,omitempty"`
+	// Taker buy/sell volume, CVD and large trades (see TakerFlowSeries)
+	TakerFlow *TakerFlowSeries `json:"taker_flow,omitempty"`
+}
+
+// SeriesOptions strategy-selected series computed for every timeframe in addition to the built-in ones
+type SeriesOptions struct {
+	Indicators  []IndicatorSpec
+	Expressions []ExpressionSpec
+	TakerFlow   bool // Taker buy/sell volume and CVD, live data adds aggTrade large-trade counts
 }
 
 // OIData Open Interest data
@@ -85,6 +105,7 @@ type SymbolInfo struct {
 	ContractType      string `json:"contractType"`
 	PricePrecision    int    `json:"pricePrecision"`
 	QuantityPrecision int    `json:"quantityPrecision"`
+	OnboardDate       int64  `json:"onboardDate"` // Listing time (ms)
 }
 
 type Kline struct {
@@ -28,6 +28,10 @@ type DecisionRecord struct {
 	Success             bool               `json:"success"`
 	ErrorMessage        string             `json:"error_message"`
 	AIRequestDurationMs int64              `json:"ai_request_duration_ms"`
+	TriggerReason       string             `json:"trigger_reason,omitempty"` // Why an out-of-band cycle started (empty = scheduled)
+	MarketRegime        string             `json:"market_regime,omitempty"`  // Market (BTC) regime label of the cycle (empty = not classified)
+	Regimes             map[string]string  `json:"regimes,omitempty"`        // Regime label per symbol
+	Gated               bool               `json:"gated,omitempty"`          // Pre-AI gate recorded a wait without calling the AI
 	AccountState        AccountSnapshot    `json:"account_state"`
 	Positions           []PositionSnapshot `json:"positions"`
 	Decisions           []DecisionAction   `json:"decisions"`
@@ -57,15 +61,21 @@ type PositionSnapshot struct {
 
 // DecisionAction decision action
 type DecisionAction struct{
-	Action    string    `json:"action"`
-	Symbol    string    `json:"symbol"`
-	Quantity  float64   `json:"quantity"`
-	Leverage  int       `json:"leverage"`
-	Price     float64   `json:"price"`
-	OrderID   int64     `json:"order_id"`
-	Timestamp time.Time `json:"timestamp"`
-	Success   bool      `json:"success"`
-	Error     string    `json:"error"`
+	Action        string    `json:"action"`
+	Symbol        string    `json:"symbol"`
+	Quantity      float64   `json:"quantity"`
+	Leverage      int       `json:"leverage"`
+	Price         float64   `json:"price"`                    // Mark price at submission
+	DecisionPrice float64   `json:"decision_price,omitempty"` // Mark price the AI decided on
+	FillPrice     float64   `json:"fill_price,omitempty"`     // Average fill price
+	SlippageBps   float64   `json:"slippage_bps,omitempty"`   // Decision price -> fill price (positive = adverse)
+	// Order book estimate before submission: average fill vs. mid price in bps (positive = adverse, nil = not estimated)
+	EstimatedSlippageBps *float64 `json:"estimated_slippage_bps,omitempty"`
+	OrderID       int64     `json:"order_id"`
+	ClientOrderID string    `json:"client_order_id,omitempty"` // Deterministic client order ID sent to exchange
+	Timestamp     time.Time `json:"timestamp"`
+	Success       bool      `json:"success"`
+	Error         string    `json:"error"`
 }
 
 // Statistics statistics information
@@ -112,6 +122,13 @@ func (s *DecisionStore) initTables() error {
 
 	// Migration: add raw_response column if not exists
 	s.db.Exec(`ALTER TABLE decision_records ADD COLUMN raw_response TEXT DEFAULT ''`)
+	// Migration: add trigger_reason column if not exists
+	s.db.Exec(`ALTER TABLE decision_records ADD COLUMN trigger_reason TEXT DEFAULT ''`)
+	// Migration: add regime columns if not exists
+	s.db.Exec(`ALTER TABLE decision_records ADD COLUMN market_regime TEXT DEFAULT ''`)
+	s.db.Exec(`ALTER TABLE decision_records ADD COLUMN regimes TEXT DEFAULT ''`)
+	// Migration: add gated column if not exists
+	s.db.Exec(`ALTER TABLE decision_records ADD COLUMN gated BOOLEAN DEFAULT 0`)
 
 	return nil
 }
@@ -127,19 +144,25 @@ func (s *DecisionStore) LogDecision(record *DecisionRecord) error {
 	// Serialize candidate coins and execution log to JSON
 	candidateCoinsJSON, _ := json.Marshal(record.CandidateCoins)
 	executionLogJSON, _ := json.Marshal(record.ExecutionLog)
+	var regimesJSON []byte
+	if len(record.Regimes) > 0 {
+		regimesJSON, _ = json.Marshal(record.Regimes)
+	}
 
 	// Insert decision record main table (only save AI decision related content)
 	result, err := s.db.Exec(`
 		INSERT INTO decision_records (
 			trader_id, cycle_number, timestamp, system_prompt, input_prompt,
 			cot_trace, decision_json, raw_response, candidate_coins, execution_log,
-			success, error_message, ai_request_duration_ms
-		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+			success, error_message, ai_request_duration_ms, trigger_reason,
+			market_regime, regimes, gated
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
 	`,
 		record.TraderID, record.CycleNumber, record.Timestamp.Format(time.RFC3339),
 		record.SystemPrompt, record.InputPrompt, record.CoTTrace, record.DecisionJSON,
 		record.RawResponse, string(candidateCoinsJSON), string(executionLogJSON),
-		record.Success, record.ErrorMessage, record.AIRequestDurationMs,
+		record.Success, record.ErrorMessage, record.AIRequestDurationMs, record.TriggerReason,
+		record.MarketRegime, string(regimesJSON), record.Gated,
 	)
 	if err != nil {
 		return fmt.Errorf("failed to insert decision record: %w", err)
@@ -159,7 +182,8 @@ func (s *DecisionStore) GetLatestRecords(traderID string, n int) ([]*DecisionRec
 	rows, err := s.db.Query(`
 		SELECT id, trader_id, cycle_number, timestamp, system_prompt, input_prompt,
 			   cot_trace, decision_json, candidate_coins, execution_log,
-			   success, error_message, ai_request_duration_ms
+			   success, error_message, ai_request_duration_ms, COALESCE(trigger_reason, ''),
+			   COALESCE(market_regime, ''), COALESCE(regimes, ''), COALESCE(gated, 0)
 		FROM decision_records
 		WHERE trader_id = ?
 		ORDER BY timestamp DESC
@@ -197,7 +221,8 @@ func (s *DecisionStore) GetAllLatestRecords(n int) ([]*DecisionRecord, error) {
 	rows, err := s.db.Query(`
 		SELECT id, trader_id, cycle_number, timestamp, system_prompt, input_prompt,
 			   cot_trace, decision_json, candidate_coins, execution_log,
-			   success, error_message, ai_request_duration_ms
+			   success, error_message, ai_request_duration_ms, COALESCE(trigger_reason, ''),
+			   COALESCE(market_regime, ''), COALESCE(regimes, ''), COALESCE(gated, 0)
 		FROM decision_records
 		ORDER BY timestamp DESC
 		LIMIT ?
@@ -231,7 +256,8 @@ func (s *DecisionStore) GetRecordsByDate(traderID string, date time.Time) ([]*De
 	rows, err := s.db.Query(`
 		SELECT id, trader_id, cycle_number, timestamp, system_prompt, input_prompt,
 			   cot_trace, decision_json, candidate_coins, execution_log,
-			   success, error_message, ai_request_duration_ms
+			   success, error_message, ai_request_duration_ms, COALESCE(trigger_reason, ''),
+			   COALESCE(market_regime, ''), COALESCE(regimes, ''), COALESCE(gated, 0)
 		FROM decision_records
 		WHERE trader_id = ? AND DATE(timestamp) = ?
 		ORDER BY timestamp ASC
@@ -253,6 +279,50 @@ func (s *DecisionStore) GetRecordsByDate(traderID string, date time.Time) ([]*De
 	return records, nil
 }
 
+// FindActionReasoning finds the reasoning of the decision on a symbol's action nearest to a time (within window)
+// Records are saved after the cycle executes, so the matching record can be slightly later than the order. Also returns the symbol's regime in that cycle (market regime when the symbol wasn't classified); empty when not found
+func (s *DecisionStore) FindActionReasoning(traderID, symbol, action string, at time.Time, window time.Duration) (string, string, error) {
+	rows, err := s.db.Query(`
+		SELECT decision_json, COALESCE(market_regime, ''), COALESCE(regimes, '')
+		FROM decision_records
+		WHERE trader_id = ? AND decision_json != ''
+			AND julianday(timestamp) BETWEEN julianday(?) AND julianday(?)
+		ORDER BY ABS(julianday(timestamp) - julianday(?))
+	`, traderID, at.Add(-window).UTC().Format(time.RFC3339), at.Add(window).UTC().Format(time.RFC3339),
+		at.UTC().Format(time.RFC3339))
+	if err != nil {
+		return "", "", fmt.Errorf("failed to query decision records: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var decisionJSON, marketRegime, regimesJSON string
+		if err := rows.Scan(&decisionJSON, &marketRegime, &regimesJSON); err != nil {
+			continue
+		}
+		var decisions []struct {
+			Symbol    string `json:"symbol"`
+			Action    string `json:"action"`
+			Reasoning string `json:"reasoning"`
+		}
+		if json.Unmarshal([]byte(decisionJSON), &decisions) != nil {
+			continue
+		}
+		for _, d := range decisions {
+			if d.Symbol != symbol || d.Action != action {
+				continue
+			}
+			regime := marketRegime
+			var regimes map[string]string
+			if regimesJSON != "" && json.Unmarshal([]byte(regimesJSON), &regimes) == nil && regimes[symbol] != "" {
+				regime = regimes[symbol]
+			}
+			return d.Reasoning, regime, nil
+		}
+	}
+	return "", "", nil
+}
+
 // CleanOldRecords cleans old records from N days ago
 func (s *DecisionStore) CleanOldRecords(traderID string, days int) (int64, error) {
 	cutoffTime := time.Now().AddDate(0, 0, -days).Format(time.RFC3339)
@@ -322,6 +392,18 @@ func (s *DecisionStore) GetAllStatistics() (*Statistics, error) {
 	return stats, nil
 }
 
+// CountGated counts the cycles of a trader where the pre-AI gate skipped the AI call
+func (s *DecisionStore) CountGated(traderID string) (int, error) {
+	var count int
+	err := s.db.QueryRow(`
+		SELECT COUNT(*) FROM decision_records WHERE trader_id = ? AND gated = 1
+	`, traderID).Scan(&count)
+	if err != nil {
+		return 0, fmt.Errorf("failed to count gated cycles: %w", err)
+	}
+	return count, nil
+}
+
 // GetLastCycleNumber gets the last cycle number for specified trader
 func (s *DecisionStore) GetLastCycleNumber(traderID string) (int, error) {
 	var cycleNumber int
@@ -338,13 +420,14 @@ func (s *DecisionStore) GetLastCycleNumber(traderID string) (int, error) {
 func (s *DecisionStore) scanDecisionRecord(rows *sql.Rows) (*DecisionRecord, error) {
 	var record DecisionRecord
 	var timestampStr string
-	var candidateCoinsJSON, executionLogJSON string
+	var candidateCoinsJSON, executionLogJSON, regimesJSON string
 
 	err := rows.Scan(
 		&record.ID, &record.TraderID, &record.CycleNumber, &timestampStr,
 		&record.SystemPrompt, &record.InputPrompt, &record.CoTTrace,
 		&record.DecisionJSON, &candidateCoinsJSON, &executionLogJSON,
-		&record.Success, &record.ErrorMessage, &record.AIRequestDurationMs,
+		&record.Success, &record.ErrorMessage, &record.AIRequestDurationMs, &record.TriggerReason,
+		&record.MarketRegime, &regimesJSON, &record.Gated,
 	)
 	if err != nil {
 		return nil, err
@@ -353,6 +436,9 @@ func (s *DecisionStore) scanDecisionRecord(rows *sql.Rows) (*DecisionRecord, err
 	record.Timestamp, _ = time.Parse(time.RFC3339, timestampStr)
 	json.Unmarshal([]byte(candidateCoinsJSON), &record.CandidateCoins)
 	json.Unmarshal([]byte(executionLogJSON), &record.ExecutionLog)
+	if regimesJSON != "" {
+		json.Unmarshal([]byte(regimesJSON), &record.Regimes)
+	}
 
 	return &record, nil
 }
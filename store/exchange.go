@@ -20,7 +20,7 @@ type ExchangeStore struct {
 // Exchange exchange configuration
 type Exchange struct {
 	ID                      string    `json:"id"`            // UUID
-	ExchangeType            string    `json:"exchange_type"` // "binance", "bybit", "okx", "hyperliquid", "aster", "lighter"
+	ExchangeType            string    `json:"exchange_type"` // "binance", "bybit", "okx", "hyperliquid", "aster", "lighter", "dydx"
 	AccountName             string    `json:"account_name"`  // User-defined account name
 	UserID                  string    `json:"user_id"`
 	Name                    string    `json:"name"` // Display name (auto-generated or user-defined)
@@ -31,12 +31,16 @@ type Exchange struct {
 	Passphrase              string    `json:"passphrase"` // OKX-specific
 	Testnet                 bool      `json:"testnet"`
 	HyperliquidWalletAddr   string    `json:"hyperliquidWalletAddr"`
+	HyperliquidVaultAddr    string    `json:"hyperliquidVaultAddr"` // Sub-account or vault traded on behalf of the main wallet
 	AsterUser               string    `json:"asterUser"`
 	AsterSigner             string    `json:"asterSigner"`
 	AsterPrivateKey         string    `json:"asterPrivateKey"`
 	LighterWalletAddr       string    `json:"lighterWalletAddr"`
 	LighterPrivateKey       string    `json:"lighterPrivateKey"`
 	LighterAPIKeyPrivateKey string    `json:"lighterAPIKeyPrivateKey"`
+	DydxAddress             string    `json:"dydxAddress"`
+	DydxMnemonic            string    `json:"dydxMnemonic"`
+	DydxSubaccount          int       `json:"dydxSubaccount"`
 	CreatedAt               time.Time `json:"created_at"`
 	UpdatedAt               time.Time `json:"updated_at"`
 }
@@ -63,6 +67,10 @@ func (s *ExchangeStore) initTables() error {
 			lighter_wallet_addr TEXT DEFAULT '',
 			lighter_private_key TEXT DEFAULT '',
 			lighter_api_key_private_key TEXT DEFAULT '',
+			dydx_address TEXT DEFAULT '',
+			dydx_mnemonic TEXT DEFAULT '',
+			dydx_subaccount INTEGER DEFAULT 0,
+			hyperliquid_vault_addr TEXT DEFAULT '',
 			created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
 			updated_at DATETIME DEFAULT CURRENT_TIMESTAMP
 		)
@@ -75,6 +83,10 @@ func (s *ExchangeStore) initTables() error {
 	s.db.Exec(`ALTER TABLE exchanges ADD COLUMN passphrase TEXT DEFAULT ''`)
 	s.db.Exec(`ALTER TABLE exchanges ADD COLUMN exchange_type TEXT NOT NULL DEFAULT ''`)
 	s.db.Exec(`ALTER TABLE exchanges ADD COLUMN account_name TEXT NOT NULL DEFAULT ''`)
+	s.db.Exec(`ALTER TABLE exchanges ADD COLUMN dydx_address TEXT DEFAULT ''`)
+	s.db.Exec(`ALTER TABLE exchanges ADD COLUMN dydx_mnemonic TEXT DEFAULT ''`)
+	s.db.Exec(`ALTER TABLE exchanges ADD COLUMN dydx_subaccount INTEGER DEFAULT 0`)
+	s.db.Exec(`ALTER TABLE exchanges ADD COLUMN hyperliquid_vault_addr TEXT DEFAULT ''`)
 
 	// Run migration to multi-account if needed
 	if err := s.migrateToMultiAccount(); err != nil {
@@ -230,6 +242,10 @@ func (s *ExchangeStore) List(userID string) ([]*Exchange, error) {
 		       COALESCE(lighter_wallet_addr, '') as lighter_wallet_addr,
 		       COALESCE(lighter_private_key, '') as lighter_private_key,
 		       COALESCE(lighter_api_key_private_key, '') as lighter_api_key_private_key,
+		       COALESCE(dydx_address, '') as dydx_address,
+		       COALESCE(dydx_mnemonic, '') as dydx_mnemonic,
+		       COALESCE(dydx_subaccount, 0) as dydx_subaccount,
+		       COALESCE(hyperliquid_vault_addr, '') as hyperliquid_vault_addr,
 		       created_at, updated_at
 		FROM exchanges WHERE user_id = ? ORDER BY exchange_type, account_name
 	`, userID)
@@ -248,6 +264,7 @@ func (s *ExchangeStore) List(userID string) ([]*Exchange, error) {
 			&e.Enabled, &e.APIKey, &e.SecretKey, &e.Passphrase, &e.Testnet,
 			&e.HyperliquidWalletAddr, &e.AsterUser, &e.AsterSigner, &e.AsterPrivateKey,
 			&e.LighterWalletAddr, &e.LighterPrivateKey, &e.LighterAPIKeyPrivateKey,
+			&e.DydxAddress, &e.DydxMnemonic, &e.DydxSubaccount, &e.HyperliquidVaultAddr,
 			&createdAt, &updatedAt,
 		)
 		if err != nil {
@@ -261,6 +278,7 @@ func (s *ExchangeStore) List(userID string) ([]*Exchange, error) {
 		e.AsterPrivateKey = s.decrypt(e.AsterPrivateKey)
 		e.LighterPrivateKey = s.decrypt(e.LighterPrivateKey)
 		e.LighterAPIKeyPrivateKey = s.decrypt(e.LighterAPIKeyPrivateKey)
+		e.DydxMnemonic = s.decrypt(e.DydxMnemonic)
 		exchanges = append(exchanges, &e)
 	}
 	return exchanges, nil
@@ -281,6 +299,10 @@ func (s *ExchangeStore) GetByID(userID, id string) (*Exchange, error) {
 		       COALESCE(lighter_wallet_addr, '') as lighter_wallet_addr,
 		       COALESCE(lighter_private_key, '') as lighter_private_key,
 		       COALESCE(lighter_api_key_private_key, '') as lighter_api_key_private_key,
+		       COALESCE(dydx_address, '') as dydx_address,
+		       COALESCE(dydx_mnemonic, '') as dydx_mnemonic,
+		       COALESCE(dydx_subaccount, 0) as dydx_subaccount,
+		       COALESCE(hyperliquid_vault_addr, '') as hyperliquid_vault_addr,
 		       created_at, updated_at
 		FROM exchanges WHERE id = ? AND user_id = ?
 	`, id, userID).Scan(
@@ -289,6 +311,7 @@ func (s *ExchangeStore) GetByID(userID, id string) (*Exchange, error) {
 		&e.Enabled, &e.APIKey, &e.SecretKey, &e.Passphrase, &e.Testnet,
 		&e.HyperliquidWalletAddr, &e.AsterUser, &e.AsterSigner, &e.AsterPrivateKey,
 		&e.LighterWalletAddr, &e.LighterPrivateKey, &e.LighterAPIKeyPrivateKey,
+		&e.DydxAddress, &e.DydxMnemonic, &e.DydxSubaccount, &e.HyperliquidVaultAddr,
 		&createdAt, &updatedAt,
 	)
 	if err != nil {
@@ -302,6 +325,7 @@ func (s *ExchangeStore) GetByID(userID, id string) (*Exchange, error) {
 	e.AsterPrivateKey = s.decrypt(e.AsterPrivateKey)
 	e.LighterPrivateKey = s.decrypt(e.LighterPrivateKey)
 	e.LighterAPIKeyPrivateKey = s.decrypt(e.LighterAPIKeyPrivateKey)
+	e.DydxMnemonic = s.decrypt(e.DydxMnemonic)
 	return &e, nil
 }
 
@@ -316,12 +340,16 @@ func getExchangeNameAndType(exchangeType string) (name string, typ string) {
 		return "OKX Futures", "cex"
 	case "bitget":
 		return "Bitget Futures", "cex"
+	case "gate":
+		return "Gate Futures", "cex"
 	case "hyperliquid":
 		return "Hyperliquid", "dex"
 	case "aster":
 		return "Aster DEX", "dex"
 	case "lighter":
 		return "LIGHTER DEX", "dex"
+	case "dydx":
+		return "dYdX v4", "dex"
 	default:
 		return exchangeType + " Exchange", "cex"
 	}
@@ -331,7 +359,8 @@ func getExchangeNameAndType(exchangeType string) (name string, typ string) {
 func (s *ExchangeStore) Create(userID, exchangeType, accountName string, enabled bool,
 	apiKey, secretKey, passphrase string, testnet bool,
 	hyperliquidWalletAddr, asterUser, asterSigner, asterPrivateKey,
-	lighterWalletAddr, lighterPrivateKey, lighterApiKeyPrivateKey string) (string, error) {
+	lighterWalletAddr, lighterPrivateKey, lighterApiKeyPrivateKey,
+	dydxAddress, dydxMnemonic string, dydxSubaccount int, hyperliquidVaultAddr string) (string, error) {
 
 	id := uuid.New().String()
 	name, typ := getExchangeNameAndType(exchangeType)
@@ -349,12 +378,14 @@ func (s *ExchangeStore) Create(userID, exchangeType, accountName string, enabled
 		                       api_key, secret_key, passphrase, testnet,
 		                       hyperliquid_wallet_addr, aster_user, aster_signer, aster_private_key,
 		                       lighter_wallet_addr, lighter_private_key, lighter_api_key_private_key,
+		                       dydx_address, dydx_mnemonic, dydx_subaccount, hyperliquid_vault_addr,
 		                       created_at, updated_at)
-		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, datetime('now'), datetime('now'))
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, datetime('now'), datetime('now'))
 	`, id, exchangeType, accountName, userID, name, typ, enabled,
 		s.encrypt(apiKey), s.encrypt(secretKey), s.encrypt(passphrase), testnet,
 		hyperliquidWalletAddr, asterUser, asterSigner, s.encrypt(asterPrivateKey),
-		lighterWalletAddr, s.encrypt(lighterPrivateKey), s.encrypt(lighterApiKeyPrivateKey))
+		lighterWalletAddr, s.encrypt(lighterPrivateKey), s.encrypt(lighterApiKeyPrivateKey),
+		dydxAddress, s.encrypt(dydxMnemonic), dydxSubaccount, hyperliquidVaultAddr)
 
 	if err != nil {
 		return "", err
@@ -364,7 +395,8 @@ func (s *ExchangeStore) Create(userID, exchangeType, accountName string, enabled
 
 // Update updates exchange configuration by UUID
 func (s *ExchangeStore) Update(userID, id string, enabled bool, apiKey, secretKey, passphrase string, testnet bool,
-	hyperliquidWalletAddr, asterUser, asterSigner, asterPrivateKey, lighterWalletAddr, lighterPrivateKey, lighterApiKeyPrivateKey string) error {
+	hyperliquidWalletAddr, asterUser, asterSigner, asterPrivateKey, lighterWalletAddr, lighterPrivateKey, lighterApiKeyPrivateKey,
+	dydxAddress, dydxMnemonic string, dydxSubaccount int, hyperliquidVaultAddr string) error {
 
 	logger.Debugf("🔧 ExchangeStore.Update: userID=%s, id=%s, enabled=%v", userID, id, enabled)
 
@@ -375,9 +407,12 @@ func (s *ExchangeStore) Update(userID, id string, enabled bool, apiKey, secretKe
 		"aster_user = ?",
 		"aster_signer = ?",
 		"lighter_wallet_addr = ?",
+		"dydx_address = ?",
+		"dydx_subaccount = ?",
+		"hyperliquid_vault_addr = ?",
 		"updated_at = datetime('now')",
 	}
-	args := []interface{}{enabled, testnet, hyperliquidWalletAddr, asterUser, asterSigner, lighterWalletAddr}
+	args := []interface{}{enabled, testnet, hyperliquidWalletAddr, asterUser, asterSigner, lighterWalletAddr, dydxAddress, dydxSubaccount, hyperliquidVaultAddr}
 
 	if apiKey != "" {
 		setClauses = append(setClauses, "api_key = ?")
@@ -403,6 +438,10 @@ func (s *ExchangeStore) Update(userID, id string, enabled bool, apiKey, secretKe
 		setClauses = append(setClauses, "lighter_api_key_private_key = ?")
 		args = append(args, s.encrypt(lighterApiKeyPrivateKey))
 	}
+	if dydxMnemonic != "" {
+		setClauses = append(setClauses, "dydx_mnemonic = ?")
+		args = append(args, s.encrypt(dydxMnemonic))
+	}
 
 	args = append(args, id, userID)
 	query := fmt.Sprintf(`UPDATE exchanges SET %s WHERE id = ? AND user_id = ?`, strings.Join(setClauses, ", "))
@@ -456,7 +495,7 @@ func (s *ExchangeStore) CreateLegacy(userID, id, name, typ string, enabled bool,
 	if id == "binance" || id == "bybit" || id == "okx" || id == "bitget" || id == "hyperliquid" || id == "aster" || id == "lighter" {
 		// Use new Create method with exchange type
 		_, err := s.Create(userID, id, "Default", enabled, apiKey, secretKey, "", testnet,
-			hyperliquidWalletAddr, asterUser, asterSigner, asterPrivateKey, "", "", "")
+			hyperliquidWalletAddr, asterUser, asterSigner, asterPrivateKey, "", "", "", "", "", 0, "")
 		return err
 	}
 
@@ -0,0 +1,321 @@
+package store
+
+import (
+	"database/sql"
+	"fmt"
+	"sort"
+	"time"
+)
+
+// ExecutionStore order execution quality storage
+type ExecutionStore struct {
+	db *sql.DB
+}
+
+// OrderExecution execution record of a single order
+// Slippage is signed so that positive always means adverse (paid more on buys, received less on sells)
+type OrderExecution struct {
+	ID              int64     `json:"id"`
+	TraderID        string    `json:"trader_id"`
+	ExchangeType    string    `json:"exchange_type"`
+	Symbol          string    `json:"symbol"`
+	Action          string    `json:"action"` // open_long, open_short, close_long, close_short
+	OrderID         string    `json:"order_id"`
+	Quantity        float64   `json:"quantity"`
+	NotionalUSD     float64   `json:"notional_usd"`
+	DecisionPrice   float64   `json:"decision_price"`    // Mark price the AI decided on (0 = unknown)
+	SubmitPrice     float64   `json:"submit_price"`      // Mark price right before submission
+	FillPrice       float64   `json:"fill_price"`        // Average fill price
+	DecisionBps     float64   `json:"decision_bps"`      // Decision price -> submit price drift
+	SlippageBps     float64   `json:"slippage_bps"`      // Submit price -> fill price slippage
+	TotalBps        float64   `json:"total_bps"`         // Decision price -> fill price
+	EstimatedBps    float64   `json:"estimated_bps"`     // Order book slippage estimate before submission
+	HasEstimate     bool      `json:"has_estimate"`      // EstimatedBps is set (a real estimate can be 0)
+	AILatencyMs     int64     `json:"ai_latency_ms"`     // AI call of the deciding cycle (0 = not an AI decision)
+	SubmitLatencyMs int64     `json:"submit_latency_ms"` // Submit -> exchange acknowledgement
+	FillLatencyMs   int64     `json:"fill_latency_ms"`   // Submit -> exchange fill time (0 = fill time unknown)
+	SubmitTime      time.Time `json:"submit_time"`
+}
+
+// ExecutionQualityBucket aggregated execution quality of a group of orders
+type ExecutionQualityBucket struct {
+	Key              string  `json:"key"`
+	Orders           int     `json:"orders"`
+	NotionalUSD      float64 `json:"notional_usd"`
+	AvgDecisionBps   float64 `json:"avg_decision_bps"`
+	AvgSlippageBps   float64 `json:"avg_slippage_bps"`
+	AvgTotalBps      float64 `json:"avg_total_bps"`
+	AvgEstimatedBps  float64 `json:"avg_estimated_bps"` // Average order book estimate of the orders that had one
+	MaxSlippageBps   float64 `json:"max_slippage_bps"`
+	SlippageCostUSD  float64 `json:"slippage_cost_usd"`   // Decision price -> fill price cost in USD
+	AvgAILatencyMs   float64 `json:"avg_ai_latency_ms"`   // Average over orders decided by an AI call (rules and gated cycles excluded)
+	AvgFillLatencyMs float64 `json:"avg_fill_latency_ms"` // Average over orders with a known fill time
+
+	decisionOrders  int // Orders with a known decision price
+	aiOrders        int // Orders decided by an AI call (known AI latency)
+	estimatedOrders int // Orders with an order book slippage estimate
+	timedOrders     int // Orders with a known fill time
+	rank            int // Sort position ahead of the key (size buckets sort by size, not label)
+}
+
+// ExecutionQualityReport execution quality report of a trader
+type ExecutionQualityReport struct {
+	TraderID   string                    `json:"trader_id"`
+	Since      time.Time                 `json:"since"`
+	Overall    *ExecutionQualityBucket   `json:"overall"`
+	BySymbol   []*ExecutionQualityBucket `json:"by_symbol"`
+	ByExchange []*ExecutionQualityBucket `json:"by_exchange"`
+	ByHour     []*ExecutionQualityBucket `json:"by_hour"` // UTC hour of submission
+	BySize     []*ExecutionQualityBucket `json:"by_size"` // Order notional in USD
+}
+
+// initTables initializes execution tables
+func (s *ExecutionStore) initTables() error {
+	queries := []string{
+		`CREATE TABLE IF NOT EXISTS order_executions (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			trader_id TEXT NOT NULL,
+			exchange_type TEXT DEFAULT '',
+			symbol TEXT NOT NULL,
+			action TEXT NOT NULL,
+			order_id TEXT DEFAULT '',
+			quantity REAL DEFAULT 0,
+			notional_usd REAL DEFAULT 0,
+			decision_price REAL DEFAULT 0,
+			submit_price REAL DEFAULT 0,
+			fill_price REAL DEFAULT 0,
+			decision_bps REAL DEFAULT 0,
+			slippage_bps REAL DEFAULT 0,
+			total_bps REAL DEFAULT 0,
+			ai_latency_ms INTEGER DEFAULT 0,
+			submit_latency_ms INTEGER DEFAULT 0,
+			fill_latency_ms INTEGER DEFAULT 0,
+			submit_time DATETIME NOT NULL,
+			created_at DATETIME DEFAULT CURRENT_TIMESTAMP
+		)`,
+		`CREATE INDEX IF NOT EXISTS idx_executions_trader_time ON order_executions(trader_id, submit_time DESC)`,
+	}
+
+	for _, query := range queries {
+		if _, err := s.db.Exec(query); err != nil {
+			return fmt.Errorf("failed to execute SQL: %w", err)
+		}
+	}
+
+	// Migration: order book slippage estimate (ignore error if column exists)
+	s.db.Exec(`ALTER TABLE order_executions ADD COLUMN estimated_bps REAL DEFAULT 0`)
+	// Migration: estimate flag, earlier rows only stored non-zero estimates (ignore error if column exists)
+	if _, err := s.db.Exec(`ALTER TABLE order_executions ADD COLUMN has_estimate BOOLEAN DEFAULT 0`); err == nil {
+		s.db.Exec(`UPDATE order_executions SET has_estimate = 1 WHERE estimated_bps != 0`)
+	}
+
+	return nil
+}
+
+// Save saves an execution record, computing the slippage fields from the prices
+func (s *ExecutionStore) Save(e *OrderExecution) error {
+	if e.SubmitTime.IsZero() {
+		e.SubmitTime = time.Now()
+	}
+	e.NotionalUSD = e.Quantity * e.FillPrice
+	e.SlippageBps = AdverseBps(e.Action, e.SubmitPrice, e.FillPrice)
+	if e.DecisionPrice > 0 {
+		e.DecisionBps = AdverseBps(e.Action, e.DecisionPrice, e.SubmitPrice)
+		e.TotalBps = AdverseBps(e.Action, e.DecisionPrice, e.FillPrice)
+	}
+
+	result, err := s.db.Exec(`
+		INSERT INTO order_executions (
+			trader_id, exchange_type, symbol, action, order_id, quantity, notional_usd,
+			decision_price, submit_price, fill_price, decision_bps, slippage_bps, total_bps, estimated_bps, has_estimate,
+			ai_latency_ms, submit_latency_ms, fill_latency_ms, submit_time
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`,
+		e.TraderID, e.ExchangeType, e.Symbol, e.Action, e.OrderID, e.Quantity, e.NotionalUSD,
+		e.DecisionPrice, e.SubmitPrice, e.FillPrice, e.DecisionBps, e.SlippageBps, e.TotalBps, e.EstimatedBps, e.HasEstimate,
+		e.AILatencyMs, e.SubmitLatencyMs, e.FillLatencyMs, e.SubmitTime.UTC().Format(time.RFC3339),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to save execution record: %w", err)
+	}
+
+	e.ID, _ = result.LastInsertId()
+	return nil
+}
+
+// List gets execution records of a trader since the given time (oldest first)
+func (s *ExecutionStore) List(traderID string, since time.Time) ([]*OrderExecution, error) {
+	rows, err := s.db.Query(`
+		SELECT id, trader_id, exchange_type, symbol, action, order_id, quantity, notional_usd,
+			decision_price, submit_price, fill_price, decision_bps, slippage_bps, total_bps, estimated_bps, has_estimate,
+			ai_latency_ms, submit_latency_ms, fill_latency_ms, submit_time
+		FROM order_executions
+		WHERE trader_id = ? AND submit_time >= ?
+		ORDER BY submit_time ASC
+	`, traderID, since.UTC().Format(time.RFC3339))
+	if err != nil {
+		return nil, fmt.Errorf("failed to query execution records: %w", err)
+	}
+	defer rows.Close()
+
+	var executions []*OrderExecution
+	for rows.Next() {
+		var e OrderExecution
+		var submitTime string
+		if err := rows.Scan(
+			&e.ID, &e.TraderID, &e.ExchangeType, &e.Symbol, &e.Action, &e.OrderID, &e.Quantity, &e.NotionalUSD,
+			&e.DecisionPrice, &e.SubmitPrice, &e.FillPrice, &e.DecisionBps, &e.SlippageBps, &e.TotalBps, &e.EstimatedBps, &e.HasEstimate,
+			&e.AILatencyMs, &e.SubmitLatencyMs, &e.FillLatencyMs, &submitTime,
+		); err != nil {
+			continue
+		}
+		e.SubmitTime, _ = time.Parse(time.RFC3339, submitTime)
+		executions = append(executions, &e)
+	}
+	return executions, nil
+}
+
+// GetQualityReport aggregates slippage and latency by symbol, exchange, hour and order size
+func (s *ExecutionStore) GetQualityReport(traderID string, since time.Time) (*ExecutionQualityReport, error) {
+	executions, err := s.List(traderID, since)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &ExecutionQualityReport{
+		TraderID: traderID,
+		Since:    since,
+		Overall:  &ExecutionQualityBucket{Key: "all"},
+	}
+	bySymbol := make(map[string]*ExecutionQualityBucket)
+	byExchange := make(map[string]*ExecutionQualityBucket)
+	byHour := make(map[string]*ExecutionQualityBucket)
+	bySize := make(map[string]*ExecutionQualityBucket)
+
+	for _, e := range executions {
+		report.Overall.add(e)
+		bucketFor(bySymbol, e.Symbol).add(e)
+		bucketFor(byExchange, e.ExchangeType).add(e)
+		bucketFor(byHour, fmt.Sprintf("%02d:00", e.SubmitTime.UTC().Hour())).add(e)
+		label, rank := sizeBucket(e.NotionalUSD)
+		sized := bucketFor(bySize, label)
+		sized.rank = rank
+		sized.add(e)
+	}
+
+	report.Overall.finish()
+	report.BySymbol = sortedBuckets(bySymbol)
+	report.ByExchange = sortedBuckets(byExchange)
+	report.ByHour = sortedBuckets(byHour)
+	report.BySize = sortedBuckets(bySize)
+	return report, nil
+}
+
+// AdverseBps price difference in basis points, positive when adverse for the order direction
+func AdverseBps(action string, reference, actual float64) float64 {
+	if reference <= 0 || actual <= 0 {
+		return 0
+	}
+	bps := (actual - reference) / reference * 10000
+	switch action {
+	case "open_short", "close_long": // Sells: lower price is adverse
+		return -bps
+	}
+	return bps
+}
+
+// add accumulates an execution into the bucket (sums, averaged in finish)
+func (b *ExecutionQualityBucket) add(e *OrderExecution) {
+	b.Orders++
+	b.NotionalUSD += e.NotionalUSD
+	b.AvgSlippageBps += e.SlippageBps
+	if e.FillLatencyMs > 0 {
+		b.timedOrders++
+		b.AvgFillLatencyMs += float64(e.FillLatencyMs)
+	}
+	if e.SlippageBps > b.MaxSlippageBps {
+		b.MaxSlippageBps = e.SlippageBps
+	}
+
+	totalBps := e.SlippageBps
+	if e.DecisionPrice > 0 {
+		b.decisionOrders++
+		b.AvgDecisionBps += e.DecisionBps
+		b.AvgTotalBps += e.TotalBps
+		totalBps = e.TotalBps
+	}
+	if e.AILatencyMs > 0 {
+		b.aiOrders++
+		b.AvgAILatencyMs += float64(e.AILatencyMs)
+	}
+	if e.HasEstimate {
+		b.estimatedOrders++
+		b.AvgEstimatedBps += e.EstimatedBps
+	}
+	b.SlippageCostUSD += e.NotionalUSD * totalBps / 10000
+}
+
+// finish turns accumulated sums into averages
+func (b *ExecutionQualityBucket) finish() {
+	if b.Orders > 0 {
+		b.AvgSlippageBps /= float64(b.Orders)
+	}
+	if b.decisionOrders > 0 {
+		b.AvgDecisionBps /= float64(b.decisionOrders)
+		b.AvgTotalBps /= float64(b.decisionOrders)
+	}
+	if b.aiOrders > 0 {
+		b.AvgAILatencyMs /= float64(b.aiOrders)
+	}
+	if b.timedOrders > 0 {
+		b.AvgFillLatencyMs /= float64(b.timedOrders)
+	}
+	if b.estimatedOrders > 0 {
+		b.AvgEstimatedBps /= float64(b.estimatedOrders)
+	}
+}
+
+// bucketFor gets or creates the bucket for a key
+func bucketFor(buckets map[string]*ExecutionQualityBucket, key string) *ExecutionQualityBucket {
+	if key == "" {
+		key = "unknown"
+	}
+	b, ok := buckets[key]
+	if !ok {
+		b = &ExecutionQualityBucket{Key: key}
+		buckets[key] = b
+	}
+	return b
+}
+
+// sortedBuckets finishes buckets and returns them sorted by rank, then key
+func sortedBuckets(buckets map[string]*ExecutionQualityBucket) []*ExecutionQualityBucket {
+	result := make([]*ExecutionQualityBucket, 0, len(buckets))
+	for _, b := range buckets {
+		b.finish()
+		result = append(result, b)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].rank != result[j].rank {
+			return result[i].rank < result[j].rank
+		}
+		return result[i].Key < result[j].Key
+	})
+	return result
+}
+
+// sizeBucket order notional bucket label and its rank in size order
+func sizeBucket(notional float64) (string, int) {
+	switch {
+	case notional < 100:
+		return "<100", 1
+	case notional < 500:
+		return "100-500", 2
+	case notional < 2000:
+		return "500-2000", 3
+	case notional < 10000:
+		return "2000-10000", 4
+	default:
+		return "10000+", 5
+	}
+}
@@ -0,0 +1,319 @@
+package store
+
+import (
+	"database/sql"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Follower sizing modes
+const (
+	FollowerSizingEquityRatio = "equity_ratio" // Leader quantity × follower equity / leader equity × multiplier
+	FollowerSizingMultiplier  = "multiplier"   // Leader quantity × multiplier
+)
+
+// FollowerStore copy-trading follower storage
+type FollowerStore struct {
+	db *sql.DB
+}
+
+// Follower an exchange account replicating the orders of a leader trader
+type Follower struct {
+	ID                string    `json:"id"`
+	UserID            string    `json:"user_id"`
+	LeaderTraderID    string    `json:"leader_trader_id"`
+	ExchangeID        string    `json:"exchange_id"`
+	Name              string    `json:"name"`
+	Enabled           bool      `json:"enabled"`
+	SizingMode        string    `json:"sizing_mode"`         // equity_ratio or multiplier
+	Multiplier        float64   `json:"multiplier"`          // Scale factor (default 1)
+	MaxPositionUSD    float64   `json:"max_position_usd"`    // Max notional per position (0 = unlimited)
+	MaxLeverage       int       `json:"max_leverage"`        // Leverage cap (0 = leader's leverage)
+	MaxPositions      int       `json:"max_positions"`       // Max concurrent positions (0 = unlimited)
+	ReconcileDrift    bool      `json:"reconcile_drift"`     // Close copied positions the leader no longer has and trim oversized ones
+	DriftTolerancePct float64   `json:"drift_tolerance_pct"` // Allowed size drift before trimming (default 20%)
+	CreatedAt         time.Time `json:"created_at"`
+	UpdatedAt         time.Time `json:"updated_at"`
+}
+
+// FollowerExecution result of replicating one leader order on a follower account
+type FollowerExecution struct {
+	ID             int64     `json:"id"`
+	FollowerID     string    `json:"follower_id"`
+	LeaderTraderID string    `json:"leader_trader_id"`
+	CycleNumber    int       `json:"cycle_number"`
+	Symbol         string    `json:"symbol"`
+	Action         string    `json:"action"` // open_long, close_short, reconcile_close_long, reconcile_clear_long, ...
+	LeaderQuantity float64   `json:"leader_quantity"`
+	Quantity       float64   `json:"quantity"`        // Ordered quantity
+	FilledQuantity float64   `json:"filled_quantity"` // Confirmed filled quantity (what the copied position changed by)
+	Price          float64   `json:"price"`
+	OrderID        string    `json:"order_id"`
+	Success        bool      `json:"success"`
+	Error          string    `json:"error,omitempty"`
+	CreatedAt      time.Time `json:"created_at"`
+}
+
+// initTables initializes follower tables
+func (s *FollowerStore) initTables() error {
+	queries := []string{
+		`CREATE TABLE IF NOT EXISTS trader_followers (
+			id TEXT PRIMARY KEY,
+			user_id TEXT NOT NULL,
+			leader_trader_id TEXT NOT NULL,
+			exchange_id TEXT NOT NULL,
+			name TEXT NOT NULL DEFAULT '',
+			enabled BOOLEAN DEFAULT 1,
+			sizing_mode TEXT NOT NULL DEFAULT 'equity_ratio',
+			multiplier REAL DEFAULT 1,
+			max_position_usd REAL DEFAULT 0,
+			max_leverage INTEGER DEFAULT 0,
+			max_positions INTEGER DEFAULT 0,
+			reconcile_drift BOOLEAN DEFAULT 1,
+			drift_tolerance_pct REAL DEFAULT 20,
+			created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+			updated_at DATETIME DEFAULT CURRENT_TIMESTAMP
+		)`,
+		`CREATE INDEX IF NOT EXISTS idx_followers_leader ON trader_followers(leader_trader_id)`,
+		`CREATE TABLE IF NOT EXISTS follower_executions (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			follower_id TEXT NOT NULL,
+			leader_trader_id TEXT NOT NULL,
+			cycle_number INTEGER DEFAULT 0,
+			symbol TEXT NOT NULL,
+			action TEXT NOT NULL,
+			leader_quantity REAL DEFAULT 0,
+			quantity REAL DEFAULT 0,
+			filled_quantity REAL DEFAULT 0,
+			price REAL DEFAULT 0,
+			order_id TEXT DEFAULT '',
+			success BOOLEAN DEFAULT 0,
+			error TEXT DEFAULT '',
+			created_at DATETIME NOT NULL
+		)`,
+		`CREATE INDEX IF NOT EXISTS idx_follower_exec_follower_time ON follower_executions(follower_id, created_at DESC)`,
+		`CREATE INDEX IF NOT EXISTS idx_follower_exec_leader_time ON follower_executions(leader_trader_id, created_at DESC)`,
+	}
+
+	for _, query := range queries {
+		if _, err := s.db.Exec(query); err != nil {
+			return fmt.Errorf("failed to execute SQL: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// applyDefaults fills in default sizing and drift settings
+func (f *Follower) applyDefaults() {
+	if f.SizingMode == "" {
+		f.SizingMode = FollowerSizingEquityRatio
+	}
+	if f.Multiplier <= 0 {
+		f.Multiplier = 1
+	}
+	if f.DriftTolerancePct <= 0 {
+		f.DriftTolerancePct = 20
+	}
+}
+
+// Validate checks the follower configuration
+func (f *Follower) Validate() error {
+	if f.LeaderTraderID == "" {
+		return fmt.Errorf("leader_trader_id is required")
+	}
+	if f.ExchangeID == "" {
+		return fmt.Errorf("exchange_id is required")
+	}
+	switch f.SizingMode {
+	case "", FollowerSizingEquityRatio, FollowerSizingMultiplier:
+	default:
+		return fmt.Errorf("invalid sizing_mode %q (expected %s or %s)", f.SizingMode, FollowerSizingEquityRatio, FollowerSizingMultiplier)
+	}
+	if f.Multiplier < 0 || f.MaxPositionUSD < 0 || f.MaxLeverage < 0 || f.MaxPositions < 0 || f.DriftTolerancePct < 0 {
+		return fmt.Errorf("follower limits cannot be negative")
+	}
+	return nil
+}
+
+// Create creates a follower
+func (s *FollowerStore) Create(f *Follower) error {
+	if f.ID == "" {
+		f.ID = uuid.New().String()
+	}
+	f.applyDefaults()
+	_, err := s.db.Exec(`
+		INSERT INTO trader_followers (id, user_id, leader_trader_id, exchange_id, name, enabled, sizing_mode,
+		                              multiplier, max_position_usd, max_leverage, max_positions,
+		                              reconcile_drift, drift_tolerance_pct)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`, f.ID, f.UserID, f.LeaderTraderID, f.ExchangeID, f.Name, f.Enabled, f.SizingMode,
+		f.Multiplier, f.MaxPositionUSD, f.MaxLeverage, f.MaxPositions, f.ReconcileDrift, f.DriftTolerancePct)
+	if err != nil {
+		return fmt.Errorf("failed to create follower: %w", err)
+	}
+	return nil
+}
+
+// Update updates a follower's configuration
+func (s *FollowerStore) Update(f *Follower) error {
+	f.applyDefaults()
+	_, err := s.db.Exec(`
+		UPDATE trader_followers SET exchange_id = ?, name = ?, enabled = ?, sizing_mode = ?, multiplier = ?,
+		       max_position_usd = ?, max_leverage = ?, max_positions = ?, reconcile_drift = ?,
+		       drift_tolerance_pct = ?, updated_at = CURRENT_TIMESTAMP
+		WHERE id = ? AND user_id = ?
+	`, f.ExchangeID, f.Name, f.Enabled, f.SizingMode, f.Multiplier, f.MaxPositionUSD, f.MaxLeverage,
+		f.MaxPositions, f.ReconcileDrift, f.DriftTolerancePct, f.ID, f.UserID)
+	if err != nil {
+		return fmt.Errorf("failed to update follower: %w", err)
+	}
+	return nil
+}
+
+// Delete deletes a follower
+func (s *FollowerStore) Delete(userID, id string) error {
+	_, err := s.db.Exec(`DELETE FROM trader_followers WHERE id = ? AND user_id = ?`, id, userID)
+	return err
+}
+
+// Get gets a follower
+func (s *FollowerStore) Get(userID, id string) (*Follower, error) {
+	followers, err := s.query(`WHERE id = ? AND user_id = ?`, id, userID)
+	if err != nil {
+		return nil, err
+	}
+	if len(followers) == 0 {
+		return nil, sql.ErrNoRows
+	}
+	return followers[0], nil
+}
+
+// ListByLeader gets the followers of a leader trader
+func (s *FollowerStore) ListByLeader(leaderTraderID string) ([]*Follower, error) {
+	return s.query(`WHERE leader_trader_id = ? ORDER BY created_at ASC`, leaderTraderID)
+}
+
+// query loads followers matching the WHERE clause
+func (s *FollowerStore) query(where string, args ...interface{}) ([]*Follower, error) {
+	rows, err := s.db.Query(`
+		SELECT id, user_id, leader_trader_id, exchange_id, name, enabled, sizing_mode, multiplier,
+		       max_position_usd, max_leverage, max_positions, reconcile_drift, drift_tolerance_pct,
+		       created_at, updated_at
+		FROM trader_followers `+where, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query followers: %w", err)
+	}
+	defer rows.Close()
+
+	var followers []*Follower
+	for rows.Next() {
+		var f Follower
+		var createdAt, updatedAt string
+		if err := rows.Scan(&f.ID, &f.UserID, &f.LeaderTraderID, &f.ExchangeID, &f.Name, &f.Enabled,
+			&f.SizingMode, &f.Multiplier, &f.MaxPositionUSD, &f.MaxLeverage, &f.MaxPositions,
+			&f.ReconcileDrift, &f.DriftTolerancePct, &createdAt, &updatedAt); err != nil {
+			return nil, err
+		}
+		f.CreatedAt, _ = time.Parse("2006-01-02 15:04:05", createdAt)
+		f.UpdatedAt, _ = time.Parse("2006-01-02 15:04:05", updatedAt)
+		followers = append(followers, &f)
+	}
+	return followers, nil
+}
+
+// RecordExecution saves the result of a replicated order
+func (s *FollowerStore) RecordExecution(e *FollowerExecution) error {
+	if e.CreatedAt.IsZero() {
+		e.CreatedAt = time.Now()
+	}
+	result, err := s.db.Exec(`
+		INSERT INTO follower_executions (follower_id, leader_trader_id, cycle_number, symbol, action,
+		                                 leader_quantity, quantity, filled_quantity, price, order_id, success, error, created_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`, e.FollowerID, e.LeaderTraderID, e.CycleNumber, e.Symbol, e.Action, e.LeaderQuantity, e.Quantity,
+		e.FilledQuantity, e.Price, e.OrderID, e.Success, e.Error, e.CreatedAt.UTC().Format(time.RFC3339))
+	if err != nil {
+		return fmt.Errorf("failed to save follower execution: %w", err)
+	}
+	e.ID, _ = result.LastInsertId()
+	return nil
+}
+
+// GetExecutions gets the latest replicated orders of a leader trader (followerID filters to one follower)
+func (s *FollowerStore) GetExecutions(leaderTraderID, followerID string, limit int) ([]*FollowerExecution, error) {
+	if limit <= 0 {
+		limit = 100
+	}
+	query := `
+		SELECT id, follower_id, leader_trader_id, cycle_number, symbol, action, leader_quantity, quantity,
+		       filled_quantity, price, order_id, success, error, created_at
+		FROM follower_executions WHERE leader_trader_id = ?`
+	args := []interface{}{leaderTraderID}
+	if followerID != "" {
+		query += ` AND follower_id = ?`
+		args = append(args, followerID)
+	}
+	query += ` ORDER BY created_at DESC, id DESC LIMIT ?`
+	args = append(args, limit)
+
+	rows, err := s.db.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query follower executions: %w", err)
+	}
+	defer rows.Close()
+
+	var executions []*FollowerExecution
+	for rows.Next() {
+		var e FollowerExecution
+		var createdAt string
+		if err := rows.Scan(&e.ID, &e.FollowerID, &e.LeaderTraderID, &e.CycleNumber, &e.Symbol, &e.Action,
+			&e.LeaderQuantity, &e.Quantity, &e.FilledQuantity, &e.Price, &e.OrderID, &e.Success, &e.Error, &createdAt); err != nil {
+			continue
+		}
+		e.CreatedAt, _ = time.Parse(time.RFC3339, createdAt)
+		executions = append(executions, &e)
+	}
+	return executions, nil
+}
+
+// GetCopiedQuantities net quantity the leader's copy-trading currently holds on a follower account, keyed "SYMBOL_side"
+// Replayed from the confirmed fills of successful executions: opens add, closes subtract
+// (floored at zero, a close-all resets the position)
+func (s *FollowerStore) GetCopiedQuantities(leaderTraderID, followerID string) (map[string]float64, error) {
+	rows, err := s.db.Query(`
+		SELECT symbol, action, filled_quantity FROM follower_executions
+		WHERE leader_trader_id = ? AND follower_id = ? AND success = 1
+		ORDER BY created_at ASC, id ASC
+	`, leaderTraderID, followerID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query follower executions: %w", err)
+	}
+	defer rows.Close()
+
+	copied := make(map[string]float64)
+	for rows.Next() {
+		var symbol, action string
+		var quantity float64
+		if err := rows.Scan(&symbol, &action, &quantity); err != nil {
+			return nil, err
+		}
+		side := action[strings.LastIndex(action, "_")+1:]
+		key := symbol + "_" + side
+		if strings.HasPrefix(action, "open_") {
+			copied[key] += quantity
+		} else {
+			copied[key] = math.Max(copied[key]-quantity, 0)
+		}
+	}
+	for key, quantity := range copied {
+		if quantity <= 0 {
+			delete(copied, key)
+		}
+	}
+	return copied, nil
+}
@@ -0,0 +1,198 @@
+package store
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+// FundingStore funding payment storage
+type FundingStore struct {
+	db *sql.DB
+}
+
+// FundingPayment funding payment received or paid by a position
+type FundingPayment struct {
+	ID           int64     `json:"id"`
+	TraderID     string    `json:"trader_id"`
+	PositionID   int64     `json:"position_id"` // 0 when no local position matched the payment
+	Symbol       string    `json:"symbol"`
+	Amount       float64   `json:"amount"` // Positive = received, negative = paid
+	Rate         float64   `json:"rate"`   // Funding rate (0 when the exchange doesn't report it)
+	FundingTime  time.Time `json:"funding_time"`
+	ExchangeTxID string    `json:"exchange_tx_id"`
+}
+
+// initTables initializes funding tables
+func (s *FundingStore) initTables() error {
+	queries := []string{
+		`CREATE TABLE IF NOT EXISTS position_funding (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			trader_id TEXT NOT NULL,
+			position_id INTEGER NOT NULL DEFAULT 0,
+			symbol TEXT NOT NULL,
+			amount REAL NOT NULL DEFAULT 0,
+			rate REAL DEFAULT 0,
+			funding_time DATETIME NOT NULL,
+			exchange_tx_id TEXT NOT NULL DEFAULT '',
+			created_at DATETIME DEFAULT CURRENT_TIMESTAMP
+		)`,
+		// Same payment must not be counted twice when history windows overlap
+		`CREATE UNIQUE INDEX IF NOT EXISTS idx_funding_unique ON position_funding(trader_id, symbol, funding_time, exchange_tx_id)`,
+		`CREATE INDEX IF NOT EXISTS idx_funding_position ON position_funding(position_id)`,
+		`CREATE INDEX IF NOT EXISTS idx_funding_trader_time ON position_funding(trader_id, funding_time DESC)`,
+	}
+
+	for _, query := range queries {
+		if _, err := s.db.Exec(query); err != nil {
+			return fmt.Errorf("failed to execute SQL: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// Record saves funding payments and attributes each one to the position that was open at funding time
+// Returns the number of newly stored payments (duplicates are ignored)
+func (s *FundingStore) Record(traderID string, payments []FundingPayment) (int, error) {
+	created := 0
+	for i := range payments {
+		p := &payments[i]
+		p.TraderID = traderID
+		fundingTime := p.FundingTime.UTC().Format(time.RFC3339)
+
+		if p.PositionID == 0 {
+			p.PositionID = s.positionAt(traderID, p.Symbol, fundingTime)
+		}
+
+		result, err := s.db.Exec(`
+			INSERT OR IGNORE INTO position_funding (
+				trader_id, position_id, symbol, amount, rate, funding_time, exchange_tx_id
+			) VALUES (?, ?, ?, ?, ?, ?, ?)
+		`, traderID, p.PositionID, p.Symbol, p.Amount, p.Rate, fundingTime, p.ExchangeTxID)
+		if err != nil {
+			return created, fmt.Errorf("failed to save funding payment: %w", err)
+		}
+		if n, _ := result.RowsAffected(); n > 0 {
+			p.ID, _ = result.LastInsertId()
+			created++
+		}
+	}
+	return created, nil
+}
+
+// positionAt finds the position of the symbol open at funding time (0 = none)
+// Funding applies to the net position; in hedge mode with both sides open the earliest one gets it
+func (s *FundingStore) positionAt(traderID, symbol, fundingTime string) int64 {
+	var positionID sql.NullInt64
+	s.db.QueryRow(`
+		SELECT id FROM trader_positions
+		WHERE trader_id = ? AND symbol = ?
+			AND julianday(entry_time) <= julianday(?)
+			AND (exit_time IS NULL OR julianday(exit_time) >= julianday(?))
+		ORDER BY entry_time ASC LIMIT 1
+	`, traderID, symbol, fundingTime, fundingTime).Scan(&positionID)
+	return positionID.Int64
+}
+
+// RelinkUnattributed attributes payments stored before their position existed locally, or whose position
+// was replaced by a history rebuild, to the position open at funding time
+// Payments without a match keep position_id 0. Returns the number of re-linked payments.
+func (s *FundingStore) RelinkUnattributed(traderID string) (int, error) {
+	rows, err := s.db.Query(`
+		SELECT id, symbol, funding_time, position_id FROM position_funding f
+		WHERE f.trader_id = ?
+			AND (f.position_id = 0 OR NOT EXISTS (SELECT 1 FROM trader_positions p WHERE p.id = f.position_id))
+	`, traderID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to query unattributed funding payments: %w", err)
+	}
+	type unattributed struct {
+		id, positionID      int64
+		symbol, fundingTime string
+	}
+	var payments []unattributed
+	for rows.Next() {
+		var p unattributed
+		if err := rows.Scan(&p.id, &p.symbol, &p.fundingTime, &p.positionID); err != nil {
+			rows.Close()
+			return 0, err
+		}
+		payments = append(payments, p)
+	}
+	rows.Close()
+
+	relinked := 0
+	for _, p := range payments {
+		positionID := s.positionAt(traderID, p.symbol, p.fundingTime)
+		if positionID == p.positionID {
+			continue
+		}
+		if _, err := s.db.Exec(`UPDATE position_funding SET position_id = ? WHERE id = ?`, positionID, p.id); err != nil {
+			return relinked, fmt.Errorf("failed to re-link funding payment: %w", err)
+		}
+		if positionID != 0 {
+			relinked++
+		}
+	}
+	return relinked, nil
+}
+
+// GetLastFundingTime gets the time of the latest stored funding payment
+// Returns zero time when the trader has no funding history yet
+func (s *FundingStore) GetLastFundingTime(traderID string) (time.Time, error) {
+	var fundingTime sql.NullString
+	err := s.db.QueryRow(`
+		SELECT funding_time FROM position_funding
+		WHERE trader_id = ?
+		ORDER BY funding_time DESC LIMIT 1
+	`, traderID).Scan(&fundingTime)
+
+	if err == sql.ErrNoRows || !fundingTime.Valid {
+		return time.Time{}, nil
+	}
+	if err != nil {
+		return time.Time{}, fmt.Errorf("failed to get last funding time: %w", err)
+	}
+
+	t, _ := time.Parse(time.RFC3339, fundingTime.String)
+	return t, nil
+}
+
+// GetByPosition gets funding payments of a position
+func (s *FundingStore) GetByPosition(positionID int64) ([]*FundingPayment, error) {
+	rows, err := s.db.Query(`
+		SELECT id, trader_id, position_id, symbol, amount, rate, funding_time, exchange_tx_id
+		FROM position_funding
+		WHERE position_id = ?
+		ORDER BY funding_time ASC
+	`, positionID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query funding payments: %w", err)
+	}
+	defer rows.Close()
+
+	var payments []*FundingPayment
+	for rows.Next() {
+		var p FundingPayment
+		var fundingTime string
+		if err := rows.Scan(&p.ID, &p.TraderID, &p.PositionID, &p.Symbol, &p.Amount, &p.Rate, &fundingTime, &p.ExchangeTxID); err != nil {
+			continue
+		}
+		p.FundingTime, _ = time.Parse(time.RFC3339, fundingTime)
+		payments = append(payments, &p)
+	}
+	return payments, nil
+}
+
+// GetPositionFunding gets total funding of a position (positive = net received)
+func (s *FundingStore) GetPositionFunding(positionID int64) (float64, error) {
+	var total float64
+	err := s.db.QueryRow(`
+		SELECT COALESCE(SUM(amount), 0) FROM position_funding WHERE position_id = ?
+	`, positionID).Scan(&total)
+	if err != nil {
+		return 0, fmt.Errorf("failed to sum position funding: %w", err)
+	}
+	return total, nil
+}
@@ -0,0 +1,236 @@
+package store
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// JournalStore trade journal storage
+type JournalStore struct {
+	db *sql.DB
+}
+
+// JournalEntry reflection on a closed position: why it was opened, what happened and the outcome
+type JournalEntry struct {
+	ID             int64     `json:"id"`
+	TraderID       string    `json:"trader_id"`
+	PositionID     int64     `json:"position_id"` // Negative (-ID) while detached by a position history rebuild
+	Symbol         string    `json:"symbol"`
+	Side           string    `json:"side"`   // long/short
+	Regime         string    `json:"regime"` // Regime label at entry (empty = not classified)
+	Leverage       int       `json:"leverage"`
+	EntryPrice     float64   `json:"entry_price"`
+	ExitPrice      float64   `json:"exit_price"`
+	RealizedPnL    float64   `json:"realized_pnl"`
+	PnLPct         float64   `json:"pnl_pct"` // Return on margin (%)
+	HoldMinutes    int       `json:"hold_minutes"`
+	CloseReason    string    `json:"close_reason"`
+	EntryReasoning string    `json:"entry_reasoning"` // Reasoning of the decision that opened the position
+	ExitReasoning  string    `json:"exit_reasoning"`  // Reasoning of the decision that closed it (empty = closed by the exchange)
+	Summary        string    `json:"summary"`         // Compact journal text shown to the AI (editable)
+	EntryTime      time.Time `json:"entry_time"`
+	ExitTime       time.Time `json:"exit_time"`
+	CreatedAt      time.Time `json:"created_at"`
+	UpdatedAt      time.Time `json:"updated_at"`
+}
+
+// initTables initializes trade journal tables
+func (s *JournalStore) initTables() error {
+	queries := []string{
+		`CREATE TABLE IF NOT EXISTS trade_journal (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			trader_id TEXT NOT NULL,
+			position_id INTEGER NOT NULL,
+			symbol TEXT NOT NULL,
+			side TEXT NOT NULL,
+			regime TEXT DEFAULT '',
+			leverage INTEGER DEFAULT 1,
+			entry_price REAL DEFAULT 0,
+			exit_price REAL DEFAULT 0,
+			realized_pnl REAL DEFAULT 0,
+			pnl_pct REAL DEFAULT 0,
+			hold_minutes INTEGER DEFAULT 0,
+			close_reason TEXT DEFAULT '',
+			entry_reasoning TEXT DEFAULT '',
+			exit_reasoning TEXT DEFAULT '',
+			summary TEXT DEFAULT '',
+			entry_time DATETIME,
+			exit_time DATETIME,
+			deleted BOOLEAN DEFAULT 0,
+			created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+			updated_at DATETIME DEFAULT CURRENT_TIMESTAMP
+		)`,
+		// One entry per closed position
+		`CREATE UNIQUE INDEX IF NOT EXISTS idx_journal_position ON trade_journal(trader_id, position_id)`,
+		`CREATE INDEX IF NOT EXISTS idx_journal_trader_exit ON trade_journal(trader_id, exit_time DESC)`,
+	}
+
+	for _, query := range queries {
+		if _, err := s.db.Exec(query); err != nil {
+			return fmt.Errorf("failed to execute SQL: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// Create saves a journal entry (ignored if the position is already journaled)
+func (s *JournalStore) Create(e *JournalEntry) error {
+	now := time.Now().UTC()
+	e.CreatedAt = now
+	e.UpdatedAt = now
+	result, err := s.db.Exec(`
+		INSERT OR IGNORE INTO trade_journal (
+			trader_id, position_id, symbol, side, regime, leverage, entry_price, exit_price,
+			realized_pnl, pnl_pct, hold_minutes, close_reason, entry_reasoning, exit_reasoning,
+			summary, entry_time, exit_time, created_at, updated_at
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`,
+		e.TraderID, e.PositionID, e.Symbol, e.Side, e.Regime, e.Leverage, e.EntryPrice, e.ExitPrice,
+		e.RealizedPnL, e.PnLPct, e.HoldMinutes, e.CloseReason, e.EntryReasoning, e.ExitReasoning,
+		e.Summary, e.EntryTime.UTC().Format(time.RFC3339), e.ExitTime.UTC().Format(time.RFC3339),
+		now.Format(time.RFC3339), now.Format(time.RFC3339),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to save journal entry: %w", err)
+	}
+	e.ID, _ = result.LastInsertId()
+	return nil
+}
+
+// GetUnjournaledPositions gets positions closed since a time that have no journal entry yet (oldest first)
+func (s *JournalStore) GetUnjournaledPositions(traderID string, since time.Time, limit int) ([]*TraderPosition, error) {
+	rows, err := s.db.Query(`
+		SELECT id, trader_id, exchange_id, COALESCE(exchange_type, '') as exchange_type, symbol, side, quantity, entry_price, entry_order_id,
+			entry_time, exit_price, exit_order_id, exit_time, realized_pnl, fee,
+			leverage, status, close_reason, created_at, updated_at
+		FROM trader_positions p
+		WHERE trader_id = ? AND status = 'CLOSED' AND exit_time IS NOT NULL
+			AND julianday(exit_time) >= julianday(?)
+			AND NOT EXISTS (SELECT 1 FROM trade_journal j WHERE j.trader_id = p.trader_id AND j.position_id = p.id)
+		ORDER BY exit_time ASC
+		LIMIT ?
+	`, traderID, since.UTC().Format(time.RFC3339), limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query unjournaled positions: %w", err)
+	}
+	defer rows.Close()
+
+	return (&PositionStore{db: s.db}).scanPositions(rows)
+}
+
+// List gets the latest journal entries of a trader (symbol filters to one symbol)
+func (s *JournalStore) List(traderID, symbol string, limit int) ([]*JournalEntry, error) {
+	if limit <= 0 {
+		limit = 50
+	}
+	where := `WHERE trader_id = ? AND deleted = 0`
+	args := []interface{}{traderID}
+	if symbol != "" {
+		where += ` AND symbol = ?`
+		args = append(args, symbol)
+	}
+	args = append(args, limit)
+	return s.query(where+` ORDER BY exit_time DESC, id DESC LIMIT ?`, args...)
+}
+
+// Get gets a journal entry of a trader
+func (s *JournalStore) Get(traderID string, id int64) (*JournalEntry, error) {
+	entries, err := s.query(`WHERE trader_id = ? AND id = ? AND deleted = 0`, traderID, id)
+	if err != nil {
+		return nil, err
+	}
+	if len(entries) == 0 {
+		return nil, sql.ErrNoRows
+	}
+	return entries[0], nil
+}
+
+// UpdateSummary replaces the summary of a journal entry
+func (s *JournalStore) UpdateSummary(traderID string, id int64, summary string) error {
+	_, err := s.db.Exec(`
+		UPDATE trade_journal SET summary = ?, updated_at = ?
+		WHERE trader_id = ? AND id = ? AND deleted = 0
+	`, summary, time.Now().UTC().Format(time.RFC3339), traderID, id)
+	if err != nil {
+		return fmt.Errorf("failed to update journal entry: %w", err)
+	}
+	return nil
+}
+
+// Delete hides a journal entry
+// The row is kept so the position isn't journaled again
+func (s *JournalStore) Delete(traderID string, id int64) error {
+	_, err := s.db.Exec(`
+		UPDATE trade_journal SET deleted = 1, updated_at = ?
+		WHERE trader_id = ? AND id = ?
+	`, time.Now().UTC().Format(time.RFC3339), traderID, id)
+	return err
+}
+
+// RelinkDetached links entries detached by a position history rebuild to the rebuilt position of
+// the same symbol and side whose entry time is closest within window, keeping user edits and deletions
+// Entries without a match stay detached. Returns the number of re-linked entries.
+func (s *JournalStore) RelinkDetached(traderID string, window time.Duration) (int, error) {
+	detached, err := s.query(`WHERE trader_id = ? AND position_id < 0`, traderID)
+	if err != nil {
+		return 0, err
+	}
+
+	relinked := 0
+	for _, e := range detached {
+		entryTime := e.EntryTime.UTC().Format(time.RFC3339)
+		var positionID int64
+		err := s.db.QueryRow(`
+			SELECT p.id FROM trader_positions p
+			WHERE p.trader_id = ? AND p.symbol = ? AND p.side = ? AND p.status = 'CLOSED'
+				AND ABS(julianday(p.entry_time) - julianday(?)) * 86400 <= ?
+				AND NOT EXISTS (SELECT 1 FROM trade_journal j WHERE j.trader_id = p.trader_id AND j.position_id = p.id)
+			ORDER BY ABS(julianday(p.entry_time) - julianday(?))
+			LIMIT 1
+		`, traderID, e.Symbol, strings.ToUpper(e.Side), entryTime, window.Seconds(), entryTime).Scan(&positionID)
+		if err == sql.ErrNoRows {
+			continue
+		}
+		if err != nil {
+			return relinked, fmt.Errorf("failed to find rebuilt position: %w", err)
+		}
+		if _, err := s.db.Exec(`UPDATE trade_journal SET position_id = ? WHERE id = ?`, positionID, e.ID); err != nil {
+			return relinked, fmt.Errorf("failed to re-link journal entry: %w", err)
+		}
+		relinked++
+	}
+	return relinked, nil
+}
+
+// query loads journal entries matching the WHERE clause
+func (s *JournalStore) query(where string, args ...interface{}) ([]*JournalEntry, error) {
+	rows, err := s.db.Query(`
+		SELECT id, trader_id, position_id, symbol, side, regime, leverage, entry_price, exit_price,
+		       realized_pnl, pnl_pct, hold_minutes, close_reason, entry_reasoning, exit_reasoning,
+		       summary, entry_time, exit_time, created_at, updated_at
+		FROM trade_journal `+where, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query journal entries: %w", err)
+	}
+	defer rows.Close()
+
+	var entries []*JournalEntry
+	for rows.Next() {
+		var e JournalEntry
+		var entryTime, exitTime, createdAt, updatedAt sql.NullString
+		if err := rows.Scan(&e.ID, &e.TraderID, &e.PositionID, &e.Symbol, &e.Side, &e.Regime, &e.Leverage,
+			&e.EntryPrice, &e.ExitPrice, &e.RealizedPnL, &e.PnLPct, &e.HoldMinutes, &e.CloseReason,
+			&e.EntryReasoning, &e.ExitReasoning, &e.Summary, &entryTime, &exitTime, &createdAt, &updatedAt); err != nil {
+			return nil, err
+		}
+		e.EntryTime, _ = time.Parse(time.RFC3339, entryTime.String)
+		e.ExitTime, _ = time.Parse(time.RFC3339, exitTime.String)
+		e.CreatedAt, _ = time.Parse(time.RFC3339, createdAt.String)
+		e.UpdatedAt, _ = time.Parse(time.RFC3339, updatedAt.String)
+		entries = append(entries, &e)
+	}
+	return entries, nil
+}
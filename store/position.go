@@ -16,8 +16,10 @@ type TraderStats struct {
 	WinRate        float64 `json:"win_rate"`         // Win rate (%)
 	ProfitFactor   float64 `json:"profit_factor"`    // Profit factor
 	SharpeRatio    float64 `json:"sharpe_ratio"`     // Sharpe ratio
-	TotalPnL       float64 `json:"total_pnl"`        // Total PnL
-	TotalFee       float64 `json:"total_fee"`        // Total fees
+	TotalPnL       float64 `json:"total_pnl"`        // Total price PnL (before fees and funding)
+	TotalFee       float64 `json:"total_fee"`        // Total trading fees
+	TotalFunding   float64 `json:"total_funding"`    // Total funding (positive = received)
+	NetPnL         float64 `json:"net_pnl"`          // Price PnL - fees + funding
 	AvgWin         float64 `json:"avg_win"`          // Average win
 	AvgLoss        float64 `json:"avg_loss"`         // Average loss
 	MaxDrawdownPct float64 `json:"max_drawdown_pct"` // Max drawdown (%)
@@ -291,12 +293,13 @@ func (s *PositionStore) GetPositionStats(traderID string) (map[string]interface{
 func (s *PositionStore) GetFullStats(traderID string) (*TraderStats, error) {
 	stats := &TraderStats{}
 
-	// Query all closed positions
+	// Query all closed positions with the funding paid/received while they were open
 	rows, err := s.db.Query(`
-		SELECT realized_pnl, fee, exit_time
-		FROM trader_positions
-		WHERE trader_id = ? AND status = 'CLOSED'
-		ORDER BY exit_time ASC
+		SELECT p.realized_pnl, p.fee, p.exit_time,
+			COALESCE((SELECT SUM(f.amount) FROM position_funding f WHERE f.position_id = p.id), 0)
+		FROM trader_positions p
+		WHERE p.trader_id = ? AND p.status = 'CLOSED'
+		ORDER BY p.exit_time ASC
 	`, traderID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to query position statistics: %w", err)
@@ -307,15 +310,16 @@ func (s *PositionStore) GetFullStats(traderID string) (*TraderStats, error) {
 	var totalWin, totalLoss float64
 
 	for rows.Next() {
-		var pnl, fee float64
+		var pnl, fee, funding float64
 		var exitTime sql.NullString
-		if err := rows.Scan(&pnl, &fee, &exitTime); err != nil {
+		if err := rows.Scan(&pnl, &fee, &exitTime, &funding); err != nil {
 			continue
 		}
 
 		stats.TotalTrades++
 		stats.TotalPnL += pnl
 		stats.TotalFee += fee
+		stats.TotalFunding += funding
 		pnls = append(pnls, pnl)
 
 		if pnl > 0 {
@@ -327,6 +331,8 @@ func (s *PositionStore) GetFullStats(traderID string) (*TraderStats, error) {
 		}
 	}
 
+	stats.NetPnL = stats.TotalPnL - stats.TotalFee + stats.TotalFunding
+
 	// Calculate win rate
 	if stats.TotalTrades > 0 {
 		stats.WinRate = float64(stats.WinTrades) / float64(stats.TotalTrades) * 100
@@ -702,6 +708,12 @@ type HistorySummary struct {
 	TotalPnL       float64 `json:"total_pnl"`
 	AvgTradeReturn float64 `json:"avg_trade_return"` // Percentage
 
+	// Net PnL split (closed positions)
+	PricePnL    float64 `json:"price_pnl"`    // PnL from price movement
+	TradingFees float64 `json:"trading_fees"` // Trading fees paid
+	FundingPnL  float64 `json:"funding_pnl"`  // Funding received (positive) or paid (negative)
+	NetPnL      float64 `json:"net_pnl"`      // PricePnL - TradingFees + FundingPnL
+
 	// Best/Worst performers
 	BestSymbols  []SymbolStats `json:"best_symbols"`  // Top 3 profitable
 	WorstSymbols []SymbolStats `json:"worst_symbols"` // Top 3 losing
@@ -738,6 +750,10 @@ func (s *PositionStore) GetHistorySummary(traderID string) (*HistorySummary, err
 	summary.TotalTrades = fullStats.TotalTrades
 	summary.WinRate = fullStats.WinRate
 	summary.TotalPnL = fullStats.TotalPnL
+	summary.PricePnL = fullStats.TotalPnL
+	summary.TradingFees = fullStats.TotalFee
+	summary.FundingPnL = fullStats.TotalFunding
+	summary.NetPnL = fullStats.NetPnL
 	if fullStats.TotalTrades > 0 {
 		summary.AvgTradeReturn = fullStats.TotalPnL / float64(fullStats.TotalTrades)
 	}
@@ -906,6 +922,64 @@ func (s *PositionStore) ExistsWithExchangePositionID(exchangeID, exchangePositio
 	return count > 0, nil
 }
 
+// ExistsWithOrderID checks if a position was already recorded with the given entry or exit order ID
+// Used to avoid double-recording an order whose submission was retried (keyed by exchange order ID)
+func (s *PositionStore) ExistsWithOrderID(traderID, orderID string) (bool, error) {
+	if orderID == "" {
+		return false, nil
+	}
+
+	var count int
+	err := s.db.QueryRow(`
+		SELECT COUNT(*) FROM trader_positions
+		WHERE trader_id = ? AND (entry_order_id = ? OR exit_order_id = ?)
+	`, traderID, orderID, orderID).Scan(&count)
+	if err != nil {
+		return false, fmt.Errorf("failed to check order existence: %w", err)
+	}
+	return count > 0, nil
+}
+
+// ExistsClosedNear checks if a closed position of the same symbol and side was closed within window of exitTime
+// Used to avoid re-importing closes from exchange history that are already tracked locally
+func (s *PositionStore) ExistsClosedNear(traderID, symbol, side string, exitTime time.Time, window time.Duration) (bool, error) {
+	var count int
+	err := s.db.QueryRow(`
+		SELECT COUNT(*) FROM trader_positions
+		WHERE trader_id = ? AND symbol = ? AND side = ? AND status = 'CLOSED' AND exit_time IS NOT NULL
+		AND ABS(julianday(exit_time) - julianday(?)) * 86400 <= ?
+	`, traderID, symbol, strings.ToUpper(side), exitTime.Format(time.RFC3339), window.Seconds()).Scan(&count)
+	if err != nil {
+		return false, fmt.Errorf("failed to check closed position: %w", err)
+	}
+	return count > 0, nil
+}
+
+// DeleteSyncedClosedSince deletes closed positions synced from exchange history that closed at or after since
+// Positions tracked by the trader itself are kept; funding and journal entries of deleted positions are detached
+// (journal entries keep user edits and deletions and are re-linked to the rebuilt positions, see JournalStore.RelinkDetached;
+// funding is re-attributed by FundingStore.RelinkUnattributed)
+func (s *PositionStore) DeleteSyncedClosedSince(traderID string, since time.Time) (int64, error) {
+	where := `trader_id = ? AND status = 'CLOSED' AND source = 'sync'
+		AND julianday(exit_time) >= julianday(?)`
+	args := []interface{}{traderID, since.Format(time.RFC3339)}
+
+	if _, err := s.db.Exec(`UPDATE position_funding SET position_id = 0
+		WHERE position_id IN (SELECT id FROM trader_positions WHERE `+where+`)`, args...); err != nil {
+		return 0, fmt.Errorf("failed to detach funding payments: %w", err)
+	}
+	if _, err := s.db.Exec(`UPDATE trade_journal SET position_id = -id
+		WHERE position_id IN (SELECT id FROM trader_positions WHERE `+where+`)`, args...); err != nil {
+		return 0, fmt.Errorf("failed to detach journal entries: %w", err)
+	}
+
+	result, err := s.db.Exec(`DELETE FROM trader_positions WHERE `+where, args...)
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete synced positions: %w", err)
+	}
+	return result.RowsAffected()
+}
+
 // CreateFromClosedPnL creates a closed position record from exchange closed PnL data
 // This is used for syncing historical positions from exchange
 // Returns true if created, false if already exists (deduped) or invalid data
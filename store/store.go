@@ -16,15 +16,19 @@ type Store struct {
 	db *sql.DB
 
 	// Sub-stores (lazy initialization)
-	user     *UserStore
-	aiModel  *AIModelStore
-	exchange *ExchangeStore
-	trader   *TraderStore
-	decision *DecisionStore
-	backtest *BacktestStore
-	position *PositionStore
-	strategy *StrategyStore
-	equity   *EquityStore
+	user      *UserStore
+	aiModel   *AIModelStore
+	exchange  *ExchangeStore
+	trader    *TraderStore
+	decision  *DecisionStore
+	backtest  *BacktestStore
+	position  *PositionStore
+	strategy  *StrategyStore
+	equity    *EquityStore
+	funding   *FundingStore
+	execution *ExecutionStore
+	follower  *FollowerStore
+	journal   *JournalStore
 
 	// Encryption functions
 	encryptFunc func(string) string
@@ -137,12 +141,24 @@ func (s *Store) initTables() error {
 	if err := s.Position().InitTables(); err != nil {
 		return fmt.Errorf("failed to initialize position tables: %w", err)
 	}
+	if err := s.Funding().initTables(); err != nil {
+		return fmt.Errorf("failed to initialize funding tables: %w", err)
+	}
+	if err := s.Execution().initTables(); err != nil {
+		return fmt.Errorf("failed to initialize execution tables: %w", err)
+	}
+	if err := s.Follower().initTables(); err != nil {
+		return fmt.Errorf("failed to initialize follower tables: %w", err)
+	}
 	if err := s.Strategy().initTables(); err != nil {
 		return fmt.Errorf("failed to initialize strategy tables: %w", err)
 	}
 	if err := s.Equity().initTables(); err != nil {
 		return fmt.Errorf("failed to initialize equity tables: %w", err)
 	}
+	if err := s.Journal().initTables(); err != nil {
+		return fmt.Errorf("failed to initialize trade journal tables: %w", err)
+	}
 	return nil
 }
 
@@ -267,6 +283,46 @@ func (s *Store) Equity() *EquityStore {
 	return s.equity
 }
 
+// Funding gets funding payment storage
+func (s *Store) Funding() *FundingStore {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.funding == nil {
+		s.funding = &FundingStore{db: s.db}
+	}
+	return s.funding
+}
+
+// Execution gets order execution quality storage
+func (s *Store) Execution() *ExecutionStore {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.execution == nil {
+		s.execution = &ExecutionStore{db: s.db}
+	}
+	return s.execution
+}
+
+// Follower gets copy-trading follower storage
+func (s *Store) Follower() *FollowerStore {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.follower == nil {
+		s.follower = &FollowerStore{db: s.db}
+	}
+	return s.follower
+}
+
+// Journal gets trade journal storage
+func (s *Store) Journal() *JournalStore {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.journal == nil {
+		s.journal = &JournalStore{db: s.db}
+	}
+	return s.journal
+}
+
 // Close closes database connection
 func (s *Store) Close() error {
 	return s.db.Close()
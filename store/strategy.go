@@ -37,8 +37,30 @@ type StrategyConfig struct {
 	RiskControl RiskControlConfig `json:"risk_control"`
 	// editable sections of System Prompt
 	PromptSections PromptSectionsConfig `json:"prompt_sections,omitempty"`
+	// event-driven decision triggers (extra cycles between scan intervals)
+	Triggers TriggerConfig `json:"triggers,omitempty"`
+	// align scheduled cycles to the close of the primary timeframe candle
+	CandleAlignment CandleAlignmentConfig `json:"candle_alignment,omitempty"`
+	// when new positions may be opened (session windows and blackout calendar)
+	TradingSessions TradingSessionConfig `json:"trading_sessions,omitempty"`
+	// market regime classification and per-regime overrides
+	Regime RegimeConfig `json:"regime,omitempty"`
+	// how decisions are made: "ai" (default) or "rules" (declarative rules, no AI call)
+	Mode string `json:"mode,omitempty"`
+	// declarative trading rules, used when Mode is "rules"
+	Rules RuleConfig `json:"rules,omitempty"`
+	// pre-AI gate: cycles that don't pass it record a wait without calling the AI
+	AIGate AIGateConfig `json:"ai_gate,omitempty"`
+	// past trade journal entries added to the prompt
+	Journal JournalConfig `json:"journal,omitempty"`
 }
 
+// Strategy modes
+const (
+	StrategyModeAI    = "ai"
+	StrategyModeRules = "rules"
+)
+
 // PromptSectionsConfig editable sections of System Prompt
 type PromptSectionsConfig struct {
 	// role definition (title + description)
@@ -53,7 +75,7 @@ type PromptSectionsConfig struct {
 
 // CoinSourceConfig coin source configuration
 type CoinSourceConfig struct {
-	// source type: "static" | "coinpool" | "oi_top" | "mixed"
+	// source type: "static" | "coinpool" | "oi_top" | "mixed" | "screener"
 	SourceType string `json:"source_type"`
 	// static coin list (used when source_type = "static")
 	StaticCoins []string `json:"static_coins,omitempty"`
@@ -69,6 +91,24 @@ type CoinSourceConfig struct {
 	OITopLimit int `json:"oi_top_limit,omitempty"`
 	// OI Top API URL (strategy-level configuration)
 	OITopAPIURL string `json:"oi_top_api_url,omitempty"`
+	// built-in screener over the exchange universe (used when source_type = "screener")
+	Screener ScreenerConfig `json:"screener,omitempty"`
+}
+
+// ScreenerConfig built-in coin screener filters, optional bounds are nil when not set
+type ScreenerConfig struct {
+	MinQuoteVolume     float64  `json:"min_quote_volume,omitempty"`     // min 24h quote volume (USDT)
+	MinVolatilityPct   float64  `json:"min_volatility_pct,omitempty"`   // min 24h high-low range in %
+	MaxVolatilityPct   float64  `json:"max_volatility_pct,omitempty"`   // max 24h range in %, 0 = no limit
+	MinFundingRatePct  *float64 `json:"min_funding_rate_pct,omitempty"` // funding rate band in %
+	MaxFundingRatePct  *float64 `json:"max_funding_rate_pct,omitempty"`
+	MinOIChangePct     *float64 `json:"min_oi_change_pct,omitempty"` // open interest change band in %
+	MaxOIChangePct     *float64 `json:"max_oi_change_pct,omitempty"`
+	OIChangeHours      int      `json:"oi_change_hours,omitempty"`       // OI change window, default 24
+	MaxPriceChangeRank int      `json:"max_price_change_rank,omitempty"` // only the top N absolute 24h movers
+	MinListingDays     int      `json:"min_listing_days,omitempty"`
+	RankBy             string   `json:"rank_by,omitempty"` // "volume" (default) | "price_change" | "volatility" | "oi_change"
+	Limit              int      `json:"limit,omitempty"`   // max candidates, default 10
 }
 
 // IndicatorConfig indicator configuration
@@ -78,19 +118,26 @@ type IndicatorConfig struct {
 	// raw kline data (OHLCV) - always enabled, required for AI analysis
 	EnableRawKlines bool `json:"enable_raw_klines"`
 	// technical indicator switches
-	EnableEMA         bool `json:"enable_ema"`
-	EnableMACD        bool `json:"enable_macd"`
-	EnableRSI         bool `json:"enable_rsi"`
-	EnableATR         bool `json:"enable_atr"`
-	EnableVolume      bool `json:"enable_volume"`
-	EnableOI          bool `json:"enable_oi"`           // open interest
-	EnableFundingRate bool `json:"enable_funding_rate"` // funding rate
+	EnableEMA          bool `json:"enable_ema"`
+	EnableMACD         bool `json:"enable_macd"`
+	EnableRSI          bool `json:"enable_rsi"`
+	EnableATR          bool `json:"enable_atr"`
+	EnableVolume       bool `json:"enable_volume"`
+	EnableOI           bool `json:"enable_oi"`           // open interest
+	EnableFundingRate  bool `json:"enable_funding_rate"` // funding rate
+	EnableOrderBook    bool `json:"enable_order_book"`   // order book spread, imbalance, depth and walls
+	EnableTakerFlow    bool `json:"enable_taker_flow"`   // taker buy/sell volume, CVD and large trades from exchange trades
+	EnableLiquidations bool `json:"enable_liquidations"` // long/short liquidation notional over rolling windows
 	// EMA period configuration
 	EMAPeriods []int `json:"ema_periods,omitempty"` // default [20, 50]
 	// RSI period configuration
 	RSIPeriods []int `json:"rsi_periods,omitempty"` // default [7, 14]
 	// ATR period configuration
 	ATRPeriods []int `json:"atr_periods,omitempty"` // default [14]
+	// additional indicators from the market indicator registry (bollinger, vwap, adx, ...)
+	CustomIndicators []IndicatorSpec `json:"custom_indicators,omitempty"`
+	// user-defined series, e.g. {"name": "ema_spread", "expr": "ema(close,9) - ema(close,21)"}
+	Expressions []ExpressionSpec `json:"expressions,omitempty"`
 	// external data sources
 	ExternalDataSources []ExternalDataSource `json:"external_data_sources,omitempty"`
 	// quantitative data sources (capital flow, position changes, price changes)
@@ -105,6 +152,18 @@ type IndicatorConfig struct {
 	OIRankingLimit    int    `json:"oi_ranking_limit,omitempty"`    // number of entries (default 10)
 }
 
+// IndicatorSpec a registry indicator with its parameters, e.g. {"name": "bollinger", "params": {"period": 20, "stddev": 2}}
+type IndicatorSpec struct {
+	Name   string             `json:"name"`
+	Params map[string]float64 `json:"params,omitempty"` // missing params use the indicator defaults
+}
+
+// ExpressionSpec a named indicator expression evaluated over kline series
+type ExpressionSpec struct {
+	Name string `json:"name"`
+	Expr string `json:"expr"`
+}
+
 // KlineConfig K-line configuration
 type KlineConfig struct {
 	// primary timeframe: "1m", "3m", "5m", "15m", "1h", "4h"
@@ -176,6 +235,192 @@ type RiskControlConfig struct {
 	MinConfidence int `json:"min_confidence"`
 }
 
+// TriggerConfig event-driven decision trigger configuration
+// A trigger starts an out-of-band decision cycle when market or position conditions change
+// between scan intervals. Thresholds left at 0 disable the corresponding trigger.
+type TriggerConfig struct {
+	// whether event-driven triggers are enabled
+	Enabled bool `json:"enabled"`
+	// min seconds between two cycles, bounds AI cost (default 60)
+	MinIntervalSecs int `json:"min_interval_secs,omitempty"`
+	// price moved more than this percentage since the last cycle
+	PriceMovePct float64 `json:"price_move_pct,omitempty"`
+	// price moved more than this many ATR (3m, 14) since the last cycle
+	PriceMoveATR float64 `json:"price_move_atr,omitempty"`
+	// current 3m volume above this multiple of the average of the previous 20 klines
+	VolumeSpikeMultiple float64 `json:"volume_spike_multiple,omitempty"`
+	// funding rate changed sign since the last cycle
+	FundingFlip bool `json:"funding_flip,omitempty"`
+	// position PnL percentages (e.g. [-5, 5, 10]), crossing one starts a cycle
+	PositionPnLThresholds []float64 `json:"position_pnl_thresholds,omitempty"`
+	// accept external signals (POST /api/traders/:id/trigger)
+	ExternalSignals bool `json:"external_signals,omitempty"`
+}
+
+// CandleAlignmentConfig candle-close aligned cycle scheduling
+// When enabled, scheduled cycles start shortly after the primary timeframe candle closes
+// (instead of every ScanInterval from start) and the AI only sees closed candles,
+// matching backtest bar semantics.
+type CandleAlignmentConfig struct {
+	// whether cycles are aligned to candle closes
+	Enabled bool `json:"enabled"`
+	// decide every N primary candles (default: ScanInterval / primary timeframe, min 1)
+	CadenceNBars int `json:"cadence_nbars,omitempty"`
+	// seconds to wait after the close so the final kline has arrived (default 5)
+	CloseOffsetSecs int `json:"close_offset_secs,omitempty"`
+	// max extra seconds per trader, spreads API calls of traders sharing a timeframe
+	// (unset = 10, 0 = disabled so every trader decides right at the close offset, like backtests)
+	JitterSecs *int `json:"jitter_secs,omitempty"`
+}
+
+// TradingSessionConfig trading session windows and blackout calendar
+// Outside the session windows or during a blackout, no new positions are opened;
+// existing positions are still managed.
+type TradingSessionConfig struct {
+	// whether session restrictions are enabled
+	Enabled bool `json:"enabled"`
+	// IANA timezone of the windows, e.g. "America/New_York" (default UTC)
+	Timezone string `json:"timezone,omitempty"`
+	// windows in which new positions may be opened (empty = any time)
+	Windows []SessionWindow `json:"windows,omitempty"`
+	// one-off events (CPI, FOMC, ...) during which trading is restricted
+	Blackouts []BlackoutEvent `json:"blackouts,omitempty"`
+	// what to do with open positions during a blackout: "block_opens" (default) | "tighten_stops" | "flatten"
+	BlackoutAction string `json:"blackout_action,omitempty"`
+	// tighten_stops: max stop distance from mark price in percent (default 1)
+	TightenStopPct float64 `json:"tighten_stop_pct,omitempty"`
+}
+
+// Blackout actions
+const (
+	BlackoutActionBlockOpens   = "block_opens"
+	BlackoutActionTightenStops = "tighten_stops"
+	BlackoutActionFlatten      = "flatten"
+)
+
+// SessionWindow weekly window in the session timezone
+type SessionWindow struct {
+	// weekdays, e.g. ["mon", "tue"] (empty = every day)
+	Days []string `json:"days,omitempty"`
+	// "HH:MM", inclusive
+	Start string `json:"start"`
+	// "HH:MM", exclusive; earlier than Start means the window crosses midnight
+	End string `json:"end"`
+}
+
+// BlackoutEvent one-off restricted period
+type BlackoutEvent struct {
+	Name  string    `json:"name"`
+	Start time.Time `json:"start"`
+	End   time.Time `json:"end"`
+}
+
+// RegimeConfig market regime classification
+// Each symbol, and the overall market via BTC, is labelled from ADX, ATR and EMA slope
+// on the regime timeframe; overrides change behaviour per regime.
+type RegimeConfig struct {
+	// whether regime classification is enabled
+	Enabled bool `json:"enabled"`
+	// timeframe the features are computed on (default: primary timeframe)
+	Timeframe string `json:"timeframe,omitempty"`
+	// ADX at or above which a symbol is trending (default 25)
+	TrendADX float64 `json:"trend_adx,omitempty"`
+	// ATR relative to its recent average at or above which a symbol is high-volatility (default 1.5)
+	HighVolATRRatio float64 `json:"high_vol_atr_ratio,omitempty"`
+	// ATR as percent of price at or above which a symbol is high-volatility (0 = not used)
+	HighVolATRPct float64 `json:"high_vol_atr_pct,omitempty"`
+	// average quote volume per bar (USDT) below which a symbol is low-liquidity (0 = not used)
+	MinQuoteVolume float64 `json:"min_quote_volume,omitempty"`
+	// behaviour changes applied in a regime
+	Overrides []RegimeOverride `json:"overrides,omitempty"`
+}
+
+// Regime labels
+const (
+	RegimeTrendingUp     = "trending_up"
+	RegimeTrendingDown   = "trending_down"
+	RegimeRanging        = "ranging"
+	RegimeHighVolatility = "high_volatility"
+	RegimeLowLiquidity   = "low_liquidity"
+)
+
+// Regime override scopes
+const (
+	RegimeScopeSymbol = "symbol" // Applies when the traded symbol is in the regime
+	RegimeScopeMarket = "market" // Applies to every symbol when the market (BTC) is in the regime
+)
+
+// RegimeOverride behaviour changes while a regime is active
+type RegimeOverride struct {
+	// regime label the override applies to
+	Regime string `json:"regime"`
+	// "symbol" (default) or "market"
+	Scope string `json:"scope,omitempty"`
+	// max leverage for new positions (0 = unchanged)
+	MaxLeverage int `json:"max_leverage,omitempty"`
+	// extra prompt section shown while the regime is active
+	PromptSection string `json:"prompt_section,omitempty"`
+	// no new positions while the regime is active
+	BlockNewEntries bool `json:"block_new_entries,omitempty"`
+}
+
+// RuleConfig declarative trading rules evaluated on closed klines
+// e.g. open_long when ema(close,9) crosses_above ema(close,21) and rsi(close,14) < 70, SL = 1.5 ATR, TP = 3 ATR
+type RuleConfig struct {
+	// timeframe the rules are evaluated on (default: primary timeframe)
+	Timeframe string `json:"timeframe,omitempty"`
+	// rules in priority order, the first matching open rule of a symbol wins
+	Rules []TradingRule `json:"rules,omitempty"`
+}
+
+// TradingRule an action taken when all conditions hold on the last closed kline
+type TradingRule struct {
+	Name string `json:"name,omitempty"`
+	// "open_long" | "open_short" | "close_long" | "close_short"
+	Action     string          `json:"action"`
+	Conditions []RuleCondition `json:"conditions"`
+	// stop loss and take profit distance from the entry in ATR multiples (open rules)
+	StopLossATR   float64 `json:"stop_loss_atr,omitempty"`
+	TakeProfitATR float64 `json:"take_profit_atr,omitempty"`
+	// ATR period for stop loss and take profit (default 14)
+	ATRPeriod int `json:"atr_period,omitempty"`
+	// leverage of new positions (default: risk control maximum)
+	Leverage int `json:"leverage,omitempty"`
+	// position value in percent of account equity (default: max position value / max positions)
+	PositionSizePct float64 `json:"position_size_pct,omitempty"`
+}
+
+// RuleCondition compares two indicator expressions, e.g. {"left": "rsi(close,14)", "op": "<", "right": "70"}
+type RuleCondition struct {
+	Left string `json:"left"`
+	// ">" | ">=" | "<" | "<=" | "crosses_above" | "crosses_below"
+	Op    string `json:"op"`
+	Right string `json:"right"`
+}
+
+// AIGateConfig when a cycle calls the AI
+// The AI is called when a position is open, the cycle was started by a trigger,
+// or a candidate meets all conditions; otherwise the cycle records a wait.
+type AIGateConfig struct {
+	// whether the gate is enabled
+	Enabled bool `json:"enabled"`
+	// timeframe the conditions are evaluated on (default: primary timeframe)
+	Timeframe string `json:"timeframe,omitempty"`
+	// conditions a candidate must all meet on the last closed kline (required unless triggers are enabled)
+	Conditions []RuleCondition `json:"conditions,omitempty"`
+}
+
+// JournalConfig which trade journal entries are added to the prompt
+// Entries on the same symbol rank first, then entries opened in a current regime, then the most recent.
+type JournalConfig struct {
+	// whether journal entries are added to the prompt (entries are recorded either way)
+	Enabled bool `json:"enabled"`
+	// max entries in the prompt (default 5)
+	MaxEntries int `json:"max_entries,omitempty"`
+	// approximate token budget of the journal section (default 600)
+	TokenBudget int `json:"token_budget,omitempty"`
+}
+
 func (s *StrategyStore) initTables() error {
 	_, err := s.db.Exec(`
 		CREATE TABLE IF NOT EXISTS strategies (
@@ -0,0 +1,277 @@
+package trader
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+// AccountEventType account stream event type
+type AccountEventType string
+
+const (
+	AccountEventConnected AccountEventType = "connected"  // Stream authenticated and subscribed
+	AccountEventOrderFill AccountEventType = "order_fill" // Order (partially) filled
+	AccountEventPosition  AccountEventType = "position"   // Position size changed
+	AccountEventBalance   AccountEventType = "balance"    // Wallet balance changed
+)
+
+// Close reasons reported for closing fills triggered by the exchange
+const (
+	CloseReasonStopLoss    = "stop_loss"
+	CloseReasonTakeProfit  = "take_profit"
+	CloseReasonLiquidation = "liquidation"
+)
+
+// AccountEvent unified event from exchange user-data streams
+type AccountEvent struct {
+	Type   AccountEventType
+	Time   time.Time
+	Symbol string // Unified symbol, e.g. BTCUSDT
+	Side   string // Position side: LONG / SHORT
+
+	// Order fill fields
+	OrderID       string
+	ClientOrderID string
+	Price         float64 // Fill price of this execution
+	Quantity      float64 // Filled quantity of this execution
+	OrderFilled   bool    // Order is completely filled
+	ReduceOnly    bool    // Fill reduces/closes a position
+	RealizedPnL   float64
+	Fee           float64 // Positive = paid
+	CloseReason   string  // stop_loss / take_profit / liquidation, empty for regular orders
+
+	// Position fields
+	PositionAmt float64 // Absolute position size after the update (0 = closed)
+	EntryPrice  float64
+
+	// Balance fields
+	Asset   string
+	Balance float64
+}
+
+// AccountStream is implemented by exchanges that provide a user-data websocket
+// Position sync only acts on closes (stop loss, take profit, liquidation); opens, increases and
+// balance updates are left to order confirmation and polling (see handleAccountEvent)
+type AccountStream interface {
+	// StreamAccount connects to the user-data stream and delivers events to handler
+	// Blocks until ctx is cancelled (returns nil) or the connection drops (returns error)
+	StreamAccount(ctx context.Context, handler func(AccountEvent)) error
+}
+
+// ============================================================================
+// Fill hub - hands stream fills to order confirmation
+// ============================================================================
+
+// streamFill aggregated fills of a single order
+type streamFill struct {
+	qty      float64
+	notional float64
+	fee      float64
+	filled   bool
+	filledAt time.Time // Exchange time of the completing fill (arrival time when not reported)
+	updated  time.Time
+}
+
+// accountFillHub collects order fills from account streams so that
+// recordAndConfirmOrder can confirm orders without polling GetOrderStatus
+type accountFillHub struct {
+	mu     sync.Mutex
+	active map[string]bool        // trader ID -> stream connected
+	fills  map[string]*streamFill // trader ID|order key -> aggregated fill
+	notify chan struct{}          // Closed and replaced on every publish
+}
+
+// fillHubRetention how long aggregated fills are kept for confirmation
+const fillHubRetention = 10 * time.Minute
+
+var fillHub = newAccountFillHub()
+
+func newAccountFillHub() *accountFillHub {
+	return &accountFillHub{
+		active: make(map[string]bool),
+		fills:  make(map[string]*streamFill),
+		notify: make(chan struct{}),
+	}
+}
+
+// setActive marks whether the account stream of a trader is connected
+func (h *accountFillHub) setActive(traderID string, active bool) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if active {
+		h.active[traderID] = true
+	} else {
+		delete(h.active, traderID)
+	}
+}
+
+// isActive reports whether the account stream of a trader is connected
+func (h *accountFillHub) isActive(traderID string) bool {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return h.active[traderID]
+}
+
+// publish records a fill event under both exchange order ID and client order ID
+func (h *accountFillHub) publish(traderID string, ev AccountEvent) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	now := time.Now()
+	for key, fill := range h.fills {
+		if now.Sub(fill.updated) > fillHubRetention {
+			delete(h.fills, key)
+		}
+	}
+
+	// Both keys share one aggregate
+	var fill *streamFill
+	for _, id := range []string{ev.OrderID, ev.ClientOrderID} {
+		if id == "" {
+			continue
+		}
+		if existing, ok := h.fills[traderID+"|"+id]; ok {
+			fill = existing
+			break
+		}
+	}
+	if fill == nil {
+		fill = &streamFill{}
+	}
+	fill.qty += ev.Quantity
+	fill.notional += ev.Price * ev.Quantity
+	fill.fee += ev.Fee
+	if ev.OrderFilled && !fill.filled {
+		fill.filled = true
+		fill.filledAt = ev.Time
+		if fill.filledAt.IsZero() {
+			fill.filledAt = now
+		}
+	}
+	fill.updated = now
+	for _, id := range []string{ev.OrderID, ev.ClientOrderID} {
+		if id != "" {
+			h.fills[traderID+"|"+id] = fill
+		}
+	}
+
+	close(h.notify)
+	h.notify = make(chan struct{})
+}
+
+// waitForFill waits until the order identified by orderID or clientOrderID is completely filled
+// Returns average price, filled quantity, fee and fill time. ok=false when the stream is not
+// connected or the fill did not arrive in time (caller should fall back to polling).
+func (h *accountFillHub) waitForFill(traderID, orderID, clientOrderID string, timeout time.Duration) (orderFill, bool) {
+	if !h.isActive(traderID) {
+		return orderFill{}, false
+	}
+
+	deadline := time.NewTimer(timeout)
+	defer deadline.Stop()
+
+	for {
+		h.mu.Lock()
+		for _, id := range []string{orderID, clientOrderID} {
+			if id == "" {
+				continue
+			}
+			if fill, found := h.fills[traderID+"|"+id]; found && fill.filled && fill.qty > 0 {
+				h.mu.Unlock()
+				return orderFill{price: fill.notional / fill.qty, qty: fill.qty, fee: fill.fee, time: fill.filledAt}, true
+			}
+		}
+		notify := h.notify
+		h.mu.Unlock()
+
+		select {
+		case <-notify:
+		case <-deadline.C:
+			return orderFill{}, false
+		}
+	}
+}
+
+// ============================================================================
+// Websocket helper shared by Bybit / OKX / Hyperliquid streams
+// ============================================================================
+
+// accountWSConn websocket connection with serialized writes
+type accountWSConn struct {
+	conn *websocket.Conn
+	mu   sync.Mutex
+}
+
+func (c *accountWSConn) writeJSON(v interface{}) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.conn.SetWriteDeadline(time.Now().Add(10 * time.Second))
+	return c.conn.WriteJSON(v)
+}
+
+func (c *accountWSConn) writeText(text string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.conn.SetWriteDeadline(time.Now().Add(10 * time.Second))
+	return c.conn.WriteMessage(websocket.TextMessage, []byte(text))
+}
+
+// runAccountWebsocket dials url and runs the read loop until ctx is cancelled or the connection fails
+// onOpen sends authentication/subscription, ping is called every pingInterval,
+// onMessage handles every received message (returning an error drops the connection)
+func runAccountWebsocket(ctx context.Context, url string, pingInterval time.Duration,
+	onOpen func(c *accountWSConn) error,
+	ping func(c *accountWSConn) error,
+	onMessage func(c *accountWSConn, msg []byte) error) error {
+
+	dialer := websocket.Dialer{HandshakeTimeout: 10 * time.Second}
+	conn, _, err := dialer.DialContext(ctx, url, nil)
+	if err != nil {
+		return fmt.Errorf("websocket connection failed: %w", err)
+	}
+	defer conn.Close()
+
+	c := &accountWSConn{conn: conn}
+	if err := onOpen(c); err != nil {
+		return err
+	}
+
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		ticker := time.NewTicker(pingInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				conn.Close() // Unblock ReadMessage
+				return
+			case <-done:
+				return
+			case <-ticker.C:
+				if err := ping(c); err != nil {
+					conn.Close()
+					return
+				}
+			}
+		}
+	}()
+
+	for {
+		conn.SetReadDeadline(time.Now().Add(3 * pingInterval))
+		_, msg, err := conn.ReadMessage()
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			return fmt.Errorf("websocket read failed: %w", err)
+		}
+		if err := onMessage(c, msg); err != nil {
+			return err
+		}
+	}
+}
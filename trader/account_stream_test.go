@@ -0,0 +1,271 @@
+package trader
+
+import (
+	"context"
+	"encoding/json"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"nofx/store"
+
+	"github.com/adshao/go-binance/v2/futures"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestAccountStream_InterfaceCompliance tests which exchanges provide account streams
+func TestAccountStream_InterfaceCompliance(t *testing.T) {
+	var _ AccountStream = (*FuturesTrader)(nil)
+	var _ AccountStream = (*BybitTrader)(nil)
+	var _ AccountStream = (*OKXTrader)(nil)
+	var _ AccountStream = (*HyperliquidTrader)(nil)
+}
+
+// TestAccountFillHub tests fill aggregation and waiting
+func TestAccountFillHub(t *testing.T) {
+	hub := newAccountFillHub()
+
+	// Inactive stream returns immediately
+	_, ok := hub.waitForFill("trader-1", "1", "", time.Second)
+	assert.False(t, ok)
+
+	hub.setActive("trader-1", true)
+	go func() {
+		time.Sleep(10 * time.Millisecond)
+		hub.publish("trader-1", AccountEvent{Type: AccountEventOrderFill, OrderID: "1", ClientOrderID: "cid", Price: 100, Quantity: 1, Fee: 0.1})
+		hub.publish("trader-1", AccountEvent{Type: AccountEventOrderFill, OrderID: "1", ClientOrderID: "cid", Price: 102, Quantity: 1, Fee: 0.1, OrderFilled: true})
+	}()
+
+	fill, ok := hub.waitForFill("trader-1", "", "cid", time.Second)
+	assert.True(t, ok)
+	assert.InDelta(t, 101.0, fill.price, 1e-9)
+	assert.InDelta(t, 2.0, fill.qty, 1e-9)
+	assert.InDelta(t, 0.2, fill.fee, 1e-9)
+	assert.False(t, fill.time.IsZero(), "fill time falls back to the arrival time")
+
+	// Unknown order times out
+	_, ok = hub.waitForFill("trader-1", "2", "", 20*time.Millisecond)
+	assert.False(t, ok)
+}
+
+// blockingStreamTrader account stream that stays connected until cancelled
+type blockingStreamTrader struct {
+	Trader
+	closed chan struct{}
+}
+
+func (s *blockingStreamTrader) StreamAccount(ctx context.Context, handler func(AccountEvent)) error {
+	handler(AccountEvent{Type: AccountEventConnected})
+	<-ctx.Done()
+	s.closed <- struct{}{}
+	return nil
+}
+
+// TestReleaseAccountStream tests streams are closed when the trader stops or no longer needs one
+func TestReleaseAccountStream(t *testing.T) {
+	m := NewPositionSyncManager(nil, time.Second)
+	registerStreamOwner(m, true)
+	defer registerStreamOwner(m, false)
+
+	stream := &blockingStreamTrader{closed: make(chan struct{}, 1)}
+	m.traderCache["trader-stream-test"] = stream
+	waitClosed := func() {
+		select {
+		case <-stream.closed:
+		case <-time.After(time.Second):
+			t.Fatal("account stream not closed")
+		}
+	}
+
+	// Trader stopped or removed from memory
+	m.ensureAccountStreams(map[string]bool{"trader-stream-test": true})
+	ReleaseAccountStream("trader-stream-test")
+	waitClosed()
+	assert.Empty(t, m.streams)
+
+	// Trader no longer running and without open positions
+	m.ensureAccountStreams(map[string]bool{"trader-stream-test": true})
+	m.ensureAccountStreams(map[string]bool{})
+	waitClosed()
+	assert.Empty(t, m.streams)
+	m.wg.Wait()
+}
+
+// TestHandleAccountEvent_OnlyCloses tests open/increase updates are skipped and stop loss closes recorded
+func TestHandleAccountEvent_OnlyCloses(t *testing.T) {
+	st, err := store.New(filepath.Join(t.TempDir(), "test.db"))
+	require.NoError(t, err)
+	defer st.Close()
+
+	const traderID = "trader-events-test"
+	pos := &store.TraderPosition{TraderID: traderID, Symbol: "BTCUSDT", Side: "LONG", Quantity: 0.1, EntryPrice: 50000, EntryTime: time.Now(), Status: "OPEN"}
+	require.NoError(t, st.Position().Create(pos))
+
+	m := NewPositionSyncManager(st, time.Second)
+	m.streams[traderID] = &accountStreamState{cancel: func() {}, connected: true, pendingCloses: make(map[string]*pendingClose)}
+
+	// Increase: fill plus a larger position size
+	m.handleAccountEvent(traderID, AccountEvent{Type: AccountEventOrderFill, Symbol: "BTCUSDT", Side: "LONG", Price: 51000, Quantity: 0.1})
+	m.handleAccountEvent(traderID, AccountEvent{Type: AccountEventPosition, Symbol: "BTCUSDT", Side: "LONG", PositionAmt: 0.2, EntryPrice: 50500})
+	m.handleAccountEvent(traderID, AccountEvent{Type: AccountEventBalance, Asset: "USDT", Balance: 1000})
+	open, err := st.Position().GetOpenPositionBySymbol(traderID, "BTCUSDT", "LONG")
+	require.NoError(t, err)
+	require.NotNil(t, open)
+	assert.Equal(t, 0.1, open.Quantity)
+	assert.Empty(t, m.streams[traderID].pendingCloses)
+
+	// Stop loss close
+	m.handleAccountEvent(traderID, AccountEvent{Type: AccountEventOrderFill, Symbol: "BTCUSDT", Side: "LONG", Price: 49000, Quantity: 0.2,
+		ReduceOnly: true, RealizedPnL: -300, CloseReason: CloseReasonStopLoss, OrderID: "sl-1", Time: time.Now()})
+	m.handleAccountEvent(traderID, AccountEvent{Type: AccountEventPosition, Symbol: "BTCUSDT", Side: "LONG"})
+	open, err = st.Position().GetOpenPositionBySymbol(traderID, "BTCUSDT", "LONG")
+	require.NoError(t, err)
+	assert.Nil(t, open)
+}
+
+// TestBinanceAccountEvents tests Binance user data conversion
+func TestBinanceAccountEvents(t *testing.T) {
+	event := &futures.WsUserDataEvent{
+		Event: futures.UserDataEventTypeOrderTradeUpdate,
+		WsUserDataOrderTradeUpdate: futures.WsUserDataOrderTradeUpdate{
+			OrderTradeUpdate: futures.WsOrderTradeUpdate{
+				Symbol:          "BTCUSDT",
+				ClientOrderID:   "x-KzrpZaP9abc",
+				Side:            futures.SideTypeSell,
+				Type:            futures.OrderTypeMarket,
+				OriginalType:    futures.OrderTypeStopMarket,
+				ExecutionType:   futures.OrderExecutionTypeTrade,
+				Status:          futures.OrderStatusTypeFilled,
+				ID:              123,
+				LastFilledQty:   "0.01",
+				LastFilledPrice: "95000",
+				Commission:      "0.38",
+				RealizedPnL:     "-50",
+				PositionSide:    futures.PositionSideTypeLong,
+			},
+		},
+	}
+
+	events := binanceAccountEvents(event)
+	assert.Len(t, events, 1)
+	fill := events[0]
+	assert.Equal(t, AccountEventOrderFill, fill.Type)
+	assert.Equal(t, "LONG", fill.Side)
+	assert.Equal(t, "123", fill.OrderID)
+	assert.True(t, fill.ReduceOnly)
+	assert.True(t, fill.OrderFilled)
+	assert.Equal(t, CloseReasonStopLoss, fill.CloseReason)
+	assert.InDelta(t, -50.0, fill.RealizedPnL, 1e-9)
+
+	// One-way mode flat position has no side
+	event = &futures.WsUserDataEvent{
+		Event: futures.UserDataEventTypeAccountUpdate,
+		WsUserDataAccountUpdate: futures.WsUserDataAccountUpdate{
+			AccountUpdate: futures.WsAccountUpdate{
+				Positions: []futures.WsPosition{{Symbol: "BTCUSDT", Side: futures.PositionSideTypeBoth, Amount: "0"}},
+			},
+		},
+	}
+	events = binanceAccountEvents(event)
+	assert.Len(t, events, 1)
+	assert.Equal(t, AccountEventPosition, events[0].Type)
+	assert.Equal(t, "", events[0].Side)
+	assert.Equal(t, 0.0, events[0].PositionAmt)
+}
+
+// TestBybitAccountEvents tests Bybit private topic conversion
+func TestBybitAccountEvents(t *testing.T) {
+	data, _ := json.Marshal([]map[string]string{{
+		"category":      "linear",
+		"symbol":        "ETHUSDT",
+		"orderId":       "o-1",
+		"orderLinkId":   "cid-1",
+		"side":          "Buy",
+		"execPrice":     "3000",
+		"execQty":       "1",
+		"execFee":       "1.8",
+		"execPnl":       "120",
+		"execType":      "Trade",
+		"execTime":      "1700000000000",
+		"leavesQty":     "0",
+		"closedSize":    "1",
+		"stopOrderType": "TakeProfit",
+	}})
+
+	events := bybitAccountEvents(bybitWSMessage{Topic: "execution", Data: data})
+	assert.Len(t, events, 1)
+	assert.Equal(t, "SHORT", events[0].Side, "closing buy belongs to short position")
+	assert.True(t, events[0].ReduceOnly)
+	assert.True(t, events[0].OrderFilled)
+	assert.Equal(t, CloseReasonTakeProfit, events[0].CloseReason)
+	assert.Equal(t, "cid-1", events[0].ClientOrderID)
+}
+
+// TestHyperliquidFillEvents tests position derivation from Hyperliquid fills
+func TestHyperliquidFillEvents(t *testing.T) {
+	// Full close of a long position by liquidation
+	events := hyperliquidFillEvents(hyperliquidWSFill{
+		Coin:          "BTC",
+		Px:            "90000",
+		Sz:            "0.5",
+		Side:          "A",
+		StartPosition: "0.5",
+		ClosedPnl:     "-2500",
+		Oid:           42,
+		Fee:           "10",
+		Liquidation:   json.RawMessage(`{"method":"market"}`),
+	}, false)
+	assert.Len(t, events, 2)
+	assert.Equal(t, "LONG", events[0].Side)
+	assert.True(t, events[0].ReduceOnly)
+	assert.Equal(t, CloseReasonLiquidation, events[0].CloseReason)
+	assert.Equal(t, AccountEventPosition, events[1].Type)
+	assert.Equal(t, "LONG", events[1].Side)
+	assert.Equal(t, 0.0, events[1].PositionAmt)
+
+	// Flip from short to long closes the short and opens a long
+	events = hyperliquidFillEvents(hyperliquidWSFill{Coin: "ETH", Px: "3000", Sz: "3", Side: "B", StartPosition: "-1", Cloid: "0xabc"}, true)
+	assert.Equal(t, "0xabc", events[0].ClientOrderID)
+	assert.True(t, events[0].OrderFilled)
+	assert.Len(t, events, 3)
+	assert.Equal(t, "SHORT", events[1].Side)
+	assert.Equal(t, 0.0, events[1].PositionAmt)
+	assert.Equal(t, "LONG", events[2].Side)
+	assert.InDelta(t, 2.0, events[2].PositionAmt, 1e-9)
+}
+
+// TestHyperliquidOrderTracker tests order completion from partial fills and order updates in either order
+func TestHyperliquidOrderTracker(t *testing.T) {
+	orders := newHyperliquidOrderTracker()
+	update := func(oid int64, status, origSz, sz string) *AccountEvent {
+		var u hyperliquidWSOrderUpdate
+		u.Order.Coin, u.Order.Oid, u.Order.OrigSz, u.Order.Sz, u.Order.Cloid = "BTC", oid, origSz, sz, "0xc1"
+		u.Status = status
+		return orders.update(u)
+	}
+
+	// Update first: the fill reaching the order size completes it
+	assert.Nil(t, update(1, "open", "1.0", "1.0"))
+	assert.False(t, orders.fill(hyperliquidWSFill{Oid: 1, Sz: "0.4"}))
+	assert.True(t, orders.fill(hyperliquidWSFill{Oid: 1, Sz: "0.6"}))
+	assert.Nil(t, update(1, "filled", "1.0", "0.0"), "already reported by the completing fill")
+
+	// Fills first: the filled status completes it
+	assert.False(t, orders.fill(hyperliquidWSFill{Oid: 2, Sz: "0.1"}))
+	assert.False(t, orders.fill(hyperliquidWSFill{Oid: 2, Sz: "0.2"}))
+	ev := update(2, "filled", "0.3", "0.0")
+	require.NotNil(t, ev)
+	assert.True(t, ev.OrderFilled)
+	assert.Equal(t, "2", ev.OrderID)
+	assert.Equal(t, "0xc1", ev.ClientOrderID)
+	assert.Zero(t, ev.Quantity)
+
+	// IOC partially filled then canceled completes at the filled part
+	assert.False(t, orders.fill(hyperliquidWSFill{Oid: 3, Sz: "0.5"}))
+	require.NotNil(t, update(3, "canceled", "2.0", "1.5"))
+
+	// Canceled without fills is dropped
+	assert.Nil(t, update(4, "canceled", "1.0", "1.0"))
+	assert.NotContains(t, orders.orders, int64(4))
+}
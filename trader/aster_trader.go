@@ -9,7 +9,6 @@ import (
 	"fmt"
 	"io"
 	"nofx/logger"
-	"math"
 	"math/big"
 	"net/http"
 	"net/url"
@@ -17,7 +16,6 @@ import (
 	"sort"
 	"strconv"
 	"strings"
-	"sync"
 	"time"
 
 	"github.com/ethereum/go-ethereum/accounts/abi"
@@ -33,18 +31,6 @@ type AsterTrader struct {
 	privateKey *ecdsa.PrivateKey // API wallet private key
 	client     *http.Client
 	baseURL    string
-
-	// Cache symbol precision information
-	symbolPrecision map[string]SymbolPrecision
-	mu              sync.RWMutex
-}
-
-// SymbolPrecision Symbol precision information
-type SymbolPrecision struct {
-	PricePrecision    int
-	QuantityPrecision int
-	TickSize          float64 // Price tick size
-	StepSize          float64 // Quantity step size
 }
 
 // NewAsterTrader Create Aster trader
@@ -71,13 +57,12 @@ func NewAsterTrader(user, signer, privateKeyHex string) (*AsterTrader, error) {
 	}
 
 	return &AsterTrader{
-		ctx:             context.Background(),
-		user:            user,
-		signer:          signer,
-		privateKey:      privKey,
-		symbolPrecision: make(map[string]SymbolPrecision),
-		client:          client,
-		baseURL:         "https://fapi.asterdex.com",
+		ctx:        context.Background(),
+		user:       user,
+		signer:     signer,
+		privateKey: privKey,
+		client:     client,
+		baseURL:    "https://fapi.asterdex.com",
 	}, nil
 }
 
@@ -86,127 +71,60 @@ func (t *AsterTrader) genNonce() uint64 {
 	return uint64(time.Now().UnixMicro())
 }
 
-// getPrecision Get symbol precision information
-func (t *AsterTrader) getPrecision(symbol string) (SymbolPrecision, error) {
-	t.mu.RLock()
-	if prec, ok := t.symbolPrecision[symbol]; ok {
-		t.mu.RUnlock()
-		return prec, nil
-	}
-	t.mu.RUnlock()
-
-	// Get exchange information
+// GetInstruments gets trading rules of all USDT perpetuals (implements InstrumentProvider)
+func (t *AsterTrader) GetInstruments() ([]Instrument, error) {
 	resp, err := t.client.Get(t.baseURL + "/fapi/v3/exchangeInfo")
 	if err != nil {
-		return SymbolPrecision{}, err
+		return nil, err
 	}
 	defer resp.Body.Close()
 
-	body, _ := io.ReadAll(resp.Body)
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
 	var info struct {
 		Symbols []struct {
-			Symbol            string                   `json:"symbol"`
-			PricePrecision    int                      `json:"pricePrecision"`
-			QuantityPrecision int                      `json:"quantityPrecision"`
-			Filters           []map[string]interface{} `json:"filters"`
+			Symbol       string                   `json:"symbol"`
+			Status       string                   `json:"status"`
+			ContractType string                   `json:"contractType"`
+			QuoteAsset   string                   `json:"quoteAsset"`
+			Filters      []map[string]interface{} `json:"filters"`
 		} `json:"symbols"`
 	}
-
 	if err := json.Unmarshal(body, &info); err != nil {
-		return SymbolPrecision{}, err
+		return nil, err
 	}
 
-	// Cache precision for all symbols
-	t.mu.Lock()
+	var instruments []Instrument
 	for _, s := range info.Symbols {
-		prec := SymbolPrecision{
-			PricePrecision:    s.PricePrecision,
-			QuantityPrecision: s.QuantityPrecision,
+		if s.QuoteAsset != "USDT" || s.Status != "TRADING" || (s.ContractType != "" && s.ContractType != "PERPETUAL") {
+			continue
 		}
-
-		// Parse filters to get tickSize and stepSize
+		inst := Instrument{Symbol: s.Symbol, VenueSymbol: s.Symbol, ContractMultiplier: 1}
 		for _, filter := range s.Filters {
-			filterType, _ := filter["filterType"].(string)
-			switch filterType {
+			value := func(key string) float64 {
+				v, _ := filter[key].(string)
+				return parseFloatOrZero(v)
+			}
+			switch filter["filterType"] {
 			case "PRICE_FILTER":
-				if tickSizeStr, ok := filter["tickSize"].(string); ok {
-					prec.TickSize, _ = strconv.ParseFloat(tickSizeStr, 64)
-				}
+				inst.TickSize = value("tickSize")
 			case "LOT_SIZE":
-				if stepSizeStr, ok := filter["stepSize"].(string); ok {
-					prec.StepSize, _ = strconv.ParseFloat(stepSizeStr, 64)
-				}
+				inst.LotStep = value("stepSize")
+				inst.MinQty = value("minQty")
+			case "MIN_NOTIONAL":
+				inst.MinNotional = value("notional")
 			}
 		}
-
-		t.symbolPrecision[s.Symbol] = prec
-	}
-	t.mu.Unlock()
-
-	if prec, ok := t.symbolPrecision[symbol]; ok {
-		return prec, nil
-	}
-
-	return SymbolPrecision{}, fmt.Errorf("precision information not found for symbol %s", symbol)
-}
-
-// roundToTickSize Round price/quantity to the nearest multiple of tick size/step size
-func roundToTickSize(value float64, tickSize float64) float64 {
-	if tickSize <= 0 {
-		return value
-	}
-	// Calculate how many tick sizes
-	steps := value / tickSize
-	// Round to the nearest integer
-	roundedSteps := math.Round(steps)
-	// Multiply back by tick size
-	return roundedSteps * tickSize
-}
-
-// formatPrice Format price to correct precision and tick size
-func (t *AsterTrader) formatPrice(symbol string, price float64) (float64, error) {
-	prec, err := t.getPrecision(symbol)
-	if err != nil {
-		return 0, err
-	}
-
-	// Prioritize tick size to ensure price is a multiple of tick size
-	if prec.TickSize > 0 {
-		return roundToTickSize(price, prec.TickSize), nil
-	}
-
-	// If no tick size, round by precision
-	multiplier := math.Pow10(prec.PricePrecision)
-	return math.Round(price*multiplier) / multiplier, nil
-}
-
-// formatQuantity Format quantity to correct precision and step size
-func (t *AsterTrader) formatQuantity(symbol string, quantity float64) (float64, error) {
-	prec, err := t.getPrecision(symbol)
-	if err != nil {
-		return 0, err
+		instruments = append(instruments, inst)
 	}
-
-	// Prioritize step size to ensure quantity is a multiple of step size
-	if prec.StepSize > 0 {
-		return roundToTickSize(quantity, prec.StepSize), nil
-	}
-
-	// If no step size, round by precision
-	multiplier := math.Pow10(prec.QuantityPrecision)
-	return math.Round(quantity*multiplier) / multiplier, nil
+	return instruments, nil
 }
 
-// formatFloatWithPrecision Format float to string with specified precision (remove trailing zeros)
-func (t *AsterTrader) formatFloatWithPrecision(value float64, precision int) string {
-	// Format with specified precision
-	formatted := strconv.FormatFloat(value, 'f', precision, 64)
-
-	// Remove trailing zeros and decimal point (if any)
-	formatted = strings.TrimRight(formatted, "0")
-	formatted = strings.TrimRight(formatted, ".")
-
-	return formatted
+// instrument gets a symbol's tick size and lot step from the instrument registry
+func (t *AsterTrader) instrument(symbol string) (*Instrument, error) {
+	return venueInstrument(Venue{Exchange: "aster"}, t, symbol)
 }
 
 // normalizeAndStringify Normalize parameters and serialize to JSON string (sorted by key)
@@ -481,6 +399,7 @@ func (t *AsterTrader) GetBalance() (map[string]interface{}, error) {
 			"totalWalletBalance":    crossWalletBalance,
 			"availableBalance":      availableBalance,
 			"totalUnrealizedProfit": crossUnPnl,
+			"total_equity":          crossWalletBalance + crossUnPnl,
 		}, nil
 	}
 
@@ -516,6 +435,7 @@ func (t *AsterTrader) GetBalance() (map[string]interface{}, error) {
 		"totalWalletBalance":    totalWalletBalance, // Wallet balance (excluding unrealized PnL)
 		"availableBalance":      availableBalance,   // Available balance
 		"totalUnrealizedProfit": realUnrealizedPnl,  // Unrealized PnL (accumulated from positions)
+		"total_equity":          totalEquity,        // Account equity (wallet + unrealized)
 	}, nil
 }
 
@@ -600,28 +520,15 @@ func (t *AsterTrader) OpenLong(symbol string, quantity float64, leverage int) (m
 	// Use limit order to simulate market order (price set slightly higher to ensure execution)
 	limitPrice := price * 1.01
 
-	// Format price and quantity to correct precision
-	formattedPrice, err := t.formatPrice(symbol, limitPrice)
-	if err != nil {
-		return nil, err
-	}
-	formattedQty, err := t.formatQuantity(symbol, quantity)
+	// Round price to the tick size and quantity to the lot step of the instrument registry
+	inst, err := t.instrument(symbol)
 	if err != nil {
 		return nil, err
 	}
+	priceStr := inst.FormatPrice(limitPrice)
+	qtyStr := inst.FormatQuantity(quantity)
 
-	// Get precision information
-	prec, err := t.getPrecision(symbol)
-	if err != nil {
-		return nil, err
-	}
-
-	// Convert to string with correct precision format
-	priceStr := t.formatFloatWithPrecision(formattedPrice, prec.PricePrecision)
-	qtyStr := t.formatFloatWithPrecision(formattedQty, prec.QuantityPrecision)
-
-	logger.Infof("  📏 Precision handling: price %.8f -> %s (precision=%d), quantity %.8f -> %s (precision=%d)",
-		limitPrice, priceStr, prec.PricePrecision, quantity, qtyStr, prec.QuantityPrecision)
+	logger.Infof("  📏 Precision handling: price %.8f -> %s, quantity %.8f -> %s", limitPrice, priceStr, quantity, qtyStr)
 
 	params := map[string]interface{}{
 		"symbol":       symbol,
@@ -673,28 +580,15 @@ func (t *AsterTrader) OpenShort(symbol string, quantity float64, leverage int) (
 	// Use limit order to simulate market order (price set slightly lower to ensure execution)
 	limitPrice := price * 0.99
 
-	// Format price and quantity to correct precision
-	formattedPrice, err := t.formatPrice(symbol, limitPrice)
-	if err != nil {
-		return nil, err
-	}
-	formattedQty, err := t.formatQuantity(symbol, quantity)
-	if err != nil {
-		return nil, err
-	}
-
-	// Get precision information
-	prec, err := t.getPrecision(symbol)
+	// Round price to the tick size and quantity to the lot step of the instrument registry
+	inst, err := t.instrument(symbol)
 	if err != nil {
 		return nil, err
 	}
+	priceStr := inst.FormatPrice(limitPrice)
+	qtyStr := inst.FormatQuantity(quantity)
 
-	// Convert to string with correct precision format
-	priceStr := t.formatFloatWithPrecision(formattedPrice, prec.PricePrecision)
-	qtyStr := t.formatFloatWithPrecision(formattedQty, prec.QuantityPrecision)
-
-	logger.Infof("  📏 Precision handling: price %.8f -> %s (precision=%d), quantity %.8f -> %s (precision=%d)",
-		limitPrice, priceStr, prec.PricePrecision, quantity, qtyStr, prec.QuantityPrecision)
+	logger.Infof("  📏 Precision handling: price %.8f -> %s, quantity %.8f -> %s", limitPrice, priceStr, quantity, qtyStr)
 
 	params := map[string]interface{}{
 		"symbol":       symbol,
@@ -748,28 +642,15 @@ func (t *AsterTrader) CloseLong(symbol string, quantity float64) (map[string]int
 
 	limitPrice := price * 0.99
 
-	// Format price and quantity to correct precision
-	formattedPrice, err := t.formatPrice(symbol, limitPrice)
-	if err != nil {
-		return nil, err
-	}
-	formattedQty, err := t.formatQuantity(symbol, quantity)
-	if err != nil {
-		return nil, err
-	}
-
-	// Get precision information
-	prec, err := t.getPrecision(symbol)
+	// Round price to the tick size and quantity to the lot step of the instrument registry
+	inst, err := t.instrument(symbol)
 	if err != nil {
 		return nil, err
 	}
+	priceStr := inst.FormatPrice(limitPrice)
+	qtyStr := inst.FormatQuantity(quantity)
 
-	// Convert to string with correct precision format
-	priceStr := t.formatFloatWithPrecision(formattedPrice, prec.PricePrecision)
-	qtyStr := t.formatFloatWithPrecision(formattedQty, prec.QuantityPrecision)
-
-	logger.Infof("  📏 Precision handling: price %.8f -> %s (precision=%d), quantity %.8f -> %s (precision=%d)",
-		limitPrice, priceStr, prec.PricePrecision, quantity, qtyStr, prec.QuantityPrecision)
+	logger.Infof("  📏 Precision handling: price %.8f -> %s, quantity %.8f -> %s", limitPrice, priceStr, quantity, qtyStr)
 
 	params := map[string]interface{}{
 		"symbol":       symbol,
@@ -831,28 +712,15 @@ func (t *AsterTrader) CloseShort(symbol string, quantity float64) (map[string]in
 
 	limitPrice := price * 1.01
 
-	// Format price and quantity to correct precision
-	formattedPrice, err := t.formatPrice(symbol, limitPrice)
-	if err != nil {
-		return nil, err
-	}
-	formattedQty, err := t.formatQuantity(symbol, quantity)
+	// Round price to the tick size and quantity to the lot step of the instrument registry
+	inst, err := t.instrument(symbol)
 	if err != nil {
 		return nil, err
 	}
+	priceStr := inst.FormatPrice(limitPrice)
+	qtyStr := inst.FormatQuantity(quantity)
 
-	// Get precision information
-	prec, err := t.getPrecision(symbol)
-	if err != nil {
-		return nil, err
-	}
-
-	// Convert to string with correct precision format
-	priceStr := t.formatFloatWithPrecision(formattedPrice, prec.PricePrecision)
-	qtyStr := t.formatFloatWithPrecision(formattedQty, prec.QuantityPrecision)
-
-	logger.Infof("  📏 Precision handling: price %.8f -> %s (precision=%d), quantity %.8f -> %s (precision=%d)",
-		limitPrice, priceStr, prec.PricePrecision, quantity, qtyStr, prec.QuantityPrecision)
+	logger.Infof("  📏 Precision handling: price %.8f -> %s, quantity %.8f -> %s", limitPrice, priceStr, quantity, qtyStr)
 
 	params := map[string]interface{}{
 		"symbol":       symbol,
@@ -976,25 +844,13 @@ func (t *AsterTrader) SetStopLoss(symbol string, positionSide string, quantity,
 		side = "BUY"
 	}
 
-	// Format price and quantity to correct precision
-	formattedPrice, err := t.formatPrice(symbol, stopPrice)
-	if err != nil {
-		return err
-	}
-	formattedQty, err := t.formatQuantity(symbol, quantity)
+	// Round price to the tick size and quantity to the lot step of the instrument registry
+	inst, err := t.instrument(symbol)
 	if err != nil {
 		return err
 	}
-
-	// Get precision information
-	prec, err := t.getPrecision(symbol)
-	if err != nil {
-		return err
-	}
-
-	// Convert to string with correct precision format
-	priceStr := t.formatFloatWithPrecision(formattedPrice, prec.PricePrecision)
-	qtyStr := t.formatFloatWithPrecision(formattedQty, prec.QuantityPrecision)
+	priceStr := inst.FormatPrice(stopPrice)
+	qtyStr := inst.FormatQuantity(quantity)
 
 	params := map[string]interface{}{
 		"symbol":       symbol,
@@ -1017,25 +873,13 @@ func (t *AsterTrader) SetTakeProfit(symbol string, positionSide string, quantity
 		side = "BUY"
 	}
 
-	// Format price and quantity to correct precision
-	formattedPrice, err := t.formatPrice(symbol, takeProfitPrice)
-	if err != nil {
-		return err
-	}
-	formattedQty, err := t.formatQuantity(symbol, quantity)
-	if err != nil {
-		return err
-	}
-
-	// Get precision information
-	prec, err := t.getPrecision(symbol)
+	// Round price to the tick size and quantity to the lot step of the instrument registry
+	inst, err := t.instrument(symbol)
 	if err != nil {
 		return err
 	}
-
-	// Convert to string with correct precision format
-	priceStr := t.formatFloatWithPrecision(formattedPrice, prec.PricePrecision)
-	qtyStr := t.formatFloatWithPrecision(formattedQty, prec.QuantityPrecision)
+	priceStr := inst.FormatPrice(takeProfitPrice)
+	qtyStr := inst.FormatQuantity(quantity)
 
 	params := map[string]interface{}{
 		"symbol":       symbol,
@@ -1110,6 +954,50 @@ func (t *AsterTrader) CancelStopLossOrders(symbol string) error {
 	return nil
 }
 
+// GetStopLossOrders Get open stop-loss orders of a symbol
+func (t *AsterTrader) GetStopLossOrders(symbol string) ([]StopOrder, error) {
+	body, err := t.request("GET", "/fapi/v3/openOrders", map[string]interface{}{"symbol": symbol})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get open orders: %w", err)
+	}
+
+	var orders []map[string]interface{}
+	if err := json.Unmarshal(body, &orders); err != nil {
+		return nil, fmt.Errorf("failed to parse order data: %w", err)
+	}
+
+	var stops []StopOrder
+	for _, order := range orders {
+		orderType, _ := order["type"].(string)
+		if orderType != "STOP_MARKET" && orderType != "STOP" {
+			continue
+		}
+		orderID, _ := order["orderId"].(float64)
+		positionSide, _ := order["positionSide"].(string)
+		side, _ := order["side"].(string)
+		stopPrice, _ := order["stopPrice"].(string)
+		stops = append(stops, StopOrder{
+			OrderID:      strconv.FormatInt(int64(orderID), 10),
+			Symbol:       symbol,
+			PositionSide: stopPositionSide(positionSide, side),
+			StopPrice:    parseFloatOrZero(stopPrice),
+		})
+	}
+	return stops, nil
+}
+
+// CancelOrder Cancel one open order by order ID
+func (t *AsterTrader) CancelOrder(symbol, orderID string) error {
+	id, err := strconv.ParseInt(orderID, 10, 64)
+	if err != nil {
+		return fmt.Errorf("invalid order ID %q: %w", orderID, err)
+	}
+	if _, err := t.request("DELETE", "/fapi/v1/order", map[string]interface{}{"symbol": symbol, "orderId": id}); err != nil {
+		return fmt.Errorf("failed to cancel order %s: %w", orderID, err)
+	}
+	return nil
+}
+
 // CancelTakeProfitOrders Cancel take-profit orders only (does not affect stop-loss orders)
 func (t *AsterTrader) CancelTakeProfitOrders(symbol string) error {
 	// Get all open orders for this symbol
@@ -1236,11 +1124,11 @@ func (t *AsterTrader) CancelStopOrders(symbol string) error {
 
 // FormatQuantity Format quantity (implements Trader interface)
 func (t *AsterTrader) FormatQuantity(symbol string, quantity float64) (string, error) {
-	formatted, err := t.formatQuantity(symbol, quantity)
+	inst, err := t.instrument(symbol)
 	if err != nil {
 		return "", err
 	}
-	return fmt.Sprintf("%v", formatted), nil
+	return inst.FormatQuantity(quantity), nil
 }
 
 // GetOrderStatus Get order status
@@ -96,6 +96,9 @@ func NewAsterTraderTestSuite(t *testing.T) *AsterTraderTestSuite {
 				"symbols": []map[string]interface{}{
 					{
 						"symbol":             "BTCUSDT",
+						"status":             "TRADING",
+						"contractType":       "PERPETUAL",
+						"quoteAsset":         "USDT",
 						"pricePrecision":     1,
 						"quantityPrecision":  3,
 						"baseAssetPrecision": 8,
@@ -113,6 +116,9 @@ func NewAsterTraderTestSuite(t *testing.T) *AsterTraderTestSuite {
 					},
 					{
 						"symbol":             "ETHUSDT",
+						"status":             "TRADING",
+						"contractType":       "PERPETUAL",
+						"quoteAsset":         "USDT",
 						"pricePrecision":     2,
 						"quantityPrecision":  3,
 						"baseAssetPrecision": 8,
@@ -192,13 +198,12 @@ func NewAsterTraderTestSuite(t *testing.T) *AsterTraderTestSuite {
 
 	// Create mock trader using mock server's URL
 	trader := &AsterTrader{
-		ctx:             context.Background(),
-		user:            "0x1234567890123456789012345678901234567890",
-		signer:          "0xabcdefabcdefabcdefabcdefabcdefabcdefabcd",
-		privateKey:      privateKey,
-		client:          mockServer.Client(),
-		baseURL:         mockServer.URL, // Use mock server's URL
-		symbolPrecision: make(map[string]SymbolPrecision),
+		ctx:        context.Background(),
+		user:       "0x1234567890123456789012345678901234567890",
+		signer:     "0xabcdefabcdefabcdefabcdefabcdefabcdefabcd",
+		privateKey: privateKey,
+		client:     mockServer.Client(),
+		baseURL:    mockServer.URL, // Use mock server's URL
 	}
 
 	// Create base suite
@@ -225,6 +230,7 @@ func (s *AsterTraderTestSuite) Cleanup() {
 // TestAsterTrader_InterfaceCompliance tests interface compliance
 func TestAsterTrader_InterfaceCompliance(t *testing.T) {
 	var _ Trader = (*AsterTrader)(nil)
+	var _ StopOrderProvider = (*AsterTrader)(nil)
 }
 
 // TestAsterTrader_CommonInterface runs all common interface tests using test suite
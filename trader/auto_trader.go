@@ -9,8 +9,10 @@ import (
 	"nofx/market"
 	"nofx/mcp"
 	"nofx/store"
+	"strconv"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 )
 
@@ -22,42 +24,11 @@ type AutoTraderConfig struct {
 	AIModel string // AI model: "qwen" or "deepseek"
 
 	// Trading platform selection
-	Exchange   string // Exchange type: "binance", "bybit", "okx", "bitget", "hyperliquid", "aster" or "lighter"
+	Exchange   string // Exchange type: "binance", "bybit", "okx", "bitget", "gate", "hyperliquid", "aster", "lighter" or "dydx"
 	ExchangeID string // Exchange account UUID (for multi-account support)
 
-	// Binance API configuration
-	BinanceAPIKey    string
-	BinanceSecretKey string
-
-	// Bybit API configuration
-	BybitAPIKey    string
-	BybitSecretKey string
-
-	// OKX API configuration
-	OKXAPIKey    string
-	OKXSecretKey string
-	OKXPassphrase string
-
-	// Bitget API configuration
-	BitgetAPIKey    string
-	BitgetSecretKey string
-	BitgetPassphrase string
-
-	// Hyperliquid configuration
-	HyperliquidPrivateKey string
-	HyperliquidWalletAddr string
-	HyperliquidTestnet    bool
-
-	// Aster configuration
-	AsterUser       string // Aster main wallet address
-	AsterSigner     string // Aster API wallet address
-	AsterPrivateKey string // Aster API wallet private key
-
-	// LIGHTER configuration
-	LighterWalletAddr       string // LIGHTER wallet address (L1 wallet)
-	LighterPrivateKey       string // LIGHTER L1 private key (for account identification)
-	LighterAPIKeyPrivateKey string // LIGHTER API Key private key (40 bytes, for transaction signing)
-	LighterTestnet          bool   // Whether to use testnet
+	// Exchange account (credentials and testnet flag)
+	Account *store.Exchange
 
 	// AI configuration
 	UseQwen     bool
@@ -90,6 +61,11 @@ type AutoTraderConfig struct {
 	StrategyConfig *store.StrategyConfig // Strategy configuration (includes coin sources, indicators, risk control, prompts, etc.)
 }
 
+// isTestnet reports whether the configured exchange runs against its testnet/demo environment
+func (c AutoTraderConfig) isTestnet() bool {
+	return c.Account != nil && c.Account.Testnet
+}
+
 // AutoTrader automatic trader
 type AutoTrader struct {
 	id                    string // Trader unique identifier
@@ -111,15 +87,25 @@ type AutoTrader struct {
 	lastResetTime         time.Time
 	stopUntil             time.Time
 	isRunning             bool
-	startTime             time.Time          // System start time
-	callCount             int                // AI call count
-	positionFirstSeenTime map[string]int64   // Position first seen time (symbol_side -> timestamp in milliseconds)
-	stopMonitorCh         chan struct{}      // Used to stop monitoring goroutine
-	monitorWg             sync.WaitGroup     // Used to wait for monitoring goroutine to finish
-	peakPnLCache          map[string]float64 // Peak profit cache (symbol -> peak P&L percentage)
-	peakPnLCacheMutex     sync.RWMutex       // Cache read-write lock
-	lastBalanceSyncTime   time.Time          // Last balance sync time
-	userID                string             // User ID
+	startTime             time.Time             // System start time
+	callCount             int                   // AI call count
+	positionFirstSeenTime map[string]int64      // Position first seen time (symbol_side -> timestamp in milliseconds)
+	stopMonitorCh         chan struct{}         // Used to stop monitoring goroutine
+	monitorWg             sync.WaitGroup        // Used to wait for monitoring goroutine to finish
+	loopDone              chan struct{}         // Closed when the main loop, including a cycle in flight, has exited
+	peakPnLCache          map[string]float64    // Peak profit cache (symbol -> peak P&L percentage)
+	peakPnLCacheMutex     sync.RWMutex          // Cache read-write lock
+	lastBalanceSyncTime   time.Time             // Last balance sync time
+	cycleAILatencyMs      int64                 // AI latency of the current cycle (for execution quality records)
+	triggers              *triggerMonitor       // Event-driven decision triggers (nil = fixed ScanInterval only)
+	triggerCh             chan string           // Pending triggered cycle reason
+	lastCycleTime         time.Time             // Start time of the last decision cycle
+	positionStops         map[string]float64    // Stop loss placed on each open position (symbol_side -> stop price)
+	cycleRegimes          *decision.RegimeState // Regimes of the last cycle (nil = classification disabled)
+	killed                atomic.Bool           // Kill switch engaged, new positions are refused until the next Run
+	followers             []*Follower           // Copy-trading accounts replicating this trader's orders
+	followersMu           sync.RWMutex          // Followers read-write lock
+	userID                string                // User ID
 }
 
 // NewAutoTrader creates an automatic trader
@@ -206,67 +192,26 @@ func NewAutoTrader(config AutoTraderConfig, st *store.Store, userID string) (*Au
 		config.Exchange = "binance"
 	}
 
-	// Create corresponding trader based on configuration
-	var trader Trader
-	var err error
-
 	// Record position mode (general)
 	marginModeStr := "Cross Margin"
 	if !config.IsCrossMargin {
 		marginModeStr = "Isolated Margin"
 	}
 	logger.Infof("📊 [%s] Position mode: %s", config.Name, marginModeStr)
+	if config.isTestnet() {
+		logger.Infof("🧪 [%s] Environment: TESTNET (orders go to the %s testnet/demo environment)", config.Name, config.Exchange)
+	} else {
+		logger.Infof("🌐 [%s] Environment: MAINNET", config.Name)
+	}
 
-	switch config.Exchange {
-	case "binance":
-		logger.Infof("🏦 [%s] Using Binance Futures trading", config.Name)
-		trader = NewFuturesTrader(config.BinanceAPIKey, config.BinanceSecretKey, userID)
-	case "bybit":
-		logger.Infof("🏦 [%s] Using Bybit Futures trading", config.Name)
-		trader = NewBybitTrader(config.BybitAPIKey, config.BybitSecretKey)
-	case "okx":
-		logger.Infof("🏦 [%s] Using OKX Futures trading", config.Name)
-		trader = NewOKXTrader(config.OKXAPIKey, config.OKXSecretKey, config.OKXPassphrase)
-	case "bitget":
-		logger.Infof("🏦 [%s] Using Bitget Futures trading", config.Name)
-		trader = NewBitgetTrader(config.BitgetAPIKey, config.BitgetSecretKey, config.BitgetPassphrase)
-	case "hyperliquid":
-		logger.Infof("🏦 [%s] Using Hyperliquid trading", config.Name)
-		trader, err = NewHyperliquidTrader(config.HyperliquidPrivateKey, config.HyperliquidWalletAddr, config.HyperliquidTestnet)
-		if err != nil {
-			return nil, fmt.Errorf("failed to initialize Hyperliquid trader: %w", err)
-		}
-	case "aster":
-		logger.Infof("🏦 [%s] Using Aster trading", config.Name)
-		trader, err = NewAsterTrader(config.AsterUser, config.AsterSigner, config.AsterPrivateKey)
-		if err != nil {
-			return nil, fmt.Errorf("failed to initialize Aster trader: %w", err)
-		}
-	case "lighter":
-		logger.Infof("🏦 [%s] Using LIGHTER trading", config.Name)
-
-		// Prefer V2 (requires API Key)
-		if config.LighterAPIKeyPrivateKey != "" {
-			logger.Infof("✓ Using LIGHTER SDK (V2) - Full signature support")
-			trader, err = NewLighterTraderV2(
-				config.LighterPrivateKey,
-				config.LighterWalletAddr,
-				config.LighterAPIKeyPrivateKey,
-				config.LighterTestnet,
-			)
-			if err != nil {
-				return nil, fmt.Errorf("failed to initialize LIGHTER trader (V2): %w", err)
-			}
-		} else {
-			// Fallback to V1 (basic HTTP implementation)
-			logger.Infof("⚠️  Using LIGHTER basic implementation (V1) - Limited functionality, please configure API Key")
-			trader, err = NewLighterTrader(config.LighterPrivateKey, config.LighterWalletAddr, config.LighterTestnet)
-			if err != nil {
-				return nil, fmt.Errorf("failed to initialize LIGHTER trader (V1): %w", err)
-			}
-		}
-	default:
-		return nil, fmt.Errorf("unsupported trading platform: %s", config.Exchange)
+	// Create corresponding trader based on configuration
+	if config.Account == nil {
+		return nil, fmt.Errorf("no exchange account configured")
+	}
+	logger.Infof("🏦 [%s] Using %s trading", config.Name, config.Exchange)
+	trader, err := NewTraderFromExchange(config.Account, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize %s trader: %w", config.Exchange, err)
 	}
 
 	// Validate initial balance configuration, auto-fetch from exchange if 0
@@ -315,7 +260,7 @@ func NewAutoTrader(config AutoTraderConfig, st *store.Store, userID string) (*Au
 	strategyEngine := decision.NewStrategyEngine(config.StrategyConfig)
 	logger.Infof("✓ [%s] Using strategy engine (strategy configuration loaded)", config.Name)
 
-	return &AutoTrader{
+	at := &AutoTrader{
 		id:                    config.ID,
 		name:                  config.Name,
 		aiModel:               config.AIModel,
@@ -339,13 +284,23 @@ func NewAutoTrader(config AutoTraderConfig, st *store.Store, userID string) (*Au
 		peakPnLCache:          make(map[string]float64),
 		peakPnLCacheMutex:     sync.RWMutex{},
 		lastBalanceSyncTime:   time.Now(),
+		triggerCh:             make(chan string, 1),
 		userID:                userID,
-	}, nil
+	}
+
+	if triggerConfig := config.StrategyConfig.Triggers; triggerConfig.Enabled {
+		at.triggers = newTriggerMonitor(triggerConfig, at.requestTriggeredCycle)
+	}
+
+	return at, nil
 }
 
 // Run runs the automatic trading main loop
 func (at *AutoTrader) Run() error {
+	at.loopDone = make(chan struct{})
+	defer close(at.loopDone)
 	at.isRunning = true
+	at.killed.Store(false)
 	at.stopMonitorCh = make(chan struct{})
 	at.startTime = time.Now()
 
@@ -353,26 +308,75 @@ func (at *AutoTrader) Run() error {
 	logger.Infof("💰 Initial balance: %.2f USDT", at.initialBalance)
 	logger.Infof("⚙️  Scan interval: %v", at.config.ScanInterval)
 	logger.Info("🤖 AI will make full decisions on leverage, position size, stop loss/take profit, etc.")
-	at.monitorWg.Add(1)
-	defer at.monitorWg.Done()
 
 	// Start drawdown monitoring
 	at.startDrawdownMonitor()
 
-	ticker := time.NewTicker(at.config.ScanInterval)
-	defer ticker.Stop()
+	// Start event-driven decision triggers
+	if at.triggers != nil {
+		unsubscribe := at.startTriggerMonitor()
+		defer unsubscribe()
+	}
+
+	// Scheduled cycles: every ScanInterval, or aligned to primary candle closes
+	schedule := at.candleSchedule()
+	if schedule != nil {
+		logger.Infof("🕯️ [%s] Cycles aligned to %s candle close: every %d bars, +%v offset, +%v jitter",
+			at.name, schedule.Timeframe, schedule.CadenceNBars, schedule.Offset, schedule.Jitter.Round(time.Millisecond))
+	}
+	cycleTimer := newCycleTimer(at.config.ScanInterval, schedule, time.Now())
+	defer cycleTimer.Stop()
 
 	// Execute immediately on first run
 	if err := at.runCycle(); err != nil {
 		logger.Infof("❌ Execution failed: %v", err)
 	}
+	cycleTimer.Reset(at.lastCycleTime)
+	if schedule != nil {
+		logger.Infof("🕯️ [%s] Next cycle at %s", at.name, cycleTimer.Next().Format("15:04:05"))
+	}
+
+	// Triggered cycle deferred until the minimum spacing has elapsed
+	var deferredTimer <-chan time.Time
+	deferredReason := ""
 
 	for at.isRunning {
 		select {
-		case <-ticker.C:
+		case <-cycleTimer.C():
+			// The scheduled cycle sees the same market as a pending triggered one, which is dropped to keep the spacing
+			if deferredTimer != nil {
+				logger.Infof("⏭ [%s] Deferred triggered cycle (%s) covered by the scheduled cycle", at.name, deferredReason)
+				deferredTimer, deferredReason = nil, ""
+			}
 			if err := at.runCycle(); err != nil {
 				logger.Infof("❌ Execution failed: %v", err)
 			}
+			cycleTimer.Reset(at.lastCycleTime)
+		case reason := <-at.triggerCh:
+			if deferredTimer != nil {
+				continue // A triggered cycle is already scheduled
+			}
+			if wait := at.triggers.minInterval() - time.Since(at.lastCycleTime); wait > 0 {
+				logger.Infof("⏳ [%s] Triggered cycle deferred %v (min spacing between cycles)", at.name, wait.Round(time.Second))
+				deferredTimer = time.After(wait)
+				deferredReason = reason
+				continue
+			}
+			if err := at.runCycleWithTrigger(reason); err != nil {
+				logger.Infof("❌ Execution failed: %v", err)
+			}
+			cycleTimer.Reset(at.lastCycleTime)
+		case <-deferredTimer:
+			if wait := at.triggers.minInterval() - time.Since(at.lastCycleTime); wait > 0 {
+				deferredTimer = time.After(wait)
+				continue
+			}
+			reason := deferredReason
+			deferredTimer, deferredReason = nil, ""
+			if err := at.runCycleWithTrigger(reason); err != nil {
+				logger.Infof("❌ Execution failed: %v", err)
+			}
+			cycleTimer.Reset(at.lastCycleTime)
 		case <-at.stopMonitorCh:
 			logger.Infof("[%s] ⏹ Stop signal received, exiting automatic trading main loop", at.name)
 			return nil
@@ -383,28 +387,47 @@ func (at *AutoTrader) Run() error {
 }
 
 // Stop stops the automatic trading
+// Returns once the main loop has exited, so no cycle is still trading afterwards
 func (at *AutoTrader) Stop() {
 	if !at.isRunning {
 		return
 	}
 	at.isRunning = false
-	close(at.stopMonitorCh) // Notify monitoring goroutine to stop
+	close(at.stopMonitorCh) // Notify monitoring goroutine and main loop to stop
+	<-at.loopDone           // Wait for the main loop, including a cycle in flight, to exit
 	at.monitorWg.Wait()     // Wait for monitoring goroutine to finish
+	ReleaseAccountStream(at.id)
 	logger.Info("⏹ Automatic trading system stopped")
 }
 
-// runCycle runs one trading cycle (using AI full decision-making)
+// runCycle runs one scheduled trading cycle (using AI full decision-making)
 func (at *AutoTrader) runCycle() error {
-	at.callCount++
+	return at.runCycleWithTrigger("")
+}
+
+// runCycleWithTrigger runs one trading cycle, triggerReason is the event that started it (empty = scheduled)
+func (at *AutoTrader) runCycleWithTrigger(triggerReason string) error {
+	// A cycle that became due together with the stop signal is skipped
+	select {
+	case <-at.stopMonitorCh:
+		return nil
+	default:
+	}
+
+	at.lastCycleTime = time.Now()
 
 	logger.Info("\n" + strings.Repeat("=", 70) + "\n")
-	logger.Infof("⏰ %s - AI decision cycle #%d", time.Now().Format("2006-01-02 15:04:05"), at.callCount)
+	logger.Infof("⏰ %s - AI decision cycle #%d", time.Now().Format("2006-01-02 15:04:05"), at.cycleNumber+1)
+	if triggerReason != "" {
+		logger.Infof("⚡ Triggered by: %s", triggerReason)
+	}
 	logger.Info(strings.Repeat("=", 70))
 
 	// Create decision record
 	record := &store.DecisionRecord{
-		ExecutionLog: []string{},
-		Success:      true,
+		ExecutionLog:  []string{},
+		Success:       true,
+		TriggerReason: triggerReason,
 	}
 
 	// 1. Check if trading needs to be stopped
@@ -424,6 +447,9 @@ func (at *AutoTrader) runCycle() error {
 		logger.Info("📅 Daily P&L reset")
 	}
 
+	// 3. Refresh venue instrument rules (cached, reloaded hourly)
+	at.refreshInstruments()
+
 	// 4. Collect trading context
 	ctx, err := at.buildTradingContext()
 	if err != nil {
@@ -433,6 +459,18 @@ func (at *AutoTrader) runCycle() error {
 		return fmt.Errorf("failed to build trading context: %w", err)
 	}
 
+	ctx.TriggerReason = triggerReason
+
+	// Trading session restrictions (blackouts may close positions or tighten stops)
+	ctx.Session = at.sessionState()
+	if ctx.Session != nil && !ctx.Session.OpensAllowed {
+		logger.Infof("🚫 [%s] Trading restricted: %s", at.name, ctx.Session.Reason)
+		record.ExecutionLog = append(record.ExecutionLog, "🚫 Trading restricted: "+ctx.Session.Reason)
+	}
+	// Follower accounts drifted from the leader (missed orders, stop-outs, manual trades)
+	at.reconcileFollowers(ctx.Positions, record)
+	at.applyBlackoutAction(ctx.Session, ctx.Positions, record)
+
 	// Save equity snapshot independently (decoupled from AI decision, used for drawing profit curve)
 	at.saveEquitySnapshot(ctx)
 
@@ -444,12 +482,41 @@ func (at *AutoTrader) runCycle() error {
 	logger.Infof("📊 Account equity: %.2f USDT | Available: %.2f USDT | Positions: %d",
 		ctx.Account.TotalEquity, ctx.Account.AvailableBalance, ctx.Account.PositionCount)
 
-	// 5. Use strategy engine to call AI for decision
-	logger.Infof("🤖 Requesting AI analysis and decision... [Strategy Engine]")
+	// 5. Use strategy engine to call AI for decision (rule-based strategies evaluate their rules instead)
+	rulesMode := at.strategyEngine.GetConfig().Mode == store.StrategyModeRules
+	if rulesMode {
+		logger.Infof("📐 Evaluating strategy rules... [Strategy Engine]")
+	} else {
+		logger.Infof("🤖 Requesting AI analysis and decision... [Strategy Engine]")
+	}
 	aiDecision, err := decision.GetFullDecisionWithStrategy(ctx, at.mcpClient, at.strategyEngine, "balanced")
 
+	// Only cycles that reached the model count as AI calls (gated and rule-based cycles don't)
+	if !rulesMode && (aiDecision == nil || !aiDecision.Gated) {
+		at.callCount++
+	}
+
+	// Regimes the decision was made in, enforced on execution and stored to slice performance by regime
+	at.cycleRegimes = ctx.Regimes
+	record.MarketRegime = ctx.Regimes.MarketLabel()
+	record.Regimes = ctx.Regimes.Labels()
+
+	// Triggers measure changes relative to what the AI has just seen
+	if at.triggers != nil {
+		at.triggers.reset(primaryTimeframe(at.strategyEngine.GetConfig()), ctx.MarketDataMap, ctx.Positions)
+	}
+
+	// Pre-AI gate closed: the cycle records a synthetic wait
+	if aiDecision != nil && aiDecision.Gated {
+		record.Gated = true
+		logger.Infof("⏭️ [%s] %s", at.name, aiDecision.CoTTrace)
+		record.ExecutionLog = append(record.ExecutionLog, "⏭️ "+aiDecision.CoTTrace)
+	}
+
+	at.cycleAILatencyMs = 0
 	if aiDecision != nil && aiDecision.AIRequestDurationMs > 0 {
 		record.AIRequestDurationMs = aiDecision.AIRequestDurationMs
+		at.cycleAILatencyMs = aiDecision.AIRequestDurationMs
 		logger.Infof("⏱️ AI call duration: %.2f seconds", float64(record.AIRequestDurationMs)/1000)
 		record.ExecutionLog = append(record.ExecutionLog,
 			fmt.Sprintf("AI call duration: %d ms", record.AIRequestDurationMs))
@@ -540,6 +607,10 @@ func (at *AutoTrader) runCycle() error {
 			Timestamp: time.Now(),
 			Success:   false,
 		}
+		// Mark price the AI saw, used to measure the cost of decision latency
+		if data, ok := ctx.MarketDataMap[d.Symbol]; ok && data != nil {
+			actionRecord.DecisionPrice = data.CurrentPrice
+		}
 
 		if err := at.executeDecisionWithRecord(&d, &actionRecord); err != nil {
 			logger.Infof("❌ Failed to execute decision (%s %s): %v", d.Symbol, d.Action, err)
@@ -693,6 +764,7 @@ func (at *AutoTrader) buildTradingContext() (*decision.Context, error) {
 	if err != nil {
 		return nil, fmt.Errorf("failed to get candidate coins: %w", err)
 	}
+	candidateCoins = at.filterListedCoins(candidateCoins)
 	logger.Infof("📋 [%s] Strategy engine fetched candidate coins: %d", at.name, len(candidateCoins))
 
 	// 4. Calculate total P&L
@@ -717,7 +789,7 @@ func (at *AutoTrader) buildTradingContext() (*decision.Context, error) {
 	ctx := &decision.Context{
 		CurrentTime:     time.Now().UTC().Format("2006-01-02 15:04:05 UTC"),
 		RuntimeMinutes:  int(time.Since(at.startTime).Minutes()),
-		CallCount:       at.callCount,
+		CallCount:       at.callCount + 1, // The AI call this context is built for
 		BTCETHLeverage:  btcEthLeverage,
 		AltcoinLeverage: altcoinLeverage,
 		Account: decision.AccountInfo{
@@ -756,6 +828,12 @@ func (at *AutoTrader) buildTradingContext() (*decision.Context, error) {
 				})
 			}
 		}
+
+		// Journal newly closed trades; the prompt picks the relevant entries once regimes are known
+		at.reflectClosedTrades()
+		if strategyConfig.Journal.Enabled {
+			ctx.Journal = at.loadJournal()
+		}
 	} else {
 		logger.Infof("⚠️ [%s] Store is nil, cannot get recent trades", at.name)
 	}
@@ -796,21 +874,42 @@ func (at *AutoTrader) buildTradingContext() (*decision.Context, error) {
 
 // executeDecisionWithRecord executes AI decision and records detailed information
 func (at *AutoTrader) executeDecisionWithRecord(decision *decision.Decision, actionRecord *store.DecisionAction) error {
+	switch decision.Action {
+	case "open_long", "open_short":
+		if err := at.checkOpenAllowed(); err != nil {
+			return err
+		}
+		if err := at.checkRegime(decision); err != nil {
+			return err
+		}
+		if err := at.checkInstrument(decision); err != nil {
+			return err
+		}
+	}
+
+	var err error
 	switch decision.Action {
 	case "open_long":
-		return at.executeOpenLongWithRecord(decision, actionRecord)
+		err = at.executeOpenLongWithRecord(decision, actionRecord)
 	case "open_short":
-		return at.executeOpenShortWithRecord(decision, actionRecord)
+		err = at.executeOpenShortWithRecord(decision, actionRecord)
 	case "close_long":
-		return at.executeCloseLongWithRecord(decision, actionRecord)
+		err = at.executeCloseLongWithRecord(decision, actionRecord)
 	case "close_short":
-		return at.executeCloseShortWithRecord(decision, actionRecord)
+		err = at.executeCloseShortWithRecord(decision, actionRecord)
 	case "hold", "wait":
 		// No execution needed, just record
 		return nil
 	default:
 		return fmt.Errorf("unknown action: %s", decision.Action)
 	}
+	if err != nil {
+		return err
+	}
+
+	// Replicate the executed order on follower accounts
+	at.replicateToFollowers(decision, actionRecord)
+	return nil
 }
 
 // ExecuteDecision executes a trading decision from external sources (e.g., debate consensus)
@@ -823,6 +922,8 @@ func (at *AutoTrader) ExecuteDecision(d *decision.Decision) error {
 		Symbol:   d.Symbol,
 		Action:   d.Action,
 		Leverage: d.Leverage,
+		// External decisions run outside the cycle, give them their own client order ID scope
+		ClientOrderID: GenerateClientOrderID(at.id, at.cycleNumber+1, d.Symbol, fmt.Sprintf("external_%s_%d", d.Action, time.Now().UnixMilli())),
 	}
 
 	// Execute the decision
@@ -912,7 +1013,10 @@ func (at *AutoTrader) executeOpenLongWithRecord(decision *decision.Decision, act
 	}
 
 	// Calculate quantity with adjusted position size
-	quantity := actualPositionSize / marketData.CurrentPrice
+	quantity, err := at.roundOrderQuantity(decision.Symbol, actualPositionSize/marketData.CurrentPrice, marketData.CurrentPrice)
+	if err != nil {
+		return err
+	}
 	actionRecord.Quantity = quantity
 	actionRecord.Price = marketData.CurrentPrice
 
@@ -922,11 +1026,15 @@ func (at *AutoTrader) executeOpenLongWithRecord(decision *decision.Decision, act
 		// Continue execution, doesn't affect trading
 	}
 
+	at.estimateOrderSlippage(actionRecord, true, quantity*marketData.CurrentPrice)
+
 	// Open position
-	order, err := at.trader.OpenLong(decision.Symbol, quantity, decision.Leverage)
+	submitTime := time.Now()
+	order, err := at.openLong(decision.Symbol, quantity, decision.Leverage, at.clientOrderID(actionRecord))
 	if err != nil {
 		return err
 	}
+	ackTime := time.Now()
 
 	// Record order ID
 	if orderID, ok := order["orderId"].(int64); ok {
@@ -936,7 +1044,10 @@ func (at *AutoTrader) executeOpenLongWithRecord(decision *decision.Decision, act
 	logger.Infof("  ✓ Position opened successfully, order ID: %v, quantity: %.4f", order["orderId"], quantity)
 
 	// Record order to database and poll for confirmation
-	at.recordAndConfirmOrder(order, decision.Symbol, "open_long", quantity, marketData.CurrentPrice, decision.Leverage, 0)
+	fill, filled := at.recordAndConfirmOrder(order, decision.Symbol, "open_long", quantity, marketData.CurrentPrice, decision.Leverage, 0)
+	if filled {
+		at.recordExecution(actionRecord, order, submitTime, ackTime, fill)
+	}
 
 	// Record position opening time
 	posKey := decision.Symbol + "_long"
@@ -945,6 +1056,8 @@ func (at *AutoTrader) executeOpenLongWithRecord(decision *decision.Decision, act
 	// Set stop loss and take profit
 	if err := at.trader.SetStopLoss(decision.Symbol, "LONG", quantity, decision.StopLoss); err != nil {
 		logger.Infof("  ⚠ Failed to set stop loss: %v", err)
+	} else {
+		at.recordStop(posKey, decision.StopLoss)
 	}
 	if err := at.trader.SetTakeProfit(decision.Symbol, "LONG", quantity, decision.TakeProfit); err != nil {
 		logger.Infof("  ⚠ Failed to set take profit: %v", err)
@@ -1029,7 +1142,10 @@ func (at *AutoTrader) executeOpenShortWithRecord(decision *decision.Decision, ac
 	}
 
 	// Calculate quantity with adjusted position size
-	quantity := actualPositionSize / marketData.CurrentPrice
+	quantity, err := at.roundOrderQuantity(decision.Symbol, actualPositionSize/marketData.CurrentPrice, marketData.CurrentPrice)
+	if err != nil {
+		return err
+	}
 	actionRecord.Quantity = quantity
 	actionRecord.Price = marketData.CurrentPrice
 
@@ -1039,11 +1155,15 @@ func (at *AutoTrader) executeOpenShortWithRecord(decision *decision.Decision, ac
 		// Continue execution, doesn't affect trading
 	}
 
+	at.estimateOrderSlippage(actionRecord, false, quantity*marketData.CurrentPrice)
+
 	// Open position
-	order, err := at.trader.OpenShort(decision.Symbol, quantity, decision.Leverage)
+	submitTime := time.Now()
+	order, err := at.openShort(decision.Symbol, quantity, decision.Leverage, at.clientOrderID(actionRecord))
 	if err != nil {
 		return err
 	}
+	ackTime := time.Now()
 
 	// Record order ID
 	if orderID, ok := order["orderId"].(int64); ok {
@@ -1053,7 +1173,10 @@ func (at *AutoTrader) executeOpenShortWithRecord(decision *decision.Decision, ac
 	logger.Infof("  ✓ Position opened successfully, order ID: %v, quantity: %.4f", order["orderId"], quantity)
 
 	// Record order to database and poll for confirmation
-	at.recordAndConfirmOrder(order, decision.Symbol, "open_short", quantity, marketData.CurrentPrice, decision.Leverage, 0)
+	fill, filled := at.recordAndConfirmOrder(order, decision.Symbol, "open_short", quantity, marketData.CurrentPrice, decision.Leverage, 0)
+	if filled {
+		at.recordExecution(actionRecord, order, submitTime, ackTime, fill)
+	}
 
 	// Record position opening time
 	posKey := decision.Symbol + "_short"
@@ -1062,6 +1185,8 @@ func (at *AutoTrader) executeOpenShortWithRecord(decision *decision.Decision, ac
 	// Set stop loss and take profit
 	if err := at.trader.SetStopLoss(decision.Symbol, "SHORT", quantity, decision.StopLoss); err != nil {
 		logger.Infof("  ⚠ Failed to set stop loss: %v", err)
+	} else {
+		at.recordStop(posKey, decision.StopLoss)
 	}
 	if err := at.trader.SetTakeProfit(decision.Symbol, "SHORT", quantity, decision.TakeProfit); err != nil {
 		logger.Infof("  ⚠ Failed to set take profit: %v", err)
@@ -1099,11 +1224,15 @@ func (at *AutoTrader) executeCloseLongWithRecord(decision *decision.Decision, ac
 		}
 	}
 
+	at.estimateOrderSlippage(actionRecord, false, quantity*marketData.CurrentPrice)
+
 	// Close position
-	order, err := at.trader.CloseLong(decision.Symbol, 0) // 0 = close all
+	submitTime := time.Now()
+	order, err := at.closeLong(decision.Symbol, 0, at.clientOrderID(actionRecord)) // 0 = close all
 	if err != nil {
 		return err
 	}
+	ackTime := time.Now()
 
 	// Record order ID
 	if orderID, ok := order["orderId"].(int64); ok {
@@ -1111,7 +1240,10 @@ func (at *AutoTrader) executeCloseLongWithRecord(decision *decision.Decision, ac
 	}
 
 	// Record order to database and poll for confirmation
-	at.recordAndConfirmOrder(order, decision.Symbol, "close_long", quantity, marketData.CurrentPrice, 0, entryPrice)
+	fill, filled := at.recordAndConfirmOrder(order, decision.Symbol, "close_long", quantity, marketData.CurrentPrice, 0, entryPrice)
+	if filled {
+		at.recordExecution(actionRecord, order, submitTime, ackTime, fill)
+	}
 
 	logger.Infof("  ✓ Position closed successfully")
 	return nil
@@ -1146,11 +1278,15 @@ func (at *AutoTrader) executeCloseShortWithRecord(decision *decision.Decision, a
 		}
 	}
 
+	at.estimateOrderSlippage(actionRecord, true, quantity*marketData.CurrentPrice)
+
 	// Close position
-	order, err := at.trader.CloseShort(decision.Symbol, 0) // 0 = close all
+	submitTime := time.Now()
+	order, err := at.closeShort(decision.Symbol, 0, at.clientOrderID(actionRecord)) // 0 = close all
 	if err != nil {
 		return err
 	}
+	ackTime := time.Now()
 
 	// Record order ID
 	if orderID, ok := order["orderId"].(int64); ok {
@@ -1158,7 +1294,10 @@ func (at *AutoTrader) executeCloseShortWithRecord(decision *decision.Decision, a
 	}
 
 	// Record order to database and poll for confirmation
-	at.recordAndConfirmOrder(order, decision.Symbol, "close_short", quantity, marketData.CurrentPrice, 0, entryPrice)
+	fill, filled := at.recordAndConfirmOrder(order, decision.Symbol, "close_short", quantity, marketData.CurrentPrice, 0, entryPrice)
+	if filled {
+		at.recordExecution(actionRecord, order, submitTime, ackTime, fill)
+	}
 
 	logger.Infof("  ✓ Position closed successfully")
 	return nil
@@ -1271,6 +1410,14 @@ func (at *AutoTrader) GetStatus() map[string]interface{} {
 		aiProvider = "Qwen"
 	}
 
+	// Gated cycles are counted from the decision records so the count survives restarts
+	savedAICalls := 0
+	if at.store != nil {
+		if count, err := at.store.Decision().CountGated(at.id); err == nil {
+			savedAICalls = count
+		}
+	}
+
 	return map[string]interface{}{
 		"trader_id":       at.id,
 		"trader_name":     at.name,
@@ -1280,11 +1427,14 @@ func (at *AutoTrader) GetStatus() map[string]interface{} {
 		"start_time":      at.startTime.Format(time.RFC3339),
 		"runtime_minutes": int(time.Since(at.startTime).Minutes()),
 		"call_count":      at.callCount,
+		"saved_ai_calls":  savedAICalls,
 		"initial_balance": at.initialBalance,
 		"scan_interval":   at.config.ScanInterval.String(),
 		"stop_until":      at.stopUntil.Format(time.RFC3339),
 		"last_reset_time": at.lastResetTime.Format(time.RFC3339),
 		"ai_provider":     aiProvider,
+		"testnet":         at.config.isTestnet(),
+		"environment":     environmentName(at.config.isTestnet()),
 	}
 }
 
@@ -1527,6 +1677,10 @@ func (at *AutoTrader) checkPositionDrawdown() {
 			currentPnLPct = ((entryPrice - markPrice) / entryPrice) * float64(leverage) * 100
 		}
 
+		if at.triggers != nil {
+			at.triggers.checkPositionPnL(symbol, side, currentPnLPct)
+		}
+
 		// Construct unique position identifier (distinguish long/short)
 		posKey := symbol + "_" + side
 
@@ -1573,15 +1727,16 @@ func (at *AutoTrader) checkPositionDrawdown() {
 
 // emergencyClosePosition emergency close position function
 func (at *AutoTrader) emergencyClosePosition(symbol, side string) error {
+	clientOrderID := GenerateClientOrderID(at.id, at.cycleNumber+1, symbol, at.cycleAttempt("emergency_close_"+side))
 	switch side {
 	case "long":
-		order, err := at.trader.CloseLong(symbol, 0) // 0 = close all
+		order, err := at.closeLong(symbol, 0, clientOrderID) // 0 = close all
 		if err != nil {
 			return err
 		}
 		logger.Infof("✅ Emergency close long position succeeded, order ID: %v", order["orderId"])
 	case "short":
-		order, err := at.trader.CloseShort(symbol, 0) // 0 = close all
+		order, err := at.closeShort(symbol, 0, clientOrderID) // 0 = close all
 		if err != nil {
 			return err
 		}
@@ -1632,12 +1787,69 @@ func (at *AutoTrader) ClearPeakPnLCache(symbol, side string) {
 	delete(at.peakPnLCache, posKey)
 }
 
+// clientOrderID returns the deterministic client order ID for an action, generating it on first use
+// Orders in the same cycle run for the same symbol and action always get the same ID
+func (at *AutoTrader) clientOrderID(actionRecord *store.DecisionAction) string {
+	if actionRecord.ClientOrderID == "" {
+		actionRecord.ClientOrderID = GenerateClientOrderID(at.id, at.cycleNumber+1, actionRecord.Symbol, at.cycleAttempt(actionRecord.Action))
+	}
+	return actionRecord.ClientOrderID
+}
+
+// cycleAttempt scopes an order action to the current cycle run with the cycle's start time
+// The cycle number alone repeats when the process crashes before the decision is saved, and a rerun of
+// the cycle must not reuse the client order IDs of orders that were already filled
+func (at *AutoTrader) cycleAttempt(action string) string {
+	return fmt.Sprintf("%s@%d", action, at.lastCycleTime.UnixMilli())
+}
+
+// openLong opens long position, tagging the order with client order ID when the exchange supports it
+func (at *AutoTrader) openLong(symbol string, quantity float64, leverage int, clientOrderID string) (map[string]interface{}, error) {
+	if ct, ok := at.trader.(ClientOrderTrader); ok && clientOrderID != "" {
+		return ct.OpenLongWithClientID(symbol, quantity, leverage, clientOrderID)
+	}
+	return at.trader.OpenLong(symbol, quantity, leverage)
+}
+
+// openShort opens short position, tagging the order with client order ID when the exchange supports it
+func (at *AutoTrader) openShort(symbol string, quantity float64, leverage int, clientOrderID string) (map[string]interface{}, error) {
+	if ct, ok := at.trader.(ClientOrderTrader); ok && clientOrderID != "" {
+		return ct.OpenShortWithClientID(symbol, quantity, leverage, clientOrderID)
+	}
+	return at.trader.OpenShort(symbol, quantity, leverage)
+}
+
+// closeLong closes long position, tagging the order with client order ID when the exchange supports it
+func (at *AutoTrader) closeLong(symbol string, quantity float64, clientOrderID string) (map[string]interface{}, error) {
+	if ct, ok := at.trader.(ClientOrderTrader); ok && clientOrderID != "" {
+		return ct.CloseLongWithClientID(symbol, quantity, clientOrderID)
+	}
+	return at.trader.CloseLong(symbol, quantity)
+}
+
+// closeShort closes short position, tagging the order with client order ID when the exchange supports it
+func (at *AutoTrader) closeShort(symbol string, quantity float64, clientOrderID string) (map[string]interface{}, error) {
+	if ct, ok := at.trader.(ClientOrderTrader); ok && clientOrderID != "" {
+		return ct.CloseShortWithClientID(symbol, quantity, clientOrderID)
+	}
+	return at.trader.CloseShort(symbol, quantity)
+}
+
+// orderFill confirmed fill of an order
+type orderFill struct {
+	price float64   // Average fill price (0 = the exchange didn't report one)
+	qty   float64   // Filled quantity
+	fee   float64   // Fee paid
+	time  time.Time // When the order was filled (zero = the exchange didn't report it)
+}
+
 // recordAndConfirmOrder polls order status for actual fill data and records position
 // action: open_long, open_short, close_long, close_short
 // entryPrice: entry price when closing (0 when opening)
-func (at *AutoTrader) recordAndConfirmOrder(orderResult map[string]interface{}, symbol, action string, quantity float64, price float64, leverage int, entryPrice float64) {
+// Returns the confirmed fill price and quantity (filled=false when the fill couldn't be confirmed)
+func (at *AutoTrader) recordAndConfirmOrder(orderResult map[string]interface{}, symbol, action string, quantity float64, price float64, leverage int, entryPrice float64) (fill orderFill, filled bool) {
 	if at.store == nil {
-		return
+		return orderFill{}, false
 	}
 
 	// Get order ID (supports multiple types)
@@ -1653,9 +1865,26 @@ func (at *AutoTrader) recordAndConfirmOrder(orderResult map[string]interface{},
 		orderID = fmt.Sprintf("%v", v)
 	}
 
-	if orderID == "" || orderID == "0" {
+	clientOrderID, _ := orderResult["clientOrderId"].(string)
+	if orderID == "0" || orderID == "<nil>" {
+		orderID = ""
+	}
+	if orderID == "" && clientOrderID == "" {
 		logger.Infof("  ⚠️ Order ID is empty, skipping record")
-		return
+		return orderFill{}, false
+	}
+
+	// Exchange order ID is the record key; client order ID only when the exchange didn't return one
+	recordID := orderID
+	if recordID == "" {
+		recordID = clientOrderID
+	}
+
+	// Dedupe: the same order may come back from a retried submission (an ambiguous submission
+	// that was looked up returns the exchange order ID of the order that went through)
+	if exists, err := at.store.Position().ExistsWithOrderID(at.id, recordID); err == nil && exists {
+		logger.Infof("  ⚠️ Order %s already recorded, skipping duplicate record", recordID)
+		return orderFill{}, false
 	}
 
 	// Determine positionSide
@@ -1672,40 +1901,157 @@ func (at *AutoTrader) recordAndConfirmOrder(orderResult map[string]interface{},
 	var actualQty = quantity      // fallback to requested quantity
 	var fee float64
 
-	// Wait for order to be filled and get actual fill data
-	time.Sleep(500 * time.Millisecond)
-	for i := 0; i < 5; i++ {
-		status, err := at.trader.GetOrderStatus(symbol, orderID)
-		if err == nil {
-			statusStr, _ := status["status"].(string)
-			if statusStr == "FILLED" {
-				// Get actual fill price
-				if avgPrice, ok := status["avgPrice"].(float64); ok && avgPrice > 0 {
-					actualPrice = avgPrice
-				}
-				// Get actual executed quantity
-				if execQty, ok := status["executedQty"].(float64); ok && execQty > 0 {
-					actualQty = execQty
+	// Prefer fills pushed by the account stream, fall back to polling order status
+	if streamed, ok := fillHub.waitForFill(at.id, orderID, clientOrderID, 3*time.Second); ok {
+		fill = streamed
+		actualPrice, actualQty, fee = streamed.price, streamed.qty, streamed.fee
+		filled = true
+		logger.Infof("  ✅ Order filled (account stream): avgPrice=%.6f, qty=%.6f, fee=%.6f", actualPrice, actualQty, fee)
+	} else {
+		// Wait for order to be filled and get actual fill data
+		time.Sleep(500 * time.Millisecond)
+		for i := 0; i < 5; i++ {
+			var status map[string]interface{}
+			var err error
+			if orderID == "" {
+				ct, ok := at.trader.(ClientOrderTrader)
+				if !ok {
+					break
 				}
-				// Get commission/fee
-				if commission, ok := status["commission"].(float64); ok {
-					fee = commission
+				status, err = ct.GetOrderByClientID(symbol, clientOrderID)
+			} else {
+				status, err = at.trader.GetOrderStatus(symbol, orderID)
+			}
+			if err == nil {
+				statusStr, _ := status["status"].(string)
+				if statusStr == "FILLED" {
+					// Get actual fill price
+					if avgPrice, ok := status["avgPrice"].(float64); ok && avgPrice > 0 {
+						actualPrice = avgPrice
+						fill.price = avgPrice
+					}
+					// Get actual executed quantity
+					if execQty, ok := status["executedQty"].(float64); ok && execQty > 0 {
+						actualQty = execQty
+					}
+					// Get commission/fee
+					if commission, ok := status["commission"].(float64); ok {
+						fee = commission
+					}
+					fill.time = orderUpdateTime(status)
+					logger.Infof("  ✅ Order filled: avgPrice=%.6f, qty=%.6f, fee=%.6f", actualPrice, actualQty, fee)
+					filled = true
+					break
+				} else if statusStr == "CANCELED" || statusStr == "EXPIRED" || statusStr == "REJECTED" {
+					logger.Infof("  ⚠️ Order %s, skipping position record", statusStr)
+					return orderFill{}, false
 				}
-				logger.Infof("  ✅ Order filled: avgPrice=%.6f, qty=%.6f, fee=%.6f", actualPrice, actualQty, fee)
-				break
-			} else if statusStr == "CANCELED" || statusStr == "EXPIRED" || statusStr == "REJECTED" {
-				logger.Infof("  ⚠️ Order %s, skipping position record", statusStr)
-				return
 			}
+			time.Sleep(500 * time.Millisecond)
 		}
-		time.Sleep(500 * time.Millisecond)
 	}
 
 	logger.Infof("  📝 Recording position (ID: %s, action: %s, price: %.6f, qty: %.6f, fee: %.4f)",
-		orderID, action, actualPrice, actualQty, fee)
+		recordID, action, actualPrice, actualQty, fee)
 
 	// Record position change with actual fill data
-	at.recordPositionChange(orderID, symbol, positionSide, action, actualQty, actualPrice, leverage, entryPrice, fee)
+	at.recordPositionChange(recordID, symbol, positionSide, action, actualQty, actualPrice, leverage, entryPrice, fee)
+	fill.qty, fill.fee = actualQty, fee
+	return fill, filled
+}
+
+// orderUpdateTime exchange time of the last order update in an order status (zero = not reported)
+func orderUpdateTime(status map[string]interface{}) time.Time {
+	var ms int64
+	switch v := status["updateTime"].(type) {
+	case int64:
+		ms = v
+	case float64:
+		ms = int64(v)
+	case string:
+		ms, _ = strconv.ParseInt(v, 10, 64)
+	}
+	if ms <= 0 {
+		return time.Time{}
+	}
+	return time.UnixMilli(ms)
+}
+
+// estimateOrderSlippage estimates market order slippage from the order book before submission
+// (only when the strategy enables order book data, and only on Binance: the order book is Binance depth,
+// which says nothing about another venue's liquidity)
+func (at *AutoTrader) estimateOrderSlippage(actionRecord *store.DecisionAction, buy bool, notional float64) {
+	if at.config.StrategyConfig == nil || !at.config.StrategyConfig.Indicators.EnableOrderBook || notional <= 0 {
+		return
+	}
+	if at.exchange != "binance" {
+		return
+	}
+
+	book, err := market.GetOrderBook(actionRecord.Symbol)
+	if err != nil {
+		logger.Infof("  ⚠️ Failed to fetch order book for slippage estimate: %v", err)
+		return
+	}
+	bps, complete := market.EstimateSlippageBps(book, buy, notional)
+	actionRecord.EstimatedSlippageBps = &bps
+	if !complete {
+		logger.Infof("  ⚠️ %s order of %.2f USDT exceeds visible order book depth, estimated slippage %.2f bps is a lower bound",
+			actionRecord.Symbol, notional, bps)
+		return
+	}
+	logger.Infof("  📚 Estimated slippage for %.2f USDT: %.2f bps", notional, bps)
+}
+
+// recordExecution records execution quality (decision/submit/fill prices and latencies) of a filled order
+// Orders without a reported fill price are skipped, a fallback price would record fake ~0 bps slippage
+func (at *AutoTrader) recordExecution(actionRecord *store.DecisionAction, orderResult map[string]interface{}, submitTime, ackTime time.Time, fill orderFill) {
+	if fill.price <= 0 {
+		logger.Infof("  ⚠️ Exchange reported no fill price for %s %s, skipping execution quality sample", actionRecord.Symbol, actionRecord.Action)
+		return
+	}
+	actionRecord.FillPrice = fill.price
+	if actionRecord.DecisionPrice > 0 {
+		actionRecord.SlippageBps = store.AdverseBps(actionRecord.Action, actionRecord.DecisionPrice, fill.price)
+	}
+
+	if at.store == nil {
+		return
+	}
+
+	execution := &store.OrderExecution{
+		TraderID:        at.id,
+		ExchangeType:    at.exchange,
+		Symbol:          actionRecord.Symbol,
+		Action:          actionRecord.Action,
+		OrderID:         fmt.Sprintf("%v", orderResult["orderId"]),
+		Quantity:        fill.qty,
+		DecisionPrice:   actionRecord.DecisionPrice,
+		SubmitPrice:     actionRecord.Price,
+		FillPrice:       fill.price,
+		SubmitLatencyMs: ackTime.Sub(submitTime).Milliseconds(),
+		SubmitTime:      submitTime,
+	}
+	if actionRecord.EstimatedSlippageBps != nil {
+		execution.EstimatedBps = *actionRecord.EstimatedSlippageBps
+		execution.HasEstimate = true
+	}
+	// Fill latency comes from the exchange fill time, polling cadence would distort it
+	// (a fill time before submission means local clock skew, left unknown)
+	if !fill.time.IsZero() && fill.time.After(submitTime) {
+		execution.FillLatencyMs = fill.time.Sub(submitTime).Milliseconds()
+	}
+	// External decisions (no decision price) didn't go through this cycle's AI call
+	if actionRecord.DecisionPrice > 0 {
+		execution.AILatencyMs = at.cycleAILatencyMs
+	}
+
+	if err := at.store.Execution().Save(execution); err != nil {
+		logger.Infof("  ⚠️ Failed to save execution record: %v", err)
+		return
+	}
+	logger.Infof("  📐 Execution: decision=%.6f submit=%.6f fill=%.6f, slippage %.2f bps (total %.2f bps), fill latency %dms",
+		execution.DecisionPrice, execution.SubmitPrice, execution.FillPrice, execution.SlippageBps, execution.TotalBps, execution.FillLatencyMs)
 }
 
 // recordPositionChange records position change (create record on open, update record on close)
@@ -0,0 +1,247 @@
+package trader
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/adshao/go-binance/v2/futures"
+	"github.com/gorilla/websocket"
+)
+
+// binanceListenKeyKeepalive listenKey expires after 60 minutes without keepalive
+const binanceListenKeyKeepalive = 30 * time.Minute
+
+// StreamAccount streams Binance Futures user data (listenKey) until ctx is cancelled or the stream drops
+func (t *FuturesTrader) StreamAccount(ctx context.Context, handler func(AccountEvent)) error {
+	listenKey, err := t.client.NewStartUserStreamService().Do(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to create listenKey: %w", err)
+	}
+	defer t.client.NewCloseUserStreamService().ListenKey(listenKey).Do(context.Background())
+
+	var lastErr error
+	var errMutex sync.Mutex
+	errHandler := func(err error) {
+		errMutex.Lock()
+		lastErr = err
+		errMutex.Unlock()
+	}
+
+	serve := futures.WsUserDataServe
+	if t.testnet {
+		serve = binanceTestnetUserDataServe
+	}
+
+	expired := make(chan struct{}, 1)
+	doneC, stopC, err := serve(listenKey, func(event *futures.WsUserDataEvent) {
+		if event.Event == futures.UserDataEventTypeListenKeyExpired {
+			select {
+			case expired <- struct{}{}:
+			default:
+			}
+			return
+		}
+		for _, ev := range binanceAccountEvents(event) {
+			handler(ev)
+		}
+	}, errHandler)
+	if err != nil {
+		return fmt.Errorf("failed to connect user data stream: %w", err)
+	}
+
+	handler(AccountEvent{Type: AccountEventConnected, Time: time.Now()})
+
+	keepalive := time.NewTicker(binanceListenKeyKeepalive)
+	defer keepalive.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			close(stopC)
+			<-doneC
+			return nil
+		case <-doneC:
+			errMutex.Lock()
+			defer errMutex.Unlock()
+			if lastErr != nil {
+				return fmt.Errorf("user data stream closed: %w", lastErr)
+			}
+			return fmt.Errorf("user data stream closed")
+		case <-expired:
+			close(stopC)
+			<-doneC
+			return fmt.Errorf("listenKey expired")
+		case <-keepalive.C:
+			if err := t.client.NewKeepaliveUserStreamService().ListenKey(listenKey).Do(ctx); err != nil {
+				close(stopC)
+				<-doneC
+				return fmt.Errorf("failed to keep listenKey alive: %w", err)
+			}
+		}
+	}
+}
+
+// binanceTestnetUserDataServe is futures.WsUserDataServe against the testnet stream host
+// The library only switches websocket hosts through the process-wide futures.UseTestnet flag,
+// which would move mainnet traders running in the same process to testnet as well
+func binanceTestnetUserDataServe(listenKey string, handler futures.WsUserDataHandler, errHandler futures.ErrHandler) (doneC, stopC chan struct{}, err error) {
+	dialer := websocket.Dialer{HandshakeTimeout: 45 * time.Second}
+	conn, _, err := dialer.Dial(futures.BaseWsTestnetUrl+"/"+listenKey, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	doneC = make(chan struct{})
+	stopC = make(chan struct{})
+	go func() {
+		defer close(doneC)
+		stopped := make(chan struct{})
+		go func() {
+			select {
+			case <-stopC:
+				close(stopped)
+			case <-doneC:
+			}
+			conn.Close() // Unblock ReadMessage
+		}()
+		for {
+			_, message, err := conn.ReadMessage()
+			if err != nil {
+				select {
+				case <-stopped:
+				default:
+					errHandler(err)
+				}
+				return
+			}
+			event := new(futures.WsUserDataEvent)
+			if err := json.Unmarshal(message, event); err != nil {
+				errHandler(err)
+				continue
+			}
+			handler(event)
+		}
+	}()
+	return doneC, stopC, nil
+}
+
+// binanceAccountEvents converts a Binance user data event to unified account events
+func binanceAccountEvents(event *futures.WsUserDataEvent) []AccountEvent {
+	eventTime := time.UnixMilli(event.Time)
+	var events []AccountEvent
+
+	switch event.Event {
+	case futures.UserDataEventTypeOrderTradeUpdate:
+		o := event.OrderTradeUpdate
+		if o.ExecutionType != futures.OrderExecutionTypeTrade {
+			return nil
+		}
+
+		price, _ := strconv.ParseFloat(o.LastFilledPrice, 64)
+		qty, _ := strconv.ParseFloat(o.LastFilledQty, 64)
+		fee, _ := strconv.ParseFloat(o.Commission, 64)
+		pnl, _ := strconv.ParseFloat(o.RealizedPnL, 64)
+
+		side, reduceOnly := binanceFillPositionSide(o)
+		events = append(events, AccountEvent{
+			Type:          AccountEventOrderFill,
+			Time:          time.UnixMilli(o.TradeTime),
+			Symbol:        o.Symbol,
+			Side:          side,
+			OrderID:       strconv.FormatInt(o.ID, 10),
+			ClientOrderID: o.ClientOrderID,
+			Price:         price,
+			Quantity:      qty,
+			OrderFilled:   o.Status == futures.OrderStatusTypeFilled,
+			ReduceOnly:    reduceOnly,
+			RealizedPnL:   pnl,
+			Fee:           fee,
+			CloseReason:   binanceCloseReason(o),
+		})
+
+	case futures.UserDataEventTypeAccountUpdate:
+		for _, p := range event.AccountUpdate.Positions {
+			amt, _ := strconv.ParseFloat(p.Amount, 64)
+			entryPrice, _ := strconv.ParseFloat(p.EntryPrice, 64)
+
+			side := string(p.Side)
+			if p.Side == futures.PositionSideTypeBoth {
+				// One-way mode: direction from sign, empty when flat (matches either side)
+				switch {
+				case amt > 0:
+					side = "LONG"
+				case amt < 0:
+					side = "SHORT"
+				default:
+					side = ""
+				}
+			}
+			if amt < 0 {
+				amt = -amt
+			}
+
+			events = append(events, AccountEvent{
+				Type:        AccountEventPosition,
+				Time:        eventTime,
+				Symbol:      p.Symbol,
+				Side:        side,
+				PositionAmt: amt,
+				EntryPrice:  entryPrice,
+			})
+		}
+		for _, b := range event.AccountUpdate.Balances {
+			balance, _ := strconv.ParseFloat(b.Balance, 64)
+			events = append(events, AccountEvent{
+				Type:    AccountEventBalance,
+				Time:    eventTime,
+				Asset:   b.Asset,
+				Balance: balance,
+			})
+		}
+	}
+
+	return events
+}
+
+// binanceFillPositionSide determines the position side a fill belongs to and whether it reduces it
+func binanceFillPositionSide(o futures.WsOrderTradeUpdate) (string, bool) {
+	switch o.PositionSide {
+	case futures.PositionSideTypeLong:
+		return "LONG", o.Side == futures.SideTypeSell
+	case futures.PositionSideTypeShort:
+		return "SHORT", o.Side == futures.SideTypeBuy
+	}
+
+	// One-way mode
+	pnl, _ := strconv.ParseFloat(o.RealizedPnL, 64)
+	closing := o.IsReduceOnly || o.IsClosingPosition || pnl != 0
+	if o.Side == futures.SideTypeBuy {
+		if closing {
+			return "SHORT", true
+		}
+		return "LONG", false
+	}
+	if closing {
+		return "LONG", true
+	}
+	return "SHORT", false
+}
+
+// binanceCloseReason maps exchange-triggered orders to close reasons
+func binanceCloseReason(o futures.WsOrderTradeUpdate) string {
+	if string(o.Type) == "LIQUIDATION" || strings.HasPrefix(o.ClientOrderID, "autoclose-") || strings.HasPrefix(o.ClientOrderID, "adl_autoclose") {
+		return CloseReasonLiquidation
+	}
+	switch o.OriginalType {
+	case futures.OrderTypeStopMarket, futures.OrderTypeStop:
+		return CloseReasonStopLoss
+	case futures.OrderTypeTakeProfitMarket, futures.OrderTypeTakeProfit:
+		return CloseReasonTakeProfit
+	}
+	return ""
+}
@@ -45,7 +45,8 @@ func getBrOrderID() string {
 
 // FuturesTrader Binance futures trader
 type FuturesTrader struct {
-	client *futures.Client
+	client  *futures.Client
+	testnet bool
 
 	// Balance cache
 	cachedBalance     map[string]interface{}
@@ -62,20 +63,27 @@ type FuturesTrader struct {
 }
 
 // NewFuturesTrader creates futures trader
-func NewFuturesTrader(apiKey, secretKey string, userId string) *FuturesTrader {
+// testnet switches REST and user data stream to the Binance futures testnet
+func NewFuturesTrader(apiKey, secretKey string, userId string, testnet bool) *FuturesTrader {
 	client := futures.NewClient(apiKey, secretKey)
 
 	hookRes := hook.HookExec[hook.NewBinanceTraderResult](hook.NEW_BINANCE_TRADER, userId, client)
 	if hookRes != nil && hookRes.GetResult() != nil {
 		client = hookRes.GetResult()
 	}
+	// Set after the hook so a replaced client can't silently point a testnet trader at mainnet
+	if testnet {
+		client.BaseURL = futures.BaseApiTestnetUrl
+	}
 
 	// Sync time to avoid "Timestamp ahead" error
 	syncBinanceServerTime(client)
 	trader := &FuturesTrader{
 		client:        client,
+		testnet:       testnet,
 		cacheDuration: 15 * time.Second, // 15-second cache
 	}
+	logger.Infof("🟡 [Binance] Trader initialized (%s)", environmentName(testnet))
 
 	// Set dual-side position mode (Hedge Mode)
 	// This is required because the code uses PositionSide (LONG/SHORT)
@@ -146,6 +154,7 @@ func (t *FuturesTrader) GetBalance() (map[string]interface{}, error) {
 	result["totalWalletBalance"], _ = strconv.ParseFloat(account.TotalWalletBalance, 64)
 	result["availableBalance"], _ = strconv.ParseFloat(account.AvailableBalance, 64)
 	result["totalUnrealizedProfit"], _ = strconv.ParseFloat(account.TotalUnrealizedProfit, 64)
+	result["total_equity"] = result["totalWalletBalance"].(float64) + result["totalUnrealizedProfit"].(float64)
 
 	logger.Infof("✓ Binance API returned: total balance=%s, available=%s, unrealized PnL=%s",
 		account.TotalWalletBalance,
@@ -315,6 +324,11 @@ func (t *FuturesTrader) SetLeverage(symbol string, leverage int) error {
 
 // OpenLong opens a long position
 func (t *FuturesTrader) OpenLong(symbol string, quantity float64, leverage int) (map[string]interface{}, error) {
+	return t.OpenLongWithClientID(symbol, quantity, leverage, "")
+}
+
+// OpenLongWithClientID opens a long position tagged with client order ID
+func (t *FuturesTrader) OpenLongWithClientID(symbol string, quantity float64, leverage int, clientOrderID string) (map[string]interface{}, error) {
 	// First cancel all pending orders for this symbol (clean up old stop-loss and take-profit orders)
 	if err := t.CancelAllOrders(symbol); err != nil {
 		logger.Infof("  ⚠ Failed to cancel old pending orders (may not have any): %v", err)
@@ -345,31 +359,24 @@ func (t *FuturesTrader) OpenLong(symbol string, quantity float64, leverage int)
 	}
 
 	// Create market buy order (using br ID)
-	order, err := t.client.NewCreateOrderService().
-		Symbol(symbol).
-		Side(futures.SideTypeBuy).
-		PositionSide(futures.PositionSideTypeLong).
-		Type(futures.OrderTypeMarket).
-		Quantity(quantityStr).
-		NewClientOrderID(getBrOrderID()).
-		Do(context.Background())
-
+	result, err := t.placeMarketOrder(symbol, futures.SideTypeBuy, futures.PositionSideTypeLong, quantityStr, clientOrderID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to open long position: %w", err)
 	}
 
 	logger.Infof("✓ Opened long position successfully: %s quantity: %s", symbol, quantityStr)
-	logger.Infof("  Order ID: %d", order.OrderID)
+	logger.Infof("  Order ID: %v", result["orderId"])
 
-	result := make(map[string]interface{})
-	result["orderId"] = order.OrderID
-	result["symbol"] = order.Symbol
-	result["status"] = order.Status
 	return result, nil
 }
 
 // OpenShort opens a short position
 func (t *FuturesTrader) OpenShort(symbol string, quantity float64, leverage int) (map[string]interface{}, error) {
+	return t.OpenShortWithClientID(symbol, quantity, leverage, "")
+}
+
+// OpenShortWithClientID opens a short position tagged with client order ID
+func (t *FuturesTrader) OpenShortWithClientID(symbol string, quantity float64, leverage int, clientOrderID string) (map[string]interface{}, error) {
 	// First cancel all pending orders for this symbol (clean up old stop-loss and take-profit orders)
 	if err := t.CancelAllOrders(symbol); err != nil {
 		logger.Infof("  ⚠ Failed to cancel old pending orders (may not have any): %v", err)
@@ -400,31 +407,24 @@ func (t *FuturesTrader) OpenShort(symbol string, quantity float64, leverage int)
 	}
 
 	// Create market sell order (using br ID)
-	order, err := t.client.NewCreateOrderService().
-		Symbol(symbol).
-		Side(futures.SideTypeSell).
-		PositionSide(futures.PositionSideTypeShort).
-		Type(futures.OrderTypeMarket).
-		Quantity(quantityStr).
-		NewClientOrderID(getBrOrderID()).
-		Do(context.Background())
-
+	result, err := t.placeMarketOrder(symbol, futures.SideTypeSell, futures.PositionSideTypeShort, quantityStr, clientOrderID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to open short position: %w", err)
 	}
 
 	logger.Infof("✓ Opened short position successfully: %s quantity: %s", symbol, quantityStr)
-	logger.Infof("  Order ID: %d", order.OrderID)
+	logger.Infof("  Order ID: %v", result["orderId"])
 
-	result := make(map[string]interface{})
-	result["orderId"] = order.OrderID
-	result["symbol"] = order.Symbol
-	result["status"] = order.Status
 	return result, nil
 }
 
 // CloseLong closes a long position
 func (t *FuturesTrader) CloseLong(symbol string, quantity float64) (map[string]interface{}, error) {
+	return t.CloseLongWithClientID(symbol, quantity, "")
+}
+
+// CloseLongWithClientID closes a long position tagged with client order ID
+func (t *FuturesTrader) CloseLongWithClientID(symbol string, quantity float64, clientOrderID string) (map[string]interface{}, error) {
 	// If quantity is 0, get current position quantity
 	if quantity == 0 {
 		positions, err := t.GetPositions()
@@ -451,15 +451,7 @@ func (t *FuturesTrader) CloseLong(symbol string, quantity float64) (map[string]i
 	}
 
 	// Create market sell order (close long, using br ID)
-	order, err := t.client.NewCreateOrderService().
-		Symbol(symbol).
-		Side(futures.SideTypeSell).
-		PositionSide(futures.PositionSideTypeLong).
-		Type(futures.OrderTypeMarket).
-		Quantity(quantityStr).
-		NewClientOrderID(getBrOrderID()).
-		Do(context.Background())
-
+	result, err := t.placeMarketOrder(symbol, futures.SideTypeSell, futures.PositionSideTypeLong, quantityStr, clientOrderID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to close long position: %w", err)
 	}
@@ -471,15 +463,16 @@ func (t *FuturesTrader) CloseLong(symbol string, quantity float64) (map[string]i
 		logger.Infof("  ⚠ Failed to cancel pending orders: %v", err)
 	}
 
-	result := make(map[string]interface{})
-	result["orderId"] = order.OrderID
-	result["symbol"] = order.Symbol
-	result["status"] = order.Status
 	return result, nil
 }
 
 // CloseShort closes a short position
 func (t *FuturesTrader) CloseShort(symbol string, quantity float64) (map[string]interface{}, error) {
+	return t.CloseShortWithClientID(symbol, quantity, "")
+}
+
+// CloseShortWithClientID closes a short position tagged with client order ID
+func (t *FuturesTrader) CloseShortWithClientID(symbol string, quantity float64, clientOrderID string) (map[string]interface{}, error) {
 	// If quantity is 0, get current position quantity
 	if quantity == 0 {
 		positions, err := t.GetPositions()
@@ -506,15 +499,7 @@ func (t *FuturesTrader) CloseShort(symbol string, quantity float64) (map[string]
 	}
 
 	// Create market buy order (close short, using br ID)
-	order, err := t.client.NewCreateOrderService().
-		Symbol(symbol).
-		Side(futures.SideTypeBuy).
-		PositionSide(futures.PositionSideTypeShort).
-		Type(futures.OrderTypeMarket).
-		Quantity(quantityStr).
-		NewClientOrderID(getBrOrderID()).
-		Do(context.Background())
-
+	result, err := t.placeMarketOrder(symbol, futures.SideTypeBuy, futures.PositionSideTypeShort, quantityStr, clientOrderID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to close short position: %w", err)
 	}
@@ -526,10 +511,6 @@ func (t *FuturesTrader) CloseShort(symbol string, quantity float64) (map[string]
 		logger.Infof("  ⚠ Failed to cancel pending orders: %v", err)
 	}
 
-	result := make(map[string]interface{})
-	result["orderId"] = order.OrderID
-	result["symbol"] = order.Symbol
-	result["status"] = order.Status
 	return result, nil
 }
 
@@ -583,6 +564,47 @@ func (t *FuturesTrader) CancelStopLossOrders(symbol string) error {
 	return nil
 }
 
+// GetStopLossOrders gets the open stop-loss orders of a symbol
+func (t *FuturesTrader) GetStopLossOrders(symbol string) ([]StopOrder, error) {
+	orders, err := t.client.NewListOpenOrdersService().
+		Symbol(symbol).
+		Do(context.Background())
+	if err != nil {
+		return nil, fmt.Errorf("failed to get open orders: %w", err)
+	}
+
+	var stops []StopOrder
+	for _, order := range orders {
+		if order.Type != futures.OrderTypeStopMarket && order.Type != futures.OrderTypeStop {
+			continue
+		}
+		stopPrice, _ := strconv.ParseFloat(order.StopPrice, 64)
+		stops = append(stops, StopOrder{
+			OrderID:      strconv.FormatInt(order.OrderID, 10),
+			Symbol:       order.Symbol,
+			PositionSide: stopPositionSide(string(order.PositionSide), string(order.Side)),
+			StopPrice:    stopPrice,
+		})
+	}
+	return stops, nil
+}
+
+// CancelOrder cancels one open order by order ID
+func (t *FuturesTrader) CancelOrder(symbol, orderID string) error {
+	id, err := strconv.ParseInt(orderID, 10, 64)
+	if err != nil {
+		return fmt.Errorf("invalid order ID %q: %w", orderID, err)
+	}
+	_, err = t.client.NewCancelOrderService().
+		Symbol(symbol).
+		OrderID(id).
+		Do(context.Background())
+	if err != nil {
+		return fmt.Errorf("failed to cancel order %s: %w", orderID, err)
+	}
+	return nil
+}
+
 // CancelTakeProfitOrders cancels only take-profit orders (doesn't affect stop-loss orders)
 func (t *FuturesTrader) CancelTakeProfitOrders(symbol string) error {
 	// Get all open orders for this symbol
@@ -825,84 +847,54 @@ func (t *FuturesTrader) CheckMinNotional(symbol string, quantity float64) error
 	return nil
 }
 
-// GetSymbolPrecision gets the quantity precision for a trading pair
-func (t *FuturesTrader) GetSymbolPrecision(symbol string) (int, error) {
+// GetInstruments gets trading rules of all USDT perpetuals (implements InstrumentProvider)
+func (t *FuturesTrader) GetInstruments() ([]Instrument, error) {
 	exchangeInfo, err := t.client.NewExchangeInfoService().Do(context.Background())
 	if err != nil {
-		return 0, fmt.Errorf("failed to get trading rules: %w", err)
-	}
-
-	for _, s := range exchangeInfo.Symbols {
-		if s.Symbol == symbol {
-			// Get precision from LOT_SIZE filter
-			for _, filter := range s.Filters {
-				if filter["filterType"] == "LOT_SIZE" {
-					stepSize := filter["stepSize"].(string)
-					precision := calculatePrecision(stepSize)
-					logger.Infof("  %s quantity precision: %d (stepSize: %s)", symbol, precision, stepSize)
-					return precision, nil
-				}
-			}
-		}
+		return nil, fmt.Errorf("failed to get trading rules: %w", err)
 	}
 
-	logger.Infof("  ⚠ %s precision information not found, using default precision 3", symbol)
-	return 3, nil // Default precision is 3
-}
-
-// calculatePrecision calculates precision from stepSize
-func calculatePrecision(stepSize string) int {
-	// Remove trailing zeros
-	stepSize = trimTrailingZeros(stepSize)
-
-	// Find decimal point
-	dotIndex := -1
-	for i := 0; i < len(stepSize); i++ {
-		if stepSize[i] == '.' {
-			dotIndex = i
-			break
+	// Max leverage is only available from the (signed) leverage brackets, it stays unknown if they fail
+	maxLeverage := make(map[string]int)
+	if brackets, err := t.client.NewGetLeverageBracketService().Do(context.Background()); err == nil {
+		for _, b := range brackets {
+			if len(b.Brackets) > 0 {
+				maxLeverage[b.Symbol] = b.Brackets[0].InitialLeverage
+			}
 		}
 	}
 
-	// If no decimal point or decimal point is at the end, precision is 0
-	if dotIndex == -1 || dotIndex == len(stepSize)-1 {
-		return 0
-	}
-
-	// Return number of digits after decimal point
-	return len(stepSize) - dotIndex - 1
-}
-
-// trimTrailingZeros removes trailing zeros
-func trimTrailingZeros(s string) string {
-	// If no decimal point, return directly
-	if !stringContains(s, ".") {
-		return s
-	}
-
-	// Iterate backwards to remove trailing zeros
-	for len(s) > 0 && s[len(s)-1] == '0' {
-		s = s[:len(s)-1]
-	}
-
-	// If last character is decimal point, remove it too
-	if len(s) > 0 && s[len(s)-1] == '.' {
-		s = s[:len(s)-1]
+	var instruments []Instrument
+	for i := range exchangeInfo.Symbols {
+		s := &exchangeInfo.Symbols[i]
+		if s.ContractType != futures.ContractTypePerpetual || s.QuoteAsset != "USDT" || s.Status != "TRADING" {
+			continue
+		}
+		inst := Instrument{Symbol: s.Symbol, VenueSymbol: s.Symbol, MaxLeverage: maxLeverage[s.Symbol], ContractMultiplier: 1}
+		if f := s.LotSizeFilter(); f != nil {
+			inst.LotStep = parseFloatOrZero(f.StepSize)
+			inst.MinQty = parseFloatOrZero(f.MinQuantity)
+		}
+		if f := s.PriceFilter(); f != nil {
+			inst.TickSize = parseFloatOrZero(f.TickSize)
+		}
+		if f := s.MinNotionalFilter(); f != nil {
+			inst.MinNotional = parseFloatOrZero(f.Notional)
+		}
+		instruments = append(instruments, inst)
 	}
-
-	return s
+	return instruments, nil
 }
 
-// FormatQuantity formats quantity to correct precision
+// FormatQuantity formats quantity to the lot step of the instrument registry
 func (t *FuturesTrader) FormatQuantity(symbol string, quantity float64) (string, error) {
-	precision, err := t.GetSymbolPrecision(symbol)
+	inst, err := venueInstrument(Venue{Exchange: "binance", Testnet: t.testnet}, t, symbol)
 	if err != nil {
 		// If retrieval fails, use default format
+		logger.Infof("  ⚠ %v, using default precision 3", err)
 		return fmt.Sprintf("%.3f", quantity), nil
 	}
-
-	format := fmt.Sprintf("%%.%df", precision)
-	return fmt.Sprintf(format, quantity), nil
+	return inst.FormatQuantity(quantity), nil
 }
 
 // Helper functions
@@ -935,27 +927,93 @@ func (t *FuturesTrader) GetOrderStatus(symbol string, orderID string) (map[strin
 		return nil, fmt.Errorf("failed to get order status: %w", err)
 	}
 
+	return binanceOrderToMap(order), nil
+}
+
+// GetOrderByClientID gets order status by client order ID
+func (t *FuturesTrader) GetOrderByClientID(symbol string, clientOrderID string) (map[string]interface{}, error) {
+	order, err := t.client.NewGetOrderService().
+		Symbol(symbol).
+		OrigClientOrderID(binanceClientOrderID(clientOrderID)).
+		Do(context.Background())
+	if err != nil {
+		return nil, fmt.Errorf("failed to get order by client order ID: %w", err)
+	}
+
+	return binanceOrderToMap(order), nil
+}
+
+// binanceOrderToMap converts Binance order to unified order status map
+func binanceOrderToMap(order *futures.Order) map[string]interface{} {
 	// Parse execution price
 	avgPrice, _ := strconv.ParseFloat(order.AvgPrice, 64)
 	executedQty, _ := strconv.ParseFloat(order.ExecutedQuantity, 64)
 
 	result := map[string]interface{}{
-		"orderId":     order.OrderID,
-		"symbol":      order.Symbol,
-		"status":      string(order.Status),
-		"avgPrice":    avgPrice,
-		"executedQty": executedQty,
-		"side":        string(order.Side),
-		"type":        string(order.Type),
-		"time":        order.Time,
-		"updateTime":  order.UpdateTime,
+		"orderId":       order.OrderID,
+		"clientOrderId": order.ClientOrderID,
+		"symbol":        order.Symbol,
+		"status":        string(order.Status),
+		"avgPrice":      avgPrice,
+		"executedQty":   executedQty,
+		"side":          string(order.Side),
+		"type":          string(order.Type),
+		"time":          order.Time,
+		"updateTime":    order.UpdateTime,
 	}
 
 	// Binance futures commission fee needs to be obtained through GetUserTrades, not retrieved here for now
 	// Can be obtained later through WebSocket or separate query
 	result["commission"] = 0.0
 
-	return result, nil
+	return result
+}
+
+// binanceClientOrderID converts a client order ID to Binance format (keeps br ID prefix, max 32 characters)
+// Empty ID falls back to a random br order ID
+func binanceClientOrderID(clientOrderID string) string {
+	if clientOrderID == "" {
+		return getBrOrderID()
+	}
+	const prefix = "x-KzrpZaP9"
+	if strings.HasPrefix(clientOrderID, prefix) {
+		return clientOrderID
+	}
+	id := prefix + clientOrderID
+	if len(id) > 32 {
+		id = id[:32]
+	}
+	return id
+}
+
+// placeMarketOrder places a market order with client order ID, resolving ambiguous failures by client order ID
+func (t *FuturesTrader) placeMarketOrder(symbol string, side futures.SideType, positionSide futures.PositionSideType, quantityStr string, clientOrderID string) (map[string]interface{}, error) {
+	clOrdID := binanceClientOrderID(clientOrderID)
+
+	submit := func() (map[string]interface{}, error) {
+		order, err := t.client.NewCreateOrderService().
+			Symbol(symbol).
+			Side(side).
+			PositionSide(positionSide).
+			Type(futures.OrderTypeMarket).
+			Quantity(quantityStr).
+			NewClientOrderID(clOrdID).
+			Do(context.Background())
+		if err != nil {
+			return nil, err
+		}
+		return map[string]interface{}{
+			"orderId":       order.OrderID,
+			"clientOrderId": order.ClientOrderID,
+			"symbol":        order.Symbol,
+			"status":        order.Status,
+		}, nil
+	}
+	lookup := func() (map[string]interface{}, error) {
+		return t.GetOrderByClientID(symbol, clOrdID)
+	}
+
+	return submitWithClientOrderID(clOrdID, submit, lookup)
 }
 
 // GetClosedPnL retrieves recent closing trades from Binance Futures
@@ -1017,47 +1075,126 @@ func (t *FuturesTrader) GetClosedPnL(startTime time.Time, limit int) ([]ClosedPn
 	return records, nil
 }
 
-// GetTrades retrieves trade history from Binance Futures using Income API
+// GetTrades retrieves the earliest fills since startTime (up to limit) from Binance Futures for all symbols
+// userTrades is per symbol and limited to 7-day windows, so traded symbols of each window are discovered
+// from the Income API first. Windows are walked from startTime only until limit fills are collected,
+// so paging through a long history (fetchTradeHistory) doesn't refetch it from the start on every page.
 // Note: Income API has delays (~minutes), for real-time use GetTradesForSymbol instead
 func (t *FuturesTrader) GetTrades(startTime time.Time, limit int) ([]TradeRecord, error) {
+	const window = 7 * 24 * time.Hour
 	if limit <= 0 {
-		limit = 100
-	}
-	if limit > 1000 {
 		limit = 1000
 	}
 
-	// Use Income API to get REALIZED_PNL records (all symbols)
-	incomes, err := t.client.NewGetIncomeHistoryService().
-		IncomeType("REALIZED_PNL").
-		StartTime(startTime.UnixMilli()).
-		Limit(int64(limit)).
-		Do(context.Background())
-	if err != nil {
-		return nil, fmt.Errorf("failed to get income history: %w", err)
+	var trades []TradeRecord
+	now := time.Now()
+	for windowStart := startTime; windowStart.Before(now) && len(trades) < limit; windowStart = windowStart.Add(window) {
+		windowEnd := windowStart.Add(window)
+		if windowEnd.After(now) {
+			windowEnd = now
+		}
+
+		symbols, err := t.getTradedSymbols(windowStart, windowEnd)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, symbol := range symbols {
+			from := windowStart
+			for {
+				accountTrades, err := t.client.NewListAccountTradeService().
+					Symbol(symbol).
+					StartTime(from.UnixMilli()).
+					EndTime(windowEnd.UnixMilli()).
+					Limit(1000).
+					Do(context.Background())
+				if err != nil {
+					return nil, fmt.Errorf("failed to get trade history for %s: %w", symbol, err)
+				}
+
+				for _, at := range accountTrades {
+					trades = append(trades, convertBinanceAccountTrade(at))
+				}
+
+				if len(accountTrades) < 1000 {
+					break
+				}
+				from = time.UnixMilli(accountTrades[len(accountTrades)-1].Time + 1)
+			}
+		}
 	}
 
-	var trades []TradeRecord
-	for _, income := range incomes {
-		pnl, _ := strconv.ParseFloat(income.Income, 64)
-		if pnl == 0 {
-			continue // Skip zero PnL records
+	return sortAndLimitTrades(trades, limit), nil
+}
+
+// getTradedSymbols lists symbols with commission or realized PnL income between startTime and endTime
+func (t *FuturesTrader) getTradedSymbols(startTime, endTime time.Time) ([]string, error) {
+	const pageSize = 1000
+
+	seen := make(map[string]bool)
+	var symbols []string
+	for _, incomeType := range []string{"COMMISSION", "REALIZED_PNL"} {
+		from := startTime
+		for {
+			incomes, err := t.client.NewGetIncomeHistoryService().
+				IncomeType(incomeType).
+				StartTime(from.UnixMilli()).
+				EndTime(endTime.UnixMilli()).
+				Limit(pageSize).
+				Do(context.Background())
+			if err != nil {
+				return nil, fmt.Errorf("failed to get income history: %w", err)
+			}
+
+			for _, income := range incomes {
+				if income.Symbol != "" && !seen[income.Symbol] {
+					seen[income.Symbol] = true
+					symbols = append(symbols, income.Symbol)
+				}
+			}
+
+			if len(incomes) < pageSize {
+				break
+			}
+			from = time.UnixMilli(incomes[len(incomes)-1].Time + 1)
+		}
+	}
+
+	return symbols, nil
+}
+
+// GetFundingHistory retrieves funding payments via Income API (FUNDING_FEE)
+func (t *FuturesTrader) GetFundingHistory(startTime time.Time) ([]FundingRecord, error) {
+	const pageSize = 1000
+
+	var records []FundingRecord
+	for {
+		incomes, err := t.client.NewGetIncomeHistoryService().
+			IncomeType("FUNDING_FEE").
+			StartTime(startTime.UnixMilli()).
+			Limit(pageSize).
+			Do(context.Background())
+		if err != nil {
+			return nil, fmt.Errorf("failed to get funding history: %w", err)
 		}
 
-		// Income API doesn't provide full trade details, create a minimal record
-		// This is mainly used for detecting recent closures, not historical reconstruction
-		trade := TradeRecord{
-			TradeID:     strconv.FormatInt(income.TranID, 10),
-			Symbol:      income.Symbol,
-			RealizedPnL: pnl,
-			Time:        time.UnixMilli(income.Time),
-			// Note: Income API doesn't provide price, quantity, side, fee
-			// For accurate data, use GetTradesForSymbol with specific symbol
+		for _, income := range incomes {
+			amount, _ := strconv.ParseFloat(income.Income, 64)
+			records = append(records, FundingRecord{
+				Symbol: income.Symbol,
+				Amount: amount,
+				Time:   time.UnixMilli(income.Time),
+				TxID:   strconv.FormatInt(income.TranID, 10),
+			})
 		}
-		trades = append(trades, trade)
+
+		if len(incomes) < pageSize {
+			break
+		}
+		startTime = time.UnixMilli(incomes[len(incomes)-1].Time + 1)
 	}
 
-	return trades, nil
+	return records, nil
 }
 
 // GetTradesForSymbol retrieves trade history for a specific symbol
@@ -1081,24 +1218,28 @@ func (t *FuturesTrader) GetTradesForSymbol(symbol string, startTime time.Time, l
 
 	var trades []TradeRecord
 	for _, at := range accountTrades {
-		price, _ := strconv.ParseFloat(at.Price, 64)
-		qty, _ := strconv.ParseFloat(at.Quantity, 64)
-		fee, _ := strconv.ParseFloat(at.Commission, 64)
-		pnl, _ := strconv.ParseFloat(at.RealizedPnl, 64)
-
-		trade := TradeRecord{
-			TradeID:      strconv.FormatInt(at.ID, 10),
-			Symbol:       at.Symbol,
-			Side:         string(at.Side),
-			PositionSide: string(at.PositionSide),
-			Price:        price,
-			Quantity:     qty,
-			RealizedPnL:  pnl,
-			Fee:          fee,
-			Time:         time.UnixMilli(at.Time),
-		}
-		trades = append(trades, trade)
+		trades = append(trades, convertBinanceAccountTrade(at))
 	}
 
 	return trades, nil
 }
+
+// convertBinanceAccountTrade converts a userTrades entry to the unified TradeRecord format
+func convertBinanceAccountTrade(at *futures.AccountTrade) TradeRecord {
+	price, _ := strconv.ParseFloat(at.Price, 64)
+	qty, _ := strconv.ParseFloat(at.Quantity, 64)
+	fee, _ := strconv.ParseFloat(at.Commission, 64)
+	pnl, _ := strconv.ParseFloat(at.RealizedPnl, 64)
+
+	return TradeRecord{
+		TradeID:      strconv.FormatInt(at.ID, 10),
+		Symbol:       at.Symbol,
+		Side:         string(at.Side),
+		PositionSide: string(at.PositionSide),
+		Price:        price,
+		Quantity:     qty,
+		RealizedPnL:  pnl,
+		Fee:          fee,
+		Time:         time.UnixMilli(at.Time),
+	}
+}
@@ -299,6 +299,7 @@ func (s *BinanceFuturesTestSuite) Cleanup() {
 // TestFuturesTrader_InterfaceCompliance tests interface compliance
 func TestFuturesTrader_InterfaceCompliance(t *testing.T) {
 	var _ Trader = (*FuturesTrader)(nil)
+	var _ StopOrderProvider = (*FuturesTrader)(nil)
 }
 
 // TestFuturesTrader_CommonInterface runs all common interface tests using test suite
@@ -343,7 +344,7 @@ func TestNewFuturesTrader(t *testing.T) {
 	defer mockServer.Close()
 
 	// Test successful creation
-	trader := NewFuturesTrader("test_api_key", "test_secret_key", "test_user")
+	trader := NewFuturesTrader("test_api_key", "test_secret_key", "test_user", false)
 
 	// Modify client to use mock server
 	trader.client.BaseURL = mockServer.URL
@@ -418,3 +419,59 @@ func TestGetBrOrderID(t *testing.T) {
 		ids[id] = true
 	}
 }
+
+// TestFuturesTrader_GetTradesPaging tests that a page only walks the 7-day windows it needs
+func TestFuturesTrader_GetTradesPaging(t *testing.T) {
+	start := time.Now().Add(-30 * 24 * time.Hour).Truncate(time.Millisecond)
+	fillTimes := []time.Time{start.Add(24 * time.Hour), start.Add(48 * time.Hour), start.Add(20 * 24 * time.Hour)}
+
+	var tradeRequests int
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		var from, to int64
+		fmt.Sscan(query.Get("startTime"), &from)
+		fmt.Sscan(query.Get("endTime"), &to)
+
+		respBody := []map[string]interface{}{}
+		for i, ts := range fillTimes {
+			if ms := ts.UnixMilli(); ms < from || ms > to {
+				continue
+			}
+			switch r.URL.Path {
+			case "/fapi/v1/income":
+				respBody = append(respBody, map[string]interface{}{
+					"symbol": "BTCUSDT", "incomeType": query.Get("incomeType"), "income": "-0.1", "time": ts.UnixMilli(),
+				})
+			case "/fapi/v1/userTrades":
+				respBody = append(respBody, map[string]interface{}{
+					"symbol": "BTCUSDT", "id": i + 1, "orderId": i + 1, "side": "BUY", "positionSide": "LONG",
+					"price": "100", "qty": "1", "commission": "0.1", "time": ts.UnixMilli(),
+				})
+			}
+		}
+		if r.URL.Path == "/fapi/v1/userTrades" {
+			tradeRequests++
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(respBody)
+	}))
+	defer mockServer.Close()
+
+	client := futures.NewClient("test_api_key", "test_secret_key")
+	client.BaseURL = mockServer.URL
+	client.HTTPClient = mockServer.Client()
+	trader := &FuturesTrader{client: client}
+
+	// The first window already fills the page, later windows aren't fetched
+	page, err := trader.GetTrades(start, 2)
+	assert.NoError(t, err)
+	assert.Len(t, page, 2)
+	assert.Equal(t, 1, tradeRequests)
+
+	// Paging through the history fetches each window with fills once per page
+	tradeRequests = 0
+	trades, err := fetchTradeHistory(trader, start)
+	assert.NoError(t, err)
+	assert.Len(t, trades, 3)
+	assert.Equal(t, 2, tradeRequests)
+}
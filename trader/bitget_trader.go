@@ -8,8 +8,10 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
+	"math"
 	"net/http"
 	"nofx/logger"
+	"sort"
 	"strconv"
 	"strings"
 	"sync"
@@ -38,6 +40,9 @@ type BitgetTrader struct {
 	secretKey  string
 	passphrase string
 
+	// Demo trading (paptrading header)
+	testnet bool
+
 	// HTTP client
 	httpClient *http.Client
 
@@ -51,27 +56,10 @@ type BitgetTrader struct {
 	positionsCacheTime  time.Time
 	positionsCacheMutex sync.RWMutex
 
-	// Contract info cache
-	contractsCache      map[string]*BitgetContract
-	contractsCacheTime  time.Time
-	contractsCacheMutex sync.RWMutex
-
 	// Cache duration
 	cacheDuration time.Duration
 }
 
-// BitgetContract Bitget contract info
-type BitgetContract struct {
-	Symbol       string  // Symbol name
-	BaseCoin     string  // Base coin
-	QuoteCoin    string  // Quote coin
-	MinTradeNum  float64 // Minimum trade amount
-	MaxTradeNum  float64 // Maximum trade amount
-	SizeMultiplier float64 // Contract size multiplier
-	PricePlace   int     // Price decimal places
-	VolumePlace  int     // Volume decimal places
-}
-
 // BitgetResponse Bitget API response
 type BitgetResponse struct {
 	Code    string          `json:"code"`
@@ -81,19 +69,20 @@ type BitgetResponse struct {
 }
 
 // NewBitgetTrader creates a Bitget trader
-func NewBitgetTrader(apiKey, secretKey, passphrase string) *BitgetTrader {
+// testnet routes all requests to Bitget demo trading (requires demo API keys)
+func NewBitgetTrader(apiKey, secretKey, passphrase string, testnet bool) *BitgetTrader {
 	httpClient := &http.Client{
 		Timeout:   30 * time.Second,
 		Transport: http.DefaultTransport,
 	}
 
 	trader := &BitgetTrader{
-		apiKey:         apiKey,
-		secretKey:      secretKey,
-		passphrase:     passphrase,
-		httpClient:     httpClient,
-		cacheDuration:  15 * time.Second,
-		contractsCache: make(map[string]*BitgetContract),
+		apiKey:        apiKey,
+		secretKey:     secretKey,
+		passphrase:    passphrase,
+		testnet:       testnet,
+		httpClient:    httpClient,
+		cacheDuration: 15 * time.Second,
 	}
 
 	// Set one-way position mode (net mode)
@@ -101,7 +90,7 @@ func NewBitgetTrader(apiKey, secretKey, passphrase string) *BitgetTrader {
 		logger.Infof("⚠️ Failed to set Bitget position mode: %v (ignore if already set)", err)
 	}
 
-	logger.Infof("🟢 [Bitget] Trader initialized")
+	logger.Infof("🟢 [Bitget] Trader initialized (%s)", environmentName(testnet))
 
 	return trader
 }
@@ -182,6 +171,9 @@ func (t *BitgetTrader) doRequest(method, path string, body interface{}) ([]byte,
 	req.Header.Set("ACCESS-PASSPHRASE", t.passphrase)
 	req.Header.Set("Content-Type", "application/json")
 	req.Header.Set("locale", "en-US")
+	if t.testnet {
+		req.Header.Set("paptrading", "1")
+	}
 	// Channel code only for order endpoints
 	if strings.Contains(path, "/order/") {
 		req.Header.Set("X-CHANNEL-API-CODE", "7fygt")
@@ -359,74 +351,51 @@ func (t *BitgetTrader) GetPositions() ([]map[string]interface{}, error) {
 	return result, nil
 }
 
-// getContract gets contract info
-func (t *BitgetTrader) getContract(symbol string) (*BitgetContract, error) {
-	symbol = t.convertSymbol(symbol)
-
-	// Check cache
-	t.contractsCacheMutex.RLock()
-	if contract, ok := t.contractsCache[symbol]; ok && time.Since(t.contractsCacheTime) < 5*time.Minute {
-		t.contractsCacheMutex.RUnlock()
-		return contract, nil
-	}
-	t.contractsCacheMutex.RUnlock()
-
-	params := map[string]interface{}{
-		"productType": "USDT-FUTURES",
-		"symbol":      symbol,
-	}
-
-	data, err := t.doRequest("GET", bitgetContractsPath, params)
+// GetInstruments gets trading rules of all USDT perpetuals (implements InstrumentProvider)
+func (t *BitgetTrader) GetInstruments() ([]Instrument, error) {
+	data, err := t.doRequest("GET", bitgetContractsPath, map[string]interface{}{"productType": "USDT-FUTURES"})
 	if err != nil {
 		return nil, err
 	}
 
 	var contracts []struct {
 		Symbol         string `json:"symbol"`
-		BaseCoin       string `json:"baseCoin"`
 		QuoteCoin      string `json:"quoteCoin"`
+		SymbolStatus   string `json:"symbolStatus"`
 		MinTradeNum    string `json:"minTradeNum"`
-		MaxTradeNum    string `json:"maxTradeNum"`
+		MinTradeUSDT   string `json:"minTradeUSDT"`
 		SizeMultiplier string `json:"sizeMultiplier"`
 		PricePlace     string `json:"pricePlace"`
-		VolumePlace    string `json:"volumePlace"`
+		PriceEndStep   string `json:"priceEndStep"`
+		MaxLever       string `json:"maxLever"`
 	}
-
 	if err := json.Unmarshal(data, &contracts); err != nil {
 		return nil, err
 	}
 
-	// Find matching contract
+	var instruments []Instrument
 	for _, c := range contracts {
-		if c.Symbol == symbol {
-			minTrade, _ := strconv.ParseFloat(c.MinTradeNum, 64)
-			maxTrade, _ := strconv.ParseFloat(c.MaxTradeNum, 64)
-			sizeMult, _ := strconv.ParseFloat(c.SizeMultiplier, 64)
-			pricePlace, _ := strconv.Atoi(c.PricePlace)
-			volumePlace, _ := strconv.Atoi(c.VolumePlace)
-
-			contract := &BitgetContract{
-				Symbol:         c.Symbol,
-				BaseCoin:       c.BaseCoin,
-				QuoteCoin:      c.QuoteCoin,
-				MinTradeNum:    minTrade,
-				MaxTradeNum:    maxTrade,
-				SizeMultiplier: sizeMult,
-				PricePlace:     pricePlace,
-				VolumePlace:    volumePlace,
-			}
-
-			// Update cache
-			t.contractsCacheMutex.Lock()
-			t.contractsCache[symbol] = contract
-			t.contractsCacheTime = time.Now()
-			t.contractsCacheMutex.Unlock()
-
-			return contract, nil
+		if c.QuoteCoin != "USDT" || (c.SymbolStatus != "" && c.SymbolStatus != "normal") {
+			continue
 		}
-	}
-
-	return nil, fmt.Errorf("contract info not found: %s", symbol)
+		// Tick size = priceEndStep × 10^-pricePlace
+		pricePlace, _ := strconv.Atoi(c.PricePlace)
+		tick := math.Pow10(-pricePlace)
+		if step := parseFloatOrZero(c.PriceEndStep); step > 0 {
+			tick *= step
+		}
+		instruments = append(instruments, Instrument{
+			Symbol:             c.Symbol,
+			VenueSymbol:        c.Symbol,
+			TickSize:           tick,
+			LotStep:            parseFloatOrZero(c.SizeMultiplier),
+			MinQty:             parseFloatOrZero(c.MinTradeNum),
+			MinNotional:        parseFloatOrZero(c.MinTradeUSDT),
+			MaxLeverage:        int(parseFloatOrZero(c.MaxLever)),
+			ContractMultiplier: 1,
+		})
+	}
+	return instruments, nil
 }
 
 // SetMarginMode sets margin mode
@@ -487,6 +456,11 @@ func (t *BitgetTrader) SetLeverage(symbol string, leverage int) error {
 
 // OpenLong opens long position
 func (t *BitgetTrader) OpenLong(symbol string, quantity float64, leverage int) (map[string]interface{}, error) {
+	return t.OpenLongWithClientID(symbol, quantity, leverage, "")
+}
+
+// OpenLongWithClientID opens long position tagged with client order ID
+func (t *BitgetTrader) OpenLongWithClientID(symbol string, quantity float64, leverage int, clientOrderID string) (map[string]interface{}, error) {
 	symbol = t.convertSymbol(symbol)
 
 	// Cancel old orders first
@@ -508,39 +482,31 @@ func (t *BitgetTrader) OpenLong(symbol string, quantity float64, leverage int) (
 		"side":        "buy",
 		"orderType":   "market",
 		"size":        qtyStr,
-		"clientOid":   genBitgetClientOid(),
+		"clientOid":   bitgetClientOid(clientOrderID),
 	}
 
 	logger.Infof("  📊 Bitget OpenLong: symbol=%s, qty=%s, leverage=%d", symbol, qtyStr, leverage)
 
-	data, err := t.doRequest("POST", bitgetOrderPath, body)
+	order, err := t.placeOrder(symbol, body)
 	if err != nil {
 		return nil, fmt.Errorf("failed to open long position: %w", err)
 	}
 
-	var order struct {
-		OrderId   string `json:"orderId"`
-		ClientOid string `json:"clientOid"`
-	}
-
-	if err := json.Unmarshal(data, &order); err != nil {
-		return nil, fmt.Errorf("failed to parse order response: %w", err)
-	}
-
 	// Clear cache
 	t.clearCache()
 
 	logger.Infof("✓ Bitget opened long position successfully: %s", symbol)
 
-	return map[string]interface{}{
-		"orderId": order.OrderId,
-		"symbol":  symbol,
-		"status":  "FILLED",
-	}, nil
+	return order, nil
 }
 
 // OpenShort opens short position
 func (t *BitgetTrader) OpenShort(symbol string, quantity float64, leverage int) (map[string]interface{}, error) {
+	return t.OpenShortWithClientID(symbol, quantity, leverage, "")
+}
+
+// OpenShortWithClientID opens short position tagged with client order ID
+func (t *BitgetTrader) OpenShortWithClientID(symbol string, quantity float64, leverage int, clientOrderID string) (map[string]interface{}, error) {
 	symbol = t.convertSymbol(symbol)
 
 	// Cancel old orders first
@@ -562,39 +528,31 @@ func (t *BitgetTrader) OpenShort(symbol string, quantity float64, leverage int)
 		"side":        "sell",
 		"orderType":   "market",
 		"size":        qtyStr,
-		"clientOid":   genBitgetClientOid(),
+		"clientOid":   bitgetClientOid(clientOrderID),
 	}
 
 	logger.Infof("  📊 Bitget OpenShort: symbol=%s, qty=%s, leverage=%d", symbol, qtyStr, leverage)
 
-	data, err := t.doRequest("POST", bitgetOrderPath, body)
+	order, err := t.placeOrder(symbol, body)
 	if err != nil {
 		return nil, fmt.Errorf("failed to open short position: %w", err)
 	}
 
-	var order struct {
-		OrderId   string `json:"orderId"`
-		ClientOid string `json:"clientOid"`
-	}
-
-	if err := json.Unmarshal(data, &order); err != nil {
-		return nil, fmt.Errorf("failed to parse order response: %w", err)
-	}
-
 	// Clear cache
 	t.clearCache()
 
 	logger.Infof("✓ Bitget opened short position successfully: %s", symbol)
 
-	return map[string]interface{}{
-		"orderId": order.OrderId,
-		"symbol":  symbol,
-		"status":  "FILLED",
-	}, nil
+	return order, nil
 }
 
 // CloseLong closes long position
 func (t *BitgetTrader) CloseLong(symbol string, quantity float64) (map[string]interface{}, error) {
+	return t.CloseLongWithClientID(symbol, quantity, "")
+}
+
+// CloseLongWithClientID closes long position tagged with client order ID
+func (t *BitgetTrader) CloseLongWithClientID(symbol string, quantity float64, clientOrderID string) (map[string]interface{}, error) {
 	symbol = t.convertSymbol(symbol)
 
 	// If quantity is 0, get current position
@@ -626,38 +584,31 @@ func (t *BitgetTrader) CloseLong(symbol string, quantity float64) (map[string]in
 		"orderType":   "market",
 		"size":        qtyStr,
 		"reduceOnly":  "YES",
-		"clientOid":   genBitgetClientOid(),
+		"clientOid":   bitgetClientOid(clientOrderID),
 	}
 
 	logger.Infof("  📊 Bitget CloseLong: symbol=%s, qty=%s", symbol, qtyStr)
 
-	data, err := t.doRequest("POST", bitgetOrderPath, body)
+	order, err := t.placeOrder(symbol, body)
 	if err != nil {
 		return nil, fmt.Errorf("failed to close long position: %w", err)
 	}
 
-	var order struct {
-		OrderId string `json:"orderId"`
-	}
-
-	if err := json.Unmarshal(data, &order); err != nil {
-		return nil, err
-	}
-
 	// Clear cache
 	t.clearCache()
 
 	logger.Infof("✓ Bitget closed long position successfully: %s", symbol)
 
-	return map[string]interface{}{
-		"orderId": order.OrderId,
-		"symbol":  symbol,
-		"status":  "FILLED",
-	}, nil
+	return order, nil
 }
 
 // CloseShort closes short position
 func (t *BitgetTrader) CloseShort(symbol string, quantity float64) (map[string]interface{}, error) {
+	return t.CloseShortWithClientID(symbol, quantity, "")
+}
+
+// CloseShortWithClientID closes short position tagged with client order ID
+func (t *BitgetTrader) CloseShortWithClientID(symbol string, quantity float64, clientOrderID string) (map[string]interface{}, error) {
 	symbol = t.convertSymbol(symbol)
 
 	// If quantity is 0, get current position
@@ -694,34 +645,22 @@ func (t *BitgetTrader) CloseShort(symbol string, quantity float64) (map[string]i
 		"orderType":   "market",
 		"size":        qtyStr,
 		"reduceOnly":  "YES",
-		"clientOid":   genBitgetClientOid(),
+		"clientOid":   bitgetClientOid(clientOrderID),
 	}
 
 	logger.Infof("  📊 Bitget CloseShort: symbol=%s, qty=%s", symbol, qtyStr)
 
-	data, err := t.doRequest("POST", bitgetOrderPath, body)
+	order, err := t.placeOrder(symbol, body)
 	if err != nil {
 		return nil, fmt.Errorf("failed to close short position: %w", err)
 	}
 
-	var order struct {
-		OrderId string `json:"orderId"`
-	}
-
-	if err := json.Unmarshal(data, &order); err != nil {
-		return nil, err
-	}
-
 	// Clear cache
 	t.clearCache()
 
 	logger.Infof("✓ Bitget closed short position successfully: %s", symbol)
 
-	return map[string]interface{}{
-		"orderId": order.OrderId,
-		"symbol":  symbol,
-		"status":  "FILLED",
-	}, nil
+	return order, nil
 }
 
 // GetMarketPrice gets market price
@@ -936,26 +875,78 @@ func (t *BitgetTrader) CancelStopOrders(symbol string) error {
 	return nil
 }
 
-// FormatQuantity formats quantity
+// FormatQuantity formats quantity rounded down to the lot step of the instrument registry
 func (t *BitgetTrader) FormatQuantity(symbol string, quantity float64) (string, error) {
-	contract, err := t.getContract(symbol)
+	inst, err := venueInstrument(Venue{Exchange: "bitget", Testnet: t.testnet}, t, symbol)
 	if err != nil {
 		return fmt.Sprintf("%.4f", quantity), nil
 	}
+	return inst.FormatQuantity(quantity), nil
+}
+
+// placeOrder submits an order request, resolving ambiguous failures by clientOid
+func (t *BitgetTrader) placeOrder(symbol string, body map[string]interface{}) (map[string]interface{}, error) {
+	clientOid, _ := body["clientOid"].(string)
+
+	submit := func() (map[string]interface{}, error) {
+		data, err := t.doRequest("POST", bitgetOrderPath, body)
+		if err != nil {
+			return nil, err
+		}
+
+		var order struct {
+			OrderId   string `json:"orderId"`
+			ClientOid string `json:"clientOid"`
+		}
 
-	// Format according to volume precision
-	format := fmt.Sprintf("%%.%df", contract.VolumePlace)
-	return fmt.Sprintf(format, quantity), nil
+		if err := json.Unmarshal(data, &order); err != nil {
+			return nil, fmt.Errorf("failed to parse order response: %w", err)
+		}
+
+		return map[string]interface{}{
+			"orderId":       order.OrderId,
+			"clientOrderId": order.ClientOid,
+			"symbol":        symbol,
+			"status":        "FILLED",
+		}, nil
+	}
+	lookup := func() (map[string]interface{}, error) {
+		return t.GetOrderByClientID(symbol, clientOid)
+	}
+
+	return submitWithClientOrderID(clientOid, submit, lookup)
+}
+
+// bitgetClientOid converts a client order ID to Bitget clientOid (max 50 characters)
+// Empty ID falls back to a random ID
+func bitgetClientOid(clientOrderID string) string {
+	if clientOrderID == "" {
+		return genBitgetClientOid()
+	}
+	if strings.HasPrefix(clientOrderID, "nofx") {
+		return clientOrderID
+	}
+	return "nofx" + clientOrderID
 }
 
 // GetOrderStatus gets order status
 func (t *BitgetTrader) GetOrderStatus(symbol string, orderID string) (map[string]interface{}, error) {
+	return t.queryOrder(symbol, "orderId", orderID)
+}
+
+// GetOrderByClientID gets order status by client order ID (clientOid)
+func (t *BitgetTrader) GetOrderByClientID(symbol string, clientOrderID string) (map[string]interface{}, error) {
+	return t.queryOrder(symbol, "clientOid", bitgetClientOid(clientOrderID))
+}
+
+// queryOrder queries order detail by orderId or clientOid
+func (t *BitgetTrader) queryOrder(symbol string, idField string, id string) (map[string]interface{}, error) {
 	symbol = t.convertSymbol(symbol)
 
 	params := map[string]interface{}{
 		"symbol":      symbol,
 		"productType": "USDT-FUTURES",
-		"orderId":     orderID,
+		idField:       id,
 	}
 
 	data, err := t.doRequest("GET", "/api/v2/mix/order/detail", params)
@@ -965,6 +956,7 @@ func (t *BitgetTrader) GetOrderStatus(symbol string, orderID string) (map[string
 
 	var order struct {
 		OrderId      string `json:"orderId"`
+		ClientOid    string `json:"clientOid"`
 		State        string `json:"state"`        // filled, canceled, partially_filled, new
 		PriceAvg     string `json:"priceAvg"`     // Average fill price
 		BaseVolume   string `json:"baseVolume"`   // Filled quantity
@@ -999,16 +991,17 @@ func (t *BitgetTrader) GetOrderStatus(symbol string, orderID string) (map[string
 	}
 
 	return map[string]interface{}{
-		"orderId":     order.OrderId,
-		"symbol":      symbol,
-		"status":      status,
-		"avgPrice":    avgPrice,
-		"executedQty": fillQty,
-		"side":        order.Side,
-		"type":        order.OrderType,
-		"time":        cTime,
-		"updateTime":  uTime,
-		"commission":  -fee,
+		"orderId":       order.OrderId,
+		"clientOrderId": order.ClientOid,
+		"symbol":        symbol,
+		"status":        status,
+		"avgPrice":      avgPrice,
+		"executedQty":   fillQty,
+		"side":          order.Side,
+		"type":          order.OrderType,
+		"time":          cTime,
+		"updateTime":    uTime,
+		"commission":    -fee,
 	}, nil
 }
 
@@ -1079,6 +1072,149 @@ func (t *BitgetTrader) GetClosedPnL(startTime time.Time, limit int) ([]ClosedPnL
 	return records, nil
 }
 
+// GetTrades retrieves trade history (fills) from /api/v2/mix/order/fill-history
+// Bitget limits each query to a 7-day window, so the range is walked window by window
+// The account runs in one-way mode (see setPositionMode), so PositionSide is "BOTH"
+func (t *BitgetTrader) GetTrades(startTime time.Time, limit int) ([]TradeRecord, error) {
+	const (
+		window   = 7 * 24 * time.Hour
+		pageSize = 100
+	)
+	if limit <= 0 {
+		limit = 1000
+	}
+
+	var trades []TradeRecord
+	now := time.Now()
+	for windowStart := startTime; windowStart.Before(now) && len(trades) < limit; windowStart = windowStart.Add(window) {
+		windowEnd := windowStart.Add(window)
+		if windowEnd.After(now) {
+			windowEnd = now
+		}
+
+		idLessThan := ""
+		for {
+			params := map[string]interface{}{
+				"productType": "USDT-FUTURES",
+				"startTime":   fmt.Sprintf("%d", windowStart.UnixMilli()),
+				"endTime":     fmt.Sprintf("%d", windowEnd.UnixMilli()),
+				"limit":       fmt.Sprintf("%d", pageSize),
+			}
+			if idLessThan != "" {
+				params["idLessThan"] = idLessThan // Pagination: fills older than this ID
+			}
+
+			data, err := t.doRequest("GET", "/api/v2/mix/order/fill-history", params)
+			if err != nil {
+				return nil, fmt.Errorf("failed to get trade history: %w", err)
+			}
+
+			var resp struct {
+				FillList []struct {
+					TradeID    string `json:"tradeId"`
+					Symbol     string `json:"symbol"`
+					Price      string `json:"price"`
+					BaseVolume string `json:"baseVolume"`
+					Side       string `json:"side"`
+					Profit     string `json:"profit"`
+					FeeDetail  []struct {
+						TotalFee string `json:"totalFee"`
+					} `json:"feeDetail"`
+					CTime string `json:"cTime"`
+				} `json:"fillList"`
+				EndID string `json:"endId"`
+			}
+			if err := json.Unmarshal(data, &resp); err != nil {
+				return nil, fmt.Errorf("failed to parse trade history: %w", err)
+			}
+
+			for _, fill := range resp.FillList {
+				var fee float64
+				for _, detail := range fill.FeeDetail {
+					fee -= parseFloatOrZero(detail.TotalFee) // Negative = paid
+				}
+				cTime, _ := strconv.ParseInt(fill.CTime, 10, 64)
+				trades = append(trades, TradeRecord{
+					TradeID:      fill.TradeID,
+					Symbol:       strings.ToUpper(fill.Symbol),
+					Side:         strings.ToUpper(fill.Side),
+					PositionSide: "BOTH",
+					Price:        parseFloatOrZero(fill.Price),
+					Quantity:     parseFloatOrZero(fill.BaseVolume),
+					RealizedPnL:  parseFloatOrZero(fill.Profit),
+					Fee:          fee,
+					Time:         time.UnixMilli(cTime),
+				})
+			}
+
+			if len(resp.FillList) < pageSize || resp.EndID == "" {
+				break
+			}
+			idLessThan = resp.EndID
+		}
+	}
+
+	// Fills are returned newest first
+	return sortAndLimitTrades(trades, limit), nil
+}
+
+// GetFundingHistory retrieves funding payments from the account bill (businessType=contract_settle_fee)
+func (t *BitgetTrader) GetFundingHistory(startTime time.Time) ([]FundingRecord, error) {
+	const pageSize = 100
+
+	var records []FundingRecord
+	idLessThan := ""
+	for {
+		params := map[string]interface{}{
+			"productType":  "USDT-FUTURES",
+			"businessType": "contract_settle_fee",
+			"startTime":    fmt.Sprintf("%d", startTime.UnixMilli()),
+			"limit":        fmt.Sprintf("%d", pageSize),
+		}
+		if idLessThan != "" {
+			params["idLessThan"] = idLessThan // Pagination: bills older than this ID
+		}
+
+		data, err := t.doRequest("GET", "/api/v2/mix/account/bill", params)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get funding history: %w", err)
+		}
+
+		var resp struct {
+			Bills []struct {
+				BillID string `json:"billId"`
+				Symbol string `json:"symbol"`
+				Amount string `json:"amount"`
+				CTime  string `json:"cTime"`
+			} `json:"bills"`
+			EndID string `json:"endId"`
+		}
+		if err := json.Unmarshal(data, &resp); err != nil {
+			return nil, fmt.Errorf("failed to parse funding history: %w", err)
+		}
+
+		for _, bill := range resp.Bills {
+			amount, _ := strconv.ParseFloat(bill.Amount, 64)
+			cTime, _ := strconv.ParseInt(bill.CTime, 10, 64)
+			records = append(records, FundingRecord{
+				Symbol: bill.Symbol,
+				Amount: amount,
+				Time:   time.UnixMilli(cTime),
+				TxID:   bill.BillID,
+			})
+		}
+
+		if len(resp.Bills) < pageSize || resp.EndID == "" {
+			break
+		}
+		idLessThan = resp.EndID
+	}
+
+	// Bills are returned newest first
+	sort.Slice(records, func(i, j int) bool { return records[i].Time.Before(records[j].Time) })
+	return records, nil
+}
+
 // clearCache clears all caches
 func (t *BitgetTrader) clearCache() {
 	t.balanceCacheMutex.Lock()
@@ -0,0 +1,242 @@
+package trader
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+)
+
+const (
+	bybitPrivateWSURL        = "wss://stream.bybit.com/v5/private"
+	bybitTestnetPrivateWSURL = "wss://stream-testnet.bybit.com/v5/private"
+	bybitWSPingInterval      = 20 * time.Second
+	bybitWSAuthExpiresIn     = 10 * time.Second
+)
+
+// bybitWSMessage Bybit private stream message (operation responses and topic pushes)
+type bybitWSMessage struct {
+	Op           string          `json:"op"`
+	Success      *bool           `json:"success"`
+	RetMsg       string          `json:"ret_msg"`
+	Topic        string          `json:"topic"`
+	CreationTime int64           `json:"creationTime"`
+	Data         json.RawMessage `json:"data"`
+}
+
+// bybitWSExecution execution topic item
+type bybitWSExecution struct {
+	Category      string `json:"category"`
+	Symbol        string `json:"symbol"`
+	OrderID       string `json:"orderId"`
+	OrderLinkID   string `json:"orderLinkId"`
+	Side          string `json:"side"`
+	ExecPrice     string `json:"execPrice"`
+	ExecQty       string `json:"execQty"`
+	ExecFee       string `json:"execFee"`
+	ExecPnl       string `json:"execPnl"`
+	ExecType      string `json:"execType"`
+	ExecTime      string `json:"execTime"`
+	LeavesQty     string `json:"leavesQty"`
+	ClosedSize    string `json:"closedSize"`
+	StopOrderType string `json:"stopOrderType"`
+}
+
+// bybitWSPosition position topic item
+type bybitWSPosition struct {
+	Category    string `json:"category"`
+	Symbol      string `json:"symbol"`
+	Side        string `json:"side"`
+	Size        string `json:"size"`
+	EntryPrice  string `json:"entryPrice"`
+	PositionIdx int    `json:"positionIdx"`
+	UpdatedTime string `json:"updatedTime"`
+}
+
+// bybitWSWallet wallet topic item
+type bybitWSWallet struct {
+	Coin []struct {
+		Coin          string `json:"coin"`
+		WalletBalance string `json:"walletBalance"`
+	} `json:"coin"`
+}
+
+// StreamAccount streams Bybit private topics (execution/position/wallet) until ctx is cancelled or the stream drops
+func (t *BybitTrader) StreamAccount(ctx context.Context, handler func(AccountEvent)) error {
+	onOpen := func(c *accountWSConn) error {
+		expires := time.Now().Add(bybitWSAuthExpiresIn).UnixMilli()
+		mac := hmac.New(sha256.New, []byte(t.secretKey))
+		mac.Write([]byte(fmt.Sprintf("GET/realtime%d", expires)))
+		signature := hex.EncodeToString(mac.Sum(nil))
+
+		return c.writeJSON(map[string]interface{}{
+			"op":   "auth",
+			"args": []interface{}{t.apiKey, expires, signature},
+		})
+	}
+
+	ping := func(c *accountWSConn) error {
+		return c.writeJSON(map[string]string{"op": "ping"})
+	}
+
+	onMessage := func(c *accountWSConn, raw []byte) error {
+		var msg bybitWSMessage
+		if err := json.Unmarshal(raw, &msg); err != nil {
+			return nil // Ignore malformed messages
+		}
+
+		switch msg.Op {
+		case "auth":
+			if msg.Success == nil || !*msg.Success {
+				return fmt.Errorf("bybit stream authentication failed: %s", msg.RetMsg)
+			}
+			return c.writeJSON(map[string]interface{}{
+				"op":   "subscribe",
+				"args": []string{"execution", "position", "wallet"},
+			})
+		case "subscribe":
+			if msg.Success == nil || !*msg.Success {
+				return fmt.Errorf("bybit stream subscription failed: %s", msg.RetMsg)
+			}
+			t.clearCache()
+			handler(AccountEvent{Type: AccountEventConnected, Time: time.Now()})
+			return nil
+		}
+
+		if msg.Topic == "" {
+			return nil
+		}
+		for _, ev := range bybitAccountEvents(msg) {
+			if ev.Type == AccountEventPosition {
+				t.clearCache()
+			}
+			handler(ev)
+		}
+		return nil
+	}
+
+	wsURL := bybitPrivateWSURL
+	if t.testnet {
+		wsURL = bybitTestnetPrivateWSURL
+	}
+	return runAccountWebsocket(ctx, wsURL, bybitWSPingInterval, onOpen, ping, onMessage)
+}
+
+// bybitAccountEvents converts a Bybit topic push to unified account events
+func bybitAccountEvents(msg bybitWSMessage) []AccountEvent {
+	var events []AccountEvent
+
+	switch msg.Topic {
+	case "execution":
+		var executions []bybitWSExecution
+		if err := json.Unmarshal(msg.Data, &executions); err != nil {
+			return nil
+		}
+		for _, e := range executions {
+			if e.Category != "linear" || e.ExecType == "Funding" {
+				continue
+			}
+
+			price, _ := strconv.ParseFloat(e.ExecPrice, 64)
+			qty, _ := strconv.ParseFloat(e.ExecQty, 64)
+			fee, _ := strconv.ParseFloat(e.ExecFee, 64)
+			pnl, _ := strconv.ParseFloat(e.ExecPnl, 64)
+			closedSize, _ := strconv.ParseFloat(e.ClosedSize, 64)
+			leavesQty, _ := strconv.ParseFloat(e.LeavesQty, 64)
+			execTime, _ := strconv.ParseInt(e.ExecTime, 10, 64)
+
+			reduceOnly := closedSize > 0
+			// Buy opens long / closes short, Sell opens short / closes long
+			side := "LONG"
+			if (e.Side == "Sell") != reduceOnly {
+				side = "SHORT"
+			}
+
+			events = append(events, AccountEvent{
+				Type:          AccountEventOrderFill,
+				Time:          time.UnixMilli(execTime),
+				Symbol:        e.Symbol,
+				Side:          side,
+				OrderID:       e.OrderID,
+				ClientOrderID: e.OrderLinkID,
+				Price:         price,
+				Quantity:      qty,
+				OrderFilled:   leavesQty == 0,
+				ReduceOnly:    reduceOnly,
+				RealizedPnL:   pnl,
+				Fee:           fee,
+				CloseReason:   bybitCloseReason(e),
+			})
+		}
+
+	case "position":
+		var positions []bybitWSPosition
+		if err := json.Unmarshal(msg.Data, &positions); err != nil {
+			return nil
+		}
+		for _, p := range positions {
+			if p.Category != "" && p.Category != "linear" {
+				continue
+			}
+
+			size, _ := strconv.ParseFloat(p.Size, 64)
+			entryPrice, _ := strconv.ParseFloat(p.EntryPrice, 64)
+
+			// Hedge mode uses positionIdx, one-way mode uses side (empty when flat)
+			side := ""
+			switch {
+			case p.PositionIdx == 1 || (p.PositionIdx == 0 && p.Side == "Buy"):
+				side = "LONG"
+			case p.PositionIdx == 2 || (p.PositionIdx == 0 && p.Side == "Sell"):
+				side = "SHORT"
+			}
+
+			events = append(events, AccountEvent{
+				Type:        AccountEventPosition,
+				Time:        time.UnixMilli(msg.CreationTime),
+				Symbol:      p.Symbol,
+				Side:        side,
+				PositionAmt: size,
+				EntryPrice:  entryPrice,
+			})
+		}
+
+	case "wallet":
+		var wallets []bybitWSWallet
+		if err := json.Unmarshal(msg.Data, &wallets); err != nil {
+			return nil
+		}
+		for _, w := range wallets {
+			for _, coin := range w.Coin {
+				balance, _ := strconv.ParseFloat(coin.WalletBalance, 64)
+				events = append(events, AccountEvent{
+					Type:    AccountEventBalance,
+					Time:    time.UnixMilli(msg.CreationTime),
+					Asset:   coin.Coin,
+					Balance: balance,
+				})
+			}
+		}
+	}
+
+	return events
+}
+
+// bybitCloseReason maps exchange-triggered executions to close reasons
+func bybitCloseReason(e bybitWSExecution) string {
+	switch e.ExecType {
+	case "BustTrade", "AdlTrade":
+		return CloseReasonLiquidation
+	}
+	switch e.StopOrderType {
+	case "StopLoss", "PartialStopLoss", "TrailingStop":
+		return CloseReasonStopLoss
+	case "TakeProfit", "PartialTakeProfit":
+		return CloseReasonTakeProfit
+	}
+	return ""
+}
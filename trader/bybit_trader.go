@@ -10,7 +10,9 @@ import (
 	"io"
 	"math"
 	"net/http"
+	"net/url"
 	"nofx/logger"
+	"sort"
 	"strconv"
 	"strings"
 	"sync"
@@ -24,6 +26,8 @@ type BybitTrader struct {
 	client    *bybit.Client
 	apiKey    string
 	secretKey string
+	testnet   bool
+	baseURL   string
 
 	// Balance cache
 	cachedBalance     map[string]interface{}
@@ -35,19 +39,20 @@ type BybitTrader struct {
 	positionsCacheTime  time.Time
 	positionsCacheMutex sync.RWMutex
 
-	// Trading pair precision cache (symbol -> qtyStep)
-	qtyStepCache      map[string]float64
-	qtyStepCacheMutex sync.RWMutex
-
 	// Cache duration (15 seconds)
 	cacheDuration time.Duration
 }
 
 // NewBybitTrader creates a Bybit trader
-func NewBybitTrader(apiKey, secretKey string) *BybitTrader {
+// testnet switches REST and websocket endpoints to the Bybit testnet
+func NewBybitTrader(apiKey, secretKey string, testnet bool) *BybitTrader {
 	const src = "Up000938"
 
-	client := bybit.NewBybitHttpClient(apiKey, secretKey, bybit.WithBaseURL(bybit.MAINNET))
+	baseURL := bybit.MAINNET
+	if testnet {
+		baseURL = bybit.TESTNET
+	}
+	client := bybit.NewBybitHttpClient(apiKey, secretKey, bybit.WithBaseURL(baseURL))
 
 	// Set HTTP transport
 	if client != nil && client.HTTPClient != nil {
@@ -66,11 +71,12 @@ func NewBybitTrader(apiKey, secretKey string) *BybitTrader {
 		client:        client,
 		apiKey:        apiKey,
 		secretKey:     secretKey,
+		testnet:       testnet,
+		baseURL:       baseURL,
 		cacheDuration: 15 * time.Second,
-		qtyStepCache:  make(map[string]float64),
 	}
 
-	logger.Infof("🔵 [Bybit] Trader initialized")
+	logger.Infof("🔵 [Bybit] Trader initialized (%s)", environmentName(testnet))
 
 	return trader
 }
@@ -150,6 +156,7 @@ func (t *BybitTrader) GetBalance() (map[string]interface{}, error) {
 		"availableBalance":      availableBalance,
 		"totalUnrealizedProfit": totalPerpUPL,
 		"balance":               totalEquity, // Compatible with other exchange formats
+		"total_equity":          totalEquity,
 	}
 
 	// Update cache
@@ -278,6 +285,11 @@ func (t *BybitTrader) GetPositions() ([]map[string]interface{}, error) {
 
 // OpenLong opens a long position
 func (t *BybitTrader) OpenLong(symbol string, quantity float64, leverage int) (map[string]interface{}, error) {
+	return t.OpenLongWithClientID(symbol, quantity, leverage, "")
+}
+
+// OpenLongWithClientID opens a long position tagged with client order ID
+func (t *BybitTrader) OpenLongWithClientID(symbol string, quantity float64, leverage int, clientOrderID string) (map[string]interface{}, error) {
 	logger.Infof("[Bybit] ===== OpenLong called: symbol=%s, qty=%.6f, leverage=%d =====", symbol, quantity, leverage)
 
 	// Set leverage first
@@ -299,7 +311,7 @@ func (t *BybitTrader) OpenLong(symbol string, quantity float64, leverage int) (m
 
 	logger.Infof("[Bybit] OpenLong placing order: %+v", params)
 
-	result, err := t.client.NewUtaBybitServiceWithParams(params).PlaceOrder(context.Background())
+	result, err := t.placeOrder(symbol, params, clientOrderID)
 	if err != nil {
 		return nil, fmt.Errorf("Bybit open long failed: %w", err)
 	}
@@ -307,11 +319,16 @@ func (t *BybitTrader) OpenLong(symbol string, quantity float64, leverage int) (m
 	// Clear cache
 	t.clearCache()
 
-	return t.parseOrderResult(result)
+	return result, nil
 }
 
 // OpenShort opens a short position
 func (t *BybitTrader) OpenShort(symbol string, quantity float64, leverage int) (map[string]interface{}, error) {
+	return t.OpenShortWithClientID(symbol, quantity, leverage, "")
+}
+
+// OpenShortWithClientID opens a short position tagged with client order ID
+func (t *BybitTrader) OpenShortWithClientID(symbol string, quantity float64, leverage int, clientOrderID string) (map[string]interface{}, error) {
 	logger.Infof("[Bybit] ===== OpenShort called: symbol=%s, qty=%.6f, leverage=%d =====", symbol, quantity, leverage)
 
 	// Set leverage first
@@ -333,7 +350,7 @@ func (t *BybitTrader) OpenShort(symbol string, quantity float64, leverage int) (
 
 	logger.Infof("[Bybit] OpenShort placing order: %+v", params)
 
-	result, err := t.client.NewUtaBybitServiceWithParams(params).PlaceOrder(context.Background())
+	result, err := t.placeOrder(symbol, params, clientOrderID)
 	if err != nil {
 		return nil, fmt.Errorf("Bybit open short failed: %w", err)
 	}
@@ -341,11 +358,16 @@ func (t *BybitTrader) OpenShort(symbol string, quantity float64, leverage int) (
 	// Clear cache
 	t.clearCache()
 
-	return t.parseOrderResult(result)
+	return result, nil
 }
 
 // CloseLong closes a long position
 func (t *BybitTrader) CloseLong(symbol string, quantity float64) (map[string]interface{}, error) {
+	return t.CloseLongWithClientID(symbol, quantity, "")
+}
+
+// CloseLongWithClientID closes a long position tagged with client order ID
+func (t *BybitTrader) CloseLongWithClientID(symbol string, quantity float64, clientOrderID string) (map[string]interface{}, error) {
 	// If quantity = 0, get current position quantity
 	if quantity == 0 {
 		positions, err := t.GetPositions()
@@ -378,7 +400,7 @@ func (t *BybitTrader) CloseLong(symbol string, quantity float64) (map[string]int
 		"reduceOnly":  true,
 	}
 
-	result, err := t.client.NewUtaBybitServiceWithParams(params).PlaceOrder(context.Background())
+	result, err := t.placeOrder(symbol, params, clientOrderID)
 	if err != nil {
 		return nil, fmt.Errorf("Bybit close long failed: %w", err)
 	}
@@ -386,11 +408,16 @@ func (t *BybitTrader) CloseLong(symbol string, quantity float64) (map[string]int
 	// Clear cache
 	t.clearCache()
 
-	return t.parseOrderResult(result)
+	return result, nil
 }
 
 // CloseShort closes a short position
 func (t *BybitTrader) CloseShort(symbol string, quantity float64) (map[string]interface{}, error) {
+	return t.CloseShortWithClientID(symbol, quantity, "")
+}
+
+// CloseShortWithClientID closes a short position tagged with client order ID
+func (t *BybitTrader) CloseShortWithClientID(symbol string, quantity float64, clientOrderID string) (map[string]interface{}, error) {
 	// If quantity = 0, get current position quantity
 	if quantity == 0 {
 		positions, err := t.GetPositions()
@@ -423,7 +450,7 @@ func (t *BybitTrader) CloseShort(symbol string, quantity float64) (map[string]in
 		"reduceOnly":  true,
 	}
 
-	result, err := t.client.NewUtaBybitServiceWithParams(params).PlaceOrder(context.Background())
+	result, err := t.placeOrder(symbol, params, clientOrderID)
 	if err != nil {
 		return nil, fmt.Errorf("Bybit close short failed: %w", err)
 	}
@@ -431,7 +458,7 @@ func (t *BybitTrader) CloseShort(symbol string, quantity float64) (map[string]in
 	// Clear cache
 	t.clearCache()
 
-	return t.parseOrderResult(result)
+	return result, nil
 }
 
 // SetLeverage sets leverage
@@ -652,86 +679,88 @@ func (t *BybitTrader) CancelStopOrders(symbol string) error {
 	return nil
 }
 
-// getQtyStep retrieves the quantity step for a trading pair
-func (t *BybitTrader) getQtyStep(symbol string) float64 {
-	// Check cache first
-	t.qtyStepCacheMutex.RLock()
-	if step, ok := t.qtyStepCache[symbol]; ok {
-		t.qtyStepCacheMutex.RUnlock()
-		return step
-	}
-	t.qtyStepCacheMutex.RUnlock()
-
-	// Call public API directly to get contract information
-	url := fmt.Sprintf("https://api.bybit.com/v5/market/instruments-info?category=linear&symbol=%s", symbol)
-	resp, err := http.Get(url)
-	if err != nil {
-		logger.Infof("⚠️ [Bybit] Failed to get precision info for %s: %v", symbol, err)
-		return 1 // Default to integer
-	}
-	defer resp.Body.Close()
-
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return 1
-	}
-
-	var result struct {
-		RetCode int `json:"retCode"`
-		Result  struct {
-			List []struct {
-				LotSizeFilter struct {
-					QtyStep string `json:"qtyStep"`
-				} `json:"lotSizeFilter"`
-			} `json:"list"`
-		} `json:"result"`
-	}
+// GetInstruments gets trading rules of all USDT perpetuals (implements InstrumentProvider)
+func (t *BybitTrader) GetInstruments() ([]Instrument, error) {
+	var instruments []Instrument
+	cursor := ""
+	for page := 0; page < 10; page++ {
+		reqURL := t.baseURL + "/v5/market/instruments-info?category=linear&limit=1000"
+		if cursor != "" {
+			reqURL += "&cursor=" + url.QueryEscape(cursor)
+		}
+		resp, err := http.Get(reqURL)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get instruments: %w", err)
+		}
+		body, err := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		if err != nil {
+			return nil, err
+		}
 
-	if err := json.Unmarshal(body, &result); err != nil {
-		return 1
-	}
+		var result struct {
+			RetCode int    `json:"retCode"`
+			RetMsg  string `json:"retMsg"`
+			Result  struct {
+				List []struct {
+					Symbol         string `json:"symbol"`
+					ContractType   string `json:"contractType"`
+					Status         string `json:"status"`
+					QuoteCoin      string `json:"quoteCoin"`
+					LeverageFilter struct {
+						MaxLeverage string `json:"maxLeverage"`
+					} `json:"leverageFilter"`
+					PriceFilter struct {
+						TickSize string `json:"tickSize"`
+					} `json:"priceFilter"`
+					LotSizeFilter struct {
+						QtyStep          string `json:"qtyStep"`
+						MinOrderQty      string `json:"minOrderQty"`
+						MinNotionalValue string `json:"minNotionalValue"`
+					} `json:"lotSizeFilter"`
+				} `json:"list"`
+				NextPageCursor string `json:"nextPageCursor"`
+			} `json:"result"`
+		}
+		if err := json.Unmarshal(body, &result); err != nil {
+			return nil, fmt.Errorf("failed to parse instruments: %w", err)
+		}
+		if result.RetCode != 0 {
+			return nil, fmt.Errorf("failed to get instruments: %s", result.RetMsg)
+		}
 
-	if result.RetCode != 0 || len(result.Result.List) == 0 {
-		return 1
-	}
+		for _, item := range result.Result.List {
+			if item.ContractType != "LinearPerpetual" || item.QuoteCoin != "USDT" || item.Status != "Trading" {
+				continue
+			}
+			instruments = append(instruments, Instrument{
+				Symbol:             item.Symbol,
+				VenueSymbol:        item.Symbol,
+				TickSize:           parseFloatOrZero(item.PriceFilter.TickSize),
+				LotStep:            parseFloatOrZero(item.LotSizeFilter.QtyStep),
+				MinQty:             parseFloatOrZero(item.LotSizeFilter.MinOrderQty),
+				MinNotional:        parseFloatOrZero(item.LotSizeFilter.MinNotionalValue),
+				MaxLeverage:        int(parseFloatOrZero(item.LeverageFilter.MaxLeverage)),
+				ContractMultiplier: 1,
+			})
+		}
 
-	qtyStep, _ := strconv.ParseFloat(result.Result.List[0].LotSizeFilter.QtyStep, 64)
-	if qtyStep <= 0 {
-		qtyStep = 1
+		cursor = result.Result.NextPageCursor
+		if cursor == "" {
+			break
+		}
 	}
-
-	// Cache result
-	t.qtyStepCacheMutex.Lock()
-	t.qtyStepCache[symbol] = qtyStep
-	t.qtyStepCacheMutex.Unlock()
-
-	logger.Infof("🔵 [Bybit] %s qtyStep: %v", symbol, qtyStep)
-
-	return qtyStep
+	return instruments, nil
 }
 
-// FormatQuantity formats quantity
+// FormatQuantity formats quantity rounded down to the lot step of the instrument registry
 func (t *BybitTrader) FormatQuantity(symbol string, quantity float64) (string, error) {
-	// Get qtyStep for this symbol
-	qtyStep := t.getQtyStep(symbol)
-
-	// Align quantity according to qtyStep (round down to nearest step)
-	alignedQty := math.Floor(quantity/qtyStep) * qtyStep
-
-	// Calculate required decimal places
-	decimals := 0
-	if qtyStep < 1 {
-		stepStr := strconv.FormatFloat(qtyStep, 'f', -1, 64)
-		if idx := strings.Index(stepStr, "."); idx >= 0 {
-			decimals = len(stepStr) - idx - 1
-		}
+	inst, err := venueInstrument(Venue{Exchange: "bybit", Testnet: t.testnet}, t, symbol)
+	if err != nil {
+		logger.Infof("⚠️ [Bybit] %v, rounding %s quantity to an integer", err, symbol)
+		return fmt.Sprintf("%.0f", math.Floor(quantity)), nil
 	}
-
-	// Format
-	format := fmt.Sprintf("%%.%df", decimals)
-	formatted := fmt.Sprintf(format, alignedQty)
-
-	return formatted, nil
+	return inst.FormatQuantity(quantity), nil
 }
 
 // Helper methods
@@ -764,12 +793,58 @@ func (t *BybitTrader) parseOrderResult(result *bybit.ServerResponse) (map[string
 	}, nil
 }
 
+// placeOrder places an order tagged with orderLinkId, resolving ambiguous failures by client order ID
+func (t *BybitTrader) placeOrder(symbol string, params map[string]interface{}, clientOrderID string) (map[string]interface{}, error) {
+	orderLinkID := bybitOrderLinkID(clientOrderID)
+	params["orderLinkId"] = orderLinkID
+
+	submit := func() (map[string]interface{}, error) {
+		result, err := t.client.NewUtaBybitServiceWithParams(params).PlaceOrder(context.Background())
+		if err != nil {
+			return nil, err
+		}
+		order, err := t.parseOrderResult(result)
+		if err != nil {
+			return nil, err
+		}
+		order["clientOrderId"] = orderLinkID
+		return order, nil
+	}
+	lookup := func() (map[string]interface{}, error) {
+		return t.GetOrderByClientID(symbol, orderLinkID)
+	}
+
+	return submitWithClientOrderID(orderLinkID, submit, lookup)
+}
+
+// bybitOrderLinkID converts a client order ID to Bybit orderLinkId (max 36 characters)
+// Empty ID falls back to a random ID
+func bybitOrderLinkID(clientOrderID string) string {
+	if clientOrderID == "" {
+		return fmt.Sprintf("nofx%d%05d", time.Now().UnixNano()%10000000000000, time.Now().Nanosecond()%100000)
+	}
+	if len(clientOrderID) > 36 {
+		return clientOrderID[:36]
+	}
+	return clientOrderID
+}
+
 // GetOrderStatus retrieves order status
 func (t *BybitTrader) GetOrderStatus(symbol string, orderID string) (map[string]interface{}, error) {
+	return t.queryOrder(symbol, "orderId", orderID)
+}
+
+// GetOrderByClientID retrieves order status by client order ID (orderLinkId)
+func (t *BybitTrader) GetOrderByClientID(symbol string, clientOrderID string) (map[string]interface{}, error) {
+	return t.queryOrder(symbol, "orderLinkId", bybitOrderLinkID(clientOrderID))
+}
+
+// queryOrder queries order history by orderId or orderLinkId
+func (t *BybitTrader) queryOrder(symbol string, idField string, id string) (map[string]interface{}, error) {
 	params := map[string]interface{}{
 		"category": "linear",
 		"symbol":   symbol,
-		"orderId":  orderID,
+		idField:    id,
 	}
 
 	result, err := t.client.NewUtaBybitServiceWithParams(params).GetOrderHistory(context.Background())
@@ -788,12 +863,14 @@ func (t *BybitTrader) GetOrderStatus(symbol string, orderID string) (map[string]
 
 	list, _ := resultData["list"].([]interface{})
 	if len(list) == 0 {
-		return nil, fmt.Errorf("order %s not found", orderID)
+		return nil, fmt.Errorf("order %s not found", id)
 	}
 
 	order, _ := list[0].(map[string]interface{})
 
 	// Parse order data
+	orderID, _ := order["orderId"].(string)
+	orderLinkID, _ := order["orderLinkId"].(string)
 	status, _ := order["orderStatus"].(string)
 	avgPriceStr, _ := order["avgPrice"].(string)
 	cumExecQtyStr, _ := order["cumExecQty"].(string)
@@ -817,11 +894,12 @@ func (t *BybitTrader) GetOrderStatus(symbol string, orderID string) (map[string]
 	}
 
 	return map[string]interface{}{
-		"orderId":     orderID,
-		"status":      unifiedStatus,
-		"avgPrice":    avgPrice,
-		"executedQty": executedQty,
-		"commission":  commission,
+		"orderId":       orderID,
+		"clientOrderId": orderLinkID,
+		"status":        unifiedStatus,
+		"avgPrice":      avgPrice,
+		"executedQty":   executedQty,
+		"commission":    commission,
 	}, nil
 }
 
@@ -891,7 +969,7 @@ func (t *BybitTrader) GetClosedPnL(startTime time.Time, limit int) ([]ClosedPnLR
 func (t *BybitTrader) getClosedPnLViaHTTP(startTime time.Time, limit int) ([]ClosedPnLRecord, error) {
 	// Build query string
 	queryParams := fmt.Sprintf("category=linear&startTime=%d&limit=%d", startTime.UnixMilli(), limit)
-	url := "https://api.bybit.com/v5/position/closed-pnl?" + queryParams
+	url := t.baseURL + "/v5/position/closed-pnl?" + queryParams
 
 	// Generate timestamp
 	timestamp := fmt.Sprintf("%d", time.Now().UnixMilli())
@@ -948,6 +1026,226 @@ func (t *BybitTrader) getClosedPnLViaHTTP(startTime time.Time, limit int) ([]Clo
 	return t.parseClosedPnLResult(result.Result)
 }
 
+// GetFundingHistory retrieves funding payments from the transaction log (type=SETTLEMENT)
+// Bybit limits each query to a 7-day window, so the range is walked window by window
+func (t *BybitTrader) GetFundingHistory(startTime time.Time) ([]FundingRecord, error) {
+	const window = 7 * 24 * time.Hour
+
+	var records []FundingRecord
+	now := time.Now()
+	for windowStart := startTime; windowStart.Before(now); windowStart = windowStart.Add(window) {
+		windowEnd := windowStart.Add(window)
+		if windowEnd.After(now) {
+			windowEnd = now
+		}
+
+		cursor := ""
+		for {
+			query := fmt.Sprintf("accountType=UNIFIED&category=linear&type=SETTLEMENT&startTime=%d&endTime=%d&limit=50",
+				windowStart.UnixMilli(), windowEnd.UnixMilli())
+			if cursor != "" {
+				query += "&cursor=" + url.QueryEscape(cursor)
+			}
+
+			data, err := t.signedGet("/v5/account/transaction-log", query)
+			if err != nil {
+				return nil, fmt.Errorf("failed to get funding history: %w", err)
+			}
+
+			var result struct {
+				List []struct {
+					ID              string `json:"id"`
+					Symbol          string `json:"symbol"`
+					Funding         string `json:"funding"`
+					FeeRate         string `json:"feeRate"`
+					TransactionTime string `json:"transactionTime"`
+				} `json:"list"`
+				NextPageCursor string `json:"nextPageCursor"`
+			}
+			if err := json.Unmarshal(data, &result); err != nil {
+				return nil, fmt.Errorf("failed to parse funding history: %w", err)
+			}
+
+			for _, item := range result.List {
+				funding, _ := strconv.ParseFloat(item.Funding, 64)
+				rate, _ := strconv.ParseFloat(item.FeeRate, 64)
+				txTime, _ := strconv.ParseInt(item.TransactionTime, 10, 64)
+				records = append(records, FundingRecord{
+					Symbol: item.Symbol,
+					Amount: -funding, // Bybit reports funding as fee: positive = paid
+					Rate:   rate,
+					Time:   time.UnixMilli(txTime),
+					TxID:   item.ID,
+				})
+			}
+
+			if result.NextPageCursor == "" || len(result.List) == 0 {
+				break
+			}
+			cursor = result.NextPageCursor
+		}
+	}
+
+	// Transaction log is returned newest first
+	sort.Slice(records, func(i, j int) bool { return records[i].Time.Before(records[j].Time) })
+	return records, nil
+}
+
+// GetTrades retrieves trade history (executions) from /v5/execution/list
+// Bybit limits each query to a 7-day window, so the range is walked window by window
+// A fill that flips the position is split into its closing and opening parts
+func (t *BybitTrader) GetTrades(startTime time.Time, limit int) ([]TradeRecord, error) {
+	const window = 7 * 24 * time.Hour
+	if limit <= 0 {
+		limit = 1000
+	}
+
+	var trades []TradeRecord
+	now := time.Now()
+	for windowStart := startTime; windowStart.Before(now) && len(trades) < limit; windowStart = windowStart.Add(window) {
+		windowEnd := windowStart.Add(window)
+		if windowEnd.After(now) {
+			windowEnd = now
+		}
+
+		cursor := ""
+		for {
+			query := fmt.Sprintf("category=linear&startTime=%d&endTime=%d&limit=100",
+				windowStart.UnixMilli(), windowEnd.UnixMilli())
+			if cursor != "" {
+				query += "&cursor=" + url.QueryEscape(cursor)
+			}
+
+			data, err := t.signedGet("/v5/execution/list", query)
+			if err != nil {
+				return nil, fmt.Errorf("failed to get trade history: %w", err)
+			}
+
+			var result struct {
+				List []struct {
+					ExecID     string `json:"execId"`
+					Symbol     string `json:"symbol"`
+					Side       string `json:"side"`
+					ExecPrice  string `json:"execPrice"`
+					ExecQty    string `json:"execQty"`
+					ExecFee    string `json:"execFee"`
+					ExecPnl    string `json:"execPnl"`
+					ExecType   string `json:"execType"`
+					ClosedSize string `json:"closedSize"`
+					ExecTime   string `json:"execTime"`
+				} `json:"list"`
+				NextPageCursor string `json:"nextPageCursor"`
+			}
+			if err := json.Unmarshal(data, &result); err != nil {
+				return nil, fmt.Errorf("failed to parse trade history: %w", err)
+			}
+
+			for _, exec := range result.List {
+				if exec.ExecType == "Funding" {
+					continue // Funding settlements are not fills
+				}
+				price, _ := strconv.ParseFloat(exec.ExecPrice, 64)
+				qty, _ := strconv.ParseFloat(exec.ExecQty, 64)
+				fee, _ := strconv.ParseFloat(exec.ExecFee, 64)
+				pnl, _ := strconv.ParseFloat(exec.ExecPnl, 64)
+				closedSize, _ := strconv.ParseFloat(exec.ClosedSize, 64)
+				execTime, _ := strconv.ParseInt(exec.ExecTime, 10, 64)
+				if qty <= 0 {
+					continue
+				}
+
+				side := strings.ToUpper(exec.Side)
+				trade := TradeRecord{
+					TradeID: exec.ExecID,
+					Symbol:  exec.Symbol,
+					Side:    side,
+					Price:   price,
+					Time:    time.UnixMilli(execTime),
+				}
+
+				// Closing part belongs to the side being reduced: selling closes LONG, buying closes SHORT
+				if closedSize > 0 {
+					closing := trade
+					closing.PositionSide = "LONG"
+					if side == "BUY" {
+						closing.PositionSide = "SHORT"
+					}
+					closing.Quantity = math.Min(closedSize, qty)
+					closing.RealizedPnL = pnl
+					closing.Fee = fee * closing.Quantity / qty
+					trades = append(trades, closing)
+				}
+
+				if opened := qty - closedSize; opened > 0 {
+					opening := trade
+					if closedSize > 0 {
+						opening.TradeID = exec.ExecID + "-open"
+					}
+					opening.PositionSide = "SHORT"
+					if side == "BUY" {
+						opening.PositionSide = "LONG"
+					}
+					opening.Quantity = opened
+					opening.Fee = fee * opened / qty
+					trades = append(trades, opening)
+				}
+			}
+
+			if result.NextPageCursor == "" || len(result.List) == 0 {
+				break
+			}
+			cursor = result.NextPageCursor
+		}
+	}
+
+	// Executions are returned newest first
+	return sortAndLimitTrades(trades, limit), nil
+}
+
+// signedGet makes a signed GET request to Bybit V5 API and returns the result field
+func (t *BybitTrader) signedGet(path, queryParams string) (json.RawMessage, error) {
+	timestamp := fmt.Sprintf("%d", time.Now().UnixMilli())
+	recvWindow := "5000"
+
+	h := hmac.New(sha256.New, []byte(t.secretKey))
+	h.Write([]byte(timestamp + t.apiKey + recvWindow + queryParams))
+	signature := hex.EncodeToString(h.Sum(nil))
+
+	req, err := http.NewRequest("GET", t.baseURL+path+"?"+queryParams, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("X-BAPI-API-KEY", t.apiKey)
+	req.Header.Set("X-BAPI-SIGN", signature)
+	req.Header.Set("X-BAPI-SIGN-TYPE", "2")
+	req.Header.Set("X-BAPI-TIMESTAMP", timestamp)
+	req.Header.Set("X-BAPI-RECV-WINDOW", recvWindow)
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to call Bybit API: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response: %w", err)
+	}
+
+	var result struct {
+		RetCode int             `json:"retCode"`
+		RetMsg  string          `json:"retMsg"`
+		Result  json.RawMessage `json:"result"`
+	}
+	if err := json.Unmarshal(body, &result); err != nil {
+		return nil, fmt.Errorf("failed to parse response: %w", err)
+	}
+	if result.RetCode != 0 {
+		return nil, fmt.Errorf("Bybit API error: %s", result.RetMsg)
+	}
+	return result.Result, nil
+}
+
 // parseClosedPnLResult parses the closed PnL result from Bybit API
 func (t *BybitTrader) parseClosedPnLResult(resultData interface{}) ([]ClosedPnLRecord, error) {
 	data, ok := resultData.(map[string]interface{})
@@ -2,13 +2,16 @@ package trader
 
 import (
 	"encoding/json"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"strconv"
 	"strings"
 	"testing"
 	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 // ============================================================
@@ -66,7 +69,7 @@ func NewBybitTraderTestSuite(t *testing.T) *BybitTraderTestSuite {
 	}))
 
 	// Create real Bybit trader (for interface compliance testing)
-	trader := NewBybitTrader("test_api_key", "test_secret_key")
+	trader := NewBybitTrader("test_api_key", "test_secret_key", false)
 
 	// Create base suite
 	baseSuite := NewTraderTestSuite(t, trader)
@@ -128,7 +131,7 @@ func TestNewBybitTrader(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			trader := NewBybitTrader(tt.apiKey, tt.secretKey)
+			trader := NewBybitTrader(tt.apiKey, tt.secretKey, false)
 
 			if tt.wantNil {
 				assert.Nil(t, trader)
@@ -176,7 +179,7 @@ func TestBybitTrader_SymbolFormat(t *testing.T) {
 
 // TestBybitTrader_FormatQuantity Test quantity formatting
 func TestBybitTrader_FormatQuantity(t *testing.T) {
-	trader := NewBybitTrader("test", "test")
+	trader := NewBybitTrader("test", "test", false)
 
 	tests := []struct {
 		name     string
@@ -335,7 +338,7 @@ func convertBybitSide(side string) string {
 // TestBybitTrader_CategoryLinear Test using only linear category
 func TestBybitTrader_CategoryLinear(t *testing.T) {
 	// Bybit trader should only use linear category (USDT perpetual contracts)
-	trader := NewBybitTrader("test", "test")
+	trader := NewBybitTrader("test", "test", false)
 	assert.NotNil(t, trader)
 
 	// Verify default configuration
@@ -344,12 +347,23 @@ func TestBybitTrader_CategoryLinear(t *testing.T) {
 
 // TestBybitTrader_CacheDuration Test cache duration
 func TestBybitTrader_CacheDuration(t *testing.T) {
-	trader := NewBybitTrader("test", "test")
+	trader := NewBybitTrader("test", "test", false)
 
 	// Verify default cache time is 15 seconds
 	assert.Equal(t, 15*time.Second, trader.cacheDuration)
 }
 
+// TestBybitTrader_Testnet Test testnet switches REST base URL
+func TestBybitTrader_Testnet(t *testing.T) {
+	mainnet := NewBybitTrader("test", "test", false)
+	assert.Equal(t, "https://api.bybit.com", mainnet.baseURL)
+	assert.False(t, mainnet.testnet)
+
+	testnet := NewBybitTrader("test", "test", true)
+	assert.Equal(t, "https://api-testnet.bybit.com", testnet.baseURL)
+	assert.True(t, testnet.testnet)
+}
+
 // ============================================================
 // Part 4: Mock server integration tests
 // ============================================================
@@ -467,3 +481,79 @@ func TestBybitTrader_MockServerSetLeverage(t *testing.T) {
 
 	assert.NotNil(t, mockServer)
 }
+
+// TestBybitTrader_GetTrades Test execution history parsing, flip splitting and window walking
+func TestBybitTrader_GetTrades(t *testing.T) {
+	now := time.Now()
+	requests := 0
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v5/execution/list" {
+			http.NotFound(w, r)
+			return
+		}
+		requests++
+		assert.Equal(t, "linear", r.URL.Query().Get("category"))
+
+		var list []map[string]interface{}
+		windowStart, _ := strconv.ParseInt(r.URL.Query().Get("startTime"), 10, 64)
+		if now.Sub(time.UnixMilli(windowStart)) < 7*24*time.Hour {
+			// Latest window, newest first
+			list = []map[string]interface{}{
+				{"execId": "e3", "symbol": "BTCUSDT", "side": "Buy", "execPrice": "49000", "execQty": "0.3",
+					"execFee": "3", "execPnl": "200", "execType": "Trade", "closedSize": "0.1",
+					"execTime": fmt.Sprintf("%d", now.Add(-time.Hour).UnixMilli())},
+				{"execId": "f1", "symbol": "BTCUSDT", "side": "Sell", "execQty": "0", "execType": "Funding",
+					"execTime": fmt.Sprintf("%d", now.Add(-2*time.Hour).UnixMilli())},
+				{"execId": "e2", "symbol": "BTCUSDT", "side": "Sell", "execPrice": "51000", "execQty": "0.1",
+					"execFee": "1", "execPnl": "0", "execType": "Trade", "closedSize": "0",
+					"execTime": fmt.Sprintf("%d", now.Add(-3*time.Hour).UnixMilli())},
+			}
+		} else {
+			list = []map[string]interface{}{
+				{"execId": "e1", "symbol": "ETHUSDT", "side": "Buy", "execPrice": "3000", "execQty": "1",
+					"execFee": "0.5", "execPnl": "0", "execType": "Trade", "closedSize": "0",
+					"execTime": fmt.Sprintf("%d", now.Add(-8*24*time.Hour).UnixMilli())},
+			}
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"retCode": 0,
+			"retMsg":  "OK",
+			"result":  map[string]interface{}{"list": list, "nextPageCursor": ""},
+		})
+	}))
+	defer mockServer.Close()
+
+	trader := NewBybitTrader("test", "test", false)
+	trader.baseURL = mockServer.URL
+
+	trades, err := trader.GetTrades(now.Add(-10*24*time.Hour), 0)
+	require.NoError(t, err)
+	assert.Equal(t, 2, requests)
+	require.Len(t, trades, 4)
+
+	// Oldest first
+	assert.Equal(t, "e1", trades[0].TradeID)
+	assert.Equal(t, "LONG", trades[0].PositionSide)
+	assert.Equal(t, "e2", trades[1].TradeID)
+	assert.Equal(t, "SHORT", trades[1].PositionSide)
+	assert.Equal(t, "SELL", trades[1].Side)
+
+	// Flip: closes 0.1 SHORT with the PnL, opens 0.2 LONG
+	assert.Equal(t, "e3", trades[2].TradeID)
+	assert.Equal(t, "SHORT", trades[2].PositionSide)
+	assert.InDelta(t, 0.1, trades[2].Quantity, 1e-9)
+	assert.InDelta(t, 200.0, trades[2].RealizedPnL, 1e-9)
+	assert.InDelta(t, 1.0, trades[2].Fee, 1e-9)
+	assert.Equal(t, "e3-open", trades[3].TradeID)
+	assert.Equal(t, "LONG", trades[3].PositionSide)
+	assert.InDelta(t, 0.2, trades[3].Quantity, 1e-9)
+	assert.InDelta(t, 0.0, trades[3].RealizedPnL, 1e-9)
+
+	// Limit keeps the oldest fills
+	limited, err := trader.GetTrades(now.Add(-10*24*time.Hour), 1)
+	require.NoError(t, err)
+	require.Len(t, limited, 1)
+	assert.Equal(t, "e1", limited[0].TradeID)
+}
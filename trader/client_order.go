@@ -0,0 +1,122 @@
+package trader
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"nofx/logger"
+	"strings"
+	"time"
+)
+
+// ClientOrderTrader is implemented by exchanges that accept a caller-supplied client order ID.
+// AutoTrader uses it to tag every order with a deterministic ID, so an order whose
+// submission outcome is unknown (timeout, dropped connection) can be looked up instead
+// of being blindly resubmitted.
+type ClientOrderTrader interface {
+	Trader
+
+	// OpenLongWithClientID Open long position tagged with client order ID
+	OpenLongWithClientID(symbol string, quantity float64, leverage int, clientOrderID string) (map[string]interface{}, error)
+
+	// OpenShortWithClientID Open short position tagged with client order ID
+	OpenShortWithClientID(symbol string, quantity float64, leverage int, clientOrderID string) (map[string]interface{}, error)
+
+	// CloseLongWithClientID Close long position tagged with client order ID (quantity=0 means close all)
+	CloseLongWithClientID(symbol string, quantity float64, clientOrderID string) (map[string]interface{}, error)
+
+	// CloseShortWithClientID Close short position tagged with client order ID (quantity=0 means close all)
+	CloseShortWithClientID(symbol string, quantity float64, clientOrderID string) (map[string]interface{}, error)
+
+	// GetOrderByClientID Get order by client order ID
+	// Returns the same fields as GetOrderStatus, plus "clientOrderId"
+	GetOrderByClientID(symbol string, clientOrderID string) (map[string]interface{}, error)
+}
+
+// clientOrderLookupAttempts / clientOrderLookupDelay control how long an adapter
+// waits for an ambiguous order to show up before resubmitting it
+var (
+	clientOrderLookupAttempts = 3
+	clientOrderLookupDelay    = 1 * time.Second
+)
+
+// GenerateClientOrderID builds a deterministic client order ID from trader ID, cycle, symbol and action
+// The same inputs always produce the same ID, so a retried order in the same cycle is recognised by the exchange.
+// Returns 32 lowercase hex characters; adapters adapt it to venue-specific formats.
+func GenerateClientOrderID(traderID string, cycle int, symbol, action string) string {
+	sum := sha256.Sum256([]byte(fmt.Sprintf("%s|%d|%s|%s", traderID, cycle, strings.ToUpper(symbol), action)))
+	return hex.EncodeToString(sum[:])[:32]
+}
+
+// isAmbiguousOrderError reports whether an order submission error leaves the order state unknown
+// (request may have reached the exchange), as opposed to a definite rejection
+func isAmbiguousOrderError(err error) bool {
+	if err == nil {
+		return false
+	}
+	if errors.Is(err, context.DeadlineExceeded) || errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+		return true
+	}
+	var netErr net.Error
+	if errors.As(err, &netErr) && netErr.Timeout() {
+		return true
+	}
+
+	msg := strings.ToLower(err.Error())
+	for _, pattern := range []string{
+		"timeout",
+		"timed out",
+		"connection reset",
+		"broken pipe",
+		"eof",
+		"status unknown", // Binance -1007: "Send status unknown; execution status unknown"
+		"502 bad gateway",
+		"503 service unavailable",
+		"504 gateway timeout",
+	} {
+		if strings.Contains(msg, pattern) {
+			return true
+		}
+	}
+	return false
+}
+
+// submitWithClientOrderID submits an order and resolves ambiguous failures by client order ID
+// On an ambiguous error the order is looked up first; it is only resubmitted when the
+// exchange definitely doesn't know it. If the lookup itself is ambiguous, the original
+// error is returned rather than risking a duplicate position.
+func submitWithClientOrderID(clientOrderID string, submit func() (map[string]interface{}, error), lookup func() (map[string]interface{}, error)) (map[string]interface{}, error) {
+	result, err := submit()
+	if err == nil || clientOrderID == "" || !isAmbiguousOrderError(err) {
+		return result, err
+	}
+
+	logger.Infof("  ⚠️ Order %s submission outcome unknown (%v), querying by client order ID", clientOrderID, err)
+
+	var lookupErr error
+	for i := 0; i < clientOrderLookupAttempts; i++ {
+		time.Sleep(clientOrderLookupDelay)
+
+		var existing map[string]interface{}
+		existing, lookupErr = lookup()
+		if lookupErr == nil && existing != nil {
+			if status, _ := existing["status"].(string); status == "CANCELED" || status == "REJECTED" || status == "EXPIRED" {
+				return nil, fmt.Errorf("order %s reached exchange but ended %s: %w", clientOrderID, status, err)
+			}
+			logger.Infof("  ✓ Order %s found on exchange, skipping resubmission", clientOrderID)
+			existing["clientOrderId"] = clientOrderID
+			return existing, nil
+		}
+	}
+
+	if lookupErr != nil && isAmbiguousOrderError(lookupErr) {
+		return nil, fmt.Errorf("order %s state unknown (lookup failed: %v): %w", clientOrderID, lookupErr, err)
+	}
+
+	logger.Infof("  🔁 Order %s not found on exchange, resubmitting", clientOrderID)
+	return submit()
+}
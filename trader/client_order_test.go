@@ -0,0 +1,172 @@
+package trader
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"nofx/store"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestClientOrderTrader_InterfaceCompliance tests which exchanges support client order IDs
+func TestClientOrderTrader_InterfaceCompliance(t *testing.T) {
+	var _ ClientOrderTrader = (*FuturesTrader)(nil)
+	var _ ClientOrderTrader = (*BybitTrader)(nil)
+	var _ ClientOrderTrader = (*OKXTrader)(nil)
+	var _ ClientOrderTrader = (*BitgetTrader)(nil)
+	var _ ClientOrderTrader = (*HyperliquidTrader)(nil)
+}
+
+// TestGenerateClientOrderID tests deterministic client order ID generation
+func TestGenerateClientOrderID(t *testing.T) {
+	id := GenerateClientOrderID("trader-1", 42, "BTCUSDT", "open_long")
+
+	assert.Len(t, id, 32, "client order ID should be 32 hex characters")
+	assert.Equal(t, id, GenerateClientOrderID("trader-1", 42, "btcusdt", "open_long"), "same inputs should produce same ID")
+
+	others := []string{
+		GenerateClientOrderID("trader-2", 42, "BTCUSDT", "open_long"),
+		GenerateClientOrderID("trader-1", 43, "BTCUSDT", "open_long"),
+		GenerateClientOrderID("trader-1", 42, "ETHUSDT", "open_long"),
+		GenerateClientOrderID("trader-1", 42, "BTCUSDT", "close_long"),
+	}
+	for _, other := range others {
+		assert.NotEqual(t, id, other, "different inputs should produce different IDs")
+	}
+}
+
+// TestAutoTraderClientOrderID tests client order IDs are stable within a cycle run but not reused by a rerun of the cycle number
+func TestAutoTraderClientOrderID(t *testing.T) {
+	started := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+	at := &AutoTrader{id: "trader-1", cycleNumber: 41, lastCycleTime: started}
+
+	id := at.clientOrderID(&store.DecisionAction{Symbol: "BTCUSDT", Action: "open_long"})
+	assert.Equal(t, id, at.clientOrderID(&store.DecisionAction{Symbol: "BTCUSDT", Action: "open_long"}), "retry in the same run")
+
+	// The process crashed before the decision was saved: cycle 42 runs again
+	at.lastCycleTime = started.Add(3 * time.Minute)
+	assert.NotEqual(t, id, at.clientOrderID(&store.DecisionAction{Symbol: "BTCUSDT", Action: "open_long"}))
+}
+
+// TestClientOrderID_VenueFormats tests conversion to exchange-specific client order ID formats
+func TestClientOrderID_VenueFormats(t *testing.T) {
+	id := GenerateClientOrderID("trader-1", 1, "BTCUSDT", "open_long")
+
+	binanceID := binanceClientOrderID(id)
+	assert.True(t, strings.HasPrefix(binanceID, "x-KzrpZaP9"))
+	assert.LessOrEqual(t, len(binanceID), 32)
+	assert.Equal(t, binanceID, binanceClientOrderID(binanceID), "conversion should be idempotent")
+
+	okxID := okxClOrdID(id)
+	assert.True(t, strings.HasPrefix(okxID, okxTag))
+	assert.LessOrEqual(t, len(okxID), 32)
+
+	assert.LessOrEqual(t, len(bybitOrderLinkID(id)), 36)
+	assert.True(t, strings.HasPrefix(bitgetClientOid(id), "nofx"))
+
+	cloid := hyperliquidCloid(id)
+	assert.Equal(t, "0x"+id, cloid)
+	assert.Equal(t, cloid, hyperliquidCloid(cloid))
+	assert.Empty(t, hyperliquidCloid(""))
+}
+
+// TestIsAmbiguousOrderError tests classification of order submission errors
+func TestIsAmbiguousOrderError(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{"nil", nil, false},
+		{"timeout", errors.New("Post \"https://fapi.binance.com/fapi/v1/order\": context deadline exceeded (Client.Timeout exceeded)"), true},
+		{"connection reset", errors.New("read tcp: connection reset by peer"), true},
+		{"binance unknown status", errors.New("<APIError> code=-1007, msg=Timeout waiting for response from backend server. Send status unknown; execution status unknown."), true},
+		{"insufficient margin", errors.New("<APIError> code=-2019, msg=Margin is insufficient."), false},
+		{"invalid quantity", errors.New("order quantity too small"), false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, isAmbiguousOrderError(tt.err))
+		})
+	}
+}
+
+// TestSubmitWithClientOrderID tests ambiguous failure handling
+func TestSubmitWithClientOrderID(t *testing.T) {
+	origDelay := clientOrderLookupDelay
+	clientOrderLookupDelay = time.Millisecond
+	defer func() { clientOrderLookupDelay = origDelay }()
+
+	timeoutErr := errors.New("request timeout")
+
+	t.Run("found on exchange is not resubmitted", func(t *testing.T) {
+		submits := 0
+		submit := func() (map[string]interface{}, error) {
+			submits++
+			return nil, timeoutErr
+		}
+		lookup := func() (map[string]interface{}, error) {
+			return map[string]interface{}{"orderId": "123", "status": "FILLED"}, nil
+		}
+
+		result, err := submitWithClientOrderID("cid-1", submit, lookup)
+		assert.NoError(t, err)
+		assert.Equal(t, 1, submits)
+		assert.Equal(t, "123", result["orderId"])
+		assert.Equal(t, "cid-1", result["clientOrderId"])
+	})
+
+	t.Run("not found is resubmitted once", func(t *testing.T) {
+		submits := 0
+		submit := func() (map[string]interface{}, error) {
+			submits++
+			if submits == 1 {
+				return nil, timeoutErr
+			}
+			return map[string]interface{}{"orderId": "456"}, nil
+		}
+		lookup := func() (map[string]interface{}, error) {
+			return nil, errors.New("order not exist")
+		}
+
+		result, err := submitWithClientOrderID("cid-2", submit, lookup)
+		assert.NoError(t, err)
+		assert.Equal(t, 2, submits)
+		assert.Equal(t, "456", result["orderId"])
+	})
+
+	t.Run("ambiguous lookup is not resubmitted", func(t *testing.T) {
+		submits := 0
+		submit := func() (map[string]interface{}, error) {
+			submits++
+			return nil, timeoutErr
+		}
+		lookup := func() (map[string]interface{}, error) {
+			return nil, errors.New("lookup timeout")
+		}
+
+		_, err := submitWithClientOrderID("cid-3", submit, lookup)
+		assert.Error(t, err)
+		assert.ErrorIs(t, err, timeoutErr)
+		assert.Equal(t, 1, submits)
+	})
+
+	t.Run("definite rejection skips lookup", func(t *testing.T) {
+		lookups := 0
+		submit := func() (map[string]interface{}, error) {
+			return nil, errors.New("insufficient balance")
+		}
+		lookup := func() (map[string]interface{}, error) {
+			lookups++
+			return nil, nil
+		}
+
+		_, err := submitWithClientOrderID("cid-4", submit, lookup)
+		assert.Error(t, err)
+		assert.Equal(t, 0, lookups)
+	})
+}
@@ -0,0 +1,542 @@
+package trader
+
+import (
+	"fmt"
+	"math"
+	"nofx/decision"
+	"nofx/logger"
+	"nofx/store"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Follower an exchange account replicating the leader trader's orders
+type Follower struct {
+	config *store.Follower
+	trader Trader
+	mu     sync.Mutex // Serializes orders on the follower account
+}
+
+// NewFollower creates a follower from its configuration and exchange client
+func NewFollower(config *store.Follower, client Trader) *Follower {
+	return &Follower{config: config, trader: client}
+}
+
+// ID returns the follower ID
+func (f *Follower) ID() string {
+	return f.config.ID
+}
+
+// SetFollowers replaces the follower accounts replicating this trader's orders
+func (at *AutoTrader) SetFollowers(followers []*Follower) {
+	at.followersMu.Lock()
+	defer at.followersMu.Unlock()
+	at.followers = followers
+	if len(followers) > 0 {
+		logger.Infof("👥 [%s] %d follower account(s) will replicate orders", at.name, len(followers))
+	}
+}
+
+// activeFollowers returns the enabled followers
+func (at *AutoTrader) activeFollowers() []*Follower {
+	at.followersMu.RLock()
+	defer at.followersMu.RUnlock()
+	var active []*Follower
+	for _, f := range at.followers {
+		if f.config.Enabled {
+			active = append(active, f)
+		}
+	}
+	return active
+}
+
+// replicateToFollowers replays an executed leader decision on every follower account (in parallel)
+// Followers never block or fail the leader, results are recorded per follower
+func (at *AutoTrader) replicateToFollowers(d *decision.Decision, actionRecord *store.DecisionAction) {
+	followers := at.activeFollowers()
+	if len(followers) == 0 {
+		return
+	}
+
+	leaderEquity := 0.0
+	if d.Action == "open_long" || d.Action == "open_short" {
+		balance, err := at.trader.GetBalance()
+		if err == nil {
+			leaderEquity, err = balanceEquity(balance)
+		}
+		if err != nil {
+			logger.Errorf("  👥 Leader equity unknown, equity-ratio followers skip %s %s: %v", d.Action, d.Symbol, err)
+		}
+	}
+
+	var wg sync.WaitGroup
+	for _, f := range followers {
+		wg.Add(1)
+		go func(f *Follower) {
+			defer wg.Done()
+			execution := at.replicateOrder(f, d, actionRecord, leaderEquity)
+			if execution == nil {
+				return
+			}
+			if execution.Success {
+				logger.Infof("  👥 Follower %s: %s %s %.6f ✓", f.config.Name, execution.Action, execution.Symbol, execution.Quantity)
+			} else {
+				logger.Infof("  👥 Follower %s: %s %s failed: %s", f.config.Name, execution.Action, execution.Symbol, execution.Error)
+			}
+			at.saveFollowerExecution(execution)
+		}(f)
+	}
+	wg.Wait()
+}
+
+// replicateOrder replays one leader decision on a follower account
+// Returns nil when there is nothing to do (e.g. closing a position the follower doesn't have)
+func (at *AutoTrader) replicateOrder(f *Follower, d *decision.Decision, actionRecord *store.DecisionAction, leaderEquity float64) *store.FollowerExecution {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	execution := &store.FollowerExecution{
+		FollowerID:     f.config.ID,
+		LeaderTraderID: at.id,
+		CycleNumber:    at.cycleNumber + 1,
+		Symbol:         d.Symbol,
+		Action:         d.Action,
+		LeaderQuantity: actionRecord.Quantity,
+		Price:          actionRecord.Price,
+		CreatedAt:      time.Now(),
+	}
+	clientOrderID := GenerateClientOrderID(f.config.ID, at.cycleNumber+1, d.Symbol, actionRecord.ClientOrderID)
+
+	positions, err := f.trader.GetPositions()
+	if err != nil {
+		execution.Error = fmt.Sprintf("failed to get positions: %v", err)
+		return execution
+	}
+
+	var order map[string]interface{}
+	switch d.Action {
+	case "open_long", "open_short":
+		side := strings.TrimPrefix(d.Action, "open_")
+		quantity, leverage, err := at.followerOrderSize(f, d, actionRecord, leaderEquity, positions)
+		if err != nil {
+			execution.Error = err.Error()
+			return execution
+		}
+		execution.Quantity = quantity
+
+		if err := f.trader.SetMarginMode(d.Symbol, at.config.IsCrossMargin); err != nil {
+			logger.Infof("  ⚠️ Follower %s: failed to set margin mode: %v", f.config.Name, err)
+		}
+		order, err = submitFollowerOrder(f.trader, d.Action, d.Symbol, quantity, leverage, clientOrderID)
+		if err != nil {
+			execution.Error = err.Error()
+			return execution
+		}
+		if !confirmFollowerOrder(f.trader, execution, order, clientOrderID) {
+			return execution
+		}
+		quantity = execution.FilledQuantity
+		positionSide := strings.ToUpper(side)
+		if d.StopLoss > 0 {
+			if err := f.trader.SetStopLoss(d.Symbol, positionSide, quantity, d.StopLoss); err != nil {
+				logger.Infof("  ⚠ Follower %s: failed to set stop loss: %v", f.config.Name, err)
+			}
+		}
+		if d.TakeProfit > 0 {
+			if err := f.trader.SetTakeProfit(d.Symbol, positionSide, quantity, d.TakeProfit); err != nil {
+				logger.Infof("  ⚠ Follower %s: failed to set take profit: %v", f.config.Name, err)
+			}
+		}
+
+	case "close_long", "close_short":
+		// Only close what copy-trading opened, the rest of the position belongs to the account owner
+		side := strings.TrimPrefix(d.Action, "close_")
+		held := positionQuantity(positions, d.Symbol, side)
+		quantity, closeQty := at.copiedCloseQuantity(f, d.Symbol, side, held)
+		if quantity == 0 {
+			return nil
+		}
+		execution.Quantity = quantity
+		order, err = submitFollowerOrder(f.trader, d.Action, d.Symbol, closeQty, 0, clientOrderID)
+		if err != nil {
+			execution.Error = err.Error()
+			return execution
+		}
+		confirmFollowerOrder(f.trader, execution, order, clientOrderID)
+
+	default:
+		return nil
+	}
+	return execution
+}
+
+// followerOrderSize scales the leader's order to the follower account and applies the follower's risk limits
+func (at *AutoTrader) followerOrderSize(f *Follower, d *decision.Decision, actionRecord *store.DecisionAction, leaderEquity float64, positions []map[string]interface{}) (float64, int, error) {
+	side := strings.TrimPrefix(d.Action, "open_")
+	if positionQuantity(positions, d.Symbol, side) > 0 {
+		return 0, 0, fmt.Errorf("follower already has %s %s position", d.Symbol, side)
+	}
+	if f.config.MaxPositions > 0 && openPositionCount(positions) >= f.config.MaxPositions {
+		return 0, 0, fmt.Errorf("follower max positions reached (%d)", f.config.MaxPositions)
+	}
+
+	ratio := 1.0
+	if f.config.SizingMode != store.FollowerSizingMultiplier {
+		if leaderEquity <= 0 {
+			return 0, 0, fmt.Errorf("leader equity unknown, cannot scale by equity ratio")
+		}
+		balance, err := f.trader.GetBalance()
+		if err != nil {
+			return 0, 0, fmt.Errorf("failed to get follower balance: %w", err)
+		}
+		followerEquity, err := balanceEquity(balance)
+		if err != nil {
+			logger.Errorf("  👥 Follower %s: equity unknown, replication skipped: %v", f.config.Name, err)
+			return 0, 0, fmt.Errorf("follower equity unknown: %w", err)
+		}
+		ratio = followerEquity / leaderEquity
+	}
+	quantity := scaleFollowerQuantity(actionRecord.Quantity, ratio, f.config.Multiplier)
+
+	price := actionRecord.Price
+	if price <= 0 {
+		p, err := f.trader.GetMarketPrice(d.Symbol)
+		if err != nil {
+			return 0, 0, fmt.Errorf("failed to get price: %w", err)
+		}
+		price = p
+	}
+	if f.config.MaxPositionUSD > 0 && quantity*price > f.config.MaxPositionUSD {
+		quantity = f.config.MaxPositionUSD / price
+	}
+	if quantity <= 0 {
+		return 0, 0, fmt.Errorf("scaled quantity is zero")
+	}
+
+	leverage := d.Leverage
+	if f.config.MaxLeverage > 0 && leverage > f.config.MaxLeverage {
+		leverage = f.config.MaxLeverage
+	}
+	return quantity, leverage, nil
+}
+
+// scaleFollowerQuantity scales the leader quantity by equity ratio and multiplier
+func scaleFollowerQuantity(leaderQuantity, equityRatio, multiplier float64) float64 {
+	if multiplier <= 0 {
+		multiplier = 1
+	}
+	return leaderQuantity * equityRatio * multiplier
+}
+
+// reconcileFollowers corrects follower positions that drifted from the leader
+// Only positions opened by copy-trading are touched: copied positions the leader no longer has are closed and
+// oversized ones are trimmed; missing positions are reported only, entries are never chased at a worse price
+func (at *AutoTrader) reconcileFollowers(leaderPositions []decision.PositionInfo, record *store.DecisionRecord) {
+	followers := at.activeFollowers()
+	if len(followers) == 0 {
+		return
+	}
+
+	leaderEquity := 0.0
+	balance, err := at.trader.GetBalance()
+	if err == nil {
+		leaderEquity, err = balanceEquity(balance)
+	}
+	if err != nil {
+		logger.Errorf("  👥 Leader equity unknown, followers only close copied positions the leader no longer has: %v", err)
+	}
+	leader := make(map[string]decision.PositionInfo)
+	for _, pos := range leaderPositions {
+		leader[pos.Symbol+"_"+pos.Side] = pos
+	}
+
+	for _, f := range followers {
+		if !f.config.ReconcileDrift {
+			continue
+		}
+		for _, execution := range at.reconcileFollower(f, leader, leaderEquity) {
+			at.saveFollowerExecution(execution)
+			if execution.Success {
+				record.ExecutionLog = append(record.ExecutionLog, fmt.Sprintf("👥 Follower %s: %s %s %.6f", f.config.Name, execution.Action, execution.Symbol, execution.Quantity))
+			} else {
+				record.ExecutionLog = append(record.ExecutionLog, fmt.Sprintf("❌ Follower %s: %s %s failed: %s", f.config.Name, execution.Action, execution.Symbol, execution.Error))
+			}
+		}
+	}
+}
+
+// reconcileFollower compares the copied positions of one follower account with the leader's positions and corrects the drift
+func (at *AutoTrader) reconcileFollower(f *Follower, leader map[string]decision.PositionInfo, leaderEquity float64) []*store.FollowerExecution {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	copied, err := at.copiedQuantities(f)
+	if err != nil {
+		logger.Infof("  ⚠️ Follower %s: reconcile skipped, %v", f.config.Name, err)
+		return nil
+	}
+	positions, err := f.trader.GetPositions()
+	if err != nil {
+		logger.Infof("  ⚠️ Follower %s: reconcile skipped, failed to get positions: %v", f.config.Name, err)
+		return nil
+	}
+
+	ratio := 1.0
+	if f.config.SizingMode != store.FollowerSizingMultiplier {
+		ratio = 0 // Unknown: only close orphaned positions
+		if leaderEquity > 0 {
+			balance, err := f.trader.GetBalance()
+			if err == nil {
+				var followerEquity float64
+				if followerEquity, err = balanceEquity(balance); err == nil {
+					ratio = followerEquity / leaderEquity
+				}
+			}
+			if err != nil {
+				logger.Errorf("  👥 Follower %s: equity unknown, oversized copied positions are not trimmed: %v", f.config.Name, err)
+			}
+		}
+	}
+
+	var executions []*store.FollowerExecution
+	held := make(map[string]float64)
+	for _, pos := range positions {
+		symbol, _ := pos["symbol"].(string)
+		side, _ := pos["side"].(string)
+		quantity := math.Abs(getFloatFromMap(pos, "positionAmt"))
+		if symbol == "" || quantity == 0 {
+			continue
+		}
+		key := symbol + "_" + side
+		held[key] = quantity
+
+		copiedQty := math.Min(copied[key], quantity)
+		if copiedQty <= 0 {
+			continue // Not opened by copy-trading
+		}
+		leaderPos, ok := leader[key]
+		closeQty := copiedQty
+		if ok {
+			if ratio <= 0 {
+				continue
+			}
+			target := scaleFollowerQuantity(leaderPos.Quantity, ratio, f.config.Multiplier)
+			if f.config.MaxPositionUSD > 0 && leaderPos.MarkPrice > 0 {
+				target = math.Min(target, f.config.MaxPositionUSD/leaderPos.MarkPrice)
+			}
+			if target <= 0 || copiedQty <= target*(1+f.config.DriftTolerancePct/100) {
+				continue
+			}
+			closeQty = copiedQty - target
+		}
+
+		action := "reconcile_close_" + side
+		execution := &store.FollowerExecution{
+			FollowerID:     f.config.ID,
+			LeaderTraderID: at.id,
+			CycleNumber:    at.cycleNumber,
+			Symbol:         symbol,
+			Action:         action,
+			LeaderQuantity: leaderPos.Quantity,
+			Quantity:       closeQty,
+			CreatedAt:      time.Now(),
+		}
+		orderQty := closeQty
+		if closeQty >= quantity {
+			orderQty = 0 // Whole position is copied: close all, avoids leaving dust
+		}
+		clientOrderID := GenerateClientOrderID(f.config.ID, at.cycleNumber, symbol, at.cycleAttempt(action))
+		order, err := submitFollowerOrder(f.trader, "close_"+side, symbol, orderQty, 0, clientOrderID)
+		if err != nil {
+			execution.Error = err.Error()
+		} else {
+			confirmFollowerOrder(f.trader, execution, order, clientOrderID)
+		}
+		executions = append(executions, execution)
+	}
+
+	// Copied positions closed outside nofx (stop loss, manual close): clear them so a later manual
+	// position on the same symbol is not mistaken for a copied one
+	for key, quantity := range copied {
+		if held[key] >= quantity {
+			continue
+		}
+		symbol, side := key[:strings.LastIndex(key, "_")], key[strings.LastIndex(key, "_")+1:]
+		executions = append(executions, &store.FollowerExecution{
+			FollowerID:     f.config.ID,
+			LeaderTraderID: at.id,
+			CycleNumber:    at.cycleNumber,
+			Symbol:         symbol,
+			Action:         "reconcile_clear_" + side,
+			Quantity:       quantity - held[key],
+			FilledQuantity: quantity - held[key], // Bookkeeping only, no order
+			Success:        true,
+			CreatedAt:      time.Now(),
+		})
+	}
+
+	for key, pos := range leader {
+		if held[key] == 0 {
+			logger.Infof("  👥 Follower %s: missing %s %s position of the leader (not chased)", f.config.Name, pos.Symbol, pos.Side)
+		}
+	}
+	return executions
+}
+
+// copiedQuantities net quantities copy-trading holds on the follower account, keyed "SYMBOL_side"
+func (at *AutoTrader) copiedQuantities(f *Follower) (map[string]float64, error) {
+	if at.store == nil {
+		return nil, fmt.Errorf("no store to look up copied positions")
+	}
+	copied, err := at.store.Follower().GetCopiedQuantities(at.id, f.config.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get copied positions: %w", err)
+	}
+	return copied, nil
+}
+
+// copiedCloseQuantity the part of a follower position held by copy-trading
+// Returns the quantity to close and the order quantity (0 = close all, when the whole position is copied)
+func (at *AutoTrader) copiedCloseQuantity(f *Follower, symbol, side string, held float64) (float64, float64) {
+	if held == 0 {
+		return 0, 0
+	}
+	copied, err := at.copiedQuantities(f)
+	if err != nil {
+		logger.Infof("  ⚠️ Follower %s: close skipped, %v", f.config.Name, err)
+		return 0, 0
+	}
+	quantity := math.Min(copied[symbol+"_"+side], held)
+	if quantity <= 0 || quantity < held {
+		return quantity, quantity
+	}
+	return quantity, 0
+}
+
+// saveFollowerExecution stores a follower execution result
+func (at *AutoTrader) saveFollowerExecution(execution *store.FollowerExecution) {
+	if at.store == nil {
+		return
+	}
+	if err := at.store.Follower().RecordExecution(execution); err != nil {
+		logger.Infof("  ⚠️ Failed to save follower execution: %v", err)
+	}
+}
+
+// submitFollowerOrder submits an order on a follower account, tagged with a client order ID when supported
+func submitFollowerOrder(t Trader, action, symbol string, quantity float64, leverage int, clientOrderID string) (map[string]interface{}, error) {
+	ct, tagged := t.(ClientOrderTrader)
+	switch action {
+	case "open_long":
+		if tagged {
+			return ct.OpenLongWithClientID(symbol, quantity, leverage, clientOrderID)
+		}
+		return t.OpenLong(symbol, quantity, leverage)
+	case "open_short":
+		if tagged {
+			return ct.OpenShortWithClientID(symbol, quantity, leverage, clientOrderID)
+		}
+		return t.OpenShort(symbol, quantity, leverage)
+	case "close_long":
+		if tagged {
+			return ct.CloseLongWithClientID(symbol, quantity, clientOrderID)
+		}
+		return t.CloseLong(symbol, quantity)
+	case "close_short":
+		if tagged {
+			return ct.CloseShortWithClientID(symbol, quantity, clientOrderID)
+		}
+		return t.CloseShort(symbol, quantity)
+	default:
+		return nil, fmt.Errorf("unknown action: %s", action)
+	}
+}
+
+// balanceEquity extracts account equity (wallet balance + unrealized PnL) from a GetBalance result
+// Every adapter reports it as total_equity; leader and follower are only compared on that one definition,
+// wallet or available balance would skew the ratio whenever positions are open
+func balanceEquity(balance map[string]interface{}) (float64, error) {
+	eq, ok := balance["total_equity"].(float64)
+	if !ok {
+		return 0, fmt.Errorf("balance has no total_equity")
+	}
+	if eq <= 0 {
+		return 0, fmt.Errorf("total_equity is %.2f", eq)
+	}
+	return eq, nil
+}
+
+// followerFillPollDelay wait between order status polls when confirming a follower fill
+var followerFillPollDelay = 500 * time.Millisecond
+
+// confirmFollowerOrder records the order ID and the confirmed filled quantity of a submitted follower order
+// The execution only counts as copied size once the fill is confirmed, so reconciliation never acts on size that
+// was not filled. Returns whether anything was filled.
+func confirmFollowerOrder(t Trader, execution *store.FollowerExecution, order map[string]interface{}, clientOrderID string) bool {
+	orderID := ""
+	if id, ok := order["orderId"]; ok && id != nil {
+		orderID = fmt.Sprint(id)
+	}
+	if orderID == "0" {
+		orderID = ""
+	}
+	execution.OrderID = orderID
+
+	var status map[string]interface{}
+	for i := 0; i < 5; i++ {
+		if i > 0 {
+			time.Sleep(followerFillPollDelay)
+		}
+		var err error
+		if orderID != "" {
+			status, err = t.GetOrderStatus(execution.Symbol, orderID)
+		} else if ct, ok := t.(ClientOrderTrader); ok && clientOrderID != "" {
+			status, err = ct.GetOrderByClientID(execution.Symbol, clientOrderID)
+		} else {
+			break
+		}
+		if err != nil {
+			continue
+		}
+		statusStr, _ := status["status"].(string)
+		if statusStr == "FILLED" || statusStr == "CANCELED" || statusStr == "EXPIRED" || statusStr == "REJECTED" {
+			break
+		}
+	}
+
+	filled := getFloatFromMap(status, "executedQty")
+	if filled <= 0 {
+		statusStr, _ := status["status"].(string)
+		if statusStr == "" {
+			execution.Error = "order submitted but fill not confirmed, not counted as copied"
+		} else {
+			execution.Error = fmt.Sprintf("order %s without a fill", statusStr)
+		}
+		return false
+	}
+	execution.FilledQuantity = filled
+	execution.Success = true
+	return true
+}
+
+// positionQuantity returns the absolute size of the symbol/side position (0 = no position)
+func positionQuantity(positions []map[string]interface{}, symbol, side string) float64 {
+	for _, pos := range positions {
+		if pos["symbol"] == symbol && pos["side"] == side {
+			return math.Abs(getFloatFromMap(pos, "positionAmt"))
+		}
+	}
+	return 0
+}
+
+// openPositionCount counts non-empty positions
+func openPositionCount(positions []map[string]interface{}) int {
+	count := 0
+	for _, pos := range positions {
+		if getFloatFromMap(pos, "positionAmt") != 0 {
+			count++
+		}
+	}
+	return count
+}
@@ -0,0 +1,239 @@
+package trader
+
+import (
+	"fmt"
+	"path/filepath"
+	"testing"
+
+	"nofx/decision"
+	"nofx/store"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// copyStubTrader records the orders submitted on a follower account
+type copyStubTrader struct {
+	Trader
+	equity    float64
+	positions []map[string]interface{}
+	opened    []float64 // Quantities of opened positions
+	leverage  []int
+	closed    []float64 // Quantities of closes (0 = close all)
+	filled    map[string]float64
+	unfilled  bool // Orders are canceled without a fill
+}
+
+func (s *copyStubTrader) GetBalance() (map[string]interface{}, error) {
+	return map[string]interface{}{"total_equity": s.equity}, nil
+}
+
+func (s *copyStubTrader) GetPositions() ([]map[string]interface{}, error) {
+	return s.positions, nil
+}
+
+// fill records the executed quantity of an order for GetOrderStatus
+func (s *copyStubTrader) fill(orderID int64, quantity float64) map[string]interface{} {
+	if s.filled == nil {
+		s.filled = make(map[string]float64)
+	}
+	s.filled[fmt.Sprint(orderID)] = quantity
+	return map[string]interface{}{"orderId": orderID}
+}
+
+func (s *copyStubTrader) GetOrderStatus(symbol string, orderID string) (map[string]interface{}, error) {
+	if s.unfilled {
+		return map[string]interface{}{"status": "CANCELED", "executedQty": 0.0}, nil
+	}
+	return map[string]interface{}{"status": "FILLED", "executedQty": s.filled[orderID]}, nil
+}
+
+func (s *copyStubTrader) OpenLong(symbol string, quantity float64, leverage int) (map[string]interface{}, error) {
+	s.opened = append(s.opened, quantity)
+	s.leverage = append(s.leverage, leverage)
+	return s.fill(42, quantity), nil
+}
+
+func (s *copyStubTrader) CloseLong(symbol string, quantity float64) (map[string]interface{}, error) {
+	s.closed = append(s.closed, quantity)
+	if quantity == 0 {
+		quantity = positionQuantity(s.positions, symbol, "long")
+	}
+	return s.fill(43, quantity), nil
+}
+
+func (s *copyStubTrader) CloseShort(symbol string, quantity float64) (map[string]interface{}, error) {
+	s.closed = append(s.closed, quantity)
+	if quantity == 0 {
+		quantity = positionQuantity(s.positions, symbol, "short")
+	}
+	return s.fill(44, quantity), nil
+}
+
+func (s *copyStubTrader) SetMarginMode(symbol string, isCrossMargin bool) error { return nil }
+func (s *copyStubTrader) SetStopLoss(symbol, positionSide string, quantity, stopPrice float64) error {
+	return nil
+}
+func (s *copyStubTrader) SetTakeProfit(symbol, positionSide string, quantity, takeProfitPrice float64) error {
+	return nil
+}
+
+func newCopyTestTrader(t *testing.T, leaderEquity float64, followers ...*Follower) *AutoTrader {
+	st, err := store.New(filepath.Join(t.TempDir(), "test.db"))
+	require.NoError(t, err)
+	t.Cleanup(func() { st.Close() })
+
+	at := &AutoTrader{id: "leader", name: "leader", trader: &copyStubTrader{equity: leaderEquity}, store: st}
+	at.SetFollowers(followers)
+	return at
+}
+
+// recordCopied records a successful replicated order on the follower account
+func recordCopied(t *testing.T, at *AutoTrader, followerID, symbol, action string, quantity float64) {
+	require.NoError(t, at.store.Follower().RecordExecution(&store.FollowerExecution{
+		FollowerID: followerID, LeaderTraderID: at.id, Symbol: symbol, Action: action, Quantity: quantity, FilledQuantity: quantity, Success: true,
+	}))
+}
+
+// TestReplicateToFollowers tests orders are scaled by equity ratio / multiplier and capped by follower limits
+func TestReplicateToFollowers(t *testing.T) {
+	byEquity := &copyStubTrader{equity: 500}
+	byMultiplier := &copyStubTrader{equity: 100}
+	capped := &copyStubTrader{equity: 1000}
+	disabled := &copyStubTrader{equity: 1000}
+	at := newCopyTestTrader(t, 1000,
+		NewFollower(&store.Follower{ID: "f1", Enabled: true, SizingMode: store.FollowerSizingEquityRatio, Multiplier: 1}, byEquity),
+		NewFollower(&store.Follower{ID: "f2", Enabled: true, SizingMode: store.FollowerSizingMultiplier, Multiplier: 0.25}, byMultiplier),
+		NewFollower(&store.Follower{ID: "f3", Enabled: true, SizingMode: store.FollowerSizingEquityRatio, Multiplier: 1, MaxPositionUSD: 100, MaxLeverage: 3}, capped),
+		NewFollower(&store.Follower{ID: "f4", Enabled: false}, disabled),
+	)
+
+	d := &decision.Decision{Symbol: "BTCUSDT", Action: "open_long", Leverage: 10}
+	at.replicateToFollowers(d, &store.DecisionAction{Symbol: "BTCUSDT", Action: "open_long", Quantity: 0.02, Price: 50000})
+
+	require.Len(t, byEquity.opened, 1)
+	assert.InDelta(t, 0.01, byEquity.opened[0], 1e-9)
+	assert.Equal(t, 10, byEquity.leverage[0])
+
+	require.Len(t, byMultiplier.opened, 1)
+	assert.InDelta(t, 0.005, byMultiplier.opened[0], 1e-9)
+
+	require.Len(t, capped.opened, 1)
+	assert.InDelta(t, 0.002, capped.opened[0], 1e-9) // 100 USD / 50000
+	assert.Equal(t, 3, capped.leverage[0])
+
+	assert.Empty(t, disabled.opened)
+}
+
+// TestReplicateToFollowersLimits tests follower risk limits refuse the order
+func TestReplicateToFollowersLimits(t *testing.T) {
+	full := &copyStubTrader{equity: 1000, positions: []map[string]interface{}{
+		{"symbol": "ETHUSDT", "side": "long", "positionAmt": 1.0},
+	}}
+	f := NewFollower(&store.Follower{ID: "f1", Enabled: true, SizingMode: store.FollowerSizingMultiplier, Multiplier: 1, MaxPositions: 1}, full)
+	at := newCopyTestTrader(t, 1000, f)
+
+	d := &decision.Decision{Symbol: "BTCUSDT", Action: "open_long", Leverage: 5}
+	execution := at.replicateOrder(f, d, &store.DecisionAction{Quantity: 0.01, Price: 50000}, 1000)
+	require.NotNil(t, execution)
+	assert.False(t, execution.Success)
+	assert.Contains(t, execution.Error, "max positions")
+	assert.Empty(t, full.opened)
+
+	// Closing a position the follower doesn't have is a no-op
+	d = &decision.Decision{Symbol: "BTCUSDT", Action: "close_long"}
+	assert.Nil(t, at.replicateOrder(f, d, &store.DecisionAction{}, 0))
+
+	// The follower's own position is not closed
+	d = &decision.Decision{Symbol: "ETHUSDT", Action: "close_long"}
+	assert.Nil(t, at.replicateOrder(f, d, &store.DecisionAction{}, 0))
+	assert.Empty(t, full.closed)
+
+	// Only the copied part is closed; a fully copied position is closed all
+	recordCopied(t, at, "f1", "ETHUSDT", "open_long", 0.4)
+	execution = at.replicateOrder(f, d, &store.DecisionAction{}, 0)
+	require.NotNil(t, execution)
+	assert.True(t, execution.Success)
+	assert.Equal(t, "43", execution.OrderID)
+	assert.InDelta(t, 0.4, execution.Quantity, 1e-9)
+	recordCopied(t, at, "f1", "ETHUSDT", "open_long", 0.6)
+	require.NotNil(t, at.replicateOrder(f, d, &store.DecisionAction{}, 0))
+	require.Len(t, full.closed, 2)
+	assert.InDelta(t, 0.4, full.closed[0], 1e-9)
+	assert.Equal(t, 0.0, full.closed[1])
+}
+
+// TestReconcileFollowers tests orphaned copied positions are closed, oversized ones trimmed and the account
+// owner's own positions left alone
+func TestReconcileFollowers(t *testing.T) {
+	follower := &copyStubTrader{equity: 500, positions: []map[string]interface{}{
+		{"symbol": "BTCUSDT", "side": "long", "positionAmt": 0.03},  // Target 0.01 → trim 0.02
+		{"symbol": "ETHUSDT", "side": "short", "positionAmt": -1.0}, // Leader has none → close
+		{"symbol": "SOLUSDT", "side": "long", "positionAmt": 11.0},  // Target 10, within tolerance
+		{"symbol": "XRPUSDT", "side": "long", "positionAmt": 500.0}, // Manual position → untouched
+		{"symbol": "DOGEUSDT", "side": "short", "positionAmt": -3000.0},
+	}}
+	at := newCopyTestTrader(t, 1000, NewFollower(&store.Follower{
+		ID: "f1", Name: "team", Enabled: true, SizingMode: store.FollowerSizingEquityRatio,
+		Multiplier: 1, ReconcileDrift: true, DriftTolerancePct: 20,
+	}, follower))
+	recordCopied(t, at, "f1", "BTCUSDT", "open_long", 0.03)
+	recordCopied(t, at, "f1", "ETHUSDT", "open_short", 1.0)
+	recordCopied(t, at, "f1", "SOLUSDT", "open_long", 11.0)
+	recordCopied(t, at, "f1", "DOGEUSDT", "open_short", 1000.0) // Manual add-on of 2000 on top of the copy
+	recordCopied(t, at, "f1", "ADAUSDT", "open_long", 100.0)    // Stopped out on the follower
+
+	record := &store.DecisionRecord{}
+	at.reconcileFollowers([]decision.PositionInfo{
+		{Symbol: "BTCUSDT", Side: "long", Quantity: 0.02, MarkPrice: 50000},
+		{Symbol: "SOLUSDT", Side: "long", Quantity: 20, MarkPrice: 100},
+	}, record)
+
+	require.Len(t, follower.closed, 3)
+	assert.InDelta(t, 0.02, follower.closed[0], 1e-9)
+	assert.Equal(t, 0.0, follower.closed[1])
+	assert.InDelta(t, 1000, follower.closed[2], 1e-9)
+	assert.Len(t, record.ExecutionLog, 4)
+
+	copied, err := at.store.Follower().GetCopiedQuantities(at.id, "f1")
+	require.NoError(t, err)
+	assert.InDelta(t, 0.01, copied["BTCUSDT_long"], 1e-9)
+	assert.InDelta(t, 11.0, copied["SOLUSDT_long"], 1e-9)
+	assert.NotContains(t, copied, "ETHUSDT_short")
+	assert.NotContains(t, copied, "DOGEUSDT_short")
+	assert.NotContains(t, copied, "ADAUSDT_long")
+}
+
+// TestReplicateToFollowersFills tests only confirmed fills count as copied size, and equity is only compared as total equity
+func TestReplicateToFollowersFills(t *testing.T) {
+	follower := &copyStubTrader{equity: 500, unfilled: true}
+	f := NewFollower(&store.Follower{ID: "f1", Enabled: true, SizingMode: store.FollowerSizingEquityRatio, Multiplier: 1}, follower)
+	at := newCopyTestTrader(t, 1000, f)
+	d := &decision.Decision{Symbol: "BTCUSDT", Action: "open_long", Leverage: 5}
+
+	execution := at.replicateOrder(f, d, &store.DecisionAction{Quantity: 0.02, Price: 50000}, 1000)
+	require.NotNil(t, execution)
+	assert.False(t, execution.Success)
+	assert.Contains(t, execution.Error, "without a fill")
+	at.saveFollowerExecution(execution)
+	copied, err := at.store.Follower().GetCopiedQuantities(at.id, "f1")
+	require.NoError(t, err)
+	assert.Empty(t, copied, "unfilled order is not copied size")
+
+	follower.unfilled = false
+	execution = at.replicateOrder(f, d, &store.DecisionAction{Quantity: 0.02, Price: 50000}, 1000)
+	require.NotNil(t, execution)
+	assert.True(t, execution.Success)
+	assert.InDelta(t, 0.01, execution.FilledQuantity, 1e-9)
+
+	// A balance without total equity is not compared against another definition
+	_, err = balanceEquity(map[string]interface{}{"totalWalletBalance": 1000.0, "availableBalance": 800.0})
+	assert.Error(t, err)
+	noEquity := &copyStubTrader{}
+	f2 := NewFollower(&store.Follower{ID: "f2", Enabled: true, SizingMode: store.FollowerSizingEquityRatio, Multiplier: 1}, noEquity)
+	execution = at.replicateOrder(f2, d, &store.DecisionAction{Quantity: 0.02, Price: 50000}, 1000)
+	require.NotNil(t, execution)
+	assert.False(t, execution.Success)
+	assert.Contains(t, execution.Error, "equity unknown")
+	assert.Empty(t, noEquity.opened)
+}
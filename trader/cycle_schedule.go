@@ -0,0 +1,145 @@
+package trader
+
+import (
+	"hash/fnv"
+	"math"
+	"nofx/logger"
+	"nofx/market"
+	"nofx/store"
+	"time"
+)
+
+const (
+	defaultCandleCloseOffset = 5 * time.Second
+	defaultCandleJitterSecs  = 10
+)
+
+// cycleTimer fires scheduled cycles, either every ScanInterval or aligned to candle closes
+type cycleTimer struct {
+	interval time.Duration
+	schedule *market.BarSchedule // nil = fixed interval
+	anchor   time.Time           // Close of bar 0 (aligned mode)
+	timer    *time.Timer
+	next     time.Time
+}
+
+// newCycleTimer creates a cycle timer, now is the time of the first (immediate) cycle
+func newCycleTimer(interval time.Duration, schedule *market.BarSchedule, now time.Time) *cycleTimer {
+	t := &cycleTimer{interval: interval, schedule: schedule}
+	if schedule != nil {
+		t.anchor, _ = market.LastBarClose(schedule.Timeframe, now)
+	}
+	t.timer = time.NewTimer(time.Until(t.nextAfter(now)))
+	return t
+}
+
+// C channel that fires when the next scheduled cycle is due
+func (t *cycleTimer) C() <-chan time.Time {
+	return t.timer.C
+}
+
+// Next time of the next scheduled cycle
+func (t *cycleTimer) Next() time.Time {
+	return t.next
+}
+
+// Reset schedules the next cycle after a cycle started at cycleStart
+func (t *cycleTimer) Reset(cycleStart time.Time) {
+	if !t.timer.Stop() {
+		select {
+		case <-t.timer.C:
+		default:
+		}
+	}
+	t.timer.Reset(time.Until(t.nextAfter(cycleStart)))
+}
+
+// Stop stops the timer
+func (t *cycleTimer) Stop() {
+	t.timer.Stop()
+}
+
+// nextAfter computes the next cycle time after a cycle started at now
+func (t *cycleTimer) nextAfter(now time.Time) time.Time {
+	t.next = now.Add(t.interval)
+	if t.schedule != nil {
+		// The immediate first cycle already covered bar 0
+		from := now
+		if first := t.anchor.Add(t.schedule.Offset + t.schedule.Jitter); from.Before(first) {
+			from = first
+		}
+		if next, err := t.schedule.NextDecisionTime(t.anchor, from); err == nil {
+			t.next = next
+		}
+	}
+	return t.next
+}
+
+// primaryTimeframe the strategy's primary kline timeframe (the first selected one when unset, 3m by default)
+func primaryTimeframe(config *store.StrategyConfig) string {
+	timeframe := config.Indicators.Klines.PrimaryTimeframe
+	if timeframe == "" && len(config.Indicators.Klines.SelectedTimeframes) > 0 {
+		timeframe = config.Indicators.Klines.SelectedTimeframes[0]
+	}
+	if timeframe == "" {
+		timeframe = "3m"
+	}
+	return timeframe
+}
+
+// candleSchedule builds the candle-close aligned schedule from the strategy config
+// Returns nil when alignment is disabled or misconfigured (falls back to ScanInterval)
+func (at *AutoTrader) candleSchedule() *market.BarSchedule {
+	config := at.strategyEngine.GetConfig()
+	alignment := config.CandleAlignment
+	if !alignment.Enabled {
+		return nil
+	}
+
+	timeframe := primaryTimeframe(config)
+	barDuration, err := market.TFDuration(timeframe)
+	if err != nil {
+		logger.Infof("⚠️ [%s] Candle alignment disabled: %v", at.name, err)
+		return nil
+	}
+
+	cadence := alignment.CadenceNBars
+	if cadence <= 0 {
+		cadence = int(math.Max(1, math.Round(float64(at.config.ScanInterval)/float64(barDuration))))
+	}
+
+	offset := defaultCandleCloseOffset
+	if alignment.CloseOffsetSecs > 0 {
+		offset = time.Duration(alignment.CloseOffsetSecs) * time.Second
+	}
+
+	schedule := &market.BarSchedule{
+		Timeframe:    timeframe,
+		CadenceNBars: cadence,
+		Offset:       offset,
+		Jitter:       traderJitter(at.id, candleJitterMax(alignment)),
+	}
+	if _, err := schedule.NextDecisionTime(time.Now(), time.Now()); err != nil {
+		logger.Infof("⚠️ [%s] Candle alignment disabled: %v", at.name, err)
+		return nil
+	}
+	return schedule
+}
+
+// candleJitterMax max per-trader jitter: default when unset, none when set to 0
+func candleJitterMax(alignment store.CandleAlignmentConfig) time.Duration {
+	if alignment.JitterSecs == nil {
+		return defaultCandleJitterSecs * time.Second
+	}
+	return time.Duration(max(*alignment.JitterSecs, 0)) * time.Second
+}
+
+// traderJitter stable per-trader delay in [0, max), so restarts keep the same slot
+func traderJitter(traderID string, max time.Duration) time.Duration {
+	if max <= 0 {
+		return 0
+	}
+	h := fnv.New32a()
+	h.Write([]byte(traderID))
+	return time.Duration(h.Sum32()%uint32(max.Milliseconds())) * time.Millisecond
+}
@@ -0,0 +1,57 @@
+package trader
+
+import (
+	"testing"
+	"time"
+
+	"nofx/market"
+	"nofx/store"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestCycleTimer_Interval tests fixed interval scheduling from the cycle start
+func TestCycleTimer_Interval(t *testing.T) {
+	start := time.Now()
+	timer := newCycleTimer(3*time.Minute, nil, start)
+	defer timer.Stop()
+
+	timer.Reset(start.Add(time.Minute))
+	assert.Equal(t, start.Add(4*time.Minute), timer.Next())
+}
+
+// TestCycleTimer_Aligned tests candle-close aligned scheduling
+func TestCycleTimer_Aligned(t *testing.T) {
+	schedule := &market.BarSchedule{Timeframe: "5m", CadenceNBars: 3, Offset: 5 * time.Second, Jitter: time.Second}
+	now := time.Now()
+	anchor, err := market.LastBarClose("5m", now)
+	assert.NoError(t, err)
+
+	timer := newCycleTimer(3*time.Minute, schedule, now)
+	defer timer.Stop()
+
+	// The immediate first cycle covers bar 0, next decision is 3 bars later
+	timer.Reset(now)
+	assert.Equal(t, anchor.Add(15*time.Minute+6*time.Second), timer.Next())
+
+	// A cycle started at the decision time moves to the next decision bar
+	timer.Reset(anchor.Add(15*time.Minute + 6*time.Second))
+	assert.Equal(t, anchor.Add(30*time.Minute+6*time.Second), timer.Next())
+}
+
+// TestTraderJitter tests jitter is stable per trader and bounded
+func TestTraderJitter(t *testing.T) {
+	max := 10 * time.Second
+	a := traderJitter("trader-a", max)
+	assert.Equal(t, a, traderJitter("trader-a", max))
+	assert.Less(t, a, max)
+	assert.Zero(t, traderJitter("trader-a", 0))
+}
+
+// TestCandleJitterMax tests jitter defaults when unset and can be disabled with 0
+func TestCandleJitterMax(t *testing.T) {
+	zero, custom := 0, 30
+	assert.Equal(t, 10*time.Second, candleJitterMax(store.CandleAlignmentConfig{}))
+	assert.Zero(t, candleJitterMax(store.CandleAlignmentConfig{JitterSecs: &zero}))
+	assert.Equal(t, 30*time.Second, candleJitterMax(store.CandleAlignmentConfig{JitterSecs: &custom}))
+}
@@ -0,0 +1,278 @@
+package trader
+
+import (
+	"fmt"
+	"math"
+	"nofx/decision"
+	"nofx/logger"
+	"nofx/market"
+	"nofx/store"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	defaultTriggerMinInterval = 60 * time.Second
+	triggerVolumeLookback     = 20 // Klines averaged for volume spike detection
+	triggerATRPeriod          = 14
+)
+
+// triggerBaseline market state of a symbol at the last cycle
+type triggerBaseline struct {
+	price       float64
+	atr         float64
+	avgVolume   float64
+	fundingRate float64
+	fired       bool // A trigger already fired for this symbol since the last cycle
+}
+
+// triggerMonitor watches kline streams, funding and position PnL for conditions that warrant
+// an out-of-band decision cycle. Every cycle resets the baselines; each symbol (and each
+// position) fires at most once until the next cycle.
+type triggerMonitor struct {
+	config store.TriggerConfig
+	fire   func(reason string)
+
+	mu        sync.Mutex
+	timeframe string                      // Strategy's primary timeframe: ATR and volume baselines and watched klines
+	baselines map[string]*triggerBaseline // symbol -> state at last cycle
+	pnlZones  map[string]int              // symbol_side -> PnL threshold zone at last check
+}
+
+// newTriggerMonitor creates a trigger monitor, fire must not block
+func newTriggerMonitor(config store.TriggerConfig, fire func(reason string)) *triggerMonitor {
+	thresholds := append([]float64(nil), config.PositionPnLThresholds...)
+	sort.Float64s(thresholds)
+	config.PositionPnLThresholds = thresholds
+
+	return &triggerMonitor{
+		config:    config,
+		fire:      fire,
+		timeframe: "3m",
+		baselines: make(map[string]*triggerBaseline),
+		pnlZones:  make(map[string]int),
+	}
+}
+
+// minInterval min spacing between two cycles
+func (m *triggerMonitor) minInterval() time.Duration {
+	if m.config.MinIntervalSecs > 0 {
+		return time.Duration(m.config.MinIntervalSecs) * time.Second
+	}
+	return defaultTriggerMinInterval
+}
+
+// reset records the market state the AI has just seen as new baselines
+// timeframe is the strategy's primary timeframe: ATR and average volume come from the cycle's klines of it
+func (m *triggerMonitor) reset(timeframe string, marketData map[string]*market.Data, positions []decision.PositionInfo) {
+	baselines := make(map[string]*triggerBaseline, len(marketData))
+	for symbol, data := range marketData {
+		if data == nil || data.CurrentPrice <= 0 {
+			continue
+		}
+		baseline := &triggerBaseline{price: data.CurrentPrice, fundingRate: data.FundingRate}
+		if series, ok := data.TimeframeData[timeframe]; ok && series != nil {
+			klines := barsToKlines(series.Klines)
+			baseline.atr = market.CalculateATR(klines, triggerATRPeriod)
+			baseline.avgVolume = averageVolume(klines)
+		}
+		baselines[symbol] = baseline
+	}
+
+	zones := make(map[string]int, len(positions))
+	for _, pos := range positions {
+		zones[pos.Symbol+"_"+pos.Side] = m.pnlZone(pos.UnrealizedPnLPct)
+	}
+
+	m.mu.Lock()
+	m.timeframe = timeframe
+	m.baselines = baselines
+	m.pnlZones = zones
+	m.mu.Unlock()
+}
+
+// onKline evaluates price move and volume spike triggers on kline updates of the primary timeframe
+func (m *triggerMonitor) onKline(symbol, interval string, kline market.Kline, closed bool) {
+	if kline.Close <= 0 {
+		return
+	}
+
+	m.mu.Lock()
+	if interval != m.timeframe {
+		m.mu.Unlock()
+		return
+	}
+	baseline, ok := m.baselines[symbol]
+	if !ok || baseline.fired {
+		m.mu.Unlock()
+		return
+	}
+
+	reason := ""
+	move := kline.Close - baseline.price
+	movePct := move / baseline.price * 100
+	switch {
+	case m.config.PriceMovePct > 0 && math.Abs(movePct) >= m.config.PriceMovePct:
+		reason = fmt.Sprintf("%s price moved %+.2f%% since last cycle (%.4f → %.4f)", symbol, movePct, baseline.price, kline.Close)
+	case m.config.PriceMoveATR > 0 && baseline.atr > 0 && math.Abs(move) >= m.config.PriceMoveATR*baseline.atr:
+		reason = fmt.Sprintf("%s price moved %+.1f ATR since last cycle (%.4f → %.4f)", symbol, move/baseline.atr, baseline.price, kline.Close)
+	case m.config.VolumeSpikeMultiple > 0 && baseline.avgVolume > 0 && kline.Volume >= m.config.VolumeSpikeMultiple*baseline.avgVolume:
+		reason = fmt.Sprintf("%s %s volume spike: %.1fx the %d-kline average", symbol, interval, kline.Volume/baseline.avgVolume, triggerVolumeLookback)
+	}
+	if reason != "" {
+		baseline.fired = true
+	}
+	m.mu.Unlock()
+
+	if reason != "" {
+		m.fire(reason)
+	}
+}
+
+// checkFunding evaluates funding flip triggers (funding rates are cached for up to 1 hour)
+func (m *triggerMonitor) checkFunding() {
+	if !m.config.FundingFlip {
+		return
+	}
+
+	m.mu.Lock()
+	symbols := make([]string, 0, len(m.baselines))
+	for symbol, baseline := range m.baselines {
+		if !baseline.fired && baseline.fundingRate != 0 {
+			symbols = append(symbols, symbol)
+		}
+	}
+	m.mu.Unlock()
+
+	for _, symbol := range symbols {
+		rate, err := market.GetFundingRate(symbol)
+		if err != nil || rate == 0 {
+			continue
+		}
+
+		m.mu.Lock()
+		baseline, ok := m.baselines[symbol]
+		flipped := ok && !baseline.fired && (rate > 0) != (baseline.fundingRate > 0)
+		if flipped {
+			baseline.fired = true
+		}
+		m.mu.Unlock()
+
+		if flipped {
+			m.fire(fmt.Sprintf("%s funding rate flipped %+.4f%% → %+.4f%%", symbol, baseline.fundingRate*100, rate*100))
+		}
+	}
+}
+
+// checkPositionPnL evaluates position PnL threshold triggers
+func (m *triggerMonitor) checkPositionPnL(symbol, side string, pnlPct float64) {
+	if len(m.config.PositionPnLThresholds) == 0 {
+		return
+	}
+
+	key := symbol + "_" + side
+	zone := m.pnlZone(pnlPct)
+
+	m.mu.Lock()
+	lastZone, ok := m.pnlZones[key]
+	m.pnlZones[key] = zone
+	m.mu.Unlock()
+
+	if !ok || zone == lastZone {
+		return
+	}
+
+	// Last threshold crossed on the way to the new zone
+	var threshold float64
+	if zone > lastZone {
+		threshold = m.config.PositionPnLThresholds[zone-1]
+	} else {
+		threshold = m.config.PositionPnLThresholds[zone]
+	}
+	m.fire(fmt.Sprintf("%s %s position PnL crossed %+.1f%% (now %+.2f%%)", symbol, strings.ToUpper(side), threshold, pnlPct))
+}
+
+// pnlZone index of the PnL within the sorted thresholds
+func (m *triggerMonitor) pnlZone(pnlPct float64) int {
+	return sort.SearchFloat64s(m.config.PositionPnLThresholds, pnlPct)
+}
+
+// barsToKlines converts the kline bars of a timeframe series to klines for indicator calculation
+func barsToKlines(bars []market.KlineBar) []market.Kline {
+	klines := make([]market.Kline, len(bars))
+	for i, bar := range bars {
+		klines[i] = market.Kline{OpenTime: bar.Time, Open: bar.Open, High: bar.High, Low: bar.Low, Close: bar.Close, Volume: bar.Volume}
+	}
+	return klines
+}
+
+// averageVolume average volume of the klines before the current (unfinished) one
+func averageVolume(klines []market.Kline) float64 {
+	if len(klines) < 2 {
+		return 0
+	}
+	closed := klines[:len(klines)-1]
+	if len(closed) > triggerVolumeLookback {
+		closed = closed[len(closed)-triggerVolumeLookback:]
+	}
+	sum := 0.0
+	for _, k := range closed {
+		sum += k.Volume
+	}
+	return sum / float64(len(closed))
+}
+
+// TriggerCycle requests an out-of-band decision cycle (external signal)
+// Returns an error when the trader doesn't accept external signals
+func (at *AutoTrader) TriggerCycle(reason string) error {
+	if at.triggers == nil || !at.triggers.config.ExternalSignals {
+		return fmt.Errorf("external signals are not enabled for this trader")
+	}
+	if !at.isRunning {
+		return fmt.Errorf("trader is not running")
+	}
+	at.requestTriggeredCycle("external signal: " + reason)
+	return nil
+}
+
+// requestTriggeredCycle queues a triggered cycle; while one is pending, further triggers are dropped
+func (at *AutoTrader) requestTriggeredCycle(reason string) {
+	select {
+	case at.triggerCh <- reason:
+		logger.Infof("⚡ [%s] Decision trigger: %s", at.name, reason)
+	default:
+	}
+}
+
+// startTriggerMonitor subscribes the trigger monitor to kline streams and starts funding checks
+// Returns a function that unsubscribes from the kline streams
+func (at *AutoTrader) startTriggerMonitor() func() {
+	unsubscribe := func() {}
+	if market.WSMonitorCli != nil {
+		unsubscribe = market.WSMonitorCli.AddKlineListener(at.triggers.onKline)
+	}
+
+	if at.triggers.config.FundingFlip {
+		at.monitorWg.Add(1)
+		go func() {
+			defer at.monitorWg.Done()
+
+			ticker := time.NewTicker(1 * time.Minute)
+			defer ticker.Stop()
+
+			for {
+				select {
+				case <-ticker.C:
+					at.triggers.checkFunding()
+				case <-at.stopMonitorCh:
+					return
+				}
+			}
+		}()
+	}
+
+	logger.Infof("⚡ [%s] Event-driven decision triggers enabled (min spacing %v)", at.name, at.triggers.minInterval())
+	return unsubscribe
+}
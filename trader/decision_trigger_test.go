@@ -0,0 +1,120 @@
+package trader
+
+import (
+	"strings"
+	"testing"
+
+	"nofx/decision"
+	"nofx/market"
+	"nofx/store"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestTriggerMonitor(config store.TriggerConfig) (*triggerMonitor, *[]string) {
+	var fired []string
+	m := newTriggerMonitor(config, func(reason string) { fired = append(fired, reason) })
+	return m, &fired
+}
+
+// TestTriggerMonitor_PriceMove tests price move triggers fire once per cycle
+func TestTriggerMonitor_PriceMove(t *testing.T) {
+	m, fired := newTestTriggerMonitor(store.TriggerConfig{Enabled: true, PriceMovePct: 1})
+	m.reset("3m", map[string]*market.Data{"BTCUSDT": {Symbol: "BTCUSDT", CurrentPrice: 100}}, nil)
+
+	m.onKline("BTCUSDT", "3m", market.Kline{Close: 100.5}, false)
+	m.onKline("BTCUSDT", "4h", market.Kline{Close: 102}, false)
+	m.onKline("ETHUSDT", "3m", market.Kline{Close: 1}, false)
+	assert.Empty(t, *fired)
+
+	m.onKline("BTCUSDT", "3m", market.Kline{Close: 98.9}, false)
+	require.Len(t, *fired, 1)
+	assert.Contains(t, (*fired)[0], "BTCUSDT price moved -1.10%")
+
+	// Already fired this cycle
+	m.onKline("BTCUSDT", "3m", market.Kline{Close: 97}, false)
+	assert.Len(t, *fired, 1)
+
+	// New cycle resets the baseline
+	m.reset("3m", map[string]*market.Data{"BTCUSDT": {Symbol: "BTCUSDT", CurrentPrice: 97}}, nil)
+	m.onKline("BTCUSDT", "3m", market.Kline{Close: 98.5}, false)
+	assert.Len(t, *fired, 2)
+}
+
+// TestTriggerMonitor_ATRAndVolume tests ATR move and volume spike triggers
+func TestTriggerMonitor_ATRAndVolume(t *testing.T) {
+	m, fired := newTestTriggerMonitor(store.TriggerConfig{Enabled: true, PriceMoveATR: 2, VolumeSpikeMultiple: 3})
+	m.baselines = map[string]*triggerBaseline{
+		"BTCUSDT": {price: 100, atr: 0.5, avgVolume: 10},
+		"ETHUSDT": {price: 100, atr: 0.5, avgVolume: 10},
+	}
+
+	m.onKline("BTCUSDT", "3m", market.Kline{Close: 100.9, Volume: 20}, false)
+	assert.Empty(t, *fired)
+	m.onKline("BTCUSDT", "3m", market.Kline{Close: 101, Volume: 20}, false)
+	require.Len(t, *fired, 1)
+	assert.Contains(t, (*fired)[0], "+2.0 ATR")
+
+	m.onKline("ETHUSDT", "3m", market.Kline{Close: 100, Volume: 30}, false)
+	require.Len(t, *fired, 2)
+	assert.Contains(t, (*fired)[1], "volume spike: 3.0x")
+}
+
+// TestTriggerMonitor_PrimaryTimeframe tests ATR and volume baselines and watched klines follow the strategy's primary timeframe
+func TestTriggerMonitor_PrimaryTimeframe(t *testing.T) {
+	m, fired := newTestTriggerMonitor(store.TriggerConfig{Enabled: true, PriceMoveATR: 2, VolumeSpikeMultiple: 3})
+	bars := make([]market.KlineBar, 30)
+	for i := range bars {
+		bars[i] = market.KlineBar{Open: 100, High: 101, Low: 99, Close: 100, Volume: 10}
+	}
+	m.reset("15m", map[string]*market.Data{"BTCUSDT": {
+		Symbol: "BTCUSDT", CurrentPrice: 100,
+		TimeframeData: map[string]*market.TimeframeSeriesData{"15m": {Timeframe: "15m", Klines: bars}},
+	}}, nil)
+	require.Contains(t, m.baselines, "BTCUSDT")
+	assert.InDelta(t, 2.0, m.baselines["BTCUSDT"].atr, 1e-9)
+	assert.InDelta(t, 10.0, m.baselines["BTCUSDT"].avgVolume, 1e-9)
+
+	// 3m klines don't count against a 15m baseline
+	m.onKline("BTCUSDT", "3m", market.Kline{Close: 100, Volume: 50}, false)
+	assert.Empty(t, *fired)
+	m.onKline("BTCUSDT", "15m", market.Kline{Close: 100, Volume: 50}, false)
+	require.Len(t, *fired, 1)
+	assert.Contains(t, (*fired)[0], "BTCUSDT 15m volume spike: 5.0x")
+}
+
+// TestTriggerMonitor_PositionPnL tests PnL threshold crossing in both directions
+func TestTriggerMonitor_PositionPnL(t *testing.T) {
+	m, fired := newTestTriggerMonitor(store.TriggerConfig{Enabled: true, PositionPnLThresholds: []float64{10, -5}})
+	m.reset("3m", nil, []decision.PositionInfo{{Symbol: "BTCUSDT", Side: "long", UnrealizedPnLPct: 2}})
+
+	m.checkPositionPnL("BTCUSDT", "long", 8)
+	assert.Empty(t, *fired)
+
+	m.checkPositionPnL("BTCUSDT", "long", 12)
+	require.Len(t, *fired, 1)
+	assert.Contains(t, (*fired)[0], "BTCUSDT LONG position PnL crossed +10.0%")
+
+	m.checkPositionPnL("BTCUSDT", "long", -6)
+	require.Len(t, *fired, 2)
+	assert.Contains(t, (*fired)[1], "crossed -5.0%")
+
+	// First observation of a position opened after the cycle only sets its zone
+	m.checkPositionPnL("ETHUSDT", "short", 20)
+	assert.Len(t, *fired, 2)
+}
+
+// TestTriggerCycle tests external signals queue a single pending cycle
+func TestTriggerCycle(t *testing.T) {
+	at := &AutoTrader{name: "test", triggerCh: make(chan string, 1), isRunning: true}
+	assert.Error(t, at.TriggerCycle("news"))
+
+	at.triggers = newTriggerMonitor(store.TriggerConfig{Enabled: true, ExternalSignals: true}, at.requestTriggeredCycle)
+	require.NoError(t, at.TriggerCycle("news"))
+	require.NoError(t, at.TriggerCycle("dropped while pending"))
+
+	reason := <-at.triggerCh
+	assert.True(t, strings.HasPrefix(reason, "external signal: news"))
+	assert.Len(t, at.triggerCh, 0)
+}
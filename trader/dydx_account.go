@@ -0,0 +1,332 @@
+package trader
+
+import (
+	"fmt"
+	"math"
+	"net/url"
+	"nofx/logger"
+	"sort"
+	"strconv"
+	"time"
+)
+
+// dydxPerpetualPosition indexer perpetual position
+type dydxPerpetualPosition struct {
+	Market        string `json:"market"`
+	Status        string `json:"status"` // OPEN, CLOSED, LIQUIDATED
+	Side          string `json:"side"`   // LONG, SHORT
+	Size          string `json:"size"`   // Signed, negative for short
+	MaxSize       string `json:"maxSize"`
+	EntryPrice    string `json:"entryPrice"`
+	ExitPrice     string `json:"exitPrice"`
+	RealizedPnl   string `json:"realizedPnl"`
+	UnrealizedPnl string `json:"unrealizedPnl"`
+	NetFunding    string `json:"netFunding"`
+	SumClose      string `json:"sumClose"`
+	CreatedAt     string `json:"createdAt"`
+	ClosedAt      string `json:"closedAt"`
+}
+
+// dydxSubaccount indexer subaccount
+type dydxSubaccount struct {
+	Equity                 string                           `json:"equity"`
+	FreeCollateral         string                           `json:"freeCollateral"`
+	OpenPerpetualPositions map[string]dydxPerpetualPosition `json:"openPerpetualPositions"`
+}
+
+// getSubaccount gets the subaccount from the indexer
+func (t *DydxTrader) getSubaccount() (*dydxSubaccount, error) {
+	var resp struct {
+		Subaccount dydxSubaccount `json:"subaccount"`
+	}
+	path := fmt.Sprintf("/addresses/%s/subaccountNumber/%d", t.address, t.subaccount)
+	if err := t.indexerGet(path, nil, &resp); err != nil {
+		return nil, err
+	}
+	return &resp.Subaccount, nil
+}
+
+// GetBalance gets account balance
+func (t *DydxTrader) GetBalance() (map[string]interface{}, error) {
+	// Check cache
+	t.balanceCacheMutex.RLock()
+	if t.cachedBalance != nil && time.Since(t.balanceCacheTime) < t.cacheDuration {
+		t.balanceCacheMutex.RUnlock()
+		return t.cachedBalance, nil
+	}
+	t.balanceCacheMutex.RUnlock()
+
+	subaccount, err := t.getSubaccount()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get account balance: %w", err)
+	}
+
+	equity := parseFloatOrZero(subaccount.Equity)
+	freeCollateral := parseFloatOrZero(subaccount.FreeCollateral)
+	unrealizedPnL := 0.0
+	for _, pos := range subaccount.OpenPerpetualPositions {
+		unrealizedPnL += parseFloatOrZero(pos.UnrealizedPnl)
+	}
+	logger.Infof("✓ [dYdX] Balance: equity=%.2f, available=%.2f", equity, freeCollateral)
+
+	result := map[string]interface{}{
+		"totalWalletBalance":    equity - unrealizedPnL,
+		"availableBalance":      freeCollateral,
+		"totalUnrealizedProfit": unrealizedPnL,
+		"total_equity":          equity,
+	}
+
+	// Update cache
+	t.balanceCacheMutex.Lock()
+	t.cachedBalance = result
+	t.balanceCacheTime = time.Now()
+	t.balanceCacheMutex.Unlock()
+
+	return result, nil
+}
+
+// GetPositions gets all positions
+// dYdX reports no per-position leverage or liquidation price; leverage is the recorded setting,
+// falling back to position notional over subaccount equity
+func (t *DydxTrader) GetPositions() ([]map[string]interface{}, error) {
+	// Check cache
+	t.positionsCacheMutex.RLock()
+	if t.cachedPositions != nil && time.Since(t.positionsCacheTime) < t.cacheDuration {
+		t.positionsCacheMutex.RUnlock()
+		return t.cachedPositions, nil
+	}
+	t.positionsCacheMutex.RUnlock()
+
+	subaccount, err := t.getSubaccount()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get positions: %w", err)
+	}
+	markets, err := t.loadMarkets()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get positions: %w", err)
+	}
+	equity := parseFloatOrZero(subaccount.Equity)
+
+	result := []map[string]interface{}{}
+	for ticker, pos := range subaccount.OpenPerpetualPositions {
+		size := parseFloatOrZero(pos.Size)
+		if size == 0 {
+			continue
+		}
+
+		symbol := CanonicalSymbol("dydx", ticker)
+		side := "long"
+		if size < 0 {
+			side = "short"
+			size = -size
+		}
+
+		markPrice := 0.0
+		if m, ok := markets[ticker]; ok {
+			markPrice = m.OraclePrice
+		}
+		leverage := float64(t.getLeverage(symbol))
+		if leverage == 0 && equity > 0 {
+			leverage = math.Max(1, math.Round(size*markPrice/equity))
+		}
+		createdAt, _ := time.Parse(time.RFC3339, pos.CreatedAt)
+
+		result = append(result, map[string]interface{}{
+			"symbol":           symbol,
+			"positionAmt":      size,
+			"entryPrice":       parseFloatOrZero(pos.EntryPrice),
+			"markPrice":        markPrice,
+			"unRealizedProfit": parseFloatOrZero(pos.UnrealizedPnl),
+			"leverage":         leverage,
+			"liquidationPrice": 0.0,
+			"side":             side,
+			"createdTime":      createdAt.UnixMilli(),
+		})
+	}
+
+	// Update cache
+	t.positionsCacheMutex.Lock()
+	t.cachedPositions = result
+	t.positionsCacheTime = time.Now()
+	t.positionsCacheMutex.Unlock()
+
+	return result, nil
+}
+
+// GetClosedPnL retrieves closed position records from the indexer
+// realizedPnl on dYdX already includes trading fees and funding, so Fee is reported as 0
+func (t *DydxTrader) GetClosedPnL(startTime time.Time, limit int) ([]ClosedPnLRecord, error) {
+	if limit <= 0 || limit > 100 {
+		limit = 100
+	}
+
+	query := t.subaccountQuery()
+	query.Set("status", "CLOSED")
+	query.Set("limit", strconv.Itoa(limit))
+
+	var resp struct {
+		Positions []dydxPerpetualPosition `json:"positions"`
+	}
+	if err := t.indexerGet("/perpetualPositions", query, &resp); err != nil {
+		return nil, fmt.Errorf("failed to get closed positions: %w", err)
+	}
+
+	records := make([]ClosedPnLRecord, 0, len(resp.Positions))
+	for _, pos := range resp.Positions {
+		exitTime, _ := time.Parse(time.RFC3339, pos.ClosedAt)
+		if exitTime.Before(startTime) {
+			continue
+		}
+		entryTime, _ := time.Parse(time.RFC3339, pos.CreatedAt)
+
+		quantity := parseFloatOrZero(pos.SumClose)
+		if quantity == 0 {
+			quantity = math.Abs(parseFloatOrZero(pos.MaxSize))
+		}
+		closeType := "unknown"
+		if pos.Status == "LIQUIDATED" {
+			closeType = "liquidation"
+		}
+
+		records = append(records, ClosedPnLRecord{
+			Symbol:      CanonicalSymbol("dydx", pos.Market),
+			Side:        map[string]string{"LONG": "long", "SHORT": "short"}[pos.Side],
+			EntryPrice:  parseFloatOrZero(pos.EntryPrice),
+			ExitPrice:   parseFloatOrZero(pos.ExitPrice),
+			Quantity:    quantity,
+			RealizedPnL: parseFloatOrZero(pos.RealizedPnl),
+			EntryTime:   entryTime,
+			ExitTime:    exitTime,
+			CloseType:   closeType,
+			ExchangeID:  pos.Market + "-" + pos.CreatedAt,
+		})
+	}
+
+	// Oldest first
+	sort.Slice(records, func(i, j int) bool { return records[i].ExitTime.Before(records[j].ExitTime) })
+	return records, nil
+}
+
+// dydxFill indexer fill
+type dydxFill struct {
+	ID        string `json:"id"`
+	Side      string `json:"side"` // BUY, SELL
+	Market    string `json:"market"`
+	Price     string `json:"price"`
+	Size      string `json:"size"`
+	Fee       string `json:"fee"`
+	OrderID   string `json:"orderId"`
+	CreatedAt string `json:"createdAt"`
+}
+
+// getFills gets recent fills of the subaccount, optionally for one market
+func (t *DydxTrader) getFills(ticker string, limit int) ([]dydxFill, error) {
+	return t.getFillsBefore(ticker, time.Time{}, limit)
+}
+
+// getFillsBefore gets the latest fills created at or before the given time (zero = now), newest first
+func (t *DydxTrader) getFillsBefore(ticker string, before time.Time, limit int) ([]dydxFill, error) {
+	query := t.subaccountQuery()
+	query.Set("limit", strconv.Itoa(limit))
+	if ticker != "" {
+		query.Set("market", ticker)
+		query.Set("marketType", "PERPETUAL")
+	}
+	if !before.IsZero() {
+		query.Set("createdBeforeOrAt", before.UTC().Format(time.RFC3339Nano))
+	}
+
+	var resp struct {
+		Fills []dydxFill `json:"fills"`
+	}
+	if err := t.indexerGet("/fills", query, &resp); err != nil {
+		return nil, err
+	}
+	return resp.Fills, nil
+}
+
+// GetTrades retrieves trade history (fills) from the indexer
+// dYdX positions are net (one-way), so PositionSide is "BOTH" and RealizedPnL is 0; use GetClosedPnL for closed positions
+// The indexer returns fills newest first, so history is walked forward in 7-day windows, each paged back from its end
+func (t *DydxTrader) GetTrades(startTime time.Time, limit int) ([]TradeRecord, error) {
+	const (
+		window   = 7 * 24 * time.Hour
+		pageSize = 100
+	)
+	if limit <= 0 {
+		limit = 1000
+	}
+
+	var trades []TradeRecord
+	seen := make(map[string]bool)
+	now := time.Now()
+	for windowStart := startTime; windowStart.Before(now) && len(trades) < limit; windowStart = windowStart.Add(window) {
+		windowEnd := windowStart.Add(window)
+		if windowEnd.After(now) {
+			windowEnd = now
+		}
+
+		before := windowEnd
+		for {
+			fills, err := t.getFillsBefore("", before, pageSize)
+			if err != nil {
+				return nil, fmt.Errorf("failed to get trade history: %w", err)
+			}
+
+			added := 0
+			for _, fill := range fills {
+				tradeTime, _ := time.Parse(time.RFC3339, fill.CreatedAt)
+				if tradeTime.Before(before) {
+					before = tradeTime
+				}
+				// Pages overlap on the boundary timestamp, keep each fill in exactly one window
+				if seen[fill.ID] || tradeTime.Before(windowStart) || !tradeTime.Before(windowEnd) {
+					continue
+				}
+				seen[fill.ID] = true
+				added++
+				trades = append(trades, TradeRecord{
+					TradeID:      fill.ID,
+					Symbol:       CanonicalSymbol("dydx", fill.Market),
+					Side:         fill.Side,
+					PositionSide: "BOTH",
+					Price:        parseFloatOrZero(fill.Price),
+					Quantity:     parseFloatOrZero(fill.Size),
+					Fee:          parseFloatOrZero(fill.Fee),
+					Time:         tradeTime,
+				})
+			}
+
+			if len(fills) < pageSize || added == 0 || before.Before(windowStart) {
+				break
+			}
+		}
+	}
+
+	return sortAndLimitTrades(trades, limit), nil
+}
+
+// getOrders lists orders of a market with the given status
+func (t *DydxTrader) getOrders(ticker, status string) ([]dydxIndexerOrder, error) {
+	query := t.subaccountQuery()
+	query.Set("ticker", ticker)
+	query.Set("limit", "100")
+	if status != "" {
+		query.Set("status", status)
+	}
+
+	var orders []dydxIndexerOrder
+	if err := t.indexerGet("/orders", query, &orders); err != nil {
+		return nil, err
+	}
+	return orders, nil
+}
+
+// getOrder gets an order by indexer order ID
+func (t *DydxTrader) getOrder(orderID string) (*dydxIndexerOrder, error) {
+	var order dydxIndexerOrder
+	if err := t.indexerGet("/orders/"+url.PathEscape(orderID), nil, &order); err != nil {
+		return nil, err
+	}
+	return &order, nil
+}
@@ -0,0 +1,444 @@
+package trader
+
+import (
+	"encoding/hex"
+	"fmt"
+	"hash/fnv"
+	"math/rand"
+	"nofx/logger"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// dydxIndexerOrder indexer order
+type dydxIndexerOrder struct {
+	ID               string `json:"id"`
+	ClientID         string `json:"clientId"`
+	ClobPairID       string `json:"clobPairId"`
+	OrderFlags       string `json:"orderFlags"`
+	Ticker           string `json:"ticker"`
+	Side             string `json:"side"` // BUY, SELL
+	Size             string `json:"size"`
+	TotalFilled      string `json:"totalFilled"`
+	Price            string `json:"price"`
+	TriggerPrice     string `json:"triggerPrice"`
+	Type             string `json:"type"`   // MARKET, LIMIT, STOP_MARKET, TAKE_PROFIT_MARKET, ...
+	Status           string `json:"status"` // OPEN, FILLED, CANCELED, BEST_EFFORT_CANCELED, UNTRIGGERED, ...
+	GoodTilBlock     string `json:"goodTilBlock"`
+	GoodTilBlockTime string `json:"goodTilBlockTime"`
+	UpdatedAt        string `json:"updatedAt"`
+}
+
+// onChainID rebuilds the on-chain order ID of an indexer order
+func (o *dydxIndexerOrder) onChainID(owner string, subaccount uint32) dydxOrderID {
+	clientID, _ := strconv.ParseUint(o.ClientID, 10, 32)
+	flags, _ := strconv.ParseUint(o.OrderFlags, 10, 32)
+	clobPairID, _ := strconv.ParseUint(o.ClobPairID, 10, 32)
+	return dydxOrderID{
+		Owner:            owner,
+		SubaccountNumber: subaccount,
+		ClientID:         uint32(clientID),
+		OrderFlags:       uint32(flags),
+		ClobPairID:       uint32(clobPairID),
+	}
+}
+
+// dydxClientID converts a client order ID to a dYdX uint32 client ID
+// Hex IDs (GenerateClientOrderID) use their first 8 characters, other IDs are hashed, empty IDs are random
+func dydxClientID(clientOrderID string) uint32 {
+	if clientOrderID == "" {
+		return rand.Uint32()
+	}
+	if len(clientOrderID) >= 8 {
+		if b, err := hex.DecodeString(clientOrderID[:8]); err == nil {
+			return uint32(b[0])<<24 | uint32(b[1])<<16 | uint32(b[2])<<8 | uint32(b[3])
+		}
+	}
+	h := fnv.New32a()
+	h.Write([]byte(clientOrderID))
+	return h.Sum32()
+}
+
+// OpenLong opens long position
+func (t *DydxTrader) OpenLong(symbol string, quantity float64, leverage int) (map[string]interface{}, error) {
+	return t.openPosition(symbol, quantity, leverage, dydxSideBuy, "")
+}
+
+// OpenLongWithClientID opens long position tagged with client order ID
+func (t *DydxTrader) OpenLongWithClientID(symbol string, quantity float64, leverage int, clientOrderID string) (map[string]interface{}, error) {
+	return t.openPosition(symbol, quantity, leverage, dydxSideBuy, clientOrderID)
+}
+
+// OpenShort opens short position
+func (t *DydxTrader) OpenShort(symbol string, quantity float64, leverage int) (map[string]interface{}, error) {
+	return t.openPosition(symbol, quantity, leverage, dydxSideSell, "")
+}
+
+// OpenShortWithClientID opens short position tagged with client order ID
+func (t *DydxTrader) OpenShortWithClientID(symbol string, quantity float64, leverage int, clientOrderID string) (map[string]interface{}, error) {
+	return t.openPosition(symbol, quantity, leverage, dydxSideSell, clientOrderID)
+}
+
+// openPosition opens a position with a market order
+func (t *DydxTrader) openPosition(symbol string, quantity float64, leverage int, side int, clientOrderID string) (map[string]interface{}, error) {
+	action := "OpenLong"
+	if side == dydxSideSell {
+		action = "OpenShort"
+	}
+
+	// Cancel old pending orders first
+	if err := t.CancelAllOrders(symbol); err != nil {
+		logger.Infof("  ⚠ Failed to cancel old pending orders: %v", err)
+	}
+	if err := t.SetLeverage(symbol, leverage); err != nil {
+		return nil, err
+	}
+
+	logger.Infof("  📊 dYdX %s: symbol=%s, quantity=%v", action, symbol, quantity)
+
+	order, err := t.placeMarketOrder(symbol, side, quantity, false, clientOrderID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to %s: %w", action, err)
+	}
+
+	// Clear cache
+	t.clearCache()
+
+	logger.Infof("✓ dYdX %s order placed: %s", action, symbol)
+
+	return order, nil
+}
+
+// CloseLong closes long position
+func (t *DydxTrader) CloseLong(symbol string, quantity float64) (map[string]interface{}, error) {
+	return t.closePosition(symbol, "long", quantity, "")
+}
+
+// CloseLongWithClientID closes long position tagged with client order ID
+func (t *DydxTrader) CloseLongWithClientID(symbol string, quantity float64, clientOrderID string) (map[string]interface{}, error) {
+	return t.closePosition(symbol, "long", quantity, clientOrderID)
+}
+
+// CloseShort closes short position
+func (t *DydxTrader) CloseShort(symbol string, quantity float64) (map[string]interface{}, error) {
+	return t.closePosition(symbol, "short", quantity, "")
+}
+
+// CloseShortWithClientID closes short position tagged with client order ID
+func (t *DydxTrader) CloseShortWithClientID(symbol string, quantity float64, clientOrderID string) (map[string]interface{}, error) {
+	return t.closePosition(symbol, "short", quantity, clientOrderID)
+}
+
+// closePosition closes a position with a reduce-only market order
+func (t *DydxTrader) closePosition(symbol, side string, quantity float64, clientOrderID string) (map[string]interface{}, error) {
+	// If quantity is 0, get current position
+	if quantity == 0 {
+		positions, err := t.GetPositions()
+		if err != nil {
+			return nil, err
+		}
+		for _, pos := range positions {
+			if pos["symbol"] == symbol && pos["side"] == side {
+				quantity = pos["positionAmt"].(float64)
+				break
+			}
+		}
+		if quantity == 0 {
+			return nil, fmt.Errorf("%s position not found for %s", side, symbol)
+		}
+	}
+
+	// Closing a long sells, closing a short buys
+	orderSide, action := dydxSideSell, "CloseLong"
+	if side == "short" {
+		orderSide, action = dydxSideBuy, "CloseShort"
+	}
+
+	logger.Infof("  📊 dYdX %s: symbol=%s, quantity=%v", action, symbol, quantity)
+
+	order, err := t.placeMarketOrder(symbol, orderSide, quantity, true, clientOrderID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to close %s position: %w", side, err)
+	}
+
+	// Clear cache
+	t.clearCache()
+
+	// Remove protective orders of the closed position
+	if err := t.CancelStopOrders(symbol); err != nil {
+		logger.Infof("  ⚠ Failed to cancel stop orders: %v", err)
+	}
+
+	logger.Infof("✓ dYdX closed %s position successfully: %s", side, symbol)
+
+	return order, nil
+}
+
+// placeMarketOrder submits a short-term IOC order priced at the oracle price plus slippage,
+// resolving ambiguous failures by client order ID
+func (t *DydxTrader) placeMarketOrder(symbol string, side int, quantity float64, reduceOnly bool, clientOrderID string) (map[string]interface{}, error) {
+	market, err := t.getMarket(symbol)
+	if err != nil {
+		return nil, err
+	}
+	quantums, err := market.toQuantums(quantity)
+	if err != nil {
+		return nil, err
+	}
+
+	worstPrice := market.OraclePrice * (1 + dydxMarketSlippage)
+	if side == dydxSideSell {
+		worstPrice = market.OraclePrice * (1 - dydxMarketSlippage)
+	}
+
+	clientID := dydxClientID(clientOrderID)
+	submit := func() (map[string]interface{}, error) {
+		height, err := t.getBlockHeight()
+		if err != nil {
+			return nil, err
+		}
+		msg := encodeMsgPlaceOrder(dydxOrderMsg{
+			ID: dydxOrderID{
+				Owner:            t.address,
+				SubaccountNumber: t.subaccount,
+				ClientID:         clientID,
+				OrderFlags:       dydxOrderFlagsShortTerm,
+				ClobPairID:       market.ClobPairID,
+			},
+			Side:         side,
+			Quantums:     quantums,
+			Subticks:     market.toSubticks(worstPrice),
+			GoodTilBlock: height + dydxShortTermBlockWindow,
+			TimeInForce:  dydxTimeInForceIOC,
+			ReduceOnly:   reduceOnly,
+		})
+		txHash, err := t.sendTx(dydxMsgPlaceOrder, msg, false)
+		if err != nil {
+			return nil, err
+		}
+		return map[string]interface{}{
+			"orderId":       strconv.FormatUint(uint64(clientID), 10),
+			"clientOrderId": clientOrderID,
+			"symbol":        symbol,
+			"status":        "NEW",
+			"txHash":        txHash,
+		}, nil
+	}
+	lookup := func() (map[string]interface{}, error) {
+		return t.GetOrderByClientID(symbol, clientOrderID)
+	}
+
+	return submitWithClientOrderID(clientOrderID, submit, lookup)
+}
+
+// SetStopLoss sets a stop-loss (conditional stop market) order
+func (t *DydxTrader) SetStopLoss(symbol string, positionSide string, quantity, stopPrice float64) error {
+	if err := t.placeConditionalOrder(symbol, positionSide, quantity, stopPrice, dydxConditionStopLoss); err != nil {
+		return fmt.Errorf("failed to set stop loss: %w", err)
+	}
+	logger.Infof("  Stop loss price set: %.4f", stopPrice)
+	return nil
+}
+
+// SetTakeProfit sets a take-profit (conditional take profit market) order
+func (t *DydxTrader) SetTakeProfit(symbol string, positionSide string, quantity, takeProfitPrice float64) error {
+	if err := t.placeConditionalOrder(symbol, positionSide, quantity, takeProfitPrice, dydxConditionTakeProfit); err != nil {
+		return fmt.Errorf("failed to set take profit: %w", err)
+	}
+	logger.Infof("  Take profit price set: %.4f", takeProfitPrice)
+	return nil
+}
+
+// placeConditionalOrder places a reduce-only conditional IOC order closing the position at triggerPrice
+func (t *DydxTrader) placeConditionalOrder(symbol, positionSide string, quantity, triggerPrice float64, conditionType int) error {
+	market, err := t.getMarket(symbol)
+	if err != nil {
+		return err
+	}
+	quantums, err := market.toQuantums(quantity)
+	if err != nil {
+		return err
+	}
+
+	// Closing a long sells, closing a short buys; the limit price leaves room for slippage after triggering
+	side, worstPrice := dydxSideSell, triggerPrice*(1-dydxStopSlippage)
+	if strings.ToUpper(positionSide) == "SHORT" {
+		side, worstPrice = dydxSideBuy, triggerPrice*(1+dydxStopSlippage)
+	}
+
+	msg := encodeMsgPlaceOrder(dydxOrderMsg{
+		ID: dydxOrderID{
+			Owner:            t.address,
+			SubaccountNumber: t.subaccount,
+			ClientID:         rand.Uint32(),
+			OrderFlags:       dydxOrderFlagsConditional,
+			ClobPairID:       market.ClobPairID,
+		},
+		Side:             side,
+		Quantums:         quantums,
+		Subticks:         market.toSubticks(worstPrice),
+		GoodTilBlockTime: uint32(time.Now().Add(dydxConditionalOrderExpiry).Unix()),
+		TimeInForce:      dydxTimeInForceIOC,
+		ReduceOnly:       true,
+		ConditionType:    conditionType,
+		TriggerSubticks:  market.toSubticks(triggerPrice),
+	})
+	_, err = t.sendTx(dydxMsgPlaceOrder, msg, true)
+	return err
+}
+
+// CancelStopLossOrders cancels only stop-loss orders
+func (t *DydxTrader) CancelStopLossOrders(symbol string) error {
+	return t.cancelOrders(symbol, []string{"UNTRIGGERED"}, func(o *dydxIndexerOrder) bool {
+		return strings.HasPrefix(o.Type, "STOP_")
+	})
+}
+
+// CancelTakeProfitOrders cancels only take-profit orders
+func (t *DydxTrader) CancelTakeProfitOrders(symbol string) error {
+	return t.cancelOrders(symbol, []string{"UNTRIGGERED"}, func(o *dydxIndexerOrder) bool {
+		return strings.HasPrefix(o.Type, "TAKE_PROFIT")
+	})
+}
+
+// CancelStopOrders cancels stop-loss and take-profit orders
+func (t *DydxTrader) CancelStopOrders(symbol string) error {
+	return t.cancelOrders(symbol, []string{"UNTRIGGERED"}, nil)
+}
+
+// CancelAllOrders cancels all pending orders, including untriggered conditional orders
+func (t *DydxTrader) CancelAllOrders(symbol string) error {
+	return t.cancelOrders(symbol, []string{"OPEN", "UNTRIGGERED"}, nil)
+}
+
+// cancelOrders cancels orders of a symbol in the given statuses that match the filter (nil = all)
+func (t *DydxTrader) cancelOrders(symbol string, statuses []string, match func(o *dydxIndexerOrder) bool) error {
+	ticker := t.convertSymbol(symbol)
+
+	canceled := 0
+	for _, status := range statuses {
+		orders, err := t.getOrders(ticker, status)
+		if err != nil {
+			return fmt.Errorf("failed to get %s orders: %w", strings.ToLower(status), err)
+		}
+		for i := range orders {
+			order := &orders[i]
+			if match != nil && !match(order) {
+				continue
+			}
+
+			id := order.onChainID(t.address, t.subaccount)
+			goodTilBlock, _ := strconv.ParseUint(order.GoodTilBlock, 10, 32)
+			var goodTilBlockTime uint32
+			if tm, err := time.Parse(time.RFC3339, order.GoodTilBlockTime); err == nil {
+				goodTilBlockTime = uint32(tm.Unix())
+			}
+
+			msg := encodeMsgCancelOrder(id, uint32(goodTilBlock), goodTilBlockTime)
+			if _, err := t.sendTx(dydxMsgCancelOrder, msg, id.OrderFlags != dydxOrderFlagsShortTerm); err != nil {
+				logger.Infof("  ⚠ Failed to cancel dYdX order %s: %v", order.ID, err)
+				continue
+			}
+			canceled++
+		}
+	}
+
+	if canceled > 0 {
+		logger.Infof("  ✓ Canceled %d dYdX orders for %s", canceled, symbol)
+	}
+	return nil
+}
+
+// GetOrderStatus gets order status
+// orderID is either the numeric client ID returned when placing the order or an indexer order ID
+func (t *DydxTrader) GetOrderStatus(symbol string, orderID string) (map[string]interface{}, error) {
+	if _, err := strconv.ParseUint(orderID, 10, 32); err == nil {
+		return t.findOrderByClientID(symbol, orderID)
+	}
+	order, err := t.getOrder(orderID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get order status: %w", err)
+	}
+	return t.parseOrder(symbol, order)
+}
+
+// GetOrderByClientID gets order status by client order ID
+func (t *DydxTrader) GetOrderByClientID(symbol string, clientOrderID string) (map[string]interface{}, error) {
+	result, err := t.findOrderByClientID(symbol, strconv.FormatUint(uint64(dydxClientID(clientOrderID)), 10))
+	if err != nil {
+		return nil, err
+	}
+	result["clientOrderId"] = clientOrderID
+	return result, nil
+}
+
+// findOrderByClientID finds the latest order of a symbol with the given numeric client ID
+func (t *DydxTrader) findOrderByClientID(symbol string, clientID string) (map[string]interface{}, error) {
+	orders, err := t.getOrders(t.convertSymbol(symbol), "")
+	if err != nil {
+		return nil, fmt.Errorf("failed to get order status: %w", err)
+	}
+	for i := range orders {
+		if orders[i].ClientID == clientID {
+			return t.parseOrder(symbol, &orders[i])
+		}
+	}
+	return nil, fmt.Errorf("order with client ID %s not found", clientID)
+}
+
+// parseOrder converts an indexer order to the common order map, with average price and fees from its fills
+func (t *DydxTrader) parseOrder(symbol string, order *dydxIndexerOrder) (map[string]interface{}, error) {
+	filled := parseFloatOrZero(order.TotalFilled)
+
+	// IOC orders end CANCELED when the remainder was cancelled after a partial fill
+	var status string
+	switch order.Status {
+	case "OPEN", "BEST_EFFORT_OPENED", "UNTRIGGERED":
+		status = "NEW"
+		if filled > 0 {
+			status = "PARTIALLY_FILLED"
+		}
+	case "FILLED":
+		status = "FILLED"
+	default:
+		status = "CANCELED"
+		if filled > 0 {
+			status = "FILLED"
+		}
+	}
+
+	avgPrice, commission := 0.0, 0.0
+	if filled > 0 {
+		fills, err := t.getFills(order.Ticker, 100)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get order fills: %w", err)
+		}
+		notional, size := 0.0, 0.0
+		for _, fill := range fills {
+			if fill.OrderID != order.ID {
+				continue
+			}
+			fillSize := parseFloatOrZero(fill.Size)
+			notional += parseFloatOrZero(fill.Price) * fillSize
+			size += fillSize
+			commission += parseFloatOrZero(fill.Fee)
+		}
+		if size > 0 {
+			avgPrice = notional / size
+		}
+	}
+	updatedAt, _ := time.Parse(time.RFC3339, order.UpdatedAt)
+
+	return map[string]interface{}{
+		"orderId":     order.ID,
+		"symbol":      symbol,
+		"status":      status,
+		"avgPrice":    avgPrice,
+		"executedQty": filled,
+		"side":        order.Side,
+		"type":        order.Type,
+		"updateTime":  updatedAt.UnixMilli(),
+		"commission":  commission,
+	}, nil
+}
@@ -0,0 +1,382 @@
+package trader
+
+import (
+	"crypto/ecdsa"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"net/url"
+	"nofx/logger"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// dYdX v4 endpoints
+// The indexer serves read-only account and market data; orders are chain transactions sent to a validator node
+const (
+	dydxMainnetIndexerURL   = "https://indexer.dydx.trade/v4"
+	dydxMainnetValidatorURL = "https://dydx-rest.publicnode.com"
+	dydxMainnetChainID      = "dydx-mainnet-1"
+
+	dydxTestnetIndexerURL   = "https://indexer.v4testnet.dydx.exchange/v4"
+	dydxTestnetValidatorURL = "https://dydx-testnet-api.polkachu.com"
+	dydxTestnetChainID      = "dydx-testnet-4"
+)
+
+// dYdX order parameters
+const (
+	dydxMarketSlippage         = 0.05                // Worst price of market (IOC) orders relative to oracle price
+	dydxStopSlippage           = 0.10                // Worst price of triggered SL/TP orders relative to trigger price
+	dydxShortTermBlockWindow   = 10                  // Short-term orders expire this many blocks ahead
+	dydxConditionalOrderExpiry = 28 * 24 * time.Hour // Conditional orders are stateful and need an expiry
+	dydxQuoteAtomicResolution  = -6                  // USDC quantums
+)
+
+// DydxTrader dYdX v4 perpetuals trader
+// Markets are USD-quoted (BTC-USD) and settled in USDC; canonical symbols keep the USDT suffix like other venues.
+// All margin is cross margin within the configured subaccount.
+type DydxTrader struct {
+	privateKey *ecdsa.PrivateKey
+	address    string // dydx1... bech32 address
+	subaccount uint32 // Subaccount number (0-127 share cross margin)
+	chainID    string
+	testnet    bool
+
+	indexerURL   string
+	validatorURL string
+	client       *http.Client
+
+	// Serializes transactions so account sequences don't collide
+	txMutex sync.Mutex
+
+	// Balance cache
+	cachedBalance     map[string]interface{}
+	balanceCacheTime  time.Time
+	balanceCacheMutex sync.RWMutex
+
+	// Positions cache
+	cachedPositions     []map[string]interface{}
+	positionsCacheTime  time.Time
+	positionsCacheMutex sync.RWMutex
+
+	// Market info cache (keyed by ticker, e.g. BTC-USD)
+	marketsCache      map[string]*DydxMarket
+	marketsCacheTime  time.Time
+	marketsCacheMutex sync.RWMutex
+
+	// Requested leverage per symbol (dYdX has no leverage setting; kept for position reporting)
+	leverages      map[string]int
+	leveragesMutex sync.RWMutex
+
+	// Cache duration
+	cacheDuration time.Duration
+}
+
+// DydxMarket dYdX perpetual market info
+type DydxMarket struct {
+	Ticker                    string  // Market ticker (e.g. BTC-USD)
+	ClobPairID                uint32  // Order book ID used in order IDs
+	OraclePrice               float64 // Oracle price
+	TickSize                  float64 // Price increment
+	StepSize                  float64 // Size increment
+	AtomicResolution          int     // Size quantums = size * 10^-atomicResolution
+	QuantumConversionExponent int     // Price subticks exponent
+	StepBaseQuantums          uint64  // Size increment in quantums
+	SubticksPerTick           uint64  // Price increment in subticks
+	InitialMarginFraction     float64 // 1 / max leverage
+	Active                    bool    // Market is trading
+}
+
+// NewDydxTrader creates a dYdX v4 trader
+// mnemonic: BIP39 mnemonic of the dYdX account (a hex secp256k1 private key is also accepted)
+// address: dydx1... address, used to verify the derived key (optional)
+// subaccount: subaccount number holding the margin
+// testnet: use dydx-testnet-4
+func NewDydxTrader(mnemonic, address string, subaccount int, testnet bool) (*DydxTrader, error) {
+	// 1. Derive signing key
+	privateKey, err := dydxKeyFromSecret(mnemonic)
+	if err != nil {
+		return nil, err
+	}
+
+	// 2. Derive address and check it matches the configured one
+	derived := cosmosAddress(dydxAddressPrefix, &privateKey.PublicKey)
+	if address != "" && !strings.EqualFold(strings.TrimSpace(address), derived) {
+		return nil, fmt.Errorf("dYdX address mismatch: configured %s, mnemonic derives %s", address, derived)
+	}
+	if subaccount < 0 {
+		return nil, fmt.Errorf("invalid dYdX subaccount number: %d", subaccount)
+	}
+
+	// 3. Select network
+	indexerURL, validatorURL, chainID := dydxMainnetIndexerURL, dydxMainnetValidatorURL, dydxMainnetChainID
+	if testnet {
+		indexerURL, validatorURL, chainID = dydxTestnetIndexerURL, dydxTestnetValidatorURL, dydxTestnetChainID
+	}
+
+	trader := &DydxTrader{
+		privateKey:    privateKey,
+		address:       derived,
+		subaccount:    uint32(subaccount),
+		chainID:       chainID,
+		testnet:       testnet,
+		indexerURL:    indexerURL,
+		validatorURL:  validatorURL,
+		client:        &http.Client{Timeout: 30 * time.Second},
+		marketsCache:  make(map[string]*DydxMarket),
+		leverages:     make(map[string]int),
+		cacheDuration: 15 * time.Second,
+	}
+
+	logger.Infof("🟣 [dYdX] Trader initialized: address=%s, subaccount=%d, chain=%s", derived, subaccount, chainID)
+
+	return trader, nil
+}
+
+// convertSymbol converts a canonical symbol to a dYdX ticker
+// e.g., BTCUSDT -> BTC-USD
+func (t *DydxTrader) convertSymbol(symbol string) string {
+	return VenueSymbol("dydx", symbol)
+}
+
+// getJSON sends a GET request and decodes the JSON response
+func (t *DydxTrader) getJSON(url string, out interface{}) error {
+	resp, err := t.client.Get(url)
+	if err != nil {
+		return fmt.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("failed to read response: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("HTTP %s: %s", resp.Status, string(body))
+	}
+	if err := json.Unmarshal(body, out); err != nil {
+		return fmt.Errorf("failed to parse response: %w", err)
+	}
+	return nil
+}
+
+// indexerGet queries the indexer API
+func (t *DydxTrader) indexerGet(path string, query url.Values, out interface{}) error {
+	u := t.indexerURL + path
+	if len(query) > 0 {
+		u += "?" + query.Encode()
+	}
+	return t.getJSON(u, out)
+}
+
+// subaccountQuery query parameters selecting this trader's subaccount
+func (t *DydxTrader) subaccountQuery() url.Values {
+	return url.Values{
+		"address":          {t.address},
+		"subaccountNumber": {strconv.FormatUint(uint64(t.subaccount), 10)},
+	}
+}
+
+// getBlockHeight gets the latest block height from the indexer
+func (t *DydxTrader) getBlockHeight() (uint32, error) {
+	var resp struct {
+		Height string `json:"height"`
+	}
+	if err := t.indexerGet("/height", nil, &resp); err != nil {
+		return 0, fmt.Errorf("failed to get block height: %w", err)
+	}
+	height, err := strconv.ParseUint(resp.Height, 10, 32)
+	if err != nil {
+		return 0, fmt.Errorf("invalid block height %q", resp.Height)
+	}
+	return uint32(height), nil
+}
+
+// loadMarkets loads all perpetual markets into the cache
+func (t *DydxTrader) loadMarkets() (map[string]*DydxMarket, error) {
+	t.marketsCacheMutex.RLock()
+	if len(t.marketsCache) > 0 && time.Since(t.marketsCacheTime) < t.cacheDuration {
+		markets := t.marketsCache
+		t.marketsCacheMutex.RUnlock()
+		return markets, nil
+	}
+	t.marketsCacheMutex.RUnlock()
+
+	var resp struct {
+		Markets map[string]struct {
+			Ticker                    string `json:"ticker"`
+			Status                    string `json:"status"`
+			ClobPairID                string `json:"clobPairId"`
+			OraclePrice               string `json:"oraclePrice"`
+			TickSize                  string `json:"tickSize"`
+			StepSize                  string `json:"stepSize"`
+			AtomicResolution          int    `json:"atomicResolution"`
+			QuantumConversionExponent int    `json:"quantumConversionExponent"`
+			StepBaseQuantums          uint64 `json:"stepBaseQuantums"`
+			SubticksPerTick           uint64 `json:"subticksPerTick"`
+			InitialMarginFraction     string `json:"initialMarginFraction"`
+		} `json:"markets"`
+	}
+	if err := t.indexerGet("/perpetualMarkets", nil, &resp); err != nil {
+		return nil, fmt.Errorf("failed to get markets: %w", err)
+	}
+
+	markets := make(map[string]*DydxMarket, len(resp.Markets))
+	for ticker, m := range resp.Markets {
+		clobPairID, _ := strconv.ParseUint(m.ClobPairID, 10, 32)
+		markets[ticker] = &DydxMarket{
+			Ticker:                    ticker,
+			ClobPairID:                uint32(clobPairID),
+			OraclePrice:               parseFloatOrZero(m.OraclePrice),
+			TickSize:                  parseFloatOrZero(m.TickSize),
+			StepSize:                  parseFloatOrZero(m.StepSize),
+			AtomicResolution:          m.AtomicResolution,
+			QuantumConversionExponent: m.QuantumConversionExponent,
+			StepBaseQuantums:          m.StepBaseQuantums,
+			SubticksPerTick:           m.SubticksPerTick,
+			InitialMarginFraction:     parseFloatOrZero(m.InitialMarginFraction),
+			Active:                    m.Status == "ACTIVE",
+		}
+	}
+
+	t.marketsCacheMutex.Lock()
+	t.marketsCache = markets
+	t.marketsCacheTime = time.Now()
+	t.marketsCacheMutex.Unlock()
+
+	return markets, nil
+}
+
+// getMarket gets market info of a symbol
+func (t *DydxTrader) getMarket(symbol string) (*DydxMarket, error) {
+	markets, err := t.loadMarkets()
+	if err != nil {
+		return nil, err
+	}
+	market, ok := markets[t.convertSymbol(symbol)]
+	if !ok {
+		return nil, fmt.Errorf("dYdX market not found for %s", symbol)
+	}
+	return market, nil
+}
+
+// toQuantums converts a base asset size to quantums, rounded down to the market step
+func (m *DydxMarket) toQuantums(size float64) (uint64, error) {
+	raw := size * math.Pow10(-m.AtomicResolution)
+	step := m.StepBaseQuantums
+	if step == 0 {
+		step = 1
+	}
+	quantums := uint64(math.Floor(raw/float64(step)+1e-9)) * step
+	if quantums == 0 {
+		return 0, fmt.Errorf("%s size %v is below the minimum of %v", m.Ticker, size, m.StepSize)
+	}
+	return quantums, nil
+}
+
+// toSubticks converts a price to subticks, rounded to the market tick
+func (m *DydxMarket) toSubticks(price float64) uint64 {
+	exponent := m.AtomicResolution - m.QuantumConversionExponent - dydxQuoteAtomicResolution
+	raw := price * math.Pow10(exponent)
+	tick := m.SubticksPerTick
+	if tick == 0 {
+		tick = 1
+	}
+	subticks := uint64(math.Round(raw/float64(tick))) * tick
+	if subticks < tick {
+		subticks = tick
+	}
+	return subticks
+}
+
+// GetMarketPrice gets market price (oracle price)
+func (t *DydxTrader) GetMarketPrice(symbol string) (float64, error) {
+	market, err := t.getMarket(symbol)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get price: %w", err)
+	}
+	if market.OraclePrice <= 0 {
+		return 0, fmt.Errorf("no price data received for %s", symbol)
+	}
+	return market.OraclePrice, nil
+}
+
+// FormatQuantity formats quantity to the market step size of the instrument registry
+func (t *DydxTrader) FormatQuantity(symbol string, quantity float64) (string, error) {
+	inst, err := venueInstrument(Venue{Exchange: "dydx", Testnet: t.testnet}, t, symbol)
+	if err != nil {
+		return fmt.Sprintf("%.4f", quantity), nil
+	}
+	return inst.FormatQuantity(quantity), nil
+}
+
+// GetInstruments gets trading rules of all active markets (implements InstrumentProvider)
+func (t *DydxTrader) GetInstruments() ([]Instrument, error) {
+	markets, err := t.loadMarkets()
+	if err != nil {
+		return nil, err
+	}
+
+	var instruments []Instrument
+	for _, m := range markets {
+		if !m.Active {
+			continue
+		}
+		maxLeverage := 0
+		if m.InitialMarginFraction > 0 {
+			maxLeverage = int(math.Round(1 / m.InitialMarginFraction))
+		}
+		instruments = append(instruments, Instrument{
+			Symbol:             CanonicalSymbol("dydx", m.Ticker),
+			VenueSymbol:        m.Ticker,
+			TickSize:           m.TickSize,
+			LotStep:            m.StepSize,
+			MinQty:             m.StepSize,
+			MaxLeverage:        maxLeverage,
+			ContractMultiplier: 1,
+		})
+	}
+	return instruments, nil
+}
+
+// SetLeverage records leverage for a symbol
+// dYdX has no per-market leverage setting: margin requirements follow position size and collateral
+func (t *DydxTrader) SetLeverage(symbol string, leverage int) error {
+	t.leveragesMutex.Lock()
+	t.leverages[symbol] = leverage
+	t.leveragesMutex.Unlock()
+
+	logger.Infof("  ✓ [dYdX] %s leverage noted as %dx (dYdX sizes margin by position, no exchange setting)", symbol, leverage)
+	return nil
+}
+
+// getLeverage gets the recorded leverage of a symbol (0 if never set)
+func (t *DydxTrader) getLeverage(symbol string) int {
+	t.leveragesMutex.RLock()
+	defer t.leveragesMutex.RUnlock()
+	return t.leverages[symbol]
+}
+
+// SetMarginMode sets margin mode
+// Subaccounts 0-127 are always cross margin; isolated positions would need a dedicated subaccount
+func (t *DydxTrader) SetMarginMode(symbol string, isCrossMargin bool) error {
+	if !isCrossMargin {
+		logger.Infof("  ⚠️ [dYdX] Isolated margin is not supported on subaccount %d, %s stays cross margin", t.subaccount, symbol)
+	}
+	return nil
+}
+
+// clearCache clears all caches
+func (t *DydxTrader) clearCache() {
+	t.balanceCacheMutex.Lock()
+	t.cachedBalance = nil
+	t.balanceCacheMutex.Unlock()
+
+	t.positionsCacheMutex.Lock()
+	t.cachedPositions = nil
+	t.positionsCacheMutex.Unlock()
+}
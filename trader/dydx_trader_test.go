@@ -0,0 +1,542 @@
+package trader
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const dydxTestPrivateKey = "4c0883a69102937d6231471b5dbb6204fe5129617082792ae468d01a3f362318"
+
+// ============================================================
+// 1. DydxTraderTestSuite - inherits base test suite
+// ============================================================
+
+// dydxSentTx a transaction received by the stub validator
+type dydxSentTx struct {
+	TypeURL  string
+	Msg      []byte
+	GasLimit uint64
+}
+
+// DydxTraderTestSuite dYdX trader test suite
+// Inherits TraderTestSuite; one stub server plays both the indexer (/v4) and the validator (/cosmos)
+type DydxTraderTestSuite struct {
+	*TraderTestSuite // Embeds base test suite
+	mockServer       *httptest.Server
+	dydxTrader       *DydxTrader
+
+	mu      sync.Mutex
+	sentTxs []dydxSentTx
+}
+
+// NewDydxTraderTestSuite creates dYdX test suite
+func NewDydxTraderTestSuite(t *testing.T) *DydxTraderTestSuite {
+	suite := &DydxTraderTestSuite{}
+
+	key, err := crypto.HexToECDSA(dydxTestPrivateKey)
+	require.NoError(t, err)
+	address := cosmosAddress(dydxAddressPrefix, &key.PublicKey)
+
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path := r.URL.Path
+		query := r.URL.Query()
+
+		var respBody interface{}
+
+		switch {
+		// Indexer: block height
+		case path == "/v4/height":
+			respBody = map[string]interface{}{"height": "1000", "time": "2024-01-01T00:00:00.000Z"}
+
+		// Indexer: markets
+		case path == "/v4/perpetualMarkets":
+			respBody = map[string]interface{}{
+				"markets": map[string]interface{}{
+					"BTC-USD": map[string]interface{}{
+						"ticker": "BTC-USD", "status": "ACTIVE", "clobPairId": "0", "oraclePrice": "50000",
+						"tickSize": "1", "stepSize": "0.0001", "atomicResolution": -10, "quantumConversionExponent": -9,
+						"stepBaseQuantums": 1000000, "subticksPerTick": 100000, "initialMarginFraction": "0.05",
+					},
+					"ETH-USD": map[string]interface{}{
+						"ticker": "ETH-USD", "status": "ACTIVE", "clobPairId": "1", "oraclePrice": "3000",
+						"tickSize": "0.1", "stepSize": "0.001", "atomicResolution": -9, "quantumConversionExponent": -9,
+						"stepBaseQuantums": 1000000, "subticksPerTick": 100000, "initialMarginFraction": "0.05",
+					},
+					"OLD-USD": map[string]interface{}{
+						"ticker": "OLD-USD", "status": "FINAL_SETTLEMENT", "clobPairId": "99", "oraclePrice": "1",
+						"tickSize": "0.0001", "stepSize": "1", "atomicResolution": -6, "quantumConversionExponent": -9,
+						"stepBaseQuantums": 1000000, "subticksPerTick": 1000000, "initialMarginFraction": "0.2",
+					},
+				},
+			}
+
+		// Indexer: subaccount
+		case path == "/v4/addresses/"+address+"/subaccountNumber/0":
+			respBody = map[string]interface{}{
+				"subaccount": map[string]interface{}{
+					"address":        address,
+					"equity":         "10100.5",
+					"freeCollateral": "8000",
+					"openPerpetualPositions": map[string]interface{}{
+						"BTC-USD": map[string]interface{}{
+							"market": "BTC-USD", "status": "OPEN", "side": "LONG", "size": "0.5",
+							"entryPrice": "50000", "unrealizedPnl": "100.5", "createdAt": "2024-01-01T00:00:00.000Z",
+						},
+					},
+				},
+			}
+
+		// Indexer: orders
+		case path == "/v4/orders":
+			orders := []map[string]interface{}{}
+			if query.Get("ticker") == "BTC-USD" {
+				switch query.Get("status") {
+				case "UNTRIGGERED":
+					orders = append(orders,
+						map[string]interface{}{"id": "sl-1", "clientId": "77", "clobPairId": "0", "orderFlags": "32", "ticker": "BTC-USD",
+							"type": "STOP_MARKET", "status": "UNTRIGGERED", "goodTilBlockTime": "2030-01-01T00:00:00.000Z"},
+						map[string]interface{}{"id": "tp-1", "clientId": "78", "clobPairId": "0", "orderFlags": "32", "ticker": "BTC-USD",
+							"type": "TAKE_PROFIT_MARKET", "status": "UNTRIGGERED", "goodTilBlockTime": "2030-01-01T00:00:00.000Z"},
+					)
+				case "":
+					orders = append(orders, dydxTestFilledOrder())
+				}
+			}
+			respBody = orders
+
+		// Indexer: order by ID
+		case path == "/v4/orders/abc-uuid":
+			respBody = dydxTestFilledOrder()
+
+		// Indexer: fills (newest first)
+		case path == "/v4/fills":
+			respBody = map[string]interface{}{
+				"fills": []map[string]interface{}{
+					{"id": "f3", "side": "SELL", "market": "ETH-USD", "price": "3010", "size": "0.002", "fee": "0.1", "orderId": "abc-uuid", "createdAt": "2024-01-01T01:00:01.000Z"},
+					{"id": "f2", "side": "SELL", "market": "ETH-USD", "price": "3000", "size": "0.002", "fee": "0.1", "orderId": "abc-uuid", "createdAt": "2024-01-01T01:00:00.000Z"},
+					{"id": "f1", "side": "BUY", "market": "BTC-USD", "price": "50000", "size": "0.5", "fee": "5", "orderId": "other", "createdAt": "2023-12-31T00:00:00.000Z"},
+				},
+			}
+
+		// Indexer: closed positions
+		case path == "/v4/perpetualPositions":
+			respBody = map[string]interface{}{
+				"positions": []map[string]interface{}{
+					{"market": "ETH-USD", "status": "CLOSED", "side": "SHORT", "size": "0", "maxSize": "-0.1",
+						"entryPrice": "3000", "exitPrice": "2900", "realizedPnl": "9.5", "sumClose": "0.1",
+						"createdAt": "2024-01-01T00:00:00.000Z", "closedAt": "2024-01-01T02:00:00.000Z"},
+					{"market": "BTC-USD", "status": "LIQUIDATED", "side": "LONG", "size": "0", "maxSize": "0.2",
+						"entryPrice": "50000", "exitPrice": "45000", "realizedPnl": "-1000", "sumClose": "0.2",
+						"createdAt": "2024-01-01T00:00:00.000Z", "closedAt": "2024-01-01T03:00:00.000Z"},
+					{"market": "BTC-USD", "status": "CLOSED", "side": "LONG", "size": "0", "maxSize": "0.1",
+						"entryPrice": "40000", "exitPrice": "41000", "realizedPnl": "100", "sumClose": "0.1",
+						"createdAt": "2023-01-01T00:00:00.000Z", "closedAt": "2023-01-02T00:00:00.000Z"},
+				},
+			}
+
+		// Validator: account
+		case path == "/cosmos/auth/v1beta1/accounts/"+address:
+			respBody = map[string]interface{}{
+				"account": map[string]interface{}{"address": address, "account_number": "42", "sequence": "7"},
+			}
+
+		// Validator: simulate
+		case path == "/cosmos/tx/v1beta1/simulate":
+			respBody = map[string]interface{}{"gas_info": map[string]interface{}{"gas_used": "100000"}}
+
+		// Validator: broadcast (signature verified against the account's public key)
+		case path == "/cosmos/tx/v1beta1/txs":
+			bodyBytes, _ := io.ReadAll(r.Body)
+			var req struct {
+				TxBytes string `json:"tx_bytes"`
+				Mode    string `json:"mode"`
+			}
+			json.Unmarshal(bodyBytes, &req)
+			txBytes, _ := base64.StdEncoding.DecodeString(req.TxBytes)
+
+			sent, ok := dydxVerifyTx(txBytes, "dydx-testnet-4", 42, 7)
+			if !ok || req.Mode != "BROADCAST_MODE_SYNC" {
+				respBody = map[string]interface{}{"tx_response": map[string]interface{}{"code": 4, "raw_log": "signature verification failed"}}
+				break
+			}
+			suite.mu.Lock()
+			suite.sentTxs = append(suite.sentTxs, sent)
+			suite.mu.Unlock()
+			respBody = map[string]interface{}{"tx_response": map[string]interface{}{"code": 0, "txhash": "ABCDEF"}}
+
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			respBody = map[string]interface{}{"errors": []map[string]interface{}{{"msg": "not found"}}}
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(respBody)
+	}))
+
+	trader := &DydxTrader{
+		privateKey:    key,
+		address:       address,
+		chainID:       "dydx-testnet-4",
+		indexerURL:    mockServer.URL + "/v4",
+		validatorURL:  mockServer.URL,
+		client:        mockServer.Client(),
+		marketsCache:  make(map[string]*DydxMarket),
+		leverages:     make(map[string]int),
+		cacheDuration: 15 * time.Second,
+	}
+
+	suite.TraderTestSuite = NewTraderTestSuite(t, trader)
+	suite.mockServer = mockServer
+	suite.dydxTrader = trader
+	return suite
+}
+
+// dydxTestFilledOrder a short-term IOC order that filled 0.004 ETH before expiring
+func dydxTestFilledOrder() map[string]interface{} {
+	return map[string]interface{}{
+		"id": "abc-uuid", "clientId": "305419896", "clobPairId": "1", "orderFlags": "0", "ticker": "ETH-USD",
+		"side": "SELL", "size": "0.01", "totalFilled": "0.004", "type": "MARKET", "status": "BEST_EFFORT_CANCELED",
+		"updatedAt": "2024-01-01T01:00:01.000Z",
+	}
+}
+
+// Cleanup cleans up resources
+func (s *DydxTraderTestSuite) Cleanup() {
+	if s.mockServer != nil {
+		s.mockServer.Close()
+	}
+	s.TraderTestSuite.Cleanup()
+}
+
+// lastTx returns the last broadcast transaction
+func (s *DydxTraderTestSuite) lastTx() dydxSentTx {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if len(s.sentTxs) == 0 {
+		return dydxSentTx{}
+	}
+	return s.sentTxs[len(s.sentTxs)-1]
+}
+
+// protoFields decodes a protobuf message into field number -> values
+// Varint and fixed32 values are returned in v, length-delimited values in b
+type protoField struct {
+	v uint64
+	b []byte
+}
+
+func protoFields(data []byte) map[int][]protoField {
+	fields := make(map[int][]protoField)
+	for len(data) > 0 {
+		tag, n := binary.Uvarint(data)
+		data = data[n:]
+		field := protoField{}
+		switch tag & 7 {
+		case 0:
+			field.v, n = binary.Uvarint(data)
+			data = data[n:]
+		case 2:
+			length, n := binary.Uvarint(data)
+			field.b = data[n : n+int(length)]
+			data = data[n+int(length):]
+		case 5:
+			field.v = uint64(binary.LittleEndian.Uint32(data))
+			data = data[4:]
+		default:
+			return fields
+		}
+		fields[int(tag>>3)] = append(fields[int(tag>>3)], field)
+	}
+	return fields
+}
+
+// first returns the first value of a field (zero value when absent)
+func first(fields map[int][]protoField, num int) protoField {
+	if len(fields[num]) == 0 {
+		return protoField{}
+	}
+	return fields[num][0]
+}
+
+// dydxVerifyTx decodes a TxRaw and verifies its SIGN_MODE_DIRECT signature
+func dydxVerifyTx(txBytes []byte, chainID string, accountNumber, sequence uint64) (dydxSentTx, bool) {
+	raw := protoFields(txBytes)
+	body, authInfo, sig := first(raw, 1).b, first(raw, 2).b, first(raw, 3).b
+
+	auth := protoFields(authInfo)
+	signer := protoFields(first(auth, 1).b)
+	if first(signer, 3).v != sequence {
+		return dydxSentTx{}, false
+	}
+	pubKeyAny := protoFields(first(signer, 1).b)
+	if string(first(pubKeyAny, 1).b) != dydxPubKeyType {
+		return dydxSentTx{}, false
+	}
+	pubKey := first(protoFields(first(pubKeyAny, 2).b), 1).b
+
+	hash := sha256.Sum256(encodeSignDoc(body, authInfo, chainID, accountNumber))
+	if len(sig) != 64 || !crypto.VerifySignature(pubKey, hash[:], sig) {
+		return dydxSentTx{}, false
+	}
+
+	msgAny := protoFields(first(protoFields(body), 1).b)
+	return dydxSentTx{
+		TypeURL:  string(first(msgAny, 1).b),
+		Msg:      first(msgAny, 2).b,
+		GasLimit: first(protoFields(first(auth, 2).b), 2).v,
+	}, true
+}
+
+// decodeTestOrder decodes the order of a MsgPlaceOrder into (order fields, order ID fields)
+func decodeTestOrder(msg []byte) (map[int][]protoField, map[int][]protoField) {
+	order := protoFields(first(protoFields(msg), 1).b)
+	return order, protoFields(first(order, 1).b)
+}
+
+// ============================================================
+// 2. Run common tests using DydxTraderTestSuite
+// ============================================================
+
+// TestDydxTrader_InterfaceCompliance tests interface compliance
+func TestDydxTrader_InterfaceCompliance(t *testing.T) {
+	var _ Trader = (*DydxTrader)(nil)
+	var _ ClientOrderTrader = (*DydxTrader)(nil)
+	var _ InstrumentProvider = (*DydxTrader)(nil)
+}
+
+// TestDydxTrader_CommonInterface runs all common interface tests using test suite
+func TestDydxTrader_CommonInterface(t *testing.T) {
+	suite := NewDydxTraderTestSuite(t)
+	defer suite.Cleanup()
+
+	suite.RunAllTests()
+}
+
+// ============================================================
+// 3. dYdX specific unit tests
+// ============================================================
+
+// TestDydxKeyDerivation tests mnemonic derivation along m/44'/118'/0'/0/0 and bech32 addresses
+func TestDydxKeyDerivation(t *testing.T) {
+	mnemonic := "abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon about"
+	key, err := dydxKeyFromSecret(mnemonic)
+	require.NoError(t, err)
+	assert.Equal(t, "cosmos19rl4cm2hmr8afy4kldpxz3fka4jguq0auqdal4", cosmosAddress("cosmos", &key.PublicKey))
+
+	trader, err := NewDydxTrader(mnemonic, "", 0, true)
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(trader.address, "dydx1"))
+	assert.Equal(t, "dydx-testnet-4", trader.chainID)
+
+	_, err = NewDydxTrader(mnemonic, "dydx1wrongaddress", 0, false)
+	assert.ErrorContains(t, err, "address mismatch")
+
+	_, err = dydxKeyFromSecret("abandon abandon")
+	assert.Error(t, err)
+}
+
+// TestDydxTrader_MarketOrderEncoding tests short-term IOC orders are encoded in quantums and subticks
+func TestDydxTrader_MarketOrderEncoding(t *testing.T) {
+	suite := NewDydxTraderTestSuite(t)
+	defer suite.Cleanup()
+
+	result, err := suite.dydxTrader.OpenLongWithClientID("BTCUSDT", 0.01234, 5, "12345678abcdef0123456789abcdef01")
+	require.NoError(t, err)
+	assert.Equal(t, "305419896", result["orderId"]) // 0x12345678
+
+	tx := suite.lastTx()
+	assert.Equal(t, dydxMsgPlaceOrder, tx.TypeURL)
+	assert.Zero(t, tx.GasLimit) // Short-term orders are gasless
+
+	order, id := decodeTestOrder(tx.Msg)
+	assert.Equal(t, uint64(0x12345678), first(id, 2).v)
+	assert.Equal(t, uint64(dydxOrderFlagsShortTerm), first(id, 3).v)
+	assert.Equal(t, uint64(dydxSideBuy), first(order, 2).v)
+	assert.Equal(t, uint64(123000000), first(order, 3).v)  // 0.0123 BTC at 1e-10 resolution, rounded down to step
+	assert.Equal(t, uint64(5250000000), first(order, 4).v) // 52500 (oracle + 5%) at 1e5 subticks per dollar
+	assert.Equal(t, uint64(1010), first(order, 5).v)       // Block height + 10
+	assert.Equal(t, uint64(dydxTimeInForceIOC), first(order, 7).v)
+	assert.Zero(t, first(order, 8).v) // Not reduce-only
+
+	_, err = suite.dydxTrader.CloseLong("BTCUSDT", 0)
+	require.NoError(t, err)
+	suite.mu.Lock()
+	var closeMsg []byte
+	for _, sent := range suite.sentTxs {
+		if sent.TypeURL == dydxMsgPlaceOrder {
+			closeMsg = sent.Msg
+		}
+	}
+	suite.mu.Unlock()
+	order, id = decodeTestOrder(closeMsg)
+	assert.Equal(t, uint64(dydxSideSell), first(order, 2).v)
+	assert.Equal(t, uint64(5000000000), first(order, 3).v) // Whole 0.5 BTC position
+	assert.Equal(t, uint64(4750000000), first(order, 4).v) // Oracle - 5%
+	assert.Equal(t, uint64(1), first(order, 8).v)          // Reduce-only
+
+	_, err = suite.dydxTrader.OpenLong("BTCUSDT", 0.00001, 5)
+	assert.ErrorContains(t, err, "below the minimum")
+}
+
+// TestDydxTrader_ConditionalOrders tests SL/TP are conditional orders paying simulated gas
+func TestDydxTrader_ConditionalOrders(t *testing.T) {
+	suite := NewDydxTraderTestSuite(t)
+	defer suite.Cleanup()
+
+	require.NoError(t, suite.dydxTrader.SetStopLoss("ETHUSDT", "LONG", 0.1, 2800))
+	tx := suite.lastTx()
+	assert.Equal(t, uint64(140000), tx.GasLimit) // Simulated gas * 1.4
+
+	order, id := decodeTestOrder(tx.Msg)
+	assert.Equal(t, uint64(dydxOrderFlagsConditional), first(id, 3).v)
+	assert.Equal(t, uint64(1), first(id, 4).v) // ETH clob pair
+	assert.Equal(t, uint64(dydxSideSell), first(order, 2).v)
+	assert.Equal(t, uint64(100000000), first(order, 3).v)  // 0.1 ETH at 1e-9 resolution
+	assert.Equal(t, uint64(2520000000), first(order, 4).v) // Trigger - 10%
+	assert.Greater(t, first(order, 6).v, uint64(time.Now().Unix()))
+	assert.Equal(t, uint64(1), first(order, 8).v) // Reduce-only
+	assert.Equal(t, uint64(dydxConditionStopLoss), first(order, 10).v)
+	assert.Equal(t, uint64(2800000000), first(order, 11).v)
+
+	require.NoError(t, suite.dydxTrader.SetTakeProfit("ETHUSDT", "SHORT", 0.1, 2500))
+	order, _ = decodeTestOrder(suite.lastTx().Msg)
+	assert.Equal(t, uint64(dydxSideBuy), first(order, 2).v)
+	assert.Equal(t, uint64(dydxConditionTakeProfit), first(order, 10).v)
+}
+
+// TestDydxTrader_CancelStopLossOrders tests only stop-loss orders are cancelled
+func TestDydxTrader_CancelStopLossOrders(t *testing.T) {
+	suite := NewDydxTraderTestSuite(t)
+	defer suite.Cleanup()
+
+	require.NoError(t, suite.dydxTrader.CancelStopLossOrders("BTCUSDT"))
+
+	suite.mu.Lock()
+	defer suite.mu.Unlock()
+	require.Len(t, suite.sentTxs, 1)
+	assert.Equal(t, dydxMsgCancelOrder, suite.sentTxs[0].TypeURL)
+
+	msg := protoFields(suite.sentTxs[0].Msg)
+	id := protoFields(first(msg, 1).b)
+	assert.Equal(t, uint64(77), first(id, 2).v)
+	assert.Equal(t, uint64(dydxOrderFlagsConditional), first(id, 3).v)
+	assert.Equal(t, uint64(time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC).Unix()), first(msg, 3).v)
+}
+
+// TestDydxTrader_GetOrderStatus tests order status with average price and fees from fills
+func TestDydxTrader_GetOrderStatus(t *testing.T) {
+	suite := NewDydxTraderTestSuite(t)
+	defer suite.Cleanup()
+
+	status, err := suite.dydxTrader.GetOrderStatus("ETHUSDT", "abc-uuid")
+	require.NoError(t, err)
+	assert.Equal(t, "FILLED", status["status"]) // Partially filled IOC
+	assert.InDelta(t, 0.004, status["executedQty"], 1e-9)
+	assert.InDelta(t, 3005.0, status["avgPrice"], 1e-9)
+	assert.InDelta(t, 0.2, status["commission"], 1e-9)
+
+	status, err = suite.dydxTrader.GetOrderByClientID("BTCUSDT", "12345678abcdef0123456789abcdef01")
+	require.NoError(t, err)
+	assert.Equal(t, "abc-uuid", status["orderId"])
+	assert.Equal(t, "12345678abcdef0123456789abcdef01", status["clientOrderId"])
+}
+
+// TestDydxTrader_Account tests balance and position mapping
+func TestDydxTrader_Account(t *testing.T) {
+	suite := NewDydxTraderTestSuite(t)
+	defer suite.Cleanup()
+
+	balance, err := suite.dydxTrader.GetBalance()
+	require.NoError(t, err)
+	assert.Equal(t, 10100.5, balance["total_equity"])
+	assert.Equal(t, 10000.0, balance["totalWalletBalance"])
+	assert.Equal(t, 8000.0, balance["availableBalance"])
+
+	positions, err := suite.dydxTrader.GetPositions()
+	require.NoError(t, err)
+	require.Len(t, positions, 1)
+	assert.Equal(t, "BTCUSDT", positions[0]["symbol"])
+	assert.Equal(t, "long", positions[0]["side"])
+	assert.Equal(t, 0.5, positions[0]["positionAmt"])
+	assert.Equal(t, 50000.0, positions[0]["markPrice"])
+	assert.Equal(t, 2.0, positions[0]["leverage"]) // 25000 notional / 10100.5 equity
+}
+
+// TestDydxTrader_GetClosedPnL tests closed position mapping from the indexer
+func TestDydxTrader_GetClosedPnL(t *testing.T) {
+	suite := NewDydxTraderTestSuite(t)
+	defer suite.Cleanup()
+
+	records, err := suite.dydxTrader.GetClosedPnL(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), 10)
+	require.NoError(t, err)
+	require.Len(t, records, 2) // 2023 position filtered out
+
+	assert.Equal(t, "ETHUSDT", records[0].Symbol)
+	assert.Equal(t, "short", records[0].Side)
+	assert.Equal(t, 3000.0, records[0].EntryPrice)
+	assert.Equal(t, 2900.0, records[0].ExitPrice)
+	assert.Equal(t, 0.1, records[0].Quantity)
+	assert.Equal(t, 9.5, records[0].RealizedPnL)
+
+	assert.Equal(t, "BTCUSDT", records[1].Symbol)
+	assert.Equal(t, "liquidation", records[1].CloseType)
+}
+
+// TestDydxTrader_GetTrades tests fill history mapping
+func TestDydxTrader_GetTrades(t *testing.T) {
+	suite := NewDydxTraderTestSuite(t)
+	defer suite.Cleanup()
+
+	trades, err := suite.dydxTrader.GetTrades(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), 100)
+	require.NoError(t, err)
+	require.Len(t, trades, 2)
+
+	// Oldest first
+	assert.Equal(t, "f2", trades[0].TradeID)
+	assert.Equal(t, "ETHUSDT", trades[0].Symbol)
+	assert.Equal(t, "SELL", trades[0].Side)
+	assert.Equal(t, "BOTH", trades[0].PositionSide)
+	assert.Equal(t, 0.1, trades[0].Fee)
+}
+
+// TestDydxTrader_GetInstruments tests market rules
+func TestDydxTrader_GetInstruments(t *testing.T) {
+	suite := NewDydxTraderTestSuite(t)
+	defer suite.Cleanup()
+
+	instruments, err := suite.dydxTrader.GetInstruments()
+	require.NoError(t, err)
+	require.Len(t, instruments, 2) // Settled market skipped
+
+	bySymbol := make(map[string]Instrument)
+	for _, inst := range instruments {
+		bySymbol[inst.Symbol] = inst
+	}
+	btc := bySymbol["BTCUSDT"]
+	assert.Equal(t, "BTC-USD", btc.VenueSymbol)
+	assert.Equal(t, 0.0001, btc.LotStep)
+	assert.Equal(t, 1.0, btc.TickSize)
+	assert.Equal(t, 20, btc.MaxLeverage)
+}
+
+// TestDydxSymbolMapping tests canonical and dYdX ticker conversion
+func TestDydxSymbolMapping(t *testing.T) {
+	assert.Equal(t, "BTC-USD", VenueSymbol("dydx", "btcusdt"))
+	assert.Equal(t, "BTC-USD", VenueSymbol("dydx", "BTC-USD"))
+	assert.Equal(t, "BTCUSDT", CanonicalSymbol("dydx", "BTC-USD"))
+	assert.Equal(t, uint32(0x12345678), dydxClientID("12345678abcdef"))
+	assert.Equal(t, dydxClientID("not-hex"), dydxClientID("not-hex"))
+}
@@ -0,0 +1,492 @@
+package trader
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"crypto/hmac"
+	"crypto/pbkdf2"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/big"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/crypto"
+	"golang.org/x/crypto/ripemd160"
+)
+
+// dYdX v4 chain transaction encoding and signing
+// Orders are Cosmos SDK transactions (SIGN_MODE_DIRECT) broadcast to a validator REST endpoint.
+// Only the handful of protobuf messages the adapter needs are encoded here, by hand.
+
+const (
+	dydxAddressPrefix   = "dydx"
+	dydxMsgPlaceOrder   = "/dydxprotocol.clob.MsgPlaceOrder"
+	dydxMsgCancelOrder  = "/dydxprotocol.clob.MsgCancelOrder"
+	dydxPubKeyType      = "/cosmos.crypto.secp256k1.PubKey"
+	dydxSignModeDirect  = 1
+	dydxGasPriceUUSDC   = 0.025 // Minimum gas price in micro-USDC
+	dydxGasAdjustment   = 1.4
+	dydxUSDCDenom       = "ibc/8E27BA2D5493AF5636760E354E46004562C46AB7EC0CC4C1CA14E9E20E2545B5"
+	bip32HardenedOffset = 0x80000000
+)
+
+// dYdX order enums
+const (
+	dydxSideBuy  = 1
+	dydxSideSell = 2
+
+	dydxTimeInForceIOC = 1
+
+	dydxConditionStopLoss   = 1
+	dydxConditionTakeProfit = 2
+
+	dydxOrderFlagsShortTerm   = 0
+	dydxOrderFlagsConditional = 32
+)
+
+// dydxOrderID identifies an order on chain
+type dydxOrderID struct {
+	Owner            string
+	SubaccountNumber uint32
+	ClientID         uint32
+	OrderFlags       uint32
+	ClobPairID       uint32
+}
+
+// dydxOrderMsg a dydxprotocol.clob.Order
+type dydxOrderMsg struct {
+	ID               dydxOrderID
+	Side             int
+	Quantums         uint64
+	Subticks         uint64
+	GoodTilBlock     uint32 // Short-term orders
+	GoodTilBlockTime uint32 // Stateful (conditional) orders, unix seconds
+	TimeInForce      int
+	ReduceOnly       bool
+	ConditionType    int
+	TriggerSubticks  uint64
+}
+
+// ============================================================
+// Keys and addresses
+// ============================================================
+
+// dydxKeyFromSecret parses a hex private key or derives one from a BIP39 mnemonic (m/44'/118'/0'/0/0)
+func dydxKeyFromSecret(secret string) (*ecdsa.PrivateKey, error) {
+	secret = strings.TrimSpace(secret)
+	words := strings.Fields(strings.ToLower(secret))
+	if len(words) == 1 {
+		key, err := crypto.HexToECDSA(strings.TrimPrefix(words[0], "0x"))
+		if err != nil {
+			return nil, fmt.Errorf("invalid dYdX private key: %w", err)
+		}
+		return key, nil
+	}
+	if len(words) != 12 && len(words) != 24 {
+		return nil, fmt.Errorf("invalid dYdX mnemonic: expected 12 or 24 words, got %d", len(words))
+	}
+
+	seed, err := pbkdf2.Key(sha512.New, strings.Join(words, " "), []byte("mnemonic"), 2048, 64)
+	if err != nil {
+		return nil, fmt.Errorf("failed to derive seed: %w", err)
+	}
+	return bip32Derive(seed, []uint32{
+		44 + bip32HardenedOffset, 118 + bip32HardenedOffset, bip32HardenedOffset, 0, 0,
+	})
+}
+
+// bip32Derive derives a secp256k1 private key from a seed along a BIP32 path
+func bip32Derive(seed []byte, path []uint32) (*ecdsa.PrivateKey, error) {
+	mac := hmac.New(sha512.New, []byte("Bitcoin seed"))
+	mac.Write(seed)
+	sum := mac.Sum(nil)
+	key, chainCode := sum[:32], sum[32:]
+
+	n := crypto.S256().Params().N
+	for _, index := range path {
+		var data []byte
+		if index >= bip32HardenedOffset {
+			data = append([]byte{0}, key...)
+		} else {
+			parent, err := crypto.ToECDSA(key)
+			if err != nil {
+				return nil, err
+			}
+			data = crypto.CompressPubkey(&parent.PublicKey)
+		}
+		data = binary.BigEndian.AppendUint32(data, index)
+
+		mac := hmac.New(sha512.New, chainCode)
+		mac.Write(data)
+		sum := mac.Sum(nil)
+
+		child := new(big.Int).SetBytes(sum[:32])
+		if child.Cmp(n) >= 0 {
+			return nil, fmt.Errorf("invalid derived key at index %d", index)
+		}
+		child.Add(child, new(big.Int).SetBytes(key))
+		child.Mod(child, n)
+		if child.Sign() == 0 {
+			return nil, fmt.Errorf("invalid derived key at index %d", index)
+		}
+		key = child.FillBytes(make([]byte, 32))
+		chainCode = sum[32:]
+	}
+	return crypto.ToECDSA(key)
+}
+
+// cosmosAddress bech32 account address of a secp256k1 public key
+func cosmosAddress(prefix string, pub *ecdsa.PublicKey) string {
+	sha := sha256.Sum256(crypto.CompressPubkey(pub))
+	hasher := ripemd160.New()
+	hasher.Write(sha[:])
+	return bech32Encode(prefix, hasher.Sum(nil))
+}
+
+// bech32Encode encodes data with a human-readable prefix (BIP173)
+func bech32Encode(hrp string, data []byte) string {
+	const charset = "qpzry9x8gf2tvdw0s3jn54khce6mua7l"
+
+	// Regroup 8-bit bytes into 5-bit words
+	var words []byte
+	acc, bits := 0, 0
+	for _, b := range data {
+		acc = acc<<8 | int(b)
+		bits += 8
+		for bits >= 5 {
+			bits -= 5
+			words = append(words, byte(acc>>bits&31))
+		}
+	}
+	if bits > 0 {
+		words = append(words, byte(acc<<(5-bits)&31))
+	}
+
+	values := make([]byte, 0, len(hrp)*2+1+len(words)+6)
+	for i := 0; i < len(hrp); i++ {
+		values = append(values, hrp[i]>>5)
+	}
+	values = append(values, 0)
+	for i := 0; i < len(hrp); i++ {
+		values = append(values, hrp[i]&31)
+	}
+	values = append(values, words...)
+	values = append(values, 0, 0, 0, 0, 0, 0)
+
+	polymod := bech32Polymod(values) ^ 1
+	var sb strings.Builder
+	sb.WriteString(hrp)
+	sb.WriteByte('1')
+	for _, w := range words {
+		sb.WriteByte(charset[w])
+	}
+	for i := 0; i < 6; i++ {
+		sb.WriteByte(charset[(polymod>>uint(5*(5-i)))&31])
+	}
+	return sb.String()
+}
+
+// bech32Polymod bech32 checksum
+func bech32Polymod(values []byte) uint32 {
+	gen := []uint32{0x3b6a57b2, 0x26508e6d, 0x1ea119fa, 0x3d4233dd, 0x2a1462b3}
+	chk := uint32(1)
+	for _, v := range values {
+		top := chk >> 25
+		chk = (chk&0x1ffffff)<<5 ^ uint32(v)
+		for i := 0; i < 5; i++ {
+			if (top>>uint(i))&1 == 1 {
+				chk ^= gen[i]
+			}
+		}
+	}
+	return chk
+}
+
+// ============================================================
+// Protobuf encoding
+// ============================================================
+
+// protoWriter minimal protobuf (proto3) encoder
+type protoWriter struct {
+	buf []byte
+}
+
+func (w *protoWriter) tag(field int, wireType int) {
+	w.buf = binary.AppendUvarint(w.buf, uint64(field<<3|wireType))
+}
+
+// uint writes a varint field (skipped when zero, like proto3)
+func (w *protoWriter) uint(field int, v uint64) {
+	if v == 0 {
+		return
+	}
+	w.tag(field, 0)
+	w.buf = binary.AppendUvarint(w.buf, v)
+}
+
+// bool writes a bool field (skipped when false)
+func (w *protoWriter) bool(field int, v bool) {
+	if v {
+		w.uint(field, 1)
+	}
+}
+
+// fixed32 writes a fixed32 field (skipped when zero)
+func (w *protoWriter) fixed32(field int, v uint32) {
+	if v == 0 {
+		return
+	}
+	w.tag(field, 5)
+	w.buf = binary.LittleEndian.AppendUint32(w.buf, v)
+}
+
+// bytes writes a length-delimited field (skipped when empty)
+func (w *protoWriter) bytes(field int, v []byte) {
+	if len(v) == 0 {
+		return
+	}
+	w.message(field, v)
+}
+
+// string writes a string field (skipped when empty)
+func (w *protoWriter) string(field int, v string) {
+	w.bytes(field, []byte(v))
+}
+
+// message writes an embedded message, even when empty (presence matters for sub-messages)
+func (w *protoWriter) message(field int, v []byte) {
+	w.tag(field, 2)
+	w.buf = binary.AppendUvarint(w.buf, uint64(len(v)))
+	w.buf = append(w.buf, v...)
+}
+
+// encodeOrderID encodes dydxprotocol.clob.OrderId
+func encodeOrderID(id dydxOrderID) []byte {
+	var subaccount protoWriter
+	subaccount.string(1, id.Owner)
+	subaccount.uint(2, uint64(id.SubaccountNumber))
+
+	var w protoWriter
+	w.message(1, subaccount.buf)
+	w.fixed32(2, id.ClientID)
+	w.uint(3, uint64(id.OrderFlags))
+	w.uint(4, uint64(id.ClobPairID))
+	return w.buf
+}
+
+// encodeMsgPlaceOrder encodes dydxprotocol.clob.MsgPlaceOrder
+func encodeMsgPlaceOrder(o dydxOrderMsg) []byte {
+	var order protoWriter
+	order.message(1, encodeOrderID(o.ID))
+	order.uint(2, uint64(o.Side))
+	order.uint(3, o.Quantums)
+	order.uint(4, o.Subticks)
+	if o.ID.OrderFlags == dydxOrderFlagsShortTerm {
+		order.uint(5, uint64(o.GoodTilBlock))
+	} else {
+		order.fixed32(6, o.GoodTilBlockTime)
+	}
+	order.uint(7, uint64(o.TimeInForce))
+	order.bool(8, o.ReduceOnly)
+	order.uint(10, uint64(o.ConditionType))
+	order.uint(11, o.TriggerSubticks)
+
+	var msg protoWriter
+	msg.message(1, order.buf)
+	return msg.buf
+}
+
+// encodeMsgCancelOrder encodes dydxprotocol.clob.MsgCancelOrder
+func encodeMsgCancelOrder(id dydxOrderID, goodTilBlock, goodTilBlockTime uint32) []byte {
+	var msg protoWriter
+	msg.message(1, encodeOrderID(id))
+	if id.OrderFlags == dydxOrderFlagsShortTerm {
+		msg.uint(2, uint64(goodTilBlock))
+	} else {
+		msg.fixed32(3, goodTilBlockTime)
+	}
+	return msg.buf
+}
+
+// encodeAny encodes google.protobuf.Any
+func encodeAny(typeURL string, value []byte) []byte {
+	var w protoWriter
+	w.string(1, typeURL)
+	w.bytes(2, value)
+	return w.buf
+}
+
+// encodeTxBody encodes cosmos.tx.v1beta1.TxBody with a single message
+func encodeTxBody(typeURL string, msg []byte) []byte {
+	var w protoWriter
+	w.message(1, encodeAny(typeURL, msg))
+	return w.buf
+}
+
+// encodeAuthInfo encodes cosmos.tx.v1beta1.AuthInfo for one SIGN_MODE_DIRECT signer
+func encodeAuthInfo(pubKey []byte, sequence uint64, gasLimit uint64, feeUUSDC uint64) []byte {
+	var key protoWriter
+	key.bytes(1, pubKey)
+
+	var single protoWriter
+	single.uint(1, dydxSignModeDirect)
+	var modeInfo protoWriter
+	modeInfo.message(1, single.buf)
+
+	var signer protoWriter
+	signer.message(1, encodeAny(dydxPubKeyType, key.buf))
+	signer.message(2, modeInfo.buf)
+	signer.uint(3, sequence)
+
+	var fee protoWriter
+	if feeUUSDC > 0 {
+		var coin protoWriter
+		coin.string(1, dydxUSDCDenom)
+		coin.string(2, strconv.FormatUint(feeUUSDC, 10))
+		fee.message(1, coin.buf)
+	}
+	fee.uint(2, gasLimit)
+
+	var w protoWriter
+	w.message(1, signer.buf)
+	w.message(2, fee.buf)
+	return w.buf
+}
+
+// encodeSignDoc encodes cosmos.tx.v1beta1.SignDoc
+func encodeSignDoc(body, authInfo []byte, chainID string, accountNumber uint64) []byte {
+	var w protoWriter
+	w.bytes(1, body)
+	w.bytes(2, authInfo)
+	w.string(3, chainID)
+	w.uint(4, accountNumber)
+	return w.buf
+}
+
+// encodeTxRaw encodes cosmos.tx.v1beta1.TxRaw
+func encodeTxRaw(body, authInfo, signature []byte) []byte {
+	var w protoWriter
+	w.bytes(1, body)
+	w.bytes(2, authInfo)
+	w.message(3, signature)
+	return w.buf
+}
+
+// ============================================================
+// Signing and broadcasting
+// ============================================================
+
+// dydxAccount on-chain account number and sequence
+type dydxAccount struct {
+	AccountNumber uint64
+	Sequence      uint64
+}
+
+// signTx builds and signs a transaction carrying one message
+func (t *DydxTrader) signTx(typeURL string, msg []byte, account dydxAccount, gasLimit, feeUUSDC uint64) []byte {
+	body := encodeTxBody(typeURL, msg)
+	authInfo := encodeAuthInfo(crypto.CompressPubkey(&t.privateKey.PublicKey), account.Sequence, gasLimit, feeUUSDC)
+
+	hash := sha256.Sum256(encodeSignDoc(body, authInfo, t.chainID, account.AccountNumber))
+	sig, _ := crypto.Sign(hash[:], t.privateKey) // [R || S || V], Cosmos uses R || S
+	return encodeTxRaw(body, authInfo, sig[:64])
+}
+
+// getAccount gets the account number and sequence from the validator
+func (t *DydxTrader) getAccount() (dydxAccount, error) {
+	var resp struct {
+		Account struct {
+			AccountNumber string `json:"account_number"`
+			Sequence      string `json:"sequence"`
+		} `json:"account"`
+	}
+	if err := t.getJSON(t.validatorURL+"/cosmos/auth/v1beta1/accounts/"+t.address, &resp); err != nil {
+		return dydxAccount{}, fmt.Errorf("failed to get account: %w", err)
+	}
+	accountNumber, _ := strconv.ParseUint(resp.Account.AccountNumber, 10, 64)
+	sequence, _ := strconv.ParseUint(resp.Account.Sequence, 10, 64)
+	return dydxAccount{AccountNumber: accountNumber, Sequence: sequence}, nil
+}
+
+// sendTx signs and broadcasts a message, returning the transaction hash
+// Short-term orders are gasless and skip sequence checks; stateful messages are simulated for gas and pay fees in USDC
+func (t *DydxTrader) sendTx(typeURL string, msg []byte, stateful bool) (string, error) {
+	t.txMutex.Lock()
+	defer t.txMutex.Unlock()
+
+	account, err := t.getAccount()
+	if err != nil {
+		return "", err
+	}
+
+	var gasLimit, fee uint64
+	if stateful {
+		gasUsed, err := t.simulateTx(t.signTx(typeURL, msg, account, 0, 0))
+		if err != nil {
+			return "", fmt.Errorf("failed to simulate transaction: %w", err)
+		}
+		gasLimit = uint64(float64(gasUsed) * dydxGasAdjustment)
+		fee = uint64(float64(gasLimit)*dydxGasPriceUUSDC) + 1
+	}
+
+	txBytes := t.signTx(typeURL, msg, account, gasLimit, fee)
+	body, _ := json.Marshal(map[string]string{
+		"tx_bytes": base64.StdEncoding.EncodeToString(txBytes),
+		"mode":     "BROADCAST_MODE_SYNC",
+	})
+
+	var resp struct {
+		TxResponse struct {
+			TxHash string `json:"txhash"`
+			Code   int    `json:"code"`
+			RawLog string `json:"raw_log"`
+		} `json:"tx_response"`
+	}
+	if err := t.postJSON(t.validatorURL+"/cosmos/tx/v1beta1/txs", body, &resp); err != nil {
+		return "", fmt.Errorf("failed to broadcast transaction: %w", err)
+	}
+	if resp.TxResponse.Code != 0 {
+		return "", fmt.Errorf("dYdX transaction rejected: code=%d, log=%s", resp.TxResponse.Code, resp.TxResponse.RawLog)
+	}
+	return resp.TxResponse.TxHash, nil
+}
+
+// simulateTx simulates a transaction and returns the gas used
+func (t *DydxTrader) simulateTx(txBytes []byte) (uint64, error) {
+	body, _ := json.Marshal(map[string]string{"tx_bytes": base64.StdEncoding.EncodeToString(txBytes)})
+
+	var resp struct {
+		GasInfo struct {
+			GasUsed string `json:"gas_used"`
+		} `json:"gas_info"`
+	}
+	if err := t.postJSON(t.validatorURL+"/cosmos/tx/v1beta1/simulate", body, &resp); err != nil {
+		return 0, err
+	}
+	return strconv.ParseUint(resp.GasInfo.GasUsed, 10, 64)
+}
+
+// postJSON posts a JSON body and decodes the JSON response
+func (t *DydxTrader) postJSON(url string, body []byte, out interface{}) error {
+	resp, err := t.client.Post(url, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("failed to read response: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("HTTP %s: %s", resp.Status, string(respBody))
+	}
+	return json.Unmarshal(respBody, out)
+}
@@ -0,0 +1,85 @@
+package trader
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"nofx/store"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestRecordExecution tests slippage/latency recording and the execution quality report
+func TestRecordExecution(t *testing.T) {
+	st, err := store.New(filepath.Join(t.TempDir(), "test.db"))
+	require.NoError(t, err)
+	defer st.Close()
+
+	at := &AutoTrader{id: "trader-exec-test", exchange: "binance", store: st, cycleAILatencyMs: 45000}
+	submitTime := time.Now().Add(-2 * time.Second)
+
+	// Long entry: decided at 100, submitted at 100.1, filled at 100.2 -> adverse
+	// Order book estimate of 0 bps is a real estimate
+	zeroEstimate, shortEstimate := 0.0, 6.0
+	longRecord := &store.DecisionAction{Action: "open_long", Symbol: "BTCUSDT", DecisionPrice: 100, Price: 100.1, EstimatedSlippageBps: &zeroEstimate}
+	at.recordExecution(longRecord, map[string]interface{}{"orderId": int64(1)}, submitTime, submitTime.Add(200*time.Millisecond), orderFill{price: 100.2, qty: 10, time: submitTime.Add(500 * time.Millisecond)})
+	assert.InDelta(t, 100.2, longRecord.FillPrice, 1e-9)
+	assert.InDelta(t, 20.0, longRecord.SlippageBps, 1e-6)
+
+	// Short entry filled above submit price -> favorable
+	shortRecord := &store.DecisionAction{Action: "open_short", Symbol: "ETHUSDT", DecisionPrice: 200, Price: 200, EstimatedSlippageBps: &shortEstimate}
+	at.recordExecution(shortRecord, map[string]interface{}{"orderId": "2"}, submitTime, submitTime, orderFill{price: 200.2, qty: 1})
+	assert.InDelta(t, -10.0, shortRecord.SlippageBps, 1e-6)
+
+	// External decision: no decision price, no AI latency
+	external := &store.DecisionAction{Action: "close_long", Symbol: "BTCUSDT", Price: 100}
+	at.recordExecution(external, map[string]interface{}{"orderId": int64(3)}, submitTime, submitTime, orderFill{price: 99.9, qty: 10, time: submitTime.Add(1500 * time.Millisecond)})
+	assert.Zero(t, external.SlippageBps)
+
+	// No reported fill price (e.g. Hyperliquid order status): no sample instead of a fake 0 bps one
+	unpriced := &store.DecisionAction{Action: "open_long", Symbol: "BTCUSDT", DecisionPrice: 100, Price: 100}
+	at.recordExecution(unpriced, map[string]interface{}{"orderId": int64(4)}, submitTime, submitTime, orderFill{qty: 10})
+	assert.Zero(t, unpriced.FillPrice)
+
+	// Rules-mode order: decision price but no AI call, doesn't pull the AI latency average down
+	at.cycleAILatencyMs = 0
+	rules := &store.DecisionAction{Action: "open_long", Symbol: "SOLUSDT", DecisionPrice: 10, Price: 10}
+	at.recordExecution(rules, map[string]interface{}{"orderId": int64(5)}, submitTime, submitTime, orderFill{price: 10, qty: 1000})
+
+	report, err := st.Execution().GetQualityReport(at.id, time.Now().Add(-time.Hour))
+	require.NoError(t, err)
+	assert.Equal(t, 4, report.Overall.Orders)
+	assert.Len(t, report.BySymbol, 3)
+	// The external order has no estimate, the 0 bps one counts
+	assert.InDelta(t, 3.0, report.Overall.AvgEstimatedBps, 1e-6)
+	assert.Len(t, report.ByExchange, 1)
+
+	var btc *store.ExecutionQualityBucket
+	for _, b := range report.BySymbol {
+		if b.Key == "BTCUSDT" {
+			btc = b
+		}
+	}
+	require.NotNil(t, btc)
+	assert.Equal(t, 2, btc.Orders)
+	// Submit -> fill: 100.1 -> 100.2 (~9.99 bps) and 100 -> 99.9 on a sell (10 bps)
+	assert.InDelta(t, 9.995, btc.AvgSlippageBps, 0.01)
+	// Only the AI-driven order has a decision price
+	assert.InDelta(t, 10.0, btc.AvgDecisionBps, 1e-6)
+	assert.InDelta(t, 20.0, btc.AvgTotalBps, 1e-6)
+	// AI latency averages the AI-decided orders only, fill latency the orders with a fill time
+	assert.InDelta(t, 45000.0, btc.AvgAILatencyMs, 1e-6)
+	assert.InDelta(t, 1000.0, btc.AvgFillLatencyMs, 1e-6)
+	// The ETH order has no fill time and doesn't pull the overall average down
+	assert.InDelta(t, 1000.0, report.Overall.AvgFillLatencyMs, 1e-6)
+	assert.InDelta(t, 45000.0, report.Overall.AvgAILatencyMs, 1e-6)
+
+	// Size buckets carry clean labels, in size order
+	var sizes []string
+	for _, b := range report.BySize {
+		sizes = append(sizes, b.Key)
+	}
+	assert.Equal(t, []string{"100-500", "500-2000", "10000+"}, sizes)
+}
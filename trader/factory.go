@@ -0,0 +1,40 @@
+package trader
+
+import (
+	"fmt"
+	"nofx/logger"
+	"nofx/store"
+)
+
+// NewTraderFromExchange creates an exchange client from an exchange account configuration
+// This is the only place that maps exchange types to adapters: auto traders, position sync,
+// the kill switch and backtests all create their clients here
+func NewTraderFromExchange(exchange *store.Exchange, userID string) (Trader, error) {
+	switch exchange.ExchangeType {
+	case "binance":
+		return NewFuturesTrader(exchange.APIKey, exchange.SecretKey, userID, exchange.Testnet), nil
+	case "bybit":
+		return NewBybitTrader(exchange.APIKey, exchange.SecretKey, exchange.Testnet), nil
+	case "okx":
+		return NewOKXTrader(exchange.APIKey, exchange.SecretKey, exchange.Passphrase, exchange.Testnet), nil
+	case "bitget":
+		return NewBitgetTrader(exchange.APIKey, exchange.SecretKey, exchange.Passphrase, exchange.Testnet), nil
+	case "gate":
+		return NewGateTrader(exchange.APIKey, exchange.SecretKey, exchange.Testnet), nil
+	case "hyperliquid":
+		return NewHyperliquidTrader(exchange.APIKey, exchange.HyperliquidWalletAddr, exchange.HyperliquidVaultAddr, exchange.Testnet)
+	case "aster":
+		return NewAsterTrader(exchange.AsterUser, exchange.AsterSigner, exchange.AsterPrivateKey)
+	case "lighter":
+		// Prefer V2 (requires API Key)
+		if exchange.LighterAPIKeyPrivateKey != "" {
+			return NewLighterTraderV2(exchange.LighterPrivateKey, exchange.LighterWalletAddr, exchange.LighterAPIKeyPrivateKey, exchange.Testnet)
+		}
+		logger.Infof("⚠️  Using LIGHTER basic implementation (V1) - Limited functionality, please configure API Key")
+		return NewLighterTrader(exchange.LighterPrivateKey, exchange.LighterWalletAddr, exchange.Testnet)
+	case "dydx":
+		return NewDydxTrader(exchange.DydxMnemonic, exchange.DydxAddress, exchange.DydxSubaccount, exchange.Testnet)
+	default:
+		return nil, fmt.Errorf("unsupported exchange type: %s", exchange.ExchangeType)
+	}
+}
@@ -0,0 +1,127 @@
+package trader
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"nofx/store"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fundingTrader Trader stub that only provides funding history
+type fundingTrader struct {
+	Trader
+	records []FundingRecord
+}
+
+func (f *fundingTrader) GetFundingHistory(startTime time.Time) ([]FundingRecord, error) {
+	var records []FundingRecord
+	for _, rec := range f.records {
+		if !rec.Time.Before(startTime) {
+			records = append(records, rec)
+		}
+	}
+	return records, nil
+}
+
+// TestFundingHistoryProvider_InterfaceCompliance tests which exchanges provide funding history
+func TestFundingHistoryProvider_InterfaceCompliance(t *testing.T) {
+	var _ FundingHistoryProvider = (*FuturesTrader)(nil)
+	var _ FundingHistoryProvider = (*BybitTrader)(nil)
+	var _ FundingHistoryProvider = (*OKXTrader)(nil)
+	var _ FundingHistoryProvider = (*BitgetTrader)(nil)
+	var _ FundingHistoryProvider = (*HyperliquidTrader)(nil)
+}
+
+// TestSyncFundingHistory tests funding attribution to positions and the net PnL split
+func TestSyncFundingHistory(t *testing.T) {
+	st, err := store.New(filepath.Join(t.TempDir(), "test.db"))
+	require.NoError(t, err)
+	defer st.Close()
+
+	traderID := "trader-funding-test"
+	entry := time.Now().Add(-48 * time.Hour).Truncate(time.Second)
+
+	pos := &store.TraderPosition{
+		TraderID:   traderID,
+		Symbol:     "BTCUSDT",
+		Side:       "LONG",
+		Quantity:   0.1,
+		EntryPrice: 100000,
+		EntryTime:  entry,
+		Leverage:   5,
+	}
+	require.NoError(t, st.Position().Create(pos))
+	require.NoError(t, st.Position().ClosePosition(pos.ID, 101000, "exit-1", 100, 8, "take_profit"))
+
+	ft := &fundingTrader{records: []FundingRecord{
+		{Symbol: "BTCUSDT", Amount: -30, Time: entry.Add(8 * time.Hour), TxID: "1"},
+		{Symbol: "BTCUSDT", Amount: -25, Time: entry.Add(16 * time.Hour), TxID: "2"},
+		{Symbol: "ETHUSDT", Amount: 5, Time: entry.Add(16 * time.Hour), TxID: "3"}, // No position
+	}}
+
+	m := NewPositionSyncManager(st, time.Second)
+	m.syncFundingHistory(traderID, ft)
+	m.syncFundingHistory(traderID, ft) // Already synced, nothing new
+
+	funding, err := st.Funding().GetPositionFunding(pos.ID)
+	require.NoError(t, err)
+	assert.InDelta(t, -55.0, funding, 1e-9)
+
+	stats, err := st.Position().GetFullStats(traderID)
+	require.NoError(t, err)
+	assert.InDelta(t, 100.0, stats.TotalPnL, 1e-9)
+	assert.InDelta(t, 8.0, stats.TotalFee, 1e-9)
+	assert.InDelta(t, -55.0, stats.TotalFunding, 1e-9)
+	assert.InDelta(t, 37.0, stats.NetPnL, 1e-9)
+
+	summary, err := st.Position().GetHistorySummary(traderID)
+	require.NoError(t, err)
+	assert.InDelta(t, 100.0, summary.PricePnL, 1e-9)
+	assert.InDelta(t, 8.0, summary.TradingFees, 1e-9)
+	assert.InDelta(t, -55.0, summary.FundingPnL, 1e-9)
+	assert.InDelta(t, 37.0, summary.NetPnL, 1e-9)
+}
+
+// TestSyncFundingBeforePositions tests funding synced before its position is rebuilt from fills is attributed afterwards
+func TestSyncFundingBeforePositions(t *testing.T) {
+	st, err := store.New(filepath.Join(t.TempDir(), "test.db"))
+	require.NoError(t, err)
+	defer st.Close()
+
+	traderID := "trader-funding-relink-test"
+	entry := time.Now().Add(-48 * time.Hour).Truncate(time.Second)
+
+	// Funding arrives first: no local position yet
+	m := NewPositionSyncManager(st, time.Second)
+	m.syncFundingHistory(traderID, &fundingTrader{records: []FundingRecord{
+		{Symbol: "BTCUSDT", Amount: -30, Time: entry.Add(8 * time.Hour), TxID: "1"},
+		{Symbol: "BTCUSDT", Amount: -25, Time: entry.Add(16 * time.Hour), TxID: "2"},
+		{Symbol: "BTCUSDT", Amount: 7, Time: entry.Add(30 * time.Hour), TxID: "3"}, // After the close
+	}})
+
+	tt := &tradeHistoryTrader{trades: []TradeRecord{
+		{TradeID: "1", Symbol: "BTCUSDT", Side: "BUY", PositionSide: "BOTH", Price: 100000, Quantity: 0.1, Time: entry},
+		{TradeID: "2", Symbol: "BTCUSDT", Side: "SELL", PositionSide: "BOTH", Price: 101000, Quantity: 0.1, Time: entry.Add(24 * time.Hour)},
+	}}
+	_, _, err = m.rebuildClosedPositions(traderID, "exchange-1", "binance", tt, entry.Add(-time.Hour), entry.Add(-time.Hour))
+	require.NoError(t, err)
+
+	summary, err := st.Position().GetHistorySummary(traderID)
+	require.NoError(t, err)
+	assert.InDelta(t, -55.0, summary.FundingPnL, 1e-9)
+	assert.InDelta(t, 100.0-55.0, summary.NetPnL, 1e-9)
+
+	// A rebuild replaces the position: its funding follows the rebuilt one
+	_, err = st.Position().DeleteSyncedClosedSince(traderID, entry.Add(-time.Hour))
+	require.NoError(t, err)
+	_, _, err = m.rebuildClosedPositions(traderID, "exchange-1", "binance", tt, entry.Add(-time.Hour), entry.Add(-time.Hour))
+	require.NoError(t, err)
+
+	summary, err = st.Position().GetHistorySummary(traderID)
+	require.NoError(t, err)
+	assert.InDelta(t, -55.0, summary.FundingPnL, 1e-9)
+}
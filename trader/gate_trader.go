@@ -0,0 +1,1131 @@
+package trader
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha512"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"net/url"
+	"nofx/logger"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Gate.io API endpoints (V4, USDT-settled perpetuals)
+const (
+	gateBaseURL         = "https://api.gateio.ws"
+	gateTestnetBaseURL  = "https://fx-api-testnet.gateio.ws" // Futures testnet (requires testnet API keys)
+	gateAPIPrefix       = "/api/v4"
+	gateAccountsPath    = "/futures/usdt/accounts"
+	gatePositionsPath   = "/futures/usdt/positions"
+	gateContractsPath   = "/futures/usdt/contracts"
+	gateTickersPath     = "/futures/usdt/tickers"
+	gateOrdersPath      = "/futures/usdt/orders"
+	gatePriceOrdersPath = "/futures/usdt/price_orders"
+	gateDualModePath    = "/futures/usdt/dual_mode"
+	gatePositionClose   = "/futures/usdt/position_close"
+	gateMyTradesPath    = "/futures/usdt/my_trades_timerange"
+	gateAccountBookPath = "/futures/usdt/account_book"
+)
+
+// Gate price trigger rules
+const (
+	gateTriggerRuleGTE = 1 // Trigger when price >= trigger price
+	gateTriggerRuleLTE = 2 // Trigger when price <= trigger price
+)
+
+// GateTrader Gate.io USDT perpetual futures trader
+// Gate sizes orders and positions in contracts; this adapter converts to base asset units
+// using each contract's quanto_multiplier, so callers always work in coins like other venues.
+type GateTrader struct {
+	apiKey    string
+	secretKey string
+	baseURL   string
+	testnet   bool
+
+	// HTTP client
+	httpClient *http.Client
+
+	// Balance cache
+	cachedBalance     map[string]interface{}
+	balanceCacheTime  time.Time
+	balanceCacheMutex sync.RWMutex
+
+	// Positions cache
+	cachedPositions     []map[string]interface{}
+	positionsCacheTime  time.Time
+	positionsCacheMutex sync.RWMutex
+
+	// Margin mode per contract (true = cross), applied when leverage is set
+	marginModes      map[string]bool
+	marginModesMutex sync.RWMutex
+
+	// Cache duration
+	cacheDuration time.Duration
+}
+
+// GateContract Gate contract info
+type GateContract struct {
+	Name             string  // Contract name (e.g. BTC_USDT)
+	QuantoMultiplier float64 // Base asset per contract
+	OrderPriceRound  float64 // Price tick size
+	OrderSizeMin     int64   // Minimum order size in contracts
+	LeverageMax      int     // Maximum leverage
+	InDelisting      bool    // Contract is being delisted
+}
+
+// gateError Gate API error response
+type gateError struct {
+	Label   string `json:"label"`
+	Message string `json:"message"`
+}
+
+// NewGateTrader creates a Gate.io trader
+// testnet routes all requests to the Gate futures testnet (requires testnet API keys)
+func NewGateTrader(apiKey, secretKey string, testnet bool) *GateTrader {
+	baseURL := gateBaseURL
+	if testnet {
+		baseURL = gateTestnetBaseURL
+	}
+	trader := &GateTrader{
+		apiKey:    apiKey,
+		secretKey: secretKey,
+		baseURL:   baseURL,
+		testnet:   testnet,
+		httpClient: &http.Client{
+			Timeout:   30 * time.Second,
+			Transport: http.DefaultTransport,
+		},
+		cacheDuration: 15 * time.Second,
+		marginModes:   make(map[string]bool),
+	}
+
+	// Use single (one-way) position mode
+	if err := trader.setSingleMode(); err != nil {
+		logger.Infof("⚠️ Failed to set Gate position mode: %v (ignore if already set)", err)
+	}
+
+	logger.Infof("🟢 [Gate] Trader initialized (%s)", environmentName(testnet))
+
+	return trader
+}
+
+// setSingleMode switches the account to single (one-way) position mode
+func (t *GateTrader) setSingleMode() error {
+	_, err := t.doRequest("POST", gateDualModePath, url.Values{"dual_mode": {"false"}}, nil)
+	return err
+}
+
+// sign generates Gate API v4 signature
+func (t *GateTrader) sign(method, path, query, body, timestamp string) string {
+	// Signature = HEX(HMAC_SHA512(method\npath\nquery\nHEX(SHA512(body))\ntimestamp, secretKey))
+	bodyHash := sha512.Sum512([]byte(body))
+	payload := strings.Join([]string{method, path, query, hex.EncodeToString(bodyHash[:]), timestamp}, "\n")
+	h := hmac.New(sha512.New, []byte(t.secretKey))
+	h.Write([]byte(payload))
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// doRequest executes a signed HTTP request
+func (t *GateTrader) doRequest(method, path string, query url.Values, body interface{}) ([]byte, error) {
+	var bodyBytes []byte
+	if body != nil {
+		var err error
+		bodyBytes, err = json.Marshal(body)
+		if err != nil {
+			return nil, fmt.Errorf("failed to serialize request body: %w", err)
+		}
+	}
+
+	fullPath := gateAPIPrefix + path
+	queryString := query.Encode()
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+
+	reqURL := t.baseURL + fullPath
+	if queryString != "" {
+		reqURL += "?" + queryString
+	}
+	req, err := http.NewRequest(method, reqURL, bytes.NewReader(bodyBytes))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	req.Header.Set("KEY", t.apiKey)
+	req.Header.Set("Timestamp", timestamp)
+	req.Header.Set("SIGN", t.sign(method, fullPath, queryString, string(bodyBytes), timestamp))
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := t.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response: %w", err)
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		var apiErr gateError
+		json.Unmarshal(respBody, &apiErr)
+		return nil, fmt.Errorf("Gate API error: status=%s, label=%s, message=%s", resp.Status, apiErr.Label, apiErr.Message)
+	}
+
+	return respBody, nil
+}
+
+// convertSymbol converts generic symbol to Gate contract name
+// e.g., BTCUSDT -> BTC_USDT
+func (t *GateTrader) convertSymbol(symbol string) string {
+	return VenueSymbol("gate", symbol)
+}
+
+// GetBalance gets account balance
+func (t *GateTrader) GetBalance() (map[string]interface{}, error) {
+	// Check cache
+	t.balanceCacheMutex.RLock()
+	if t.cachedBalance != nil && time.Since(t.balanceCacheTime) < t.cacheDuration {
+		t.balanceCacheMutex.RUnlock()
+		return t.cachedBalance, nil
+	}
+	t.balanceCacheMutex.RUnlock()
+
+	data, err := t.doRequest("GET", gateAccountsPath, nil, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get account balance: %w", err)
+	}
+
+	var account struct {
+		Total         string `json:"total"`          // Wallet balance (excludes unrealised PnL)
+		UnrealisedPnl string `json:"unrealised_pnl"` // Unrealized P&L
+		Available     string `json:"available"`      // Available balance
+		Currency      string `json:"currency"`
+	}
+	if err := json.Unmarshal(data, &account); err != nil {
+		return nil, fmt.Errorf("failed to parse balance data: %w, raw: %s", err, string(data))
+	}
+
+	walletBalance, _ := strconv.ParseFloat(account.Total, 64)
+	unrealizedPnL, _ := strconv.ParseFloat(account.UnrealisedPnl, 64)
+	availableBalance, _ := strconv.ParseFloat(account.Available, 64)
+	logger.Infof("✓ [Gate] Balance: equity=%.2f, available=%.2f", walletBalance+unrealizedPnL, availableBalance)
+
+	result := map[string]interface{}{
+		"totalWalletBalance":    walletBalance,
+		"availableBalance":      availableBalance,
+		"totalUnrealizedProfit": unrealizedPnL,
+		"total_equity":          walletBalance + unrealizedPnL,
+	}
+
+	// Update cache
+	t.balanceCacheMutex.Lock()
+	t.cachedBalance = result
+	t.balanceCacheTime = time.Now()
+	t.balanceCacheMutex.Unlock()
+
+	return result, nil
+}
+
+// GetPositions gets all positions
+func (t *GateTrader) GetPositions() ([]map[string]interface{}, error) {
+	// Check cache
+	t.positionsCacheMutex.RLock()
+	if t.cachedPositions != nil && time.Since(t.positionsCacheTime) < t.cacheDuration {
+		t.positionsCacheMutex.RUnlock()
+		return t.cachedPositions, nil
+	}
+	t.positionsCacheMutex.RUnlock()
+
+	data, err := t.doRequest("GET", gatePositionsPath, url.Values{"holding": {"true"}}, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get positions: %w", err)
+	}
+
+	var positions []struct {
+		Contract           string `json:"contract"`
+		Size               int64  `json:"size"` // Contracts, negative for short
+		EntryPrice         string `json:"entry_price"`
+		MarkPrice          string `json:"mark_price"`
+		UnrealisedPnl      string `json:"unrealised_pnl"`
+		Leverage           string `json:"leverage"` // 0 = cross margin
+		CrossLeverageLimit string `json:"cross_leverage_limit"`
+		LiqPrice           string `json:"liq_price"`
+		OpenTime           int64  `json:"open_time"`
+		UpdateTime         int64  `json:"update_time"`
+	}
+	if err := json.Unmarshal(data, &positions); err != nil {
+		return nil, fmt.Errorf("failed to parse position data: %w", err)
+	}
+
+	result := []map[string]interface{}{}
+	for _, pos := range positions {
+		if pos.Size == 0 {
+			continue
+		}
+
+		multiplier := t.quantoMultiplier(pos.Contract)
+		entryPrice, _ := strconv.ParseFloat(pos.EntryPrice, 64)
+		markPrice, _ := strconv.ParseFloat(pos.MarkPrice, 64)
+		unrealizedPnL, _ := strconv.ParseFloat(pos.UnrealisedPnl, 64)
+		liqPrice, _ := strconv.ParseFloat(pos.LiqPrice, 64)
+		leverage, _ := strconv.ParseFloat(pos.Leverage, 64)
+		if leverage == 0 {
+			leverage, _ = strconv.ParseFloat(pos.CrossLeverageLimit, 64)
+		}
+
+		side := "long"
+		size := pos.Size
+		if size < 0 {
+			side = "short"
+			size = -size
+		}
+
+		result = append(result, map[string]interface{}{
+			"symbol":           CanonicalSymbol("gate", pos.Contract),
+			"positionAmt":      float64(size) * multiplier,
+			"entryPrice":       entryPrice,
+			"markPrice":        markPrice,
+			"unRealizedProfit": unrealizedPnL,
+			"leverage":         leverage,
+			"liquidationPrice": liqPrice,
+			"side":             side,
+			"createdTime":      pos.OpenTime * 1000,
+			"updatedTime":      pos.UpdateTime * 1000,
+		})
+	}
+
+	// Update cache
+	t.positionsCacheMutex.Lock()
+	t.cachedPositions = result
+	t.positionsCacheTime = time.Now()
+	t.positionsCacheMutex.Unlock()
+
+	return result, nil
+}
+
+// loadContracts loads all USDT perpetual contracts (keyed by Gate contract name, e.g. BTC_USDT)
+func (t *GateTrader) loadContracts() (map[string]*GateContract, error) {
+	data, err := t.doRequest("GET", gateContractsPath, nil, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get contracts: %w", err)
+	}
+
+	var list []struct {
+		Name             string `json:"name"`
+		QuantoMultiplier string `json:"quanto_multiplier"`
+		OrderPriceRound  string `json:"order_price_round"`
+		OrderSizeMin     int64  `json:"order_size_min"`
+		LeverageMax      string `json:"leverage_max"`
+		InDelisting      bool   `json:"in_delisting"`
+	}
+	if err := json.Unmarshal(data, &list); err != nil {
+		return nil, fmt.Errorf("failed to parse contracts: %w", err)
+	}
+
+	contracts := make(map[string]*GateContract, len(list))
+	for _, c := range list {
+		contracts[c.Name] = &GateContract{
+			Name:             c.Name,
+			QuantoMultiplier: parseFloatOrZero(c.QuantoMultiplier),
+			OrderPriceRound:  parseFloatOrZero(c.OrderPriceRound),
+			OrderSizeMin:     c.OrderSizeMin,
+			LeverageMax:      int(parseFloatOrZero(c.LeverageMax)),
+			InDelisting:      c.InDelisting,
+		}
+	}
+	return contracts, nil
+}
+
+// instrument gets a contract's rules from the instrument registry (symbol may be canonical or a Gate contract name)
+func (t *GateTrader) instrument(symbol string) (*Instrument, error) {
+	return venueInstrument(Venue{Exchange: "gate", Testnet: t.testnet}, t, CanonicalSymbol("gate", symbol))
+}
+
+// quantoMultiplier gets base asset per contract (1 when the contract is unknown)
+func (t *GateTrader) quantoMultiplier(symbol string) float64 {
+	inst, err := t.instrument(symbol)
+	if err != nil {
+		return 1
+	}
+	return inst.ContractMultiplier
+}
+
+// toContracts converts a base asset quantity to a whole number of contracts (rounded down)
+func (t *GateTrader) toContracts(symbol string, quantity float64) (int64, error) {
+	inst, err := t.instrument(symbol)
+	if err != nil {
+		return 0, err
+	}
+
+	// The lot step is one contract, so rounded quantities are whole contract counts
+	contracts := int64(math.Round(inst.RoundQuantity(math.Abs(quantity)) / inst.ContractMultiplier))
+	minSize := int64(math.Round(inst.MinQty / inst.ContractMultiplier))
+	if contracts < minSize {
+		return 0, fmt.Errorf("%s quantity %v is below the minimum of %d contracts (%v per contract)", symbol, quantity, minSize, inst.ContractMultiplier)
+	}
+	return contracts, nil
+}
+
+// GetInstruments gets trading rules of all USDT perpetuals (implements InstrumentProvider)
+func (t *GateTrader) GetInstruments() ([]Instrument, error) {
+	contracts, err := t.loadContracts()
+	if err != nil {
+		return nil, err
+	}
+
+	var instruments []Instrument
+	for _, c := range contracts {
+		if c.InDelisting || !strings.HasSuffix(c.Name, "_USDT") {
+			continue
+		}
+		multiplier := c.QuantoMultiplier
+		if multiplier <= 0 {
+			multiplier = 1
+		}
+		minSize := c.OrderSizeMin
+		if minSize < 1 {
+			minSize = 1
+		}
+		instruments = append(instruments, Instrument{
+			Symbol:             CanonicalSymbol("gate", c.Name),
+			VenueSymbol:        c.Name,
+			TickSize:           c.OrderPriceRound,
+			LotStep:            multiplier,
+			MinQty:             float64(minSize) * multiplier,
+			MaxLeverage:        c.LeverageMax,
+			ContractMultiplier: multiplier,
+		})
+	}
+	return instruments, nil
+}
+
+// SetMarginMode sets margin mode
+// Gate encodes margin mode in position leverage (0 = cross), so the current leverage is re-applied in the new mode
+func (t *GateTrader) SetMarginMode(symbol string, isCrossMargin bool) error {
+	contract := t.convertSymbol(symbol)
+
+	t.marginModesMutex.Lock()
+	t.marginModes[contract] = isCrossMargin
+	t.marginModesMutex.Unlock()
+
+	data, err := t.doRequest("GET", gatePositionsPath+"/"+contract, nil, nil)
+	if err != nil {
+		return fmt.Errorf("failed to get %s position: %w", contract, err)
+	}
+
+	var pos struct {
+		Size               int64  `json:"size"`
+		Leverage           string `json:"leverage"`
+		CrossLeverageLimit string `json:"cross_leverage_limit"`
+	}
+	if err := json.Unmarshal(data, &pos); err != nil {
+		return fmt.Errorf("failed to parse position data: %w", err)
+	}
+
+	leverage := int(parseFloatOrZero(pos.Leverage))
+	if (leverage == 0) == isCrossMargin {
+		return nil // Already in requested mode
+	}
+	if pos.Size != 0 {
+		logger.Infof("  ⚠️ %s has positions, cannot change margin mode", symbol)
+		return nil
+	}
+	if leverage == 0 {
+		leverage = int(parseFloatOrZero(pos.CrossLeverageLimit))
+	}
+	if leverage <= 0 {
+		return nil // Applied with the next SetLeverage
+	}
+
+	if err := t.applyLeverage(contract, leverage, isCrossMargin); err != nil {
+		return err
+	}
+
+	marginMode := "isolated"
+	if isCrossMargin {
+		marginMode = "cross"
+	}
+	logger.Infof("  ✓ %s margin mode set to %s", symbol, marginMode)
+	return nil
+}
+
+// SetLeverage sets leverage
+func (t *GateTrader) SetLeverage(symbol string, leverage int) error {
+	contract := t.convertSymbol(symbol)
+
+	t.marginModesMutex.RLock()
+	isCross, ok := t.marginModes[contract]
+	t.marginModesMutex.RUnlock()
+	if !ok {
+		isCross = true
+	}
+
+	if err := t.applyLeverage(contract, leverage, isCross); err != nil {
+		logger.Infof("  ⚠️ Failed to set %s leverage: %v", symbol, err)
+		return err
+	}
+
+	logger.Infof("  ✓ %s leverage set to %dx", symbol, leverage)
+	return nil
+}
+
+// applyLeverage updates position leverage (cross margin uses leverage=0 with cross_leverage_limit)
+func (t *GateTrader) applyLeverage(contract string, leverage int, isCross bool) error {
+	query := url.Values{"leverage": {strconv.Itoa(leverage)}}
+	if isCross {
+		query.Set("leverage", "0")
+		query.Set("cross_leverage_limit", strconv.Itoa(leverage))
+	}
+	_, err := t.doRequest("POST", gatePositionsPath+"/"+contract+"/leverage", query, nil)
+	return err
+}
+
+// OpenLong opens long position
+func (t *GateTrader) OpenLong(symbol string, quantity float64, leverage int) (map[string]interface{}, error) {
+	return t.OpenLongWithClientID(symbol, quantity, leverage, "")
+}
+
+// OpenLongWithClientID opens long position tagged with client order ID
+func (t *GateTrader) OpenLongWithClientID(symbol string, quantity float64, leverage int, clientOrderID string) (map[string]interface{}, error) {
+	return t.openPosition(symbol, quantity, leverage, 1, clientOrderID)
+}
+
+// OpenShort opens short position
+func (t *GateTrader) OpenShort(symbol string, quantity float64, leverage int) (map[string]interface{}, error) {
+	return t.OpenShortWithClientID(symbol, quantity, leverage, "")
+}
+
+// OpenShortWithClientID opens short position tagged with client order ID
+func (t *GateTrader) OpenShortWithClientID(symbol string, quantity float64, leverage int, clientOrderID string) (map[string]interface{}, error) {
+	return t.openPosition(symbol, quantity, leverage, -1, clientOrderID)
+}
+
+// openPosition places a market order opening a position (direction: 1 = long, -1 = short)
+func (t *GateTrader) openPosition(symbol string, quantity float64, leverage int, direction int64, clientOrderID string) (map[string]interface{}, error) {
+	symbol = strings.ToUpper(symbol)
+	sideName, action := "long", "OpenLong"
+	if direction < 0 {
+		sideName, action = "short", "OpenShort"
+	}
+
+	// Cancel old orders first
+	t.CancelAllOrders(symbol)
+
+	// Set leverage
+	if err := t.SetLeverage(symbol, leverage); err != nil {
+		logger.Infof("  ⚠️ Failed to set leverage: %v", err)
+	}
+
+	contracts, err := t.toContracts(symbol, quantity)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open %s position: %w", sideName, err)
+	}
+
+	logger.Infof("  📊 Gate %s: symbol=%s, contracts=%d, leverage=%d", action, symbol, contracts, leverage)
+
+	order, err := t.placeOrder(symbol, direction*contracts, false, clientOrderID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open %s position: %w", sideName, err)
+	}
+
+	// Clear cache
+	t.clearCache()
+
+	logger.Infof("✓ Gate opened %s position successfully: %s", sideName, symbol)
+
+	return order, nil
+}
+
+// CloseLong closes long position
+func (t *GateTrader) CloseLong(symbol string, quantity float64) (map[string]interface{}, error) {
+	return t.CloseLongWithClientID(symbol, quantity, "")
+}
+
+// CloseLongWithClientID closes long position tagged with client order ID
+func (t *GateTrader) CloseLongWithClientID(symbol string, quantity float64, clientOrderID string) (map[string]interface{}, error) {
+	return t.closePosition(symbol, "long", quantity, clientOrderID)
+}
+
+// CloseShort closes short position
+func (t *GateTrader) CloseShort(symbol string, quantity float64) (map[string]interface{}, error) {
+	return t.CloseShortWithClientID(symbol, quantity, "")
+}
+
+// CloseShortWithClientID closes short position tagged with client order ID
+func (t *GateTrader) CloseShortWithClientID(symbol string, quantity float64, clientOrderID string) (map[string]interface{}, error) {
+	return t.closePosition(symbol, "short", quantity, clientOrderID)
+}
+
+// closePosition places a reduce-only market order closing a position (quantity=0 means close all)
+func (t *GateTrader) closePosition(symbol, side string, quantity float64, clientOrderID string) (map[string]interface{}, error) {
+	symbol = strings.ToUpper(symbol)
+
+	// If quantity is 0, get current position
+	if quantity == 0 {
+		positions, err := t.GetPositions()
+		if err != nil {
+			return nil, err
+		}
+		for _, pos := range positions {
+			if pos["symbol"] == symbol && pos["side"] == side {
+				quantity = pos["positionAmt"].(float64)
+				break
+			}
+		}
+		if quantity == 0 {
+			return nil, fmt.Errorf("%s position not found for %s", side, symbol)
+		}
+	}
+
+	contracts, err := t.toContracts(symbol, quantity)
+	if err != nil {
+		return nil, fmt.Errorf("failed to close %s position: %w", side, err)
+	}
+
+	// Closing a long sells, closing a short buys
+	size, action := -contracts, "CloseLong"
+	if side == "short" {
+		size, action = contracts, "CloseShort"
+	}
+
+	logger.Infof("  📊 Gate %s: symbol=%s, contracts=%d", action, symbol, contracts)
+
+	order, err := t.placeOrder(symbol, size, true, clientOrderID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to close %s position: %w", side, err)
+	}
+
+	// Clear cache
+	t.clearCache()
+
+	logger.Infof("✓ Gate closed %s position successfully: %s", side, symbol)
+
+	return order, nil
+}
+
+// placeOrder submits a market (IOC) order of size contracts (negative = sell), resolving ambiguous failures by client order ID
+func (t *GateTrader) placeOrder(symbol string, size int64, reduceOnly bool, clientOrderID string) (map[string]interface{}, error) {
+	text := ""
+	if clientOrderID != "" {
+		text = gateOrderText(clientOrderID)
+	}
+
+	body := map[string]interface{}{
+		"contract":    t.convertSymbol(symbol),
+		"size":        size,
+		"price":       "0",
+		"tif":         "ioc",
+		"reduce_only": reduceOnly,
+	}
+	if text != "" {
+		body["text"] = text
+	}
+
+	submit := func() (map[string]interface{}, error) {
+		data, err := t.doRequest("POST", gateOrdersPath, nil, body)
+		if err != nil {
+			return nil, err
+		}
+		return t.parseOrder(symbol, data)
+	}
+	lookup := func() (map[string]interface{}, error) {
+		return t.GetOrderByClientID(symbol, clientOrderID)
+	}
+
+	return submitWithClientOrderID(clientOrderID, submit, lookup)
+}
+
+// gateOrderText converts a client order ID to Gate order text ("t-" prefix, at most 28 characters after it)
+func gateOrderText(clientOrderID string) string {
+	id := strings.TrimPrefix(clientOrderID, "t-")
+	if len(id) > 28 {
+		id = id[:28]
+	}
+	return "t-" + id
+}
+
+// GetMarketPrice gets market price
+func (t *GateTrader) GetMarketPrice(symbol string) (float64, error) {
+	data, err := t.doRequest("GET", gateTickersPath, url.Values{"contract": {t.convertSymbol(symbol)}}, nil)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get price: %w", err)
+	}
+
+	var tickers []struct {
+		Contract string `json:"contract"`
+		Last     string `json:"last"`
+	}
+	if err := json.Unmarshal(data, &tickers); err != nil {
+		return 0, err
+	}
+	if len(tickers) == 0 {
+		return 0, fmt.Errorf("no price data received")
+	}
+
+	price, err := strconv.ParseFloat(tickers[0].Last, 64)
+	if err != nil {
+		return 0, err
+	}
+	return price, nil
+}
+
+// SetStopLoss sets stop loss order
+func (t *GateTrader) SetStopLoss(symbol string, positionSide string, quantity, stopPrice float64) error {
+	// Long stop triggers when price falls to stopPrice, short stop when it rises
+	rule := gateTriggerRuleLTE
+	if strings.ToUpper(positionSide) == "SHORT" {
+		rule = gateTriggerRuleGTE
+	}
+	if err := t.placeTriggerOrder(symbol, positionSide, quantity, stopPrice, rule); err != nil {
+		return fmt.Errorf("failed to set stop loss: %w", err)
+	}
+
+	logger.Infof("  ✓ [Gate] Stop loss set: %s @ %.4f", symbol, stopPrice)
+	return nil
+}
+
+// SetTakeProfit sets take profit order
+func (t *GateTrader) SetTakeProfit(symbol string, positionSide string, quantity, takeProfitPrice float64) error {
+	// Long take profit triggers when price rises to takeProfitPrice, short when it falls
+	rule := gateTriggerRuleGTE
+	if strings.ToUpper(positionSide) == "SHORT" {
+		rule = gateTriggerRuleLTE
+	}
+	if err := t.placeTriggerOrder(symbol, positionSide, quantity, takeProfitPrice, rule); err != nil {
+		return fmt.Errorf("failed to set take profit: %w", err)
+	}
+
+	logger.Infof("  ✓ [Gate] Take profit set: %s @ %.4f", symbol, takeProfitPrice)
+	return nil
+}
+
+// placeTriggerOrder places a reduce-only market order triggered by mark price
+func (t *GateTrader) placeTriggerOrder(symbol, positionSide string, quantity, triggerPrice float64, rule int) error {
+	contracts, err := t.toContracts(symbol, quantity)
+	if err != nil {
+		return err
+	}
+
+	size := -contracts
+	orderType := "plan-close-long-position"
+	if strings.ToUpper(positionSide) == "SHORT" {
+		size = contracts
+		orderType = "plan-close-short-position"
+	}
+
+	body := map[string]interface{}{
+		"initial": map[string]interface{}{
+			"contract":    t.convertSymbol(symbol),
+			"size":        size,
+			"price":       "0",
+			"tif":         "ioc",
+			"reduce_only": true,
+		},
+		"trigger": map[string]interface{}{
+			"strategy_type": 0, // Price trigger
+			"price_type":    1, // Mark price
+			"price":         t.formatPrice(symbol, triggerPrice),
+			"rule":          rule,
+		},
+		"order_type": orderType,
+	}
+
+	_, err = t.doRequest("POST", gatePriceOrdersPath, nil, body)
+	return err
+}
+
+// formatPrice formats a price rounded to the contract's tick size
+func (t *GateTrader) formatPrice(symbol string, price float64) string {
+	inst, err := t.instrument(symbol)
+	if err != nil {
+		return strconv.FormatFloat(price, 'f', -1, 64)
+	}
+	return inst.FormatPrice(price)
+}
+
+// CancelStopLossOrders cancels stop loss orders
+func (t *GateTrader) CancelStopLossOrders(symbol string) error {
+	return t.cancelTriggerOrders(symbol, true)
+}
+
+// CancelTakeProfitOrders cancels take profit orders
+func (t *GateTrader) CancelTakeProfitOrders(symbol string) error {
+	return t.cancelTriggerOrders(symbol, false)
+}
+
+// cancelTriggerOrders cancels open stop loss (stopLoss=true) or take profit trigger orders
+// Gate doesn't tag trigger orders as SL/TP, so they are told apart by close direction and trigger rule
+func (t *GateTrader) cancelTriggerOrders(symbol string, stopLoss bool) error {
+	contract := t.convertSymbol(symbol)
+
+	query := url.Values{"status": {"open"}, "contract": {contract}}
+	data, err := t.doRequest("GET", gatePriceOrdersPath, query, nil)
+	if err != nil {
+		return err
+	}
+
+	var orders []struct {
+		ID      int64 `json:"id"`
+		Initial struct {
+			Size int64 `json:"size"`
+		} `json:"initial"`
+		Trigger struct {
+			Rule int `json:"rule"`
+		} `json:"trigger"`
+	}
+	if err := json.Unmarshal(data, &orders); err != nil {
+		return err
+	}
+
+	for _, order := range orders {
+		// Selling (closing long) below the trigger, or buying (closing short) above it, is a stop loss
+		isStopLoss := (order.Initial.Size < 0 && order.Trigger.Rule == gateTriggerRuleLTE) ||
+			(order.Initial.Size > 0 && order.Trigger.Rule == gateTriggerRuleGTE)
+		if isStopLoss != stopLoss {
+			continue
+		}
+		t.doRequest("DELETE", fmt.Sprintf("%s/%d", gatePriceOrdersPath, order.ID), nil, nil)
+	}
+
+	return nil
+}
+
+// CancelAllOrders cancels all pending orders
+func (t *GateTrader) CancelAllOrders(symbol string) error {
+	query := url.Values{"contract": {t.convertSymbol(symbol)}}
+	if _, err := t.doRequest("DELETE", gateOrdersPath, query, nil); err != nil {
+		return err
+	}
+
+	// Also cancel trigger orders
+	t.CancelStopOrders(symbol)
+
+	return nil
+}
+
+// CancelStopOrders cancels stop loss and take profit orders
+func (t *GateTrader) CancelStopOrders(symbol string) error {
+	query := url.Values{"contract": {t.convertSymbol(symbol)}}
+	_, err := t.doRequest("DELETE", gatePriceOrdersPath, query, nil)
+	return err
+}
+
+// FormatQuantity formats quantity as a whole number of contracts
+func (t *GateTrader) FormatQuantity(symbol string, quantity float64) (string, error) {
+	inst, err := t.instrument(symbol)
+	if err != nil {
+		return fmt.Sprintf("%.4f", quantity), nil
+	}
+
+	// Gate uses contract count: quantity (in base asset) / quanto_multiplier (asset per contract)
+	return inst.FormatContracts(quantity), nil
+}
+
+// GetOrderStatus gets order status
+func (t *GateTrader) GetOrderStatus(symbol string, orderID string) (map[string]interface{}, error) {
+	return t.queryOrder(symbol, orderID)
+}
+
+// GetOrderByClientID gets order status by client order ID (Gate order text)
+func (t *GateTrader) GetOrderByClientID(symbol string, clientOrderID string) (map[string]interface{}, error) {
+	return t.queryOrder(symbol, gateOrderText(clientOrderID))
+}
+
+// queryOrder queries an order by order ID or order text (Gate accepts either)
+func (t *GateTrader) queryOrder(symbol string, id string) (map[string]interface{}, error) {
+	data, err := t.doRequest("GET", gateOrdersPath+"/"+url.PathEscape(id), nil, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get order status: %w", err)
+	}
+	return t.parseOrder(symbol, data)
+}
+
+// parseOrder converts a Gate order to the common order map (quantities in base asset)
+func (t *GateTrader) parseOrder(symbol string, data []byte) (map[string]interface{}, error) {
+	var order struct {
+		ID         int64   `json:"id"`
+		Contract   string  `json:"contract"`
+		Size       int64   `json:"size"`
+		Left       int64   `json:"left"`
+		FillPrice  string  `json:"fill_price"`
+		Status     string  `json:"status"`    // open, finished
+		FinishAs   string  `json:"finish_as"` // filled, cancelled, ioc, reduce_only, ...
+		Text       string  `json:"text"`
+		CreateTime float64 `json:"create_time"`
+		FinishTime float64 `json:"finish_time"`
+	}
+	if err := json.Unmarshal(data, &order); err != nil {
+		return nil, fmt.Errorf("failed to parse order response: %w", err)
+	}
+
+	size := order.Size
+	side := "BUY"
+	if size < 0 {
+		side = "SELL"
+		size = -size
+	}
+	left := order.Left
+	if left < 0 {
+		left = -left
+	}
+	filled := size - left
+	multiplier := t.quantoMultiplier(order.Contract)
+	avgPrice, _ := strconv.ParseFloat(order.FillPrice, 64)
+
+	// Status mapping: IOC market orders finish "ioc" when the remainder was cancelled after a partial fill
+	var status string
+	switch {
+	case order.Status == "open" && filled > 0:
+		status = "PARTIALLY_FILLED"
+	case order.Status == "open":
+		status = "NEW"
+	case filled > 0:
+		status = "FILLED"
+	default:
+		status = "CANCELED"
+	}
+
+	return map[string]interface{}{
+		"orderId":       strconv.FormatInt(order.ID, 10),
+		"clientOrderId": order.Text,
+		"symbol":        symbol,
+		"status":        status,
+		"avgPrice":      avgPrice,
+		"executedQty":   float64(filled) * multiplier,
+		"side":          side,
+		"type":          "MARKET",
+		"time":          int64(order.CreateTime * 1000),
+		"updateTime":    int64(order.FinishTime * 1000),
+		"commission":    0.0, // Not reported on orders, see GetTrades
+	}, nil
+}
+
+// GetClosedPnL retrieves closed position PnL records from position close history
+func (t *GateTrader) GetClosedPnL(startTime time.Time, limit int) ([]ClosedPnLRecord, error) {
+	if limit <= 0 || limit > 1000 {
+		limit = 100
+	}
+
+	query := url.Values{
+		"from":  {strconv.FormatInt(startTime.Unix(), 10)},
+		"limit": {strconv.Itoa(limit)},
+	}
+	data, err := t.doRequest("GET", gatePositionClose, query, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get position close history: %w", err)
+	}
+
+	var closes []struct {
+		Time          float64 `json:"time"`
+		Contract      string  `json:"contract"`
+		Side          string  `json:"side"` // long, short
+		Pnl           string  `json:"pnl"`
+		PnlPnl        string  `json:"pnl_pnl"`
+		PnlFee        string  `json:"pnl_fee"`
+		Text          string  `json:"text"`
+		LongPrice     string  `json:"long_price"`
+		ShortPrice    string  `json:"short_price"`
+		AccumSize     string  `json:"accum_size"`
+		FirstOpenTime int64   `json:"first_open_time"`
+	}
+	if err := json.Unmarshal(data, &closes); err != nil {
+		return nil, fmt.Errorf("failed to parse response: %w", err)
+	}
+
+	records := make([]ClosedPnLRecord, 0, len(closes))
+	for _, c := range closes {
+		record := ClosedPnLRecord{
+			Symbol:    CanonicalSymbol("gate", c.Contract),
+			Side:      c.Side,
+			Quantity:  math.Abs(parseFloatOrZero(c.AccumSize)) * t.quantoMultiplier(c.Contract),
+			Fee:       -parseFloatOrZero(c.PnlFee),
+			EntryTime: time.Unix(c.FirstOpenTime, 0),
+			ExitTime:  time.Unix(0, int64(c.Time*float64(time.Second))),
+			OrderID:   c.Text,
+			CloseType: "unknown",
+		}
+
+		// Long entries are buys and exits sells, short the other way around
+		longPrice := parseFloatOrZero(c.LongPrice)
+		shortPrice := parseFloatOrZero(c.ShortPrice)
+		if c.Side == "short" {
+			record.EntryPrice, record.ExitPrice = shortPrice, longPrice
+		} else {
+			record.EntryPrice, record.ExitPrice = longPrice, shortPrice
+		}
+
+		// pnl_pnl is the PnL before fees; older records only carry the net pnl
+		if c.PnlPnl != "" {
+			record.RealizedPnL = parseFloatOrZero(c.PnlPnl)
+		} else {
+			record.RealizedPnL = parseFloatOrZero(c.Pnl)
+		}
+
+		if strings.Contains(strings.ToLower(c.Text), "liq") {
+			record.CloseType = "liquidation"
+		}
+		records = append(records, record)
+	}
+
+	return records, nil
+}
+
+// GetTrades retrieves trade history (fills) from Gate
+// Gate fills don't carry realized PnL, so RealizedPnL is always 0; use GetClosedPnL for closed positions
+// Fills are returned newest first, so history is walked forward in 7-day windows paged by offset
+func (t *GateTrader) GetTrades(startTime time.Time, limit int) ([]TradeRecord, error) {
+	const (
+		window   = 7 * 24 * time.Hour
+		pageSize = 1000
+	)
+	if limit <= 0 {
+		limit = 1000
+	}
+
+	type gateFill struct {
+		TradeID    string  `json:"trade_id"`
+		CreateTime float64 `json:"create_time"`
+		Contract   string  `json:"contract"`
+		Size       int64   `json:"size"`       // Contracts, negative for sell
+		CloseSize  int64   `json:"close_size"` // Portion closing a position (negative closes long)
+		Price      string  `json:"price"`
+		Fee        string  `json:"fee"`
+	}
+
+	var trades []TradeRecord
+	now := time.Now()
+	for windowStart := startTime; windowStart.Before(now) && len(trades) < limit; windowStart = windowStart.Add(window) {
+		windowEnd := windowStart.Add(window)
+		if windowEnd.After(now) {
+			windowEnd = now
+		}
+
+		for offset := 0; ; offset += pageSize {
+			query := url.Values{
+				"from":   {strconv.FormatInt(windowStart.Unix(), 10)},
+				"to":     {strconv.FormatInt(windowEnd.Unix(), 10)},
+				"limit":  {strconv.Itoa(pageSize)},
+				"offset": {strconv.Itoa(offset)},
+			}
+			data, err := t.doRequest("GET", gateMyTradesPath, query, nil)
+			if err != nil {
+				return nil, fmt.Errorf("failed to get trade history: %w", err)
+			}
+
+			var fills []gateFill
+			if err := json.Unmarshal(data, &fills); err != nil {
+				return nil, fmt.Errorf("failed to parse trade history: %w", err)
+			}
+
+			for _, fill := range fills {
+				tradeTime := time.Unix(0, int64(fill.CreateTime*float64(time.Second)))
+				// from/to are inclusive whole seconds: keep each fill in exactly one window
+				if tradeTime.Before(windowStart) || !tradeTime.Before(windowEnd) {
+					continue
+				}
+
+				side := "BUY"
+				if fill.Size < 0 {
+					side = "SELL"
+				}
+
+				// A closing fill belongs to the closed side; an opening fill to the side it opens
+				var positionSide string
+				switch {
+				case fill.CloseSize < 0:
+					positionSide = "LONG"
+				case fill.CloseSize > 0:
+					positionSide = "SHORT"
+				case fill.Size > 0:
+					positionSide = "LONG"
+				default:
+					positionSide = "SHORT"
+				}
+
+				trades = append(trades, TradeRecord{
+					TradeID:      fill.TradeID,
+					Symbol:       CanonicalSymbol("gate", fill.Contract),
+					Side:         side,
+					PositionSide: positionSide,
+					Price:        parseFloatOrZero(fill.Price),
+					Quantity:     math.Abs(float64(fill.Size)) * t.quantoMultiplier(fill.Contract),
+					Fee:          parseFloatOrZero(fill.Fee),
+					Time:         tradeTime,
+				})
+			}
+
+			if len(fills) < pageSize {
+				break
+			}
+		}
+	}
+
+	return sortAndLimitTrades(trades, limit), nil
+}
+
+// GetFundingHistory retrieves funding payments from the account book (type=fund)
+func (t *GateTrader) GetFundingHistory(startTime time.Time) ([]FundingRecord, error) {
+	const pageSize = 1000
+
+	var records []FundingRecord
+	for offset := 0; ; offset += pageSize {
+		query := url.Values{
+			"type":   {"fund"},
+			"from":   {strconv.FormatInt(startTime.Unix(), 10)},
+			"limit":  {strconv.Itoa(pageSize)},
+			"offset": {strconv.Itoa(offset)},
+		}
+		data, err := t.doRequest("GET", gateAccountBookPath, query, nil)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get funding history: %w", err)
+		}
+
+		var entries []struct {
+			ID       string  `json:"id"`
+			Time     float64 `json:"time"`
+			Change   string  `json:"change"`
+			Contract string  `json:"contract"`
+		}
+		if err := json.Unmarshal(data, &entries); err != nil {
+			return nil, fmt.Errorf("failed to parse funding history: %w", err)
+		}
+
+		for _, entry := range entries {
+			records = append(records, FundingRecord{
+				Symbol: CanonicalSymbol("gate", entry.Contract),
+				Amount: parseFloatOrZero(entry.Change),
+				Time:   time.Unix(0, int64(entry.Time*float64(time.Second))),
+				TxID:   entry.ID,
+			})
+		}
+
+		if len(entries) < pageSize {
+			break
+		}
+	}
+
+	// Account book is returned newest first
+	sort.Slice(records, func(i, j int) bool { return records[i].Time.Before(records[j].Time) })
+	return records, nil
+}
+
+// clearCache clears all caches
+func (t *GateTrader) clearCache() {
+	t.balanceCacheMutex.Lock()
+	t.cachedBalance = nil
+	t.balanceCacheMutex.Unlock()
+
+	t.positionsCacheMutex.Lock()
+	t.cachedPositions = nil
+	t.positionsCacheMutex.Unlock()
+}
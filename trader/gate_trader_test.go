@@ -0,0 +1,387 @@
+package trader
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// ============================================================
+// 1. GateTraderTestSuite - inherits base test suite
+// ============================================================
+
+// GateTraderTestSuite Gate trader test suite
+// Inherits TraderTestSuite and adds Gate specific mock logic
+type GateTraderTestSuite struct {
+	*TraderTestSuite // Embeds base test suite
+	mockServer       *httptest.Server
+	gateTrader       *GateTrader
+
+	mu          sync.Mutex
+	orderBodies []map[string]interface{} // Bodies of submitted orders
+}
+
+// NewGateTraderTestSuite creates Gate test suite
+func NewGateTraderTestSuite(t *testing.T) *GateTraderTestSuite {
+	suite := &GateTraderTestSuite{}
+
+	// Create mock HTTP server
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path := strings.TrimPrefix(r.URL.Path, "/api/v4")
+
+		// Signed requests carry KEY, Timestamp and SIGN headers
+		if r.Header.Get("KEY") == "" || r.Header.Get("Timestamp") == "" || len(r.Header.Get("SIGN")) != 128 {
+			w.WriteHeader(http.StatusUnauthorized)
+			json.NewEncoder(w).Encode(map[string]interface{}{"label": "INVALID_SIGNATURE", "message": "Signature mismatch"})
+			return
+		}
+
+		var respBody interface{}
+
+		switch {
+		// Mock GetBalance
+		case path == "/futures/usdt/accounts":
+			respBody = map[string]interface{}{
+				"total":          "10000.00",
+				"unrealised_pnl": "100.50",
+				"available":      "8000.00",
+				"currency":       "USDT",
+			}
+
+		// Mock GetPositions (single mode, negative size = short)
+		case path == "/futures/usdt/positions":
+			respBody = []map[string]interface{}{
+				{
+					"contract":             "BTC_USDT",
+					"size":                 5000,
+					"entry_price":          "50000.0",
+					"mark_price":           "50500.0",
+					"unrealised_pnl":       "250.00",
+					"leverage":             "0",
+					"cross_leverage_limit": "10",
+					"liq_price":            "45000.0",
+					"open_time":            1700000000,
+					"update_time":          1700000100,
+				},
+			}
+
+		// Mock position leverage update
+		case strings.HasSuffix(path, "/leverage") && r.Method == "POST":
+			respBody = map[string]interface{}{"contract": "BTC_USDT", "leverage": r.URL.Query().Get("leverage")}
+
+		// Mock single position
+		case strings.HasPrefix(path, "/futures/usdt/positions/"):
+			respBody = map[string]interface{}{
+				"contract":             strings.TrimPrefix(path, "/futures/usdt/positions/"),
+				"size":                 0,
+				"leverage":             "0",
+				"cross_leverage_limit": "10",
+			}
+
+		// Mock contracts
+		case path == "/futures/usdt/contracts":
+			respBody = []map[string]interface{}{
+				{
+					"name":              "BTC_USDT",
+					"quanto_multiplier": "0.0001",
+					"order_price_round": "0.1",
+					"order_size_min":    1,
+					"leverage_max":      "100",
+				},
+				{
+					"name":              "ETH_USDT",
+					"quanto_multiplier": "0.001",
+					"order_price_round": "0.01",
+					"order_size_min":    1,
+					"leverage_max":      "100",
+				},
+				{
+					"name":              "OLD_USDT",
+					"quanto_multiplier": "1",
+					"order_price_round": "0.0001",
+					"order_size_min":    1,
+					"leverage_max":      "20",
+					"in_delisting":      true,
+				},
+			}
+
+		// Mock GetMarketPrice
+		case path == "/futures/usdt/tickers":
+			contract := r.URL.Query().Get("contract")
+			price := "50000.0"
+			if contract == "ETH_USDT" {
+				price = "3000.00"
+			} else if contract == "INVALID_USDT" {
+				w.WriteHeader(http.StatusBadRequest)
+				json.NewEncoder(w).Encode(map[string]interface{}{
+					"label":   "CONTRACT_NOT_FOUND",
+					"message": "Contract not found",
+				})
+				return
+			}
+			respBody = []map[string]interface{}{{"contract": contract, "last": price}}
+
+		// Mock CreateOrder (market IOC orders fill immediately)
+		case path == "/futures/usdt/orders" && r.Method == "POST":
+			bodyBytes, _ := io.ReadAll(r.Body)
+			var order map[string]interface{}
+			json.Unmarshal(bodyBytes, &order)
+			suite.mu.Lock()
+			suite.orderBodies = append(suite.orderBodies, order)
+			suite.mu.Unlock()
+
+			respBody = map[string]interface{}{
+				"id":          123456,
+				"contract":    order["contract"],
+				"size":        order["size"],
+				"left":        0,
+				"fill_price":  "50000.0",
+				"status":      "finished",
+				"finish_as":   "filled",
+				"text":        order["text"],
+				"create_time": 1700000000.123,
+				"finish_time": 1700000000.456,
+			}
+
+		// Mock GetOrderStatus
+		case strings.HasPrefix(path, "/futures/usdt/orders/"):
+			respBody = map[string]interface{}{
+				"id":         123456,
+				"contract":   "BTC_USDT",
+				"size":       -100,
+				"left":       40,
+				"fill_price": "50010.0",
+				"status":     "finished",
+				"finish_as":  "ioc",
+				"text":       strings.TrimPrefix(path, "/futures/usdt/orders/"),
+			}
+
+		// Mock cancel orders / list and cancel price-triggered orders
+		case path == "/futures/usdt/orders" && r.Method == "DELETE",
+			strings.HasPrefix(path, "/futures/usdt/price_orders") && r.Method != "POST":
+			respBody = []map[string]interface{}{}
+
+		// Mock SetStopLoss / SetTakeProfit
+		case path == "/futures/usdt/price_orders" && r.Method == "POST":
+			respBody = map[string]interface{}{"id": 789}
+
+		// Mock position close history
+		case path == "/futures/usdt/position_close":
+			respBody = []map[string]interface{}{
+				{
+					"time":            1700003600.5,
+					"contract":        "ETH_USDT",
+					"side":            "short",
+					"pnl":             "9.5",
+					"pnl_pnl":         "10",
+					"pnl_fee":         "-0.5",
+					"text":            "web",
+					"long_price":      "2900",
+					"short_price":     "3000",
+					"accum_size":      "100",
+					"first_open_time": 1700000000,
+				},
+			}
+
+		// Mock trade history
+		case path == "/futures/usdt/my_trades_timerange":
+			respBody = []map[string]interface{}{
+				{"trade_id": "2", "create_time": 1700003600.0, "contract": "BTC_USDT", "size": -100, "close_size": -100, "price": "51000", "fee": "0.02"},
+				{"trade_id": "1", "create_time": 1700000000.0, "contract": "BTC_USDT", "size": 100, "close_size": 0, "price": "50000", "fee": "0.01"},
+			}
+
+		// Default: empty response
+		default:
+			respBody = map[string]interface{}{}
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(respBody)
+	}))
+
+	// Create mock trader using mock server's URL
+	trader := &GateTrader{
+		apiKey:        "test-key",
+		secretKey:     "test-secret",
+		baseURL:       mockServer.URL,
+		httpClient:    mockServer.Client(),
+		cacheDuration: 15 * time.Second,
+		marginModes:   make(map[string]bool),
+	}
+
+	suite.TraderTestSuite = NewTraderTestSuite(t, trader)
+	suite.mockServer = mockServer
+	suite.gateTrader = trader
+	return suite
+}
+
+// Cleanup cleans up resources
+func (s *GateTraderTestSuite) Cleanup() {
+	if s.mockServer != nil {
+		s.mockServer.Close()
+	}
+	s.TraderTestSuite.Cleanup()
+}
+
+// lastOrder returns the body of the last submitted order
+func (s *GateTraderTestSuite) lastOrder() map[string]interface{} {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if len(s.orderBodies) == 0 {
+		return nil
+	}
+	return s.orderBodies[len(s.orderBodies)-1]
+}
+
+// ============================================================
+// 2. Run common tests using GateTraderTestSuite
+// ============================================================
+
+// TestGateTrader_InterfaceCompliance tests interface compliance
+func TestGateTrader_InterfaceCompliance(t *testing.T) {
+	var _ Trader = (*GateTrader)(nil)
+	var _ ClientOrderTrader = (*GateTrader)(nil)
+	var _ InstrumentProvider = (*GateTrader)(nil)
+	var _ FundingHistoryProvider = (*GateTrader)(nil)
+}
+
+// TestGateTrader_CommonInterface runs all common interface tests using test suite
+func TestGateTrader_CommonInterface(t *testing.T) {
+	suite := NewGateTraderTestSuite(t)
+	defer suite.Cleanup()
+
+	suite.RunAllTests()
+}
+
+// ============================================================
+// 3. Gate specific unit tests
+// ============================================================
+
+// TestGateTrader_PositionsInBaseUnits tests contract sizes are converted to base asset
+func TestGateTrader_PositionsInBaseUnits(t *testing.T) {
+	suite := NewGateTraderTestSuite(t)
+	defer suite.Cleanup()
+
+	positions, err := suite.gateTrader.GetPositions()
+	require.NoError(t, err)
+	require.Len(t, positions, 1)
+	assert.Equal(t, "BTCUSDT", positions[0]["symbol"])
+	assert.Equal(t, "long", positions[0]["side"])
+	assert.InDelta(t, 0.5, positions[0]["positionAmt"], 1e-9)
+	assert.Equal(t, 10.0, positions[0]["leverage"]) // Cross margin reports cross_leverage_limit
+}
+
+// TestGateTrader_OrderSizeInContracts tests orders are submitted as signed contract counts
+func TestGateTrader_OrderSizeInContracts(t *testing.T) {
+	suite := NewGateTraderTestSuite(t)
+	defer suite.Cleanup()
+
+	_, err := suite.gateTrader.OpenShortWithClientID("ETHUSDT", 0.0255, 5, "0123456789abcdef0123456789abcdef")
+	require.NoError(t, err)
+	order := suite.lastOrder()
+	assert.Equal(t, "ETH_USDT", order["contract"])
+	assert.Equal(t, -25.0, order["size"]) // 0.0255 ETH / 0.001 rounded down, negative = sell
+	assert.Equal(t, "ioc", order["tif"])
+	assert.Equal(t, false, order["reduce_only"])
+	assert.Equal(t, "t-0123456789abcdef0123456789ab", order["text"])
+
+	_, err = suite.gateTrader.CloseLong("BTCUSDT", 0)
+	require.NoError(t, err)
+	order = suite.lastOrder()
+	assert.Equal(t, -5000.0, order["size"]) // Whole 0.5 BTC position
+	assert.Equal(t, true, order["reduce_only"])
+
+	_, err = suite.gateTrader.OpenLong("BTCUSDT", 0.00005, 10)
+	assert.ErrorContains(t, err, "below the minimum")
+}
+
+// TestGateTrader_GetOrderStatus tests order status mapping of a partially filled IOC order
+func TestGateTrader_GetOrderStatus(t *testing.T) {
+	suite := NewGateTraderTestSuite(t)
+	defer suite.Cleanup()
+
+	status, err := suite.gateTrader.GetOrderStatus("BTCUSDT", "123456")
+	require.NoError(t, err)
+	assert.Equal(t, "FILLED", status["status"])
+	assert.Equal(t, "SELL", status["side"])
+	assert.InDelta(t, 0.006, status["executedQty"], 1e-9) // 60 of 100 contracts filled
+	assert.Equal(t, 50010.0, status["avgPrice"])
+}
+
+// TestGateTrader_GetClosedPnL tests position close history mapping
+func TestGateTrader_GetClosedPnL(t *testing.T) {
+	suite := NewGateTraderTestSuite(t)
+	defer suite.Cleanup()
+
+	records, err := suite.gateTrader.GetClosedPnL(time.Unix(1700000000, 0), 10)
+	require.NoError(t, err)
+	require.Len(t, records, 1)
+
+	r := records[0]
+	assert.Equal(t, "ETHUSDT", r.Symbol)
+	assert.Equal(t, "short", r.Side)
+	assert.Equal(t, 3000.0, r.EntryPrice)
+	assert.Equal(t, 2900.0, r.ExitPrice)
+	assert.InDelta(t, 0.1, r.Quantity, 1e-9)
+	assert.Equal(t, 10.0, r.RealizedPnL)
+	assert.Equal(t, 0.5, r.Fee)
+	assert.Equal(t, int64(1700003600), r.ExitTime.Unix())
+}
+
+// TestGateTrader_GetTrades tests fill history mapping
+func TestGateTrader_GetTrades(t *testing.T) {
+	suite := NewGateTraderTestSuite(t)
+	defer suite.Cleanup()
+
+	trades, err := suite.gateTrader.GetTrades(time.Unix(1700000000, 0), 100)
+	require.NoError(t, err)
+	require.Len(t, trades, 2)
+
+	// Oldest first
+	assert.Equal(t, "1", trades[0].TradeID)
+	assert.Equal(t, "BUY", trades[0].Side)
+	assert.Equal(t, "LONG", trades[0].PositionSide)
+	assert.InDelta(t, 0.01, trades[0].Quantity, 1e-9)
+
+	assert.Equal(t, "SELL", trades[1].Side)
+	assert.Equal(t, "LONG", trades[1].PositionSide) // Closes the long
+	assert.Equal(t, 51000.0, trades[1].Price)
+}
+
+// TestGateTrader_GetInstruments tests contract rules converted to base units
+func TestGateTrader_GetInstruments(t *testing.T) {
+	suite := NewGateTraderTestSuite(t)
+	defer suite.Cleanup()
+
+	instruments, err := suite.gateTrader.GetInstruments()
+	require.NoError(t, err)
+	require.Len(t, instruments, 2) // Delisting contract skipped
+
+	bySymbol := make(map[string]Instrument)
+	for _, inst := range instruments {
+		bySymbol[inst.Symbol] = inst
+	}
+	btc := bySymbol["BTCUSDT"]
+	assert.Equal(t, "BTC_USDT", btc.VenueSymbol)
+	assert.Equal(t, 0.0001, btc.LotStep)
+	assert.Equal(t, 0.0001, btc.MinQty)
+	assert.Equal(t, 0.1, btc.TickSize)
+	assert.Equal(t, 100, btc.MaxLeverage)
+}
+
+// TestGateSymbolMapping tests canonical and Gate contract name conversion
+func TestGateSymbolMapping(t *testing.T) {
+	assert.Equal(t, "BTC_USDT", VenueSymbol("gate", "btcusdt"))
+	assert.Equal(t, "BTC_USDT", VenueSymbol("gate", "BTC_USDT"))
+	assert.Equal(t, "BTCUSDT", CanonicalSymbol("gate", "BTC_USDT"))
+	assert.Equal(t, "t-abc", gateOrderText("abc"))
+	assert.Equal(t, "t-abc", gateOrderText("t-abc"))
+}
@@ -74,3 +74,11 @@ func SafeInt(data map[string]interface{}, key string) (int, error) {
 		return 0, fmt.Errorf("value for key '%s' is not an integer (type: %T)", key, v)
 	}
 }
+
+// environmentName returns the trading environment label shown in logs and trader status
+func environmentName(testnet bool) string {
+	if testnet {
+		return "testnet"
+	}
+	return "mainnet"
+}
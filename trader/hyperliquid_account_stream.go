@@ -0,0 +1,287 @@
+package trader
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+)
+
+const (
+	hyperliquidMainnetWSURL = "wss://api.hyperliquid.xyz/ws"
+	hyperliquidTestnetWSURL = "wss://api.hyperliquid-testnet.xyz/ws"
+	hyperliquidWSPing       = 30 * time.Second
+)
+
+// hyperliquidWSMessage Hyperliquid websocket message
+type hyperliquidWSMessage struct {
+	Channel string          `json:"channel"`
+	Data    json.RawMessage `json:"data"`
+}
+
+// hyperliquidWSFill fill item of the userFills subscription
+type hyperliquidWSFill struct {
+	Coin          string          `json:"coin"`
+	Px            string          `json:"px"`
+	Sz            string          `json:"sz"`
+	Side          string          `json:"side"` // B = buy, A = sell
+	Time          int64           `json:"time"`
+	StartPosition string          `json:"startPosition"`
+	Dir           string          `json:"dir"`
+	ClosedPnl     string          `json:"closedPnl"`
+	Oid           int64           `json:"oid"`
+	Fee           string          `json:"fee"`
+	Cloid         string          `json:"cloid,omitempty"`
+	Liquidation   json.RawMessage `json:"liquidation,omitempty"`
+}
+
+// hyperliquidWSOrderUpdate item of the orderUpdates subscription
+type hyperliquidWSOrderUpdate struct {
+	Order struct {
+		Coin   string `json:"coin"`
+		Sz     string `json:"sz"` // Remaining size
+		Oid    int64  `json:"oid"`
+		OrigSz string `json:"origSz"`
+		Cloid  string `json:"cloid,omitempty"`
+	} `json:"order"`
+	Status          string `json:"status"` // open / filled / canceled / triggered / rejected / ...
+	StatusTimestamp int64  `json:"statusTimestamp"`
+}
+
+// StreamAccount streams Hyperliquid user fills until ctx is cancelled or the stream drops
+// Hyperliquid has no position push, so position updates are derived from each fill's
+// startPosition. Fills don't say whether a trigger order was a stop loss or take profit,
+// only liquidations are reported as close reason. Fills don't say whether the order is
+// complete either, order updates provide the size it completes at.
+func (t *HyperliquidTrader) StreamAccount(ctx context.Context, handler func(AccountEvent)) error {
+	url := hyperliquidMainnetWSURL
+	if t.isTestnet {
+		url = hyperliquidTestnetWSURL
+	}
+
+	onOpen := func(c *accountWSConn) error {
+		for _, channel := range []string{"userFills", "orderUpdates"} {
+			err := c.writeJSON(map[string]interface{}{
+				"method": "subscribe",
+				"subscription": map[string]string{
+					"type": channel,
+					"user": t.accountAddr(),
+				},
+			})
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	ping := func(c *accountWSConn) error {
+		return c.writeJSON(map[string]string{"method": "ping"})
+	}
+
+	orders := newHyperliquidOrderTracker()
+	onMessage := func(c *accountWSConn, raw []byte) error {
+		var msg hyperliquidWSMessage
+		if err := json.Unmarshal(raw, &msg); err != nil {
+			return nil // Ignore malformed messages
+		}
+
+		switch msg.Channel {
+		case "subscriptionResponse":
+			var data struct {
+				Subscription struct {
+					Type string `json:"type"`
+				} `json:"subscription"`
+			}
+			if err := json.Unmarshal(msg.Data, &data); err == nil && data.Subscription.Type == "userFills" {
+				handler(AccountEvent{Type: AccountEventConnected, Time: time.Now()})
+			}
+		case "error":
+			return fmt.Errorf("hyperliquid stream error: %s", string(msg.Data))
+		case "userFills":
+			var data struct {
+				IsSnapshot bool                `json:"isSnapshot"`
+				Fills      []hyperliquidWSFill `json:"fills"`
+			}
+			if err := json.Unmarshal(msg.Data, &data); err != nil || data.IsSnapshot {
+				return nil // Snapshot contains historical fills already known
+			}
+			for _, fill := range data.Fills {
+				for _, ev := range hyperliquidFillEvents(fill, orders.fill(fill)) {
+					handler(ev)
+				}
+			}
+		case "orderUpdates":
+			var updates []hyperliquidWSOrderUpdate
+			if err := json.Unmarshal(msg.Data, &updates); err != nil {
+				return nil
+			}
+			for _, update := range updates {
+				if ev := orders.update(update); ev != nil {
+					handler(*ev)
+				}
+			}
+		}
+		return nil
+	}
+
+	return runAccountWebsocket(ctx, url, hyperliquidWSPing, onOpen, ping, onMessage)
+}
+
+// hyperliquidOrderTracker derives order completion from the size filled so far and the size
+// the order completes at (its original size, or the filled part once canceled)
+// Fills and order updates arrive on separate channels, in either order.
+type hyperliquidOrderTracker struct {
+	orders map[int64]*hyperliquidTrackedOrder
+}
+
+// hyperliquidTrackedOrder fill progress of one order
+type hyperliquidTrackedOrder struct {
+	size    float64 // Size the order completes at (0 = not reported yet)
+	filled  float64
+	done    bool
+	updated time.Time
+}
+
+func newHyperliquidOrderTracker() *hyperliquidOrderTracker {
+	return &hyperliquidOrderTracker{orders: make(map[int64]*hyperliquidTrackedOrder)}
+}
+
+// get returns the tracked order, dropping orders idle longer than the fill hub keeps fills
+func (t *hyperliquidOrderTracker) get(oid int64) *hyperliquidTrackedOrder {
+	now := time.Now()
+	for id, order := range t.orders {
+		if now.Sub(order.updated) > fillHubRetention {
+			delete(t.orders, id)
+		}
+	}
+	order, ok := t.orders[oid]
+	if !ok {
+		order = &hyperliquidTrackedOrder{}
+		t.orders[oid] = order
+	}
+	order.updated = now
+	return order
+}
+
+// complete marks the order done once its fills reach the size it completes at
+func (o *hyperliquidTrackedOrder) complete() bool {
+	if o.done || o.size <= 0 || o.filled < o.size-1e-9 {
+		return false
+	}
+	o.done = true
+	return true
+}
+
+// fill adds a fill, returns whether it completes its order
+func (t *hyperliquidOrderTracker) fill(fill hyperliquidWSFill) bool {
+	qty, _ := strconv.ParseFloat(fill.Sz, 64)
+	order := t.get(fill.Oid)
+	order.filled += qty
+	return order.complete()
+}
+
+// update applies an order update, returns a completion event when the order's fills were already
+// received (nil otherwise, the completing fill reports it)
+func (t *hyperliquidOrderTracker) update(update hyperliquidWSOrderUpdate) *AccountEvent {
+	origSz, _ := strconv.ParseFloat(update.Order.OrigSz, 64)
+	remaining, _ := strconv.ParseFloat(update.Order.Sz, 64)
+
+	order := t.get(update.Order.Oid)
+	switch update.Status {
+	case "open", "triggered":
+		order.size = origSz
+	default:
+		// Final: the order completes at what was filled before it was canceled or rejected
+		order.size = origSz - remaining
+	}
+	if order.size <= 1e-9 {
+		delete(t.orders, update.Order.Oid) // Nothing filled
+		return nil
+	}
+	if !order.complete() {
+		return nil
+	}
+	return &AccountEvent{
+		Type:          AccountEventOrderFill,
+		Time:          time.UnixMilli(update.StatusTimestamp),
+		Symbol:        update.Order.Coin + "USDT",
+		OrderID:       strconv.FormatInt(update.Order.Oid, 10),
+		ClientOrderID: update.Order.Cloid,
+		OrderFilled:   true,
+	}
+}
+
+// hyperliquidFillEvents converts a fill to a fill event plus the resulting position update(s)
+// orderFilled reports whether the fill completes its order
+func hyperliquidFillEvents(fill hyperliquidWSFill, orderFilled bool) []AccountEvent {
+	symbol := fill.Coin + "USDT"
+	eventTime := time.UnixMilli(fill.Time)
+
+	price, _ := strconv.ParseFloat(fill.Px, 64)
+	qty, _ := strconv.ParseFloat(fill.Sz, 64)
+	fee, _ := strconv.ParseFloat(fill.Fee, 64)
+	pnl, _ := strconv.ParseFloat(fill.ClosedPnl, 64)
+	startPos, _ := strconv.ParseFloat(fill.StartPosition, 64)
+
+	endPos := startPos + qty
+	if fill.Side == "A" {
+		endPos = startPos - qty
+	}
+	if absFloat(endPos) < 1e-9 {
+		endPos = 0 // Float noise on full close
+	}
+
+	// A fill reduces the position when it moves it towards zero
+	reduceOnly := (startPos > 0 && endPos < startPos) || (startPos < 0 && endPos > startPos)
+	side := "LONG"
+	if (reduceOnly && startPos < 0) || (!reduceOnly && fill.Side == "A") {
+		side = "SHORT"
+	}
+
+	closeReason := ""
+	if len(fill.Liquidation) > 0 && string(fill.Liquidation) != "null" {
+		closeReason = CloseReasonLiquidation
+	}
+
+	events := []AccountEvent{{
+		Type:          AccountEventOrderFill,
+		Time:          eventTime,
+		Symbol:        symbol,
+		Side:          side,
+		OrderID:       strconv.FormatInt(fill.Oid, 10),
+		ClientOrderID: fill.Cloid,
+		Price:         price,
+		Quantity:      qty,
+		OrderFilled:   orderFilled,
+		ReduceOnly:    reduceOnly,
+		RealizedPnL:   pnl,
+		Fee:           fee,
+		CloseReason:   closeReason,
+	}}
+
+	// Position flipped (e.g. "Long > Short"): old side closed, new side opened
+	if startPos*endPos < 0 {
+		oldSide, newSide := "LONG", "SHORT"
+		if startPos < 0 {
+			oldSide, newSide = "SHORT", "LONG"
+		}
+		return append(events,
+			AccountEvent{Type: AccountEventPosition, Time: eventTime, Symbol: symbol, Side: oldSide, PositionAmt: 0},
+			AccountEvent{Type: AccountEventPosition, Time: eventTime, Symbol: symbol, Side: newSide, PositionAmt: absFloat(endPos), EntryPrice: price},
+		)
+	}
+
+	posSide := "LONG"
+	if endPos < 0 || (endPos == 0 && startPos < 0) {
+		posSide = "SHORT"
+	}
+	return append(events, AccountEvent{
+		Type:        AccountEventPosition,
+		Time:        eventTime,
+		Symbol:      symbol,
+		Side:        posSide,
+		PositionAmt: absFloat(endPos),
+	})
+}
@@ -1,16 +1,21 @@
 package trader
 
 import (
+	"bytes"
 	"context"
 	"crypto/ecdsa"
 	"encoding/json"
 	"fmt"
+	"io"
+	"math"
+	"net/http"
 	"nofx/logger"
 	"strconv"
 	"strings"
 	"sync"
 	"time"
 
+	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/sonirico/go-hyperliquid"
 )
@@ -20,13 +25,17 @@ type HyperliquidTrader struct {
 	exchange      *hyperliquid.Exchange
 	ctx           context.Context
 	walletAddr    string
+	vaultAddr     string            // Sub-account or vault traded by this trader (empty = main wallet)
 	meta          *hyperliquid.Meta // Cache meta information (including precision)
 	metaMutex     sync.RWMutex      // Protect concurrent access to meta field
 	isCrossMargin bool              // Whether to use cross margin mode
+	isTestnet     bool              // Whether connected to testnet (selects websocket endpoint)
 }
 
 // NewHyperliquidTrader creates a Hyperliquid trader
-func NewHyperliquidTrader(privateKeyHex string, walletAddr string, testnet bool) (*HyperliquidTrader, error) {
+// vaultAddr optionally targets a sub-account or vault: actions are sent with vaultAddress and
+// balances, positions, orders and fills are read from that address instead of the main wallet
+func NewHyperliquidTrader(privateKeyHex string, walletAddr string, vaultAddr string, testnet bool) (*HyperliquidTrader, error) {
 	// Remove 0x prefix from private key (if present, case-insensitive)
 	privateKeyHex = strings.TrimPrefix(strings.ToLower(privateKeyHex), "0x")
 
@@ -67,6 +76,19 @@ func NewHyperliquidTrader(privateKeyHex string, walletAddr string, testnet bool)
 		logger.Infof("  └─ Main wallet address: %s (holds funds)", walletAddr)
 	}
 
+	vaultAddr = strings.TrimSpace(vaultAddr)
+	if vaultAddr != "" {
+		if !common.IsHexAddress(vaultAddr) {
+			return nil, fmt.Errorf("invalid sub-account/vault address: %s", vaultAddr)
+		}
+		if strings.EqualFold(vaultAddr, walletAddr) {
+			vaultAddr = "" // Main wallet itself, no vaultAddress needed
+		} else {
+			vaultAddr = strings.ToLower(vaultAddr)
+			logger.Infof("  └─ Sub-account/vault address: %s (traded account)", vaultAddr)
+		}
+	}
+
 	ctx := context.Background()
 
 	// Create Exchange client (Exchange includes Info functionality)
@@ -75,12 +97,12 @@ func NewHyperliquidTrader(privateKeyHex string, walletAddr string, testnet bool)
 		privateKey,
 		apiURL,
 		nil,        // Meta will be fetched automatically
-		"",         // vault address (empty for personal account)
+		vaultAddr,  // vault address (empty for personal account)
 		walletAddr, // wallet address
 		nil,        // SpotMeta will be fetched automatically
 	)
 
-	logger.Infof("✓ Hyperliquid trader initialized successfully (testnet=%v, wallet=%s)", testnet, walletAddr)
+	logger.Infof("✓ Hyperliquid trader initialized successfully (testnet=%v, wallet=%s, vault=%s)", testnet, walletAddr, vaultAddr)
 
 	// Get meta information (including precision and other configurations)
 	meta, err := exchange.Info().Meta(ctx)
@@ -125,17 +147,27 @@ func NewHyperliquidTrader(privateKeyHex string, walletAddr string, testnet bool)
 		exchange:      exchange,
 		ctx:           ctx,
 		walletAddr:    walletAddr,
+		vaultAddr:     vaultAddr,
 		meta:          meta,
 		isCrossMargin: true, // Use cross margin mode by default
+		isTestnet:     testnet,
 	}, nil
 }
 
+// accountAddr returns the address whose balances, positions and orders this trader manages
+func (t *HyperliquidTrader) accountAddr() string {
+	if t.vaultAddr != "" {
+		return t.vaultAddr
+	}
+	return t.walletAddr
+}
+
 // GetBalance gets account balance
 func (t *HyperliquidTrader) GetBalance() (map[string]interface{}, error) {
 	logger.Infof("🔄 Calling Hyperliquid API to get account balance...")
 
 	// ✅ Step 1: Query Spot account balance
-	spotState, err := t.exchange.Info().SpotUserState(t.ctx, t.walletAddr)
+	spotState, err := t.exchange.Info().SpotUserState(t.ctx, t.accountAddr())
 	var spotUSDCBalance float64 = 0.0
 	if err != nil {
 		logger.Infof("⚠️ Failed to query Spot balance (may have no spot assets): %v", err)
@@ -150,7 +182,7 @@ func (t *HyperliquidTrader) GetBalance() (map[string]interface{}, error) {
 	}
 
 	// ✅ Step 2: Query Perpetuals contract account status
-	accountState, err := t.exchange.Info().UserState(t.ctx, t.walletAddr)
+	accountState, err := t.exchange.Info().UserState(t.ctx, t.accountAddr())
 	if err != nil {
 		logger.Infof("❌ Hyperliquid Perpetuals API call failed: %v", err)
 		return nil, fmt.Errorf("failed to get account information: %w", err)
@@ -227,6 +259,7 @@ func (t *HyperliquidTrader) GetBalance() (map[string]interface{}, error) {
 	result["availableBalance"] = availableBalance        // Available balance (Perpetuals only, excluding Spot)
 	result["totalUnrealizedProfit"] = totalUnrealizedPnl // Unrealized PnL (from Perpetuals only)
 	result["spotBalance"] = spotUSDCBalance              // Spot balance (returned separately)
+	result["total_equity"] = totalWalletBalance + totalUnrealizedPnl
 
 	logger.Infof("✓ Hyperliquid complete account:")
 	logger.Infof("  • Spot balance: %.2f USDC (manual transfer to Perpetuals required for opening positions)", spotUSDCBalance)
@@ -246,7 +279,7 @@ func (t *HyperliquidTrader) GetBalance() (map[string]interface{}, error) {
 // GetPositions gets all positions
 func (t *HyperliquidTrader) GetPositions() ([]map[string]interface{}, error) {
 	// Get account status
-	accountState, err := t.exchange.Info().UserState(t.ctx, t.walletAddr)
+	accountState, err := t.exchange.Info().UserState(t.ctx, t.accountAddr())
 	if err != nil {
 		return nil, fmt.Errorf("failed to get positions: %w", err)
 	}
@@ -374,6 +407,11 @@ func (t *HyperliquidTrader) refreshMetaIfNeeded(coin string) error {
 
 // OpenLong opens a long position
 func (t *HyperliquidTrader) OpenLong(symbol string, quantity float64, leverage int) (map[string]interface{}, error) {
+	return t.OpenLongWithClientID(symbol, quantity, leverage, "")
+}
+
+// OpenLongWithClientID opens a long position tagged with client order ID (cloid)
+func (t *HyperliquidTrader) OpenLongWithClientID(symbol string, quantity float64, leverage int, clientOrderID string) (map[string]interface{}, error) {
 	// First cancel all pending orders for this coin
 	if err := t.CancelAllOrders(symbol); err != nil {
 		logger.Infof("  ⚠ Failed to cancel old pending orders: %v", err)
@@ -415,7 +453,7 @@ func (t *HyperliquidTrader) OpenLong(symbol string, quantity float64, leverage i
 		ReduceOnly: false,
 	}
 
-	_, err = t.exchange.Order(t.ctx, order, nil)
+	err = t.placeOrder(order, clientOrderID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to open long position: %w", err)
 	}
@@ -426,12 +464,20 @@ func (t *HyperliquidTrader) OpenLong(symbol string, quantity float64, leverage i
 	result["orderId"] = 0 // Hyperliquid does not return order ID
 	result["symbol"] = symbol
 	result["status"] = "FILLED"
+	if cloid := hyperliquidCloid(clientOrderID); cloid != "" {
+		result["clientOrderId"] = cloid
+	}
 
 	return result, nil
 }
 
 // OpenShort opens a short position
 func (t *HyperliquidTrader) OpenShort(symbol string, quantity float64, leverage int) (map[string]interface{}, error) {
+	return t.OpenShortWithClientID(symbol, quantity, leverage, "")
+}
+
+// OpenShortWithClientID opens a short position tagged with client order ID (cloid)
+func (t *HyperliquidTrader) OpenShortWithClientID(symbol string, quantity float64, leverage int, clientOrderID string) (map[string]interface{}, error) {
 	// First cancel all pending orders for this coin
 	if err := t.CancelAllOrders(symbol); err != nil {
 		logger.Infof("  ⚠ Failed to cancel old pending orders: %v", err)
@@ -473,7 +519,7 @@ func (t *HyperliquidTrader) OpenShort(symbol string, quantity float64, leverage
 		ReduceOnly: false,
 	}
 
-	_, err = t.exchange.Order(t.ctx, order, nil)
+	err = t.placeOrder(order, clientOrderID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to open short position: %w", err)
 	}
@@ -484,12 +530,20 @@ func (t *HyperliquidTrader) OpenShort(symbol string, quantity float64, leverage
 	result["orderId"] = 0
 	result["symbol"] = symbol
 	result["status"] = "FILLED"
+	if cloid := hyperliquidCloid(clientOrderID); cloid != "" {
+		result["clientOrderId"] = cloid
+	}
 
 	return result, nil
 }
 
 // CloseLong closes a long position
 func (t *HyperliquidTrader) CloseLong(symbol string, quantity float64) (map[string]interface{}, error) {
+	return t.CloseLongWithClientID(symbol, quantity, "")
+}
+
+// CloseLongWithClientID closes a long position tagged with client order ID (cloid)
+func (t *HyperliquidTrader) CloseLongWithClientID(symbol string, quantity float64, clientOrderID string) (map[string]interface{}, error) {
 	// If quantity is 0, get current position quantity
 	if quantity == 0 {
 		positions, err := t.GetPositions()
@@ -540,7 +594,7 @@ func (t *HyperliquidTrader) CloseLong(symbol string, quantity float64) (map[stri
 		ReduceOnly: true, // Only close position, don't open new position
 	}
 
-	_, err = t.exchange.Order(t.ctx, order, nil)
+	err = t.placeOrder(order, clientOrderID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to close long position: %w", err)
 	}
@@ -556,12 +610,20 @@ func (t *HyperliquidTrader) CloseLong(symbol string, quantity float64) (map[stri
 	result["orderId"] = 0
 	result["symbol"] = symbol
 	result["status"] = "FILLED"
+	if cloid := hyperliquidCloid(clientOrderID); cloid != "" {
+		result["clientOrderId"] = cloid
+	}
 
 	return result, nil
 }
 
 // CloseShort closes a short position
 func (t *HyperliquidTrader) CloseShort(symbol string, quantity float64) (map[string]interface{}, error) {
+	return t.CloseShortWithClientID(symbol, quantity, "")
+}
+
+// CloseShortWithClientID closes a short position tagged with client order ID (cloid)
+func (t *HyperliquidTrader) CloseShortWithClientID(symbol string, quantity float64, clientOrderID string) (map[string]interface{}, error) {
 	// If quantity is 0, get current position quantity
 	if quantity == 0 {
 		positions, err := t.GetPositions()
@@ -612,7 +674,7 @@ func (t *HyperliquidTrader) CloseShort(symbol string, quantity float64) (map[str
 		ReduceOnly: true,
 	}
 
-	_, err = t.exchange.Order(t.ctx, order, nil)
+	err = t.placeOrder(order, clientOrderID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to close short position: %w", err)
 	}
@@ -628,6 +690,9 @@ func (t *HyperliquidTrader) CloseShort(symbol string, quantity float64) (map[str
 	result["orderId"] = 0
 	result["symbol"] = symbol
 	result["status"] = "FILLED"
+	if cloid := hyperliquidCloid(clientOrderID); cloid != "" {
+		result["clientOrderId"] = cloid
+	}
 
 	return result, nil
 }
@@ -653,7 +718,7 @@ func (t *HyperliquidTrader) CancelAllOrders(symbol string) error {
 	coin := convertSymbolToHyperliquid(symbol)
 
 	// Get all pending orders
-	openOrders, err := t.exchange.Info().OpenOrders(t.ctx, t.walletAddr)
+	openOrders, err := t.exchange.Info().OpenOrders(t.ctx, t.accountAddr())
 	if err != nil {
 		return fmt.Errorf("failed to get pending orders: %w", err)
 	}
@@ -677,7 +742,7 @@ func (t *HyperliquidTrader) CancelStopOrders(symbol string) error {
 	coin := convertSymbolToHyperliquid(symbol)
 
 	// Get all pending orders
-	openOrders, err := t.exchange.Info().OpenOrders(t.ctx, t.walletAddr)
+	openOrders, err := t.exchange.Info().OpenOrders(t.ctx, t.accountAddr())
 	if err != nil {
 		return fmt.Errorf("failed to get pending orders: %w", err)
 	}
@@ -802,14 +867,36 @@ func (t *HyperliquidTrader) SetTakeProfit(symbol string, positionSide string, qu
 	return nil
 }
 
-// FormatQuantity formats quantity to correct precision
+// FormatQuantity formats quantity rounded down to the lot step of the instrument registry
 func (t *HyperliquidTrader) FormatQuantity(symbol string, quantity float64) (string, error) {
-	coin := convertSymbolToHyperliquid(symbol)
-	szDecimals := t.getSzDecimals(coin)
+	return t.instrument(symbol).FormatQuantity(quantity), nil
+}
+
+// GetInstruments gets trading rules of all perpetuals (implements InstrumentProvider)
+// Hyperliquid prices use 5 significant figures, so tick size is left unknown; min order value is 10 USDC
+func (t *HyperliquidTrader) GetInstruments() ([]Instrument, error) {
+	t.metaMutex.RLock()
+	meta := t.meta
+	t.metaMutex.RUnlock()
+	if meta == nil {
+		return nil, fmt.Errorf("meta information is not loaded")
+	}
 
-	// Format quantity using szDecimals
-	formatStr := fmt.Sprintf("%%.%df", szDecimals)
-	return fmt.Sprintf(formatStr, quantity), nil
+	var instruments []Instrument
+	for _, asset := range meta.Universe {
+		if asset.IsDelisted {
+			continue
+		}
+		instruments = append(instruments, Instrument{
+			Symbol:             asset.Name + "USDT",
+			VenueSymbol:        asset.Name,
+			LotStep:            math.Pow10(-asset.SzDecimals),
+			MinNotional:        10,
+			MaxLeverage:        asset.MaxLeverage,
+			ContractMultiplier: 1,
+		})
+	}
+	return instruments, nil
 }
 
 // getSzDecimals gets quantity precision for coin
@@ -834,18 +921,20 @@ func (t *HyperliquidTrader) getSzDecimals(coin string) int {
 	return 4 // Default precision
 }
 
-// roundToSzDecimals rounds quantity to correct precision
-func (t *HyperliquidTrader) roundToSzDecimals(coin string, quantity float64) float64 {
-	szDecimals := t.getSzDecimals(coin)
-
-	// Calculate multiplier (10^szDecimals)
-	multiplier := 1.0
-	for i := 0; i < szDecimals; i++ {
-		multiplier *= 10.0
+// instrument gets a coin's rules from the instrument registry (lot step = 10^-szDecimals)
+// Unknown coins fall back to 4 decimals
+func (t *HyperliquidTrader) instrument(coin string) *Instrument {
+	inst, err := venueInstrument(Venue{Exchange: "hyperliquid", Testnet: t.isTestnet}, t, CanonicalSymbol("hyperliquid", coin))
+	if err != nil {
+		logger.Infof("⚠️  %v, using default precision 4", err)
+		return &Instrument{Symbol: coin, LotStep: 0.0001, ContractMultiplier: 1}
 	}
+	return inst
+}
 
-	// Round
-	return float64(int(quantity*multiplier+0.5)) / multiplier
+// roundToSzDecimals rounds quantity down to the coin's lot step
+func (t *HyperliquidTrader) roundToSzDecimals(coin string, quantity float64) float64 {
+	return t.instrument(coin).RoundQuantity(quantity)
 }
 
 // roundPriceToSigfigs rounds price to 5 significant figures
@@ -896,6 +985,127 @@ func convertSymbolToHyperliquid(symbol string) string {
 	return symbol
 }
 
+// placeOrder submits an order tagged with cloid, resolving ambiguous failures by cloid lookup
+func (t *HyperliquidTrader) placeOrder(order hyperliquid.CreateOrderRequest, clientOrderID string) error {
+	cloid := hyperliquidCloid(clientOrderID)
+	if cloid != "" {
+		order.ClientOrderID = &cloid
+	}
+
+	submit := func() (map[string]interface{}, error) {
+		status, err := t.exchange.Order(t.ctx, order, nil)
+		if err != nil {
+			return nil, err
+		}
+		if status.Error != nil {
+			return nil, fmt.Errorf("order rejected: %s", *status.Error)
+		}
+		return map[string]interface{}{}, nil
+	}
+	lookup := func() (map[string]interface{}, error) {
+		return t.GetOrderByClientID(order.Coin, cloid)
+	}
+
+	_, err := submitWithClientOrderID(cloid, submit, lookup)
+	return err
+}
+
+// hyperliquidCloid converts a client order ID to Hyperliquid cloid (0x + 32 hex characters)
+func hyperliquidCloid(clientOrderID string) string {
+	if clientOrderID == "" {
+		return ""
+	}
+	id := strings.TrimPrefix(strings.ToLower(clientOrderID), "0x")
+	if len(id) > 32 {
+		id = id[:32]
+	}
+	return "0x" + id
+}
+
+// GetOrderByClientID gets order status by client order ID (cloid)
+// Returns an error when the order is unknown to the exchange
+func (t *HyperliquidTrader) GetOrderByClientID(symbol string, clientOrderID string) (map[string]interface{}, error) {
+	cloid := hyperliquidCloid(clientOrderID)
+	result, err := t.exchange.Info().QueryOrderByCloid(t.ctx, t.accountAddr(), cloid)
+	if err != nil {
+		return nil, err
+	}
+	if result.Status != hyperliquid.OrderQueryStatusSuccess {
+		return nil, fmt.Errorf("order not found: %s", cloid)
+	}
+
+	// IOC orders end "canceled" when the remainder is cancelled after a partial fill: report the filled part
+	order := result.Order.Order
+	origSz, _ := strconv.ParseFloat(order.OrigSz, 64)
+	remaining, _ := strconv.ParseFloat(order.Sz, 64)
+	filled := origSz - remaining
+	if result.Order.Status == hyperliquid.OrderStatusValueFilled {
+		filled = origSz
+	}
+	if filled < 0 {
+		filled = 0
+	}
+
+	var status string
+	switch result.Order.Status {
+	case hyperliquid.OrderStatusValueOpen, hyperliquid.OrderStatusValueTriggered:
+		if filled > 0 {
+			status = "PARTIALLY_FILLED"
+		} else {
+			status = "NEW"
+		}
+	case hyperliquid.OrderStatusValueRejected:
+		status = "REJECTED"
+	default:
+		if filled > 0 {
+			status = "FILLED"
+		} else {
+			status = "CANCELED"
+		}
+	}
+
+	avgPrice, commission := 0.0, 0.0
+	if filled > 0 {
+		avgPrice, commission = t.orderFillPrice(order.Oid, order.Timestamp)
+	}
+
+	return map[string]interface{}{
+		"orderId":       order.Oid,
+		"clientOrderId": cloid,
+		"symbol":        symbol,
+		"status":        status,
+		"avgPrice":      avgPrice,
+		"executedQty":   filled,
+		"commission":    commission,
+	}, nil
+}
+
+// orderFillPrice volume-weighted fill price and total fee of an order, from the account fills since the order was placed
+// Returns zeros when the fills can't be fetched
+func (t *HyperliquidTrader) orderFillPrice(oid int64, placedAt int64) (float64, float64) {
+	fills, err := t.exchange.Info().UserFillsByTime(t.ctx, t.accountAddr(), placedAt, nil)
+	if err != nil {
+		logger.Infof("⚠️ [Hyperliquid] Failed to get fills of order %d: %v", oid, err)
+		return 0, 0
+	}
+	var notional, quantity, fee float64
+	for _, fill := range fills {
+		if fill.Oid != oid {
+			continue
+		}
+		price, _ := strconv.ParseFloat(fill.Price, 64)
+		size, _ := strconv.ParseFloat(fill.Size, 64)
+		f, _ := strconv.ParseFloat(fill.Fee, 64)
+		notional += price * size
+		quantity += size
+		fee += f
+	}
+	if quantity == 0 {
+		return 0, fee
+	}
+	return notional / quantity, fee
+}
+
 // GetOrderStatus gets order status
 // Hyperliquid uses IOC orders, usually filled or cancelled immediately
 // For completed orders, need to query historical records
@@ -906,7 +1116,7 @@ func (t *HyperliquidTrader) GetOrderStatus(symbol string, orderID string) (map[s
 	coin := convertSymbolToHyperliquid(symbol)
 
 	// First check if in open orders
-	openOrders, err := t.exchange.Info().OpenOrders(t.ctx, t.walletAddr)
+	openOrders, err := t.exchange.Info().OpenOrders(t.ctx, t.accountAddr())
 	if err != nil {
 		// If query fails, assume order is completed
 		return map[string]interface{}{
@@ -951,6 +1161,69 @@ func absFloat(x float64) float64 {
 	return x
 }
 
+// GetFundingHistory retrieves funding payments via the userFunding info request
+// The SDK's UserFundingHistory type doesn't match the response, so the request is made directly
+func (t *HyperliquidTrader) GetFundingHistory(startTime time.Time) ([]FundingRecord, error) {
+	apiURL := hyperliquid.MainnetAPIURL
+	if t.isTestnet {
+		apiURL = hyperliquid.TestnetAPIURL
+	}
+
+	var records []FundingRecord
+	for {
+		reqBody, _ := json.Marshal(map[string]interface{}{
+			"type":      "userFunding",
+			"user":      t.accountAddr(),
+			"startTime": startTime.UnixMilli(),
+		})
+		resp, err := http.Post(apiURL+"/info", "application/json", bytes.NewReader(reqBody))
+		if err != nil {
+			return nil, fmt.Errorf("failed to get funding history: %w", err)
+		}
+		body, err := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		if err != nil {
+			return nil, fmt.Errorf("failed to read funding history: %w", err)
+		}
+		if resp.StatusCode != http.StatusOK {
+			return nil, fmt.Errorf("failed to get funding history: status %d, body: %s", resp.StatusCode, string(body))
+		}
+
+		var items []struct {
+			Time  int64  `json:"time"`
+			Hash  string `json:"hash"`
+			Delta struct {
+				Coin        string `json:"coin"`
+				Usdc        string `json:"usdc"`
+				FundingRate string `json:"fundingRate"`
+			} `json:"delta"`
+		}
+		if err := json.Unmarshal(body, &items); err != nil {
+			return nil, fmt.Errorf("failed to parse funding history: %w", err)
+		}
+
+		for _, item := range items {
+			amount, _ := strconv.ParseFloat(item.Delta.Usdc, 64)
+			rate, _ := strconv.ParseFloat(item.Delta.FundingRate, 64)
+			records = append(records, FundingRecord{
+				Symbol: item.Delta.Coin + "USDT",
+				Amount: amount,
+				Rate:   rate,
+				Time:   time.UnixMilli(item.Time),
+				TxID:   item.Hash,
+			})
+		}
+
+		// Responses are capped at 500 entries, continue after the last one
+		if len(items) < 500 {
+			break
+		}
+		startTime = time.UnixMilli(items[len(items)-1].Time + 1)
+	}
+
+	return records, nil
+}
+
 // GetClosedPnL gets recent closing trades from Hyperliquid
 // Note: Hyperliquid does NOT have a position history API, only fill history.
 // This returns individual closing trades for real-time position closure detection.
@@ -1008,7 +1281,7 @@ func (t *HyperliquidTrader) GetClosedPnL(startTime time.Time, limit int) ([]Clos
 func (t *HyperliquidTrader) GetTrades(startTime time.Time, limit int) ([]TradeRecord, error) {
 	// Use UserFillsByTime API
 	startTimeMs := startTime.UnixMilli()
-	fills, err := t.exchange.Info().UserFillsByTime(t.ctx, t.walletAddr, startTimeMs, nil)
+	fills, err := t.exchange.Info().UserFillsByTime(t.ctx, t.accountAddr(), startTimeMs, nil)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get user fills: %w", err)
 	}
@@ -1031,7 +1304,7 @@ func (t *HyperliquidTrader) GetTrades(startTime time.Time, limit int) ([]TradeRe
 		// Hyperliquid uses one-way mode, so PositionSide is "BOTH"
 		trade := TradeRecord{
 			TradeID:      strconv.FormatInt(fill.Tid, 10),
-			Symbol:       fill.Coin,
+			Symbol:       CanonicalSymbol("hyperliquid", fill.Coin),
 			Side:         side,
 			PositionSide: "BOTH", // Hyperliquid doesn't have hedge mode
 			Price:        price,
@@ -1043,5 +1316,5 @@ func (t *HyperliquidTrader) GetTrades(startTime time.Time, limit int) ([]TradeRe
 		trades = append(trades, trade)
 	}
 
-	return trades, nil
+	return sortAndLimitTrades(trades, limit), nil
 }
@@ -11,6 +11,7 @@ import (
 	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/sonirico/go-hyperliquid"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 // ============================================================
@@ -271,6 +272,7 @@ func TestNewHyperliquidTrader(t *testing.T) {
 		name          string
 		privateKeyHex string
 		walletAddr    string
+		vaultAddr     string
 		testnet       bool
 		wantError     bool
 		errorContains string
@@ -291,11 +293,20 @@ func TestNewHyperliquidTrader(t *testing.T) {
 			wantError:     true,
 			errorContains: "Configuration error",
 		},
+		{
+			name:          "Invalid vault address",
+			privateKeyHex: "0123456789abcdef0123456789abcdef0123456789abcdef0123456789abcdef",
+			walletAddr:    "0x1234567890123456789012345678901234567890",
+			vaultAddr:     "not-an-address",
+			testnet:       true,
+			wantError:     true,
+			errorContains: "invalid sub-account/vault address",
+		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			trader, err := NewHyperliquidTrader(tt.privateKeyHex, tt.walletAddr, tt.testnet)
+			trader, err := NewHyperliquidTrader(tt.privateKeyHex, tt.walletAddr, tt.vaultAddr, tt.testnet)
 
 			if tt.wantError {
 				assert.Error(t, err)
@@ -455,6 +466,10 @@ func TestHyperliquidTrader_RoundToSzDecimals(t *testing.T) {
 			},
 		},
 	}
+	// Rounding reads lot steps from the shared registry, replace what other tests loaded
+	instruments, err := trader.GetInstruments()
+	require.NoError(t, err)
+	Instruments.Set(Venue{Exchange: "hyperliquid"}, instruments)
 
 	tests := []struct {
 		name     string
@@ -463,13 +478,13 @@ func TestHyperliquidTrader_RoundToSzDecimals(t *testing.T) {
 		expected float64
 	}{
 		{
-			name:     "BTC - round to 4 decimals",
+			name:     "BTC - round down to 4 decimals",
 			coin:     "BTC",
 			quantity: 1.23456789,
-			expected: 1.2346,
+			expected: 1.2345,
 		},
 		{
-			name:     "ETH - round to 3 decimals",
+			name:     "ETH - round down to 3 decimals",
 			coin:     "ETH",
 			quantity: 10.12345,
 			expected: 10.123,
@@ -478,14 +493,14 @@ func TestHyperliquidTrader_RoundToSzDecimals(t *testing.T) {
 			name:     "Unknown coin - use default 4 decimals",
 			coin:     "UNKNOWN",
 			quantity: 1.23456789,
-			expected: 1.2346,
+			expected: 1.2345,
 		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			result := trader.roundToSzDecimals(tt.coin, tt.quantity)
-			assert.InDelta(t, tt.expected, result, 0.0001)
+			assert.InDelta(t, tt.expected, result, 1e-9)
 		})
 	}
 }
@@ -610,6 +625,18 @@ func TestHyperliquidTrader_SetMarginMode(t *testing.T) {
 	}
 }
 
+// TestHyperliquidTrader_AccountAddr Test queries target the sub-account/vault when configured
+func TestHyperliquidTrader_AccountAddr(t *testing.T) {
+	main := &HyperliquidTrader{walletAddr: "0x1234567890123456789012345678901234567890"}
+	assert.Equal(t, "0x1234567890123456789012345678901234567890", main.accountAddr())
+
+	sub := &HyperliquidTrader{
+		walletAddr: "0x1234567890123456789012345678901234567890",
+		vaultAddr:  "0xabcdefabcdefabcdefabcdefabcdefabcdefabcd",
+	}
+	assert.Equal(t, "0xabcdefabcdefabcdefabcdefabcdefabcdefabcd", sub.accountAddr())
+}
+
 // TestNewHyperliquidTrader_PrivateKeyProcessing Test private key processing
 func TestNewHyperliquidTrader_PrivateKeyProcessing(t *testing.T) {
 	tests := []struct {
@@ -644,3 +671,72 @@ func TestNewHyperliquidTrader_PrivateKeyProcessing(t *testing.T) {
 		})
 	}
 }
+
+// TestHyperliquidTrader_GetOrderByClientID Test a partially filled IOC order reports its filled size instead of CANCELED
+func TestHyperliquidTrader_GetOrderByClientID(t *testing.T) {
+	orderStatus := "canceled"
+	remaining := "0.004"
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var reqBody map[string]interface{}
+		json.NewDecoder(r.Body).Decode(&reqBody)
+
+		var respBody interface{}
+		switch reqBody["type"] {
+		case "meta":
+			respBody = map[string]interface{}{
+				"universe":     []map[string]interface{}{{"name": "BTC", "szDecimals": 4, "maxLeverage": 50}},
+				"marginTables": []interface{}{},
+			}
+		case "spotMeta":
+			respBody = map[string]interface{}{"universe": []interface{}{}, "tokens": []interface{}{}}
+		case "orderStatus":
+			respBody = map[string]interface{}{
+				"status": "order",
+				"order": map[string]interface{}{
+					"status": orderStatus,
+					"order": map[string]interface{}{
+						"coin": "BTC", "side": "B", "limitPx": "51000", "oid": 77, "timestamp": 1700000000000,
+						"origSz": "0.01", "sz": remaining,
+					},
+				},
+			}
+		case "userFillsByTime":
+			respBody = []map[string]interface{}{
+				{"coin": "BTC", "oid": 77, "px": "50000", "sz": "0.004", "side": "B", "fee": "0.8", "time": 1700000000001},
+				{"coin": "BTC", "oid": 77, "px": "50100", "sz": "0.002", "side": "B", "fee": "0.4", "time": 1700000000002},
+				{"coin": "BTC", "oid": 78, "px": "60000", "sz": "1", "side": "B", "fee": "9", "time": 1700000000003},
+			}
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(respBody)
+	}))
+	defer mockServer.Close()
+
+	privateKey, err := crypto.HexToECDSA("0123456789abcdef0123456789abcdef0123456789abcdef0123456789abcdef")
+	require.NoError(t, err)
+	walletAddr := "0x9999999999999999999999999999999999999999"
+	trader := &HyperliquidTrader{
+		exchange:   hyperliquid.NewExchange(context.Background(), privateKey, mockServer.URL, nil, "", walletAddr, nil),
+		ctx:        context.Background(),
+		walletAddr: walletAddr,
+	}
+
+	order, err := trader.GetOrderByClientID("BTCUSDT", "abc")
+	require.NoError(t, err)
+	assert.Equal(t, "FILLED", order["status"])
+	assert.InDelta(t, 0.006, order["executedQty"], 1e-9)
+	assert.InDelta(t, (50000*0.004+50100*0.002)/0.006, order["avgPrice"], 1e-6)
+	assert.InDelta(t, 1.2, order["commission"], 1e-9)
+
+	orderStatus, remaining = "open", "0.004"
+	order, err = trader.GetOrderByClientID("BTCUSDT", "abc")
+	require.NoError(t, err)
+	assert.Equal(t, "PARTIALLY_FILLED", order["status"])
+
+	// Nothing filled: the order failed
+	orderStatus, remaining = "canceled", "0.01"
+	order, err = trader.GetOrderByClientID("BTCUSDT", "abc")
+	require.NoError(t, err)
+	assert.Equal(t, "CANCELED", order["status"])
+	assert.Equal(t, 0.0, order["executedQty"])
+}
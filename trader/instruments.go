@@ -0,0 +1,350 @@
+package trader
+
+import (
+	"fmt"
+	"math"
+	"nofx/decision"
+	"nofx/logger"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Instrument trading rules of a perpetual contract on one venue
+// Sizes are in base asset units (e.g. BTC), whatever the venue's contract size
+type Instrument struct {
+	Symbol             string  `json:"symbol"`       // Canonical symbol (e.g. BTCUSDT)
+	VenueSymbol        string  `json:"venue_symbol"` // Venue symbol (e.g. BTC-USDT-SWAP on OKX, BTC on Hyperliquid)
+	Exchange           string  `json:"exchange"`
+	TickSize           float64 `json:"tick_size"`           // Price increment (0 = unknown)
+	LotStep            float64 `json:"lot_step"`            // Quantity increment (0 = unknown)
+	MinQty             float64 `json:"min_qty"`             // Minimum order quantity (0 = unknown)
+	MinNotional        float64 `json:"min_notional"`        // Minimum order value in USDT (0 = unknown)
+	MaxLeverage        int     `json:"max_leverage"`        // Maximum leverage (0 = unknown)
+	ContractMultiplier float64 `json:"contract_multiplier"` // Base units per contract (1 for coin-sized venues)
+}
+
+// Venue an exchange environment; testnets list different instruments than their mainnet
+type Venue struct {
+	Exchange string // Exchange type (binance, okx, ...)
+	Testnet  bool
+}
+
+// String returns the venue name for logs and errors, e.g. "binance" or "binance testnet"
+func (v Venue) String() string {
+	if v.Testnet {
+		return v.Exchange + " testnet"
+	}
+	return v.Exchange
+}
+
+// InstrumentProvider is implemented by exchanges that can list their perpetual instruments
+type InstrumentProvider interface {
+	// GetInstruments Get trading rules of all listed USDT perpetuals
+	GetInstruments() ([]Instrument, error)
+}
+
+// instrumentsTTL how long loaded instruments are considered fresh
+const instrumentsTTL = time.Hour
+
+// instrumentsRetryInterval how long to wait before retrying a failed refresh, while serving the last loaded instruments
+const instrumentsRetryInterval = time.Minute
+
+// InstrumentRegistry instrument metadata of every venue, keyed by canonical symbol
+type InstrumentRegistry struct {
+	mu     sync.RWMutex
+	venues map[Venue]*venueInstruments
+}
+
+type venueInstruments struct {
+	loadedAt    time.Time
+	refreshedAt time.Time // Last refresh attempt, failed ones included
+	bySymbol    map[string]*Instrument
+}
+
+// Instruments shared instrument registry (loaded by traders, used by traders, candidate selection and backtests)
+var Instruments = NewInstrumentRegistry()
+
+// NewInstrumentRegistry creates an empty instrument registry
+func NewInstrumentRegistry() *InstrumentRegistry {
+	return &InstrumentRegistry{venues: make(map[Venue]*venueInstruments)}
+}
+
+// Load loads the instruments of a venue from the exchange, unless they are still fresh
+// When a refresh fails, the last loaded instruments keep being served and the refresh is retried later;
+// an error is only returned when the venue's instruments have never been loaded
+func (r *InstrumentRegistry) Load(venue Venue, provider InstrumentProvider) error {
+	r.mu.RLock()
+	loaded, ok := r.venues[venue]
+	fresh := ok && (time.Since(loaded.loadedAt) < instrumentsTTL || time.Since(loaded.refreshedAt) < instrumentsRetryInterval)
+	r.mu.RUnlock()
+	if fresh {
+		return nil
+	}
+
+	instruments, err := provider.GetInstruments()
+	if err != nil {
+		if !ok {
+			return fmt.Errorf("failed to load %s instruments: %w", venue, err)
+		}
+		r.mu.Lock()
+		loaded.refreshedAt = time.Now()
+		r.mu.Unlock()
+		logger.Warnf("⚠️ Failed to refresh %s instruments, keeping the ones loaded at %s (retry in %v): %v",
+			venue, loaded.loadedAt.Format("15:04:05"), instrumentsRetryInterval, err)
+		return nil
+	}
+	r.Set(venue, instruments)
+	logger.Infof("📐 Loaded %d %s instruments", len(instruments), venue)
+	return nil
+}
+
+// Set replaces the instruments of a venue
+func (r *InstrumentRegistry) Set(venue Venue, instruments []Instrument) {
+	now := time.Now()
+	loaded := &venueInstruments{loadedAt: now, refreshedAt: now, bySymbol: make(map[string]*Instrument, len(instruments))}
+	for i := range instruments {
+		inst := instruments[i]
+		inst.Exchange = venue.Exchange
+		inst.Symbol = strings.ToUpper(inst.Symbol)
+		if inst.VenueSymbol == "" {
+			inst.VenueSymbol = VenueSymbol(venue.Exchange, inst.Symbol)
+		}
+		if inst.ContractMultiplier <= 0 {
+			inst.ContractMultiplier = 1
+		}
+		loaded.bySymbol[inst.Symbol] = &inst
+	}
+
+	r.mu.Lock()
+	r.venues[venue] = loaded
+	r.mu.Unlock()
+}
+
+// Loaded reports whether the instruments of a venue are known
+func (r *InstrumentRegistry) Loaded(venue Venue) bool {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	_, ok := r.venues[venue]
+	return ok
+}
+
+// Get gets the instrument of a canonical symbol on a venue
+func (r *InstrumentRegistry) Get(venue Venue, symbol string) (*Instrument, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	loaded, ok := r.venues[venue]
+	if !ok {
+		return nil, false
+	}
+	inst, ok := loaded.bySymbol[strings.ToUpper(symbol)]
+	return inst, ok
+}
+
+// IsListed reports whether a symbol trades on the venue
+// Symbols are assumed listed when the venue's instruments are unknown
+func (r *InstrumentRegistry) IsListed(venue Venue, symbol string) bool {
+	if !r.Loaded(venue) {
+		return true
+	}
+	_, ok := r.Get(venue, symbol)
+	return ok
+}
+
+// CheckListed returns an error when the symbol is not listed on the venue
+func (r *InstrumentRegistry) CheckListed(venue Venue, symbol string) error {
+	if !r.IsListed(venue, symbol) {
+		return fmt.Errorf("%s is not listed on %s", symbol, venue)
+	}
+	return nil
+}
+
+// RoundQuantity rounds a quantity down to the lot step
+func (i *Instrument) RoundQuantity(quantity float64) float64 {
+	if i.LotStep <= 0 {
+		return quantity
+	}
+	// Small epsilon keeps 0.3/0.1 from flooring to 2 steps
+	return math.Floor(quantity/i.LotStep+1e-9) * i.LotStep
+}
+
+// RoundPrice rounds a price to the nearest tick
+func (i *Instrument) RoundPrice(price float64) float64 {
+	if i.TickSize <= 0 {
+		return price
+	}
+	return math.Round(price/i.TickSize) * i.TickSize
+}
+
+// FormatQuantity formats a quantity rounded down to the lot step, with the step's decimals
+func (i *Instrument) FormatQuantity(quantity float64) string {
+	rounded := i.RoundQuantity(quantity)
+	if i.LotStep <= 0 {
+		return strconv.FormatFloat(rounded, 'f', -1, 64)
+	}
+	return strconv.FormatFloat(rounded, 'f', stepDecimals(i.LotStep), 64)
+}
+
+// FormatPrice formats a price rounded to the tick size, with the tick's decimals
+func (i *Instrument) FormatPrice(price float64) string {
+	if i.TickSize <= 0 {
+		return strconv.FormatFloat(price, 'f', -1, 64)
+	}
+	return strconv.FormatFloat(i.RoundPrice(price), 'f', stepDecimals(i.TickSize), 64)
+}
+
+// FormatContracts formats a quantity as a contract count rounded down to the lot step, for venues that size orders in contracts
+func (i *Instrument) FormatContracts(quantity float64) string {
+	contracts := i.RoundQuantity(quantity) / i.ContractMultiplier
+	if i.LotStep <= 0 {
+		return strconv.FormatFloat(contracts, 'f', -1, 64)
+	}
+	return strconv.FormatFloat(contracts, 'f', stepDecimals(i.LotStep/i.ContractMultiplier), 64)
+}
+
+// CheckOrder validates the order size against minimum quantity and notional
+func (i *Instrument) CheckOrder(quantity, price float64) error {
+	if quantity <= 0 {
+		return fmt.Errorf("%s quantity %.8f is below the lot step %v", i.Symbol, quantity, i.LotStep)
+	}
+	if i.MinQty > 0 && quantity < i.MinQty {
+		return fmt.Errorf("%s quantity %.8f is below the minimum %v on %s", i.Symbol, quantity, i.MinQty, i.Exchange)
+	}
+	if i.MinNotional > 0 && price > 0 && quantity*price < i.MinNotional {
+		return fmt.Errorf("%s order value %.2f USDT is below the minimum %.2f USDT on %s", i.Symbol, quantity*price, i.MinNotional, i.Exchange)
+	}
+	return nil
+}
+
+// CapLeverage caps leverage to the instrument's maximum
+func (i *Instrument) CapLeverage(leverage int) int {
+	if i.MaxLeverage > 0 && leverage > i.MaxLeverage {
+		return i.MaxLeverage
+	}
+	return leverage
+}
+
+// VenueSymbol maps a canonical symbol (BTCUSDT) to the venue's symbol
+func VenueSymbol(exchange, symbol string) string {
+	symbol = strings.ToUpper(symbol)
+	base := strings.TrimSuffix(symbol, "USDT")
+	switch exchange {
+	case "okx":
+		return base + "-USDT-SWAP"
+	case "gate":
+		return strings.TrimSuffix(base, "_") + "_USDT"
+	case "dydx":
+		return strings.TrimSuffix(base, "-USD") + "-USD"
+	case "hyperliquid", "lighter":
+		return base
+	default:
+		return symbol
+	}
+}
+
+// CanonicalSymbol maps a venue symbol back to the canonical symbol (BTCUSDT)
+func CanonicalSymbol(exchange, venueSymbol string) string {
+	venueSymbol = strings.ToUpper(venueSymbol)
+	switch exchange {
+	case "okx":
+		parts := strings.Split(venueSymbol, "-")
+		if len(parts) >= 2 {
+			return parts[0] + parts[1]
+		}
+	case "gate":
+		return strings.ReplaceAll(venueSymbol, "_", "")
+	case "dydx":
+		return strings.TrimSuffix(venueSymbol, "-USD") + "USDT"
+	case "hyperliquid", "lighter":
+		if !strings.HasSuffix(venueSymbol, "USDT") {
+			return venueSymbol + "USDT"
+		}
+	}
+	return venueSymbol
+}
+
+// stepDecimals number of decimals of a step size (0.001 → 3)
+func stepDecimals(step float64) int {
+	// Rounding drops float noise of derived steps (0.01 lot × 0.01 contract value = 0.00010000000000000002)
+	s := strconv.FormatFloat(math.Round(step*1e10)/1e10, 'f', -1, 64)
+	if idx := strings.Index(s, "."); idx >= 0 {
+		return len(s) - idx - 1
+	}
+	return 0
+}
+
+// venueInstrument gets a symbol's instrument for order sizing, loading the venue's instruments when needed
+// Adapters round order quantities with it, so lot steps have a single source: the shared registry
+func venueInstrument(venue Venue, provider InstrumentProvider, symbol string) (*Instrument, error) {
+	if err := Instruments.Load(venue, provider); err != nil {
+		return nil, err
+	}
+	inst, ok := Instruments.Get(venue, symbol)
+	if !ok {
+		return nil, fmt.Errorf("%s is not listed on %s", symbol, venue)
+	}
+	return inst, nil
+}
+
+// parseFloatOrZero parses a numeric string, returning 0 when empty or invalid
+func parseFloatOrZero(s string) float64 {
+	f, _ := strconv.ParseFloat(s, 64)
+	return f
+}
+
+// venue the exchange environment the trader trades on
+func (at *AutoTrader) venue() Venue {
+	return Venue{Exchange: at.exchange, Testnet: at.config.isTestnet()}
+}
+
+// refreshInstruments loads the trader venue's instruments when the exchange can list them
+func (at *AutoTrader) refreshInstruments() {
+	provider, ok := at.trader.(InstrumentProvider)
+	if !ok {
+		return
+	}
+	if err := Instruments.Load(at.venue(), provider); err != nil {
+		logger.Infof("⚠️ [%s] %v", at.name, err)
+	}
+}
+
+// filterListedCoins drops candidate coins that are not listed on the trader's venue
+func (at *AutoTrader) filterListedCoins(coins []decision.CandidateCoin) []decision.CandidateCoin {
+	listed := coins[:0]
+	for _, coin := range coins {
+		if !Instruments.IsListed(at.venue(), coin.Symbol) {
+			logger.Infof("📐 [%s] %s is not listed on %s, skipped", at.name, coin.Symbol, at.venue())
+			continue
+		}
+		listed = append(listed, coin)
+	}
+	return listed
+}
+
+// checkInstrument refuses symbols not listed on the venue and caps the decision's leverage to the venue maximum
+func (at *AutoTrader) checkInstrument(d *decision.Decision) error {
+	if err := Instruments.CheckListed(at.venue(), d.Symbol); err != nil {
+		return err
+	}
+	if inst, ok := Instruments.Get(at.venue(), d.Symbol); ok {
+		if capped := inst.CapLeverage(d.Leverage); capped != d.Leverage {
+			logger.Infof("  📐 %s leverage %dx capped to venue maximum %dx", d.Symbol, d.Leverage, capped)
+			d.Leverage = capped
+		}
+	}
+	return nil
+}
+
+// roundOrderQuantity rounds an order quantity down to the venue's lot step and checks minimum size
+func (at *AutoTrader) roundOrderQuantity(symbol string, quantity, price float64) (float64, error) {
+	inst, ok := Instruments.Get(at.venue(), symbol)
+	if !ok {
+		return quantity, nil
+	}
+	rounded := inst.RoundQuantity(quantity)
+	if err := inst.CheckOrder(rounded, price); err != nil {
+		return 0, err
+	}
+	return rounded, nil
+}
@@ -0,0 +1,151 @@
+package trader
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"nofx/decision"
+
+	"github.com/sonirico/go-hyperliquid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestInstrumentRegistry tests listing, lookup and venue symbol mapping
+func TestInstrumentRegistry(t *testing.T) {
+	registry := NewInstrumentRegistry()
+	okx := Venue{Exchange: "okx"}
+	assert.True(t, registry.IsListed(okx, "DOGEUSDT"), "unknown venue assumes listed")
+
+	registry.Set(okx, []Instrument{{Symbol: "btcusdt", LotStep: 0.001, ContractMultiplier: 0.01}})
+	inst, ok := registry.Get(okx, "BTCUSDT")
+	require.True(t, ok)
+	assert.Equal(t, "BTC-USDT-SWAP", inst.VenueSymbol)
+	assert.Equal(t, "okx", inst.Exchange)
+	assert.False(t, registry.IsListed(okx, "DOGEUSDT"))
+	assert.Error(t, registry.CheckListed(okx, "DOGEUSDT"))
+
+	// Testnet listings don't leak into mainnet (and the other way round)
+	okxTestnet := Venue{Exchange: "okx", Testnet: true}
+	assert.True(t, registry.IsListed(okxTestnet, "DOGEUSDT"), "testnet instruments not loaded yet")
+	registry.Set(okxTestnet, []Instrument{{Symbol: "DOGEUSDT"}})
+	assert.False(t, registry.IsListed(okx, "DOGEUSDT"))
+	assert.False(t, registry.IsListed(okxTestnet, "BTCUSDT"))
+	assert.ErrorContains(t, registry.CheckListed(okxTestnet, "BTCUSDT"), "not listed on okx testnet")
+
+	assert.Equal(t, "BTC", VenueSymbol("hyperliquid", "BTCUSDT"))
+	assert.Equal(t, "BTCUSDT", VenueSymbol("binance", "btcusdt"))
+	assert.Equal(t, "BTCUSDT", CanonicalSymbol("okx", "BTC-USDT-SWAP"))
+	assert.Equal(t, "ETHUSDT", CanonicalSymbol("hyperliquid", "ETH"))
+}
+
+// TestInstrumentRules tests lot step rounding, minimum size and leverage cap
+func TestInstrumentRules(t *testing.T) {
+	inst := &Instrument{Symbol: "ETHUSDT", Exchange: "binance", LotStep: 0.001, MinQty: 0.001, MinNotional: 20, MaxLeverage: 50}
+
+	assert.InDelta(t, 0.123, inst.RoundQuantity(0.12399), 1e-12)
+	assert.InDelta(t, 0.3, inst.RoundQuantity(0.3), 1e-12)
+	assert.Equal(t, "0.123", inst.FormatQuantity(0.12399))
+
+	assert.NoError(t, inst.CheckOrder(0.01, 3000))
+	assert.ErrorContains(t, inst.CheckOrder(0.005, 3000), "below the minimum 20.00 USDT")
+	assert.ErrorContains(t, inst.CheckOrder(0, 3000), "lot step")
+
+	assert.Equal(t, 50, inst.CapLeverage(100))
+	assert.Equal(t, 10, inst.CapLeverage(10))
+	assert.Equal(t, 1, stepDecimals(0.1))
+	assert.Equal(t, 0, stepDecimals(1))
+	assert.Equal(t, 4, stepDecimals(0.01*0.01), "float noise of derived steps is ignored")
+}
+
+// TestInstrumentFormatting tests order sizes and prices formatted for the venue API
+func TestInstrumentFormatting(t *testing.T) {
+	// OKX style: lot size 0.01 contracts of 0.01 BTC
+	okx := &Instrument{Symbol: "BTCUSDT", LotStep: 0.01 * 0.01, ContractMultiplier: 0.01, TickSize: 0.1}
+	assert.Equal(t, "12.34", okx.FormatContracts(0.123456))
+	assert.Equal(t, "0.1234", okx.FormatQuantity(0.123456))
+	assert.Equal(t, "50000.3", okx.FormatPrice(50000.26))
+
+	// Gate style: whole contracts of 0.0001 BTC
+	gate := &Instrument{Symbol: "BTCUSDT", LotStep: 0.0001, ContractMultiplier: 0.0001}
+	assert.Equal(t, "1234", gate.FormatContracts(0.123456))
+
+	// Unknown rules leave values as they are
+	unknown := &Instrument{Symbol: "BTCUSDT", ContractMultiplier: 1}
+	assert.Equal(t, "0.123456", unknown.FormatContracts(0.123456))
+	assert.Equal(t, "50000.26", unknown.FormatPrice(50000.26))
+}
+
+// TestVenueInstrument tests adapters size orders from the registry, loading the venue on first use
+func TestVenueInstrument(t *testing.T) {
+	venue := Venue{Exchange: "test_lazy_venue"}
+	provider := &HyperliquidTrader{meta: &hyperliquid.Meta{Universe: []hyperliquid.AssetInfo{{Name: "BTC", SzDecimals: 5}}}}
+
+	inst, err := venueInstrument(venue, provider, "BTCUSDT")
+	require.NoError(t, err)
+	assert.Equal(t, "0.12345", inst.FormatQuantity(0.123456))
+	_, err = venueInstrument(venue, provider, "DOGEUSDT")
+	assert.ErrorContains(t, err, "not listed on test_lazy_venue")
+}
+
+// stubInstrumentProvider lists fixed instruments, or fails with err
+type stubInstrumentProvider struct {
+	instruments []Instrument
+	err         error
+	calls       int
+}
+
+func (p *stubInstrumentProvider) GetInstruments() ([]Instrument, error) {
+	p.calls++
+	return p.instruments, p.err
+}
+
+// TestInstrumentRegistryFailedRefresh tests a failed refresh keeps serving the last loaded instruments
+func TestInstrumentRegistryFailedRefresh(t *testing.T) {
+	registry := NewInstrumentRegistry()
+	venue := Venue{Exchange: "binance"}
+
+	failing := &stubInstrumentProvider{err: errors.New("exchangeInfo unavailable")}
+	assert.Error(t, registry.Load(venue, failing), "nothing loaded yet")
+
+	provider := &stubInstrumentProvider{instruments: []Instrument{{Symbol: "BTCUSDT", LotStep: 0.001}}}
+	require.NoError(t, registry.Load(venue, provider))
+
+	// Expire the instruments, then fail the refresh
+	registry.venues[venue].loadedAt = time.Now().Add(-2 * instrumentsTTL)
+	registry.venues[venue].refreshedAt = registry.venues[venue].loadedAt
+	require.NoError(t, registry.Load(venue, failing))
+	assert.Equal(t, 2, failing.calls)
+	inst, ok := registry.Get(venue, "BTCUSDT")
+	require.True(t, ok, "earlier instrument kept")
+	assert.Equal(t, "0.123", inst.FormatQuantity(0.12345))
+
+	// Retried only after the retry interval
+	require.NoError(t, registry.Load(venue, failing))
+	assert.Equal(t, 2, failing.calls)
+	registry.venues[venue].refreshedAt = time.Now().Add(-2 * instrumentsRetryInterval)
+	require.NoError(t, registry.Load(venue, provider))
+	assert.Equal(t, 2, provider.calls)
+}
+
+// TestAutoTraderInstrumentChecks tests unlisted candidates and decisions are refused on the trader's venue
+func TestAutoTraderInstrumentChecks(t *testing.T) {
+	Instruments.Set(Venue{Exchange: "test_venue"}, []Instrument{{Symbol: "BTCUSDT", LotStep: 0.001, MinNotional: 5, MaxLeverage: 20}})
+	at := &AutoTrader{name: "test", exchange: "test_venue"}
+
+	coins := at.filterListedCoins([]decision.CandidateCoin{{Symbol: "BTCUSDT"}, {Symbol: "NEWUSDT"}})
+	require.Len(t, coins, 1)
+	assert.Equal(t, "BTCUSDT", coins[0].Symbol)
+
+	assert.ErrorContains(t, at.checkInstrument(&decision.Decision{Symbol: "NEWUSDT", Leverage: 5}), "not listed on test_venue")
+	d := &decision.Decision{Symbol: "BTCUSDT", Leverage: 50}
+	require.NoError(t, at.checkInstrument(d))
+	assert.Equal(t, 20, d.Leverage)
+
+	qty, err := at.roundOrderQuantity("BTCUSDT", 0.0129, 50000)
+	require.NoError(t, err)
+	assert.InDelta(t, 0.012, qty, 1e-12)
+	_, err = at.roundOrderQuantity("BTCUSDT", 0.00005, 50000)
+	assert.Error(t, err)
+}
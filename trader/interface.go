@@ -1,6 +1,9 @@
 package trader
 
-import "time"
+import (
+	"strings"
+	"time"
+)
 
 // ClosedPnLRecord represents a single closed position record from exchange
 type ClosedPnLRecord struct {
@@ -33,6 +36,29 @@ type TradeRecord struct {
 	Time         time.Time // Trade execution time
 }
 
+// FundingRecord represents a single funding payment from exchange
+type FundingRecord struct {
+	Symbol string    // Trading pair (e.g., "BTCUSDT")
+	Amount float64   // Funding amount in quote asset (positive = received, negative = paid)
+	Rate   float64   // Funding rate (0 if not provided by exchange)
+	Time   time.Time // Funding settlement time
+	TxID   string    // Exchange-specific transaction/bill ID
+}
+
+// FundingHistoryProvider is implemented by exchanges that expose funding payment history
+// Position sync uses it to attribute funding to positions, so net PnL includes funding
+type FundingHistoryProvider interface {
+	// GetFundingHistory Get funding payments settled since startTime (all symbols, oldest first)
+	GetFundingHistory(startTime time.Time) ([]FundingRecord, error)
+}
+
+// TradeHistoryProvider is implemented by exchanges that expose account fill history
+// Position sync rebuilds closed positions from fills with RebuildPositionsFromTrades, for every venue alike
+type TradeHistoryProvider interface {
+	// GetTrades Get up to limit fills executed since startTime (all symbols, oldest first)
+	GetTrades(startTime time.Time, limit int) ([]TradeRecord, error)
+}
+
 // Trader Unified trader interface
 // Supports multiple trading platforms (Binance, Hyperliquid, etc.)
 type Trader interface {
@@ -94,3 +120,34 @@ type Trader interface {
 	// Returns accurate exit price, fees, and close reason for positions closed externally
 	GetClosedPnL(startTime time.Time, limit int) ([]ClosedPnLRecord, error)
 }
+
+// StopOrder an open stop-loss order on the exchange
+type StopOrder struct {
+	OrderID      string  // Exchange order ID
+	Symbol       string  // Trading pair (e.g., "BTCUSDT")
+	PositionSide string  // "LONG" or "SHORT" (the position the stop closes)
+	StopPrice    float64 // Trigger price
+}
+
+// StopOrderProvider is implemented by exchanges that can list and cancel individual stop-loss orders
+// Blackout stop tightening reads the live stops from it, so stops placed before a restart or by hand are never loosened
+type StopOrderProvider interface {
+	// GetStopLossOrders Get open stop-loss orders of a symbol
+	GetStopLossOrders(symbol string) ([]StopOrder, error)
+
+	// CancelOrder Cancel one open order by exchange order ID
+	CancelOrder(symbol, orderID string) error
+}
+
+// stopPositionSide the position a stop order closes: its position side in hedge mode,
+// otherwise the opposite of its order side (a SELL stop closes a long)
+func stopPositionSide(positionSide, orderSide string) string {
+	switch strings.ToUpper(positionSide) {
+	case "LONG", "SHORT":
+		return strings.ToUpper(positionSide)
+	}
+	if strings.ToUpper(orderSide) == "SELL" {
+		return "LONG"
+	}
+	return "SHORT"
+}
@@ -0,0 +1,64 @@
+package trader
+
+import (
+	"nofx/decision"
+	"nofx/logger"
+	"strings"
+	"time"
+)
+
+const (
+	journalBackfillWindow   = 7 * 24 * time.Hour // Older closed positions are not journaled
+	journalBatchSize        = 20                 // Positions journaled per cycle
+	journalReasoningWindow  = 30 * time.Minute   // How far from an open/close to look for its decision
+	journalPromptCandidates = 50                 // Latest entries the prompt selection ranks
+)
+
+// reflectClosedTrades writes a journal entry for every recently closed position that has none yet
+// Entry and exit reasoning come from the decision records of the cycles that opened and closed the position
+func (at *AutoTrader) reflectClosedTrades() {
+	positions, err := at.store.Journal().GetUnjournaledPositions(at.id, time.Now().Add(-journalBackfillWindow), journalBatchSize)
+	if err != nil {
+		logger.Infof("⚠️ [%s] Failed to get closed positions for the trade journal: %v", at.name, err)
+		return
+	}
+
+	for _, pos := range positions {
+		side := strings.ToLower(pos.Side)
+		entryReasoning, regime, err := at.store.Decision().FindActionReasoning(at.id, pos.Symbol, "open_"+side, pos.EntryTime, journalReasoningWindow)
+		if err != nil {
+			logger.Infof("⚠️ [%s] Failed to find entry reasoning of %s %s: %v", at.name, pos.Symbol, side, err)
+		}
+		var exitReasoning string
+		if pos.ExitTime != nil {
+			exitReasoning, _, _ = at.store.Decision().FindActionReasoning(at.id, pos.Symbol, "close_"+side, *pos.ExitTime, journalReasoningWindow)
+		}
+
+		entry := decision.ReflectTrade(pos, entryReasoning, exitReasoning, regime)
+		if err := at.store.Journal().Create(entry); err != nil {
+			logger.Infof("⚠️ [%s] Failed to save journal entry: %v", at.name, err)
+			continue
+		}
+		logger.Infof("📓 [%s] Trade journaled: %s", at.name, entry.Summary)
+	}
+}
+
+// loadJournal loads the latest journal entries the prompt selection ranks
+func (at *AutoTrader) loadJournal() []decision.JournalEntry {
+	entries, err := at.store.Journal().List(at.id, "", journalPromptCandidates)
+	if err != nil {
+		logger.Infof("⚠️ [%s] Failed to load trade journal: %v", at.name, err)
+		return nil
+	}
+	journal := make([]decision.JournalEntry, 0, len(entries))
+	for _, e := range entries {
+		journal = append(journal, decision.JournalEntry{
+			Symbol:   e.Symbol,
+			Side:     e.Side,
+			Regime:   e.Regime,
+			ExitTime: e.ExitTime,
+			Summary:  e.Summary,
+		})
+	}
+	return journal
+}
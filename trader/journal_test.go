@@ -0,0 +1,114 @@
+package trader
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"nofx/store"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestReflectClosedTrades tests journal entries are built from the opening and closing decisions
+func TestReflectClosedTrades(t *testing.T) {
+	st, err := store.New(filepath.Join(t.TempDir(), "test.db"))
+	require.NoError(t, err)
+	defer st.Close()
+
+	at := &AutoTrader{id: "trader-journal-test", name: "journal", store: st}
+	entry := time.Now().Add(-3 * time.Hour).Truncate(time.Second)
+
+	// Decision records are saved after the cycle's orders
+	require.NoError(t, st.Decision().LogDecision(&store.DecisionRecord{
+		TraderID:     at.id,
+		Timestamp:    entry.Add(20 * time.Second),
+		DecisionJSON: `[{"symbol":"SOLUSDT","action":"open_long","reasoning":"Breakout above the 4h range with rising volume"}]`,
+		MarketRegime: store.RegimeRanging,
+		Regimes:      map[string]string{"SOLUSDT": store.RegimeTrendingUp},
+	}))
+	pos := &store.TraderPosition{
+		TraderID:   at.id,
+		Symbol:     "SOLUSDT",
+		Side:       "LONG",
+		Quantity:   10,
+		EntryPrice: 100,
+		EntryTime:  entry,
+		Leverage:   5,
+	}
+	require.NoError(t, st.Position().Create(pos))
+	require.NoError(t, st.Position().ClosePosition(pos.ID, 98, "exit-1", -20, 1, "stop_loss"))
+
+	at.reflectClosedTrades()
+	at.reflectClosedTrades() // Already journaled, nothing new
+
+	entries, err := st.Journal().List(at.id, "", 10)
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	e := entries[0]
+	assert.Equal(t, pos.ID, e.PositionID)
+	assert.Equal(t, "long", e.Side)
+	assert.Equal(t, store.RegimeTrendingUp, e.Regime)
+	assert.InDelta(t, -10.0, e.PnLPct, 1e-9)
+	assert.Equal(t, "Breakout above the 4h range with rising volume", e.EntryReasoning)
+	assert.Empty(t, e.ExitReasoning)
+	assert.Contains(t, e.Summary, "SOLUSDT long 5x in trending_up: loss -10.00% (-20.00 USDT)")
+	assert.Contains(t, e.Summary, "closed by stop_loss")
+
+	// A deleted entry is hidden and the position isn't journaled again
+	require.NoError(t, st.Journal().Delete(at.id, e.ID))
+	at.reflectClosedTrades()
+	assert.Empty(t, at.loadJournal())
+}
+
+// TestJournalSurvivesHistoryRebuild tests that edited and deleted entries of rebuilt positions are kept
+func TestJournalSurvivesHistoryRebuild(t *testing.T) {
+	st, err := store.New(filepath.Join(t.TempDir(), "test.db"))
+	require.NoError(t, err)
+	defer st.Close()
+
+	at := &AutoTrader{id: "trader-journal-rebuild", name: "journal", store: st}
+	entry := time.Now().Add(-5 * time.Hour).Truncate(time.Second)
+	closes := []store.ClosedPnLRecord{
+		{Symbol: "BTCUSDT", Side: "LONG", EntryPrice: 100, ExitPrice: 110, Quantity: 1, RealizedPnL: 10, Leverage: 2,
+			EntryTime: entry, ExitTime: entry.Add(time.Hour), OrderID: "close-1"},
+		{Symbol: "ETHUSDT", Side: "SHORT", EntryPrice: 50, ExitPrice: 55, Quantity: 2, RealizedPnL: -10, Leverage: 2,
+			EntryTime: entry, ExitTime: entry.Add(2 * time.Hour), OrderID: "close-2"},
+	}
+	syncCloses := func() {
+		for i := range closes {
+			created, err := st.Position().CreateFromClosedPnL(at.id, "ex-1", "binance", &closes[i])
+			require.NoError(t, err)
+			require.True(t, created)
+		}
+	}
+	syncCloses()
+	at.reflectClosedTrades()
+
+	entries, err := st.Journal().List(at.id, "", 10)
+	require.NoError(t, err)
+	require.Len(t, entries, 2)
+	btc, eth := entries[1], entries[0]
+	require.NoError(t, st.Journal().UpdateSummary(at.id, btc.ID, "Edited by the user"))
+	require.NoError(t, st.Journal().Delete(at.id, eth.ID))
+
+	// Rebuild: synced positions are replaced by new rows, fills place the entry a few seconds off
+	removed, err := st.Position().DeleteSyncedClosedSince(at.id, entry)
+	require.NoError(t, err)
+	assert.EqualValues(t, 2, removed)
+	closes[0].EntryTime = entry.Add(3 * time.Second)
+	syncCloses()
+	relinked, err := st.Journal().RelinkDetached(at.id, journalRelinkWindow)
+	require.NoError(t, err)
+	assert.Equal(t, 2, relinked)
+
+	at.reflectClosedTrades()
+	entries, err = st.Journal().List(at.id, "", 10)
+	require.NoError(t, err)
+	require.Len(t, entries, 1, "the deleted entry stays deleted and nothing is journaled twice")
+	assert.Equal(t, btc.ID, entries[0].ID)
+	assert.Equal(t, "Edited by the user", entries[0].Summary)
+	assert.NotEqual(t, btc.PositionID, entries[0].PositionID)
+	assert.Positive(t, entries[0].PositionID)
+}
@@ -0,0 +1,131 @@
+package trader
+
+import (
+	"fmt"
+	"nofx/logger"
+	"sort"
+	"strings"
+	"time"
+)
+
+// FlattenPosition outcome of market-closing one position
+type FlattenPosition struct {
+	Symbol   string  `json:"symbol"`
+	Side     string  `json:"side"` // "long" or "short"
+	Quantity float64 `json:"quantity"`
+	Success  bool    `json:"success"`
+	Error    string  `json:"error,omitempty"`
+}
+
+// FlattenResult outcome of flattening one exchange account
+type FlattenResult struct {
+	ExchangeID         string            `json:"exchange_id"`
+	ExchangeType       string            `json:"exchange_type"`
+	AccountName        string            `json:"account_name"`
+	CancelledSymbols   []string          `json:"cancelled_symbols"`         // Symbols whose open orders were cancelled
+	Positions          []FlattenPosition `json:"positions"`                 // Close attempts
+	RemainingPositions int               `json:"remaining_positions"`       // Positions still open after flattening (-1 = unknown)
+	Errors             []string          `json:"errors,omitempty"`          // Order cancellation / query errors
+	Success            bool              `json:"success"`
+	DurationMs         int64             `json:"duration_ms"`
+}
+
+// FlattenAccount cancels all open orders and market-closes every position of an exchange account,
+// including positions not opened by nofx. extraSymbols are symbols that may have open orders
+// without a position (the Trader interface can only cancel orders per symbol).
+func FlattenAccount(t Trader, extraSymbols []string) *FlattenResult {
+	start := time.Now()
+	result := &FlattenResult{CancelledSymbols: []string{}, Positions: []FlattenPosition{}, RemainingPositions: -1}
+
+	positions, err := t.GetPositions()
+	if err != nil {
+		result.Errors = append(result.Errors, fmt.Sprintf("get positions: %v", err))
+	}
+
+	// 1. Cancel orders first, so stop/take-profit orders can't reopen or interfere with the closes
+	symbols := make(map[string]bool)
+	for _, pos := range positions {
+		if symbol, _ := pos["symbol"].(string); symbol != "" {
+			symbols[symbol] = true
+		}
+	}
+	for _, symbol := range extraSymbols {
+		if symbol != "" {
+			symbols[strings.ToUpper(symbol)] = true
+		}
+	}
+	sortedSymbols := make([]string, 0, len(symbols))
+	for symbol := range symbols {
+		sortedSymbols = append(sortedSymbols, symbol)
+	}
+	sort.Strings(sortedSymbols)
+
+	for _, symbol := range sortedSymbols {
+		if err := t.CancelAllOrders(symbol); err != nil {
+			result.Errors = append(result.Errors, fmt.Sprintf("cancel orders %s: %v", symbol, err))
+			continue
+		}
+		result.CancelledSymbols = append(result.CancelledSymbols, symbol)
+	}
+
+	// 2. Market-close every position
+	for _, pos := range positions {
+		symbol, _ := pos["symbol"].(string)
+		side, _ := pos["side"].(string)
+		quantity, _ := pos["positionAmt"].(float64)
+		if quantity < 0 {
+			quantity = -quantity
+		}
+		if symbol == "" || quantity == 0 {
+			continue
+		}
+
+		closed := FlattenPosition{Symbol: symbol, Side: side, Quantity: quantity}
+		var closeErr error
+		switch side {
+		case "long":
+			_, closeErr = t.CloseLong(symbol, 0)
+		case "short":
+			_, closeErr = t.CloseShort(symbol, 0)
+		default:
+			closeErr = fmt.Errorf("unknown position side %q", side)
+		}
+		if closeErr != nil {
+			closed.Error = closeErr.Error()
+			logger.Infof("❌ Kill switch: failed to close %s %s: %v", symbol, side, closeErr)
+		} else {
+			closed.Success = true
+			logger.Infof("🛑 Kill switch: closed %s %s (%.6f)", symbol, side, quantity)
+		}
+		result.Positions = append(result.Positions, closed)
+	}
+
+	// 3. Verify the account is flat
+	if remaining, err := t.GetPositions(); err != nil {
+		result.Errors = append(result.Errors, fmt.Sprintf("verify positions: %v", err))
+	} else {
+		result.RemainingPositions = 0
+		for _, pos := range remaining {
+			if quantity, _ := pos["positionAmt"].(float64); quantity != 0 {
+				result.RemainingPositions++
+			}
+		}
+	}
+
+	result.Success = len(result.Errors) == 0 && result.RemainingPositions == 0
+	for _, p := range result.Positions {
+		if !p.Success {
+			result.Success = false
+		}
+	}
+	result.DurationMs = time.Since(start).Milliseconds()
+	return result
+}
+
+// Kill stops the trader for the kill switch
+// New positions are refused immediately, even by a cycle that is still running
+// Returns once the main loop has exited (see Stop)
+func (at *AutoTrader) Kill() {
+	at.killed.Store(true)
+	at.Stop()
+}
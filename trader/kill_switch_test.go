@@ -0,0 +1,114 @@
+package trader
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"nofx/decision"
+	"nofx/store"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// flattenStubTrader simulates an exchange account for FlattenAccount
+type flattenStubTrader struct {
+	Trader
+	positions []map[string]interface{}
+	cancelled []string
+	failClose string // symbol whose close fails
+}
+
+func (s *flattenStubTrader) GetPositions() ([]map[string]interface{}, error) {
+	return s.positions, nil
+}
+
+func (s *flattenStubTrader) CancelAllOrders(symbol string) error {
+	s.cancelled = append(s.cancelled, symbol)
+	return nil
+}
+
+func (s *flattenStubTrader) close(symbol, side string) (map[string]interface{}, error) {
+	if symbol == s.failClose {
+		return nil, fmt.Errorf("insufficient liquidity")
+	}
+	remaining := s.positions[:0]
+	for _, pos := range s.positions {
+		if pos["symbol"] != symbol || pos["side"] != side {
+			remaining = append(remaining, pos)
+		}
+	}
+	s.positions = remaining
+	return map[string]interface{}{"orderId": 1}, nil
+}
+
+func (s *flattenStubTrader) CloseLong(symbol string, quantity float64) (map[string]interface{}, error) {
+	return s.close(symbol, "long")
+}
+
+func (s *flattenStubTrader) CloseShort(symbol string, quantity float64) (map[string]interface{}, error) {
+	return s.close(symbol, "short")
+}
+
+// TestFlattenAccount tests orders are cancelled and every position (including foreign ones) is closed
+func TestFlattenAccount(t *testing.T) {
+	stub := &flattenStubTrader{positions: []map[string]interface{}{
+		{"symbol": "BTCUSDT", "side": "long", "positionAmt": 0.5},
+		{"symbol": "ETHUSDT", "side": "short", "positionAmt": -2.0},
+	}}
+
+	result := FlattenAccount(stub, []string{"solusdt", "BTCUSDT"})
+	assert.Equal(t, []string{"BTCUSDT", "ETHUSDT", "SOLUSDT"}, stub.cancelled)
+	require.Len(t, result.Positions, 2)
+	assert.True(t, result.Positions[0].Success)
+	assert.InDelta(t, 2.0, result.Positions[1].Quantity, 1e-9)
+	assert.Equal(t, 0, result.RemainingPositions)
+	assert.True(t, result.Success)
+
+	// A failed close is reported and the account is not flat
+	stub = &flattenStubTrader{
+		positions: []map[string]interface{}{{"symbol": "BTCUSDT", "side": "long", "positionAmt": 0.5}},
+		failClose: "BTCUSDT",
+	}
+	result = FlattenAccount(stub, nil)
+	require.Len(t, result.Positions, 1)
+	assert.False(t, result.Positions[0].Success)
+	assert.Contains(t, result.Positions[0].Error, "insufficient liquidity")
+	assert.Equal(t, 1, result.RemainingPositions)
+	assert.False(t, result.Success)
+}
+
+// TestKillRefusesOpens tests a killed trader refuses new positions
+func TestKillRefusesOpens(t *testing.T) {
+	at := &AutoTrader{name: "test", strategyEngine: decision.NewStrategyEngine(&store.StrategyConfig{})}
+	at.Kill()
+
+	err := at.executeDecisionWithRecord(&decision.Decision{Symbol: "BTCUSDT", Action: "open_short"}, &store.DecisionAction{})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "kill switch")
+}
+
+// TestKillWaitsForMainLoop tests Kill only returns once the main loop (and its cycle in flight) has exited
+func TestKillWaitsForMainLoop(t *testing.T) {
+	at := &AutoTrader{name: "test", isRunning: true, stopMonitorCh: make(chan struct{}), loopDone: make(chan struct{})}
+
+	killed := make(chan struct{})
+	go func() {
+		at.Kill()
+		close(killed)
+	}()
+	select {
+	case <-killed:
+		t.Fatal("Kill returned while the main loop was still running")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	close(at.loopDone)
+	select {
+	case <-killed:
+	case <-time.After(time.Second):
+		t.Fatal("Kill did not return after the main loop exited")
+	}
+	assert.True(t, at.killed.Load())
+}
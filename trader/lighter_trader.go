@@ -38,6 +38,14 @@ type LighterTrader struct {
 	precisionMutex  sync.RWMutex
 }
 
+// SymbolPrecision Symbol precision information
+type SymbolPrecision struct {
+	PricePrecision    int
+	QuantityPrecision int
+	TickSize          float64 // Price tick size
+	StepSize          float64 // Quantity step size
+}
+
 // LighterConfig LIGHTER configuration
 type LighterConfig struct {
 	PrivateKeyHex string
@@ -0,0 +1,269 @@
+package trader
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+)
+
+const (
+	okxPrivateWSURL     = "wss://ws.okx.com:8443/ws/v5/private"
+	okxDemoPrivateWSURL = "wss://wspap.okx.com:8443/ws/v5/private"
+	okxWSPingInterval   = 25 * time.Second
+)
+
+// okxWSMessage OKX private stream message (events and channel pushes)
+type okxWSMessage struct {
+	Event string `json:"event"`
+	Code  string `json:"code"`
+	Msg   string `json:"msg"`
+	Arg   struct {
+		Channel string `json:"channel"`
+	} `json:"arg"`
+	Data json.RawMessage `json:"data"`
+}
+
+// okxWSOrder orders channel item
+type okxWSOrder struct {
+	InstType   string `json:"instType"`
+	InstId     string `json:"instId"`
+	OrdId      string `json:"ordId"`
+	ClOrdId    string `json:"clOrdId"`
+	AlgoId     string `json:"algoId"`
+	Side       string `json:"side"`
+	PosSide    string `json:"posSide"`
+	FillPx     string `json:"fillPx"`
+	FillSz     string `json:"fillSz"`
+	FillFee    string `json:"fillFee"`
+	FillPnl    string `json:"fillPnl"`
+	FillTime   string `json:"fillTime"`
+	State      string `json:"state"`
+	Category   string `json:"category"`
+	ReduceOnly string `json:"reduceOnly"`
+}
+
+// okxWSPosition positions channel item
+type okxWSPosition struct {
+	InstType string `json:"instType"`
+	InstId   string `json:"instId"`
+	PosSide  string `json:"posSide"`
+	Pos      string `json:"pos"`
+	AvgPx    string `json:"avgPx"`
+	UTime    string `json:"uTime"`
+}
+
+// okxWSAccount account channel item
+type okxWSAccount struct {
+	UTime   string `json:"uTime"`
+	Details []struct {
+		Ccy     string `json:"ccy"`
+		CashBal string `json:"cashBal"`
+	} `json:"details"`
+}
+
+// StreamAccount streams OKX private channels (orders/positions/account) until ctx is cancelled or the stream drops
+func (t *OKXTrader) StreamAccount(ctx context.Context, handler func(AccountEvent)) error {
+	connected := false
+
+	onOpen := func(c *accountWSConn) error {
+		timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+		return c.writeJSON(map[string]interface{}{
+			"op": "login",
+			"args": []map[string]string{{
+				"apiKey":     t.apiKey,
+				"passphrase": t.passphrase,
+				"timestamp":  timestamp,
+				"sign":       t.sign(timestamp, "GET", "/users/self/verify", ""),
+			}},
+		})
+	}
+
+	ping := func(c *accountWSConn) error {
+		return c.writeText("ping")
+	}
+
+	onMessage := func(c *accountWSConn, raw []byte) error {
+		if string(raw) == "pong" {
+			return nil
+		}
+
+		var msg okxWSMessage
+		if err := json.Unmarshal(raw, &msg); err != nil {
+			return nil // Ignore malformed messages
+		}
+
+		switch msg.Event {
+		case "login":
+			if msg.Code != "0" {
+				return fmt.Errorf("OKX stream login failed: %s", msg.Msg)
+			}
+			return c.writeJSON(map[string]interface{}{
+				"op": "subscribe",
+				"args": []map[string]string{
+					{"channel": "orders", "instType": "SWAP"},
+					{"channel": "positions", "instType": "SWAP"},
+					{"channel": "account"},
+				},
+			})
+		case "subscribe":
+			if !connected {
+				connected = true
+				t.clearCache()
+				handler(AccountEvent{Type: AccountEventConnected, Time: time.Now()})
+			}
+			return nil
+		case "error":
+			return fmt.Errorf("OKX stream error: code=%s, msg=%s", msg.Code, msg.Msg)
+		}
+
+		if len(msg.Data) == 0 {
+			return nil
+		}
+		for _, ev := range t.okxAccountEvents(msg) {
+			if ev.Type == AccountEventPosition {
+				t.clearCache()
+			}
+			handler(ev)
+		}
+		return nil
+	}
+
+	wsURL := okxPrivateWSURL
+	if t.testnet {
+		wsURL = okxDemoPrivateWSURL
+	}
+	return runAccountWebsocket(ctx, wsURL, okxWSPingInterval, onOpen, ping, onMessage)
+}
+
+// okxAccountEvents converts an OKX channel push to unified account events
+// Contract sizes are converted to base asset quantity via ctVal
+func (t *OKXTrader) okxAccountEvents(msg okxWSMessage) []AccountEvent {
+	var events []AccountEvent
+
+	switch msg.Arg.Channel {
+	case "orders":
+		var orders []okxWSOrder
+		if err := json.Unmarshal(msg.Data, &orders); err != nil {
+			return nil
+		}
+		for _, o := range orders {
+			contracts, _ := strconv.ParseFloat(o.FillSz, 64)
+			if o.InstType != "SWAP" || contracts <= 0 {
+				continue // Not a fill
+			}
+
+			symbol := t.convertSymbolBack(o.InstId)
+			price, _ := strconv.ParseFloat(o.FillPx, 64)
+			fee, _ := strconv.ParseFloat(o.FillFee, 64)
+			pnl, _ := strconv.ParseFloat(o.FillPnl, 64)
+			fillTime, _ := strconv.ParseInt(o.FillTime, 10, 64)
+
+			side := "LONG"
+			if o.PosSide == "short" {
+				side = "SHORT"
+			}
+			reduceOnly := o.ReduceOnly == "true" ||
+				(o.PosSide == "long" && o.Side == "sell") || (o.PosSide == "short" && o.Side == "buy")
+
+			events = append(events, AccountEvent{
+				Type:          AccountEventOrderFill,
+				Time:          time.UnixMilli(fillTime),
+				Symbol:        symbol,
+				Side:          side,
+				OrderID:       o.OrdId,
+				ClientOrderID: o.ClOrdId,
+				Price:         price,
+				Quantity:      t.contractsToQuantity(symbol, contracts),
+				OrderFilled:   o.State == "filled",
+				ReduceOnly:    reduceOnly,
+				RealizedPnL:   pnl,
+				Fee:           -fee, // OKX reports fees as negative values
+				CloseReason:   okxCloseReason(o, pnl),
+			})
+		}
+
+	case "positions":
+		var positions []okxWSPosition
+		if err := json.Unmarshal(msg.Data, &positions); err != nil {
+			return nil
+		}
+		for _, p := range positions {
+			if p.InstType != "SWAP" {
+				continue
+			}
+
+			symbol := t.convertSymbolBack(p.InstId)
+			contracts, _ := strconv.ParseFloat(p.Pos, 64)
+			entryPrice, _ := strconv.ParseFloat(p.AvgPx, 64)
+			uTime, _ := strconv.ParseInt(p.UTime, 10, 64)
+
+			side := "LONG"
+			if p.PosSide == "short" || (p.PosSide == "net" && contracts < 0) {
+				side = "SHORT"
+			}
+			if contracts < 0 {
+				contracts = -contracts
+			}
+
+			events = append(events, AccountEvent{
+				Type:        AccountEventPosition,
+				Time:        time.UnixMilli(uTime),
+				Symbol:      symbol,
+				Side:        side,
+				PositionAmt: t.contractsToQuantity(symbol, contracts),
+				EntryPrice:  entryPrice,
+			})
+		}
+
+	case "account":
+		var accounts []okxWSAccount
+		if err := json.Unmarshal(msg.Data, &accounts); err != nil {
+			return nil
+		}
+		for _, a := range accounts {
+			uTime, _ := strconv.ParseInt(a.UTime, 10, 64)
+			for _, d := range a.Details {
+				balance, _ := strconv.ParseFloat(d.CashBal, 64)
+				events = append(events, AccountEvent{
+					Type:    AccountEventBalance,
+					Time:    time.UnixMilli(uTime),
+					Asset:   d.Ccy,
+					Balance: balance,
+				})
+			}
+		}
+	}
+
+	return events
+}
+
+// contractsToQuantity converts OKX contract count to base asset quantity
+func (t *OKXTrader) contractsToQuantity(symbol string, contracts float64) float64 {
+	if contracts == 0 {
+		return 0
+	}
+	inst, err := t.getInstrument(symbol)
+	if err != nil || inst.CtVal <= 0 {
+		return contracts
+	}
+	return contracts * inst.CtVal
+}
+
+// okxCloseReason maps exchange-triggered orders to close reasons
+// OKX doesn't tell whether an algo order was a stop loss or take profit,
+// so the realized PnL sign is used to tell them apart
+func okxCloseReason(o okxWSOrder, pnl float64) string {
+	switch o.Category {
+	case "full_liquidation", "partial_liquidation", "adl":
+		return CloseReasonLiquidation
+	}
+	if o.AlgoId != "" {
+		if pnl < 0 {
+			return CloseReasonStopLoss
+		}
+		return CloseReasonTakeProfit
+	}
+	return ""
+}
@@ -12,6 +12,7 @@ import (
 	"io"
 	"net/http"
 	"nofx/logger"
+	"sort"
 	"strconv"
 	"strings"
 	"sync"
@@ -41,6 +42,9 @@ type OKXTrader struct {
 	secretKey  string
 	passphrase string
 
+	// Demo trading (simulated trading header and demo websocket)
+	testnet bool
+
 	// Margin mode setting
 	isCrossMargin bool
 
@@ -67,14 +71,12 @@ type OKXTrader struct {
 }
 
 // OKXInstrument OKX instrument info
+// Lot size and tick size come from the instrument registry
 type OKXInstrument struct {
 	InstID   string  // Instrument ID
 	CtVal    float64 // Contract value
 	CtMult   float64 // Contract multiplier
-	LotSz    float64 // Minimum order size
-	MinSz    float64 // Minimum order size
 	MaxMktSz float64 // Maximum market order size
-	TickSz   float64 // Minimum price increment
 	CtType   string  // Contract type
 }
 
@@ -100,7 +102,8 @@ func genOkxClOrdID() string {
 }
 
 // NewOKXTrader creates OKX trader
-func NewOKXTrader(apiKey, secretKey, passphrase string) *OKXTrader {
+// testnet routes all requests to OKX demo trading
+func NewOKXTrader(apiKey, secretKey, passphrase string, testnet bool) *OKXTrader {
 	// Use default transport which respects system proxy settings
 	// OKX requires proxy in China due to DNS pollution
 	httpClient := &http.Client{
@@ -112,10 +115,12 @@ func NewOKXTrader(apiKey, secretKey, passphrase string) *OKXTrader {
 		apiKey:           apiKey,
 		secretKey:        secretKey,
 		passphrase:       passphrase,
+		testnet:          testnet,
 		httpClient:       httpClient,
 		cacheDuration:    15 * time.Second,
 		instrumentsCache: make(map[string]*OKXInstrument),
 	}
+	logger.Infof("🟢 [OKX] Trader initialized (%s)", environmentName(testnet))
 
 	// Set dual position mode
 	if err := trader.setPositionMode(); err != nil {
@@ -178,8 +183,12 @@ func (t *OKXTrader) doRequest(method, path string, body interface{}) ([]byte, er
 	req.Header.Set("OK-ACCESS-TIMESTAMP", timestamp)
 	req.Header.Set("OK-ACCESS-PASSPHRASE", t.passphrase)
 	req.Header.Set("Content-Type", "application/json")
-	// Set request header
-	req.Header.Set("x-simulated-trading", "0")
+	// Demo trading uses the same host, selected by the simulated trading header
+	if t.testnet {
+		req.Header.Set("x-simulated-trading", "1")
+	} else {
+		req.Header.Set("x-simulated-trading", "0")
+	}
 
 	resp, err := t.httpClient.Do(req)
 	if err != nil {
@@ -206,6 +215,17 @@ func (t *OKXTrader) doRequest(method, path string, body interface{}) ([]byte, er
 	return okxResp.Data, nil
 }
 
+// clearCache invalidates balance and positions cache
+func (t *OKXTrader) clearCache() {
+	t.balanceCacheMutex.Lock()
+	t.cachedBalance = nil
+	t.balanceCacheMutex.Unlock()
+
+	t.positionsCacheMutex.Lock()
+	t.cachedPositions = nil
+	t.positionsCacheMutex.Unlock()
+}
+
 // convertSymbol converts generic symbol to OKX format
 // e.g. BTCUSDT -> BTC-USDT-SWAP
 func (t *OKXTrader) convertSymbol(symbol string) string {
@@ -281,6 +301,7 @@ func (t *OKXTrader) GetBalance() (map[string]interface{}, error) {
 		"totalWalletBalance":    totalEq,
 		"availableBalance":      usdtAvail,
 		"totalUnrealizedProfit": usdtUPL,
+		"total_equity":          totalEq,
 	}
 
 	logger.Infof("✓ OKX balance: Total equity=%.2f, Available=%.2f, Unrealized PnL=%.2f", totalEq, usdtAvail, usdtUPL)
@@ -392,6 +413,54 @@ func (t *OKXTrader) GetPositions() ([]map[string]interface{}, error) {
 	return result, nil
 }
 
+// GetInstruments gets trading rules of all USDT perpetual swaps (implements InstrumentProvider)
+// OKX trades in contracts, lot step and minimum size are converted to base asset units
+func (t *OKXTrader) GetInstruments() ([]Instrument, error) {
+	data, err := t.doRequest("GET", okxInstrumentsPath+"?instType=SWAP", nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var items []struct {
+		InstId    string `json:"instId"`
+		SettleCcy string `json:"settleCcy"`
+		State     string `json:"state"`
+		CtVal     string `json:"ctVal"`
+		CtMult    string `json:"ctMult"`
+		LotSz     string `json:"lotSz"`
+		MinSz     string `json:"minSz"`
+		TickSz    string `json:"tickSz"`
+		Lever     string `json:"lever"`
+	}
+	if err := json.Unmarshal(data, &items); err != nil {
+		return nil, err
+	}
+
+	var instruments []Instrument
+	for _, item := range items {
+		if item.SettleCcy != "USDT" || item.State != "live" {
+			continue
+		}
+		multiplier := parseFloatOrZero(item.CtVal)
+		if ctMult := parseFloatOrZero(item.CtMult); ctMult > 0 {
+			multiplier *= ctMult
+		}
+		if multiplier <= 0 {
+			multiplier = 1
+		}
+		instruments = append(instruments, Instrument{
+			Symbol:             t.convertSymbolBack(item.InstId),
+			VenueSymbol:        item.InstId,
+			TickSize:           parseFloatOrZero(item.TickSz),
+			LotStep:            parseFloatOrZero(item.LotSz) * multiplier,
+			MinQty:             parseFloatOrZero(item.MinSz) * multiplier,
+			MaxLeverage:        int(parseFloatOrZero(item.Lever)),
+			ContractMultiplier: multiplier,
+		})
+	}
+	return instruments, nil
+}
+
 // getInstrument gets instrument info
 func (t *OKXTrader) getInstrument(symbol string) (*OKXInstrument, error) {
 	instId := t.convertSymbol(symbol)
@@ -415,10 +484,7 @@ func (t *OKXTrader) getInstrument(symbol string) (*OKXInstrument, error) {
 		InstId   string `json:"instId"`
 		CtVal    string `json:"ctVal"`
 		CtMult   string `json:"ctMult"`
-		LotSz    string `json:"lotSz"`
-		MinSz    string `json:"minSz"`
 		MaxMktSz string `json:"maxMktSz"` // Maximum market order size
-		TickSz   string `json:"tickSz"`
 		CtType   string `json:"ctType"`
 	}
 
@@ -433,19 +499,13 @@ func (t *OKXTrader) getInstrument(symbol string) (*OKXInstrument, error) {
 	inst := instruments[0]
 	ctVal, _ := strconv.ParseFloat(inst.CtVal, 64)
 	ctMult, _ := strconv.ParseFloat(inst.CtMult, 64)
-	lotSz, _ := strconv.ParseFloat(inst.LotSz, 64)
-	minSz, _ := strconv.ParseFloat(inst.MinSz, 64)
 	maxMktSz, _ := strconv.ParseFloat(inst.MaxMktSz, 64)
-	tickSz, _ := strconv.ParseFloat(inst.TickSz, 64)
 
 	instrument := &OKXInstrument{
 		InstID:   inst.InstId,
 		CtVal:    ctVal,
 		CtMult:   ctMult,
-		LotSz:    lotSz,
-		MinSz:    minSz,
 		MaxMktSz: maxMktSz,
-		TickSz:   tickSz,
 		CtType:   inst.CtType,
 	}
 
@@ -520,6 +580,11 @@ func (t *OKXTrader) SetLeverage(symbol string, leverage int) error {
 
 // OpenLong opens long position
 func (t *OKXTrader) OpenLong(symbol string, quantity float64, leverage int) (map[string]interface{}, error) {
+	return t.OpenLongWithClientID(symbol, quantity, leverage, "")
+}
+
+// OpenLongWithClientID opens long position tagged with client order ID
+func (t *OKXTrader) OpenLongWithClientID(symbol string, quantity float64, leverage int, clientOrderID string) (map[string]interface{}, error) {
 	// Cancel old orders
 	t.CancelAllOrders(symbol)
 
@@ -539,15 +604,17 @@ func (t *OKXTrader) OpenLong(symbol string, quantity float64, leverage int) (map
 	// OKX uses contract count, need to convert quantity (in base asset) to contract count
 	// sz = quantity / ctVal (number of contracts = asset amount / asset per contract)
 	sz := quantity / inst.CtVal
-	szStr := t.formatSize(sz, inst)
 
 	logger.Infof("  📊 OKX OpenLong: quantity=%.6f, ctVal=%.6f, contracts=%.2f", quantity, inst.CtVal, sz)
 
 	// Check max market order size limit
 	if inst.MaxMktSz > 0 && sz > inst.MaxMktSz {
 		logger.Infof("  ⚠️ OKX market order size %.2f exceeds max %.2f, reducing to max", sz, inst.MaxMktSz)
-		sz = inst.MaxMktSz
-		szStr = t.formatSize(sz, inst)
+		quantity = inst.MaxMktSz * inst.CtVal
+	}
+	szStr, err := t.formatSize(symbol, quantity)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get instrument info: %w", err)
 	}
 
 	body := map[string]interface{}{
@@ -557,46 +624,28 @@ func (t *OKXTrader) OpenLong(symbol string, quantity float64, leverage int) (map
 		"posSide": "long",
 		"ordType": "market",
 		"sz":      szStr,
-		"clOrdId": genOkxClOrdID(),
+		"clOrdId": okxClOrdID(clientOrderID),
 		"tag":     okxTag,
 	}
 
-	data, err := t.doRequest("POST", okxOrderPath, body)
+	order, err := t.placeOrder(symbol, body)
 	if err != nil {
 		return nil, fmt.Errorf("failed to open long position: %w", err)
 	}
 
-	var orders []struct {
-		OrdId   string `json:"ordId"`
-		ClOrdId string `json:"clOrdId"`
-		SCode   string `json:"sCode"`
-		SMsg    string `json:"sMsg"`
-	}
-
-	if err := json.Unmarshal(data, &orders); err != nil {
-		return nil, fmt.Errorf("failed to parse order response: %w", err)
-	}
-
-	if len(orders) == 0 || orders[0].SCode != "0" {
-		msg := "unknown error"
-		if len(orders) > 0 {
-			msg = orders[0].SMsg
-		}
-		return nil, fmt.Errorf("failed to open long position: %s", msg)
-	}
-
 	logger.Infof("✓ OKX opened long position successfully: %s size: %s", symbol, szStr)
-	logger.Infof("  Order ID: %s", orders[0].OrdId)
+	logger.Infof("  Order ID: %v", order["orderId"])
 
-	return map[string]interface{}{
-		"orderId": orders[0].OrdId,
-		"symbol":  symbol,
-		"status":  "FILLED",
-	}, nil
+	return order, nil
 }
 
 // OpenShort opens short position
 func (t *OKXTrader) OpenShort(symbol string, quantity float64, leverage int) (map[string]interface{}, error) {
+	return t.OpenShortWithClientID(symbol, quantity, leverage, "")
+}
+
+// OpenShortWithClientID opens short position tagged with client order ID
+func (t *OKXTrader) OpenShortWithClientID(symbol string, quantity float64, leverage int, clientOrderID string) (map[string]interface{}, error) {
 	// Cancel old orders
 	t.CancelAllOrders(symbol)
 
@@ -616,15 +665,17 @@ func (t *OKXTrader) OpenShort(symbol string, quantity float64, leverage int) (ma
 	// OKX uses contract count, need to convert quantity (in base asset) to contract count
 	// sz = quantity / ctVal (number of contracts = asset amount / asset per contract)
 	sz := quantity / inst.CtVal
-	szStr := t.formatSize(sz, inst)
 
 	logger.Infof("  📊 OKX OpenShort: quantity=%.6f, ctVal=%.6f, contracts=%.2f", quantity, inst.CtVal, sz)
 
 	// Check max market order size limit
 	if inst.MaxMktSz > 0 && sz > inst.MaxMktSz {
 		logger.Infof("  ⚠️ OKX market order size %.2f exceeds max %.2f, reducing to max", sz, inst.MaxMktSz)
-		sz = inst.MaxMktSz
-		szStr = t.formatSize(sz, inst)
+		quantity = inst.MaxMktSz * inst.CtVal
+	}
+	szStr, err := t.formatSize(symbol, quantity)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get instrument info: %w", err)
 	}
 
 	body := map[string]interface{}{
@@ -634,46 +685,28 @@ func (t *OKXTrader) OpenShort(symbol string, quantity float64, leverage int) (ma
 		"posSide": "short",
 		"ordType": "market",
 		"sz":      szStr,
-		"clOrdId": genOkxClOrdID(),
+		"clOrdId": okxClOrdID(clientOrderID),
 		"tag":     okxTag,
 	}
 
-	data, err := t.doRequest("POST", okxOrderPath, body)
+	order, err := t.placeOrder(symbol, body)
 	if err != nil {
 		return nil, fmt.Errorf("failed to open short position: %w", err)
 	}
 
-	var orders []struct {
-		OrdId   string `json:"ordId"`
-		ClOrdId string `json:"clOrdId"`
-		SCode   string `json:"sCode"`
-		SMsg    string `json:"sMsg"`
-	}
-
-	if err := json.Unmarshal(data, &orders); err != nil {
-		return nil, fmt.Errorf("failed to parse order response: %w", err)
-	}
-
-	if len(orders) == 0 || orders[0].SCode != "0" {
-		msg := "unknown error"
-		if len(orders) > 0 {
-			msg = orders[0].SMsg
-		}
-		return nil, fmt.Errorf("failed to open short position: %s", msg)
-	}
-
 	logger.Infof("✓ OKX opened short position successfully: %s size: %s", symbol, szStr)
-	logger.Infof("  Order ID: %s", orders[0].OrdId)
+	logger.Infof("  Order ID: %v", order["orderId"])
 
-	return map[string]interface{}{
-		"orderId": orders[0].OrdId,
-		"symbol":  symbol,
-		"status":  "FILLED",
-	}, nil
+	return order, nil
 }
 
 // CloseLong closes long position
 func (t *OKXTrader) CloseLong(symbol string, quantity float64) (map[string]interface{}, error) {
+	return t.CloseLongWithClientID(symbol, quantity, "")
+}
+
+// CloseLongWithClientID closes long position tagged with client order ID
+func (t *OKXTrader) CloseLongWithClientID(symbol string, quantity float64, clientOrderID string) (map[string]interface{}, error) {
 	instId := t.convertSymbol(symbol)
 
 	// Get instrument info for contract conversion
@@ -702,7 +735,10 @@ func (t *OKXTrader) CloseLong(symbol string, quantity float64) (map[string]inter
 	// Convert quantity (base asset) to contract count
 	// contracts = quantity / ctVal
 	contracts := quantity / inst.CtVal
-	szStr := t.formatSize(contracts, inst)
+	szStr, err := t.formatSize(symbol, quantity)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get instrument info: %w", err)
+	}
 
 	logger.Infof("🔻 OKX close long: symbol=%s, quantity=%.6f, ctVal=%.6f, contracts=%.2f, szStr=%s",
 		symbol, quantity, inst.CtVal, contracts, szStr)
@@ -714,47 +750,30 @@ func (t *OKXTrader) CloseLong(symbol string, quantity float64) (map[string]inter
 		"posSide": "long",
 		"ordType": "market",
 		"sz":      szStr,
-		"clOrdId": genOkxClOrdID(),
+		"clOrdId": okxClOrdID(clientOrderID),
 		"tag":     okxTag,
 	}
 
-	data, err := t.doRequest("POST", okxOrderPath, body)
+	order, err := t.placeOrder(symbol, body)
 	if err != nil {
 		return nil, fmt.Errorf("failed to close long position: %w", err)
 	}
 
-	var orders []struct {
-		OrdId string `json:"ordId"`
-		SCode string `json:"sCode"`
-		SMsg  string `json:"sMsg"`
-	}
-
-	if err := json.Unmarshal(data, &orders); err != nil {
-		return nil, err
-	}
-
-	if len(orders) == 0 || orders[0].SCode != "0" {
-		msg := "unknown error"
-		if len(orders) > 0 {
-			msg = orders[0].SMsg
-		}
-		return nil, fmt.Errorf("failed to close long position: %s", msg)
-	}
-
 	logger.Infof("✓ OKX closed long position successfully: %s", symbol)
 
 	// Cancel pending orders after closing position
 	t.CancelAllOrders(symbol)
 
-	return map[string]interface{}{
-		"orderId": orders[0].OrdId,
-		"symbol":  symbol,
-		"status":  "FILLED",
-	}, nil
+	return order, nil
 }
 
 // CloseShort closes short position
 func (t *OKXTrader) CloseShort(symbol string, quantity float64) (map[string]interface{}, error) {
+	return t.CloseShortWithClientID(symbol, quantity, "")
+}
+
+// CloseShortWithClientID closes short position tagged with client order ID
+func (t *OKXTrader) CloseShortWithClientID(symbol string, quantity float64, clientOrderID string) (map[string]interface{}, error) {
 	instId := t.convertSymbol(symbol)
 
 	// Get instrument info for contract conversion
@@ -792,7 +811,10 @@ func (t *OKXTrader) CloseShort(symbol string, quantity float64) (map[string]inte
 	// Convert quantity (base asset) to contract count
 	// contracts = quantity / ctVal
 	contracts := quantity / inst.CtVal
-	szStr := t.formatSize(contracts, inst)
+	szStr, err := t.formatSize(symbol, quantity)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get instrument info: %w", err)
+	}
 
 	logger.Infof("🔻 OKX close short: symbol=%s, quantity=%.6f, ctVal=%.6f, contracts=%.2f, szStr=%s",
 		symbol, quantity, inst.CtVal, contracts, szStr)
@@ -804,46 +826,23 @@ func (t *OKXTrader) CloseShort(symbol string, quantity float64) (map[string]inte
 		"posSide": "short",
 		"ordType": "market",
 		"sz":      szStr,
-		"clOrdId": genOkxClOrdID(),
+		"clOrdId": okxClOrdID(clientOrderID),
 		"tag":     okxTag,
 	}
 
 	logger.Infof("🔻 OKX close short request body: %+v", body)
 
-	data, err := t.doRequest("POST", okxOrderPath, body)
+	order, err := t.placeOrder(symbol, body)
 	if err != nil {
 		return nil, fmt.Errorf("failed to close short position: %w", err)
 	}
 
-	var orders []struct {
-		OrdId string `json:"ordId"`
-		SCode string `json:"sCode"`
-		SMsg  string `json:"sMsg"`
-	}
-
-	if err := json.Unmarshal(data, &orders); err != nil {
-		return nil, err
-	}
-
-	if len(orders) == 0 || orders[0].SCode != "0" {
-		msg := "unknown error"
-		if len(orders) > 0 {
-			msg = fmt.Sprintf("sCode=%s, sMsg=%s", orders[0].SCode, orders[0].SMsg)
-		}
-		logger.Infof("❌ OKX failed to close short position: %s, response: %s", msg, string(data))
-		return nil, fmt.Errorf("failed to close short position: %s", msg)
-	}
-
-	logger.Infof("✓ OKX closed short position successfully: %s, ordId=%s", symbol, orders[0].OrdId)
+	logger.Infof("✓ OKX closed short position successfully: %s, ordId=%v", symbol, order["orderId"])
 
 	// Cancel pending orders after closing position
 	t.CancelAllOrders(symbol)
 
-	return map[string]interface{}{
-		"orderId": orders[0].OrdId,
-		"symbol":  symbol,
-		"status":  "FILLED",
-	}, nil
+	return order, nil
 }
 
 // GetMarketPrice gets market price
@@ -880,16 +879,12 @@ func (t *OKXTrader) GetMarketPrice(symbol string) (float64, error) {
 func (t *OKXTrader) SetStopLoss(symbol string, positionSide string, quantity, stopPrice float64) error {
 	instId := t.convertSymbol(symbol)
 
-	// Get instrument info
-	inst, err := t.getInstrument(symbol)
+	// Calculate contract size: quantity (in base asset) / ctVal (asset per contract)
+	szStr, err := t.formatSize(symbol, quantity)
 	if err != nil {
 		return fmt.Errorf("failed to get instrument info: %w", err)
 	}
 
-	// Calculate contract size: quantity (in base asset) / ctVal (asset per contract)
-	sz := quantity / inst.CtVal
-	szStr := t.formatSize(sz, inst)
-
 	// Determine direction
 	side := "sell"
 	posSide := "long"
@@ -923,16 +918,12 @@ func (t *OKXTrader) SetStopLoss(symbol string, positionSide string, quantity, st
 func (t *OKXTrader) SetTakeProfit(symbol string, positionSide string, quantity, takeProfitPrice float64) error {
 	instId := t.convertSymbol(symbol)
 
-	// Get instrument info
-	inst, err := t.getInstrument(symbol)
+	// Calculate contract size: quantity (in base asset) / ctVal (asset per contract)
+	szStr, err := t.formatSize(symbol, quantity)
 	if err != nil {
 		return fmt.Errorf("failed to get instrument info: %w", err)
 	}
 
-	// Calculate contract size: quantity (in base asset) / ctVal (asset per contract)
-	sz := quantity / inst.CtVal
-	szStr := t.formatSize(sz, inst)
-
 	// Determine direction
 	side := "sell"
 	posSide := "long"
@@ -1062,42 +1053,96 @@ func (t *OKXTrader) CancelStopOrders(symbol string) error {
 
 // FormatQuantity formats quantity (converts base asset quantity to contract count)
 func (t *OKXTrader) FormatQuantity(symbol string, quantity float64) (string, error) {
-	inst, err := t.getInstrument(symbol)
+	szStr, err := t.formatSize(symbol, quantity)
 	if err != nil {
 		return fmt.Sprintf("%.3f", quantity), nil
 	}
-
-	// OKX uses contract count: quantity (in base asset) / ctVal (asset per contract)
-	sz := quantity / inst.CtVal
-	return t.formatSize(sz, inst), nil
+	return szStr, nil
 }
 
-// formatSize formats contract size
-func (t *OKXTrader) formatSize(sz float64, inst *OKXInstrument) string {
-	// Determine precision based on lotSz
-	if inst.LotSz >= 1 {
-		return fmt.Sprintf("%.0f", sz)
+// formatSize converts a base asset quantity to a contract count, rounded down to the lot size of the instrument registry
+func (t *OKXTrader) formatSize(symbol string, quantity float64) (string, error) {
+	inst, err := venueInstrument(Venue{Exchange: "okx", Testnet: t.testnet}, t, symbol)
+	if err != nil {
+		return "", err
 	}
+	return inst.FormatContracts(quantity), nil
+}
 
-	// Calculate decimal places
-	lotSzStr := fmt.Sprintf("%f", inst.LotSz)
-	dotIndex := strings.Index(lotSzStr, ".")
-	if dotIndex == -1 {
-		return fmt.Sprintf("%.0f", sz)
+// placeOrder submits an order request, resolving ambiguous failures by clOrdId
+func (t *OKXTrader) placeOrder(symbol string, body map[string]interface{}) (map[string]interface{}, error) {
+	clOrdID, _ := body["clOrdId"].(string)
+
+	submit := func() (map[string]interface{}, error) {
+		data, err := t.doRequest("POST", okxOrderPath, body)
+		if err != nil {
+			return nil, err
+		}
+
+		var orders []struct {
+			OrdId   string `json:"ordId"`
+			ClOrdId string `json:"clOrdId"`
+			SCode   string `json:"sCode"`
+			SMsg    string `json:"sMsg"`
+		}
+
+		if err := json.Unmarshal(data, &orders); err != nil {
+			return nil, fmt.Errorf("failed to parse order response: %w", err)
+		}
+
+		if len(orders) == 0 || orders[0].SCode != "0" {
+			msg := "unknown error"
+			if len(orders) > 0 {
+				msg = fmt.Sprintf("sCode=%s, sMsg=%s", orders[0].SCode, orders[0].SMsg)
+			}
+			logger.Infof("❌ OKX order rejected: %s, response: %s", msg, string(data))
+			return nil, fmt.Errorf("%s", msg)
+		}
+
+		return map[string]interface{}{
+			"orderId":       orders[0].OrdId,
+			"clientOrderId": orders[0].ClOrdId,
+			"symbol":        symbol,
+			"status":        "FILLED",
+		}, nil
+	}
+	lookup := func() (map[string]interface{}, error) {
+		return t.GetOrderByClientID(symbol, clOrdID)
 	}
 
-	// Remove trailing zeros
-	lotSzStr = strings.TrimRight(lotSzStr, "0")
-	precision := len(lotSzStr) - dotIndex - 1
+	return submitWithClientOrderID(clOrdID, submit, lookup)
+}
 
-	format := fmt.Sprintf("%%.%df", precision)
-	return fmt.Sprintf(format, sz)
+// okxClOrdID converts a client order ID to OKX clOrdId (order tag prefix, alphanumeric, max 32 characters)
+// Empty ID falls back to a random ID
+func okxClOrdID(clientOrderID string) string {
+	if clientOrderID == "" {
+		return genOkxClOrdID()
+	}
+	if strings.HasPrefix(clientOrderID, okxTag) {
+		return clientOrderID
+	}
+	orderID := okxTag + clientOrderID
+	if len(orderID) > 32 {
+		orderID = orderID[:32]
+	}
+	return orderID
 }
 
 // GetOrderStatus gets order status
 func (t *OKXTrader) GetOrderStatus(symbol string, orderID string) (map[string]interface{}, error) {
+	return t.queryOrder(symbol, "ordId", orderID)
+}
+
+// GetOrderByClientID gets order status by client order ID (clOrdId)
+func (t *OKXTrader) GetOrderByClientID(symbol string, clientOrderID string) (map[string]interface{}, error) {
+	return t.queryOrder(symbol, "clOrdId", okxClOrdID(clientOrderID))
+}
+
+// queryOrder queries a single order by ordId or clOrdId
+func (t *OKXTrader) queryOrder(symbol string, idField string, id string) (map[string]interface{}, error) {
 	instId := t.convertSymbol(symbol)
-	path := fmt.Sprintf("/api/v5/trade/order?instId=%s&ordId=%s", instId, orderID)
+	path := fmt.Sprintf("/api/v5/trade/order?instId=%s&%s=%s", instId, idField, id)
 
 	data, err := t.doRequest("GET", path, nil)
 	if err != nil {
@@ -1106,6 +1151,7 @@ func (t *OKXTrader) GetOrderStatus(symbol string, orderID string) (map[string]in
 
 	var orders []struct {
 		OrdId     string `json:"ordId"`
+		ClOrdId   string `json:"clOrdId"`
 		State     string `json:"state"`
 		AvgPx     string `json:"avgPx"`
 		AccFillSz string `json:"accFillSz"`
@@ -1137,7 +1183,7 @@ func (t *OKXTrader) GetOrderStatus(symbol string, orderID string) (map[string]in
 	inst, err := t.getInstrument(symbol)
 	if err == nil && inst.CtVal > 0 {
 		executedQty = fillSz * inst.CtVal
-		logger.Debugf("  📊 OKX order %s: fillSz(contracts)=%.4f, ctVal=%.6f, executedQty=%.6f", orderID, fillSz, inst.CtVal, executedQty)
+		logger.Debugf("  📊 OKX order %s: fillSz(contracts)=%.4f, ctVal=%.6f, executedQty=%.6f", order.OrdId, fillSz, inst.CtVal, executedQty)
 	}
 
 	// Status mapping
@@ -1154,16 +1200,17 @@ func (t *OKXTrader) GetOrderStatus(symbol string, orderID string) (map[string]in
 	}
 
 	return map[string]interface{}{
-		"orderId":     order.OrdId,
-		"symbol":      symbol,
-		"status":      status,
-		"avgPrice":    avgPrice,
-		"executedQty": executedQty,
-		"side":        order.Side,
-		"type":        order.OrdType,
-		"time":        cTime,
-		"updateTime":  uTime,
-		"commission":  -fee, // OKX returns negative value
+		"orderId":       order.OrdId,
+		"clientOrderId": order.ClOrdId,
+		"symbol":        symbol,
+		"status":        status,
+		"avgPrice":      avgPrice,
+		"executedQty":   executedQty,
+		"side":          order.Side,
+		"type":          order.OrdType,
+		"time":          cTime,
+		"updateTime":    uTime,
+		"commission":    -fee, // OKX returns negative value
 	}, nil
 }
 
@@ -1281,3 +1328,138 @@ func (t *OKXTrader) GetClosedPnL(startTime time.Time, limit int) ([]ClosedPnLRec
 
 	return records, nil
 }
+
+// GetTrades retrieves trade history (fills) from /api/v5/trade/fills-history (last 3 months)
+// The range is walked in 7-day windows so the oldest fills come first; sizes are converted from contracts
+func (t *OKXTrader) GetTrades(startTime time.Time, limit int) ([]TradeRecord, error) {
+	const (
+		window   = 7 * 24 * time.Hour
+		pageSize = 100
+	)
+	if limit <= 0 {
+		limit = 1000
+	}
+
+	var trades []TradeRecord
+	now := time.Now()
+	for windowStart := startTime; windowStart.Before(now) && len(trades) < limit; windowStart = windowStart.Add(window) {
+		windowEnd := windowStart.Add(window)
+		if windowEnd.After(now) {
+			windowEnd = now
+		}
+
+		after := ""
+		for {
+			path := fmt.Sprintf("/api/v5/trade/fills-history?instType=SWAP&begin=%d&end=%d&limit=%d",
+				windowStart.UnixMilli(), windowEnd.UnixMilli(), pageSize)
+			if after != "" {
+				path += "&after=" + after // Pagination: fills older than this bill ID
+			}
+
+			data, err := t.doRequest("GET", path, nil)
+			if err != nil {
+				return nil, fmt.Errorf("failed to get trade history: %w", err)
+			}
+
+			var fills []struct {
+				InstID  string `json:"instId"`
+				TradeID string `json:"tradeId"`
+				BillID  string `json:"billId"`
+				FillPx  string `json:"fillPx"`
+				FillSz  string `json:"fillSz"`
+				FillPnl string `json:"fillPnl"`
+				Side    string `json:"side"`    // buy / sell
+				PosSide string `json:"posSide"` // long / short (hedge), net (one-way)
+				Fee     string `json:"fee"`     // Negative = paid
+				Ts      string `json:"ts"`
+			}
+			if err := json.Unmarshal(data, &fills); err != nil {
+				return nil, fmt.Errorf("failed to parse trade history: %w", err)
+			}
+
+			for _, fill := range fills {
+				symbol := t.convertSymbolBack(fill.InstID)
+				contracts, _ := strconv.ParseFloat(fill.FillSz, 64)
+				ctVal := 1.0
+				if inst, err := t.getInstrument(symbol); err == nil && inst.CtVal > 0 {
+					ctVal = inst.CtVal
+				}
+
+				positionSide := "BOTH"
+				if fill.PosSide == "long" || fill.PosSide == "short" {
+					positionSide = strings.ToUpper(fill.PosSide)
+				}
+
+				ts, _ := strconv.ParseInt(fill.Ts, 10, 64)
+				trades = append(trades, TradeRecord{
+					TradeID:      fill.TradeID,
+					Symbol:       symbol,
+					Side:         strings.ToUpper(fill.Side),
+					PositionSide: positionSide,
+					Price:        parseFloatOrZero(fill.FillPx),
+					Quantity:     contracts * ctVal,
+					RealizedPnL:  parseFloatOrZero(fill.FillPnl),
+					Fee:          -parseFloatOrZero(fill.Fee),
+					Time:         time.UnixMilli(ts),
+				})
+			}
+
+			if len(fills) < pageSize {
+				break
+			}
+			after = fills[len(fills)-1].BillID
+		}
+	}
+
+	// Fills are returned newest first
+	return sortAndLimitTrades(trades, limit), nil
+}
+
+// GetFundingHistory retrieves funding payments from the bills archive (type=8, funding fee)
+func (t *OKXTrader) GetFundingHistory(startTime time.Time) ([]FundingRecord, error) {
+	const pageSize = 100
+
+	var records []FundingRecord
+	after := ""
+	for {
+		path := fmt.Sprintf("/api/v5/account/bills-archive?instType=SWAP&type=8&begin=%d&limit=%d", startTime.UnixMilli(), pageSize)
+		if after != "" {
+			path += "&after=" + after // Pagination: bills older than this bill ID
+		}
+
+		data, err := t.doRequest("GET", path, nil)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get funding history: %w", err)
+		}
+
+		var bills []struct {
+			BillID string `json:"billId"`
+			InstID string `json:"instId"`
+			BalChg string `json:"balChg"`
+			Ts     string `json:"ts"`
+		}
+		if err := json.Unmarshal(data, &bills); err != nil {
+			return nil, fmt.Errorf("failed to parse funding history: %w", err)
+		}
+
+		for _, bill := range bills {
+			amount, _ := strconv.ParseFloat(bill.BalChg, 64)
+			ts, _ := strconv.ParseInt(bill.Ts, 10, 64)
+			records = append(records, FundingRecord{
+				Symbol: t.convertSymbolBack(bill.InstID),
+				Amount: amount,
+				Time:   time.UnixMilli(ts),
+				TxID:   bill.BillID,
+			})
+		}
+
+		if len(bills) < pageSize {
+			break
+		}
+		after = bills[len(bills)-1].BillID
+	}
+
+	// Bills are returned newest first
+	sort.Slice(records, func(i, j int) bool { return records[i].Time.Before(records[j].Time) })
+	return records, nil
+}
@@ -3,6 +3,7 @@ package trader
 import (
 	"fmt"
 	"sort"
+	"strings"
 	"time"
 )
 
@@ -26,96 +27,116 @@ type positionState struct {
 	TotalQty   float64
 }
 
+// rebuildQtyEpsilon quantities below this are treated as zero
+const rebuildQtyEpsilon = 0.00000001
+
 // RebuildPositionsFromTrades reconstructs complete position records from trade history
 // This is the unified algorithm used by all exchanges
 //
 // Algorithm:
 // 1. Sort trades by time
-// 2. For each trade, determine if it's opening or closing based on RealizedPnL
-// 3. Opening trade (RealizedPnL == 0): Add to open trades list
-// 4. Closing trade (RealizedPnL != 0): Match with open trades using FIFO, generate position record
+// 2. For each trade, determine whether it opens or closes a position:
+//   - Hedge mode (LONG/SHORT): SELL closes LONG, BUY closes SHORT
+//   - One-way mode (BOTH): a trade against the tracked net position closes it,
+//     any remainder opens the opposite side
+//
+// 3. Opening trade: Add to open trades list
+// 4. Closing trade: Match with open trades using FIFO, generate position record
 //
 // The algorithm handles:
 // - Partial opens (multiple trades to build a position)
 // - Partial closes (multiple trades to close a position)
 // - Both hedge mode (LONG/SHORT) and one-way mode (BOTH)
+// - Fills without realized PnL (PnL is computed from the matched entry)
+// - Incomplete history (entry is derived from realized PnL when no opening trade is known)
 func RebuildPositionsFromTrades(trades []TradeRecord) []ClosedPnLRecord {
 	if len(trades) == 0 {
 		return nil
 	}
 
 	// Sort trades by time
-	sort.Slice(trades, func(i, j int) bool {
+	sort.SliceStable(trades, func(i, j int) bool {
 		return trades[i].Time.Before(trades[j].Time)
 	})
 
 	// Track positions by symbol_side
 	positions := make(map[string]*positionState)
-	var records []ClosedPnLRecord
+	state := func(symbol, side string) *positionState {
+		key := fmt.Sprintf("%s_%s", symbol, side)
+		if positions[key] == nil {
+			positions[key] = &positionState{}
+		}
+		return positions[key]
+	}
 
+	var records []ClosedPnLRecord
 	for _, trade := range trades {
-		// Determine position side
-		side := determinePositionSide(trade)
-		if side == "" {
+		if trade.Quantity <= 0 || trade.Price <= 0 {
 			continue // Skip invalid trades
 		}
-
-		key := fmt.Sprintf("%s_%s", trade.Symbol, side)
-		if positions[key] == nil {
-			positions[key] = &positionState{}
+		buy := strings.EqualFold(trade.Side, "BUY")
+		if !buy && !strings.EqualFold(trade.Side, "SELL") {
+			continue
 		}
-		state := positions[key]
 
-		if trade.RealizedPnL == 0 {
-			// Opening trade: add to open trades list
-			state.OpenTrades = append(state.OpenTrades, openTradeEntry{
-				Price:    trade.Price,
-				Quantity: trade.Quantity,
-				Fee:      trade.Fee,
-				Time:     trade.Time,
-				TradeID:  trade.TradeID,
-			})
-			state.TotalQty += trade.Quantity
-		} else {
-			// Closing trade: generate position record
-			record := buildClosedPosition(trade, side, state)
-			if record != nil {
+		switch strings.ToUpper(trade.PositionSide) {
+		case "LONG", "SHORT":
+			// Hedge mode: position side is explicit
+			side := strings.ToLower(trade.PositionSide)
+			if (side == "long") == buy {
+				openTrade(state(trade.Symbol, side), trade)
+			} else if record := buildClosedPosition(trade, side, state(trade.Symbol, side)); record != nil {
 				records = append(records, *record)
 			}
-		}
-	}
 
-	return records
-}
+		default:
+			// One-way mode: close the opposite side first, the remainder opens a new position
+			openSide, closeSide := "long", "short"
+			if !buy {
+				openSide, closeSide = "short", "long"
+			}
+			opposite := state(trade.Symbol, closeSide)
 
-// determinePositionSide determines the position side from a trade
-func determinePositionSide(trade TradeRecord) string {
-	// Hedge mode: use PositionSide directly
-	switch trade.PositionSide {
-	case "LONG", "long":
-		return "long"
-	case "SHORT", "short":
-		return "short"
-	}
+			closeQty := opposite.TotalQty
+			if closeQty <= rebuildQtyEpsilon && trade.RealizedPnL != 0 {
+				// Opening trades are outside the history window, the PnL marks this as a close
+				closeQty = trade.Quantity
+			}
+			if closeQty > trade.Quantity {
+				closeQty = trade.Quantity
+			}
 
-	// One-way mode (BOTH or empty): determine from trade direction and RealizedPnL
-	if trade.RealizedPnL == 0 {
-		// Opening trade
-		if trade.Side == "BUY" || trade.Side == "Buy" {
-			return "long"
-		} else if trade.Side == "SELL" || trade.Side == "Sell" {
-			return "short"
-		}
-	} else {
-		// Closing trade
-		if trade.Side == "BUY" || trade.Side == "Buy" {
-			return "short" // Buy to close short
-		} else if trade.Side == "SELL" || trade.Side == "Sell" {
-			return "long" // Sell to close long
+			if closeQty > rebuildQtyEpsilon {
+				closing := trade
+				closing.Quantity = closeQty
+				closing.Fee = trade.Fee * closeQty / trade.Quantity
+				if record := buildClosedPosition(closing, closeSide, opposite); record != nil {
+					records = append(records, *record)
+				}
+			}
+
+			if remaining := trade.Quantity - closeQty; remaining > rebuildQtyEpsilon {
+				opening := trade
+				opening.Quantity = remaining
+				opening.Fee = trade.Fee * remaining / trade.Quantity
+				openTrade(state(trade.Symbol, openSide), opening)
+			}
 		}
 	}
 
-	return ""
+	return records
+}
+
+// openTrade adds an opening trade to the position state
+func openTrade(state *positionState, trade TradeRecord) {
+	state.OpenTrades = append(state.OpenTrades, openTradeEntry{
+		Price:    trade.Price,
+		Quantity: trade.Quantity,
+		Fee:      trade.Fee,
+		Time:     trade.Time,
+		TradeID:  trade.TradeID,
+	})
+	state.TotalQty += trade.Quantity
 }
 
 // buildClosedPosition builds a closed position record from a closing trade
@@ -123,14 +144,14 @@ func buildClosedPosition(trade TradeRecord, side string, state *positionState) *
 	var entryPrice float64
 	var entryTime time.Time
 	var totalEntryFee float64
+	var matchedQty float64
 
 	if len(state.OpenTrades) > 0 {
 		// Use FIFO to match open trades
 		remainingQty := trade.Quantity
 		var weightedSum float64
-		var matchedQty float64
 
-		for i := 0; i < len(state.OpenTrades) && remainingQty > 0.00000001; i++ {
+		for i := 0; i < len(state.OpenTrades) && remainingQty > rebuildQtyEpsilon; i++ {
 			ot := &state.OpenTrades[i]
 			matchQty := ot.Quantity
 			if matchQty > remainingQty {
@@ -140,6 +161,7 @@ func buildClosedPosition(trade TradeRecord, side string, state *positionState) *
 			weightedSum += ot.Price * matchQty
 			matchedQty += matchQty
 			totalEntryFee += ot.Fee * (matchQty / ot.Quantity)
+			ot.Fee -= ot.Fee * (matchQty / ot.Quantity)
 
 			if entryTime.IsZero() {
 				entryTime = ot.Time
@@ -149,20 +171,36 @@ func buildClosedPosition(trade TradeRecord, side string, state *positionState) *
 			ot.Quantity -= matchQty
 
 			// Remove fully consumed open trade
-			if ot.Quantity <= 0.00000001 {
+			if ot.Quantity <= rebuildQtyEpsilon {
 				state.OpenTrades = append(state.OpenTrades[:i], state.OpenTrades[i+1:]...)
 				i--
 			}
 		}
 
-		if matchedQty > 0.00000001 {
+		if matchedQty > rebuildQtyEpsilon {
 			entryPrice = weightedSum / matchedQty
 		}
-		state.TotalQty -= trade.Quantity
+		state.TotalQty -= matchedQty
+		if state.TotalQty < rebuildQtyEpsilon {
+			state.TotalQty = 0
+		}
+	}
+
+	realizedPnL := trade.RealizedPnL
+	if entryPrice > 0 && realizedPnL == 0 {
+		// Exchange doesn't report PnL on fills, compute it from the matched entry
+		if side == "long" {
+			realizedPnL = (trade.Price - entryPrice) * matchedQty
+		} else {
+			realizedPnL = (entryPrice - trade.Price) * matchedQty
+		}
 	}
 
 	// If no open trades found (history incomplete), calculate entry price from PnL
-	if entryPrice == 0 && trade.Quantity > 0 {
+	if entryPrice == 0 {
+		if trade.RealizedPnL == 0 {
+			return nil // Nothing to derive the entry from
+		}
 		// PnL = (exitPrice - entryPrice) * qty for LONG
 		// PnL = (entryPrice - exitPrice) * qty for SHORT
 		if side == "long" {
@@ -184,7 +222,7 @@ func buildClosedPosition(trade TradeRecord, side string, state *positionState) *
 		EntryPrice:  entryPrice,
 		ExitPrice:   trade.Price,
 		Quantity:    trade.Quantity,
-		RealizedPnL: trade.RealizedPnL,
+		RealizedPnL: realizedPnL,
 		Fee:         trade.Fee + totalEntryFee,
 		EntryTime:   entryTime,
 		ExitTime:    trade.Time,
@@ -193,3 +231,12 @@ func buildClosedPosition(trade TradeRecord, side string, state *positionState) *
 		CloseType:   "unknown",
 	}
 }
+
+// sortAndLimitTrades sorts trades oldest first and keeps at most limit of them
+func sortAndLimitTrades(trades []TradeRecord, limit int) []TradeRecord {
+	sort.SliceStable(trades, func(i, j int) bool { return trades[i].Time.Before(trades[j].Time) })
+	if limit > 0 && len(trades) > limit {
+		trades = trades[:limit]
+	}
+	return trades
+}
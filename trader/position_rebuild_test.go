@@ -0,0 +1,273 @@
+package trader
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"nofx/store"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// tradeHistoryTrader Trader stub that only provides fill history
+type tradeHistoryTrader struct {
+	Trader
+	trades []TradeRecord
+}
+
+func (f *tradeHistoryTrader) GetTrades(startTime time.Time, limit int) ([]TradeRecord, error) {
+	var trades []TradeRecord
+	for _, trade := range f.trades {
+		if !trade.Time.Before(startTime) {
+			trades = append(trades, trade)
+		}
+	}
+	return sortAndLimitTrades(trades, limit), nil
+}
+
+// TestTradeHistoryProvider_InterfaceCompliance tests which exchanges provide fill history
+func TestTradeHistoryProvider_InterfaceCompliance(t *testing.T) {
+	var _ TradeHistoryProvider = (*FuturesTrader)(nil)
+	var _ TradeHistoryProvider = (*BybitTrader)(nil)
+	var _ TradeHistoryProvider = (*OKXTrader)(nil)
+	var _ TradeHistoryProvider = (*BitgetTrader)(nil)
+	var _ TradeHistoryProvider = (*GateTrader)(nil)
+	var _ TradeHistoryProvider = (*HyperliquidTrader)(nil)
+	var _ TradeHistoryProvider = (*AsterTrader)(nil)
+	var _ TradeHistoryProvider = (*LighterTrader)(nil)
+	var _ TradeHistoryProvider = (*LighterTraderV2)(nil)
+	var _ TradeHistoryProvider = (*DydxTrader)(nil)
+	var _ symbolTradeHistoryProvider = (*FuturesTrader)(nil)
+}
+
+// TestRebuildPositionsFromTrades_HedgeMode tests FIFO matching with explicit position sides
+func TestRebuildPositionsFromTrades_HedgeMode(t *testing.T) {
+	base := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	trades := []TradeRecord{
+		{TradeID: "3", Symbol: "BTCUSDT", Side: "SELL", PositionSide: "LONG", Price: 110, Quantity: 3, RealizedPnL: 0, Fee: 0.3, Time: base.Add(3 * time.Minute)},
+		{TradeID: "1", Symbol: "BTCUSDT", Side: "BUY", PositionSide: "LONG", Price: 100, Quantity: 2, Fee: 0.2, Time: base},
+		{TradeID: "2", Symbol: "BTCUSDT", Side: "BUY", PositionSide: "LONG", Price: 106, Quantity: 2, Fee: 0.2, Time: base.Add(time.Minute)},
+		{TradeID: "4", Symbol: "BTCUSDT", Side: "SELL", PositionSide: "SHORT", Price: 120, Quantity: 1, Fee: 0.1, Time: base.Add(4 * time.Minute)},
+	}
+
+	records := RebuildPositionsFromTrades(trades)
+	require.Len(t, records, 1)
+
+	rec := records[0]
+	assert.Equal(t, "long", rec.Side)
+	assert.InDelta(t, 102.0, rec.EntryPrice, 1e-9) // 2@100 + 1@106
+	assert.InDelta(t, 110.0, rec.ExitPrice, 1e-9)
+	assert.InDelta(t, 3.0, rec.Quantity, 1e-9)
+	assert.InDelta(t, 24.0, rec.RealizedPnL, 1e-9) // Computed, no PnL on fills
+	assert.InDelta(t, 0.6, rec.Fee, 1e-9)          // Exit fee + prorated entry fees
+	assert.Equal(t, base, rec.EntryTime)
+	assert.Equal(t, "3", rec.ExchangeID)
+}
+
+// TestRebuildPositionsFromTrades_OneWayFlip tests net position tracking when a fill reverses the position
+func TestRebuildPositionsFromTrades_OneWayFlip(t *testing.T) {
+	base := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	trades := []TradeRecord{
+		{TradeID: "1", Symbol: "ETHUSDT", Side: "BUY", PositionSide: "BOTH", Price: 2000, Quantity: 1, Time: base},
+		{TradeID: "2", Symbol: "ETHUSDT", Side: "SELL", PositionSide: "BOTH", Price: 2100, Quantity: 3, RealizedPnL: 100, Fee: 3, Time: base.Add(time.Hour)},
+		{TradeID: "3", Symbol: "ETHUSDT", Side: "BUY", PositionSide: "BOTH", Price: 2050, Quantity: 2, Time: base.Add(2 * time.Hour)},
+	}
+
+	records := RebuildPositionsFromTrades(trades)
+	require.Len(t, records, 2)
+
+	assert.Equal(t, "long", records[0].Side)
+	assert.InDelta(t, 1.0, records[0].Quantity, 1e-9)
+	assert.InDelta(t, 100.0, records[0].RealizedPnL, 1e-9)
+	assert.InDelta(t, 1.0, records[0].Fee, 1e-9) // A third of the flip fill's fee
+
+	assert.Equal(t, "short", records[1].Side)
+	assert.InDelta(t, 2100.0, records[1].EntryPrice, 1e-9)
+	assert.InDelta(t, 2.0, records[1].Quantity, 1e-9)
+	assert.InDelta(t, 100.0, records[1].RealizedPnL, 1e-9)
+	assert.InDelta(t, 2.0, records[1].Fee, 1e-9)
+}
+
+// TestRebuildPositionsFromTrades_IncompleteHistory tests closes whose opening fills are outside the window
+func TestRebuildPositionsFromTrades_IncompleteHistory(t *testing.T) {
+	base := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	trades := []TradeRecord{
+		// Short closed by a buy, entry derived from PnL: 20 = (entry - 90) * 2
+		{TradeID: "1", Symbol: "SOLUSDT", Side: "BUY", PositionSide: "BOTH", Price: 90, Quantity: 2, RealizedPnL: 20, Time: base},
+		// Hedge close without PnL and without known entry can't be rebuilt
+		{TradeID: "2", Symbol: "SOLUSDT", Side: "SELL", PositionSide: "LONG", Price: 95, Quantity: 1, Time: base.Add(time.Minute)},
+	}
+
+	records := RebuildPositionsFromTrades(trades)
+	require.Len(t, records, 1)
+	assert.Equal(t, "short", records[0].Side)
+	assert.InDelta(t, 100.0, records[0].EntryPrice, 1e-9)
+	assert.Equal(t, base, records[0].EntryTime)
+}
+
+// TestSyncClosedPositionsHistory tests rebuilding closed positions from fills through the sync manager
+func TestSyncClosedPositionsHistory(t *testing.T) {
+	st, err := store.New(filepath.Join(t.TempDir(), "test.db"))
+	require.NoError(t, err)
+	defer st.Close()
+
+	traderID := "trader-rebuild-test"
+	base := time.Now().Add(-72 * time.Hour).Truncate(time.Second)
+
+	tt := &tradeHistoryTrader{trades: []TradeRecord{
+		// Long closed by two fills executed together
+		{TradeID: "1", Symbol: "BTCUSDT", Side: "BUY", PositionSide: "BOTH", Price: 100, Quantity: 2, Time: base},
+		{TradeID: "2", Symbol: "BTCUSDT", Side: "SELL", PositionSide: "BOTH", Price: 110, Quantity: 1, Time: base.Add(time.Hour)},
+		{TradeID: "3", Symbol: "BTCUSDT", Side: "SELL", PositionSide: "BOTH", Price: 112, Quantity: 1, Time: base.Add(time.Hour)},
+		// Short closed later
+		{TradeID: "4", Symbol: "ETHUSDT", Side: "SELL", PositionSide: "BOTH", Price: 50, Quantity: 4, Time: base.Add(2 * time.Hour)},
+		{TradeID: "5", Symbol: "ETHUSDT", Side: "BUY", PositionSide: "BOTH", Price: 45, Quantity: 4, Time: base.Add(3 * time.Hour)},
+	}}
+
+	m := NewPositionSyncManager(st, time.Second)
+	m.syncClosedPositionsHistory(traderID, "exchange-1", "gate", tt)
+	m.syncClosedPositionsHistory(traderID, "exchange-1", "gate", tt) // Already synced, nothing new
+
+	positions, err := st.Position().GetClosedPositions(traderID, 10)
+	require.NoError(t, err)
+	require.Len(t, positions, 2)
+
+	bySymbol := make(map[string]*store.TraderPosition)
+	for _, pos := range positions {
+		bySymbol[pos.Symbol] = pos
+	}
+	require.Contains(t, bySymbol, "BTCUSDT")
+	assert.Equal(t, "LONG", bySymbol["BTCUSDT"].Side)
+	assert.InDelta(t, 2.0, bySymbol["BTCUSDT"].Quantity, 1e-9)
+	assert.InDelta(t, 111.0, bySymbol["BTCUSDT"].ExitPrice, 1e-9)
+	assert.InDelta(t, 22.0, bySymbol["BTCUSDT"].RealizedPnL, 1e-9)
+	require.Contains(t, bySymbol, "ETHUSDT")
+	assert.Equal(t, "SHORT", bySymbol["ETHUSDT"].Side)
+	assert.InDelta(t, 20.0, bySymbol["ETHUSDT"].RealizedPnL, 1e-9)
+}
+
+// TestRebuildClosedPositions_SkipsTrackedCloses tests that closes already tracked locally aren't imported again
+func TestRebuildClosedPositions_SkipsTrackedCloses(t *testing.T) {
+	st, err := store.New(filepath.Join(t.TempDir(), "test.db"))
+	require.NoError(t, err)
+	defer st.Close()
+
+	traderID := "trader-rebuild-test"
+
+	// Position closed by the trader itself just now
+	pos := &store.TraderPosition{
+		TraderID:   traderID,
+		Symbol:     "BTCUSDT",
+		Side:       "LONG",
+		Quantity:   1,
+		EntryPrice: 100,
+		EntryTime:  time.Now().Add(-time.Hour),
+		Leverage:   5,
+	}
+	require.NoError(t, st.Position().Create(pos))
+	require.NoError(t, st.Position().ClosePosition(pos.ID, 105, "exit-1", 5, 0.1, "take_profit"))
+
+	now := time.Now()
+	tt := &tradeHistoryTrader{trades: []TradeRecord{
+		{TradeID: "1", Symbol: "BTCUSDT", Side: "BUY", PositionSide: "BOTH", Price: 100, Quantity: 1, Time: now.Add(-time.Hour)},
+		{TradeID: "2", Symbol: "BTCUSDT", Side: "SELL", PositionSide: "BOTH", Price: 105, Quantity: 1, Time: now.Add(-30 * time.Second)},
+		{TradeID: "3", Symbol: "BTCUSDT", Side: "BUY", PositionSide: "BOTH", Price: 100, Quantity: 1, Time: now.Add(-48 * time.Hour)},
+		{TradeID: "4", Symbol: "BTCUSDT", Side: "SELL", PositionSide: "BOTH", Price: 90, Quantity: 1, Time: now.Add(-47 * time.Hour)},
+	}}
+
+	m := NewPositionSyncManager(st, time.Second)
+	created, skipped, err := m.rebuildClosedPositions(traderID, "exchange-1", "binance", tt, now.Add(-72*time.Hour), now.Add(-72*time.Hour))
+	require.NoError(t, err)
+	assert.Equal(t, 1, created) // Only the untracked close from two days ago
+	assert.Equal(t, 1, skipped)
+
+	positions, err := st.Position().GetClosedPositions(traderID, 10)
+	require.NoError(t, err)
+	assert.Len(t, positions, 2)
+}
+
+// TestFetchTradeHistory_NewestFirstVenues tests history is complete on venues whose APIs return the newest fills first
+func TestFetchTradeHistory_NewestFirstVenues(t *testing.T) {
+	start := time.Now().Add(-20 * 24 * time.Hour).Truncate(time.Second)
+	const total = 2500 // One fill every 10 minutes: more than a page in every 7-day window
+	fillTimes := make([]time.Time, total)
+	for i := range fillTimes {
+		fillTimes[i] = start.Add(time.Duration(i) * 10 * time.Minute)
+	}
+
+	// newestFirst returns the indexes of the fills in [from, to], newest first
+	newestFirst := func(from, to time.Time) []int {
+		var idx []int
+		for i := total - 1; i >= 0; i-- {
+			if !fillTimes[i].Before(from) && !fillTimes[i].After(to) {
+				idx = append(idx, i)
+			}
+		}
+		return idx
+	}
+	param := func(r *http.Request, key string) int64 {
+		v, _ := strconv.ParseInt(r.URL.Query().Get(key), 10, 64)
+		return v
+	}
+
+	// Gate: from/to in seconds, limit/offset paging
+	gateServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fills := []map[string]interface{}{}
+		if strings.HasSuffix(r.URL.Path, "/futures/usdt/my_trades_timerange") {
+			idx := newestFirst(time.Unix(param(r, "from"), 0), time.Unix(param(r, "to"), 0))
+			offset, limit := int(param(r, "offset")), int(param(r, "limit"))
+			for n, i := range idx {
+				if n >= offset && n < offset+limit {
+					fills = append(fills, map[string]interface{}{
+						"trade_id": strconv.Itoa(i), "create_time": float64(fillTimes[i].Unix()), "contract": "BTC_USDT",
+						"size": 1, "price": "50000", "fee": "0.01",
+					})
+				}
+			}
+		}
+		json.NewEncoder(w).Encode(fills)
+	}))
+	defer gateServer.Close()
+
+	// dYdX: the latest limit fills created at or before createdBeforeOrAt
+	dydxServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		before := time.Now()
+		if v := r.URL.Query().Get("createdBeforeOrAt"); v != "" {
+			before, _ = time.Parse(time.RFC3339Nano, v)
+		}
+		fills := []map[string]interface{}{}
+		for _, i := range newestFirst(time.Time{}, before) {
+			if len(fills) == int(param(r, "limit")) {
+				break
+			}
+			fills = append(fills, map[string]interface{}{
+				"id": strconv.Itoa(i), "side": "BUY", "market": "BTC-USD", "price": "50000", "size": "1", "fee": "0.01",
+				"createdAt": fillTimes[i].UTC().Format(time.RFC3339Nano),
+			})
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{"fills": fills})
+	}))
+	defer dydxServer.Close()
+
+	providers := map[string]TradeHistoryProvider{
+		"gate": &GateTrader{apiKey: "k", secretKey: "s", baseURL: gateServer.URL, httpClient: gateServer.Client(), marginModes: make(map[string]bool)},
+		"dydx": &DydxTrader{indexerURL: dydxServer.URL + "/v4", client: dydxServer.Client(), marketsCache: make(map[string]*DydxMarket)},
+	}
+	for name, provider := range providers {
+		t.Run(name, func(t *testing.T) {
+			trades, err := fetchTradeHistory(provider, start)
+			require.NoError(t, err)
+			require.Len(t, trades, total)
+			for i, trade := range trades {
+				require.Equal(t, strconv.Itoa(i), trade.TradeID)
+			}
+		})
+	}
+}
@@ -0,0 +1,316 @@
+package trader
+
+import (
+	"context"
+	"fmt"
+	"nofx/logger"
+	"sync"
+	"time"
+)
+
+const (
+	// accountStreamReconcileInterval polling interval for traders with a live account stream
+	// (safety net for events missed by the stream)
+	accountStreamReconcileInterval = 5 * time.Minute
+	accountStreamMinBackoff        = 5 * time.Second
+	accountStreamMaxBackoff        = 2 * time.Minute
+)
+
+// accountStreamState account stream state of a single trader
+type accountStreamState struct {
+	cancel        context.CancelFunc
+	connected     bool
+	connectedAt   time.Time
+	lastPoll      time.Time                // Last REST reconcile while connected
+	pendingCloses map[string]*pendingClose // symbol_side -> closing fills not yet matched with a flat position
+}
+
+// pendingClose aggregated closing fills of a position
+type pendingClose struct {
+	qty         float64
+	notional    float64
+	pnl         float64
+	fee         float64
+	orderID     string
+	closeReason string
+	time        time.Time
+}
+
+// streamOwners position sync managers running account streams, so a trader that stops can release its stream
+var streamOwners = struct {
+	sync.Mutex
+	managers map[*PositionSyncManager]bool
+}{managers: make(map[*PositionSyncManager]bool)}
+
+// registerStreamOwner registers (or unregisters) a running position sync manager
+func registerStreamOwner(m *PositionSyncManager, running bool) {
+	streamOwners.Lock()
+	defer streamOwners.Unlock()
+	if running {
+		streamOwners.managers[m] = true
+	} else {
+		delete(streamOwners.managers, m)
+	}
+}
+
+// ReleaseAccountStream stops the account stream of a trader that stopped or was removed from memory,
+// closing its websocket (and listenKey keepalive). A trader that still has open positions gets a new
+// stream on the next position sync.
+func ReleaseAccountStream(traderID string) {
+	streamOwners.Lock()
+	managers := make([]*PositionSyncManager, 0, len(streamOwners.managers))
+	for m := range streamOwners.managers {
+		managers = append(managers, m)
+	}
+	streamOwners.Unlock()
+
+	for _, m := range managers {
+		m.stopAccountStream(traderID)
+	}
+}
+
+// ensureAccountStreams keeps account streams running for exactly the given traders
+// Streams are started when the exchange supports it, streams of other traders are stopped
+func (m *PositionSyncManager) ensureAccountStreams(traderIDs map[string]bool) {
+	m.streamsMutex.Lock()
+	var idle []string
+	for traderID := range m.streams {
+		if !traderIDs[traderID] {
+			idle = append(idle, traderID)
+		}
+	}
+	m.streamsMutex.Unlock()
+	for _, traderID := range idle {
+		m.stopAccountStream(traderID)
+	}
+
+	for traderID := range traderIDs {
+		m.streamsMutex.Lock()
+		_, running := m.streams[traderID]
+		m.streamsMutex.Unlock()
+		if running {
+			continue
+		}
+
+		trader, err := m.getOrCreateTrader(traderID)
+		if err != nil {
+			continue
+		}
+		stream, ok := trader.(AccountStream)
+		if !ok {
+			continue // Exchange has no user-data stream, polling only
+		}
+
+		ctx, cancel := context.WithCancel(m.streamCtx)
+		m.streamsMutex.Lock()
+		m.streams[traderID] = &accountStreamState{
+			cancel:        cancel,
+			pendingCloses: make(map[string]*pendingClose),
+		}
+		m.streamsMutex.Unlock()
+
+		m.wg.Add(1)
+		go m.runAccountStream(ctx, traderID, stream)
+	}
+}
+
+// runAccountStream keeps the account stream of a trader connected, reconnecting with backoff
+// While disconnected the trader is covered by regular polling
+func (m *PositionSyncManager) runAccountStream(ctx context.Context, traderID string, stream AccountStream) {
+	defer m.wg.Done()
+
+	backoff := accountStreamMinBackoff
+	for {
+		err := stream.StreamAccount(ctx, func(ev AccountEvent) {
+			if ev.Type == AccountEventConnected {
+				m.setStreamConnected(traderID, true)
+				logger.Infof("📡 Account stream connected [%s], real-time position updates enabled", traderID[:8])
+				return
+			}
+			m.handleAccountEvent(traderID, ev)
+		})
+
+		connectedFor := m.setStreamConnected(traderID, false)
+		if ctx.Err() != nil {
+			return
+		}
+		logger.Infof("⚠️  Account stream dropped [%s]: %v, falling back to polling", traderID[:8], err)
+
+		// Reset backoff after a stable connection
+		if connectedFor > time.Minute {
+			backoff = accountStreamMinBackoff
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(backoff):
+		}
+
+		backoff *= 2
+		if backoff > accountStreamMaxBackoff {
+			backoff = accountStreamMaxBackoff
+		}
+	}
+}
+
+// setStreamConnected updates connection state and returns how long the previous connection lasted
+func (m *PositionSyncManager) setStreamConnected(traderID string, connected bool) time.Duration {
+	fillHub.setActive(traderID, connected)
+
+	m.streamsMutex.Lock()
+	defer m.streamsMutex.Unlock()
+
+	state, ok := m.streams[traderID]
+	if !ok {
+		return 0
+	}
+
+	var connectedFor time.Duration
+	if state.connected {
+		connectedFor = time.Since(state.connectedAt)
+	}
+	state.connected = connected
+	if connected {
+		state.connectedAt = time.Now()
+	}
+	// Reconcile right away: changes may have been missed around (re)connect
+	state.lastPoll = time.Time{}
+	return connectedFor
+}
+
+// shouldPoll reports whether exchange positions of a trader need to be polled now
+// Traders with a live account stream are only reconciled every accountStreamReconcileInterval
+func (m *PositionSyncManager) shouldPoll(traderID string) bool {
+	m.streamsMutex.Lock()
+	defer m.streamsMutex.Unlock()
+
+	state, ok := m.streams[traderID]
+	if !ok || !state.connected {
+		return true
+	}
+	if time.Since(state.lastPoll) < accountStreamReconcileInterval {
+		return false
+	}
+	state.lastPoll = time.Now()
+	return true
+}
+
+// stopAccountStream stops the account stream of a trader (e.g. after its config changed)
+func (m *PositionSyncManager) stopAccountStream(traderID string) {
+	m.streamsMutex.Lock()
+	state, ok := m.streams[traderID]
+	delete(m.streams, traderID)
+	m.streamsMutex.Unlock()
+
+	if ok {
+		state.cancel()
+	}
+	fillHub.setActive(traderID, false)
+}
+
+// handleAccountEvent applies an account stream event to PositionStore
+// Only closes are acted on: opening/increasing fills reach order confirmation through the fill hub,
+// which records the position, so other position and balance updates are skipped.
+func (m *PositionSyncManager) handleAccountEvent(traderID string, ev AccountEvent) {
+	switch ev.Type {
+	case AccountEventOrderFill:
+		fillHub.publish(traderID, ev)
+		if ev.ReduceOnly {
+			m.addPendingClose(traderID, ev)
+		}
+
+	case AccountEventPosition:
+		if ev.PositionAmt > 0.0000001 {
+			logger.Debugf("📡 Account stream [%s] %s %s size %.6f @ %.4f skipped (opens are recorded by the order)",
+				traderID[:8], ev.Symbol, ev.Side, ev.PositionAmt, ev.EntryPrice)
+			return
+		}
+		sides := []string{ev.Side}
+		if ev.Side == "" {
+			sides = []string{"LONG", "SHORT"} // One-way mode flat update doesn't carry a side
+		}
+		for _, side := range sides {
+			m.handlePositionClosed(traderID, ev.Symbol, side)
+		}
+
+	default:
+		logger.Debugf("📡 Account stream [%s] %s event skipped", traderID[:8], ev.Type)
+	}
+}
+
+// addPendingClose accumulates a closing fill until the position is reported flat
+func (m *PositionSyncManager) addPendingClose(traderID string, ev AccountEvent) {
+	m.streamsMutex.Lock()
+	defer m.streamsMutex.Unlock()
+
+	state, ok := m.streams[traderID]
+	if !ok {
+		return
+	}
+
+	key := fmt.Sprintf("%s_%s", ev.Symbol, ev.Side)
+	pc, ok := state.pendingCloses[key]
+	if !ok {
+		pc = &pendingClose{}
+		state.pendingCloses[key] = pc
+	}
+	pc.qty += ev.Quantity
+	pc.notional += ev.Price * ev.Quantity
+	pc.pnl += ev.RealizedPnL
+	pc.fee += ev.Fee
+	pc.orderID = ev.OrderID
+	pc.time = ev.Time
+	if ev.CloseReason != "" {
+		pc.closeReason = ev.CloseReason
+	}
+}
+
+// handlePositionClosed closes the local position when the exchange reports it flat
+// Stop loss / take profit / liquidation closes are recorded immediately with fill data;
+// other closes (AI orders, manual) are left to the next reconcile so that
+// recordAndConfirmOrder and the manual-close detection keep their close reasons.
+func (m *PositionSyncManager) handlePositionClosed(traderID, symbol, side string) {
+	key := fmt.Sprintf("%s_%s", symbol, side)
+
+	m.streamsMutex.Lock()
+	state, ok := m.streams[traderID]
+	var pc *pendingClose
+	if ok {
+		pc = state.pendingCloses[key]
+		delete(state.pendingCloses, key)
+	}
+	m.streamsMutex.Unlock()
+
+	localPos, err := m.store.Position().GetOpenPositionBySymbol(traderID, symbol, side)
+	if err != nil || localPos == nil {
+		return
+	}
+
+	if pc == nil || pc.closeReason == "" || pc.qty <= 0 {
+		m.streamsMutex.Lock()
+		if state, ok := m.streams[traderID]; ok {
+			state.lastPoll = time.Time{}
+		}
+		m.streamsMutex.Unlock()
+		return
+	}
+
+	exitPrice := pc.notional / pc.qty
+	err = m.store.Position().ClosePositionWithAccurateData(
+		localPos.ID,
+		exitPrice,
+		pc.orderID,
+		pc.time,
+		pc.pnl,
+		pc.fee,
+		pc.closeReason,
+	)
+	if err != nil {
+		logger.Infof("⚠️  Failed to update position status: %v", err)
+		return
+	}
+
+	logger.Infof("⚡ Position closed via account stream [%s] %s %s @ %.4f → %.4f, PnL: %.2f, Fee: %.4f (%s)",
+		traderID[:8], symbol, side, localPos.EntryPrice, exitPrice, pc.pnl, pc.fee, pc.closeReason)
+}
@@ -1,14 +1,32 @@
 package trader
 
 import (
+	"context"
 	"fmt"
 	"nofx/logger"
 	"nofx/store"
+	"sort"
 	"strings"
 	"sync"
 	"time"
 )
 
+const (
+	// tradeReplayLookback fills replayed before the last synced close, so positions opened earlier get their entry
+	tradeReplayLookback = 7 * 24 * time.Hour
+	// positionTradesLookback max age of fills fetched when a local position is closed on the exchange
+	positionTradesLookback = 7 * 24 * time.Hour
+	// trackedCloseWindow closes within this window of a local close of the same symbol and side are already tracked
+	trackedCloseWindow = 2 * time.Minute
+	// journalRelinkWindow journal entries re-link to a rebuilt position of the same symbol and side opened within this window
+	journalRelinkWindow = 2 * time.Minute
+)
+
+// symbolTradeHistoryProvider is implemented by exchanges that can query fills of a single symbol
+type symbolTradeHistoryProvider interface {
+	GetTradesForSymbol(symbol string, startTime time.Time, limit int) ([]TradeRecord, error)
+}
+
 // PositionSyncManager Position status synchronization manager
 // Responsible for periodically synchronizing exchange positions, detecting manual closures and other changes
 type PositionSyncManager struct {
@@ -22,6 +40,12 @@ type PositionSyncManager struct {
 	cacheMutex           sync.RWMutex
 	lastHistorySync      map[string]time.Time // trader_id -> last history sync time
 	lastHistorySyncMutex sync.RWMutex
+
+	// Account streams (exchange user-data websockets), polling is the fallback
+	streamCtx    context.Context
+	streamCancel context.CancelFunc
+	streams      map[string]*accountStreamState // trader_id -> account stream state
+	streamsMutex sync.Mutex
 }
 
 // NewPositionSyncManager Create position synchronization manager
@@ -29,6 +53,7 @@ func NewPositionSyncManager(st *store.Store, interval time.Duration) *PositionSy
 	if interval == 0 {
 		interval = 10 * time.Second
 	}
+	streamCtx, streamCancel := context.WithCancel(context.Background())
 	return &PositionSyncManager{
 		store:               st,
 		interval:            interval,
@@ -37,11 +62,15 @@ func NewPositionSyncManager(st *store.Store, interval time.Duration) *PositionSy
 		traderCache:         make(map[string]Trader),
 		configCache:         make(map[string]*store.TraderFullConfig),
 		lastHistorySync:     make(map[string]time.Time),
+		streamCtx:           streamCtx,
+		streamCancel:        streamCancel,
+		streams:             make(map[string]*accountStreamState),
 	}
 }
 
 // Start Start position synchronization service
 func (m *PositionSyncManager) Start() {
+	registerStreamOwner(m, true)
 	m.wg.Add(1)
 	go m.run()
 	logger.Info("📊 Position sync manager started")
@@ -52,7 +81,9 @@ func (m *PositionSyncManager) Start() {
 
 // Stop Stop position synchronization service
 func (m *PositionSyncManager) Stop() {
+	registerStreamOwner(m, false)
 	close(m.stopCh)
+	m.streamCancel()
 	m.wg.Wait()
 
 	// Clear cache
@@ -93,14 +124,26 @@ func (m *PositionSyncManager) syncPositions() {
 		return
 	}
 
-	if len(localPositions) == 0 {
-		return
-	}
-
 	// Group by trader_id
 	positionsByTrader := make(map[string][]*store.TraderPosition)
+	streamTraders := make(map[string]bool)
 	for _, pos := range localPositions {
 		positionsByTrader[pos.TraderID] = append(positionsByTrader[pos.TraderID], pos)
+		streamTraders[pos.TraderID] = true
+	}
+
+	// Keep account streams up for traders with open positions and running traders
+	if traders, err := m.store.Trader().ListAll(); err == nil {
+		for _, t := range traders {
+			if t.IsRunning {
+				streamTraders[t.ID] = true
+			}
+		}
+	}
+	m.ensureAccountStreams(streamTraders)
+
+	if len(localPositions) == 0 {
+		return
 	}
 
 	// Process each trader
@@ -132,6 +175,11 @@ func (m *PositionSyncManager) syncTraderPositions(traderID string, localPosition
 		m.maybeRunHistorySync(traderID, exchangeID, exchangeType, trader)
 	}
 
+	// Live account stream reports closes in real time, only reconcile occasionally
+	if !m.shouldPoll(traderID) {
+		return
+	}
+
 	// Get current exchange positions
 	exchangePositions, err := trader.GetPositions()
 	if err != nil {
@@ -232,14 +280,21 @@ func (m *PositionSyncManager) closeLocalPosition(pos *store.TraderPosition, trad
 }
 
 // findClosedPnLRecord Try to find matching ClosedPnL record from exchange
-// For Binance, directly query trades for the specific symbol (more reliable than Income API)
+// Fills since the position was opened are rebuilt with the unified algorithm; venues without fill history use GetClosedPnL
 func (m *PositionSyncManager) findClosedPnLRecord(trader Trader, pos *store.TraderPosition) *ClosedPnLRecord {
-	// Try to get trades directly for this symbol (Binance-specific, more reliable)
-	if binanceTrader, ok := trader.(*FuturesTrader); ok {
-		return m.findClosedPnLFromBinanceTrades(binanceTrader, pos)
+	if trades, ok := m.getPositionTrades(trader, pos); ok {
+		var closes []ClosedPnLRecord
+		for _, rec := range RebuildPositionsFromTrades(trades) {
+			if !rec.ExitTime.Before(pos.EntryTime) {
+				closes = append(closes, rec)
+			}
+		}
+		if record := m.aggregateClosedRecords(closes, pos); record != nil {
+			return record
+		}
 	}
 
-	// Fallback: use GetClosedPnL for other exchanges
+	// Fallback: use GetClosedPnL
 	startTime := time.Now().Add(-24 * time.Hour)
 	records, err := trader.GetClosedPnL(startTime, 100)
 	if err != nil {
@@ -250,97 +305,35 @@ func (m *PositionSyncManager) findClosedPnLRecord(trader Trader, pos *store.Trad
 	return m.aggregateClosedRecords(records, pos)
 }
 
-// findClosedPnLFromBinanceTrades queries Binance directly for trades of a specific symbol
-func (m *PositionSyncManager) findClosedPnLFromBinanceTrades(trader *FuturesTrader, pos *store.TraderPosition) *ClosedPnLRecord {
-	// Query trades for this specific symbol from the last hour
-	startTime := time.Now().Add(-1 * time.Hour)
-	trades, err := trader.GetTradesForSymbol(pos.Symbol, startTime, 100)
-	if err != nil {
-		logger.Infof("⚠️  Failed to get trades for %s: %v", pos.Symbol, err)
-		return nil
+// getPositionTrades fetches the fills of a position's symbol since it was opened
+// Per-symbol queries are preferred where available (Binance), they don't depend on the delayed Income API
+func (m *PositionSyncManager) getPositionTrades(trader Trader, pos *store.TraderPosition) ([]TradeRecord, bool) {
+	startTime := pos.EntryTime.Add(-1 * time.Minute)
+	if earliest := time.Now().Add(-positionTradesLookback); startTime.Before(earliest) {
+		startTime = earliest
 	}
 
-	if len(trades) == 0 {
-		logger.Infof("⚠️  No trades found for %s in the last hour", pos.Symbol)
-		return nil
+	var trades []TradeRecord
+	var err error
+	if provider, ok := trader.(symbolTradeHistoryProvider); ok {
+		trades, err = provider.GetTradesForSymbol(pos.Symbol, startTime, 1000)
+	} else if provider, ok := trader.(TradeHistoryProvider); ok {
+		trades, err = fetchTradeHistory(provider, startTime)
+	} else {
+		return nil, false
+	}
+	if err != nil {
+		logger.Infof("⚠️  Failed to get trades for %s: %v", pos.Symbol, err)
+		return nil, false
 	}
 
-	// Find all closing trades (realizedPnl != 0) that match this position
-	var totalQty, totalPnL, totalFee float64
-	var weightedExitPrice float64
-	var latestExitTime time.Time
-	var latestTradeID string
-	matchCount := 0
-
-	posSide := strings.ToLower(pos.Side)
-
+	symbolTrades := make([]TradeRecord, 0, len(trades))
 	for _, trade := range trades {
-		// Skip opening trades
-		if trade.RealizedPnL == 0 {
-			continue
-		}
-
-		// Determine if this trade closes our position
-		// For LONG position: SELL closes it
-		// For SHORT position: BUY closes it
-		isClosingTrade := false
-		tradeSide := strings.ToUpper(trade.Side)
-		positionSide := strings.ToUpper(trade.PositionSide)
-
-		if positionSide == "LONG" && posSide == "long" {
-			isClosingTrade = true
-		} else if positionSide == "SHORT" && posSide == "short" {
-			isClosingTrade = true
-		} else if positionSide == "BOTH" || positionSide == "" {
-			// One-way mode
-			if tradeSide == "SELL" && posSide == "long" {
-				isClosingTrade = true
-			} else if tradeSide == "BUY" && posSide == "short" {
-				isClosingTrade = true
-			}
-		}
-
-		if !isClosingTrade {
-			continue
+		if trade.Symbol == pos.Symbol {
+			symbolTrades = append(symbolTrades, trade)
 		}
-
-		// Aggregate this trade
-		totalQty += trade.Quantity
-		totalPnL += trade.RealizedPnL
-		totalFee += trade.Fee
-		weightedExitPrice += trade.Price * trade.Quantity
-		matchCount++
-
-		if trade.Time.After(latestExitTime) {
-			latestExitTime = trade.Time
-			latestTradeID = trade.TradeID
-		}
-	}
-
-	if matchCount == 0 {
-		logger.Infof("⚠️  No closing trades found for %s %s", pos.Symbol, pos.Side)
-		return nil
-	}
-
-	avgExitPrice := weightedExitPrice / totalQty
-
-	logger.Infof("📊 Found %d closing trades for %s %s: qty=%.4f, exitPrice=%.6f, pnl=%.4f, fee=%.4f",
-		matchCount, pos.Symbol, pos.Side, totalQty, avgExitPrice, totalPnL, totalFee)
-
-	return &ClosedPnLRecord{
-		Symbol:      pos.Symbol,
-		Side:        posSide,
-		EntryPrice:  pos.EntryPrice,
-		ExitPrice:   avgExitPrice,
-		Quantity:    totalQty,
-		RealizedPnL: totalPnL,
-		Fee:         totalFee,
-		ExitTime:    latestExitTime,
-		EntryTime:   pos.EntryTime,
-		OrderID:     latestTradeID,
-		ExchangeID:  latestTradeID,
-		CloseType:   "unknown",
 	}
+	return symbolTrades, true
 }
 
 // aggregateClosedRecords aggregates closed PnL records for a position
@@ -433,7 +426,7 @@ func (m *PositionSyncManager) getOrCreateTrader(traderID string) (Trader, error)
 		return nil, fmt.Errorf("failed to get trader config: %w", err)
 	}
 
-	trader, err = m.createTrader(config)
+	trader, err = NewTraderFromExchange(config.Exchange, config.Trader.UserID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create trader instance: %w", err)
 	}
@@ -485,53 +478,15 @@ func (m *PositionSyncManager) getTraderConfig(traderID string) (*store.TraderFul
 	return config, nil
 }
 
-// createTrader Create trader instance based on configuration
-func (m *PositionSyncManager) createTrader(config *store.TraderFullConfig) (Trader, error) {
-	exchange := config.Exchange
-
-	// Use exchange.ExchangeType to determine specific exchange, not exchange.ID (UUID) or exchange.Type (cex/dex)
-	switch exchange.ExchangeType {
-	case "binance":
-		return NewFuturesTrader(exchange.APIKey, exchange.SecretKey, config.Trader.UserID), nil
-
-	case "bybit":
-		return NewBybitTrader(exchange.APIKey, exchange.SecretKey), nil
-
-	case "okx":
-		return NewOKXTrader(exchange.APIKey, exchange.SecretKey, exchange.Passphrase), nil
-
-	case "bitget":
-		return NewBitgetTrader(exchange.APIKey, exchange.SecretKey, exchange.Passphrase), nil
-
-	case "hyperliquid":
-		return NewHyperliquidTrader(exchange.SecretKey, exchange.HyperliquidWalletAddr, exchange.Testnet)
-
-	case "aster":
-		return NewAsterTrader(exchange.AsterUser, exchange.AsterSigner, exchange.AsterPrivateKey)
-
-	case "lighter":
-		if exchange.LighterAPIKeyPrivateKey != "" {
-			return NewLighterTraderV2(
-				exchange.LighterPrivateKey,
-				exchange.LighterWalletAddr,
-				exchange.LighterAPIKeyPrivateKey,
-				exchange.Testnet,
-			)
-		}
-		return NewLighterTrader(exchange.LighterPrivateKey, exchange.LighterWalletAddr, exchange.Testnet)
-
-	default:
-		return nil, fmt.Errorf("unsupported exchange type: %s", exchange.ExchangeType)
-	}
-}
-
 // InvalidateCache Invalidate cache
 func (m *PositionSyncManager) InvalidateCache(traderID string) {
 	m.cacheMutex.Lock()
-	defer m.cacheMutex.Unlock()
-
 	delete(m.traderCache, traderID)
 	delete(m.configCache, traderID)
+	m.cacheMutex.Unlock()
+
+	// Stream uses the old trader instance, it is restarted on next sync
+	m.stopAccountStream(traderID)
 }
 
 // getFloatFromMap Get float64 value from map
@@ -594,6 +549,9 @@ func (m *PositionSyncManager) startupSync() {
 
 		// 2. Sync closed positions history from exchange
 		m.syncClosedPositionsHistory(traderID, exchangeID, exchangeType, trader)
+
+		// 3. Sync funding payments and attribute them to positions
+		m.syncFundingHistory(traderID, trader)
 	}
 
 	logger.Info("📊 Startup sync completed")
@@ -696,104 +654,204 @@ func (m *PositionSyncManager) syncExternalPositions(traderID, exchangeID, exchan
 	}
 }
 
-// syncClosedPositionsHistory syncs closed positions from exchange history
-// IMPORTANT: Only exchanges with position-level history API should sync history:
-// - Bybit: /v5/position/closed-pnl (accurate position records)
-// - OKX: /api/v5/account/positions-history (accurate position records)
-// Other exchanges (Binance, Hyperliquid, Lighter, Aster) only have trade-level data,
-// which cannot accurately reconstruct positions. They should NOT sync historical positions.
+// syncClosedPositionsHistory syncs closed positions from exchange fill history
+// Every venue goes through the same path: GetTrades → RebuildPositionsFromTrades → store
 func (m *PositionSyncManager) syncClosedPositionsHistory(traderID, exchangeID, exchangeType string, trader Trader) {
-	// Only sync history for exchanges with position-level API
-	// Binance/Hyperliquid/Lighter/Aster only have trade-level data, skip history sync
-	switch exchangeType {
-	case "bybit", "okx":
-		// These exchanges have position-level history API, proceed with sync
-	default:
-		// Other exchanges don't have accurate position history API
-		// Their GetClosedPnL only returns recent trades for closure detection, not for history sync
-		return
+	provider, ok := trader.(TradeHistoryProvider)
+	if !ok {
+		return // Exchange doesn't expose fill history
 	}
 
 	// Get last sync time from database
 	lastSyncTime, err := m.store.Position().GetLastClosedPositionTime(traderID)
 	if err != nil {
 		logger.Infof("⚠️  Failed to get last closed position time (ID: %s): %v", traderID, err)
-		// First sync: go back 90 days to get more history
-		lastSyncTime = time.Now().Add(-90 * 24 * time.Hour)
+		lastSyncTime = time.Now().Add(-30 * 24 * time.Hour)
+	}
+
+	// Replay fills from before the last close so later closes find their opening trades,
+	// subtract a small buffer to avoid missing positions at the boundary
+	created, skipped, err := m.rebuildClosedPositions(traderID, exchangeID, exchangeType, provider,
+		lastSyncTime.Add(-tradeReplayLookback), lastSyncTime.Add(-1*time.Minute))
+	if err != nil {
+		logger.Infof("⚠️  Failed to sync closed positions (ID: %s): %v", traderID, err)
+	} else if created > 0 {
+		logger.Infof("📊 Synced %d new closed positions for trader %s (skipped %d duplicates)",
+			created, traderID[:8], skipped)
 	}
 
-	// Subtract a small buffer to avoid missing positions at the boundary
-	startTime := lastSyncTime.Add(-1 * time.Minute)
+	// Update last history sync time
+	m.lastHistorySyncMutex.Lock()
+	m.lastHistorySync[traderID] = time.Now()
+	m.lastHistorySyncMutex.Unlock()
+}
 
-	// Pagination loop to get all records
-	const batchSize = 500
-	totalCreated := 0
-	totalSkipped := 0
+// HistoryRebuildResult summarizes a position history rebuild
+type HistoryRebuildResult struct {
+	Removed int `json:"removed"` // Previously synced closed positions replaced
+	Created int `json:"created"` // Closed positions rebuilt from fills
+	Skipped int `json:"skipped"` // Closes already tracked locally
+}
 
-	for {
-		// Get closed positions from exchange
-		closedRecords, err := trader.GetClosedPnL(startTime, batchSize)
+// RebuildHistory rebuilds a trader's closed position history from exchange fills since the given date
+// Closed positions previously synced from the exchange in that range are replaced, positions tracked by the trader are kept
+func (m *PositionSyncManager) RebuildHistory(traderID string, since time.Time) (*HistoryRebuildResult, error) {
+	config, err := m.getTraderConfig(traderID)
+	if err != nil {
+		return nil, err
+	}
+	trader, err := m.getOrCreateTrader(traderID)
+	if err != nil {
+		return nil, err
+	}
+	provider, ok := trader.(TradeHistoryProvider)
+	if !ok {
+		return nil, fmt.Errorf("exchange %s does not provide trade history", config.Exchange.ExchangeType)
+	}
+
+	removed, err := m.store.Position().DeleteSyncedClosedSince(traderID, since)
+	if err != nil {
+		return nil, err
+	}
+
+	created, skipped, err := m.rebuildClosedPositions(traderID, config.Exchange.ID, config.Exchange.ExchangeType, provider, since, since)
+	if err != nil {
+		return nil, err
+	}
+
+	// Journal entries of replaced positions keep their edits and deletions
+	if relinked, err := m.store.Journal().RelinkDetached(traderID, journalRelinkWindow); err != nil {
+		logger.Infof("⚠️  Failed to re-link journal entries (ID: %s): %v", traderID, err)
+	} else if relinked > 0 {
+		logger.Infof("📓 Re-linked %d journal entries to rebuilt positions for trader %s", relinked, traderID[:8])
+	}
+
+	logger.Infof("📊 Rebuilt position history for trader %s since %s: %d created, %d replaced, %d already tracked",
+		traderID[:8], since.Format("2006-01-02"), created, removed, skipped)
+	return &HistoryRebuildResult{Removed: int(removed), Created: created, Skipped: skipped}, nil
+}
+
+// rebuildClosedPositions replays fills since startTime and stores the positions closed after closedAfter
+// Closes already tracked locally (closed by the trader or detected by position sync) are skipped
+func (m *PositionSyncManager) rebuildClosedPositions(traderID, exchangeID, exchangeType string, provider TradeHistoryProvider, startTime, closedAfter time.Time) (int, int, error) {
+	trades, err := fetchTradeHistory(provider, startTime)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	var storeRecords []store.ClosedPnLRecord
+	skipped := 0
+	for _, rec := range mergeCloseFills(RebuildPositionsFromTrades(trades)) {
+		if rec.ExitTime.Before(closedAfter) {
+			continue
+		}
+		tracked, err := m.store.Position().ExistsClosedNear(traderID, rec.Symbol, rec.Side, rec.ExitTime, trackedCloseWindow)
 		if err != nil {
-			logger.Infof("⚠️  Failed to get closed PnL records (ID: %s): %v", traderID, err)
-			break
+			return 0, skipped, err
+		}
+		if tracked {
+			skipped++
+			continue
 		}
+		storeRecords = append(storeRecords, store.ClosedPnLRecord{
+			Symbol:      rec.Symbol,
+			Side:        rec.Side,
+			EntryPrice:  rec.EntryPrice,
+			ExitPrice:   rec.ExitPrice,
+			Quantity:    rec.Quantity,
+			RealizedPnL: rec.RealizedPnL,
+			Fee:         rec.Fee,
+			Leverage:    rec.Leverage,
+			EntryTime:   rec.EntryTime,
+			ExitTime:    rec.ExitTime,
+			OrderID:     rec.OrderID,
+			CloseType:   rec.CloseType,
+			ExchangeID:  rec.ExchangeID,
+		})
+	}
+
+	created, duplicates, err := m.store.Position().SyncClosedPositions(traderID, exchangeID, exchangeType, storeRecords)
+	if err != nil {
+		return created, skipped + duplicates, err
+	}
 
-		if len(closedRecords) == 0 {
-			break
+	// Funding synced before these positions existed (or attributed to replaced ones) now has a position
+	if relinked, err := m.store.Funding().RelinkUnattributed(traderID); err != nil {
+		logger.Infof("⚠️  Failed to re-link funding payments (ID: %s): %v", traderID, err)
+	} else if relinked > 0 {
+		logger.Infof("💸 Re-linked %d funding payments to synced positions for trader %s", relinked, traderID[:8])
+	}
+	return created, skipped + duplicates, nil
+}
+
+// fetchTradeHistory pages through GetTrades until all fills since startTime are collected (oldest first)
+func fetchTradeHistory(provider TradeHistoryProvider, startTime time.Time) ([]TradeRecord, error) {
+	const pageSize = 1000
+
+	seen := make(map[string]bool)
+	var trades []TradeRecord
+	for {
+		page, err := provider.GetTrades(startTime, pageSize)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get trade history: %w", err)
 		}
 
-		// Convert to store.ClosedPnLRecord and sync
-		storeRecords := make([]store.ClosedPnLRecord, len(closedRecords))
-		var latestExitTime time.Time
-		for i, rec := range closedRecords {
-			storeRecords[i] = store.ClosedPnLRecord{
-				Symbol:      rec.Symbol,
-				Side:        rec.Side,
-				EntryPrice:  rec.EntryPrice,
-				ExitPrice:   rec.ExitPrice,
-				Quantity:    rec.Quantity,
-				RealizedPnL: rec.RealizedPnL,
-				Fee:         rec.Fee,
-				Leverage:    rec.Leverage,
-				EntryTime:   rec.EntryTime,
-				ExitTime:    rec.ExitTime,
-				OrderID:     rec.OrderID,
-				CloseType:   rec.CloseType,
-				ExchangeID:  rec.ExchangeID,
+		added := 0
+		latest := startTime
+		for _, trade := range page {
+			key := trade.Symbol + "_" + trade.TradeID
+			if trade.TradeID == "" {
+				key = fmt.Sprintf("%s_%s_%d_%f", trade.Symbol, trade.Side, trade.Time.UnixMilli(), trade.Quantity)
+			}
+			if trade.Time.After(latest) {
+				latest = trade.Time
 			}
-			// Track latest exit time for pagination
-			if rec.ExitTime.After(latestExitTime) {
-				latestExitTime = rec.ExitTime
+			if seen[key] {
+				continue
 			}
+			seen[key] = true
+			trades = append(trades, trade)
+			added++
 		}
 
-		created, skipped, err := m.store.Position().SyncClosedPositions(traderID, exchangeID, exchangeType, storeRecords)
-		if err != nil {
-			logger.Infof("⚠️  Failed to sync closed positions (ID: %s): %v", traderID, err)
+		// Next page starts at the latest fill, fills sharing its timestamp are deduped above
+		if len(page) < pageSize || added == 0 {
 			break
 		}
-
-		totalCreated += created
-		totalSkipped += skipped
-
-		// If we got fewer records than batch size, we've reached the end
-		if len(closedRecords) < batchSize {
-			break
+		if !latest.After(startTime) {
+			latest = startTime.Add(time.Millisecond)
 		}
-
-		// Move start time forward for next batch (add 1ms to avoid duplicate)
-		startTime = latestExitTime.Add(time.Millisecond)
+		startTime = latest
 	}
 
-	if totalCreated > 0 {
-		logger.Infof("📊 Synced %d new closed positions for trader %s (skipped %d duplicates)",
-			totalCreated, traderID[:8], totalSkipped)
-	}
+	sort.SliceStable(trades, func(i, j int) bool { return trades[i].Time.Before(trades[j].Time) })
+	return trades, nil
+}
 
-	// Update last history sync time
-	m.lastHistorySyncMutex.Lock()
-	m.lastHistorySync[traderID] = time.Now()
-	m.lastHistorySyncMutex.Unlock()
+// mergeCloseFills merges closing fills of the same symbol and side executed together into one closed position
+func mergeCloseFills(records []ClosedPnLRecord) []ClosedPnLRecord {
+	var merged []ClosedPnLRecord
+	last := make(map[string]int) // symbol_side -> index in merged
+	for _, rec := range records {
+		key := fmt.Sprintf("%s_%s", rec.Symbol, rec.Side)
+		if i, ok := last[key]; ok && rec.ExitTime.Sub(merged[i].ExitTime) <= time.Second {
+			group := &merged[i]
+			totalQty := group.Quantity + rec.Quantity
+			group.EntryPrice = (group.EntryPrice*group.Quantity + rec.EntryPrice*rec.Quantity) / totalQty
+			group.ExitPrice = (group.ExitPrice*group.Quantity + rec.ExitPrice*rec.Quantity) / totalQty
+			group.Quantity = totalQty
+			group.RealizedPnL += rec.RealizedPnL
+			group.Fee += rec.Fee
+			if rec.EntryTime.Before(group.EntryTime) {
+				group.EntryTime = rec.EntryTime
+			}
+			group.ExitTime = rec.ExitTime
+			continue
+		}
+		merged = append(merged, rec)
+		last[key] = len(merged) - 1
+	}
+	return merged
 }
 
 // maybeRunHistorySync checks if it's time to run history sync for a trader
@@ -804,5 +862,58 @@ func (m *PositionSyncManager) maybeRunHistorySync(traderID, exchangeID, exchange
 
 	if !exists || time.Since(lastSync) >= m.historySyncInterval {
 		m.syncClosedPositionsHistory(traderID, exchangeID, exchangeType, trader)
+		m.syncFundingHistory(traderID, trader)
+
+		m.lastHistorySyncMutex.Lock()
+		m.lastHistorySync[traderID] = time.Now()
+		m.lastHistorySyncMutex.Unlock()
+	}
+}
+
+// syncFundingHistory fetches funding payments since the last stored one and attributes them to positions
+func (m *PositionSyncManager) syncFundingHistory(traderID string, trader Trader) {
+	provider, ok := trader.(FundingHistoryProvider)
+	if !ok {
+		return // Exchange doesn't expose funding history
+	}
+
+	lastFundingTime, err := m.store.Funding().GetLastFundingTime(traderID)
+	if err != nil {
+		logger.Infof("⚠️  Failed to get last funding time (ID: %s): %v", traderID, err)
+		return
+	}
+	startTime := lastFundingTime.Add(time.Millisecond)
+	if lastFundingTime.IsZero() {
+		// First sync: go back 30 days
+		startTime = time.Now().Add(-30 * 24 * time.Hour)
+	}
+
+	records, err := provider.GetFundingHistory(startTime)
+	if err != nil {
+		logger.Infof("⚠️  Failed to get funding history (ID: %s): %v", traderID, err)
+		return
+	}
+	if len(records) == 0 {
+		return
+	}
+
+	payments := make([]store.FundingPayment, len(records))
+	for i, rec := range records {
+		payments[i] = store.FundingPayment{
+			Symbol:       rec.Symbol,
+			Amount:       rec.Amount,
+			Rate:         rec.Rate,
+			FundingTime:  rec.Time,
+			ExchangeTxID: rec.TxID,
+		}
+	}
+
+	created, err := m.store.Funding().Record(traderID, payments)
+	if err != nil {
+		logger.Infof("⚠️  Failed to save funding payments (ID: %s): %v", traderID, err)
+		return
+	}
+	if created > 0 {
+		logger.Infof("💸 Synced %d funding payments for trader %s", created, traderID[:8])
 	}
 }
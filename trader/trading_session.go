@@ -0,0 +1,186 @@
+package trader
+
+import (
+	"fmt"
+	"nofx/decision"
+	"nofx/logger"
+	"nofx/store"
+	"strings"
+	"time"
+)
+
+// sessionState evaluates the strategy's trading session restrictions at the current time
+// Returns nil when no restrictions are configured
+func (at *AutoTrader) sessionState() *decision.SessionState {
+	if at.strategyEngine == nil {
+		return nil
+	}
+	return decision.EvaluateSession(at.strategyEngine.GetConfig().TradingSessions, time.Now())
+}
+
+// checkOpenAllowed returns an error when the kill switch or the trading session doesn't allow new positions
+func (at *AutoTrader) checkOpenAllowed() error {
+	if at.killed.Load() {
+		return fmt.Errorf("opening new positions is not allowed: kill switch engaged")
+	}
+	if state := at.sessionState(); state != nil && !state.OpensAllowed {
+		return fmt.Errorf("opening new positions is not allowed: %s", state.Reason)
+	}
+	return nil
+}
+
+// checkRegime applies the regime overrides of the last cycle to a new position
+// Returns an error when entries are blocked in the regime, caps leverage otherwise
+func (at *AutoTrader) checkRegime(d *decision.Decision) error {
+	if reason := at.cycleRegimes.EntryBlocked(d.Symbol); reason != "" {
+		return fmt.Errorf("opening new positions is not allowed: %s", reason)
+	}
+	if maxLeverage := at.cycleRegimes.MaxLeverage(d.Symbol); maxLeverage > 0 && d.Leverage > maxLeverage {
+		logger.Infof("  📐 %s leverage %dx capped to regime maximum %dx", d.Symbol, d.Leverage, maxLeverage)
+		d.Leverage = maxLeverage
+	}
+	return nil
+}
+
+// recordStop remembers the stop loss placed on a position (posKey = symbol_side)
+func (at *AutoTrader) recordStop(posKey string, stop float64) {
+	if at.positionStops == nil {
+		at.positionStops = make(map[string]float64)
+	}
+	at.positionStops[posKey] = stop
+}
+
+// applyBlackoutAction handles open positions during a blackout (flatten or tighten stops)
+// Closes are executed like AI decisions and appended to the record
+func (at *AutoTrader) applyBlackoutAction(state *decision.SessionState, positions []decision.PositionInfo, record *store.DecisionRecord) {
+	if state == nil || state.Blackout == nil {
+		return
+	}
+
+	// Forget the stops of closed positions (no position is opened during a blackout)
+	open := make(map[string]bool, len(positions))
+	for _, pos := range positions {
+		open[pos.Symbol+"_"+pos.Side] = true
+	}
+	for posKey := range at.positionStops {
+		if !open[posKey] {
+			delete(at.positionStops, posKey)
+		}
+	}
+
+	switch state.Action {
+	case store.BlackoutActionFlatten:
+		for _, pos := range positions {
+			d := decision.Decision{Symbol: pos.Symbol, Action: "close_" + pos.Side, Reasoning: "blackout: " + state.Blackout.Name}
+			actionRecord := store.DecisionAction{
+				Action:        d.Action,
+				Symbol:        d.Symbol,
+				Timestamp:     time.Now(),
+				ClientOrderID: GenerateClientOrderID(at.id, at.cycleNumber+1, d.Symbol, at.cycleAttempt("blackout_"+d.Action)),
+			}
+			logger.Infof("🚫 [%s] Blackout %q: closing %s %s", at.name, state.Blackout.Name, pos.Symbol, pos.Side)
+			if err := at.executeDecisionWithRecord(&d, &actionRecord); err != nil {
+				actionRecord.Error = err.Error()
+				record.ExecutionLog = append(record.ExecutionLog, fmt.Sprintf("❌ Blackout close %s %s failed: %v", pos.Symbol, pos.Side, err))
+			} else {
+				actionRecord.Success = true
+				record.ExecutionLog = append(record.ExecutionLog, fmt.Sprintf("🚫 Blackout close %s %s", pos.Symbol, pos.Side))
+			}
+			record.Decisions = append(record.Decisions, actionRecord)
+		}
+
+	case store.BlackoutActionTightenStops:
+		for _, pos := range positions {
+			if pos.MarkPrice <= 0 || pos.Quantity <= 0 {
+				continue
+			}
+			stop, tightened, err := at.tightenStop(state, pos)
+			if err != nil {
+				record.ExecutionLog = append(record.ExecutionLog, fmt.Sprintf("❌ Blackout stop %s %s failed: %v", pos.Symbol, pos.Side, err))
+				continue
+			}
+			if tightened {
+				logger.Infof("🚫 [%s] Blackout %q: %s %s stop tightened to %.4f", at.name, state.Blackout.Name, pos.Symbol, pos.Side, stop)
+				record.ExecutionLog = append(record.ExecutionLog, fmt.Sprintf("🚫 Blackout stop %s %s → %.4f", pos.Symbol, pos.Side, stop))
+			}
+		}
+	}
+}
+
+// tightenStop moves a position's stop loss to the blackout distance when that is closer to the price
+// The current stop is read from the exchange's open stop orders when the venue can list them; the old stop
+// is only cancelled once the new one is placed, so the position is never left without a stop
+func (at *AutoTrader) tightenStop(state *decision.SessionState, pos decision.PositionInfo) (float64, bool, error) {
+	posKey := pos.Symbol + "_" + pos.Side
+	positionSide := strings.ToUpper(pos.Side)
+
+	provider, ok := at.trader.(StopOrderProvider)
+	if !ok {
+		return at.tightenRecordedStop(state, pos)
+	}
+
+	orders, err := provider.GetStopLossOrders(pos.Symbol)
+	if err != nil {
+		return 0, false, fmt.Errorf("failed to read current stop: %w", err)
+	}
+	// The closest live stop protects the position; a new stop must beat it
+	var current float64
+	var old []StopOrder
+	for _, order := range orders {
+		if order.PositionSide != positionSide {
+			continue
+		}
+		old = append(old, order)
+		if current <= 0 || (pos.Side == "long" && order.StopPrice > current) || (pos.Side == "short" && order.StopPrice < current) {
+			current = order.StopPrice
+		}
+	}
+	stop, ok := state.TightenedStop(pos.Side, pos.MarkPrice, current)
+	if !ok {
+		if current > 0 {
+			at.recordStop(posKey, current)
+		}
+		return current, false, nil
+	}
+
+	if err := at.trader.SetStopLoss(pos.Symbol, positionSide, pos.Quantity, stop); err != nil {
+		return 0, false, err
+	}
+	at.recordStop(posKey, stop)
+	for _, order := range old {
+		if err := provider.CancelOrder(pos.Symbol, order.OrderID); err != nil {
+			logger.Infof("  ⚠ Failed to cancel replaced stop loss %s of %s: %v", order.OrderID, pos.Symbol, err)
+		}
+	}
+	return stop, true, nil
+}
+
+// tightenRecordedStop tightens stops on venues that can't list stop orders, from the stops placed by this process
+// When the current stop is unknown (placed before a restart or by hand) the new stop is added next to it rather than
+// replacing it, so a closer existing stop keeps working. A replaced stop is put back when placing the new one fails.
+func (at *AutoTrader) tightenRecordedStop(state *decision.SessionState, pos decision.PositionInfo) (float64, bool, error) {
+	posKey := pos.Symbol + "_" + pos.Side
+	positionSide := strings.ToUpper(pos.Side)
+	current, known := at.positionStops[posKey]
+	stop, ok := state.TightenedStop(pos.Side, pos.MarkPrice, current)
+	if !ok {
+		return current, false, nil
+	}
+
+	if known && current > 0 {
+		if err := at.trader.CancelStopLossOrders(pos.Symbol); err != nil {
+			logger.Infof("  ⚠ Failed to cancel stop loss of %s: %v", pos.Symbol, err)
+		}
+	}
+	if err := at.trader.SetStopLoss(pos.Symbol, positionSide, pos.Quantity, stop); err != nil {
+		if known && current > 0 {
+			if restoreErr := at.trader.SetStopLoss(pos.Symbol, positionSide, pos.Quantity, current); restoreErr != nil {
+				return 0, false, fmt.Errorf("%w (restoring stop %.4f also failed: %v)", err, current, restoreErr)
+			}
+			logger.Infof("  ↩ Restored %s %s stop loss at %.4f", pos.Symbol, pos.Side, current)
+		}
+		return 0, false, err
+	}
+	at.recordStop(posKey, stop)
+	return stop, true, nil
+}
@@ -0,0 +1,158 @@
+package trader
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"nofx/decision"
+	"nofx/store"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// sessionStubTrader records stop loss updates
+type sessionStubTrader struct {
+	Trader
+	stops map[string]float64
+}
+
+func (s *sessionStubTrader) CancelStopLossOrders(symbol string) error { return nil }
+
+func (s *sessionStubTrader) SetStopLoss(symbol string, positionSide string, quantity, stopPrice float64) error {
+	s.stops[symbol+"_"+positionSide] = stopPrice
+	return nil
+}
+
+// TestCheckOpenAllowed tests opens are rejected during a blackout
+func TestCheckOpenAllowed(t *testing.T) {
+	now := time.Now()
+	config := &store.StrategyConfig{TradingSessions: store.TradingSessionConfig{
+		Enabled:   true,
+		Blackouts: []store.BlackoutEvent{{Name: "FOMC", Start: now.Add(-time.Minute), End: now.Add(time.Hour)}},
+	}}
+	at := &AutoTrader{name: "test", strategyEngine: decision.NewStrategyEngine(config)}
+
+	err := at.executeDecisionWithRecord(&decision.Decision{Symbol: "BTCUSDT", Action: "open_long"}, &store.DecisionAction{})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "FOMC")
+
+	config.TradingSessions.Enabled = false
+	assert.NoError(t, at.checkOpenAllowed())
+}
+
+// TestApplyBlackoutAction_TightenStops tests stops only move towards the price
+func TestApplyBlackoutAction_TightenStops(t *testing.T) {
+	stub := &sessionStubTrader{stops: make(map[string]float64)}
+	at := &AutoTrader{name: "test", trader: stub}
+	state := &decision.SessionState{Blackout: &store.BlackoutEvent{Name: "CPI"}, Action: store.BlackoutActionTightenStops, TightenStopPct: 2}
+	record := &store.DecisionRecord{}
+
+	// The short was opened with a stop tighter than the blackout one
+	at.recordStop("ETHUSDT_short", 50.5)
+	at.applyBlackoutAction(state, []decision.PositionInfo{
+		{Symbol: "BTCUSDT", Side: "long", MarkPrice: 100, Quantity: 1},
+		{Symbol: "ETHUSDT", Side: "short", MarkPrice: 50, Quantity: 2},
+	}, record)
+	assert.InDelta(t, 98.0, stub.stops["BTCUSDT_LONG"], 1e-9)
+	assert.NotContains(t, stub.stops, "ETHUSDT_SHORT")
+
+	// Price fell: the long stop is not loosened
+	at.applyBlackoutAction(state, []decision.PositionInfo{{Symbol: "BTCUSDT", Side: "long", MarkPrice: 99, Quantity: 1}}, record)
+	assert.InDelta(t, 98.0, stub.stops["BTCUSDT_LONG"], 1e-9)
+
+	// Price rose: the long stop follows
+	at.applyBlackoutAction(state, []decision.PositionInfo{{Symbol: "BTCUSDT", Side: "long", MarkPrice: 110, Quantity: 1}}, record)
+	assert.InDelta(t, 107.8, stub.stops["BTCUSDT_LONG"], 1e-9)
+
+	// The closed short's stop is forgotten
+	assert.Len(t, at.positionStops, 1)
+	assert.InDelta(t, 107.8, at.positionStops["BTCUSDT_long"], 1e-9)
+}
+
+// stopOrderStubTrader lists live stop orders and records the order of stop placements and cancels
+type stopOrderStubTrader struct {
+	Trader
+	orders  []StopOrder
+	failSet bool
+	calls   []string
+}
+
+func (s *stopOrderStubTrader) GetStopLossOrders(symbol string) ([]StopOrder, error) {
+	return s.orders, nil
+}
+
+func (s *stopOrderStubTrader) CancelOrder(symbol, orderID string) error {
+	s.calls = append(s.calls, "cancel "+orderID)
+	return nil
+}
+
+func (s *stopOrderStubTrader) CancelStopLossOrders(symbol string) error {
+	s.calls = append(s.calls, "cancel all")
+	return nil
+}
+
+func (s *stopOrderStubTrader) SetStopLoss(symbol string, positionSide string, quantity, stopPrice float64) error {
+	if s.failSet {
+		return errors.New("rejected")
+	}
+	s.calls = append(s.calls, fmt.Sprintf("set %s %.1f", positionSide, stopPrice))
+	return nil
+}
+
+// TestApplyBlackoutAction_TightenLiveStops tests live exchange stops are never loosened and only cancelled once replaced
+func TestApplyBlackoutAction_TightenLiveStops(t *testing.T) {
+	state := &decision.SessionState{Blackout: &store.BlackoutEvent{Name: "CPI"}, Action: store.BlackoutActionTightenStops, TightenStopPct: 2}
+	long := []decision.PositionInfo{{Symbol: "BTCUSDT", Side: "long", MarkPrice: 100, Quantity: 1}}
+
+	// A stop placed before a restart is tighter than the blackout stop: left alone
+	stub := &stopOrderStubTrader{orders: []StopOrder{{OrderID: "1", Symbol: "BTCUSDT", PositionSide: "LONG", StopPrice: 99}}}
+	at := &AutoTrader{name: "test", trader: stub}
+	at.applyBlackoutAction(state, long, &store.DecisionRecord{})
+	assert.Empty(t, stub.calls)
+
+	// A looser live stop is replaced: the new stop is placed before the old one is cancelled
+	stub = &stopOrderStubTrader{orders: []StopOrder{
+		{OrderID: "1", Symbol: "BTCUSDT", PositionSide: "LONG", StopPrice: 90},
+		{OrderID: "2", Symbol: "BTCUSDT", PositionSide: "SHORT", StopPrice: 110},
+	}}
+	at = &AutoTrader{name: "test", trader: stub}
+	at.applyBlackoutAction(state, long, &store.DecisionRecord{})
+	assert.Equal(t, []string{"set LONG 98.0", "cancel 1"}, stub.calls)
+
+	// Placing fails: the old stop stays
+	stub = &stopOrderStubTrader{orders: []StopOrder{{OrderID: "1", Symbol: "BTCUSDT", PositionSide: "LONG", StopPrice: 90}}, failSet: true}
+	at = &AutoTrader{name: "test", trader: stub}
+	record := &store.DecisionRecord{}
+	at.applyBlackoutAction(state, long, record)
+	assert.Empty(t, stub.calls)
+	require.Len(t, record.ExecutionLog, 1)
+	assert.Contains(t, record.ExecutionLog[0], "failed")
+}
+
+// TestApplyBlackoutAction_RestoreRecordedStop tests venues without stop listing put the old stop back when placing fails
+func TestApplyBlackoutAction_RestoreRecordedStop(t *testing.T) {
+	state := &decision.SessionState{Blackout: &store.BlackoutEvent{Name: "CPI"}, Action: store.BlackoutActionTightenStops, TightenStopPct: 2}
+	stub := &sessionStubTrader{stops: make(map[string]float64)}
+	failing := &failingStopTrader{sessionStubTrader: stub}
+	at := &AutoTrader{name: "test", trader: failing}
+	at.recordStop("BTCUSDT_long", 90)
+
+	at.applyBlackoutAction(state, []decision.PositionInfo{{Symbol: "BTCUSDT", Side: "long", MarkPrice: 100, Quantity: 1}}, &store.DecisionRecord{})
+	assert.InDelta(t, 90.0, stub.stops["BTCUSDT_LONG"], 1e-9, "old stop restored")
+	assert.InDelta(t, 90.0, at.positionStops["BTCUSDT_long"], 1e-9)
+}
+
+// failingStopTrader rejects every stop except the restore of the previous one (90)
+type failingStopTrader struct {
+	*sessionStubTrader
+}
+
+func (f *failingStopTrader) SetStopLoss(symbol string, positionSide string, quantity, stopPrice float64) error {
+	if stopPrice != 90 {
+		return errors.New("rejected")
+	}
+	return f.sessionStubTrader.SetStopLoss(symbol, positionSide, quantity, stopPrice)
+}
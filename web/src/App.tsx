@@ -898,8 +898,25 @@ function TraderDetailsPage({
             <>
               <span>•</span>
               <span>Cycles: {status.call_count}</span>
+              {!!status.saved_ai_calls && (
+                <>
+                  <span>•</span>
+                  <span>AI calls saved: {status.saved_ai_calls}</span>
+                </>
+              )}
               <span>•</span>
               <span>Runtime: {status.runtime_minutes} min</span>
+              <span>•</span>
+              <span
+                className="font-semibold px-1.5 rounded"
+                style={
+                  status.testnet
+                    ? { color: '#F0B90B', background: 'rgba(240, 185, 11, 0.15)' }
+                    : { color: '#0ECB81' }
+                }
+              >
+                {status.testnet ? 'TESTNET' : 'MAINNET'}
+              </span>
             </>
           )}
         </div>
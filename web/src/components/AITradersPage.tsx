@@ -636,7 +636,11 @@ export function AITradersPage({ onTraderSelect }: AITradersPageProps) {
     asterPrivateKey?: string,
     lighterWalletAddr?: string,
     lighterPrivateKey?: string,
-    lighterApiKeyPrivateKey?: string
+    lighterApiKeyPrivateKey?: string,
+    dydxAddress?: string,
+    dydxMnemonic?: string,
+    dydxSubaccount?: number,
+    hyperliquidVaultAddr?: string
   ) => {
     try {
       if (exchangeId) {
@@ -656,12 +660,16 @@ export function AITradersPage({ onTraderSelect }: AITradersPageProps) {
               passphrase: passphrase || '',
               testnet: testnet || false,
               hyperliquid_wallet_addr: hyperliquidWalletAddr || '',
+              hyperliquid_vault_addr: hyperliquidVaultAddr || '',
               aster_user: asterUser || '',
               aster_signer: asterSigner || '',
               aster_private_key: asterPrivateKey || '',
               lighter_wallet_addr: lighterWalletAddr || '',
               lighter_private_key: lighterPrivateKey || '',
               lighter_api_key_private_key: lighterApiKeyPrivateKey || '',
+              dydx_address: dydxAddress || '',
+              dydx_mnemonic: dydxMnemonic || '',
+              dydx_subaccount: dydxSubaccount || 0,
             },
           },
         }
@@ -682,12 +690,16 @@ export function AITradersPage({ onTraderSelect }: AITradersPageProps) {
           passphrase: passphrase || '',
           testnet: testnet || false,
           hyperliquid_wallet_addr: hyperliquidWalletAddr || '',
+          hyperliquid_vault_addr: hyperliquidVaultAddr || '',
           aster_user: asterUser || '',
           aster_signer: asterSigner || '',
           aster_private_key: asterPrivateKey || '',
           lighter_wallet_addr: lighterWalletAddr || '',
           lighter_private_key: lighterPrivateKey || '',
           lighter_api_key_private_key: lighterApiKeyPrivateKey || '',
+          dydx_address: dydxAddress || '',
+          dydx_mnemonic: dydxMnemonic || '',
+          dydx_subaccount: dydxSubaccount || 0,
         }
 
         await toast.promise(api.createExchangeEncrypted(createRequest), {
@@ -1,6 +1,6 @@
 import { useState } from 'react'
-import { Plus, X, Database, TrendingUp, List, Link, AlertCircle } from 'lucide-react'
-import type { CoinSourceConfig } from '../../types'
+import { Plus, X, Database, TrendingUp, List, Link, AlertCircle, Filter } from 'lucide-react'
+import type { CoinSourceConfig, ScreenerConfig } from '../../types'
 
 // Default API URLs for data sources
 const DEFAULT_COIN_POOL_API_URL = 'http://nofxaios.com:30006/api/ai500/list?auth=cm_568c67eae410d912c54c'
@@ -28,6 +28,7 @@ export function CoinSourceEditor({
       coinpool: { zh: 'AI500 数据源', en: 'AI500 Data Provider' },
       oi_top: { zh: 'OI Top 持仓增长', en: 'OI Top' },
       mixed: { zh: '混合模式', en: 'Mixed Mode' },
+      screener: { zh: '内置筛选器', en: 'Screener' },
       staticCoins: { zh: '自定义币种', en: 'Custom Coins' },
       addCoin: { zh: '添加币种', en: 'Add Coin' },
       useCoinPool: { zh: '启用 AI500 数据源', en: 'Enable AI500 Data Provider' },
@@ -51,6 +52,28 @@ export function CoinSourceEditor({
         zh: '组合多种数据源，AI500 + OI Top + 自定义',
         en: 'Combine multiple sources: AI500 + OI Top + Custom',
       },
+      screenerDesc: {
+        zh: '按条件扫描交易所全部合约，无需外部 API',
+        en: 'Scan the exchange universe, no external API',
+      },
+      screenerFilters: { zh: '筛选条件', en: 'Screener Filters' },
+      minQuoteVolume: { zh: '最小 24h 成交额 (USDT)', en: 'Min 24h Volume (USDT)' },
+      minVolatilityPct: { zh: '最小 24h 振幅 %', en: 'Min 24h Range %' },
+      maxVolatilityPct: { zh: '最大 24h 振幅 %', en: 'Max 24h Range %' },
+      minFundingRatePct: { zh: '最小资金费率 %', en: 'Min Funding Rate %' },
+      maxFundingRatePct: { zh: '最大资金费率 %', en: 'Max Funding Rate %' },
+      minOIChangePct: { zh: '最小 OI 变化 %', en: 'Min OI Change %' },
+      maxOIChangePct: { zh: '最大 OI 变化 %', en: 'Max OI Change %' },
+      oiChangeHours: { zh: 'OI 变化周期 (小时)', en: 'OI Change Window (h)' },
+      maxPriceChangeRank: { zh: '涨跌幅排名前 N', en: 'Top N 24h Movers' },
+      minListingDays: { zh: '最少上市天数', en: 'Min Listing Days' },
+      rankBy: { zh: '排序方式', en: 'Rank By' },
+      screenerLimit: { zh: '候选数量上限', en: 'Max Candidates' },
+      rankVolume: { zh: '成交额', en: 'Volume' },
+      rankPriceChange: { zh: '涨跌幅', en: 'Price Change' },
+      rankVolatility: { zh: '振幅', en: 'Volatility' },
+      rankOIChange: { zh: 'OI 变化', en: 'OI Change' },
+      emptyNoLimit: { zh: '留空表示不限', en: 'Leave empty for no limit' },
       apiUrlRequired: { zh: '需要填写 API URL 才能获取数据', en: 'API URL required to fetch data' },
       dataSourceConfig: { zh: '数据源配置', en: 'Data Source Configuration' },
       fillDefault: { zh: '填入默认', en: 'Fill Default' },
@@ -63,8 +86,35 @@ export function CoinSourceEditor({
     { value: 'coinpool', icon: Database, color: '#F0B90B' },
     { value: 'oi_top', icon: TrendingUp, color: '#0ECB81' },
     { value: 'mixed', icon: Database, color: '#60a5fa' },
+    { value: 'screener', icon: Filter, color: '#c084fc' },
   ] as const
 
+  const screener = config.screener || {}
+  const screenerFields: { key: keyof ScreenerConfig; label: string; step: number }[] = [
+    { key: 'min_quote_volume', label: 'minQuoteVolume', step: 1000000 },
+    { key: 'min_volatility_pct', label: 'minVolatilityPct', step: 0.5 },
+    { key: 'max_volatility_pct', label: 'maxVolatilityPct', step: 0.5 },
+    { key: 'min_funding_rate_pct', label: 'minFundingRatePct', step: 0.001 },
+    { key: 'max_funding_rate_pct', label: 'maxFundingRatePct', step: 0.001 },
+    { key: 'min_oi_change_pct', label: 'minOIChangePct', step: 1 },
+    { key: 'max_oi_change_pct', label: 'maxOIChangePct', step: 1 },
+    { key: 'oi_change_hours', label: 'oiChangeHours', step: 1 },
+    { key: 'max_price_change_rank', label: 'maxPriceChangeRank', step: 1 },
+    { key: 'min_listing_days', label: 'minListingDays', step: 1 },
+    { key: 'limit', label: 'screenerLimit', step: 1 },
+  ]
+
+  const updateScreener = (key: keyof ScreenerConfig, value: string) => {
+    if (disabled) return
+    const next: ScreenerConfig = { ...screener }
+    if (value === '') {
+      delete next[key]
+    } else {
+      ;(next as Record<string, unknown>)[key] = key === 'rank_by' ? value : parseFloat(value)
+    }
+    onChange({ ...config, screener: next })
+  }
+
   const handleAddCoin = () => {
     if (!newCoin.trim()) return
     const symbol = newCoin.toUpperCase().trim()
@@ -93,7 +143,7 @@ export function CoinSourceEditor({
         <label className="block text-sm font-medium mb-3" style={{ color: '#EAECEF' }}>
           {t('sourceType')}
         </label>
-        <div className="grid grid-cols-4 gap-3">
+        <div className="grid grid-cols-5 gap-3">
           {sourceTypes.map(({ value, icon: Icon, color }) => (
             <button
               key={value}
@@ -180,6 +230,64 @@ export function CoinSourceEditor({
         </div>
       )}
 
+      {/* Screener Filters */}
+      {config.source_type === 'screener' && (
+        <div className="space-y-4">
+          <div className="flex items-center gap-2 mb-2">
+            <Filter className="w-4 h-4" style={{ color: '#c084fc' }} />
+            <span className="text-sm font-medium" style={{ color: '#EAECEF' }}>
+              {t('screenerFilters')}
+            </span>
+            <span className="text-xs" style={{ color: '#5E6673' }}>
+              {t('emptyNoLimit')}
+            </span>
+          </div>
+          <div className="grid grid-cols-3 gap-3">
+            {screenerFields.map(({ key, label, step }) => (
+              <div key={key}>
+                <label className="block text-xs mb-1" style={{ color: '#848E9C' }}>
+                  {t(label)}
+                </label>
+                <input
+                  type="number"
+                  step={step}
+                  value={screener[key] ?? ''}
+                  onChange={(e) => updateScreener(key, e.target.value)}
+                  disabled={disabled}
+                  className="w-full px-3 py-1.5 rounded text-sm"
+                  style={{
+                    background: '#0B0E11',
+                    border: '1px solid #2B3139',
+                    color: '#EAECEF',
+                  }}
+                />
+              </div>
+            ))}
+            <div>
+              <label className="block text-xs mb-1" style={{ color: '#848E9C' }}>
+                {t('rankBy')}
+              </label>
+              <select
+                value={screener.rank_by || 'volume'}
+                onChange={(e) => updateScreener('rank_by', e.target.value)}
+                disabled={disabled}
+                className="w-full px-3 py-1.5 rounded text-sm"
+                style={{
+                  background: '#0B0E11',
+                  border: '1px solid #2B3139',
+                  color: '#EAECEF',
+                }}
+              >
+                <option value="volume">{t('rankVolume')}</option>
+                <option value="price_change">{t('rankPriceChange')}</option>
+                <option value="volatility">{t('rankVolatility')}</option>
+                <option value="oi_change">{t('rankOIChange')}</option>
+              </select>
+            </div>
+          </div>
+        </div>
+      )}
+
       {/* Coin Pool Options */}
       {(config.source_type === 'coinpool' || config.source_type === 'mixed') && (
         <div className="space-y-4">
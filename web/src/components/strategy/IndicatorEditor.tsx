@@ -1,5 +1,5 @@
 import { Clock, Activity, Database, TrendingUp, BarChart2, Info, Lock, LineChart } from 'lucide-react'
-import type { IndicatorConfig } from '../../types'
+import type { IndicatorConfig, IndicatorSpec, ExpressionSpec } from '../../types'
 
 // Default API URL for quant data (must contain {symbol} placeholder)
 const DEFAULT_QUANT_DATA_API_URL = 'http://nofxaios.com:30006/api/coin/{symbol}?include=netflow,oi,price&auth=cm_568c67eae410d912c54c'
@@ -31,6 +31,18 @@ const allTimeframes = [
   { value: '1w', label: '1W', category: 'position' },
 ]
 
+// 指标库中的可选指标（与后端 market 指标注册表一致）
+const customIndicatorDefs: { name: string; color: string; params: { name: string; default: number }[] }[] = [
+  { name: 'bollinger', color: '#38bdf8', params: [{ name: 'period', default: 20 }, { name: 'stddev', default: 2 }] },
+  { name: 'vwap', color: '#f472b6', params: [{ name: 'period', default: 0 }] },
+  { name: 'stoch_rsi', color: '#fb7185', params: [{ name: 'rsi_period', default: 14 }, { name: 'stoch_period', default: 14 }, { name: 'k', default: 3 }, { name: 'd', default: 3 }] },
+  { name: 'adx', color: '#facc15', params: [{ name: 'period', default: 14 }] },
+  { name: 'supertrend', color: '#4ade80', params: [{ name: 'period', default: 10 }, { name: 'multiplier', default: 3 }] },
+  { name: 'ichimoku', color: '#f97316', params: [{ name: 'conversion', default: 9 }, { name: 'base', default: 26 }, { name: 'span_b', default: 52 }, { name: 'displacement', default: 26 }] },
+  { name: 'obv', color: '#a78bfa', params: [] },
+  { name: 'donchian', color: '#2dd4bf', params: [{ name: 'period', default: 20 }] },
+]
+
 export function IndicatorEditor({
   config,
   onChange,
@@ -78,6 +90,12 @@ export function IndicatorEditor({
       oiDesc: { zh: '合约未平仓量', en: 'Futures open interest' },
       fundingRate: { zh: '资金费率', en: 'Funding Rate' },
       fundingRateDesc: { zh: '永续合约资金费率', en: 'Perpetual funding rate' },
+      orderBook: { zh: '订单簿', en: 'Order Book' },
+      orderBookDesc: { zh: '价差、买卖盘失衡、深度与大单墙', en: 'Spread, imbalance, depth and walls' },
+      takerFlow: { zh: '主动买卖流', en: 'Taker Flow' },
+      takerFlowDesc: { zh: '主动买卖量、CVD 与大额成交', en: 'Taker buy/sell volume, CVD and large trades' },
+      liquidations: { zh: '爆仓数据', en: 'Liquidations' },
+      liquidationsDesc: { zh: '多空爆仓金额（5分钟至4小时）', en: 'Long/short liquidations, 5m to 4h' },
 
       // Quant data
       quantDataUrl: { zh: '数据接口 URL', en: 'Data API URL' },
@@ -91,12 +109,68 @@ export function IndicatorEditor({
       oiRankingLimit: { zh: '排行数量', en: 'Top N' },
       oiRankingNote: { zh: '显示持仓量增加/减少的币种排行，帮助发现资金流向', en: 'Shows coins with OI increase/decrease, helps identify capital flow' },
 
+      // Custom indicators
+      customIndicators: { zh: '更多指标', en: 'More Indicators' },
+      customIndicatorsDesc: { zh: '可配置参数，自动加入各周期数据', en: 'Configurable, added to every timeframe' },
+      bollinger: { zh: '布林带', en: 'Bollinger Bands' },
+      vwap: { zh: 'VWAP（周期 0 = 按日锚定）', en: 'VWAP (period 0 = daily anchored)' },
+      stoch_rsi: { zh: '随机 RSI', en: 'Stochastic RSI' },
+      adx: { zh: 'ADX 趋势强度', en: 'ADX' },
+      supertrend: { zh: '超级趋势', en: 'SuperTrend' },
+      ichimoku: { zh: '一目均衡表', en: 'Ichimoku Cloud' },
+      obv: { zh: '能量潮 OBV', en: 'On-Balance Volume' },
+      donchian: { zh: '唐奇安通道', en: 'Donchian Channel' },
+
+      // Expressions
+      expressions: { zh: '自定义表达式', en: 'Custom Expressions' },
+      expressionsDesc: {
+        zh: '可用 open/high/low/close/volume 与 ema、sma、rsi、highest、lowest、zscore 等函数',
+        en: 'Use open/high/low/close/volume with ema, sma, rsi, highest, lowest, zscore, ...',
+      },
+      addExpression: { zh: '+ 添加表达式', en: '+ Add Expression' },
+      expressionName: { zh: '名称', en: 'Name' },
+
       // Tips
       aiCanCalculate: { zh: '💡 提示：AI 可自行计算这些指标，开启可减少 AI 计算量', en: '💡 Tip: AI can calculate these, enabling reduces AI workload' },
     }
     return translations[key]?.[language] || key
   }
 
+  // 自定义指标
+  const customIndicators = config.custom_indicators || []
+  const findCustomIndicator = (name: string) => customIndicators.find((ci) => ci.name === name)
+
+  const toggleCustomIndicator = (name: string, enabled: boolean) => {
+    if (disabled) return
+    const rest = customIndicators.filter((ci) => ci.name !== name)
+    onChange({ ...config, custom_indicators: enabled ? [...rest, { name }] : rest })
+  }
+
+  const updateCustomIndicatorParam = (spec: IndicatorSpec, param: string, value: number) => {
+    if (disabled) return
+    onChange({
+      ...config,
+      custom_indicators: customIndicators.map((ci) =>
+        ci.name === spec.name ? { ...ci, params: { ...ci.params, [param]: value } } : ci
+      ),
+    })
+  }
+
+  // 自定义表达式
+  const expressions = config.expressions || []
+  const updateExpression = (index: number, patch: Partial<ExpressionSpec>) => {
+    if (disabled) return
+    onChange({ ...config, expressions: expressions.map((ex, i) => (i === index ? { ...ex, ...patch } : ex)) })
+  }
+  const addExpression = () => {
+    if (disabled) return
+    onChange({ ...config, expressions: [...expressions, { name: `expr_${expressions.length + 1}`, expr: '' }] })
+  }
+  const removeExpression = (index: number) => {
+    if (disabled) return
+    onChange({ ...config, expressions: expressions.filter((_, i) => i !== index) })
+  }
+
   // 获取当前选中的时间周期
   const selectedTimeframes = config.klines.selected_timeframes || [config.klines.primary_timeframe]
 
@@ -339,6 +413,114 @@ export function IndicatorEditor({
               </div>
             ))}
           </div>
+
+          {/* Custom Indicators */}
+          <div className="mt-3 mb-2 flex items-center gap-2">
+            <span className="text-xs font-medium" style={{ color: '#EAECEF' }}>{t('customIndicators')}</span>
+            <span className="text-[10px]" style={{ color: '#848E9C' }}>- {t('customIndicatorsDesc')}</span>
+          </div>
+          <div className="grid grid-cols-2 gap-2">
+            {customIndicatorDefs.map(({ name, color, params }) => {
+              const spec = findCustomIndicator(name)
+              return (
+                <div
+                  key={name}
+                  className="p-2.5 rounded-lg transition-all"
+                  style={{
+                    background: spec ? `${color}08` : 'transparent',
+                    border: `1px solid ${spec ? `${color}30` : '#2B3139'}`,
+                  }}
+                >
+                  <div className="flex items-center justify-between mb-1">
+                    <div className="flex items-center gap-2">
+                      <div className="w-2 h-2 rounded-full" style={{ background: color }} />
+                      <span className="text-xs font-medium" style={{ color: '#EAECEF' }}>{t(name)}</span>
+                    </div>
+                    <input
+                      type="checkbox"
+                      checked={!!spec}
+                      onChange={(e) => toggleCustomIndicator(name, e.target.checked)}
+                      disabled={disabled}
+                      className="w-4 h-4 rounded accent-yellow-500"
+                    />
+                  </div>
+                  {spec && params.length > 0 && (
+                    <div className="grid grid-cols-2 gap-1">
+                      {params.map((p) => (
+                        <label key={p.name} className="flex items-center gap-1 text-[10px]" style={{ color: '#5E6673' }}>
+                          <span className="truncate">{p.name}</span>
+                          <input
+                            type="number"
+                            value={spec.params?.[p.name] ?? p.default}
+                            onChange={(e) => {
+                              const v = parseFloat(e.target.value)
+                              if (!isNaN(v)) updateCustomIndicatorParam(spec, p.name, v)
+                            }}
+                            disabled={disabled}
+                            className="w-full px-1 py-0.5 rounded text-[10px] text-center"
+                            style={{ background: '#1E2329', border: '1px solid #2B3139', color: '#EAECEF' }}
+                          />
+                        </label>
+                      ))}
+                    </div>
+                  )}
+                </div>
+              )
+            })}
+          </div>
+
+          {/* Custom Expressions */}
+          <div className="mt-3 mb-2 flex items-center justify-between">
+            <div className="flex items-center gap-2">
+              <span className="text-xs font-medium" style={{ color: '#EAECEF' }}>{t('expressions')}</span>
+              <span className="text-[10px]" style={{ color: '#848E9C' }}>- {t('expressionsDesc')}</span>
+            </div>
+            {!disabled && (
+              <button
+                type="button"
+                onClick={addExpression}
+                className="text-[10px] px-2 py-0.5 rounded"
+                style={{ background: '#1E2329', border: '1px solid #2B3139', color: '#F0B90B' }}
+              >
+                {t('addExpression')}
+              </button>
+            )}
+          </div>
+          <div className="space-y-1.5">
+            {expressions.map((ex, index) => (
+              <div key={index} className="flex items-center gap-1.5">
+                <input
+                  type="text"
+                  value={ex.name}
+                  onChange={(e) => updateExpression(index, { name: e.target.value })}
+                  disabled={disabled}
+                  placeholder={t('expressionName')}
+                  className="w-28 px-2 py-1 rounded text-[10px] font-mono"
+                  style={{ background: '#1E2329', border: '1px solid #2B3139', color: '#EAECEF' }}
+                />
+                <span className="text-[10px]" style={{ color: '#848E9C' }}>=</span>
+                <input
+                  type="text"
+                  value={ex.expr}
+                  onChange={(e) => updateExpression(index, { expr: e.target.value })}
+                  disabled={disabled}
+                  placeholder="ema(close,9) - ema(close,21)"
+                  className="flex-1 px-2 py-1 rounded text-[10px] font-mono"
+                  style={{ background: '#1E2329', border: '1px solid #2B3139', color: '#EAECEF' }}
+                />
+                {!disabled && (
+                  <button
+                    type="button"
+                    onClick={() => removeExpression(index)}
+                    className="text-[10px] px-1.5"
+                    style={{ color: '#F6465D' }}
+                  >
+                    ✕
+                  </button>
+                )}
+              </div>
+            ))}
+          </div>
         </div>
       </div>
 
@@ -351,11 +533,14 @@ export function IndicatorEditor({
         </div>
 
         <div className="p-3">
-          <div className="grid grid-cols-3 gap-2">
+          <div className="grid grid-cols-2 gap-2">
             {[
               { key: 'enable_volume', label: 'volume', desc: 'volumeDesc', color: '#c084fc' },
               { key: 'enable_oi', label: 'oi', desc: 'oiDesc', color: '#34d399' },
               { key: 'enable_funding_rate', label: 'fundingRate', desc: 'fundingRateDesc', color: '#fbbf24' },
+              { key: 'enable_order_book', label: 'orderBook', desc: 'orderBookDesc', color: '#38bdf8' },
+              { key: 'enable_taker_flow', label: 'takerFlow', desc: 'takerFlowDesc', color: '#f472b6' },
+              { key: 'enable_liquidations', label: 'liquidations', desc: 'liquidationsDesc', color: '#f87171' },
             ].map(({ key, label, desc, color }) => (
               <div
                 key={key}
@@ -22,9 +22,11 @@ const SUPPORTED_EXCHANGE_TEMPLATES = [
   { exchange_type: 'bybit', name: 'Bybit Futures', type: 'cex' as const },
   { exchange_type: 'okx', name: 'OKX Futures', type: 'cex' as const },
   { exchange_type: 'bitget', name: 'Bitget Futures', type: 'cex' as const },
+  { exchange_type: 'gate', name: 'Gate Futures', type: 'cex' as const },
   { exchange_type: 'hyperliquid', name: 'Hyperliquid', type: 'dex' as const },
   { exchange_type: 'aster', name: 'Aster DEX', type: 'dex' as const },
   { exchange_type: 'lighter', name: 'Lighter', type: 'dex' as const },
+  { exchange_type: 'dydx', name: 'dYdX v4', type: 'dex' as const },
 ]
 
 interface ExchangeConfigModalProps {
@@ -44,7 +46,11 @@ interface ExchangeConfigModalProps {
     asterPrivateKey?: string,
     lighterWalletAddr?: string,
     lighterPrivateKey?: string,
-    lighterApiKeyPrivateKey?: string
+    lighterApiKeyPrivateKey?: string,
+    dydxAddress?: string,
+    dydxMnemonic?: string,
+    dydxSubaccount?: number,
+    hyperliquidVaultAddr?: string
   ) => Promise<void>
   onDelete: (exchangeId: string) => void
   onClose: () => void
@@ -85,12 +91,18 @@ export function ExchangeConfigModal({
 
   // Hyperliquid 特定字段
   const [hyperliquidWalletAddr, setHyperliquidWalletAddr] = useState('')
+  const [hyperliquidVaultAddr, setHyperliquidVaultAddr] = useState('')
 
   // LIGHTER 特定字段
   const [lighterWalletAddr, setLighterWalletAddr] = useState('')
   const [lighterPrivateKey, setLighterPrivateKey] = useState('')
   const [lighterApiKeyPrivateKey, setLighterApiKeyPrivateKey] = useState('')
 
+  // dYdX 特定字段
+  const [dydxAddress, setDydxAddress] = useState('')
+  const [dydxMnemonic, setDydxMnemonic] = useState('')
+  const [dydxSubaccount, setDydxSubaccount] = useState(0)
+
   // 安全输入状态
   const [secureInputTarget, setSecureInputTarget] = useState<
     null | 'hyperliquid' | 'aster' | 'lighter'
@@ -125,9 +137,11 @@ export function ExchangeConfigModal({
     okx: { url: 'https://www.okx.com/join/1865360', hasReferral: true },
     bybit: { url: 'https://partner.bybit.com/b/83856', hasReferral: true },
     bitget: { url: 'https://www.bitget.com/referral/register?from=referral&clacCode=c8a43172', hasReferral: true },
+    gate: { url: 'https://www.gate.io', hasReferral: false },
     hyperliquid: { url: 'https://app.hyperliquid.xyz/join/AITRADING', hasReferral: true },
     aster: { url: 'https://www.asterdex.com/en/referral/fdfc0e', hasReferral: true },
     lighter: { url: 'https://lighter.xyz', hasReferral: false },
+    dydx: { url: 'https://dydx.trade', hasReferral: false },
   }
 
   // 如果是编辑现有交易所，初始化表单数据
@@ -146,11 +160,17 @@ export function ExchangeConfigModal({
 
       // Hyperliquid 字段
       setHyperliquidWalletAddr(selectedExchange.hyperliquidWalletAddr || '')
+      setHyperliquidVaultAddr(selectedExchange.hyperliquidVaultAddr || '')
 
       // LIGHTER 字段
       setLighterWalletAddr(selectedExchange.lighterWalletAddr || '')
       setLighterPrivateKey('') // Don't load existing private key for security
       setLighterApiKeyPrivateKey('') // Don't load existing API key for security
+
+      // dYdX 字段
+      setDydxAddress(selectedExchange.dydxAddress || '')
+      setDydxMnemonic('') // Don't load existing mnemonic for security
+      setDydxSubaccount(selectedExchange.dydxSubaccount || 0)
     }
   }, [editingExchangeId, selectedExchange])
 
@@ -297,7 +317,17 @@ export function ExchangeConfigModal({
           '',
           '',
           testnet,
-          hyperliquidWalletAddr.trim()
+          hyperliquidWalletAddr.trim(),
+          undefined,
+          undefined,
+          undefined,
+          undefined,
+          undefined,
+          undefined,
+          undefined,
+          undefined,
+          undefined,
+          hyperliquidVaultAddr.trim()
         )
       } else if (currentExchangeType === 'aster') {
         if (!asterUser.trim() || !asterSigner.trim() || !asterPrivateKey.trim())
@@ -333,6 +363,27 @@ export function ExchangeConfigModal({
           lighterPrivateKey.trim(),
           lighterApiKeyPrivateKey.trim()
         )
+      } else if (currentExchangeType === 'dydx') {
+        if (!dydxMnemonic.trim()) return
+        await onSave(
+          exchangeId,
+          exchangeType,
+          trimmedAccountName,
+          '',
+          '',
+          '',
+          testnet,
+          undefined,
+          undefined,
+          undefined,
+          undefined,
+          undefined,
+          undefined,
+          undefined,
+          dydxAddress.trim(),
+          dydxMnemonic.trim(),
+          dydxSubaccount
+        )
       } else {
         // 默认情况（其他CEX交易所）
         if (!apiKey.trim() || !secretKey.trim()) return
@@ -538,11 +589,12 @@ export function ExchangeConfigModal({
 
             {selectedTemplate && (
               <>
-                {/* Binance/Bybit/OKX/Bitget 的输入字段 */}
+                {/* Binance/Bybit/OKX/Bitget/Gate 的输入字段 */}
                 {(currentExchangeType === 'binance' ||
                   currentExchangeType === 'bybit' ||
                   currentExchangeType === 'okx' ||
-                  currentExchangeType === 'bitget') && (
+                  currentExchangeType === 'bitget' ||
+                  currentExchangeType === 'gate') && (
                     <>
                       {/* 币安用户配置提示 (D1 方案) */}
                       {currentExchangeType === 'binance' && (
@@ -711,6 +763,26 @@ export function ExchangeConfigModal({
                         </div>
                       )}
 
+                      {/* 测试网 / 模拟盘 */}
+                      {currentExchangeType !== 'gate' && (
+                        <div>
+                          <label
+                            className="flex items-center gap-2 text-sm font-semibold"
+                            style={{ color: '#EAECEF' }}
+                          >
+                            <input
+                              type="checkbox"
+                              checked={testnet}
+                              onChange={(e) => setTestnet(e.target.checked)}
+                            />
+                            {t('useTestnet', language)}
+                          </label>
+                          <div className="text-xs mt-1" style={{ color: '#848E9C' }}>
+                            {t('testnetDescription', language)}
+                          </div>
+                        </div>
+                      )}
+
                       {/* Binance 白名单IP提示 */}
                       {currentExchangeType === 'binance' && (
                         <div
@@ -1043,6 +1115,39 @@ export function ExchangeConfigModal({
                         {t('hyperliquidMainWalletAddressDesc', language)}
                       </div>
                     </div>
+
+                    {/* Sub-account / Vault Address 字段 */}
+                    <div>
+                      <label
+                        className="block text-sm font-semibold mb-2"
+                        style={{ color: '#EAECEF' }}
+                      >
+                        {t('hyperliquidVaultAddress', language)}
+                      </label>
+                      <input
+                        type="text"
+                        value={hyperliquidVaultAddr}
+                        onChange={(e) =>
+                          setHyperliquidVaultAddr(e.target.value)
+                        }
+                        placeholder={t(
+                          'enterHyperliquidVaultAddress',
+                          language
+                        )}
+                        className="w-full px-3 py-2 rounded"
+                        style={{
+                          background: '#0B0E11',
+                          border: '1px solid #2B3139',
+                          color: '#EAECEF',
+                        }}
+                      />
+                      <div
+                        className="text-xs mt-1"
+                        style={{ color: '#848E9C' }}
+                      >
+                        {t('hyperliquidVaultAddressDesc', language)}
+                      </div>
+                    </div>
                   </>
                 )}
 
@@ -1162,6 +1267,84 @@ export function ExchangeConfigModal({
                     </div>
                   </>
                 )}
+
+                {/* dYdX 特定配置 */}
+                {currentExchangeType === 'dydx' && (
+                  <>
+                    <div className="mb-4">
+                      <label
+                        className="block text-sm font-semibold mb-2"
+                        style={{ color: '#EAECEF' }}
+                      >
+                        {t('dydxMnemonic', language)}
+                      </label>
+                      <input
+                        type="password"
+                        value={dydxMnemonic}
+                        onChange={(e) => setDydxMnemonic(e.target.value)}
+                        className="w-full px-3 py-2 rounded font-mono text-sm"
+                        style={{
+                          background: '#0B0E11',
+                          border: '1px solid #2B3139',
+                          color: '#EAECEF',
+                        }}
+                        required
+                      />
+                      <div className="text-xs mt-1" style={{ color: '#848E9C' }}>
+                        {t('dydxMnemonicDesc', language)}
+                      </div>
+                    </div>
+
+                    <div className="mb-4">
+                      <label
+                        className="block text-sm font-semibold mb-2"
+                        style={{ color: '#EAECEF' }}
+                      >
+                        {t('dydxAddress', language)}
+                      </label>
+                      <input
+                        type="text"
+                        value={dydxAddress}
+                        onChange={(e) => setDydxAddress(e.target.value)}
+                        placeholder="dydx1..."
+                        className="w-full px-3 py-2 rounded"
+                        style={{
+                          background: '#0B0E11',
+                          border: '1px solid #2B3139',
+                          color: '#EAECEF',
+                        }}
+                      />
+                      <div className="text-xs mt-1" style={{ color: '#848E9C' }}>
+                        {t('dydxAddressDesc', language)}
+                      </div>
+                    </div>
+
+                    <div className="mb-4">
+                      <label
+                        className="block text-sm font-semibold mb-2"
+                        style={{ color: '#EAECEF' }}
+                      >
+                        {t('dydxSubaccount', language)}
+                      </label>
+                      <input
+                        type="number"
+                        min={0}
+                        max={127}
+                        value={dydxSubaccount}
+                        onChange={(e) => setDydxSubaccount(Number(e.target.value) || 0)}
+                        className="w-full px-3 py-2 rounded"
+                        style={{
+                          background: '#0B0E11',
+                          border: '1px solid #2B3139',
+                          color: '#EAECEF',
+                        }}
+                      />
+                      <div className="text-xs mt-1" style={{ color: '#848E9C' }}>
+                        {t('dydxSubaccountDesc', language)}
+                      </div>
+                    </div>
+                  </>
+                )}
               </>
             )}
           </div>
@@ -1202,6 +1385,7 @@ export function ExchangeConfigModal({
                     !asterPrivateKey.trim())) ||
                 (currentExchangeType === 'lighter' &&
                   (!lighterWalletAddr.trim() || !lighterPrivateKey.trim())) ||
+                (currentExchangeType === 'dydx' && !dydxMnemonic.trim()) ||
                 (currentExchangeType === 'bybit' &&
                   (!apiKey.trim() || !secretKey.trim())) ||
                 (selectedTemplate?.type === 'cex' &&
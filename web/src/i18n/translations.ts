@@ -353,6 +353,10 @@ export const translations = {
     enterHyperliquidMainWalletAddress: 'Enter Main wallet address',
     hyperliquidMainWalletAddressDesc:
       'Main wallet address that holds your trading funds (never expose its private key)',
+    hyperliquidVaultAddress: 'Sub-account / Vault Address (optional)',
+    enterHyperliquidVaultAddress: 'Leave empty to trade the main wallet',
+    hyperliquidVaultAddressDesc:
+      'Trade a sub-account or vault you manage instead of the main wallet. Balances, positions and history are tracked per address, so add each sub-account as its own exchange account.',
     // Aster API Pro Configuration
     asterApiProTitle: 'Aster API Pro Wallet Configuration',
     asterApiProDesc:
@@ -386,6 +390,12 @@ export const translations = {
     lighterV1Description: 'Basic Mode - Limited functionality, testing framework only',
     lighterV2Description: 'Full Mode - Supports Poseidon2 signing and real trading',
     lighterPrivateKeyImported: 'LIGHTER private key imported',
+    dydxAddress: 'dYdX Address',
+    dydxAddressDesc: 'Your dydx1... address, used to verify the mnemonic (optional)',
+    dydxMnemonic: 'Mnemonic',
+    dydxMnemonicDesc: 'Mnemonic of the dYdX account (a hex private key is also accepted), stored encrypted',
+    dydxSubaccount: 'Subaccount Number',
+    dydxSubaccountDesc: 'Subaccount holding the trading margin (0 by default)',
 
     // Exchange names
     hyperliquidExchangeName: 'Hyperliquid',
@@ -855,7 +865,7 @@ export const translations = {
 
     faqTestnet: 'Can I use testnet for testing?',
     faqTestnetAnswer:
-      'Testnet is not supported at the moment. We recommend using real trading with small amounts (10-50 USDT) for testing.',
+      'Yes. Enable "Use Testnet" on the exchange account: Binance, Bybit, Hyperliquid, Lighter and dYdX connect to their testnets, OKX and Bitget to demo trading. Testnet accounts need API keys created on the testnet/demo site. Traders on such accounts show a TESTNET badge.',
 
     // Trading Questions
     faqNoTrades: "Why isn't my trader making any trades?",
@@ -1434,6 +1444,10 @@ export const translations = {
     enterHyperliquidMainWalletAddress: '输入主钱包地址',
     hyperliquidMainWalletAddressDesc:
       '持有交易资金的主钱包地址（永不暴露其私钥）',
+    hyperliquidVaultAddress: '子账户 / 金库地址（可选）',
+    enterHyperliquidVaultAddress: '留空则交易主钱包',
+    hyperliquidVaultAddressDesc:
+      '交易您管理的子账户或金库而非主钱包。余额、持仓和历史按地址独立统计，请将每个子账户添加为单独的交易所账户。',
     // Aster API Pro 配置
     asterApiProTitle: 'Aster API Pro 代理钱包配置',
     asterApiProDesc:
@@ -1467,6 +1481,12 @@ export const translations = {
     lighterV1Description: '基本模式 - 功能受限，僅用於測試框架',
     lighterV2Description: '完整模式 - 支持 Poseidon2 簽名和真實交易',
     lighterPrivateKeyImported: 'LIGHTER 私鑰已導入',
+    dydxAddress: 'dYdX 地址',
+    dydxAddressDesc: '您的 dydx1... 地址，用於校驗助記詞（可選）',
+    dydxMnemonic: '助記詞',
+    dydxMnemonicDesc: 'dYdX 賬戶助記詞（也可填寫十六進制私鑰），加密存儲',
+    dydxSubaccount: '子賬戶編號',
+    dydxSubaccountDesc: '存放交易保證金的子賬戶（默認 0）',
 
     // Exchange names
     hyperliquidExchangeName: 'Hyperliquid',
@@ -1889,7 +1909,7 @@ export const translations = {
 
     faqTestnet: '可以使用测试网测试吗？',
     faqTestnetAnswer:
-      '暂时不支持测试网。我们建议使用真实交易但小额资金（10-50 USDT）进行测试。',
+      '可以。在交易所账户中开启"使用测试网"：Binance、Bybit、Hyperliquid、Lighter 和 dYdX 连接各自测试网，OKX 和 Bitget 连接模拟盘。测试网账户需使用在测试网/模拟盘创建的 API Key，对应交易员会显示 TESTNET 标记。',
 
     // Trading Questions
     faqNoTrades: '为什么我的交易员不开仓？',
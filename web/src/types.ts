@@ -6,11 +6,14 @@ export interface SystemStatus {
   start_time: string
   runtime_minutes: number
   call_count: number
+  saved_ai_calls?: number // cycles the pre-AI gate skipped the AI call
   initial_balance: number
   scan_interval: string
   stop_until: string
   last_reset_time: string
   ai_provider: string
+  testnet?: boolean
+  environment?: 'testnet' | 'mainnet'
 }
 
 export interface AccountInfo {
@@ -112,7 +115,7 @@ export interface AIModel {
 
 export interface Exchange {
   id: string                     // UUID (empty for supported exchange templates)
-  exchange_type: string          // "binance", "bybit", "okx", "hyperliquid", "aster", "lighter"
+  exchange_type: string          // "binance", "bybit", "okx", "hyperliquid", "aster", "lighter", "dydx"
   account_name: string           // User-defined account name
   name: string                   // Display name
   type: 'cex' | 'dex'
@@ -123,6 +126,7 @@ export interface Exchange {
   testnet?: boolean
   // Hyperliquid specific
   hyperliquidWalletAddr?: string
+  hyperliquidVaultAddr?: string   // Sub-account or vault address
   // Aster specific
   asterUser?: string
   asterSigner?: string
@@ -131,10 +135,13 @@ export interface Exchange {
   lighterWalletAddr?: string
   lighterPrivateKey?: string
   lighterApiKeyPrivateKey?: string
+  // dYdX specific
+  dydxAddress?: string
+  dydxSubaccount?: number
 }
 
 export interface CreateExchangeRequest {
-  exchange_type: string          // "binance", "bybit", "okx", "hyperliquid", "aster", "lighter"
+  exchange_type: string          // "binance", "bybit", "okx", "hyperliquid", "aster", "lighter", "dydx"
   account_name: string           // User-defined account name
   enabled: boolean
   api_key?: string
@@ -142,12 +149,16 @@ export interface CreateExchangeRequest {
   passphrase?: string
   testnet?: boolean
   hyperliquid_wallet_addr?: string
+  hyperliquid_vault_addr?: string
   aster_user?: string
   aster_signer?: string
   aster_private_key?: string
   lighter_wallet_addr?: string
   lighter_private_key?: string
   lighter_api_key_private_key?: string
+  dydx_address?: string
+  dydx_mnemonic?: string
+  dydx_subaccount?: number
 }
 
 export interface CreateTraderRequest {
@@ -191,6 +202,7 @@ export interface UpdateExchangeConfigRequest {
       testnet?: boolean
       // Hyperliquid 特定字段
       hyperliquid_wallet_addr?: string
+      hyperliquid_vault_addr?: string
       // Aster 特定字段
       aster_user?: string
       aster_signer?: string
@@ -199,6 +211,10 @@ export interface UpdateExchangeConfigRequest {
       lighter_wallet_addr?: string
       lighter_private_key?: string
       lighter_api_key_private_key?: string
+      // dYdX 特定字段
+      dydx_address?: string
+      dydx_mnemonic?: string
+      dydx_subaccount?: number
     }
   }
 }
@@ -407,7 +423,7 @@ export interface StrategyConfig {
 }
 
 export interface CoinSourceConfig {
-  source_type: 'static' | 'coinpool' | 'oi_top' | 'mixed';
+  source_type: 'static' | 'coinpool' | 'oi_top' | 'mixed' | 'screener';
   static_coins?: string[];
   use_coin_pool: boolean;
   coin_pool_limit?: number;
@@ -415,6 +431,33 @@ export interface CoinSourceConfig {
   use_oi_top: boolean;
   oi_top_limit?: number;
   oi_top_api_url?: string;     // OI Top API URL
+  screener?: ScreenerConfig;   // 内置筛选器 (source_type = 'screener')
+}
+
+// Built-in screener filters, unset bounds are omitted
+export interface ScreenerConfig {
+  min_quote_volume?: number;
+  min_volatility_pct?: number;
+  max_volatility_pct?: number;
+  min_funding_rate_pct?: number;
+  max_funding_rate_pct?: number;
+  min_oi_change_pct?: number;
+  max_oi_change_pct?: number;
+  oi_change_hours?: number;
+  max_price_change_rank?: number;
+  min_listing_days?: number;
+  rank_by?: 'volume' | 'price_change' | 'volatility' | 'oi_change';
+  limit?: number;
+}
+
+export interface IndicatorSpec {
+  name: string;
+  params?: Record<string, number>;
+}
+
+export interface ExpressionSpec {
+  name: string;
+  expr: string;
 }
 
 export interface IndicatorConfig {
@@ -429,9 +472,19 @@ export interface IndicatorConfig {
   enable_volume: boolean;
   enable_oi: boolean;
   enable_funding_rate: boolean;
+  // Order book spread, imbalance, depth and walls
+  enable_order_book?: boolean;
+  // Taker buy/sell volume, CVD and large trades
+  enable_taker_flow?: boolean;
+  // Long/short liquidations over rolling windows
+  enable_liquidations?: boolean;
   ema_periods?: number[];
   rsi_periods?: number[];
   atr_periods?: number[];
+  // Registry indicators with parameters (bollinger, vwap, adx, ...)
+  custom_indicators?: IndicatorSpec[];
+  // User-defined series, e.g. { name: 'ema_spread', expr: 'ema(close,9) - ema(close,21)' }
+  expressions?: ExpressionSpec[];
   external_data_sources?: ExternalDataSource[];
   // 量化数据源（资金流向、持仓变化、价格变化）
   enable_quant_data?: boolean;